@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/alerts"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runAlerts 处理 alerts 子命令：add <symbol> -below/-above/-move、list、rm <id>、watch（守护模式）
+func runAlerts(args []string) int {
+	fs := flag.NewFlagSet("alerts", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), i18n.T("alerts.usage", os.Args[0]))
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", i18n.T("flag.config"))
+	output := fs.String("output", outputText, i18n.T("flag.output"))
+	above := fs.Float64("above", 0, i18n.T("flag.alert_above"))
+	below := fs.Float64("below", 0, i18n.T("flag.alert_below"))
+	move := fs.Float64("move", 0, i18n.T("flag.alert_move"))
+	repeat := fs.Bool("repeat", false, i18n.T("flag.alert_repeat"))
+	all := fs.Bool("all", false, i18n.T("flag.alert_all"))
+	interval := fs.Int("interval", int(alerts.DefaultInterval/time.Second), i18n.T("flag.alert_interval"))
+	depth := fs.String("depth", "", i18n.T("flag.depth"))
+	risk := fs.String("risk", "", i18n.T("flag.risk"))
+	fs.String("lang", "", i18n.T("flag.lang")) // 已在 initLocale 中读取
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Usage()
+		return 2
+	}
+	action, rest := args[0], args[1:]
+	// 位置参数（标的或 id）可以写在选项之前或之后
+	var positional []string
+	for {
+		if err := fs.Parse(rest); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+
+	format, err := parseOutputFormat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *depth != "" && !config.ValidDepth(*depth) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.depth", *depth))
+		return 2
+	}
+	if !config.ValidRiskProfile(*risk) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.risk", *risk))
+		return 2
+	}
+	cfg, err := loadConfig(*configPath, configOverrides{depth: *depth, risk: *risk})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx := context.Background()
+	var result any
+	switch action {
+	case "add":
+		if len(positional) != 1 {
+			fs.Usage()
+			return 2
+		}
+		a, err := service.CreateAlert(ctx, models.AlertAddParams{Symbol: positional[0], Above: *above, Below: *below, Move: *move, Repeat: *repeat})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			fmt.Println(i18n.T("alerts.added", a.Id, alerts.Describe(*a)))
+			return 0
+		}
+		result = a
+	case "list", "ls":
+		symbol := ""
+		if len(positional) > 0 {
+			symbol = positional[0]
+		}
+		items, err := service.LoadAlerts(ctx, models.AlertListParams{Symbol: symbol, All: *all})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			writeAlerts(items)
+			return 0
+		}
+		result = items
+	case "rm", "del":
+		if len(positional) != 1 {
+			fs.Usage()
+			return 2
+		}
+		id, err := strconv.ParseInt(positional[0], 10, 64)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("err.alert_id", positional[0]))
+			return 2
+		}
+		if err := service.RemoveAlert(ctx, id); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			fmt.Println(i18n.T("alerts.deleted", id))
+			return 0
+		}
+		result = map[string]any{"id": id, "deleted": true}
+	case "watch":
+		return watchAlerts(ctx, cfg, time.Duration(*interval)*time.Second, format)
+	default:
+		fs.Usage()
+		return 2
+	}
+	if err := writeStructured(os.Stdout, format, result); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// watchAlerts 以守护模式轮询行情直到收到 SIGINT/SIGTERM；结构化输出时每次触发输出一行 JSON/YAML
+func watchAlerts(ctx context.Context, cfg *config.Config, interval time.Duration, format string) int {
+	engine, err := service.NewAlertEngine(cfg, func(t models.AlertTrigger) {
+		if format != outputText {
+			if err := writeStructured(os.Stdout, format, t); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			return
+		}
+		if t.Error != "" {
+			fmt.Println(i18n.T("alerts.trigger_failed", alerts.Describe(t.Alert), t.Price, t.Error))
+			return
+		}
+		fmt.Println(i18n.T("alerts.triggered", alerts.Describe(t.Alert), t.Price, t.SessionId))
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	active, err := service.LoadAlerts(ctx, models.AlertListParams{})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	interval = max(interval, alerts.MinInterval)
+	fmt.Fprintln(os.Stderr, i18n.T("alerts.watching", len(active), interval))
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	engine.Run(ctx, interval, func(err error) {
+		fmt.Fprintln(os.Stderr, err)
+	})
+	return 0
+}
+
+func writeAlerts(items []models.Alert) {
+	if len(items) == 0 {
+		fmt.Println(i18n.T("alerts.none"))
+		return
+	}
+	tw := newTable(os.Stdout, false)
+	fmt.Fprintln(tw, i18n.T("alerts.header"))
+	for _, a := range items {
+		triggered := "-"
+		if !a.TriggeredAt.IsZero() {
+			triggered = fmt.Sprintf("%s @ %.2f", a.TriggeredAt.Format("2006-01-02 15:04"), a.TriggerPrice)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%g\t%t\t%s\t%s\t\n", a.Id, a.Symbol, a.Condition, a.Threshold, a.Repeat, a.Status, triggered)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/batch"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// batchOptions -batch / -resume 的参数
+type batchOptions struct {
+	Symbols     string
+	ResumeID    string
+	TradeDate   string
+	Concurrency int
+	// Adaptive 从 1 个 worker 起步，按 429 与数据源错误率自动增减，不超过 Concurrency
+	Adaptive bool
+	// Live 非空时监听配置文件，每个标的开始分析时取最新配置；深度仍由批次清单固定
+	Live *config.Manager
+	// Rotation sectors -analyze 的板块轮动排名，附在结构化输出中
+	Rotation *models.SectorRotation
+}
+
+// batchOutput 结构化输出：清单、排序后的汇总与报告目录
+type batchOutput struct {
+	Batch     *batch.Manifest `json:"batch"`
+	Summary   []batch.Row     `json:"summary"`
+	ReportDir string          `json:"report_dir,omitempty"`
+	// Rotation 由 sectors -analyze 启动时为板块轮动排名
+	Rotation *models.SectorRotation `json:"rotation,omitempty"`
+}
+
+// runBatch 批量分析多个标的，进度写入 data/batches/<id>.json；中断后用 -resume <id> 继续，已完成的标的不再重跑
+func runBatch(cfg *config.Config, opts batchOptions, format string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dir := batch.Dir(cfg.DataDir)
+	var (
+		m   *batch.Manifest
+		err error
+	)
+	if opts.ResumeID != "" {
+		m, err = batch.Load(dir, opts.ResumeID)
+		if err == nil && m.Depth != "" {
+			cfg.Depth = m.Depth
+		}
+	} else {
+		m, err = batch.New(dir, strings.Split(opts.Symbols, ","), opts.TradeDate, cfg.Depth)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if err := agents.InitChatModel(ctx, cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	remaining := len(m.Remaining())
+	fmt.Fprintln(os.Stderr, i18n.T("batch.start", m.ID, remaining, len(m.Items), m.TradeDate, m.ID))
+
+	// 大盘环境与市场级工具（财经要闻、收益率曲线、VIX）整批只取一次，各标的共用
+	shared := batch.Shared(ctx, cfg, m)
+
+	var (
+		mu   sync.Mutex
+		done int
+	)
+	if opts.Live != nil {
+		err := opts.Live.WatchReloads(ctx, func(ev config.ReloadEvent) {
+			switch {
+			case ev.Err != nil:
+				fmt.Fprintln(os.Stderr, i18n.T("batch.reload_failed", ev.Err))
+			case len(ev.Applied) > 0:
+				fmt.Fprintln(os.Stderr, i18n.T("batch.reloaded", strings.Join(ev.Applied, ", ")))
+			}
+			if len(ev.Restart) > 0 {
+				fmt.Fprintln(os.Stderr, i18n.T("batch.restart", strings.Join(ev.Restart, ", ")))
+			}
+		})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	analyzeSymbol := func(ctx context.Context, symbol string) (batch.Result, error) {
+		runCfg := cfg
+		if opts.Live != nil {
+			c := opts.Live.Get()
+			c.Depth = cfg.Depth
+			runCfg = &c
+		}
+		if runCfg.Offline {
+			if missing := tools.OfflinePreflight(runCfg, symbol); len(missing) > 0 {
+				return batch.Result{}, errors.New(i18n.T("batch.offline_missing", strings.Join(missing, "; ")))
+			}
+		}
+		res := analyze(ctx, runCfg, symbol, m.TradeDate, nil)
+		if res.Error != "" {
+			return batch.Result{}, errors.New(res.Error)
+		}
+		out := batch.Result{}
+		if res.Report != nil {
+			out.Recommendation = res.Report.Recommendation
+			out.Confidence = report.ExtractDecision(res.Report).Confidence
+		}
+		return out, nil
+	}
+	// onDone 由多个 worker 并发调用
+	onDone := func(it *batch.Item) {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		line := fmt.Sprintf("[%d/%d] %s %s", done, remaining, it.Symbol, it.Status)
+		if it.Recommendation != "" {
+			line += " " + it.Recommendation
+		}
+		if it.Error != "" {
+			line += ": " + it.Error
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+	limiter := batch.NewLimiter(1, opts.Concurrency, opts.Adaptive)
+	limiter.OnChange(func(from, to int, reason string) {
+		fmt.Fprintln(os.Stderr, i18n.T("batch.concurrency", from, to, reason))
+	})
+	runErr := batch.Run(dataflows.WithSession(ctx, shared), m, analyzeSymbol, batch.Options{
+		Limiter: limiter,
+		Probe:   dataflows.Stats,
+		OnDone:  onDone,
+	})
+
+	counts := m.Counts()
+	fmt.Fprintln(os.Stderr, i18n.T("batch.done",
+		m.ID, counts[batch.StatusCompleted], counts[batch.StatusFailed], counts[batch.StatusPending]))
+	if st := shared.Stats(); st.Hits > 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("batch.shared", st.Misses, st.Hits))
+	}
+
+	// 汇总报告：按建议与置信度排序，写 summary.md / summary.csv
+	rows := batch.Summarize(m)
+	checkCorrelation(cfg, m, rows)
+	reportDir, err := batch.WriteReport(cfg.ResultsDir, m)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("batch.report_failed", err))
+	} else {
+		fmt.Fprintln(os.Stderr, i18n.T("batch.report", reportDir))
+	}
+	if format != outputText {
+		out := batchOutput{Batch: m, Summary: rows, ReportDir: reportDir, Rotation: opts.Rotation}
+		if err := writeStructured(os.Stdout, format, out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		writeBatchTable(os.Stdout, rows)
+	}
+
+	switch {
+	case errors.Is(runErr, context.Canceled):
+		fmt.Fprintln(os.Stderr, i18n.T("batch.interrupted", m.ID))
+		return 130
+	case runErr != nil:
+		fmt.Fprintln(os.Stderr, runErr)
+		return 1
+	case counts[batch.StatusFailed] > 0:
+		return 1
+	}
+	return 0
+}
+
+// checkCorrelation 对已完成的标的做一次收益相关性检查，结果写入批次清单与汇总报告，集中度提示输出到 stderr
+func checkCorrelation(cfg *config.Config, m *batch.Manifest, rows []batch.Row) {
+	var symbols []string
+	for _, r := range rows {
+		if r.Status == batch.StatusCompleted {
+			symbols = append(symbols, r.Symbol)
+		}
+	}
+	if len(symbols) < 2 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	c, err := service.AssessPortfolioRisk(ctx, cfg, models.PortfolioRiskParams{Symbols: symbols, TradeDate: m.TradeDate})
+	if err == nil {
+		err = m.SetCorrelation(c)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("batch.correlation", err))
+		return
+	}
+	if len(c.Warnings) > 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("portfolio.flags"))
+		for _, w := range c.Warnings {
+			fmt.Fprintln(os.Stderr, "- "+w)
+		}
+	}
+}
+
+// writeBatchTable 打印排序后的汇总表
+func writeBatchTable(w io.Writer, rows []batch.Row) {
+	tw := newTable(w, false)
+	fmt.Fprintln(tw, i18n.T("batch.header"))
+	for _, r := range rows {
+		rec, conf := r.Recommendation, "-"
+		if rec == "" {
+			rec = "-"
+		}
+		if r.Confidence > 0 {
+			conf = fmt.Sprintf("%.0f%%", r.Confidence*100)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", r.Rank, r.Symbol, rec, conf, r.Status)
+	}
+	tw.Flush()
+}
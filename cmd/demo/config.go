@@ -0,0 +1,57 @@
+package main
+
+import (
+	"github.com/dyike/CortexGo/config"
+)
+
+// configOverrides 是各子命令共用的命令行覆盖项，零值表示沿用配置文件
+type configOverrides struct {
+	offline      bool
+	depth        string
+	risk         string
+	llm          string
+	dataProvider string
+}
+
+// apply 命令行参数优先于配置文件
+func (o configOverrides) apply(cfg *config.Config) {
+	if o.offline {
+		cfg.Offline = true
+	}
+	if o.depth != "" {
+		cfg.Depth = o.depth
+	}
+	if o.risk != "" {
+		cfg.RiskProfile = o.risk
+	}
+	if o.llm != "" {
+		cfg.LLMProvider = o.llm
+	}
+	if o.dataProvider != "" {
+		cfg.DataProvider = o.dataProvider
+	}
+}
+
+// setDefaultConfig 将 cfg（已应用命令行覆盖项）设为全局配置，历史检索与文档库通过它定位 data_dir。
+// 未使用配置文件（path 为空）时同样安装，否则 config.Get 会回落到默认管理器，
+// 在用户配置目录下创建 config.json 且不含命令行覆盖项
+func setDefaultConfig(path string, cfg *config.Config) {
+	mgr, err := config.NewManager(config.WithConfigPath(path), config.WithLoader(func() (*config.Config, error) {
+		c := cfg.Clone()
+		return &c, nil
+	}))
+	if err == nil {
+		config.SetDefaultManager(mgr)
+	}
+}
+
+// loadConfig 加载配置文件、应用命令行覆盖项并设为全局配置
+func loadConfig(path string, o configOverrides) (*config.Config, error) {
+	cfg, resolved, err := config.LoadResolved(path)
+	if err != nil {
+		return nil, err
+	}
+	o.apply(cfg)
+	setDefaultConfig(resolved, cfg)
+	return cfg, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runDoctor 运行环境诊断并输出结果，存在 fail 项时返回非零退出码
+func runDoctor(cfg *config.Config, format string) int {
+	resp, err := service.Diagnose(context.Background(), cfg, models.DoctorParams{})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, resp); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		tw := newTable(os.Stdout, false)
+		fmt.Fprintln(tw, i18n.T("doctor.header"))
+		for _, c := range resp.Checks {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Name, strings.ToUpper(c.Status), c.Detail)
+		}
+		tw.Flush()
+		for _, c := range resp.Checks {
+			if c.Fix != "" {
+				fmt.Printf("\n%s: %s", c.Name, c.Fix)
+			}
+		}
+		fmt.Println()
+	}
+	if !resp.OK {
+		return 1
+	}
+	return 0
+}
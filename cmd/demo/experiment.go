@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/experiment"
+	"github.com/dyike/CortexGo/internal/graph"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runExperiment 实现 experiment 子命令：用两份配置分析同一批标的与日期，输出决策、费用与耗时的对比报告
+func runExperiment(args []string) int {
+	fs := flag.NewFlagSet("experiment", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), i18n.T("experiment.usage", os.Args[0]))
+		fs.PrintDefaults()
+	}
+	baselinePath := fs.String("baseline", "", i18n.T("flag.baseline"))
+	candidatePath := fs.String("candidate", "", i18n.T("flag.candidate"))
+	casesPath := fs.String("f", "", i18n.T("flag.cases"))
+	date := fs.String("date", time.Now().Format("2006-01-02"), i18n.T("flag.date"))
+	output := fs.String("output", outputText, i18n.T("flag.output"))
+	fs.String("lang", "", i18n.T("flag.lang")) // 已在 initLocale 中读取
+
+	if len(args) == 0 || args[0] != "run" {
+		fs.Usage()
+		return 2
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	format, err := parseOutputFormat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *baselinePath == "" || *candidatePath == "" {
+		fmt.Fprintln(os.Stderr, i18n.T("experiment.configs"))
+		return 2
+	}
+	if *casesPath == "" {
+		fmt.Fprintln(os.Stderr, i18n.T("experiment.no_cases"))
+		return 2
+	}
+
+	var arms [2]experiment.Arm
+	for i, path := range []string{*baselinePath, *candidatePath} {
+		cfg, resolved, err := config.LoadResolved(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		// 全局配置以基线配置为准
+		if i == 0 {
+			setDefaultConfig(resolved, cfg)
+		}
+		arms[i] = experiment.Arm{Name: path, ConfigPath: path, Config: cfg}
+	}
+	f, err := os.Open(*casesPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	cases, err := experiment.ParseCases(f, *date)
+	f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	baseline, candidate := arms[0], arms[1]
+	fmt.Fprintln(os.Stderr, i18n.T("experiment.start", *casesPath, len(cases), baseline.Name, candidate.Name))
+	done := 0
+	onDone := func(p *experiment.Pair) {
+		done++
+		fmt.Fprintln(os.Stderr, i18n.T("experiment.case", done, len(cases), p.Symbol, p.TradeDate,
+			outcomeLabel(p.Baseline), outcomeLabel(p.Candidate)))
+	}
+	res := experiment.Run(ctx, baseline, candidate, cases, runExperimentCase, onDone)
+
+	s := res.Summary
+	fmt.Fprintln(os.Stderr, i18n.T("experiment.summary", s.Agreed, s.Compared,
+		s.Baseline.CostUSD, s.Candidate.CostUSD, s.Baseline.MeanLatencySeconds, s.Candidate.MeanLatencySeconds))
+	// 报告写到基线配置的结果目录
+	reportDir, err := experiment.WriteReport(baseline.Config.ResultsDir, res)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("experiment.failed", err))
+	} else {
+		fmt.Fprintln(os.Stderr, i18n.T("experiment.report", reportDir))
+	}
+
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, res); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		writeExperimentTable(os.Stdout, res)
+	}
+	if ctx.Err() != nil {
+		return 130
+	}
+	if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// runExperimentCase 用一份配置分析一个标的。模型为全局单例，每次运行前按该配置重建，因此各次运行只能串行
+func runExperimentCase(ctx context.Context, cfg *config.Config, c experiment.Case) experiment.Outcome {
+	agents.ResetChatModel()
+	if err := agents.InitChatModel(ctx, cfg); err != nil {
+		return experiment.Outcome{Error: err.Error()}
+	}
+	if cfg.Offline {
+		if missing := tools.OfflinePreflight(cfg, c.Symbol); len(missing) > 0 {
+			return experiment.Outcome{Error: i18n.T("batch.offline_missing", strings.Join(missing, "; "))}
+		}
+	}
+
+	usage := &graph.UsageCallback{}
+	start := time.Now()
+	res := analyze(ctx, cfg, c.Symbol, c.TradeDate, nil, compose.WithCallbacks(usage.Handler()))
+	in, out, _ := usage.Totals()
+	o := experiment.Outcome{
+		InputTokens:    in,
+		OutputTokens:   out,
+		CostUSD:        graph.CostUSD(in, out),
+		LatencySeconds: time.Since(start).Seconds(),
+		Error:          res.Error,
+	}
+	if res.Report != nil {
+		o.Recommendation = res.Report.Recommendation
+		if d := report.ExtractDecision(res.Report); d != nil {
+			o.Confidence = d.Confidence
+			o.PositionSize = d.PositionSize
+		}
+	}
+	return o
+}
+
+func outcomeLabel(o experiment.Outcome) string {
+	if o.Error != "" {
+		return "error"
+	}
+	if o.Recommendation == "" {
+		return "-"
+	}
+	return o.Recommendation
+}
+
+func writeExperimentTable(w io.Writer, res *experiment.Result) {
+	tw := newTable(w, false)
+	fmt.Fprintln(tw, i18n.T("experiment.header"))
+	for _, p := range res.Pairs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t$%.4f -> $%.4f\t%.1fs -> %.1fs\n", p.Symbol, p.TradeDate,
+			outcomeLabel(p.Baseline), outcomeLabel(p.Candidate),
+			p.Baseline.CostUSD, p.Candidate.CostUSD,
+			p.Baseline.LatencySeconds, p.Candidate.LatencySeconds)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runIndex 实现 index 子命令：列出指数成分股，或统计成分股的涨跌家数、均线广度与新高新低
+func runIndex(args []string) int {
+	fs := flag.NewFlagSet("index", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), i18n.T("index.usage", os.Args[0]))
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", i18n.T("flag.config"))
+	output := fs.String("output", outputText, i18n.T("flag.output"))
+	sector := fs.String("sector", "", i18n.T("flag.sector"))
+	date := fs.String("date", "", i18n.T("flag.date"))
+	fs.String("lang", "", i18n.T("flag.lang")) // 已在 initLocale 中读取
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Usage()
+		return 2
+	}
+	action, rest := args[0], args[1:]
+	// 指数名可以写在选项之前或之后
+	var positional []string
+	for {
+		if err := fs.Parse(rest); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	format, err := parseOutputFormat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	cfg, _, err := config.LoadResolved(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	var result any
+	switch action {
+	case "list", "ls":
+		c, err := service.LoadIndexConstituents(cfg, models.IndexConstituentsParams{Index: positional[0], Sector: *sector})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			writeConstituents(c)
+			return 0
+		}
+		result = c
+	case "breadth":
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		b, err := service.ComputeIndexBreadth(ctx, cfg, models.IndexBreadthParams{Index: positional[0], Sector: *sector, TradeDate: *date})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			writeBreadth(b)
+			return 0
+		}
+		result = b
+	default:
+		fs.Usage()
+		return 2
+	}
+	if err := writeStructured(os.Stdout, format, result); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func writeConstituents(c *models.IndexConstituents) {
+	fmt.Println(i18n.T("index.summary", c.Name, c.Index, len(c.Members), c.Source, c.FetchedAt.Format("2006-01-02 15:04")))
+	tw := newTable(os.Stdout, false)
+	fmt.Fprintln(tw, i18n.T("index.header"))
+	for _, m := range c.Members {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", m.Symbol, m.Name, m.Sector)
+	}
+	tw.Flush()
+}
+
+func writeBreadth(b *models.IndexBreadth) {
+	fmt.Println(i18n.T("index.breadth", b.Index, b.AsOf, b.Counted, b.Members))
+	fmt.Println(i18n.T("index.advance", b.Advancers, b.Decliners, b.Unchanged))
+	fmt.Println(i18n.T("index.above", b.Above50*100, b.Above200*100))
+	fmt.Println(i18n.T("index.highs", b.NewHighs, b.NewLows))
+	if len(b.Missing) > 0 {
+		fmt.Println(i18n.T("index.missing", strings.Join(b.Missing, ", ")))
+	}
+	if len(b.Sectors) == 0 {
+		return
+	}
+	fmt.Println()
+	tw := newTable(os.Stdout, false)
+	fmt.Fprintln(tw, i18n.T("index.sector_header"))
+	for _, s := range b.Sectors {
+		fmt.Fprintf(tw, "%s\t%d\t%.0f%%\n", s.Sector, s.Counted, s.Above50*100)
+	}
+	tw.Flush()
+}
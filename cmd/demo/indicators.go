@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runIndicators 输出指标表；format 为 table/csv，或沿用 -output 的 json/yaml
+func runIndicators(cfg *config.Config, params models.MarketIndicatorsParams, format string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	resp, err := service.ComputeIndicators(ctx, cfg, params)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch format {
+	case "csv":
+		err = service.WriteIndicatorsCSV(os.Stdout, resp)
+	case outputJSON, outputYAML:
+		err = writeStructured(os.Stdout, format, resp)
+	default:
+		tw := newTable(os.Stdout, true)
+		fmt.Fprint(tw, i18n.T("indicators.date")+"\t"+i18n.T("indicators.close")+"\t")
+		for _, col := range resp.Columns {
+			fmt.Fprint(tw, col+"\t")
+		}
+		fmt.Fprintln(tw)
+		for _, row := range resp.Rows {
+			fmt.Fprintf(tw, "%s\t%.2f\t", row.Date, row.Close)
+			for _, col := range resp.Columns {
+				if v, ok := row.Values[col]; ok {
+					fmt.Fprint(tw, strconv.FormatFloat(v, 'f', 2, 64)+"\t")
+				} else {
+					fmt.Fprint(tw, "-\t")
+				}
+			}
+			fmt.Fprintln(tw)
+		}
+		err = tw.Flush()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runIngest 导入文档供基本面分析师的 query_documents 检索
+func runIngest(params models.DocumentIngestParams, format string) int {
+	doc, err := service.IngestDocumentFile(context.Background(), params)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, doc); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	symbol := doc.Symbol
+	if symbol == "" {
+		symbol = i18n.T("ingest.any_symbol")
+	}
+	fmt.Println(i18n.T("ingest.done", doc.Id, doc.Title, doc.Kind, symbol, doc.Pages, doc.Chunks))
+	return 0
+}
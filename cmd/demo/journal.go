@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runJournal 实现 journal 子命令：记录实际执行的交易、平仓、列出与删除，以及汇总已实现盈亏
+func runJournal(args []string) int {
+	fs := flag.NewFlagSet("journal", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), i18n.T("journal.usage", os.Args[0]))
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", i18n.T("flag.config"))
+	output := fs.String("output", outputText, i18n.T("flag.output"))
+	run := fs.String("run", "", i18n.T("flag.journal_run"))
+	side := fs.String("side", "", i18n.T("flag.journal_side"))
+	qty := fs.Float64("qty", 0, i18n.T("flag.journal_qty"))
+	price := fs.Float64("price", 0, i18n.T("flag.journal_price"))
+	date := fs.String("date", "", i18n.T("flag.journal_date"))
+	fees := fs.Float64("fees", 0, i18n.T("flag.journal_fees"))
+	currency := fs.String("currency", "", i18n.T("flag.journal_currency"))
+	notes := fs.String("notes", "", i18n.T("flag.journal_notes"))
+	open := fs.Bool("open", false, i18n.T("flag.journal_open"))
+	fs.String("lang", "", i18n.T("flag.lang")) // 已在 initLocale 中读取
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Usage()
+		return 2
+	}
+	action, rest := args[0], args[1:]
+	// 位置参数（标的或 id）可以写在选项之前或之后
+	var positional []string
+	for {
+		if err := fs.Parse(rest); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+
+	format, err := parseOutputFormat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if _, err := loadConfig(*configPath, configOverrides{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx := context.Background()
+	var result any
+	switch action {
+	case "add":
+		// 关联分析（-run）时标的可省略
+		if len(positional) > 1 || (len(positional) == 0 && *run == "") {
+			fs.Usage()
+			return 2
+		}
+		params := models.JournalAddParams{SessionID: *run, Side: *side, Quantity: *qty, EntryPrice: *price,
+			EntryDate: *date, Fees: *fees, Currency: *currency, Notes: *notes}
+		if len(positional) == 1 {
+			params.Symbol = positional[0]
+		}
+		e, err := service.CreateJournalEntry(ctx, params)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			fmt.Println(i18n.T("journal.added", e.Id, describeTrade(*e)))
+			return 0
+		}
+		result = e
+	case "close":
+		id, ok := journalID(fs, positional)
+		if !ok {
+			return 2
+		}
+		e, err := service.CloseJournalEntry(ctx, models.JournalCloseParams{Id: id, ExitPrice: *price, ExitDate: *date, Fees: *fees, Notes: *notes})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			fmt.Println(i18n.T("journal.closed", e.Id, describeTrade(*e), e.RealizedPnL, e.Currency, e.ReturnPct))
+			return 0
+		}
+		result = e
+	case "list", "ls":
+		params := models.JournalListParams{SessionID: *run, Open: *open}
+		if len(positional) > 0 {
+			params.Symbol = positional[0]
+		}
+		items, err := service.LoadJournal(ctx, params)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			writeJournal(items)
+			return 0
+		}
+		result = items
+	case "stats":
+		stats, err := service.LoadJournalStats(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			writeJournalStats(stats)
+			return 0
+		}
+		result = stats
+	case "rm", "del":
+		id, ok := journalID(fs, positional)
+		if !ok {
+			return 2
+		}
+		if err := service.RemoveJournalEntry(ctx, id); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if format == outputText {
+			fmt.Println(i18n.T("journal.deleted", id))
+			return 0
+		}
+		result = map[string]any{"id": id, "deleted": true}
+	default:
+		fs.Usage()
+		return 2
+	}
+	if err := writeStructured(os.Stdout, format, result); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func journalID(fs *flag.FlagSet, positional []string) (int64, bool) {
+	if len(positional) != 1 {
+		fs.Usage()
+		return 0, false
+	}
+	id, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("err.journal_id", positional[0]))
+		return 0, false
+	}
+	return id, true
+}
+
+// describeTrade 如 "long 10 AAPL.US @ 190.5 on 2025-12-15"
+func describeTrade(e models.JournalEntry) string {
+	return fmt.Sprintf("%s %g %s @ %g on %s", e.Side, e.Quantity, e.Symbol, e.EntryPrice, e.EntryDate)
+}
+
+func writeJournal(items []models.JournalEntry) {
+	if len(items) == 0 {
+		fmt.Println(i18n.T("journal.none"))
+		return
+	}
+	tw := newTable(os.Stdout, false)
+	fmt.Fprintln(tw, i18n.T("journal.header"))
+	for _, e := range items {
+		run, exit, pnl := "-", "-", "-"
+		if e.SessionId > 0 {
+			run = strconv.FormatInt(e.SessionId, 10)
+			if e.Recommendation != "" {
+				run += " " + e.Recommendation
+			}
+		}
+		if e.Closed() {
+			exit = fmt.Sprintf("%g %s", e.ExitPrice, e.ExitDate)
+			pnl = fmt.Sprintf("%+.2f %s (%+.2f%%)", e.RealizedPnL, e.Currency, e.ReturnPct)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%g\t%g %s\t%s\t%s\t%s\n", e.Id, e.Symbol, e.Side, e.Quantity, e.EntryPrice, e.EntryDate, exit, run, pnl)
+	}
+	tw.Flush()
+}
+
+func writeJournalStats(s *models.JournalStats) {
+	fmt.Println(i18n.T("journal.stats", s.Trades, s.Open, s.Closed, s.WinRate*100, s.AvgReturnPct))
+	if s.Followed > 0 {
+		fmt.Println(i18n.T("journal.followed", s.Followed, s.FollowedReturnPct))
+	}
+	currencies := make([]string, 0, len(s.RealizedPnL))
+	for c := range s.RealizedPnL {
+		currencies = append(currencies, c)
+	}
+	sort.Strings(currencies)
+	for _, c := range currencies {
+		fmt.Println(i18n.T("journal.pnl", c, s.RealizedPnL[c]))
+	}
+}
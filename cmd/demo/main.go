@@ -2,10 +2,11 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,56 +14,389 @@ import (
 	"github.com/cloudwego/eino/compose"
 	"github.com/dyike/CortexGo/config"
 	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/bundle"
 	"github.com/dyike/CortexGo/internal/graph"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rundir"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/internal/tools"
 	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
 )
 
 func main() {
+	initLocale(os.Args[1:])
+	if len(os.Args) > 1 && os.Args[1] == "alerts" {
+		os.Exit(runAlerts(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "experiment" {
+		os.Exit(runExperiment(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplay(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "journal" {
+		os.Exit(runJournal(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "index" {
+		os.Exit(runIndex(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "watch" {
+		os.Exit(runWatch(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "sectors" {
+		os.Exit(runSectors(os.Args[2:]))
+	}
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), i18n.T("usage", os.Args[0]))
+		flag.PrintDefaults()
+	}
+	configPath := flag.String("config", "", i18n.T("flag.config"))
+	symbol := flag.String("symbol", "CRCL.US", i18n.T("flag.symbol"))
+	tradeDate := flag.String("date", "2025-12-15", i18n.T("flag.date"))
+	raw := flag.Bool("raw", false, i18n.T("flag.raw"))
+	output := flag.String("output", outputText, i18n.T("flag.output"))
+	validate := flag.Bool("validate", false, i18n.T("flag.validate"))
+	strict := flag.Bool("strict", false, i18n.T("flag.strict"))
+	printSchema := flag.Bool("config-schema", false, i18n.T("flag.config_schema"))
+	printEnv := flag.Bool("print-env", false, i18n.T("flag.print_env"))
+	printConfig := flag.Bool("print-config", false, i18n.T("flag.print_config"))
+	quote := flag.String("quote", "", i18n.T("flag.quote"))
+	news := flag.String("news", "", i18n.T("flag.news"))
+	newsSource := flag.String("source", "google", i18n.T("flag.source"))
+	newsDays := flag.Int("days", 3, i18n.T("flag.days"))
+	minQuality := flag.Float64("min-quality", 0, i18n.T("flag.min_quality"))
+	newOnly := flag.Bool("new-only", false, i18n.T("flag.new_only"))
+	export := flag.String("export", "", i18n.T("flag.export"))
+	indicators := flag.String("indicators", "", i18n.T("flag.indicators"))
+	lookback := flag.Int("lookback", 60, i18n.T("flag.lookback"))
+	tableFormat := flag.String("format", "", i18n.T("flag.format"))
+	doctor := flag.Bool("doctor", false, i18n.T("flag.doctor"))
+	batchSymbols := flag.String("batch", "", i18n.T("flag.batch"))
+	index := flag.String("index", "", i18n.T("flag.index"))
+	sector := flag.String("sector", "", i18n.T("flag.sector"))
+	resume := flag.String("resume", "", i18n.T("flag.resume"))
+	concurrency := flag.Int("c", 4, i18n.T("flag.c"))
+	watch := flag.Bool("watch", false, i18n.T("flag.watch"))
+	adaptive := flag.Bool("adaptive", true, i18n.T("flag.adaptive"))
+	depth := flag.String("depth", "", i18n.T("flag.depth"))
+	risk := flag.String("risk", "", i18n.T("flag.risk"))
+	llm := flag.String("llm", "", i18n.T("flag.llm"))
+	dataProvider := flag.String("data", "", i18n.T("flag.data"))
+	dryRun := flag.Bool("dry-run", false, i18n.T("flag.dry_run"))
+	offline := flag.Bool("offline", false, i18n.T("flag.offline"))
+	seed := flag.Int("seed", 0, i18n.T("flag.seed"))
+	toolData := flag.Bool("tool-data", false, i18n.T("flag.tool_data"))
+	plain := flag.Bool("plain", false, i18n.T("flag.plain"))
+	ingest := flag.String("ingest", "", i18n.T("flag.ingest"))
+	docKind := flag.String("kind", "", i18n.T("flag.kind"))
+	docTitle := flag.String("title", "", i18n.T("flag.title"))
+	portfolio := flag.String("portfolio", "", i18n.T("flag.portfolio"))
+	watchlist := flag.String("watchlist", "", i18n.T("flag.watchlist"))
+	window := flag.Int("window", 0, i18n.T("flag.window"))
+	flag.String("lang", "", i18n.T("flag.lang")) // 已在 initLocale 中读取
+	flag.Parse()
+
+	format, err := parseOutputFormat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *printSchema {
+		os.Exit(runConfigSchema(format))
+	}
+	if *printEnv {
+		os.Exit(runPrintEnv(format))
+	}
+	if *validate {
+		os.Exit(runValidate(*configPath, *strict, format))
+	}
+	if *depth != "" && !config.ValidDepth(*depth) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.depth", *depth))
+		os.Exit(2)
+	}
+	if !config.ValidRiskProfile(*risk) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.risk", *risk))
+		os.Exit(2)
+	}
+	if !config.ValidLLMProvider(*llm) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.llm", *llm))
+		os.Exit(2)
+	}
+	if !config.ValidDataProvider(*dataProvider) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.data", *dataProvider))
+		os.Exit(2)
+	}
+	// 命令行参数优先于配置文件，重新加载时同样生效
+	overrides := configOverrides{offline: *offline, depth: *depth, risk: *risk, llm: *llm, dataProvider: *dataProvider}
+	load := func(path string) (*config.Config, string, error) {
+		cfg, resolved, err := config.LoadResolved(path)
+		if err != nil {
+			return nil, "", err
+		}
+		overrides.apply(cfg)
+		if *seed > 0 {
+			cfg.Seed = *seed
+		}
+		if *toolData {
+			cfg.ExportToolData = true
+		}
+		return cfg, resolved, nil
+	}
+	cfg, cfgPath, err := load(*configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// 全局配置与本次运行使用同一份配置（含命令行覆盖项）
+	setDefaultConfig(cfgPath, cfg)
+
+	if *printConfig {
+		if format == outputText {
+			format = outputJSON
+		}
+		if err := writeStructured(os.Stdout, format, redactedConfig(cfg)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *dryRun {
+		os.Exit(runPlan(cfg, models.AgentPlanParams{Symbol: *symbol, TradeDate: *tradeDate}, format))
+	}
+	if *doctor {
+		os.Exit(runDoctor(cfg, format))
+	}
+	if *quote != "" {
+		os.Exit(runQuote(cfg, *quote, format))
+	}
+	if *indicators != "" {
+		f := *tableFormat
+		if f == "" {
+			f = format
+		}
+		os.Exit(runIndicators(cfg, models.MarketIndicatorsParams{Symbol: *indicators, Lookback: *lookback, EndDate: dateFlagIfSet(*tradeDate)}, f))
+	}
+	if *ingest != "" {
+		os.Exit(runIngest(models.DocumentIngestParams{Path: *ingest, Symbol: flagIfSet("symbol", *symbol), Kind: *docKind, Title: *docTitle}, format))
+	}
+	if *portfolio == "risk" {
+		params := models.PortfolioRiskParams{Window: *window, TradeDate: dateFlagIfSet(*tradeDate)}
+		if *watchlist != "" {
+			params.Symbols = strings.Split(*watchlist, ",")
+		}
+		os.Exit(runPortfolioRisk(cfg, params, format))
+	}
+	if *portfolio != "" {
+		os.Exit(runPortfolio(cfg, *portfolio, format))
+	}
+	if *news != "" {
+		os.Exit(runNews(cfg, models.NewsListParams{Symbol: *news, Days: *newsDays, Source: *newsSource, MinQuality: *minQuality, Incremental: *newOnly, Output: *export}, format))
+	}
+
+	// -index 分析指数全部成分股，-sector 只取某个行业
+	if *index != "" {
+		if *batchSymbols != "" || *resume != "" {
+			fmt.Fprintln(os.Stderr, i18n.T("err.index_batch"))
+			os.Exit(2)
+		}
+		c, err := service.LoadIndexConstituents(cfg, models.IndexConstituentsParams{Index: *index, Sector: *sector})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		symbols := make([]string, len(c.Members))
+		for i, m := range c.Members {
+			symbols[i] = m.Symbol
+		}
+		fmt.Fprintln(os.Stderr, i18n.T("batch.index", c.Name, len(symbols)))
+		*batchSymbols = strings.Join(symbols, ",")
+	}
+	if *batchSymbols != "" || *resume != "" {
+		opts := batchOptions{Symbols: *batchSymbols, ResumeID: *resume, TradeDate: *tradeDate, Concurrency: *concurrency, Adaptive: *adaptive}
+		if *watch {
+			if cfgPath == "" {
+				fmt.Fprintln(os.Stderr, i18n.T("err.watch_config"))
+				os.Exit(2)
+			}
+			live, err := config.NewManager(config.WithConfigPath(cfgPath), config.WithLiveReload(), config.WithLoader(func() (*config.Config, error) {
+				c, _, err := load(cfgPath)
+				return c, err
+			}))
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			opts.Live = live
+		}
+		os.Exit(runBatch(cfg, opts, format))
+	}
+	if *watch {
+		fmt.Fprintln(os.Stderr, i18n.T("err.watch_batch"))
+		os.Exit(2)
+	}
+
+	if cfg.Offline {
+		if missing := tools.OfflinePreflight(cfg, *symbol); len(missing) > 0 {
+			fmt.Fprintln(os.Stderr, i18n.T("err.offline_missing"))
+			for _, m := range missing {
+				fmt.Fprintf(os.Stderr, "  - %s\n", m)
+			}
+			os.Exit(1)
+		}
+	}
+
 	ctx := context.Background()
 	// Init eino devops server
-	err := devops.Init(ctx)
+	err = devops.Init(ctx)
 	if err != nil {
 		return
 	}
-	cfg := config.DefaultConfig()
 
 	if err := agents.InitChatModel(ctx, cfg); err != nil {
 		panic(err)
 	}
 
-	symbol := "CRCL.US"
-	tradeDate := "2025-12-15"
+	// 结构化输出时进度流写到 stderr，保证 stdout 只有可解析的结果
+	progress := os.Stdout
+	if format != outputText {
+		progress = os.Stderr
+	}
+	emit := newStreamPrinter(progress, useColor(progress), *plain).Emit
+	if *raw {
+		emit = rawEmit
+	}
+
+	res := analyze(ctx, cfg, *symbol, *tradeDate, emit)
+	if format != outputText {
+		if werr := writeStructured(os.Stdout, format, res); werr != nil {
+			fmt.Fprintln(os.Stderr, werr)
+		}
+		if res.Error != "" {
+			os.Exit(1)
+		}
+		return
+	}
+	writeText(os.Stdout, res)
 
+	// Blocking process exits
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	<-sigs
+}
+
+// analyze 运行一次完整编排，emit 接收流式事件，opts 为额外的编排选项（如统计用量的回调）
+func analyze(ctx context.Context, cfg *config.Config, symbol, tradeDate string, emit func(string, *models.ChatResp), opts ...compose.Option) analyzeResult {
+	userPrompt := fmt.Sprintf("Analyze trading opportunities for %s on %s", symbol, tradeDate)
+	return analyzePrompt(ctx, cfg, symbol, tradeDate, userPrompt, emit, opts...)
+}
+
+// analyzePrompt 与 analyze 相同，但使用调用方给出的用户提示词（如 watch 刷新时附上新出现的信息）
+func analyzePrompt(ctx context.Context, cfg *config.Config, symbol, tradeDate, userPrompt string, emit func(string, *models.ChatResp), opts ...compose.Option) analyzeResult {
 	parsedDate, err := time.Parse("2006-01-02", tradeDate)
 	if err != nil {
-		panic(err)
+		return analyzeResult{Status: "error", Error: fmt.Sprintf("invalid date: %v", err)}
 	}
-	userPrompt := fmt.Sprintf("Analyze trading opportunities for %s on %s", symbol, tradeDate)
 
+	var finalState *models.TradingState
 	genFunc := func(ctx context.Context) *models.TradingState {
-		state := models.NewTradingState(symbol, parsedDate, userPrompt, cfg)
-		return state
+		finalState = models.NewTradingState(symbol, parsedDate, userPrompt, cfg)
+		return finalState
 	}
 
+	events := &rundir.Events{}
+	record := func(event string, data *models.ChatResp) {
+		events.Record(event, data)
+		if emit != nil {
+			emit(event, data)
+		}
+	}
 	to := graph.NewTradingOrchestrator[string, string, *models.TradingState](ctx, genFunc, cfg)
-	_, err = to.Stream(ctx, "Analyze trading opportunities for UI on 2025-09-23",
-		compose.WithCallbacks(&graph.LoggerCallback{
-			Emit: func(event string, data *models.ChatResp) {
-				if data == nil {
-					fmt.Printf("[event=%s] <nil>\n", event)
-					return
-				}
-				payload, _ := json.Marshal(data)
-				fmt.Printf("[event=%s] %s\n", event, string(payload))
-			},
-		}),
+	logger := &graph.LoggerCallback{Emit: record}
+	_, err = to.Stream(ctx, userPrompt,
+		append([]compose.Option{compose.WithCallbacks(logger)}, opts...)...,
 	)
+	// 等待最后几段流式输出的事件，events.jsonl 才完整
+	logger.Wait()
+
+	res := analyzeResult{Status: "completed", Report: report.FromState(finalState)}
 	if err != nil {
-		fmt.Println("Error:", err)
+		res.Status, res.Error = "error", err.Error()
+	}
+	// 出错的运行也保留已取得的工具数据，便于排查
+	if cfg.ExportToolData {
+		if path, berr := bundle.Write(context.WithoutCancel(ctx), cfg, finalState, ""); berr != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("result.bundle_failed", berr))
+		} else if res.Report != nil {
+			res.Report.DataBundle = path
+		} else if path != "" {
+			fmt.Fprintln(os.Stderr, i18n.T("result.data_bundle", path))
+		}
+	}
+	// 运行目录最后写入，清单才能列出上面的数据包
+	if finalState != nil {
+		if dir, ferr := rundir.Finish(context.WithoutCancel(ctx), cfg, finalState, res.Report, events, "", err); ferr != nil {
+			fmt.Fprintln(os.Stderr, i18n.T("result.run_dir_failed", ferr))
+		} else {
+			res.RunDir = rundir.Location(cfg, dir)
+		}
 	}
+	return res
+}
 
-	// Blocking process exits
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
-	<-sigs
+// dateFlagIfSet 仅在显式传入 -date 时返回其值，否则使用最新交易日
+func dateFlagIfSet(date string) string {
+	return flagIfSet("date", date)
+}
+
+// flagIfSet 仅在显式传入该参数时返回其值，否则返回空串而不是默认值
+func flagIfSet(name, value string) string {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	if !set {
+		return ""
+	}
+	return value
+}
+
+// initLocale 在定义参数前确定界面语言，使 -h 的说明也随之切换：
+// -lang 优先，其次配置文件（或 CORTEXGO_LOCALE）中的 locale，最后是 LANG 等环境变量
+func initLocale(args []string) {
+	var lang, configPath string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			break
+		}
+		if !strings.HasPrefix(args[i], "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if name != "lang" && name != "config" {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		if name == "lang" {
+			lang = value
+		} else {
+			configPath = value
+		}
+	}
+	if lang == "" {
+		if cfg, _, err := config.LoadResolved(configPath); err == nil {
+			lang = cfg.Locale
+		}
+	}
+	if lang == "" {
+		lang = i18n.Detect()
+	}
+	i18n.SetLocale(lang)
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runNews 单独运行新闻数据源并输出标题与情绪分
+func runNews(cfg *config.Config, params models.NewsListParams, format string) int {
+	resp, err := service.FetchNews(cfg, params)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, resp); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	tw := newTable(os.Stdout, false)
+	fmt.Fprintln(tw, i18n.T("news.header"))
+	for _, item := range resp.Items {
+		date := "-"
+		if !item.PublishedAt.IsZero() {
+			date = item.PublishedAt.Format("2006-01-02 15:04")
+			if item.Session != "" {
+				date += " " + item.Session
+			}
+		}
+		source := item.Source
+		if item.SourceTier != "" {
+			source += " [" + item.SourceTier + "]"
+		}
+		fmt.Fprintf(tw, "%s\t%+.2f\t%s\t%s\n", date, item.Sentiment, source, item.Title)
+	}
+	tw.Flush()
+	fmt.Printf("\n%s\n", i18n.T("news.summary", len(resp.Items), resp.Source, resp.AvgSentiment))
+	if resp.Path != "" {
+		fmt.Println(i18n.T("news.exported", resp.Path))
+	}
+	return 0
+}
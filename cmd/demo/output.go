@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/pkg/i18n"
+	"gopkg.in/yaml.v3"
+)
+
+// 输出格式：text 为面向终端的流式文本，json/yaml 为便于脚本与 CI 解析的结构化结果
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+func parseOutputFormat(s string) (string, error) {
+	switch f := strings.ToLower(strings.TrimSpace(s)); f {
+	case "", outputText:
+		return outputText, nil
+	case outputJSON, outputYAML:
+		return f, nil
+	default:
+		return "", errors.New(i18n.T("err.output_format", s))
+	}
+}
+
+// analyzeResult 结构化输出的分析结果
+type analyzeResult struct {
+	Status string         `json:"status"`
+	Error  string         `json:"error,omitempty"`
+	Report *report.Report `json:"report,omitempty"`
+	// RunDir 本次运行的产物目录（状态、事件、各 agent 报告与清单）
+	RunDir string `json:"run_dir,omitempty"`
+}
+
+// runPrintEnv 打印每个配置字段对应的 CORTEXGO_* 环境变量
+func runPrintEnv(format string) int {
+	vars := config.EnvVars()
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, vars); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	tw := newTable(os.Stdout, false)
+	fmt.Fprintln(tw, i18n.T("config.env_header"))
+	for _, v := range vars {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", v.Name, v.Field, v.Type)
+	}
+	tw.Flush()
+	return 0
+}
+
+// runConfigSchema 输出配置的 JSON Schema；yaml 输出时按 yaml 编码，其余均为缩进 JSON
+func runConfigSchema(format string) int {
+	if format == outputText {
+		format = outputJSON
+	}
+	if err := writeStructured(os.Stdout, format, config.Schema()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// runValidate 校验生效配置并列出全部违规字段及其来源（default/file/env）
+func runValidate(configPath string, strict bool, format string) int {
+	report, err := config.ValidateResolved(configPath, strict)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		source := report.Path
+		if source == "" {
+			source = i18n.T("config.defaults")
+		}
+		if report.OK {
+			fmt.Println(i18n.T("config.ok", source))
+		} else {
+			fmt.Println(i18n.T("config.problems", source, len(report.Errors)))
+			tw := newTable(os.Stdout, false)
+			fmt.Fprintln(tw, i18n.T("config.problems_header"))
+			for _, e := range report.Errors {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Field, e.Source, e.Rule, e.Message)
+			}
+			tw.Flush()
+		}
+	}
+	if !report.OK {
+		return 1
+	}
+	return 0
+}
+
+// writeStructured 以 json 或 yaml 输出 v；yaml 复用 json tag 作为字段名
+func writeStructured(w io.Writer, format string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if format == outputJSON {
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeText 终端友好的结果摘要
+func writeText(w io.Writer, res analyzeResult) {
+	if res.Error != "" {
+		fmt.Fprintln(w, i18n.T("result.error"), res.Error)
+		return
+	}
+	if res.Report == nil {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", res.Report.Headline())
+	if res.Report.DataBundle != "" {
+		fmt.Fprintln(w, i18n.T("result.data_bundle", res.Report.DataBundle))
+	}
+	if res.RunDir != "" {
+		fmt.Fprintln(w, i18n.T("result.run_dir", res.RunDir))
+	}
+}
+
+// redactedConfig 输出配置时隐藏密钥类字段
+func redactedConfig(cfg *config.Config) config.Config {
+	c := *cfg
+	for _, field := range []*string{
+		&c.LongportAppSecret, &c.LongportAccessToken, &c.DeepSeekAPIKey, &c.FinnhubAPIKey, &c.FMPAPIKey,
+		&c.SMTPPassword, &c.WebhookSecret, &c.ObjstoreSecretKey, &c.EncryptionKey,
+	} {
+		if *field != "" {
+			*field = "***"
+		}
+	}
+	c.LongportAccounts = slices.Clone(c.LongportAccounts)
+	for i := range c.LongportAccounts {
+		for _, field := range []*string{&c.LongportAccounts[i].AppSecret, &c.LongportAccounts[i].AccessToken} {
+			if *field != "" {
+				*field = "***"
+			}
+		}
+	}
+	return c
+}
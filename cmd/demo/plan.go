@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runPlan 打印执行计划（dry-run），不调用模型与数据源
+func runPlan(cfg *config.Config, params models.AgentPlanParams, format string) int {
+	plan, err := service.BuildPlan(cfg, params)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, plan); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Print(i18n.T("plan.title", plan.Symbol, plan.TradeDate, plan.Depth, plan.MaxToolSteps))
+	if plan.Offline {
+		fmt.Print(i18n.T("plan.offline"))
+	}
+	fmt.Print("\n\n")
+	tw := newTable(os.Stdout, false)
+	fmt.Fprintln(tw, i18n.T("plan.steps_header"))
+	for _, s := range plan.Steps {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d/%d\t%s\n",
+			s.Stage, s.Agent, s.Model, s.Calls, s.InputTokens, s.OutputTokens, strings.Join(s.Tools, ","))
+	}
+	tw.Flush()
+
+	fmt.Println()
+	tw = newTable(os.Stdout, false)
+	fmt.Fprintln(tw, i18n.T("plan.sources_header"))
+	for _, s := range plan.Sources {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Name, s.Mode, s.Detail)
+	}
+	tw.Flush()
+
+	fmt.Printf("\n%s\n", i18n.T("plan.estimate",
+		plan.LLMCalls, plan.InputTokens, plan.OutputTokens, plan.EstimatedCostUSD))
+	for _, w := range plan.Warnings {
+		fmt.Println(i18n.T("plan.warning", w))
+	}
+	return 0
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runPortfolio 查看、同步或导入持仓：show 读取本地快照，sync 拉取长桥账户，其余视为 CSV 文件路径
+func runPortfolio(cfg *config.Config, mode, format string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var (
+		p   *models.Portfolio
+		err error
+	)
+	switch {
+	case mode == "show":
+		p, err = service.LoadPortfolio(ctx)
+	case mode == "sync" || strings.HasPrefix(mode, "sync:"):
+		// sync:<账户名> 读取 longport_accounts 中指定的账户
+		account := strings.TrimPrefix(strings.TrimPrefix(mode, "sync"), ":")
+		p, err = service.SyncPortfolioFrom(ctx, cfg, models.PortfolioSyncParams{Source: models.PortfolioSourceLongport, Account: account})
+	case strings.EqualFold(filepath.Ext(mode), ".csv"):
+		p, err = service.SyncPortfolioFrom(ctx, cfg, models.PortfolioSyncParams{Source: models.PortfolioSourceCSV, Path: mode})
+	default:
+		fmt.Fprintln(os.Stderr, i18n.T("err.portfolio_mode", mode))
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, p); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Println(i18n.T("portfolio.summary", p.Source, p.SyncedAt.Format("2006-01-02 15:04")))
+	if p.NetAssets > 0 {
+		fmt.Println(i18n.T("portfolio.net_assets", p.Currency, p.NetAssets))
+	}
+	fmt.Println()
+	if len(p.Positions) == 0 {
+		fmt.Println(i18n.T("portfolio.empty"))
+	} else {
+		tw := newTable(os.Stdout, false)
+		fmt.Fprintln(tw, i18n.T("portfolio.header"))
+		for _, pos := range p.Positions {
+			fmt.Fprintf(tw, "%s\t%s\t%g\t%g\t%.2f\t%s\t\n", pos.Symbol, pos.Name, pos.Quantity, pos.AvailableQuantity, pos.CostPrice, pos.Currency)
+		}
+		tw.Flush()
+	}
+	if len(p.Cash) > 0 {
+		fmt.Println()
+		tw := newTable(os.Stdout, false)
+		fmt.Fprintln(tw, i18n.T("portfolio.cash"))
+		for _, c := range p.Cash {
+			fmt.Fprintf(tw, "%s\t%.2f\t%.2f\t\n", c.Currency, c.Available, c.Frozen)
+		}
+		tw.Flush()
+	}
+	return 0
+}
+
+// runPortfolioRisk 输出持仓（或 -watchlist 列表）的收益相关性矩阵与集中度提示
+func runPortfolioRisk(cfg *config.Config, params models.PortfolioRiskParams, format string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	m, err := service.AssessPortfolioRisk(ctx, cfg, params)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, m); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Println(i18n.T("portfolio.risk", m.Window, m.AsOf, m.AverageCorrelation))
+	fmt.Println()
+	tw := newTable(os.Stdout, false)
+	fmt.Fprintln(tw, "\t"+strings.Join(m.Symbols, "\t")+"\t")
+	for i, row := range m.Matrix {
+		fmt.Fprint(tw, m.Symbols[i])
+		for _, c := range row {
+			fmt.Fprintf(tw, "\t%.2f", c)
+		}
+		fmt.Fprintln(tw, "\t")
+	}
+	tw.Flush()
+	if len(m.Missing) > 0 {
+		fmt.Println(i18n.T("portfolio.missing", strings.Join(m.Missing, ", ")))
+	}
+	fmt.Println()
+	if len(m.Warnings) == 0 {
+		fmt.Println(i18n.T("portfolio.no_flags"))
+		return 0
+	}
+	fmt.Println(i18n.T("portfolio.flags"))
+	for _, w := range m.Warnings {
+		fmt.Println("- " + w)
+	}
+	return 0
+}
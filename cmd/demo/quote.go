@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runQuote 输出实时行情，symbols 以逗号分隔
+func runQuote(cfg *config.Config, symbols, format string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	resp, err := service.FetchQuotes(ctx, cfg, strings.Split(symbols, ","))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if format != outputText {
+		if err := writeStructured(os.Stdout, format, resp); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+	tw := newTable(os.Stdout, true)
+	fmt.Fprintln(tw, i18n.T("quote.header"))
+	for _, q := range resp.Quotes {
+		extended := "-"
+		if len(q.Extended) > 0 {
+			parts := make([]string, len(q.Extended))
+			for i, e := range q.Extended {
+				parts[i] = fmt.Sprintf("%s %.2f (%+.2f%%)", e.Session, e.Last, e.ChangePct)
+			}
+			extended = strings.Join(parts, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%.2f\t%+.2f\t%+.2f%%\t%d\t%.2f\t%.2f\t%s\t%s\t\n",
+			q.Symbol, q.Last, q.Change, q.ChangePct, q.Volume, q.Week52Low, q.Week52High, q.Session, extended)
+	}
+	tw.Flush()
+	return 0
+}
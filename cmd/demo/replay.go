@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// replayEvent 回放时交给 streamPrinter 的一个事件
+type replayEvent struct {
+	event string
+	data  *models.ChatResp
+}
+
+// replayStep 一条存储消息还原出的事件；pause 为该消息距上一条消息的原始间隔
+type replayStep struct {
+	pause  time.Duration
+	events []replayEvent
+}
+
+// runReplay 实现 replay 子命令：按原始节奏（可加速）把一次历史分析的消息重新输出到终端
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), i18n.T("replay.usage", os.Args[0]))
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", i18n.T("flag.config"))
+	speed := fs.Float64("speed", 1, i18n.T("flag.replay_speed"))
+	maxPause := fs.Float64("max-pause", 5, i18n.T("flag.replay_max_pause"))
+	raw := fs.Bool("raw", false, i18n.T("flag.raw"))
+	plain := fs.Bool("plain", false, i18n.T("flag.plain"))
+	fs.String("lang", "", i18n.T("flag.lang")) // 已在 initLocale 中读取
+
+	// run-id 可以写在选项之前或之后
+	var positional []string
+	for rest := args; ; {
+		if err := fs.Parse(rest); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+	runID, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil || runID <= 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("replay.bad_id", positional[0]))
+		return 2
+	}
+
+	if _, err := loadConfig(*configPath, configOverrides{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	info, err := service.LoadHistoryInfo(ctx, runID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	steps := replaySteps(info.Messages)
+	if len(steps) == 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("replay.empty", runID))
+		return 1
+	}
+	s := info.Session
+	fmt.Fprintln(os.Stderr, i18n.T("replay.start", runID, s.Symbol, s.TradeDate, s.Status, len(info.Messages)))
+
+	emit := newStreamPrinter(os.Stdout, useColor(os.Stdout), *plain).Emit
+	if *raw {
+		emit = rawEmit
+	}
+	if err := playReplay(ctx, steps, emit, *speed, time.Duration(*maxPause*float64(time.Second)), sleepContext); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("replay.interrupted", runID))
+		return 130
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("replay.done", runID, s.Status))
+	return 0
+}
+
+// replaySteps 把存储的消息还原为流式事件：助手消息按行拆成分片并列出其中的工具调用，
+// 工具结果与错误各为一个事件；用户提示词已在开头打印，不再回放
+func replaySteps(msgs []models.HistoryMessage) []replayStep {
+	var (
+		steps []replayStep
+		prev  time.Time
+	)
+	for _, m := range msgs {
+		at, err := time.Parse(time.RFC3339, m.CreatedAt)
+		var pause time.Duration
+		if err == nil {
+			if !prev.IsZero() && at.After(prev) {
+				pause = at.Sub(prev)
+			}
+			prev = at
+		}
+
+		var events []replayEvent
+		switch {
+		case m.Role == "user":
+			continue
+		case m.Role == "system" || m.Status == "error":
+			events = append(events, replayEvent{"error", &models.ChatResp{AgentName: m.Agent, Role: m.Role, Content: m.Content}})
+		case m.Role == "assistant":
+			for _, line := range strings.SplitAfter(m.Content, "\n") {
+				if line != "" {
+					events = append(events, replayEvent{"message_chunk", &models.ChatResp{AgentName: m.Agent, Role: m.Role, Content: line}})
+				}
+			}
+			stop := "messgae_chunk_stop"
+			if len(m.ToolCalls) > 0 {
+				events = append(events, replayEvent{"tool_call", &models.ChatResp{AgentName: m.Agent, Role: m.Role, ToolCalls: m.ToolCalls}})
+				stop = "tool_call_stop"
+			}
+			if len(events) == 0 {
+				continue
+			}
+			events = append(events, replayEvent{stop, &models.ChatResp{AgentName: m.Agent, Role: m.Role}})
+		default:
+			events = append(events, replayEvent{"tool_call_result_final", &models.ChatResp{
+				AgentName:  m.Agent,
+				Role:       m.Role,
+				Content:    m.Content,
+				ToolCallId: m.ToolCallId,
+				ToolName:   m.ToolName,
+			}})
+		}
+		steps = append(steps, replayStep{pause: pause, events: events})
+	}
+	return steps
+}
+
+// playReplay 依次输出各步事件。每步的间隔除以 speed 且不超过 maxPause，并平摊到该步的文本分片之间，
+// 还原逐字输出的效果；speed <= 0 时不停顿。ctx 取消时返回其错误
+func playReplay(ctx context.Context, steps []replayStep, emit func(string, *models.ChatResp), speed float64, maxPause time.Duration, sleep func(context.Context, time.Duration) error) error {
+	for _, step := range steps {
+		var pause time.Duration
+		if speed > 0 {
+			pause = min(time.Duration(float64(step.pause)/speed), maxPause)
+		}
+		chunks := 0
+		for _, ev := range step.events {
+			if ev.event == "message_chunk" {
+				chunks++
+			}
+		}
+		if chunks == 0 {
+			chunks = 1
+		}
+		for i, ev := range step.events {
+			if pause > 0 && (ev.event == "message_chunk" || i == 0) {
+				if err := sleep(ctx, pause/time.Duration(chunks)); err != nil {
+					return err
+				}
+			}
+			emit(ev.event, ev.data)
+		}
+	}
+	return ctx.Err()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
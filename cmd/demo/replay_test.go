@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+func TestReplayRendersStoredRunWithScaledPauses(t *testing.T) {
+	call := &models.ToolCall{Id: "c1", Type: "function"}
+	call.Function.Name = "get_market_data"
+	call.Function.Arguments = "{\n  \"symbol\": \"AAPL.US\"\n}"
+	msgs := []models.HistoryMessage{
+		{Role: "user", Content: "Analyze AAPL.US", CreatedAt: "2025-12-15T01:00:00Z"},
+		{Role: "assistant", Agent: "market_analyst", ToolCalls: []*models.ToolCall{call}, CreatedAt: "2025-12-15T01:00:04Z"},
+		{Role: "tool", Agent: "market_analyst", ToolName: "get_market_data", Content: "close 190", CreatedAt: "2025-12-15T01:00:06Z"},
+		{Role: "assistant", Agent: "bull_researcher", Content: "Trend is up\nBuy the dip", CreatedAt: "2025-12-15T01:01:06Z"},
+		{Role: "system", Content: "rate limited", Status: "error", CreatedAt: "2025-12-15T01:01:06Z"},
+	}
+	steps := replaySteps(msgs)
+	if len(steps) != 4 {
+		t.Fatalf("expected 4 steps (user prompt skipped), got %d", len(steps))
+	}
+
+	var buf bytes.Buffer
+	var slept []time.Duration
+	sleep := func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	p := newStreamPrinter(&buf, false, true)
+	if err := playReplay(context.Background(), steps, p.Emit, 2, 10*time.Second, sleep); err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+
+	want := "[market_analyst] -> call get_market_data { \"symbol\": \"AAPL.US\" }\n" +
+		"[market_analyst] -> get_market_data returned 9 bytes\n" +
+		"[bull_researcher] Trend is up\n" +
+		"[bull_researcher] Buy the dip\n" +
+		"[error] rate limited\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("output mismatch\ngot:  %q\nwant: %q", got, want)
+	}
+	// 4s/2、2s/2，60s/2 超过上限按 10s 计并平摊到两行文本
+	wantSleeps := []time.Duration{2 * time.Second, time.Second, 5 * time.Second, 5 * time.Second}
+	if len(slept) != len(wantSleeps) {
+		t.Fatalf("expected pauses %v, got %v", wantSleeps, slept)
+	}
+	for i := range wantSleeps {
+		if slept[i] != wantSleeps[i] {
+			t.Errorf("pause %d: expected %v, got %v", i, wantSleeps[i], slept[i])
+		}
+	}
+
+	slept = nil
+	if err := playReplay(context.Background(), steps, func(string, *models.ChatResp) {}, 0, 10*time.Second, sleep); err != nil || len(slept) != 0 {
+		t.Errorf("speed 0 should not pause, got %v (err %v)", slept, err)
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/regime"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runSectors 实现 sectors 子命令：板块 ETF 多周期表现与轮动排名，-analyze N 时对排名前 N 的板块 ETF 运行 quick 深度的批量分析，
+// 作为个股分析前自上而下的起点
+func runSectors(args []string) int {
+	fs := flag.NewFlagSet("sectors", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), i18n.T("sectors.usage", os.Args[0]))
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", i18n.T("flag.config"))
+	output := fs.String("output", outputText, i18n.T("flag.output"))
+	market := fs.String("market", "US", i18n.T("flag.market"))
+	date := fs.String("date", "", i18n.T("flag.date"))
+	analyzeTop := fs.Int("analyze", 0, i18n.T("flag.sectors_analyze"))
+	concurrency := fs.Int("c", 4, i18n.T("flag.c"))
+	adaptive := fs.Bool("adaptive", true, i18n.T("flag.adaptive"))
+	llm := fs.String("llm", "", i18n.T("flag.llm"))
+	dataProvider := fs.String("data", "", i18n.T("flag.data"))
+	offline := fs.Bool("offline", false, i18n.T("flag.offline"))
+	fs.String("lang", "", i18n.T("flag.lang")) // 已在 initLocale 中读取
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 || *analyzeTop < 0 {
+		fs.Usage()
+		return 2
+	}
+	format, err := parseOutputFormat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if !config.ValidLLMProvider(*llm) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.llm", *llm))
+		return 2
+	}
+	if !config.ValidDataProvider(*dataProvider) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.data", *dataProvider))
+		return 2
+	}
+	cfg, err := loadConfig(*configPath, configOverrides{offline: *offline, llm: *llm, dataProvider: *dataProvider})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	r, err := service.ComputeSectorRotation(ctx, cfg, models.SectorRotationParams{Market: *market, TradeDate: *date})
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if *analyzeTop == 0 {
+		if format != outputText {
+			if err := writeStructured(os.Stdout, format, r); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+			return 0
+		}
+		writeRotation(r)
+		return 0
+	}
+
+	// 领涨板块的快速分析走批量流程：断点续跑、汇总报告与结构化输出与 -batch 一致
+	if format == outputText {
+		writeRotation(r)
+		fmt.Println()
+	}
+	leaders := regime.Leaders(r, *analyzeTop)
+	fmt.Fprintln(os.Stderr, i18n.T("sectors.analyze", len(leaders), strings.Join(leaders, ", ")))
+	cfg.Depth = config.DepthQuick
+	tradeDate := *date
+	if tradeDate == "" {
+		tradeDate = r.AsOf
+	}
+	return runBatch(cfg, batchOptions{
+		Symbols:     strings.Join(leaders, ","),
+		TradeDate:   tradeDate,
+		Concurrency: *concurrency,
+		Adaptive:    *adaptive,
+		Rotation:    r,
+	}, format)
+}
+
+func writeRotation(r *models.SectorRotation) {
+	fmt.Println(i18n.T("sectors.summary", r.Market, r.AsOf, r.Benchmark, i18n.T("sectors.tilt."+r.Tilt)))
+	tw := newTable(os.Stdout, false)
+	header := i18n.T("sectors.header")
+	for _, w := range r.Windows {
+		header += fmt.Sprintf("\t%dD", w)
+	}
+	// 相对基准的超额收益只列出决定轮动阶段的两个周期
+	var rel []int
+	for i, w := range r.Windows {
+		if w == regime.MomentumWindow || w == regime.StrengthWindow {
+			rel = append(rel, i)
+			header += "\t" + i18n.T("sectors.header_rel", w)
+		}
+	}
+	fmt.Fprintln(tw, header)
+	bench := fmt.Sprintf("-\t%s\t%s\t\t", r.Benchmark, i18n.T("sectors.benchmark"))
+	for _, v := range r.BenchmarkReturns {
+		bench += fmt.Sprintf("\t%+.1f%%", v)
+	}
+	fmt.Fprintln(tw, bench+strings.Repeat("\t", len(rel)))
+	for _, s := range r.Sectors {
+		name := s.Name
+		if s.Defensive {
+			name += " *"
+		}
+		line := fmt.Sprintf("%d\t%s\t%s\t%s\t%.1f", s.Rank, s.Symbol, name, i18n.T("sectors.phase."+s.Phase), s.Score)
+		for _, v := range s.Returns {
+			line += fmt.Sprintf("\t%+.1f%%", v)
+		}
+		for _, i := range rel {
+			line += fmt.Sprintf("\t%+.1f%%", s.Relative[i])
+		}
+		fmt.Fprintln(tw, line)
+	}
+	tw.Flush()
+	fmt.Println(i18n.T("sectors.legend"))
+	if len(r.Missing) > 0 {
+		fmt.Println(i18n.T("index.missing", strings.Join(r.Missing, ", ")))
+	}
+}
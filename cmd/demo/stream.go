@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// agentColors ANSI 前景色，按 agent 名称哈希分配，保证同一 agent 颜色稳定
+var agentColors = []string{"\033[36m", "\033[32m", "\033[33m", "\033[35m", "\033[34m", "\033[96m", "\033[92m", "\033[93m"}
+
+const ansiReset = "\033[0m"
+
+// streamPrinter 将 LoggerCallback 的分片事件实时输出到终端，每行带 agent 前缀
+type streamPrinter struct {
+	mu          sync.Mutex
+	out         io.Writer
+	color       bool
+	plain       bool   // 去除 emoji 与制表符，适合日志、CI 与读屏软件
+	agent       string // 当前正在输出的 agent
+	atLineStart bool
+}
+
+func newStreamPrinter(out io.Writer, color, plain bool) *streamPrinter {
+	return &streamPrinter{out: out, color: color && !plain, plain: plain, atLineStart: true}
+}
+
+// Emit 实现 graph.LoggerCallback.Emit
+func (p *streamPrinter) Emit(event string, data *models.ChatResp) {
+	if data == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch event {
+	case "message_chunk":
+		p.switchAgent(data.AgentName)
+		p.write(data.AgentName, data.Content)
+	case "tool_call_stop":
+		p.endLine()
+	case "messgae_chunk_stop":
+		p.endLine()
+		p.agent = ""
+	case "tool_call":
+		// 仅 replay 回放时产生：实时运行中工具调用参数是分片到达的，不逐片输出
+		p.endLine()
+		for _, tc := range data.ToolCalls {
+			p.write(data.AgentName, i18n.T("stream.tool_call", tc.Function.Name, compactArgs(tc.Function.Arguments))+"\n")
+		}
+	case "tool_call_result_final":
+		p.endLine()
+		p.write(data.AgentName, i18n.T("stream.tool_result", toolName(data), len(data.Content))+"\n")
+	case "error":
+		p.endLine()
+		p.write("error", data.Content+"\n")
+	}
+}
+
+// switchAgent 切换 agent 时换行，避免不同 agent 的分片混在同一行
+func (p *streamPrinter) switchAgent(agent string) {
+	if agent == p.agent {
+		return
+	}
+	p.endLine()
+	p.agent = agent
+}
+
+func (p *streamPrinter) endLine() {
+	if !p.atLineStart {
+		fmt.Fprintln(p.out)
+		p.atLineStart = true
+	}
+}
+
+// write 输出文本，在每行行首补上 agent 前缀
+func (p *streamPrinter) write(agent, text string) {
+	if p.plain {
+		text = stripDecorations(text)
+	}
+	for text != "" {
+		if p.atLineStart {
+			fmt.Fprint(p.out, p.prefix(agent))
+			p.atLineStart = false
+		}
+		line, rest, found := strings.Cut(text, "\n")
+		fmt.Fprint(p.out, line)
+		if !found {
+			return
+		}
+		fmt.Fprintln(p.out)
+		p.atLineStart = true
+		text = rest
+	}
+}
+
+func (p *streamPrinter) prefix(agent string) string {
+	if agent == "" {
+		agent = "agent"
+	}
+	if !p.color {
+		return "[" + agent + "] "
+	}
+	h := fnv.New32a()
+	h.Write([]byte(agent))
+	return agentColors[h.Sum32()%uint32(len(agentColors))] + "[" + agent + "]" + ansiReset + " "
+}
+
+func toolName(data *models.ChatResp) string {
+	if data.ToolName != "" {
+		return data.ToolName
+	}
+	return "tool"
+}
+
+// compactArgs 把工具参数压成一行，过长时截断
+func compactArgs(args string) string {
+	const limit = 160
+	args = strings.Join(strings.Fields(args), " ")
+	if r := []rune(args); len(r) > limit {
+		args = string(r[:limit]) + "..."
+	}
+	return args
+}
+
+// rawEmit 原始事件模式：每个事件一行 JSON，便于调试回调
+func rawEmit(event string, data *models.ChatResp) {
+	if data == nil {
+		fmt.Printf("[event=%s] <nil>\n", event)
+		return
+	}
+	payload, _ := json.Marshal(data)
+	fmt.Printf("[event=%s] %s\n", event, string(payload))
+}
+
+// useColor NO_COLOR（https://no-color.org）或输出不是终端时关闭颜色
+func useColor(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// boxDrawing 常见制表符到 ASCII 的替换
+var boxDrawing = strings.NewReplacer(
+	"─", "-", "━", "-", "═", "=", "│", "|", "┃", "|", "║", "|",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+", "├", "+", "┤", "+", "┬", "+", "┴", "+", "┼", "+",
+	"╔", "+", "╗", "+", "╚", "+", "╝", "+", "╠", "+", "╣", "+", "╦", "+", "╩", "+", "╬", "+",
+	"•", "-", "→", "->", "←", "<-",
+)
+
+// stripDecorations 去掉 emoji、变体选择符与零宽连接符，并把制表符换成 ASCII
+func stripDecorations(s string) string {
+	s = boxDrawing.Replace(s)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 0x1F000 && r <= 0x1FAFF, // emoji、符号与象形文字
+			r >= 0x2600 && r <= 0x27BF, // 杂项符号与装饰符号
+			r >= 0x2B00 && r <= 0x2BFF, // 箭头与星形等
+			r >= 0x2500 && r <= 0x259F, // 其余制表符与方块
+			r >= 0xFE00 && r <= 0xFE0F, // 变体选择符
+			r == 0x200D, r == 0x20E3:
+			return -1
+		}
+		return r
+	}, s)
+}
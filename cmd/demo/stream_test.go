@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+func TestStreamPrinterPrefixesLinesPerAgent(t *testing.T) {
+	var buf bytes.Buffer
+	p := newStreamPrinter(&buf, false, true)
+	p.Emit("message_chunk", &models.ChatResp{AgentName: "market_analyst", Content: "📈 Trend is"})
+	p.Emit("message_chunk", &models.ChatResp{AgentName: "market_analyst", Content: " up\n│ RSI 61"})
+	p.Emit("message_chunk", &models.ChatResp{AgentName: "news_analyst", Content: "No news"})
+	p.Emit("messgae_chunk_stop", &models.ChatResp{AgentName: "news_analyst"})
+
+	want := "[market_analyst]  Trend is up\n[market_analyst] | RSI 61\n[news_analyst] No news\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("output mismatch\ngot:  %q\nwant: %q", got, want)
+	}
+}
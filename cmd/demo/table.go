@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// table 与 tabwriter 用法相同（单元格以 \t 结尾），但按终端显示宽度对齐，
+// 中文表头与内容占两列时不会错位
+type table struct {
+	out        io.Writer
+	buf        bytes.Buffer
+	alignRight bool
+}
+
+func newTable(w io.Writer, alignRight bool) *table {
+	return &table{out: w, alignRight: alignRight}
+}
+
+func (t *table) Write(p []byte) (int, error) {
+	return t.buf.Write(p)
+}
+
+// Flush 按列宽补齐空格后输出，列间距 2；每行最后一个 \t 之后的文本不参与对齐
+func (t *table) Flush() error {
+	text := strings.TrimSuffix(t.buf.String(), "\n")
+	t.buf.Reset()
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	rows := make([][]string, len(lines))
+	var widths []int
+	for i, line := range lines {
+		rows[i] = strings.Split(line, "\t")
+		for j, cell := range rows[i][:len(rows[i])-1] {
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], displayWidth(cell))
+		}
+	}
+	var b strings.Builder
+	for _, cells := range rows {
+		var line strings.Builder
+		for j, cell := range cells[:len(cells)-1] {
+			pad := strings.Repeat(" ", widths[j]-displayWidth(cell))
+			if t.alignRight {
+				line.WriteString(pad + cell + "  ")
+			} else {
+				line.WriteString(cell + pad + "  ")
+			}
+		}
+		line.WriteString(cells[len(cells)-1])
+		b.WriteString(strings.TrimRight(line.String(), " "))
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(t.out, b.String())
+	return err
+}
+
+// displayWidth 估算字符串在终端中的列数，东亚宽字符与 emoji 计为 2
+func displayWidth(s string) int {
+	w := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		switch {
+		case r >= 0x1100 && r <= 0x115F, r >= 0x2E80 && r <= 0xA4CF, r >= 0xAC00 && r <= 0xD7A3,
+			r >= 0xF900 && r <= 0xFAFF, r >= 0xFE30 && r <= 0xFE4F, r >= 0xFF00 && r <= 0xFF60,
+			r >= 0xFFE0 && r <= 0xFFE6, r >= 0x1F300 && r <= 0x1F64F, r >= 0x20000 && r <= 0x3FFFD:
+			w += 2
+		default:
+			w++
+		}
+	}
+	return w
+}
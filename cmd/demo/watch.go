@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/agents/delta"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/internal/watch"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/i18n"
+)
+
+// runWatch 实现 watch 子命令：持续监控单个标的，首次运行完整分析，之后按间隔增量拉取行情与新闻，
+// 只有出现重大变化（价格相对上次分析的涨跌、高可信度或措辞强烈的新闻）时才刷新：默认由增量分析
+// 基于上次完整报告与新数据更新结论，-full 时改为运行一次快速深度的完整分析
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), i18n.T("watch.usage", os.Args[0]))
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", i18n.T("flag.config"))
+	output := fs.String("output", outputText, i18n.T("flag.output"))
+	interval := fs.Duration("interval", watch.DefaultInterval, i18n.T("flag.watch_interval"))
+	move := fs.Float64("move", watch.DefaultMove, i18n.T("flag.watch_move"))
+	minQuality := fs.Float64("min-quality", watch.DefaultMinQuality, i18n.T("flag.watch_quality"))
+	depth := fs.String("depth", "", i18n.T("flag.depth"))
+	risk := fs.String("risk", "", i18n.T("flag.risk"))
+	full := fs.Bool("full", false, i18n.T("flag.watch_full"))
+	fs.String("lang", "", i18n.T("flag.lang")) // 已在 initLocale 中读取
+
+	// 标的可以写在选项之前或之后
+	var positional []string
+	rest := args
+	for {
+		if err := fs.Parse(rest); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		rest = fs.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	format, err := parseOutputFormat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *depth != "" && !config.ValidDepth(*depth) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.depth", *depth))
+		return 2
+	}
+	if !config.ValidRiskProfile(*risk) {
+		fmt.Fprintln(os.Stderr, i18n.T("err.risk", *risk))
+		return 2
+	}
+	cfg, err := loadConfig(*configPath, configOverrides{depth: *depth, risk: *risk})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := agents.InitChatModel(ctx, cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	symbol := strings.ToUpper(strings.TrimSpace(positional[0]))
+	// last 为最近一次完整分析的报告，earlier 为此后的增量结论
+	var (
+		last    *report.Report
+		earlier []*models.DeltaAnalysis
+	)
+	w, err := service.NewWatcher(cfg, symbol, func(ctx context.Context, u watch.Update) (string, error) {
+		if u.Kind == watch.Refresh && last != nil && !*full {
+			// 刷新只需要确认新信息是否改变结论：把上次报告与新数据交给增量分析，单次模型调用
+			d, err := delta.Analyze(ctx, last, delta.Input{Quote: u.Quote, BasePrice: u.BasePrice, News: u.News, Reasons: u.Reasons, Earlier: earlier})
+			if err != nil {
+				return "", err
+			}
+			earlier = append(earlier, d)
+			return d.Recommendation, nil
+		}
+		tradeDate := time.Now().Format("2006-01-02")
+		runCfg := cfg
+		if u.Kind == watch.Refresh {
+			// 完整刷新用快速深度控制成本
+			c := *cfg
+			c.Depth = config.DepthQuick
+			runCfg = &c
+		}
+		res := analyzePrompt(ctx, runCfg, symbol, tradeDate, watch.Prompt(symbol, tradeDate, u), nil)
+		if res.Error != "" {
+			return "", errors.New(res.Error)
+		}
+		if res.Report == nil {
+			return "", nil
+		}
+		last, earlier = res.Report, nil
+		return res.Report.Recommendation, nil
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	w.Move, w.MinQuality = *move, *minQuality
+	w.OnEvent = func(ev models.WatchEvent) {
+		if format != outputText {
+			if err := writeStructured(os.Stdout, format, ev); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			return
+		}
+		writeWatchEvent(ev)
+	}
+
+	*interval = max(*interval, watch.MinInterval)
+	fmt.Fprintln(os.Stderr, i18n.T("watch.started", symbol, *interval))
+	w.Run(ctx, *interval, func(err error) {
+		fmt.Fprintln(os.Stderr, err)
+	})
+	return 0
+}
+
+// writeWatchEvent 以文本输出一轮轮询的结果
+func writeWatchEvent(ev models.WatchEvent) {
+	at := ev.At.Format("15:04")
+	switch {
+	case ev.Analysis == "":
+		fmt.Println(i18n.T("watch.quiet", at, ev.Symbol, ev.Price, ev.MovePct, len(ev.NewItems)))
+	case ev.Error != "":
+		fmt.Println(i18n.T("watch.failed", at, ev.Symbol, ev.Price, ev.Analysis, ev.Error))
+	default:
+		rec := ev.Recommendation
+		if rec == "" {
+			rec = "-"
+		}
+		fmt.Println(i18n.T("watch.analyzed", at, ev.Symbol, ev.Price, ev.Analysis, rec))
+	}
+	if ev.Analysis != "" {
+		for _, r := range ev.Reasons {
+			fmt.Println(i18n.T("watch.reason", r))
+		}
+	}
+	if ev.NewsError != "" {
+		fmt.Println(i18n.T("watch.news_err", ev.NewsError))
+	}
+}
@@ -18,9 +18,12 @@ static void invokeCallback(EventCallback cb, char* topic, char* payload) {
 import "C"
 import (
 	"encoding/json"
+	"strings"
 	"unsafe"
 
 	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
 	"github.com/dyike/CortexGo/pkg/app"
 	"github.com/dyike/CortexGo/pkg/bridge"
 )
@@ -69,6 +72,33 @@ func RegisterCallback(cb C.EventCallback) {
 	globalCallback = cb
 }
 
+// CortexGoSubscribe 全局回调只接收匹配的事件；topics 以逗号分隔，同 events.subscribe
+//
+//export CortexGoSubscribe
+func CortexGoSubscribe(topics *C.char) *C.char {
+	return C.CString(Dispatch("events.subscribe", topicsParams(C.GoString(topics))))
+}
+
+// CortexGoUnsubscribe 全局回调不再接收匹配的事件；topics 以逗号分隔，同 events.unsubscribe
+//
+//export CortexGoUnsubscribe
+func CortexGoUnsubscribe(topics *C.char) *C.char {
+	return C.CString(Dispatch("events.unsubscribe", topicsParams(C.GoString(topics))))
+}
+
+// CortexGoSetVerbosity level 为 error/info/debug，同 events.verbosity
+//
+//export CortexGoSetVerbosity
+func CortexGoSetVerbosity(level *C.char) *C.char {
+	b, _ := json.Marshal(models.EventVerbosityParams{Level: C.GoString(level)})
+	return C.CString(Dispatch("events.verbosity", string(b)))
+}
+
+func topicsParams(list string) string {
+	b, _ := json.Marshal(models.EventSubscribeParams{Topics: strings.Split(list, ",")})
+	return string(b)
+}
+
 //export UpdateConfig
 func UpdateConfig(jsonStr *C.char) *C.char {
 	newCfg := C.GoString(jsonStr)
@@ -93,9 +123,127 @@ func Call(method *C.char, params *C.char) *C.char {
 	return C.CString(resp)
 }
 
+//export CortexGoAnalyzeAsync
+func CortexGoAnalyzeAsync(symbol *C.char, date *C.char, cb C.EventCallback) *C.char {
+	params := models.AgentInitParams{Symbol: C.GoString(symbol), TradeDate: C.GoString(date)}
+	result, err := service.AnalyzeAsync(params, callbackNotifier(cb))
+	if err != nil {
+		return C.CString(errResp(err))
+	}
+	return C.CString(jsonResp(200, "Ok", result))
+}
+
+//export CortexGoAnalyzeStart
+func CortexGoAnalyzeStart(params *C.char, cb C.EventCallback) *C.char {
+	result, err := service.StartAnalysis(C.GoString(params), callbackNotifier(cb))
+	if err != nil {
+		return C.CString(errResp(err))
+	}
+	return C.CString(jsonResp(200, "Ok", result))
+}
+
+//export CortexGoAnalysisStatus
+func CortexGoAnalysisStatus(analysisID *C.char) *C.char {
+	info, ok := service.GetRun(C.GoString(analysisID))
+	if !ok {
+		return C.CString(jsonResp(404, "analysis not running", nil))
+	}
+	return C.CString(jsonResp(200, "Ok", info))
+}
+
+// CortexGoListResults 同 agent.history.list，filter 为其入参 JSON（可为空）
+//
+//export CortexGoListResults
+func CortexGoListResults(filter *C.char) *C.char {
+	return C.CString(Dispatch("agent.history.list", C.GoString(filter)))
+}
+
+//export CortexGoGetResult
+func CortexGoGetResult(sessionID *C.char) *C.char {
+	return C.CString(Dispatch("results.info", sessionParams(C.GoString(sessionID))))
+}
+
+//export CortexGoDeleteResult
+func CortexGoDeleteResult(sessionID *C.char) *C.char {
+	return C.CString(Dispatch("agent.history.del", sessionParams(C.GoString(sessionID))))
+}
+
+func sessionParams(id string) string {
+	b, _ := json.Marshal(map[string]string{"session_id": id})
+	return string(b)
+}
+
+// callbackNotifier 事件只推送给本次调用的 cb，不经过 RegisterCallback 注册的全局回调
+func callbackNotifier(cb C.EventCallback) func(topic, payload string) {
+	return func(topic, payload string) {
+		cTopic := C.CString(topic)
+		cPayload := C.CString(payload)
+		defer C.free(unsafe.Pointer(cTopic))
+		defer C.free(unsafe.Pointer(cPayload))
+
+		C.invokeCallback(cb, cTopic, cPayload)
+	}
+}
+
+//export CortexGoCancel
+func CortexGoCancel(analysisID *C.char) *C.char {
+	id := C.GoString(analysisID)
+	return C.CString(jsonResp(200, "Ok", models.AgentCancelResponse{SessionId: id, Cancelled: service.CancelAnalysis(id)}))
+}
+
+// CortexGoGetVersion 同 system.version，不依赖 InitSDK，可在初始化前调用
+//
+//export CortexGoGetVersion
+func CortexGoGetVersion() *C.char {
+	return C.CString(Dispatch("system.version", ""))
+}
+
+// CortexGoGetCapabilities 同 system.capabilities，按当前配置报告可用数据源、工具、方法与事件
+//
+//export CortexGoGetCapabilities
+func CortexGoGetCapabilities() *C.char {
+	return C.CString(Dispatch("system.capabilities", ""))
+}
+
+// CortexGoHealth 同 system.health，只运行本地检查，data.ok=false 表示存在失败项
+//
+//export CortexGoHealth
+func CortexGoHealth() *C.char {
+	return C.CString(Dispatch("system.health", ""))
+}
+
 //export FreeString
 func FreeString(str *C.char) {
 	C.free(unsafe.Pointer(str))
 }
 
+// CortexGoFreeString 与 FreeString 相同，便于与 CortexGo* 前缀的导出函数配套使用
+//
+//export CortexGoFreeString
+func CortexGoFreeString(str *C.char) {
+	FreeString(str)
+}
+
+//export CortexGoCallInto
+func CortexGoCallInto(method *C.char, params *C.char, buf *C.char, bufLen C.size_t) C.longlong {
+	return copyInto(Dispatch(C.GoString(method), C.GoString(params)), buf, bufLen)
+}
+
+//export CortexGoGetConfigInto
+func CortexGoGetConfigInto(buf *C.char, bufLen C.size_t) C.longlong {
+	b, _ := json.Marshal(config.Get())
+	return copyInto(string(b), buf, bufLen)
+}
+
+// copyInto 按 snprintf 语义写入调用方提供的缓冲区：最多写 bufLen-1 字节并以 NUL 结尾，
+// 返回完整结果的字节数（不含 NUL）；返回值 >= bufLen 表示结果被截断
+func copyInto(s string, buf *C.char, bufLen C.size_t) C.longlong {
+	if buf != nil && bufLen > 0 {
+		dst := unsafe.Slice((*byte)(unsafe.Pointer(buf)), int(bufLen))
+		n := copy(dst[:len(dst)-1], s)
+		dst[n] = 0
+	}
+	return C.longlong(len(s))
+}
+
 func main() {}
@@ -3,41 +3,23 @@ package main
 import (
 	"encoding/json"
 
-	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/internal/rpc"
 )
 
-type Response struct {
-	Code int    `json:"code"`
-	Msg  string `json:"msg"`
-	Data any    `json:"data,omitempty"`
+// Dispatch 通过方法注册表路由 Call；失败时 code 为 400/404/409/500，error 给出可编程判断的错误类型
+func Dispatch(method string, paramsJson string) string {
+	return marshalResp(rpc.Dispatch(method, paramsJson))
 }
 
-func Dispatch(method string, paramsJson string) string {
-	var result any
-	var err error
+func jsonResp(code int, msg string, data any) string {
+	return marshalResp(rpc.Response{Code: code, Msg: msg, Data: data})
+}
 
-	switch method {
-	case "system.info":
-		result = service.GetSystemInfo()
-	case "agent.stream":
-		result, err = service.StartAgentStream(paramsJson)
-	case "agent.history.list":
-		result, err = service.GetAgentHistory(paramsJson)
-	case "agent.history.info":
-		result, err = service.GetHistoryInfo(paramsJson)
-	case "agent.history.del":
-		result, err = service.DeleteHistory(paramsJson)
-	default:
-		return jsonResp(404, "Method not found", nil)
-	}
-	if err != nil {
-		return jsonResp(500, err.Error(), nil)
-	}
-	return jsonResp(200, "Ok", result)
+func errResp(err error) string {
+	return marshalResp(rpc.ErrorResponse(err))
 }
 
-func jsonResp(code int, msg string, data any) string {
-	resp := Response{Code: code, Msg: msg, Data: data}
+func marshalResp(resp rpc.Response) string {
 	b, _ := json.Marshal(resp)
 	return string(b)
 }
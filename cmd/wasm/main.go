@@ -0,0 +1,112 @@
+//go:build js && wasm
+
+// cmd/wasm 将数据流与指标层编译为 js/wasm，供浏览器 Demo 与 Electron 使用（无需 cgo）。
+// 加载后在 globalThis.cortexgo 上注册方法，返回值与 libcortex Call 相同的 {code,msg,error,data} JSON。
+// HTTP 走宿主的 fetch，缓存保存在内存中，不写文件；行情需由宿主以K线形式传入。
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/service"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// indicatorsParams cortexgo.indicators 的入参
+type indicatorsParams struct {
+	Symbol     string               `json:"symbol"`
+	Bars       []*models.MarketData `json:"bars"`                 // 必填，日K线，顺序不限
+	EndDate    string               `json:"end_date,omitempty"`   // 可选，YYYY-MM-DD，默认最后一根K线
+	Lookback   int                  `json:"lookback,omitempty"`   // 可选，默认 60
+	Indicators []string             `json:"indicators,omitempty"` // 可选，默认全部
+}
+
+// cfg 当前配置；configure 整体替换，方法开始时各取一份副本
+var cfg = config.DefaultConfig()
+
+func main() {
+	api := js.Global().Get("Object").New()
+	api.Set("version", js.FuncOf(func(js.Value, []js.Value) any { return service.GetVersion().Version }))
+	api.Set("configure", js.FuncOf(configure))
+	api.Set("setFetchProxy", js.FuncOf(setFetchProxy))
+	api.Set("sentiment", js.FuncOf(func(_ js.Value, args []js.Value) any {
+		return dataflows.ScoreSentiment(argString(args, 0))
+	}))
+	api.Set("indicators", promiseFunc(indicators))
+	api.Set("news", promiseFunc(news))
+	js.Global().Set("cortexgo", api)
+	select {}
+}
+
+// configure 用 JSON 覆盖当前配置中的字段，如 {"cache_enabled":true,"offline":false}
+func configure(_ js.Value, args []js.Value) any {
+	next := cfg.Clone()
+	if err := json.Unmarshal([]byte(argString(args, 0)), &next); err != nil {
+		return respond(nil, rpc.InvalidParams("invalid config: %v", err))
+	}
+	cfg = &next
+	return respond(nil, nil)
+}
+
+// setFetchProxy 设置 CORS 代理前缀（如 "https://proxy.example.com/"），空字符串表示直连
+func setFetchProxy(_ js.Value, args []js.Value) any {
+	dataflows.SetHTTPTransport(&dataflows.FetchTransport{Proxy: argString(args, 0)})
+	return nil
+}
+
+func indicators(params string) (any, error) {
+	var p indicatorsParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	if len(p.Bars) == 0 {
+		return nil, rpc.InvalidParams("bars is required")
+	}
+	return service.IndicatorTable(p.Symbol, p.Bars, p.EndDate, p.Lookback, p.Indicators)
+}
+
+func news(params string) (any, error) {
+	var p models.NewsListParams
+	if err := json.Unmarshal([]byte(params), &p); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	c := cfg.Clone()
+	return service.FetchNews(&c, p)
+}
+
+// promiseFunc 把阻塞的 Go 方法包装成返回 Promise<string> 的 JS 函数；
+// 方法在独立 goroutine 中运行，fetch 等待期间不会阻塞 JS 事件循环
+func promiseFunc(fn func(params string) (any, error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		params := argString(args, 0)
+		executor := js.FuncOf(func(_ js.Value, cb []js.Value) any {
+			resolve := cb[0]
+			go func() {
+				resolve.Invoke(respond(fn(params)))
+			}()
+			return nil
+		})
+		defer executor.Release()
+		return js.Global().Get("Promise").New(executor)
+	})
+}
+
+func respond(data any, err error) string {
+	resp := rpc.Response{Code: 200, Msg: "Ok", Data: data}
+	if err != nil {
+		resp = rpc.ErrorResponse(err)
+	}
+	b, _ := json.Marshal(resp)
+	return string(b)
+}
+
+func argString(args []js.Value, i int) string {
+	if i >= len(args) || args[i].Type() != js.TypeString {
+		return ""
+	}
+	return args[i].String()
+}
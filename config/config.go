@@ -3,31 +3,122 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/joho/godotenv"
 )
 
+// Config is the effective configuration. Fields tagged reload:"restart" are
+// read once at startup; a watched process keeps their old values until restart.
 type Config struct {
-	ProjectDir   string `json:"project_dir"`
-	ResultsDir   string `json:"results_dir"`
-	DataDir      string `json:"data_dir"`
-	DataCacheDir string `json:"data_cache_dir"`
+	ProjectDir   string `json:"project_dir" validate:"required" reload:"restart"`
+	ResultsDir   string `json:"results_dir" validate:"required" reload:"restart"`
+	DataDir      string `json:"data_dir" validate:"required" reload:"restart"`
+	DataCacheDir string `json:"data_cache_dir" validate:"required" reload:"restart"`
 	// Eino Debug configuration
-	EinoDebugEnabled bool `json:"eino_debug_enabled"`
-	EinoDebugPort    int  `json:"eino_debug_port"`
+	EinoDebugEnabled bool `json:"eino_debug_enabled" reload:"restart"`
+	EinoDebugPort    int  `json:"eino_debug_port" validate:"min=0,max=65535" reload:"restart"`
 	CacheEnabled     bool `json:"cache_enabled"`
+	// Where reports, session documents, caches and memory documents are kept: local (results_dir, data_dir and data_cache_dir),
+	// sqlite (an objects table in data_dir/agent.db) or s3 (the objstore_* bucket); empty means local
+	StorageBackend string `json:"storage_backend" validate:"oneof=local sqlite s3" reload:"restart"`
 
 	// Longport API Configuration
 	LongportAppKey      string `json:"longport_app_key"`
-	LongportAppSecret   string `json:"longport_app_secret"`
-	LongportAccessToken string `json:"longport_access_token"`
+	LongportAppSecret   string `json:"longport_app_secret" validate:"required_if=longport_app_key,strict"`
+	LongportAccessToken string `json:"longport_access_token" validate:"required_if=longport_app_key,strict"`
+	// Region of the account above: hk, us, sg or cn (cn uses the mainland endpoints; empty means hk)
+	LongportRegion string `json:"longport_region" validate:"oneof=hk us sg cn"`
+	// Further accounts, e.g. one per region; symbols are routed to the accounts covering their market
+	LongportAccounts []LongportAccount `json:"longport_accounts"`
+	// Account used for positions and balances (empty means the first configured account)
+	LongportProfile string `json:"longport_profile"`
+
+	// Offline mode: tools serve only from cache/local archives and never hit the network
+	Offline bool `json:"offline"`
+	// Data behind the market, news and social tools: live, or simulated for seeded random-walk candles and templated news without network access (empty means live)
+	DataProvider string `json:"data_provider" validate:"oneof=live simulated"`
+
+	// Article fetching politeness: seconds between requests to one site (0 means 2) and whether to skip robots.txt
+	CrawlDelay   int  `json:"crawl_delay" validate:"min=0,max=300"`
+	IgnoreRobots bool `json:"ignore_robots"`
+
+	// Timeout in seconds of a data source HTTP request, body included (0 means 30)
+	HTTPTimeout int `json:"http_timeout" validate:"min=0,max=600"`
+
+	// Seeded run: LLM sampling is pinned (temperature 0 plus this seed) and cached
+	// data is served regardless of age, so two runs can be compared (0 disables)
+	Seed int `json:"seed" validate:"min=0"`
+
+	// Analysis depth preset: quick, standard or deep (empty means standard)
+	Depth string `json:"depth" validate:"oneof=quick standard deep"`
+
+	// Risk profile the risk team and final sizing must respect: conservative, balanced or aggressive (empty means balanced)
+	RiskProfile string `json:"risk_profile" validate:"oneof=conservative balanced aggressive"`
+
+	// Skip the market regime briefing (index trend, VIX, sectors, breadth) given to every analyst
+	SkipMarketContext bool `json:"skip_market_context"`
+
+	// Keep every raw tool result of a run and write them to a zip data bundle under results_dir
+	ExportToolData bool `json:"export_tool_data"`
+
+	// Candle and indicator series written to data_dir/export: csv, parquet or off (empty means csv)
+	SeriesExport string `json:"series_export" validate:"oneof=csv parquet off"`
+
+	// Go template files replacing the built-in layout of md and html reports (exports, email attachments); empty keeps the built-in layout
+	ReportTemplateMD   string `json:"report_template_md"`
+	ReportTemplateHTML string `json:"report_template_html"`
+
+	// What exported and delivered reports leave out for redistribution: urls, social (raw social media posts and user names), sources (names of news outlets and data providers); empty keeps everything
+	ReportRedact []string `json:"report_redact" validate:"oneof=urls social sources"`
+	// Further names the sources redaction replaces, e.g. licensed newsletters
+	ReportRedactTerms []string `json:"report_redact_terms"`
+
+	// Language of command line output: en or zh-CN (empty follows LANG)
+	Locale string `json:"locale" validate:"oneof=en zh-CN" reload:"restart"`
+
+	// LLM behind every agent: deepseek, or mock for canned responses without API calls (empty means deepseek)
+	LLMProvider string `json:"llm_provider" validate:"oneof=deepseek mock" reload:"restart"`
+	// JSON file of per-agent scripted responses for the mock provider (empty uses the built-in ones)
+	MockLLMScript string `json:"mock_llm_script" reload:"restart"`
 
 	// AI Model API Keys
-	DeepSeekAPIKey string `json:"deepseek_api_key"`
+	DeepSeekAPIKey string `json:"deepseek_api_key" validate:"required_unless=llm_provider:mock,strict" reload:"restart"`
+
+	// Earnings call transcripts (Finnhub preferred, FMP as fallback)
+	FinnhubAPIKey string `json:"finnhub_api_key"`
+	FMPAPIKey     string `json:"fmp_api_key"`
+
+	// Email delivery (SMTP)
+	SMTPHost        string   `json:"smtp_host" validate:"required_if=email_recipients,strict"`
+	SMTPPort        int      `json:"smtp_port" validate:"min=0,max=65535"`
+	SMTPUsername    string   `json:"smtp_username"`
+	SMTPPassword    string   `json:"smtp_password"`
+	SMTPFrom        string   `json:"smtp_from"`
+	EmailRecipients []string `json:"email_recipients"`
+
+	// Outbound webhook on completion
+	WebhookURLs   []string `json:"webhook_urls" validate:"url"`
+	WebhookSecret string   `json:"webhook_secret"`
+
+	// Object storage (S3 / GCS interop) for syncing results across machines
+	ObjstoreEndpoint  string `json:"objstore_endpoint"`
+	ObjstoreRegion    string `json:"objstore_region"`
+	ObjstoreBucket    string `json:"objstore_bucket"`
+	ObjstoreAccessKey string `json:"objstore_access_key" validate:"required_if=objstore_bucket"`
+	ObjstoreSecretKey string `json:"objstore_secret_key" validate:"required_if=objstore_bucket"`
+	ObjstorePrefix    string `json:"objstore_prefix"`
+	ObjstorePathStyle bool   `json:"objstore_path_style"`
+
+	// Encryption at rest (AES-256-GCM) for stored results and cached articles
+	EncryptionKey      string `json:"encryption_key" reload:"restart"`      // base64 or hex, 32 bytes
+	EncryptionKeyFile  string `json:"encryption_key_file" reload:"restart"` // file containing the key
+	EncryptionKeychain bool   `json:"encryption_keychain" reload:"restart"` // read the key from the OS keychain (service "cortexgo")
 }
 
 func Initialize(path string) error {
@@ -63,6 +154,58 @@ func Get() Config {
 	return mgr.Get()
 }
 
+// ConfigPathEnv names the config file for tools that do not get an explicit path.
+const ConfigPathEnv = "CORTEXGO_CONFIG"
+
+// ResolvePath picks the config file in order: explicit path (e.g. a --config
+// flag) > $CORTEXGO_CONFIG > ./cortexgo.json > <UserConfigDir>/cortexgo/config.json.
+// A file named explicitly or via the env var must exist; otherwise the first
+// existing candidate wins and "" means none was found.
+func ResolvePath(explicit string) (string, error) {
+	for _, named := range []string{explicit, os.Getenv(ConfigPathEnv)} {
+		if named = strings.TrimSpace(named); named == "" {
+			continue
+		}
+		if _, err := os.Stat(named); err != nil {
+			return "", fmt.Errorf("config file: %w", err)
+		}
+		return named, nil
+	}
+
+	candidates := []string{"cortexgo.json"}
+	if dir, err := os.UserConfigDir(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, "cortexgo", "config.json"))
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// LoadResolved builds the effective config for command line tools: defaults,
+// then the file chosen by ResolvePath, then environment overrides. It returns
+// the file used ("" when running on defaults and env only).
+func LoadResolved(explicit string) (*Config, string, error) {
+	path, err := ResolvePath(explicit)
+	if err != nil {
+		return nil, "", err
+	}
+	cfg := DefaultConfig()
+	if path == "" {
+		return cfg, "", nil
+	}
+	if err := loadConfigFromFile(path, cfg); err != nil {
+		return nil, "", fmt.Errorf("load config %s: %w", path, err)
+	}
+	cfg.loadFromEnv()
+	if err := cfg.Validate(); err != nil {
+		return nil, "", fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, path, nil
+}
+
 func LoadConfigFromEnv() *Config {
 	cfg := &Config{}
 	_ = godotenv.Load()
@@ -91,12 +234,26 @@ func DefaultConfig() *Config {
 }
 
 func DefaultConfigWithRoot(root string) *Config {
+	cfg := builtinDefaults(root)
+
+	// Load environment variables from .env file
+	_ = godotenv.Load()
+
+	// Override with environment variables if they exist
+	cfg.loadFromEnv()
+
+	return cfg
+}
+
+// builtinDefaults are the values used when neither a file nor the environment
+// sets a field.
+func builtinDefaults(root string) *Config {
 	baseDir := root
 	if baseDir == "" {
 		currentDir, _ := os.Getwd()
 		baseDir = currentDir
 	}
-	cfg := &Config{
+	return &Config{
 		ProjectDir:   baseDir,
 		ResultsDir:   filepath.Join(baseDir, "results"),
 		DataDir:      filepath.Join(baseDir, "data"),
@@ -108,14 +265,6 @@ func DefaultConfigWithRoot(root string) *Config {
 
 		CacheEnabled: true,
 	}
-
-	// Load environment variables from .env file
-	_ = godotenv.Load()
-
-	// Override with environment variables if they exist
-	cfg.loadFromEnv()
-
-	return cfg
 }
 
 func (c *Config) loadFromEnv() {
@@ -145,27 +294,124 @@ func (c *Config) loadFromEnv() {
 	if val := os.Getenv("LONGPORT_ACCESS_TOKEN"); val != "" {
 		c.LongportAccessToken = val
 	}
+	if val := os.Getenv("LONGPORT_REGION"); val != "" {
+		c.LongportRegion = val
+	}
 
 	if val := os.Getenv("DEEPSEEK_API_KEY"); val != "" {
 		c.DeepSeekAPIKey = val
 	}
-}
+	if val := os.Getenv("FINNHUB_API_KEY"); val != "" {
+		c.FinnhubAPIKey = val
+	}
+	if val := os.Getenv("FMP_API_KEY"); val != "" {
+		c.FMPAPIKey = val
+	}
 
-func (c *Config) Validate() error {
-	if c.ProjectDir == "" {
-		return errors.New("project_dir cannot be empty")
+	if val := os.Getenv("SMTP_HOST"); val != "" {
+		c.SMTPHost = val
+	}
+	if val := os.Getenv("SMTP_PORT"); val != "" {
+		if port, err := strconv.Atoi(val); err == nil {
+			c.SMTPPort = port
+		}
+	}
+	if val := os.Getenv("SMTP_USERNAME"); val != "" {
+		c.SMTPUsername = val
+	}
+	if val := os.Getenv("SMTP_PASSWORD"); val != "" {
+		c.SMTPPassword = val
+	}
+	if val := os.Getenv("SMTP_FROM"); val != "" {
+		c.SMTPFrom = val
+	}
+	if val := os.Getenv("EMAIL_RECIPIENTS"); val != "" {
+		c.EmailRecipients = splitList(val)
+	}
+
+	if val := os.Getenv("WEBHOOK_URLS"); val != "" {
+		c.WebhookURLs = splitList(val)
+	}
+	if val := os.Getenv("WEBHOOK_SECRET"); val != "" {
+		c.WebhookSecret = val
+	}
+
+	if val := os.Getenv("OBJSTORE_ENDPOINT"); val != "" {
+		c.ObjstoreEndpoint = val
 	}
-	if c.ResultsDir == "" {
-		return errors.New("results_dir cannot be empty")
+	if val := os.Getenv("OBJSTORE_REGION"); val != "" {
+		c.ObjstoreRegion = val
 	}
-	if c.DataDir == "" {
-		return errors.New("data_dir cannot be empty")
+	if val := os.Getenv("OBJSTORE_BUCKET"); val != "" {
+		c.ObjstoreBucket = val
 	}
-	if c.DataCacheDir == "" {
-		return errors.New("data_cache_dir cannot be empty")
+	if val := os.Getenv("OBJSTORE_ACCESS_KEY"); val != "" {
+		c.ObjstoreAccessKey = val
 	}
-	if c.EinoDebugPort < 0 {
-		return errors.New("eino_debug_port cannot be negative")
+	if val := os.Getenv("OBJSTORE_SECRET_KEY"); val != "" {
+		c.ObjstoreSecretKey = val
+	}
+	if val := os.Getenv("OBJSTORE_PREFIX"); val != "" {
+		c.ObjstorePrefix = val
+	}
+	if val := os.Getenv("OBJSTORE_PATH_STYLE"); val != "" {
+		c.ObjstorePathStyle = val == "1" || strings.EqualFold(val, "true")
+	}
+
+	if val := os.Getenv("OFFLINE"); val != "" {
+		c.Offline = val == "1" || strings.EqualFold(val, "true")
+	}
+	if val := os.Getenv("ANALYSIS_DEPTH"); val != "" {
+		c.Depth = val
+	}
+
+	if val := os.Getenv("ENCRYPTION_KEY"); val != "" {
+		c.EncryptionKey = val
+	}
+	if val := os.Getenv("ENCRYPTION_KEY_FILE"); val != "" {
+		c.EncryptionKeyFile = val
+	}
+	if val := os.Getenv("ENCRYPTION_KEYCHAIN"); val != "" {
+		c.EncryptionKeychain = val == "1" || strings.EqualFold(val, "true")
+	}
+
+	c.loadPrefixedEnv()
+}
+
+// Clone returns a deep copy, so list fields are not shared with the original.
+func (c Config) Clone() Config {
+	c.EmailRecipients = slices.Clone(c.EmailRecipients)
+	c.WebhookURLs = slices.Clone(c.WebhookURLs)
+	c.ReportRedact = slices.Clone(c.ReportRedact)
+	c.ReportRedactTerms = slices.Clone(c.ReportRedactTerms)
+	c.LongportAccounts = slices.Clone(c.LongportAccounts)
+	for i := range c.LongportAccounts {
+		c.LongportAccounts[i].Markets = slices.Clone(c.LongportAccounts[i].Markets)
+	}
+	return c
+}
+
+// ObjstoreEnabled reports whether a results bucket is configured.
+func (c *Config) ObjstoreEnabled() bool {
+	return c.ObjstoreEndpoint != "" && c.ObjstoreBucket != ""
+}
+
+// splitList parses a comma separated env value, dropping empty entries.
+func splitList(val string) []string {
+	var out []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Validate checks the rules declared in the validate struct tags (see
+// validate.go). Strict-only rules are skipped.
+func (c *Config) Validate() error {
+	if errs := c.ValidateFields(false); len(errs) > 0 {
+		return &ValidationError{Errors: errs}
 	}
 	return nil
 }
@@ -2,6 +2,9 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -9,3 +12,200 @@ func TestConfigFromEnv(t *testing.T) {
 	cfg := LoadConfigFromEnv()
 	fmt.Println(cfg)
 }
+
+func TestResolvePathOrder(t *testing.T) {
+	work := t.TempDir()
+	home := t.TempDir()
+	t.Chdir(work)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("HOME", home)
+	t.Setenv(ConfigPathEnv, "")
+
+	write := func(path string) string {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(`{"depth":"quick"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	if got, err := ResolvePath(""); err != nil || got != "" {
+		t.Fatalf("expected no config, got %q, %v", got, err)
+	}
+
+	userDir, _ := os.UserConfigDir()
+	user := write(filepath.Join(userDir, "cortexgo", "config.json"))
+	if got, _ := ResolvePath(""); got != user {
+		t.Fatalf("expected user config %q, got %q", user, got)
+	}
+
+	write(filepath.Join(work, "cortexgo.json"))
+	if got, _ := ResolvePath(""); got != "cortexgo.json" {
+		t.Fatalf("expected ./cortexgo.json, got %q", got)
+	}
+
+	env := write(filepath.Join(work, "env.json"))
+	t.Setenv(ConfigPathEnv, env)
+	if got, _ := ResolvePath(""); got != env {
+		t.Fatalf("expected env config %q, got %q", env, got)
+	}
+
+	flag := write(filepath.Join(work, "flag.json"))
+	if got, _ := ResolvePath(flag); got != flag {
+		t.Fatalf("expected flag config %q, got %q", flag, got)
+	}
+	if _, err := ResolvePath(filepath.Join(work, "missing.json")); err == nil {
+		t.Fatal("expected an error for a missing explicit config")
+	}
+
+	cfg, path, err := LoadResolved(flag)
+	if err != nil || path != flag || cfg.Depth != "quick" {
+		t.Fatalf("LoadResolved = %+v, %q, %v", cfg, path, err)
+	}
+}
+
+func TestPrefixedEnvOverrides(t *testing.T) {
+	t.Setenv("SMTP_PORT", "25")
+	t.Setenv("CORTEXGO_SMTP_PORT", "587")
+	t.Setenv("CORTEXGO_DEPTH", "deep")
+	t.Setenv("CORTEXGO_OFFLINE", "true")
+	t.Setenv("CORTEXGO_WEBHOOK_URLS", "https://a.example, https://b.example")
+	t.Setenv("CORTEXGO_EINO_DEBUG_PORT", "not-a-number")
+
+	cfg := &Config{EinoDebugPort: 1}
+	cfg.loadFromEnv()
+	if cfg.SMTPPort != 587 || cfg.Depth != "deep" || !cfg.Offline || len(cfg.WebhookURLs) != 2 {
+		t.Fatalf("overrides not applied: %+v", cfg)
+	}
+	if cfg.EinoDebugPort != 1 {
+		t.Fatalf("invalid int should be ignored, got %d", cfg.EinoDebugPort)
+	}
+
+	vars := EnvVars()
+	if len(vars) == 0 || vars[0].Name != "CORTEXGO_PROJECT_DIR" || vars[0].Type != "string" {
+		t.Fatalf("unexpected env vars: %+v", vars)
+	}
+	doc, err := os.ReadFile("../doc.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vars {
+		if !strings.Contains(string(doc), v.Name) {
+			t.Errorf("doc.md does not document %s", v.Name)
+		}
+	}
+}
+
+func TestValidateFieldsReportsEveryViolation(t *testing.T) {
+	cfg := &Config{
+		ProjectDir:     "/p",
+		ResultsDir:     "/p/results",
+		DataDir:        "/p/data",
+		EinoDebugPort:  70000,
+		Depth:          "slow",
+		WebhookURLs:    []string{"ftp://x"},
+		ObjstoreBucket: "b",
+		ReportRedact:   []string{"urls", "names"},
+	}
+	errs := cfg.ValidateFields(false)
+	got := map[string]bool{}
+	for _, fe := range errs {
+		got[fe.Field] = true
+	}
+	for _, field := range []string{"data_cache_dir", "eino_debug_port", "depth", "webhook_urls", "objstore_access_key", "objstore_secret_key", "report_redact"} {
+		if !got[field] {
+			t.Errorf("missing violation for %s in %+v", field, errs)
+		}
+	}
+	if got["deepseek_api_key"] {
+		t.Error("strict-only rule applied outside strict mode")
+	}
+	if !containsField(cfg.ValidateFields(true), "deepseek_api_key") {
+		t.Error("strict mode should require deepseek_api_key")
+	}
+	mock := *cfg
+	mock.LLMProvider = LLMMock
+	if containsField(mock.ValidateFields(true), "deepseek_api_key") || mock.LLMReady() != nil {
+		t.Error("the mock provider should not need deepseek_api_key")
+	}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "data_cache_dir cannot be empty") {
+		t.Fatalf("unexpected Validate error: %v", err)
+	}
+}
+
+func TestLongportAccounts(t *testing.T) {
+	t.Setenv("CORTEXGO_LONGPORT_ACCOUNTS", `[{"name":"us","region":"us","app_key":"k","app_secret":"s","access_token":"t"}]`)
+	cfg := &Config{LongportAppKey: "k0", LongportAppSecret: "s0", LongportAccessToken: "t0"}
+	cfg.loadFromEnv()
+	accounts := cfg.LongportAccountList()
+	if len(accounts) != 2 || accounts[0].Name != LongportDefaultAccount || accounts[1].MarketList()[0] != "US" {
+		t.Fatalf("accounts = %+v", accounts)
+	}
+
+	cfg.LongportAccounts = append(cfg.LongportAccounts,
+		LongportAccount{Name: "us", Region: "eu", Markets: []string{"hk"}, AppKey: "k"},
+	)
+	cfg.LongportProfile = "sg"
+	errs := cfg.validateLongport()
+	if len(errs) != 4 {
+		t.Fatalf("errors = %+v", errs)
+	}
+	for i, want := range []string{"used twice", "region must be", "needs app_key", "does not name"} {
+		if !strings.Contains(errs[i].Message, want) {
+			t.Errorf("error %d = %q, want %q", i, errs[i].Message, want)
+		}
+	}
+}
+
+func TestValidateResolvedSources(t *testing.T) {
+	work := t.TempDir()
+	t.Chdir(work)
+	t.Setenv(ConfigPathEnv, "")
+	t.Setenv("CORTEXGO_SMTP_PORT", "99999")
+	path := filepath.Join(work, "cortexgo.json")
+	if err := os.WriteFile(path, []byte(`{"depth":"slow","typo_key":1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := ValidateResolved("", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.OK {
+		t.Fatal("expected violations")
+	}
+	sources := map[string]string{}
+	for _, fe := range report.Errors {
+		sources[fe.Field] = fe.Source
+	}
+	if sources["depth"] != SourceFile || sources["smtp_port"] != SourceEnv || sources["typo_key"] != SourceFile {
+		t.Fatalf("unexpected sources: %+v", report.Errors)
+	}
+	if report.Sources["results_dir"] != SourceDefault {
+		t.Fatalf("results_dir should come from defaults, got %q", report.Sources["results_dir"])
+	}
+}
+
+func containsField(errs []FieldError, field string) bool {
+	for _, fe := range errs {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDigestCoversOnlyAnalysisSettings(t *testing.T) {
+	a := &Config{Depth: DepthStandard, RiskProfile: "balanced", DeepSeekAPIKey: "sk-a", ResultsDir: "/a/results", SMTPHost: "smtp.a"}
+	b := *a
+	b.DeepSeekAPIKey, b.ResultsDir, b.SMTPHost = "sk-b", "/b/results", "smtp.b"
+	if a.Digest() != b.Digest() {
+		t.Error("secrets, paths and delivery settings changed the digest")
+	}
+	b.Depth = DepthDeep
+	if a.Digest() == b.Digest() {
+		t.Error("depth did not change the digest")
+	}
+}
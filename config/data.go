@@ -0,0 +1,23 @@
+package config
+
+// Data providers.
+const (
+	DataLive      = "live"
+	DataSimulated = "simulated"
+)
+
+// ValidDataProvider reports whether provider names a known data provider.
+// Empty selects live.
+func ValidDataProvider(provider string) bool {
+	switch provider {
+	case "", DataLive, DataSimulated:
+		return true
+	}
+	return false
+}
+
+// SimulatedData reports whether the market, news and social tools serve
+// generated data, leaving every other data source offline.
+func (c *Config) SimulatedData() bool {
+	return c != nil && c.DataProvider == DataSimulated
+}
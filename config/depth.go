@@ -0,0 +1,17 @@
+package config
+
+// Analysis depth presets; the bundles they map to live in internal/agents.
+const (
+	DepthQuick    = "quick"
+	DepthStandard = "standard"
+	DepthDeep     = "deep"
+)
+
+// ValidDepth reports whether depth names a known preset. Empty selects standard.
+func ValidDepth(depth string) bool {
+	switch depth {
+	case "", DepthQuick, DepthStandard, DepthDeep:
+		return true
+	}
+	return false
+}
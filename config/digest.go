@@ -0,0 +1,61 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// digestSkipped are the fields Digest leaves out besides the secrets:
+// credentials, where files go, and how results are delivered or stored.
+// None of them changes what an analysis concludes, and most differ between
+// machines running the same setup.
+var digestSkipped = map[string]bool{
+	"project_dir":         true,
+	"results_dir":         true,
+	"data_dir":            true,
+	"data_cache_dir":      true,
+	"eino_debug_enabled":  true,
+	"eino_debug_port":     true,
+	"storage_backend":     true,
+	"longport_app_key":    true,
+	"longport_accounts":   true,
+	"export_tool_data":    true,
+	"encryption_key_file": true,
+	"encryption_keychain": true,
+}
+
+// digestSkippedPrefixes cover the delivery, report layout and object storage
+// settings.
+var digestSkippedPrefixes = []string{"smtp_", "email_", "webhook_", "report_", "objstore_"}
+
+// Digest is a short hash over the settings that shape an analysis (depth,
+// risk profile, data sources, seed, model provider, ...), so two results can
+// be checked for having run with the same configuration. Secrets, paths and
+// delivery settings are left out.
+func (c *Config) Digest() string {
+	if c == nil {
+		return ""
+	}
+	h := sha256.New()
+	forEachField(reflect.ValueOf(c).Elem(), func(key string, field reflect.Value, _ reflect.StructField) {
+		if secretFields[key] || digestSkipped[key] || hasAnyPrefix(key, digestSkippedPrefixes) {
+			return
+		}
+		value, _ := json.Marshal(field.Interface())
+		fmt.Fprintf(h, "%s=%s\n", key, value)
+	})
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix prefixes the generated override for every config field. The name
+// is the upper-cased json tag, e.g. smtp_port -> CORTEXGO_SMTP_PORT. These are
+// applied after the legacy names (DEEPSEEK_API_KEY, SMTP_*, ...) and so win
+// over them.
+const EnvPrefix = "CORTEXGO_"
+
+// EnvVar describes the generated environment override for one field.
+type EnvVar struct {
+	Name  string `json:"name"`
+	Field string `json:"field"`
+	Type  string `json:"type"`
+}
+
+// EnvVars lists the generated overrides in struct order.
+func EnvVars() []EnvVar {
+	var vars []EnvVar
+	forEachField(reflect.ValueOf(&Config{}).Elem(), func(key string, _ reflect.Value, sf reflect.StructField) {
+		vars = append(vars, EnvVar{Name: envName(key), Field: key, Type: envType(sf.Type)})
+	})
+	return vars
+}
+
+// loadPrefixedEnv applies CORTEXGO_* overrides. Unparseable values are
+// ignored, matching the legacy variables.
+func (c *Config) loadPrefixedEnv() {
+	forEachField(reflect.ValueOf(c).Elem(), func(key string, v reflect.Value, _ reflect.StructField) {
+		val, ok := os.LookupEnv(envName(key))
+		if !ok {
+			return
+		}
+		switch v.Kind() {
+		case reflect.String:
+			v.SetString(val)
+		case reflect.Bool:
+			if b, err := strconv.ParseBool(val); err == nil {
+				v.SetBool(b)
+			}
+		case reflect.Int:
+			if n, err := strconv.Atoi(val); err == nil {
+				v.SetInt(int64(n))
+			}
+		case reflect.Slice:
+			if v.Type().Elem().Kind() == reflect.String {
+				v.Set(reflect.ValueOf(splitList(val)))
+				return
+			}
+			// lists of objects are given as a JSON array
+			list := reflect.New(v.Type())
+			if err := json.Unmarshal([]byte(val), list.Interface()); err == nil {
+				v.Set(list.Elem())
+			}
+		}
+	})
+}
+
+func forEachField(v reflect.Value, fn func(key string, field reflect.Value, sf reflect.StructField)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if !sf.IsExported() || key == "" || key == "-" {
+			continue
+		}
+		fn(key, v.Field(i), sf)
+	}
+}
+
+func envName(key string) string {
+	return EnvPrefix + strings.ToUpper(key)
+}
+
+func envType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Struct {
+			return "json"
+		}
+		return "list"
+	case reflect.Int:
+		return "int"
+	}
+	return t.Kind().String()
+}
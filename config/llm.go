@@ -0,0 +1,37 @@
+package config
+
+import "errors"
+
+// LLM providers.
+const (
+	LLMDeepSeek = "deepseek"
+	LLMMock     = "mock"
+)
+
+// ErrNoLLMKey is returned when an analysis is started without a DeepSeek key
+// and without the mock provider.
+var ErrNoLLMKey = errors.New("deepseek api key is required (or set llm_provider to mock)")
+
+// ValidLLMProvider reports whether provider names a known LLM provider. Empty
+// selects deepseek.
+func ValidLLMProvider(provider string) bool {
+	switch provider {
+	case "", LLMDeepSeek, LLMMock:
+		return true
+	}
+	return false
+}
+
+// MockLLM reports whether the agents answer from canned responses instead of
+// calling an LLM.
+func (c *Config) MockLLM() bool {
+	return c != nil && c.LLMProvider == LLMMock
+}
+
+// LLMReady returns ErrNoLLMKey when an analysis could not reach an LLM.
+func (c *Config) LLMReady() error {
+	if c.MockLLM() || c.DeepSeekAPIKey != "" {
+		return nil
+	}
+	return ErrNoLLMKey
+}
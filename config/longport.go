@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Longport account regions. Accounts of every region but cn use the global
+// endpoints; the region also picks the markets an account serves by default.
+const (
+	LongportRegionHK = "hk"
+	LongportRegionUS = "us"
+	LongportRegionSG = "sg"
+	LongportRegionCN = "cn"
+)
+
+// LongportDefaultAccount names the account given by longport_app_key,
+// longport_app_secret and longport_access_token.
+const LongportDefaultAccount = "default"
+
+// longportRegionMarkets are the symbol markets an account serves when it does
+// not list its own.
+var longportRegionMarkets = map[string][]string{
+	LongportRegionHK: {"HK", "SH", "SZ"},
+	LongportRegionUS: {"US"},
+	LongportRegionSG: {"SG"},
+	LongportRegionCN: {"SH", "SZ"},
+}
+
+// longportMarkets are the symbol suffixes an account may list.
+var longportMarkets = []string{"US", "HK", "SG", "SH", "SZ"}
+
+// LongportAccount is one Longport OpenAPI login.
+type LongportAccount struct {
+	Name        string   `json:"name"`
+	Region      string   `json:"region,omitempty"`  // hk, us, sg or cn; empty means hk
+	Markets     []string `json:"markets,omitempty"` // symbol suffixes routed here first; empty follows the region
+	AppKey      string   `json:"app_key"`
+	AppSecret   string   `json:"app_secret"`
+	AccessToken string   `json:"access_token"`
+}
+
+// MarketList returns the markets the account serves first, upper-cased.
+func (a LongportAccount) MarketList() []string {
+	if len(a.Markets) == 0 {
+		region := strings.ToLower(a.Region)
+		if region == "" {
+			region = LongportRegionHK
+		}
+		return longportRegionMarkets[region]
+	}
+	out := make([]string, len(a.Markets))
+	for i, m := range a.Markets {
+		out[i] = strings.ToUpper(strings.TrimSpace(m))
+	}
+	return out
+}
+
+// Complete reports whether the account has all three credentials.
+func (a LongportAccount) Complete() bool {
+	return a.AppKey != "" && a.AppSecret != "" && a.AccessToken != ""
+}
+
+// LongportAccountList returns every configured account: the one given by
+// longport_app_key first, named "default", then longport_accounts in order.
+// Accounts missing a credential are left out.
+func (c *Config) LongportAccountList() []LongportAccount {
+	var out []LongportAccount
+	if def := (LongportAccount{
+		Name:        LongportDefaultAccount,
+		Region:      c.LongportRegion,
+		AppKey:      c.LongportAppKey,
+		AppSecret:   c.LongportAppSecret,
+		AccessToken: c.LongportAccessToken,
+	}); def.Complete() {
+		out = append(out, def)
+	}
+	for _, a := range c.LongportAccounts {
+		if a.Complete() {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// LongportConfigured reports whether at least one Longport account can be used.
+func (c *Config) LongportConfigured() bool {
+	return len(c.LongportAccountList()) > 0
+}
+
+// validateLongport checks the account list and the profile naming one of
+// them; the tag rules cannot reach into the list.
+func (c *Config) validateLongport() []FieldError {
+	var errs []FieldError
+	names := map[string]bool{}
+	if c.LongportAppKey != "" {
+		names[LongportDefaultAccount] = true
+	}
+	for i, a := range c.LongportAccounts {
+		key := fmt.Sprintf("longport_accounts[%d]", i)
+		switch {
+		case strings.TrimSpace(a.Name) == "":
+			errs = append(errs, FieldError{Field: key, Rule: "required", Message: key + " needs a name"})
+		case names[a.Name]:
+			errs = append(errs, FieldError{Field: key, Rule: "unique", Message: fmt.Sprintf("%s: account name %q is used twice", key, a.Name)})
+		}
+		names[a.Name] = true
+		if a.Region != "" && longportRegionMarkets[strings.ToLower(a.Region)] == nil {
+			errs = append(errs, FieldError{Field: key, Rule: "oneof", Message: key + " region must be one of hk, us, sg, cn"})
+		}
+		for _, m := range a.MarketList() {
+			if !slices.Contains(longportMarkets, m) {
+				errs = append(errs, FieldError{Field: key, Rule: "oneof", Message: fmt.Sprintf("%s market %q must be one of %s", key, m, strings.Join(longportMarkets, ", "))})
+				break
+			}
+		}
+		if !a.Complete() {
+			errs = append(errs, FieldError{Field: key, Rule: "required", Message: key + " needs app_key, app_secret and access_token"})
+		}
+	}
+	if p := c.LongportProfile; p != "" && !names[p] {
+		errs = append(errs, FieldError{Field: "longport_profile", Rule: "oneof", Message: fmt.Sprintf("longport_profile %q does not name a configured account", p)})
+	}
+	return errs
+}
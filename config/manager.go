@@ -16,20 +16,33 @@ import (
 	"github.com/fsnotify/fsnotify"
 )
 
+// Manager owns the process-wide config. The current config is an immutable
+// snapshot swapped atomically, so readers never see a half-applied update;
+// writers (UpdateConfig, file reloads) are serialized by writeMu.
 type Manager struct {
 	path         string
-	mu           sync.RWMutex
-	cfg          Config
+	snap         atomic.Pointer[Config]
+	writeMu      sync.Mutex
+	mu           sync.Mutex // guards watcher and onChange
 	watcher      *fsnotify.Watcher
 	debounce     time.Duration
 	onChange     func(Config)
+	onReload     func(ReloadEvent)
 	suppressSelf atomic.Bool
+
+	load     func() (*Config, error)
+	liveOnly bool
+	// file is the config last read from disk (or written by Update), before
+	// restart-only fields were held back; reloads diff against it.
+	file Config
 }
 
 type managerOptions struct {
 	configPath    string
 	initialConfig *Config
 	debounce      time.Duration
+	load          func() (*Config, error)
+	liveOnly      bool
 }
 
 type ManagerOption func(*managerOptions)
@@ -56,27 +69,39 @@ func NewManager(opts ...ManagerOption) (*Manager, error) {
 		}
 	}
 
-	configDir := filepath.Dir(configPath)
-	if err := os.MkdirAll(configDir, 0o755); err != nil {
-		return nil, fmt.Errorf("create config dir: %w", err)
-	}
-
-	cfg, err := loadOrCreateConfig(configPath, options)
-	if err != nil {
-		return nil, err
+	var cfg Config
+	if options.load != nil {
+		// the loader owns the config; nothing is created on disk
+		loaded, err := options.load()
+		if err != nil {
+			return nil, err
+		}
+		cfg = *loaded
+	} else {
+		if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+			return nil, fmt.Errorf("create config dir: %w", err)
+		}
+		var err error
+		if cfg, err = loadOrCreateConfig(configPath, options); err != nil {
+			return nil, err
+		}
 	}
 
-	return &Manager{
+	m := &Manager{
 		path:     configPath,
-		cfg:      cfg,
 		debounce: options.debounce,
-	}, nil
+		load:     options.load,
+		liveOnly: options.liveOnly,
+		file:     cfg.Clone(),
+	}
+	m.snap.Store(&cfg)
+	return m, nil
 }
 
+// Get returns a private copy of the current snapshot. Each run should take
+// one copy up front and may modify it freely (per-run overrides).
 func (m *Manager) Get() Config {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	return m.cfg
+	return m.snap.Load().Clone()
 }
 
 func (m *Manager) Path() string {
@@ -95,11 +120,11 @@ func (m *Manager) Update(newCfg Config) error {
 	if err := newCfg.Validate(); err != nil {
 		return err
 	}
+	newCfg = newCfg.Clone()
 
-	m.mu.RLock()
-	current := m.cfg
-	m.mu.RUnlock()
-	if reflect.DeepEqual(current, newCfg) {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+	if reflect.DeepEqual(*m.snap.Load(), newCfg) {
 		return nil
 	}
 
@@ -111,13 +136,34 @@ func (m *Manager) Update(newCfg Config) error {
 		return err
 	}
 
+	// CORTEXGO_* overrides apply in memory only; the file keeps what the caller sent.
+	newCfg.loadPrefixedEnv()
+	m.file = newCfg.Clone()
 	m.applyConfig(newCfg)
 	return nil
 }
 
+// Watch reloads the config file on change. onChange runs with the write lock
+// held so callbacks observe snapshots in order; it must not call Update.
 func (m *Manager) Watch(ctx context.Context, onChange func(Config)) error {
 	m.mu.Lock()
 	m.onChange = onChange
+	m.mu.Unlock()
+	return m.watch(ctx)
+}
+
+// WatchReloads is Watch for callers that report reloads: onReload gets the
+// changed fields of every reload that changed something, and the error of
+// every reload that failed, in which case the previous config stays.
+func (m *Manager) WatchReloads(ctx context.Context, onReload func(ReloadEvent)) error {
+	m.mu.Lock()
+	m.onReload = onReload
+	m.mu.Unlock()
+	return m.watch(ctx)
+}
+
+func (m *Manager) watch(ctx context.Context) error {
+	m.mu.Lock()
 	if m.watcher != nil {
 		m.mu.Unlock()
 		return nil
@@ -190,41 +236,74 @@ func (m *Manager) isConfigEvent(evt fsnotify.Event, configPath string) bool {
 }
 
 func (m *Manager) reloadFromDisk() {
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	next, err := m.readFile()
+	if err != nil {
+		log.Printf("config reload failed: %v", err)
+		m.report(ReloadEvent{Err: err})
+		return
+	}
+	merged, ev := mergeLive(*m.snap.Load(), m.file, next, m.liveOnly)
+	m.file = next
+	if !m.liveOnly {
+		// the file applies as a whole
+		merged = next
+	}
+	if !reflect.DeepEqual(*m.snap.Load(), merged) {
+		m.applyConfig(merged)
+	}
+	if len(ev.Applied)+len(ev.Restart) > 0 {
+		m.report(ev)
+	}
+}
+
+// readFile reads the config file for a reload, with the loader when one was
+// given; a deleted file is recreated with the defaults otherwise.
+func (m *Manager) readFile() (Config, error) {
+	if m.load != nil {
+		cfg, err := m.load()
+		if err != nil {
+			return Config{}, err
+		}
+		return *cfg, cfg.Validate()
+	}
 	var cfg Config
 	if err := loadConfigFromFile(m.path, &cfg); err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			cfg = *DefaultConfigWithRoot(filepath.Dir(m.path))
-			if err := writeConfigFile(m.path, cfg); err != nil {
-				log.Printf("config recreate failed: %v", err)
-				return
-			}
-		} else {
-			log.Printf("config reload failed: %v", err)
-			return
+		if !errors.Is(err, os.ErrNotExist) {
+			return Config{}, err
+		}
+		cfg = *DefaultConfigWithRoot(filepath.Dir(m.path))
+		if err := writeConfigFile(m.path, cfg); err != nil {
+			return Config{}, fmt.Errorf("recreate config: %w", err)
 		}
 	}
+	cfg.loadPrefixedEnv()
 	if err := cfg.Validate(); err != nil {
-		log.Printf("config validation failed: %v", err)
-		return
+		return Config{}, fmt.Errorf("config validation failed: %w", err)
 	}
+	return cfg, nil
+}
 
-	m.mu.RLock()
-	current := m.cfg
-	m.mu.RUnlock()
-	if reflect.DeepEqual(current, cfg) {
-		return
+func (m *Manager) report(ev ReloadEvent) {
+	m.mu.Lock()
+	cb := m.onReload
+	m.mu.Unlock()
+	if cb != nil {
+		cb(ev)
 	}
-	m.applyConfig(cfg)
 }
 
+// applyConfig publishes cfg as the new snapshot; callers hold writeMu.
 func (m *Manager) applyConfig(cfg Config) {
+	m.snap.Store(&cfg)
 	m.mu.Lock()
-	m.cfg = cfg
 	cb := m.onChange
 	m.mu.Unlock()
 
 	if cb != nil {
-		cb(cfg)
+		cb(cfg.Clone())
 	}
 }
 
@@ -234,6 +313,7 @@ func loadOrCreateConfig(path string, options managerOptions) (Config, error) {
 		if err := loadConfigFromFile(path, &cfg); err != nil {
 			return Config{}, fmt.Errorf("load config: %w", err)
 		}
+		cfg.loadPrefixedEnv()
 		if err := cfg.Validate(); err != nil {
 			return Config{}, err
 		}
@@ -321,6 +401,25 @@ func WithDebounce(d time.Duration) ManagerOption {
 	}
 }
 
+// WithLoader reads the config with load instead of the file alone, at start
+// and on every reload, so reloads see the same defaults, env and command
+// line overrides as the first load (e.g. LoadResolved plus flags). Neither
+// the file nor its directory is created.
+func WithLoader(load func() (*Config, error)) ManagerOption {
+	return func(o *managerOptions) {
+		o.load = load
+	}
+}
+
+// WithLiveReload makes file reloads change only the fields that can change
+// in a running process: fields tagged reload:"restart" keep their value and
+// are reported in ReloadEvent.Restart instead.
+func WithLiveReload() ManagerOption {
+	return func(o *managerOptions) {
+		o.liveOnly = true
+	}
+}
+
 func WithInitialConfig(cfg *Config) ManagerOption {
 	return func(o *managerOptions) {
 		o.initialConfig = cfg
@@ -3,8 +3,10 @@ package config
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -38,6 +40,54 @@ func TestManagerCreatesAndUpdates(t *testing.T) {
 	}
 }
 
+func TestManagerConcurrentUpdatesAreAtomic(t *testing.T) {
+	dir := t.TempDir()
+	mgr, err := NewManager(WithConfigDir(dir))
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				cfg := mgr.Get()
+				tag := fmt.Sprintf("w%d-%d", i, n)
+				cfg.ResultsDir = filepath.Join(dir, tag)
+				cfg.WebhookURLs = []string{"https://example.com/" + tag}
+				if err := mgr.Update(cfg); err != nil {
+					t.Errorf("Update: %v", err)
+					return
+				}
+			}
+		}(i)
+	}
+	for i := 0; i < 200; i++ {
+		cfg := mgr.Get()
+		if len(cfg.WebhookURLs) == 0 {
+			continue
+		}
+		// Both fields are written by the same Update and must come from the same snapshot.
+		if filepath.Base(cfg.ResultsDir) != filepath.Base(cfg.WebhookURLs[0]) {
+			t.Fatalf("torn snapshot: %s vs %s", cfg.ResultsDir, cfg.WebhookURLs[0])
+		}
+		cfg.WebhookURLs[0] = "mutated"
+		if got := mgr.Get(); len(got.WebhookURLs) > 0 && got.WebhookURLs[0] == "mutated" {
+			t.Fatal("Get returned a slice shared with the snapshot")
+		}
+	}
+	close(stop)
+	wg.Wait()
+}
+
 func TestManagerWatchReloads(t *testing.T) {
 	dir := t.TempDir()
 	mgr, err := NewManager(WithConfigDir(dir))
@@ -71,3 +121,74 @@ func TestManagerWatchReloads(t *testing.T) {
 		t.Fatalf("watcher did not fire on config change")
 	}
 }
+
+func TestManagerLiveReloadAppliesLiveFieldsOnly(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cortexgo.json")
+	if err := os.WriteFile(path, []byte(`{"depth":"quick"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ConfigPathEnv, "")
+	load := func() (*Config, error) {
+		cfg, _, err := LoadResolved(path)
+		return cfg, err
+	}
+	initial, err := load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	r, err := NewManager(WithConfigPath(path), WithLoader(load), WithLiveReload(), WithDebounce(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan ReloadEvent, 4)
+	if err := r.WatchReloads(ctx, func(ev ReloadEvent) { events <- ev }); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"depth":"deep","data_dir":"/elsewhere"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-events:
+		if ev.Err != nil || len(ev.Applied) != 1 || ev.Applied[0] != "depth" || len(ev.Restart) != 1 || ev.Restart[0] != "data_dir" {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("reloader did not fire on config change")
+	}
+	if got := r.Get(); got.Depth != DepthDeep || got.DataDir != initial.DataDir {
+		t.Fatalf("depth=%q data_dir=%q after reload", got.Depth, got.DataDir)
+	}
+
+	// data_dir was reported once; a later edit reports only what it changes
+	if err := os.WriteFile(path, []byte(`{"depth":"standard","data_dir":"/elsewhere"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-events:
+		if ev.Err != nil || len(ev.Applied) != 1 || ev.Applied[0] != "depth" || len(ev.Restart) != 0 {
+			t.Fatalf("unexpected event %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("reloader did not fire on the second change")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"depth":"slow"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-events:
+		if ev.Err == nil {
+			t.Fatalf("invalid file should be rejected, got %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("reloader did not report the invalid file")
+	}
+	if r.Get().Depth != DepthStandard {
+		t.Fatal("invalid file replaced the running config")
+	}
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"reflect"
+)
+
+// ReloadEvent describes one reload of a watched config file.
+type ReloadEvent struct {
+	// Applied lists the changed fields now visible through Manager.Get.
+	Applied []string `json:"applied,omitempty"`
+	// Restart lists changed fields tagged reload:"restart" that keep their
+	// old value (WithLiveReload).
+	Restart []string `json:"restart,omitempty"`
+	// Err is set when the file could not be loaded or failed validation; the
+	// previous config stays in effect.
+	Err error `json:"-"`
+}
+
+// mergeLive copies the fields that changed between the last file snapshot
+// and next onto running. With liveOnly, fields tagged reload:"restart" keep
+// their running value and are reported in ev.Restart; since last moves on
+// with every reload, each such change is reported once.
+func mergeLive(running, last, next Config, liveOnly bool) (Config, ReloadEvent) {
+	var ev ReloadEvent
+	merged := running
+	mv := reflect.ValueOf(&merged).Elem()
+	nv := reflect.ValueOf(&next).Elem()
+	forEachField(reflect.ValueOf(&last).Elem(), func(key string, field reflect.Value, sf reflect.StructField) {
+		nf := nv.FieldByIndex(sf.Index)
+		if reflect.DeepEqual(field.Interface(), nf.Interface()) {
+			return
+		}
+		if liveOnly && sf.Tag.Get("reload") == "restart" {
+			ev.Restart = append(ev.Restart, key)
+			return
+		}
+		mv.FieldByIndex(sf.Index).Set(nf)
+		ev.Applied = append(ev.Applied, key)
+	})
+	return merged, ev
+}
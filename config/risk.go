@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Risk profiles the risk team argues within; empty selects balanced.
+const (
+	RiskConservative = "conservative"
+	RiskBalanced     = "balanced"
+	RiskAggressive   = "aggressive"
+)
+
+// RiskLimits are the concrete limits a risk profile puts on a trade.
+type RiskLimits struct {
+	Profile string `json:"profile"`
+	// MaxDrawdownPct is the largest peak-to-trough loss on the position, in
+	// percent, before it must be cut.
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
+	// MaxLeverage is the gross exposure per unit of capital; 1 means no margin.
+	MaxLeverage float64 `json:"max_leverage"`
+	// MinHoldingDays and MaxHoldingDays bound the intended holding period in
+	// trading days.
+	MinHoldingDays int `json:"min_holding_days"`
+	MaxHoldingDays int `json:"max_holding_days"`
+	// MaxPositionPct caps a single position as a percent of the portfolio.
+	MaxPositionPct float64 `json:"max_position_pct"`
+}
+
+var riskProfiles = map[string]RiskLimits{
+	RiskConservative: {Profile: RiskConservative, MaxDrawdownPct: 8, MaxLeverage: 1, MinHoldingDays: 20, MaxHoldingDays: 120, MaxPositionPct: 5},
+	RiskBalanced:     {Profile: RiskBalanced, MaxDrawdownPct: 15, MaxLeverage: 1.5, MinHoldingDays: 5, MaxHoldingDays: 60, MaxPositionPct: 10},
+	RiskAggressive:   {Profile: RiskAggressive, MaxDrawdownPct: 30, MaxLeverage: 3, MinHoldingDays: 1, MaxHoldingDays: 20, MaxPositionPct: 25},
+}
+
+// ValidRiskProfile reports whether profile names a known risk profile. Empty
+// selects balanced.
+func ValidRiskProfile(profile string) bool {
+	if profile == "" {
+		return true
+	}
+	_, ok := riskProfiles[profile]
+	return ok
+}
+
+// RiskProfiles lists the known profiles from most to least cautious.
+func RiskProfiles() []string {
+	return []string{RiskConservative, RiskBalanced, RiskAggressive}
+}
+
+// RiskLimitsFor returns the limits of the configured profile, balanced when
+// unset or unknown.
+func RiskLimitsFor(cfg *Config) RiskLimits {
+	if cfg != nil {
+		if l, ok := riskProfiles[strings.ToLower(cfg.RiskProfile)]; ok {
+			return l
+		}
+	}
+	return riskProfiles[RiskBalanced]
+}
+
+// Prompt renders the limits as a mandate block for the risk team's prompts.
+func (l RiskLimits) Prompt() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Risk profile: %s. Every recommendation must fit these limits:\n", l.Profile)
+	fmt.Fprintf(&b, "- Maximum drawdown tolerated on the position: %g%% from entry; set the stop loss no further away.\n", l.MaxDrawdownPct)
+	if l.MaxLeverage <= 1 {
+		b.WriteString("- Leverage: none (1x, cash only; no margin, no leveraged products).\n")
+	} else {
+		fmt.Fprintf(&b, "- Maximum leverage: %gx gross exposure.\n", l.MaxLeverage)
+	}
+	fmt.Fprintf(&b, "- Intended holding period: %d to %d trading days.\n", l.MinHoldingDays, l.MaxHoldingDays)
+	fmt.Fprintf(&b, "- Maximum position size: %g%% of the portfolio.\n", l.MaxPositionPct)
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SchemaID identifies the config schema document.
+const SchemaID = "https://github.com/dyike/CortexGo/config.schema.json"
+
+// fieldDocs are the schema descriptions shown by settings forms.
+var fieldDocs = map[string]string{
+	"project_dir":           "Project root directory",
+	"results_dir":           "Root of the per-run result directories (<symbol>/<trade date>/<run id>/)",
+	"data_dir":              "Directory for agent.db, CSV archives and batches",
+	"data_cache_dir":        "Directory for data source caches",
+	"eino_debug_enabled":    "Start the Eino devops debug server",
+	"eino_debug_port":       "Port of the Eino debug server",
+	"cache_enabled":         "Cache data source responses on disk",
+	"storage_backend":       "Where reports, session documents, caches and memory documents are kept: local files, sqlite (data_dir/agent.db) or s3 (the objstore_* bucket); empty means local",
+	"longport_app_key":      "Longport OpenAPI app key (mock market data when empty)",
+	"longport_app_secret":   "Longport OpenAPI app secret",
+	"longport_access_token": "Longport OpenAPI access token",
+	"longport_region":       "Region of the longport_app_key account: hk, us, sg or cn (mainland endpoints); empty means hk",
+	"longport_accounts":     "Further Longport accounts ({name, region, markets, app_key, app_secret, access_token}); each symbol goes to the accounts whose markets cover it first and fails over to the others",
+	"longport_profile":      "Longport account used for positions and balances (default for the longport_app_key account); empty means the first configured account",
+	"offline":               "Serve tools only from cache and local archives",
+	"data_provider":         "Data behind the market, news and social tools: live, or simulated for seeded random-walk candles and templated news without network access; empty means live",
+	"crawl_delay":           "Seconds between article page requests to the same site; 0 means 2, a longer robots.txt Crawl-delay wins",
+	"ignore_robots":         "Fetch article pages even where robots.txt disallows it",
+	"http_timeout":          "Seconds a data source HTTP request may take, body included; 0 means 30",
+	"seed":                  "Seeded run: temperature 0 and this LLM seed, cached data served regardless of age; 0 disables",
+	"depth":                 "Analysis depth preset; empty means standard",
+	"risk_profile":          "Risk profile (drawdown, leverage, holding period, position size limits) for the risk team; empty means balanced",
+	"skip_market_context":   "Skip the market regime briefing (index trend, VIX, sector ETFs, breadth) injected into analyst prompts",
+	"export_tool_data":      "Write every raw tool result of a run (arguments and full output) to a zip of JSON and CSV under results_dir",
+	"series_export":         "Format of the candle and indicator series the tools write to data_dir/export (csv, parquet or off); empty means csv",
+	"report_template_md":    "Go text/template file rendering md reports (exports, results.info) instead of the built-in layout; empty keeps the built-in layout",
+	"report_template_html":  "Go html/template file rendering html reports (exports, email attachments) instead of the built-in layout; empty keeps the built-in layout",
+	"report_redact":         "What exported and delivered reports leave out for redistribution: urls, social (raw social media posts and user names), sources (news outlet and data provider names); empty keeps everything",
+	"report_redact_terms":   "Further names replaced when report_redact includes sources",
+	"locale":                "Language of command line output (en or zh-CN); empty follows LANG",
+	"llm_provider":          "LLM behind every agent: deepseek, or mock for canned responses without API calls; empty means deepseek",
+	"mock_llm_script":       "JSON file of per-agent scripted responses for the mock LLM provider; empty uses the built-in ones",
+	"deepseek_api_key":      "DeepSeek API key used by every agent; not needed with the mock provider",
+	"finnhub_api_key":       "Finnhub API key for earnings call transcripts",
+	"fmp_api_key":           "Financial Modeling Prep API key, transcript fallback",
+	"smtp_host":             "SMTP server for report emails",
+	"smtp_port":             "SMTP port (465 implicit TLS, otherwise STARTTLS)",
+	"smtp_username":         "SMTP username",
+	"smtp_password":         "SMTP password",
+	"smtp_from":             "Sender address, defaults to smtp_username",
+	"email_recipients":      "Report recipients",
+	"webhook_urls":          "URLs that receive the result JSON after each analysis",
+	"webhook_secret":        "HMAC-SHA256 secret for webhook signatures",
+	"objstore_endpoint":     "S3 compatible endpoint, e.g. https://s3.us-east-1.amazonaws.com",
+	"objstore_region":       "Object storage region",
+	"objstore_bucket":       "Bucket results are synced to; enables sync when set",
+	"objstore_access_key":   "Object storage access key",
+	"objstore_secret_key":   "Object storage secret key",
+	"objstore_prefix":       "Key prefix inside the bucket",
+	"objstore_path_style":   "Use path-style bucket addressing (MinIO etc.)",
+	"encryption_key":        "32 byte AES-GCM key, base64 or hex",
+	"encryption_key_file":   "File containing the encryption key",
+	"encryption_keychain":   "Read the encryption key from the OS keychain",
+}
+
+// secretFields are rendered as password inputs and never echoed back.
+var secretFields = map[string]bool{
+	"longport_app_secret":   true,
+	"longport_access_token": true,
+	"deepseek_api_key":      true,
+	"finnhub_api_key":       true,
+	"fmp_api_key":           true,
+	"smtp_password":         true,
+	"webhook_secret":        true,
+	"objstore_secret_key":   true,
+	"encryption_key":        true,
+}
+
+// itemSecrets are the secret keys of list items, e.g. Longport accounts.
+var itemSecrets = map[string]bool{"app_secret": true, "access_token": true}
+
+// objectSchema describes a list item struct: its string and string list
+// fields, with the fields not marked omitempty required.
+func objectSchema(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key, opts, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if !sf.IsExported() || key == "" || key == "-" {
+			continue
+		}
+		prop := map[string]any{"type": "string"}
+		if sf.Type.Kind() == reflect.Slice {
+			prop = map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+		}
+		if itemSecrets[key] {
+			prop["writeOnly"] = true
+			prop["format"] = "password"
+		}
+		properties[key] = prop
+		if opts != "omitempty" {
+			required = append(required, key)
+		}
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required, "additionalProperties": false}
+}
+
+// Schema returns a JSON Schema (draft 2020-12) for Config, derived from the
+// json and validate tags so it enforces the same non-strict rules as Validate.
+// required_if rules become if/then clauses because every key is always present
+// in GetConfig output; property order is kept in x-order for form renderers.
+func Schema() map[string]any {
+	defaults := builtinDefaults("")
+	dv := reflect.ValueOf(defaults).Elem()
+	properties := map[string]any{}
+	var required []string
+	var conditionals []any
+	order := 0
+
+	forEachField(dv, func(key string, def reflect.Value, sf reflect.StructField) {
+		prop := map[string]any{"x-order": order}
+		order++
+		if doc := fieldDocs[key]; doc != "" {
+			prop["description"] = doc
+		}
+		switch sf.Type.Kind() {
+		case reflect.String:
+			prop["type"] = "string"
+		case reflect.Bool:
+			prop["type"] = "boolean"
+			prop["default"] = def.Bool()
+		case reflect.Int:
+			prop["type"] = "integer"
+			prop["default"] = def.Int()
+		case reflect.Slice:
+			prop["type"] = "array"
+			prop["items"] = map[string]any{"type": "string"}
+			if sf.Type.Elem().Kind() == reflect.Struct {
+				prop["items"] = objectSchema(sf.Type.Elem())
+			}
+		}
+		if secretFields[key] {
+			prop["writeOnly"] = true
+			prop["format"] = "password"
+		}
+
+		tag := sf.Tag.Get("validate")
+		if tag == "" || strings.Contains(","+tag+",", ",strict,") {
+			properties[key] = prop
+			return
+		}
+		for _, rule := range strings.Split(tag, ",") {
+			name, arg, _ := strings.Cut(rule, "=")
+			switch name {
+			case "required":
+				required = append(required, key)
+				prop["minLength"] = 1
+			case "required_if":
+				conditionals = append(conditionals, map[string]any{
+					"if": map[string]any{
+						"properties": map[string]any{arg: map[string]any{"minLength": 1}},
+						"required":   []string{arg},
+					},
+					"then": map[string]any{
+						"properties": map[string]any{key: map[string]any{"minLength": 1}},
+						"required":   []string{key},
+					},
+				})
+			case "min":
+				prop["minimum"], _ = strconv.Atoi(arg)
+			case "max":
+				prop["maximum"], _ = strconv.Atoi(arg)
+			case "oneof":
+				if sf.Type.Kind() == reflect.Slice {
+					prop["items"] = map[string]any{"type": "string", "enum": strings.Fields(arg)}
+				} else {
+					prop["enum"] = append([]string{""}, strings.Fields(arg)...)
+				}
+			case "url":
+				pattern := map[string]any{"type": "string", "pattern": "^https?://"}
+				if sf.Type.Kind() == reflect.Slice {
+					prop["items"] = pattern
+				} else {
+					prop["pattern"] = "^(https?://.*)?$"
+				}
+			}
+		}
+		properties[key] = prop
+	})
+
+	schema := map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"$id":                  SchemaID,
+		"title":                "CortexGo config",
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	if len(conditionals) > 0 {
+		schema["allOf"] = conditionals
+	}
+	return schema
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaCoversEveryField(t *testing.T) {
+	schema := Schema()
+	if _, err := json.Marshal(schema); err != nil {
+		t.Fatalf("schema is not JSON: %v", err)
+	}
+	props := schema["properties"].(map[string]any)
+	forEachField(reflect.ValueOf(&Config{}).Elem(), func(key string, _ reflect.Value, _ reflect.StructField) {
+		prop, ok := props[key].(map[string]any)
+		if !ok {
+			t.Errorf("schema is missing %s", key)
+			return
+		}
+		if prop["description"] == nil {
+			t.Errorf("%s has no description", key)
+		}
+	})
+
+	depth := props["depth"].(map[string]any)
+	if !reflect.DeepEqual(depth["enum"], []string{"", DepthQuick, DepthStandard, DepthDeep}) {
+		t.Errorf("depth enum = %v", depth["enum"])
+	}
+	if port := props["smtp_port"].(map[string]any); port["maximum"] != 65535 {
+		t.Errorf("smtp_port maximum = %v", port["maximum"])
+	}
+	if props["deepseek_api_key"].(map[string]any)["writeOnly"] != true {
+		t.Error("deepseek_api_key should be writeOnly")
+	}
+	if !reflect.DeepEqual(schema["required"], []string{"project_dir", "results_dir", "data_dir", "data_cache_dir"}) {
+		t.Errorf("required = %v", schema["required"])
+	}
+	if n := len(schema["allOf"].([]any)); n != 2 {
+		t.Errorf("want 2 required_if clauses for the objstore keys, got %d", n)
+	}
+}
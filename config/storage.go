@@ -0,0 +1,8 @@
+package config
+
+// Storage backends.
+const (
+	StorageLocal  = "local"
+	StorageSQLite = "sqlite"
+	StorageS3     = "s3"
+)
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Validation rules are declared on Config fields with a validate tag holding
+// comma separated rules:
+//
+//	required           the field must be non-empty
+//	required_if=<key>  required when the field named by json key <key> is set
+//	required_unless=<key>:<value>
+//	                   required unless the field named by <key> equals <value>
+//	min=<n>, max=<n>   integer bounds
+//	oneof=<a b c>      a non-empty string (every item of a list) must be one of the listed values
+//	url                every non-empty value must be an http(s) URL
+//	strict             the field's rules only apply in strict mode
+//
+// Field sources reported alongside errors.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// FieldError is one violated rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Source  string `json:"source,omitempty"`
+}
+
+// ValidationError carries every violation found.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// ValidateFields returns every violation of the validate tags. Strict adds the
+// rules marked strict.
+func (c *Config) ValidateFields(strict bool) []FieldError {
+	v := reflect.ValueOf(c).Elem()
+	values := map[string]reflect.Value{}
+	forEachField(v, func(key string, field reflect.Value, _ reflect.StructField) {
+		values[key] = field
+	})
+
+	var errs []FieldError
+	forEachField(v, func(key string, field reflect.Value, sf reflect.StructField) {
+		tag := sf.Tag.Get("validate")
+		if tag == "" {
+			return
+		}
+		rules := strings.Split(tag, ",")
+		for _, r := range rules {
+			if r == "strict" && !strict {
+				return
+			}
+		}
+		var lo, hi *int
+		for _, r := range rules {
+			name, arg, _ := strings.Cut(r, "=")
+			switch name {
+			case "required":
+				if isEmpty(field) {
+					errs = append(errs, FieldError{Field: key, Rule: r, Message: key + " cannot be empty"})
+				}
+			case "required_unless":
+				other, value, _ := strings.Cut(arg, ":")
+				if o, ok := values[other]; ok && o.String() != value && isEmpty(field) {
+					errs = append(errs, FieldError{Field: key, Rule: r, Message: fmt.Sprintf("%s cannot be empty unless %s is %s", key, other, value)})
+				}
+			case "required_if":
+				if other, ok := values[arg]; ok && !isEmpty(other) && isEmpty(field) {
+					errs = append(errs, FieldError{Field: key, Rule: r, Message: fmt.Sprintf("%s is required when %s is set", key, arg)})
+				}
+			case "min", "max":
+				n, err := strconv.Atoi(arg)
+				if err != nil {
+					panic(fmt.Sprintf("config: bad %s rule on %s", r, key))
+				}
+				if name == "min" {
+					lo = &n
+				} else {
+					hi = &n
+				}
+			case "oneof":
+				allowed := strings.Fields(arg)
+				for _, s := range stringValues(field) {
+					if !contains(allowed, s) {
+						errs = append(errs, FieldError{Field: key, Rule: r, Message: fmt.Sprintf("%s must be one of %s", key, strings.Join(allowed, ", "))})
+						break
+					}
+				}
+			case "url":
+				for _, u := range stringValues(field) {
+					if !ValidURL(u) {
+						errs = append(errs, FieldError{Field: key, Rule: r, Message: key + " must be http(s) URLs"})
+						break
+					}
+				}
+			}
+		}
+		if lo != nil || hi != nil {
+			if fe, ok := checkRange(key, int(field.Int()), lo, hi); !ok {
+				errs = append(errs, fe)
+			}
+		}
+	})
+	return append(errs, c.validateLongport()...)
+}
+
+func checkRange(key string, n int, lo, hi *int) (FieldError, bool) {
+	switch {
+	case lo != nil && hi != nil && (n < *lo || n > *hi):
+		return FieldError{Field: key, Rule: fmt.Sprintf("min=%d,max=%d", *lo, *hi), Message: fmt.Sprintf("%s must be between %d and %d", key, *lo, *hi)}, false
+	case lo != nil && hi == nil && n < *lo:
+		return FieldError{Field: key, Rule: fmt.Sprintf("min=%d", *lo), Message: fmt.Sprintf("%s must be at least %d", key, *lo)}, false
+	case hi != nil && lo == nil && n > *hi:
+		return FieldError{Field: key, Rule: fmt.Sprintf("max=%d", *hi), Message: fmt.Sprintf("%s must be at most %d", key, *hi)}, false
+	}
+	return FieldError{}, true
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+func stringValues(v reflect.Value) []string {
+	switch v.Kind() {
+	case reflect.String:
+		if s := v.String(); s != "" {
+			return []string{s}
+		}
+	case reflect.Slice:
+		out := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out = append(out, v.Index(i).String())
+		}
+		return out
+	}
+	return nil
+}
+
+// ValidURL reports whether u is an absolute http(s) URL with a host, what
+// the url rule accepts.
+func ValidURL(u string) bool {
+	parsed, err := url.Parse(u)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationReport is the result of ValidateResolved.
+type ValidationReport struct {
+	Path    string            `json:"path,omitempty"`
+	Strict  bool              `json:"strict"`
+	OK      bool              `json:"ok"`
+	Errors  []FieldError      `json:"errors,omitempty"`
+	Sources map[string]string `json:"sources"`
+}
+
+// ValidateResolved loads the config the same way LoadResolved does and
+// reports every violation together with where the offending value came from.
+// In strict mode unknown keys in the file are reported as well.
+func ValidateResolved(explicit string, strict bool) (*ValidationReport, error) {
+	path, err := ResolvePath(explicit)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := builtinDefaults("")
+	defaults := *cfg
+	fileKeys := map[string]bool{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", path, err)
+		}
+		for k := range raw {
+			fileKeys[k] = true
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", path, err)
+		}
+	}
+	fromFile := *cfg
+	_ = godotenv.Load()
+	cfg.loadFromEnv()
+
+	report := &ValidationReport{Path: path, Strict: strict, Sources: fieldSources(&defaults, &fromFile, cfg, fileKeys)}
+	report.Errors = cfg.ValidateFields(strict)
+	for i := range report.Errors {
+		report.Errors[i].Source = report.Sources[report.Errors[i].Field]
+	}
+	if strict {
+		var unknown []string
+		for k := range fileKeys {
+			if _, ok := report.Sources[k]; !ok {
+				unknown = append(unknown, k)
+			}
+		}
+		sort.Strings(unknown)
+		for _, k := range unknown {
+			report.Errors = append(report.Errors, FieldError{Field: k, Rule: "known", Message: "unknown config key " + k, Source: SourceFile})
+		}
+	}
+	report.OK = len(report.Errors) == 0
+	return report, nil
+}
+
+// fieldSources attributes each field to the last stage that changed it.
+func fieldSources(defaults, fromFile, final *Config, fileKeys map[string]bool) map[string]string {
+	sources := map[string]string{}
+	d, f := reflect.ValueOf(defaults).Elem(), reflect.ValueOf(fromFile).Elem()
+	forEachField(reflect.ValueOf(final).Elem(), func(key string, v reflect.Value, sf reflect.StructField) {
+		fv := f.FieldByName(sf.Name)
+		switch {
+		case !reflect.DeepEqual(v.Interface(), fv.Interface()):
+			sources[key] = SourceEnv
+		case fileKeys[key]:
+			sources[key] = SourceFile
+		case !reflect.DeepEqual(v.Interface(), d.FieldByName(sf.Name).Interface()):
+			sources[key] = SourceEnv
+		default:
+			sources[key] = SourceDefault
+		}
+	})
+	return sources
+}
@@ -1,6 +1,9 @@
 package consts
 
 const (
+	// 大盘环境节点，在分析师之前运行
+	MarketContext = "market_context"
+
 	// 分析师节点
 	MarketAnalyst       = "market_analyst"
 	SocialAnalyst       = "social_analyst"
@@ -21,6 +24,9 @@ const (
 	NeutralAnalyst = "neutral_analyst"
 	RiskJudge      = "risk_judge"
 
+	// 增量分析节点：两次完整分析之间，根据新数据更新上次的结论
+	DeltaAnalyst = "delta_analyst"
+
 	// 原有节点保持兼容
 	Coordinator = "coordinator"
 	Analyst     = "analyst"
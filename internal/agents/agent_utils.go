@@ -3,17 +3,29 @@ package agents
 import (
 	"context"
 	"sync"
+	"time"
 
 	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/buildinfo"
+	"github.com/dyike/CortexGo/internal/mockllm"
+	"github.com/dyike/CortexGo/internal/prompts"
+	"github.com/dyike/CortexGo/models"
 )
 
 var (
-	ChatModel *openai.ChatModel
+	ChatModel model.ToolCallingChatModel
 	chatMu    sync.Mutex
 )
 
+// DeepSeekBaseURL OpenAI 兼容的 DeepSeek 接口地址
+const DeepSeekBaseURL = "https://api.deepseek.com/v1"
+
+// DeepSeekModel 所有 agent 共用的模型
+const DeepSeekModel = "deepseek-chat"
+
 func InitChatModel(ctx context.Context, cfg *config.Config) error {
 	if ChatModel != nil {
 		return nil
@@ -26,12 +38,28 @@ func InitChatModel(ctx context.Context, cfg *config.Config) error {
 		return nil
 	}
 
+	// mock 提供方按 agent 返回预设或脚本回复，不调用任何接口
+	if cfg.MockLLM() {
+		var script mockllm.Script
+		if cfg.MockLLMScript != "" {
+			var err error
+			if script, err = mockllm.LoadScript(cfg.MockLLMScript); err != nil {
+				return err
+			}
+		}
+		ChatModel = mockllm.New(script)
+		return nil
+	}
+
 	maxTokens := 8192
+	temperature, seed := Sampling(cfg)
 	chatModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
-		BaseURL:   "https://api.deepseek.com/v1",
-		APIKey:    cfg.DeepSeekAPIKey,
-		Model:     "deepseek-chat",
-		MaxTokens: &maxTokens,
+		BaseURL:     DeepSeekBaseURL,
+		APIKey:      cfg.DeepSeekAPIKey,
+		Model:       DeepSeekModel,
+		MaxTokens:   &maxTokens,
+		Temperature: temperature,
+		Seed:        seed,
 	})
 	if err != nil {
 		return err
@@ -40,6 +68,62 @@ func InitChatModel(ctx context.Context, cfg *config.Config) error {
 	return nil
 }
 
+// ResetChatModel 丢弃已创建的模型，下次 InitChatModel / DecisionModel 按新的配置重建；
+// 用于同一进程依次运行不同配置（如 A/B 实验），不能与正在进行的运行并发调用
+func ResetChatModel() {
+	chatMu.Lock()
+	defer chatMu.Unlock()
+	ChatModel = nil
+	clear(decisionModels)
+}
+
+// Sampling 固定种子运行时的采样参数：温度 0 加上配置的种子（不支持 seed 的接口会忽略它）；
+// 未固定时均为 nil，使用模型默认值
+func Sampling(cfg *config.Config) (*float32, *int) {
+	if cfg == nil || cfg.Seed <= 0 {
+		return nil, nil
+	}
+	temperature, seed := float32(0), cfg.Seed
+	return &temperature, &seed
+}
+
+// RunInputsFor 记录本次运行的不确定输入；数据摘要与工具调用次数在生成报告时补全
+func RunInputsFor(cfg *config.Config) *models.RunInputs {
+	preset := PresetFor(cfg)
+	temperature, _ := Sampling(cfg)
+	ri := &models.RunInputs{
+		Temperature:   temperature,
+		Models:        []string{modelName(cfg, preset.Model)},
+		Depth:         preset.Name,
+		PromptsDigest: prompts.Digest(),
+		PromptDigests: prompts.Digests(),
+		StartedAt:     time.Now(),
+	}
+	build := buildinfo.Get()
+	ri.Build = &build
+	if preset.DecisionModel != preset.Model && !cfg.MockLLM() {
+		ri.Models = append(ri.Models, preset.DecisionModel)
+	}
+	if cfg != nil {
+		ri.Seed = cfg.Seed
+		ri.RiskProfile = cfg.RiskProfile
+		ri.PinnedData = cfg.Seed > 0
+		ri.Offline = cfg.Offline
+		ri.SimulatedData = cfg.SimulatedData()
+		ri.LLMProvider = cfg.LLMProvider
+		ri.ConfigDigest = cfg.Digest()
+	}
+	return ri
+}
+
+// modelName 返回实际使用的模型名；mock 提供方统一记为 mock
+func modelName(cfg *config.Config, name string) string {
+	if cfg.MockLLM() {
+		return mockllm.Name
+	}
+	return name
+}
+
 func ToolCallChecker(ctx context.Context, sr *schema.StreamReader[*schema.Message]) (bool, error) {
 	defer sr.Close()
 	for {
@@ -0,0 +1,12 @@
+package analysts
+
+import (
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/internal/tools"
+)
+
+// wrapAnalystTools 包装分析师的工具：记录工具输出供报告生成证据链；数据源不可用时降级返回，不中断分析
+func wrapAnalystTools(agent string, ts []tool.BaseTool) []tool.BaseTool {
+	return tools.WithDegradation(agent, provenance.WrapTools(agent, ts))
+}
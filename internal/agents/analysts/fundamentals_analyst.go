@@ -3,26 +3,53 @@ package analysts
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log"
 	"time"
 
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent/react"
 	"github.com/cloudwego/eino/schema"
 	"github.com/dyike/CortexGo/config"
 	"github.com/dyike/CortexGo/consts"
 	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/internal/prompts"
+	"github.com/dyike/CortexGo/internal/regime"
+	"github.com/dyike/CortexGo/internal/tools"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func NewFundamentalsAnalystNode[I, O any](ctx context.Context, cfg *config.Config) *compose.Graph[I, O] {
 	g := compose.NewGraph[I, O]()
+	queryDocumentsTool := tools.NewQueryDocumentsTool(cfg)
+	earningsCallTool := tools.NewEarningsCallTool(cfg)
+	insiderSentimentTool := tools.NewInsiderSentimentTool(cfg)
+
+	fundamentalsTools := []tool.BaseTool{
+		queryDocumentsTool,
+		earningsCallTool,
+		insiderSentimentTool,
+	}
+
+	agent, err := react.NewAgent(ctx, &react.AgentConfig{
+		MaxStep:          agents.PresetFor(cfg).MaxToolSteps, // 按分析深度限制工具调用步数
+		ToolCallingModel: agents.Guard(consts.FundamentalsAnalyst, agents.ChatModel),
+		ToolsConfig: compose.ToolsNodeConfig{
+			Tools: wrapAnalystTools(consts.FundamentalsAnalyst, fundamentalsTools),
+		},
+		StreamToolCallChecker: agents.ToolCallChecker,
+	})
+	if err != nil {
+		log.Fatalf("failed to create agent: %v", err)
+	}
+	agentLambda, err := compose.AnyLambda(agent.Generate, agent.Stream, nil, nil)
+	if err != nil {
+		log.Fatalf("failed to create agent lambda: %v", err)
+	}
 
 	_ = g.AddLambdaNode("load", compose.InvokableLambdaWithOption(loadFundamentalsAnalystMessages))
-	_ = g.AddChatModelNode("agent", agents.ChatModel)
+	_ = g.AddLambdaNode("agent", agentLambda)
 	_ = g.AddLambdaNode("router", compose.InvokableLambdaWithOption(fundamentalsAnalystRouter))
 
 	_ = g.AddEdge(compose.START, "load")
@@ -68,9 +95,7 @@ func fundamentalsAnalystRouter(ctx context.Context, input *schema.Message, opts
 		}
 
 		if reportContent != "" {
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "fundamentals_analyst_report.md"
-			if err := utils.WriteMarkdown(filePath, fileName, reportContent); err != nil {
+			if err := agents.WriteReport(ctx, state, "fundamentals_analyst_report.md", reportContent); err != nil {
 				log.Printf("Failed to write fundamentals report to file: %v", err)
 			}
 		}
@@ -89,12 +114,18 @@ If you or any other assistant has the FINAL TRANSACTION PROPOSAL: **BUY/HOLD/SEL
 prefix your response with FINAL TRANSACTION PROPOSAL: **BUY/HOLD/SELL** so the team knows to stop.
 
 You have access to the following tools:
-
+- query_documents: Search the annual reports, broker research, earnings slides and filings the user has ingested for {ticker}. Look up the figures you cite (revenue, margins, guidance, segment data) and reference the document title and page, e.g. (2024 Annual Report, p.45). If nothing relevant is found, say which figures are not backed by a filed document.
+- get_earnings_call_transcript: Summarize the analyst Q&A from the latest earnings call for US-listed tickers. Pass before_date={trade_date}; compare management's answers on guidance, margins and demand with the reported figures, and note any question they deflected.
+- get_insider_sentiment: Get the monthly insider sentiment (MSPR and net shares) for US-listed tickers. Pass before_date={trade_date}; when you discuss insider activity, cite the MSPR and net share figures and the months they cover rather than describing it loosely.
 
 {system_message}
 
+Every tool result starts with an evidence ID such as [E3]. When a statement relies on a figure or fact from a tool result, cite the ID right after it, e.g. "RSI 为 61.2 [E3]".
+
 For your reference, the current date is {current_date}. The company we want to look at is {ticker} .
 
+{market_context}
+
 The output content should be in Chinese.
 `
 		systemPrompt, _ := prompts.LoadPrompt("analysts/fundamentals_analyst")
@@ -109,6 +140,7 @@ The output content should be in Chinese.
 			"trade_date":        state.TradeDate,
 			"current_date":      time.Now().Format("2006-01-02"),
 			"ticker":            state.CompanyOfInterest,
+			"market_context":    regime.Context(state),
 			"system_message":    systemPrompt,
 		}
 
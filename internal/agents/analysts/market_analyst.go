@@ -2,7 +2,6 @@ package analysts
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
@@ -15,9 +14,9 @@ import (
 	"github.com/dyike/CortexGo/consts"
 	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/internal/prompts"
+	"github.com/dyike/CortexGo/internal/regime"
 	"github.com/dyike/CortexGo/internal/tools"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func NewMarketAnalyst[I, O any](ctx context.Context, cfg *config.Config) *compose.Graph[I, O] {
@@ -27,6 +26,10 @@ func NewMarketAnalyst[I, O any](ctx context.Context, cfg *config.Config) *compos
 	marketTools := []tool.BaseTool{
 		getMarketDataTool,
 		getStockStatsIndicatorsWindowTool,
+		tools.NewCorrelationTool(cfg),
+		tools.NewMarketStructureTool(cfg),
+		tools.NewVolatilityTool(cfg),
+		tools.NewIntermarketTool(cfg),
 	}
 	// Test tool info
 	if toolInfo, err := getMarketDataTool.Info(ctx); err != nil {
@@ -36,10 +39,10 @@ func NewMarketAnalyst[I, O any](ctx context.Context, cfg *config.Config) *compos
 	}
 
 	agent, err := react.NewAgent(ctx, &react.AgentConfig{
-		MaxStep:          40, // 增加最大步数，参考实现用的是40
-		ToolCallingModel: agents.ChatModel,
+		MaxStep:          agents.PresetFor(cfg).MaxToolSteps, // 按分析深度限制工具调用步数
+		ToolCallingModel: agents.Guard(consts.MarketAnalyst, agents.ChatModel),
 		ToolsConfig: compose.ToolsNodeConfig{
-			Tools: marketTools,
+			Tools: wrapAnalystTools(consts.MarketAnalyst, marketTools),
 		},
 		// 添加调试选项
 		// MessageModifier: func(ctx context.Context, input []*schema.Message) []*schema.Message {
@@ -87,11 +90,21 @@ prefix your response with FINAL TRANSACTION PROPOSAL: **BUY/HOLD/SELL** so the t
 You have access to the following tools:
 - get_market_data: Get market data for a specific symbol and date range.
 - get_stock_stats_indicators_window: Get comprehensive technical indicator analysis with ALL major indicators (SMA, EMA, RSI, MACD, Bollinger Bands, ATR, VWMA, MFI) calculated at once
+- get_correlation_matrix: Compare how the stock's daily returns move with the current holdings (pass just {ticker}) or with peers (pass several tickers). Pass before_date={trade_date}; if it flags a concentration cluster, say so and how it affects the case for adding the stock.
+- get_market_structure: Label the recent price structure (higher/lower highs and lows, trading range, breakouts, springs/upthrusts, volume climaxes) and its Wyckoff phase. Pass before_date={trade_date}; weigh the indicators against the phase and say where they agree or conflict.
+- get_volatility_regime: Read the VIX, its term structure and the volatility regime with its position-size multiplier. Pass before_date={trade_date}; in elevated or stressed regimes widen the expected range of moves and say how that changes entries and stops.
+- get_intermarket_analysis: Compare the stock with its sector ETF, the US dollar and the commodities tied to its sector. Pass before_date={trade_date} and the sector if you know it; say which drivers support or conflict with the technical picture, and flag a stock moving against its drivers.
+
+Daily bars cover the regular session only; get_market_data also returns the latest pre-market, after-hours and overnight quotes when available. Treat extended-hours moves as early, low-volume signals rather than confirmed price action.
 
 {system_message}
 
+Every tool result starts with an evidence ID such as [E3]. When a statement relies on a figure or fact from a tool result, cite the ID right after it, e.g. "RSI 为 61.2 [E3]".
+
 For your reference, the current date is {current_date}. The company we want to look at is {ticker} .
 
+{market_context}
+
 The output content should be in Chinese.
 `
 		systemPrompt, _ := prompts.LoadPrompt("analysts/market_analyst")
@@ -106,6 +119,7 @@ The output content should be in Chinese.
 			"trade_date":        state.TradeDate,
 			"current_date":      time.Now().Format("2006-01-02"),
 			"ticker":            state.CompanyOfInterest,
+			"market_context":    regime.Context(state),
 			"system_message":    systemPrompt,
 		}
 
@@ -125,10 +139,8 @@ func marketRouter(ctx context.Context, input *schema.Message, opts ...any) (outp
 			state.MarketReport = input.Content
 			state.Messages = append(state.Messages, input)
 
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "market_analyst_report.md"
 			// 将报告写入本地markdown文件
-			if err := utils.WriteMarkdown(filePath, fileName, input.Content); err != nil {
+			if err := agents.WriteReport(ctx, state, "market_analyst_report.md", input.Content); err != nil {
 				log.Printf("Failed to write market report to file: %v", err)
 			}
 		}
@@ -2,7 +2,6 @@ package analysts
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
@@ -15,9 +14,9 @@ import (
 	"github.com/dyike/CortexGo/consts"
 	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/internal/prompts"
+	"github.com/dyike/CortexGo/internal/regime"
 	"github.com/dyike/CortexGo/internal/tools"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func NewNewsAnalyst[I, O any](ctx context.Context, cfg *config.Config) *compose.Graph[I, O] {
@@ -25,18 +24,32 @@ func NewNewsAnalyst[I, O any](ctx context.Context, cfg *config.Config) *compose.
 	googleFinanceNewsTool := tools.NewGoogleFinanceNewsTool(cfg)
 	googleNewsSearchTool := tools.NewGoogleNewsSearchTool(cfg)
 	googleStockNewsTool := tools.NewGoogleStockNewsTool(cfg)
+	topHeadlinesTool := tools.NewTopHeadlinesTool(cfg)
+	newsTimelineTool := tools.NewNewsTimelineTool(cfg)
+	pastAnalysesTool := tools.NewSearchPastAnalysesTool(cfg)
+	earningsCallTool := tools.NewEarningsCallTool(cfg)
+	anomalyTool := tools.NewAnomalyTool(cfg)
+	yieldCurveTool := tools.NewYieldCurveTool(cfg)
+	catalystsTool := tools.NewCatalystsTool(cfg)
 
 	newsTools := []tool.BaseTool{
 		googleFinanceNewsTool,
 		googleNewsSearchTool,
 		googleStockNewsTool,
+		topHeadlinesTool,
+		newsTimelineTool,
+		pastAnalysesTool,
+		earningsCallTool,
+		anomalyTool,
+		yieldCurveTool,
+		catalystsTool,
 	}
 
 	agent, err := react.NewAgent(ctx, &react.AgentConfig{
-		MaxStep:          40, // 增加最大步数，参考实现用的是40
-		ToolCallingModel: agents.ChatModel,
+		MaxStep:          agents.PresetFor(cfg).MaxToolSteps, // 按分析深度限制工具调用步数
+		ToolCallingModel: agents.Guard(consts.NewsAnalyst, agents.ChatModel),
 		ToolsConfig: compose.ToolsNodeConfig{
-			Tools: newsTools,
+			Tools: wrapAnalystTools(consts.NewsAnalyst, newsTools),
 		},
 		// 添加流式工具调用检查器
 		StreamToolCallChecker: agents.ToolCallChecker,
@@ -75,11 +88,26 @@ You have access to the following tools:
 - get_google_finance_news: Pull the latest macro and sector headlines from Google Finance news to understand market-moving narratives.
 - search_google_news: Run an advanced Google News query with language, country, and recency filters to collect context-rich coverage.
 - get_google_stock_news: Retrieve Google News articles for the target ticker to monitor company announcements, sentiment, and reactions.
+- get_top_headlines: Read today's front page or a topic feed (world, business, markets, economy, ...) of a Google News country edition, without a query. Use the edition of the stock's home market (US, HK, CN, ...) to see what the whole market is reacting to before judging company-specific news.
+- get_news_timeline: Get the ticker's recent news as a dated list of typed events (earnings, guidance, rating changes, deals, legal, management, capital return, product), with duplicate reports merged. Pass before_date={trade_date}; use it to lay out what happened in order before weighing individual headlines.
+- search_past_analyses: Look up what our earlier reports concluded in similar situations (e.g. the last earnings season for this ticker). Always pass before_date={trade_date} so only earlier analyses are used, and say when a past finding informs your view.
+- get_earnings_call_transcript: Summarize the analyst Q&A from the latest earnings call for US-listed tickers. Pass before_date={trade_date}; use it to see which concerns analysts pressed management on and how confidently they answered.
+- detect_anomalies: Rank the days in the look-back window with overnight gaps, volume spikes or volatility expansions. Pass before_date={trade_date}; call it early and look for the news behind the top-ranked days first (for a gap, news published after the previous close). Say when a large move has no news to explain it.
+- get_yield_curve: Summarize the US Treasury curve (2s10s and 3m10y spreads, 2- and 10-year moves over the week and month, inversion, steepening or flattening). Pass before_date={trade_date}; use it for the rates part of the macro picture and say what the rate backdrop means for this stock's valuation and financing.
+- get_upcoming_catalysts: List the scheduled catalysts after the trade date: the next earnings release with consensus estimates, an estimated IPO lockup expiry and the FOMC rate decisions. Pass trade_date={trade_date}; name the dated events within the holding horizon that could move the stock and say how the timing affects the trade.
+
+Sources are tagged with a reliability tier: [wire] and [major] outlets report facts first-hand, [press_release] is the company's own framing, and [opinion] sites (Motley Fool, Seeking Alpha, Benzinga, ...) are commentary often written for clicks. Base your view on wire and major coverage, treat opinion pieces as a read on retail sentiment rather than evidence, and pass min_quality (e.g. 0.6) when a feed is crowded with low-quality sources.
+
+Headlines are tagged with the market session they were published in (pre-market, intraday, after-hours, overnight, closed). News released outside regular hours first trades on thin pre-market or after-hours volume and is fully priced at the next open, while intraday news has usually been absorbed already; weigh the reaction you expect accordingly.
 
 {system_message}
 
+Every tool result starts with an evidence ID such as [E3]. When a statement relies on a figure or fact from a tool result, cite the ID right after it, e.g. "RSI 为 61.2 [E3]".
+
 For your reference, the current date is {current_date}. The current company we want to analyze is {ticker}.
 
+{market_context}
+
 The output content should be in Chinese.
 `
 		systemPrompt, _ := prompts.LoadPrompt("analysts/news_analyst")
@@ -94,6 +122,7 @@ The output content should be in Chinese.
 			"trade_date":        state.TradeDate,
 			"current_date":      time.Now().Format("2006-01-02"),
 			"ticker":            state.CompanyOfInterest,
+			"market_context":    regime.Context(state),
 			"system_message":    systemPrompt,
 		}
 
@@ -112,9 +141,7 @@ func newsAnalystRouter(ctx context.Context, input *schema.Message, opts ...any)
 			state.NewsReport = input.Content
 			state.Messages = append(state.Messages, input)
 
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "news_analyst_report.md"
-			if err := utils.WriteMarkdown(filePath, fileName, input.Content); err != nil {
+			if err := agents.WriteReport(ctx, state, "news_analyst_report.md", input.Content); err != nil {
 				log.Printf("Failed to write news report to file: %v", err)
 			}
 		}
@@ -2,7 +2,6 @@ package analysts
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
@@ -15,9 +14,9 @@ import (
 	"github.com/dyike/CortexGo/consts"
 	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/internal/prompts"
+	"github.com/dyike/CortexGo/internal/regime"
 	"github.com/dyike/CortexGo/internal/tools"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func NewSocialAnalyst[I, O any](ctx context.Context, cfg *config.Config) *compose.Graph[I, O] {
@@ -35,10 +34,10 @@ func NewSocialAnalyst[I, O any](ctx context.Context, cfg *config.Config) *compos
 	}
 
 	agent, err := react.NewAgent(ctx, &react.AgentConfig{
-		MaxStep:          40, // 增加最大步数，参考实现用的是40
-		ToolCallingModel: agents.ChatModel,
+		MaxStep:          agents.PresetFor(cfg).MaxToolSteps, // 按分析深度限制工具调用步数
+		ToolCallingModel: agents.Guard(consts.SocialAnalyst, agents.ChatModel),
 		ToolsConfig: compose.ToolsNodeConfig{
-			Tools: marketTools,
+			Tools: wrapAnalystTools(consts.SocialAnalyst, marketTools),
 		},
 		// 添加流式工具调用检查器
 		StreamToolCallChecker: agents.ToolCallChecker,
@@ -87,8 +86,12 @@ Please follow these guardrails to prevent context overruns:
 
 {system_message}
 
+Every tool result starts with an evidence ID such as [E3]. When a statement relies on a figure or fact from a tool result, cite the ID right after it, e.g. "RSI 为 61.2 [E3]".
+
 For your reference, the current date is {current_date}. The current company we want to analyze is {ticker}".
 
+{market_context}
+
 The output content should be in Chinese.
 `
 		systemPrompt, _ := prompts.LoadPrompt("analysts/social_analyst")
@@ -103,6 +106,7 @@ The output content should be in Chinese.
 			"trade_date":        state.TradeDate,
 			"current_date":      time.Now().Format("2006-01-02"),
 			"ticker":            state.CompanyOfInterest,
+			"market_context":    regime.Context(state),
 			"system_message":    systemPrompt,
 		}
 
@@ -122,9 +126,7 @@ func socialAnalystRouter(ctx context.Context, input *schema.Message, opts ...any
 			state.SocialReport = input.Content
 			state.Messages = append(state.Messages, input)
 
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "social_analyst_report.md"
-			if err := utils.WriteMarkdown(filePath, fileName, input.Content); err != nil {
+			if err := agents.WriteReport(ctx, state, "social_analyst_report.md", input.Content); err != nil {
 				log.Printf("Failed to write social report to file: %v", err)
 			}
 		}
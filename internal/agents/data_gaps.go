@@ -0,0 +1,59 @@
+package agents
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// DataGaps 列出数据源不可用时仍完成报告的分析师，以及工具或模型调用崩溃的 agent，
+// 提示研究经理与风险裁判调低其权重；没有数据缺口时为空
+func DataGaps(state *models.TradingState) string {
+	failures := nodeFailures(state)
+	if len(state.SourceOutages) == 0 {
+		return failures
+	}
+	var agentsInOrder []string
+	sources := map[string][]string{}
+	calls := map[string]int{}
+	for _, o := range state.SourceOutages {
+		if _, ok := sources[o.Agent]; !ok {
+			agentsInOrder = append(agentsInOrder, o.Agent)
+		}
+		if !slices.Contains(sources[o.Agent], o.Source) {
+			sources[o.Agent] = append(sources[o.Agent], o.Source)
+		}
+		calls[o.Agent]++
+	}
+
+	var b strings.Builder
+	b.WriteString("Data gaps: these analysts wrote their reports while some of their data sources were down, so their reports rest on partial inputs:\n")
+	for _, agent := range agentsInOrder {
+		fmt.Fprintf(&b, "- %s: %s unavailable (%d tool calls failed)\n", agent, strings.Join(sources[agent], ", "), calls[agent])
+	}
+	b.WriteString("Give these reports less weight than those with complete inputs, do not read missing coverage as a neutral or positive signal, and lower your confidence accordingly.")
+	if failures != "" {
+		b.WriteString("\n" + failures)
+	}
+	return b.String()
+}
+
+// nodeFailures 列出发生 panic 的工具与模型调用；没有时为空
+func nodeFailures(state *models.TradingState) string {
+	if len(state.NodeFailures) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Failures: these steps crashed and produced no output:\n")
+	for _, f := range state.NodeFailures {
+		if f.Tool != "" {
+			fmt.Fprintf(&b, "- %s: tool %s crashed (%s)\n", f.Agent, f.Tool, f.Error)
+		} else {
+			fmt.Fprintf(&b, "- %s: its model call crashed, so its report is missing (%s)\n", f.Agent, f.Error)
+		}
+	}
+	b.WriteString("Treat the affected reports as incomplete and do not infer anything from the missing output.")
+	return b.String()
+}
@@ -0,0 +1,189 @@
+package delta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/prompts"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/utils"
+)
+
+// 提示中引用上次最终决策与此前增量结论的长度上限（字符）
+const (
+	maxDecisionExcerpt = 3000
+	maxEarlierExcerpt  = 600
+)
+
+// Input 增量分析的输入：上次完整分析之后到达的数据
+type Input struct {
+	Quote     models.MarketQuote
+	BasePrice float64 // 上次分析时的价格，未知时为 0
+	// News 上次分析之后出现的新闻，不包含上次分析已经看到的条目
+	News []models.NewsItem
+	// Reasons 触发本次刷新的原因，可为空
+	Reasons []string
+	// Earlier 同一份完整报告之后已做过的增量分析，按时间先后排列
+	Earlier []*models.DeltaAnalysis
+}
+
+// Analyze 在两次完整分析之间做一次廉价的刷新：只把上次完整报告的结论与计划、以及此后的新数据交给模型，
+// 单次调用、不使用工具，判断新信息是否改变结论。模型未给出明确建议时沿用上次的建议
+func Analyze(ctx context.Context, prev *report.Report, in Input) (res *models.DeltaAnalysis, err error) {
+	if prev == nil {
+		return nil, errors.New("delta analysis needs the previous full report")
+	}
+	if agents.ChatModel == nil {
+		return nil, errors.New("chat model is not initialized")
+	}
+	// 不在分析图中运行，模型 panic 时以错误返回，而不是让调用方（watch、libcortex）崩溃
+	defer func() {
+		if r := recover(); r != nil {
+			agents.RecordFailure(ctx, &models.NodeFailure{Agent: consts.DeltaAnalyst, Error: fmt.Sprint(r), Stack: string(debug.Stack())})
+			res, err = nil, fmt.Errorf("%s: %v", consts.DeltaAnalyst, r)
+		}
+	}()
+
+	msgs, err := Messages(prev, in)
+	if err != nil {
+		return nil, err
+	}
+	out, err := agents.ChatModel.Generate(ctx, msgs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", consts.DeltaAnalyst, err)
+	}
+	return parse(prev, in, out.Content, time.Now()), nil
+}
+
+// Messages 组装增量分析的提示：系统提示为 delta/delta_analyst，用户消息只包含上次的结论与新数据
+func Messages(prev *report.Report, in Input) ([]*schema.Message, error) {
+	systemPrompt, err := prompts.LoadPrompt("delta/delta_analyst")
+	if err != nil {
+		return nil, err
+	}
+	systemPrompt = strings.ReplaceAll(systemPrompt, "{symbol}", prev.Symbol)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Previous full analysis of %s (trade date %s, generated %s):\n", prev.Symbol, prev.TradeDate, prev.GeneratedAt.Format("2006-01-02 15:04"))
+	d := previousDecision(prev)
+	fmt.Fprintf(&b, "- Recommendation: %s\n", utils.OrDash(prev.Recommendation))
+	if d.Confidence > 0 {
+		fmt.Fprintf(&b, "- Confidence: %.2f\n", d.Confidence)
+	}
+	if d.EntryPrice > 0 || d.StopLoss > 0 || d.TakeProfit > 0 {
+		fmt.Fprintf(&b, "- Entry %.2f, stop loss %.2f, take profit %.2f", d.EntryPrice, d.StopLoss, d.TakeProfit)
+		if d.PositionSize > 0 {
+			fmt.Fprintf(&b, ", position size %.1f%%", d.PositionSize*100)
+		}
+		if d.HoldingDays > 0 {
+			fmt.Fprintf(&b, ", holding period %d days", d.HoldingDays)
+		}
+		b.WriteString("\n")
+	}
+	if text := decisionText(prev); text != "" {
+		fmt.Fprintf(&b, "\nFinal decision excerpt:\n%s\n", excerpt(text, maxDecisionExcerpt))
+	}
+
+	if len(in.Earlier) > 0 {
+		b.WriteString("\nIncremental updates made since then:\n")
+		for _, e := range in.Earlier {
+			fmt.Fprintf(&b, "- %s: %s (confidence %.2f) at price %.2f. %s\n", e.GeneratedAt.Format("2006-01-02 15:04"), utils.OrDash(e.Recommendation), e.Confidence, e.Price, excerpt(strings.Join(strings.Fields(e.Content), " "), maxEarlierExcerpt))
+		}
+	}
+
+	b.WriteString("\nNew data since the previous analysis:\n")
+	q := in.Quote
+	fmt.Fprintf(&b, "- Latest quote: %.2f (day change %+.2f%%, open %.2f, high %.2f, low %.2f)", q.Last, q.ChangePct, q.Open, q.High, q.Low)
+	if q.Timestamp != "" {
+		fmt.Fprintf(&b, " at %s", q.Timestamp)
+	}
+	b.WriteString("\n")
+	if in.BasePrice > 0 && q.Last > 0 {
+		fmt.Fprintf(&b, "- Move since the previous analysis: %+.2f%% (%.2f -> %.2f)\n", (q.Last-in.BasePrice)/in.BasePrice*100, in.BasePrice, q.Last)
+	}
+	for _, r := range in.Reasons {
+		fmt.Fprintf(&b, "- Trigger: %s\n", r)
+	}
+	if len(in.News) == 0 {
+		b.WriteString("- No new headlines.\n")
+	} else {
+		b.WriteString("- New headlines:\n")
+		for _, item := range in.News {
+			fmt.Fprintf(&b, "  - %s (%s, %s, quality %.2f, sentiment %+.2f)\n", item.Title, item.Source, item.PublishedAt.Format("2006-01-02 15:04"), item.Quality, item.Sentiment)
+		}
+	}
+	b.WriteString("\nThe output content should be in Chinese.\n")
+
+	return []*schema.Message{
+		schema.SystemMessage(systemPrompt),
+		schema.UserMessage(b.String()),
+	}, nil
+}
+
+// parse 从模型回复中解析更新后的建议、置信度与计划
+func parse(prev *report.Report, in Input, content string, now time.Time) *models.DeltaAnalysis {
+	res := &models.DeltaAnalysis{
+		Symbol:                 prev.Symbol,
+		TradeDate:              now.Format("2006-01-02"),
+		BaseSessionId:          prev.SessionID,
+		BaseGeneratedAt:        prev.GeneratedAt,
+		BasePrice:              in.BasePrice,
+		Price:                  in.Quote.Last,
+		NewsCount:              len(in.News),
+		PreviousRecommendation: prev.Recommendation,
+		Recommendation:         report.ParseRecommendation(content),
+		Content:                content,
+		GeneratedAt:            now,
+	}
+	if res.Recommendation == "" {
+		res.Recommendation = prev.Recommendation
+	}
+	// 以最近一次增量结论作为比较基准，连续刷新时只标记真正发生的变化
+	current := prev.Recommendation
+	if n := len(in.Earlier); n > 0 && in.Earlier[n-1].Recommendation != "" {
+		current = in.Earlier[n-1].Recommendation
+	}
+	res.Changed = current != "" && res.Recommendation != current
+
+	res.Decision = report.ExtractDecision(&report.Report{
+		Symbol:         res.Symbol,
+		TradeDate:      res.TradeDate,
+		Recommendation: res.Recommendation,
+		Sections:       []report.Section{{Key: "final_trade_decision", Content: content}},
+		GeneratedAt:    now,
+	})
+	res.Confidence = res.Decision.Confidence
+	return res
+}
+
+// previousDecision 返回上次报告的结构化决策，报告未附带时从最终决策文本中解析
+func previousDecision(prev *report.Report) *models.TradingDecision {
+	if prev.Decision != nil {
+		return prev.Decision
+	}
+	return report.ExtractDecision(prev)
+}
+
+func decisionText(prev *report.Report) string {
+	if text := prev.Section("final_trade_decision"); text != "" {
+		return text
+	}
+	return prev.Section("trader_investment_plan")
+}
+
+// excerpt 截取文本的前 n 个字符
+func excerpt(text string, n int) string {
+	text = strings.TrimSpace(text)
+	if r := []rune(text); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,70 @@
+package delta
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/mockllm"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/models"
+)
+
+func previous() *report.Report {
+	return &report.Report{
+		SessionID:      "7",
+		Symbol:         "AAPL.US",
+		TradeDate:      "2026-10-16",
+		Recommendation: "BUY",
+		GeneratedAt:    time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC),
+		Sections:       []report.Section{{Key: "final_trade_decision", Content: "Buy the breakout.\n\nENTRY PRICE: 100\nSTOP LOSS: 94\n\nFINAL TRANSACTION PROPOSAL: **BUY**\nCONFIDENCE: 0.7"}},
+	}
+}
+
+func TestMessagesCarryOnlyTheConclusionAndNewData(t *testing.T) {
+	in := Input{
+		Quote:     models.MarketQuote{Symbol: "AAPL.US", Last: 97},
+		BasePrice: 100,
+		News:      []models.NewsItem{{Title: "Apple cuts guidance", Source: "Reuters", Quality: 1}},
+	}
+	msgs, err := Messages(previous(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agent := mockllm.New(nil).Agent(msgs); agent != consts.DeltaAnalyst {
+		t.Errorf("prompt attributed to %q, want %q", agent, consts.DeltaAnalyst)
+	}
+	user := msgs[len(msgs)-1].Content
+	for _, want := range []string{"Recommendation: BUY", "Confidence: 0.70", "stop loss 94.00", "Buy the breakout", "-3.00% (100.00 -> 97.00)", "Apple cuts guidance"} {
+		if !strings.Contains(user, want) {
+			t.Errorf("user message lacks %q:\n%s", want, user)
+		}
+	}
+}
+
+func TestAnalyzeParsesTheUpdatedStance(t *testing.T) {
+	saved := agents.ChatModel
+	agents.ChatModel = mockllm.New(nil)
+	t.Cleanup(func() { agents.ChatModel = saved })
+
+	in := Input{Quote: models.MarketQuote{Last: 97}, BasePrice: 100}
+	d, err := Analyze(context.Background(), previous(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Recommendation != "HOLD" || d.PreviousRecommendation != "BUY" || !d.Changed || d.BaseSessionId != "7" {
+		t.Errorf("delta = %+v, want a change from BUY to HOLD", d)
+	}
+	if d.Confidence != 0.5 || d.Decision == nil || d.Decision.StopLoss != 95 {
+		t.Errorf("confidence %v, decision %+v", d.Confidence, d.Decision)
+	}
+
+	// a second refresh compares with the latest stance, not the full report
+	in.Earlier = []*models.DeltaAnalysis{d}
+	if again, err := Analyze(context.Background(), previous(), in); err != nil || again.Changed {
+		t.Errorf("second delta = %+v, %v; want HOLD unchanged", again, err)
+	}
+}
@@ -0,0 +1,116 @@
+package agents
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/consts"
+)
+
+// DeepSeekReasonerModel 深度模式下研究经理与风险裁判使用的推理模型
+const DeepSeekReasonerModel = "deepseek-reasoner"
+
+// DepthPreset 一档分析深度对应的编排参数
+type DepthPreset struct {
+	Name string
+	// Analysts 参与分析的分析师，按执行顺序
+	Analysts []string
+	// DebateTurns 多空辩论发言总次数（至少 2，保证多空各一次）
+	DebateTurns int
+	// RiskTurns 风险评审发言总次数，0 表示交易员直接交给风险裁判
+	RiskTurns int
+	// Model 分析师、研究员、交易员与风险分析师使用的模型
+	Model string
+	// DecisionModel 研究经理与风险裁判使用的模型
+	DecisionModel string
+	// MaxToolSteps ReAct 分析师的最大步数（工具调用预算）
+	MaxToolSteps int
+}
+
+var allAnalysts = []string{consts.MarketAnalyst, consts.SocialAnalyst, consts.NewsAnalyst, consts.FundamentalsAnalyst}
+
+var depthPresets = map[string]DepthPreset{
+	config.DepthQuick: {
+		Name:          config.DepthQuick,
+		Analysts:      []string{consts.MarketAnalyst, consts.NewsAnalyst},
+		DebateTurns:   2,
+		RiskTurns:     0,
+		Model:         DeepSeekModel,
+		DecisionModel: DeepSeekModel,
+		MaxToolSteps:  12,
+	},
+	config.DepthStandard: {
+		Name:          config.DepthStandard,
+		Analysts:      allAnalysts,
+		DebateTurns:   2,
+		RiskTurns:     3,
+		Model:         DeepSeekModel,
+		DecisionModel: DeepSeekModel,
+		MaxToolSteps:  40,
+	},
+	config.DepthDeep: {
+		Name:          config.DepthDeep,
+		Analysts:      allAnalysts,
+		DebateTurns:   4,
+		RiskTurns:     6,
+		Model:         DeepSeekModel,
+		DecisionModel: DeepSeekReasonerModel,
+		MaxToolSteps:  60,
+	},
+}
+
+// PresetFor 返回配置对应的深度预设，未设置或未知时为 standard
+func PresetFor(cfg *config.Config) DepthPreset {
+	if cfg != nil {
+		if p, ok := depthPresets[strings.ToLower(cfg.Depth)]; ok {
+			return p
+		}
+	}
+	return depthPresets[config.DepthStandard]
+}
+
+// UsesAnalyst 预设是否包含该分析师
+func (p DepthPreset) UsesAnalyst(name string) bool {
+	for _, a := range p.Analysts {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+var decisionModels = map[string]model.ToolCallingChatModel{}
+
+// DecisionModel 返回研究经理与风险裁判使用的模型；与默认模型相同、使用 mock 提供方或创建失败时回退到 ChatModel
+func DecisionModel(ctx context.Context, cfg *config.Config) model.ToolCallingChatModel {
+	name := PresetFor(cfg).DecisionModel
+	if name == "" || name == DeepSeekModel || cfg.MockLLM() {
+		return ChatModel
+	}
+
+	chatMu.Lock()
+	defer chatMu.Unlock()
+	if m, ok := decisionModels[name]; ok {
+		return m
+	}
+	maxTokens := 8192
+	temperature, seed := Sampling(cfg)
+	m, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
+		BaseURL:     DeepSeekBaseURL,
+		APIKey:      cfg.DeepSeekAPIKey,
+		Model:       name,
+		MaxTokens:   &maxTokens,
+		Temperature: temperature,
+		Seed:        seed,
+	})
+	if err != nil {
+		log.Printf("init %s failed, using %s: %v", name, DeepSeekModel, err)
+		return ChatModel
+	}
+	decisionModels[name] = m
+	return m
+}
@@ -0,0 +1,85 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/models"
+)
+
+// Guard 包装 agent 使用的模型：模型调用 panic 时不让整个分析（以及托管 libcortex 的进程）崩溃，
+// 而是把 panic 记入 state.NodeFailures，并返回一条说明该 agent 未能完成的回复，分析继续进行
+func Guard(agent string, m model.ToolCallingChatModel) model.ToolCallingChatModel {
+	if m == nil {
+		return nil
+	}
+	return &guardedModel{inner: m, agent: agent}
+}
+
+type guardedModel struct {
+	inner model.ToolCallingChatModel
+	agent string
+}
+
+func (m *guardedModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := m.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &guardedModel{inner: inner, agent: m.agent}, nil
+}
+
+func (m *guardedModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (out *schema.Message, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			out, err = m.recovered(ctx, r), nil
+		}
+	}()
+	return m.inner.Generate(ctx, input, opts...)
+}
+
+func (m *guardedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (out *schema.StreamReader[*schema.Message], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			out, err = schema.StreamReaderFromArray([]*schema.Message{m.recovered(ctx, r)}), nil
+		}
+	}()
+	return m.inner.Stream(ctx, input, opts...)
+}
+
+// 回调由被包装的模型自己触发，避免 eino 在外层重复触发导致用量统计翻倍
+func (m *guardedModel) IsCallbacksEnabled() bool { return components.IsCallbacksEnabled(m.inner) }
+
+func (m *guardedModel) GetType() string {
+	typ, _ := components.GetType(m.inner)
+	return typ
+}
+
+// recovered 记录 panic 并生成代替模型回复的说明
+func (m *guardedModel) recovered(ctx context.Context, r any) *schema.Message {
+	RecordFailure(ctx, &models.NodeFailure{Agent: m.agent, Error: fmt.Sprint(r), Stack: string(debug.Stack())})
+	return schema.AssistantMessage(fmt.Sprintf("（%s 未能完成本轮分析：模型调用发生内部错误 %v，本节内容缺失。）", m.agent, r), nil)
+}
+
+// RecordFailure 把一次被恢复的 panic 写入 state.NodeFailures；ctx 不在分析图中时只打印日志
+func RecordFailure(ctx context.Context, failure *models.NodeFailure) {
+	if failure.At.IsZero() {
+		failure.At = time.Now()
+	}
+	where := failure.Agent
+	if failure.Tool != "" {
+		where += "/" + failure.Tool
+	}
+	log.Printf("recovered panic in %s: %s\n%s", where, failure.Error, failure.Stack)
+	_ = compose.ProcessState[*models.TradingState](ctx, func(_ context.Context, state *models.TradingState) error {
+		state.NodeFailures = append(state.NodeFailures, failure)
+		return nil
+	})
+}
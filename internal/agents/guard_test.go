@@ -0,0 +1,101 @@
+package agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+)
+
+type panickingModel struct{}
+
+func (panickingModel) Generate(context.Context, []*schema.Message, ...model.Option) (*schema.Message, error) {
+	var m map[string]int
+	m["boom"]++
+	return nil, nil
+}
+
+func (panickingModel) Stream(context.Context, []*schema.Message, ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	panic("stream boom")
+}
+
+func (p panickingModel) WithTools([]*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return p, nil
+}
+
+// runGraph runs node alone in a graph holding a fresh trading state and
+// returns its output and the state.
+func runGraph[I, O any](t *testing.T, add func(g *compose.Graph[I, O]) error, in I) (O, *models.TradingState) {
+	t.Helper()
+	state := &models.TradingState{}
+	g := compose.NewGraph[I, O](compose.WithGenLocalState(func(context.Context) *models.TradingState { return state }))
+	if err := add(g); err != nil {
+		t.Fatal(err)
+	}
+	_ = g.AddEdge(compose.START, "node")
+	_ = g.AddEdge("node", compose.END)
+	r, err := g.Compile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := r.Invoke(context.Background(), in)
+	if err != nil {
+		t.Fatalf("panic was not isolated: %v", err)
+	}
+	return out, state
+}
+
+func TestGuardRecoversModelPanic(t *testing.T) {
+	out, state := runGraph(t, func(g *compose.Graph[[]*schema.Message, *schema.Message]) error {
+		return g.AddChatModelNode("node", agents.Guard("bull_researcher", panickingModel{}))
+	}, []*schema.Message{schema.UserMessage("hi")})
+
+	if out == nil || !strings.Contains(out.Content, "bull_researcher") {
+		t.Fatalf("reply = %+v, want a note naming the agent", out)
+	}
+	if len(state.NodeFailures) != 1 {
+		t.Fatalf("node failures = %+v, want one", state.NodeFailures)
+	}
+	f := state.NodeFailures[0]
+	if f.Agent != "bull_researcher" || f.Tool != "" || !strings.Contains(f.Error, "nil map") || f.Stack == "" {
+		t.Errorf("failure = %+v", f)
+	}
+}
+
+func TestWithDegradationRecoversToolPanic(t *testing.T) {
+	type input struct {
+		URL string `json:"url"`
+	}
+	crashing := utils.NewTool(&schema.ToolInfo{Name: "parse_page", Desc: "parses a page"},
+		func(_ context.Context, in *input) (string, error) {
+			var doc *struct{ Title string }
+			return doc.Title, nil
+		})
+	wrapped := tools.WithDegradation("news_analyst", []tool.BaseTool{crashing})[0].(tool.InvokableTool)
+
+	out, state := runGraph(t, func(g *compose.Graph[string, string]) error {
+		return g.AddLambdaNode("node", compose.InvokableLambda(func(ctx context.Context, args string) (string, error) {
+			return wrapped.InvokableRun(ctx, args)
+		}))
+	}, `{"url":"https://example.com"}`)
+
+	var failed models.FailedToolOutput
+	if err := json.Unmarshal([]byte(out), &failed); err != nil || failed.Status != "failed" || failed.Tool != "parse_page" {
+		t.Fatalf("tool output = %s (%v), want a failed result", out, err)
+	}
+	if len(state.NodeFailures) != 1 || state.NodeFailures[0].Agent != "news_analyst" || state.NodeFailures[0].Tool != "parse_page" {
+		t.Fatalf("node failures = %+v", state.NodeFailures)
+	}
+	if gaps := agents.DataGaps(state); !strings.Contains(gaps, "parse_page") {
+		t.Errorf("DataGaps = %q, want the crashed tool listed", gaps)
+	}
+}
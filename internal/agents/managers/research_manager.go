@@ -13,7 +13,6 @@ import (
 	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/internal/prompts"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func researchManagerRouter(ctx context.Context, input *schema.Message, opts ...any) (output string, err error) {
@@ -34,9 +33,7 @@ func researchManagerRouter(ctx context.Context, input *schema.Message, opts ...a
 			// Add the response to the state messages
 			state.Messages = append(state.Messages, input)
 
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "research_manager_report.md"
-			if err := utils.WriteMarkdown(filePath, fileName, input.Content); err != nil {
+			if err := agents.WriteReport(ctx, state, "research_manager_report.md", input.Content); err != nil {
 				log.Printf("Failed to write research manager report: %v", err)
 			}
 
@@ -95,6 +92,10 @@ func loadResearchManagerMessages(ctx context.Context, name string, opts ...any)
 		}
 
 		output, err = promptTemp.Format(ctx, context)
+		// 有分析师的数据源不可用时提示降低其报告权重
+		if gaps := agents.DataGaps(state); err == nil && gaps != "" {
+			output = append(output, schema.SystemMessage(gaps))
+		}
 		return err
 	})
 	return output, err
@@ -104,7 +105,7 @@ func NewResearchManagerNode[I, O any](ctx context.Context, cfg *config.Config) *
 	g := compose.NewGraph[I, O]()
 
 	_ = g.AddLambdaNode("load", compose.InvokableLambdaWithOption(loadResearchManagerMessages))
-	_ = g.AddChatModelNode("agent", agents.ChatModel)
+	_ = g.AddChatModelNode("agent", agents.Guard(consts.ResearchManager, agents.DecisionModel(ctx, cfg)))
 	_ = g.AddLambdaNode("router", compose.InvokableLambdaWithOption(researchManagerRouter))
 
 	_ = g.AddEdge(compose.START, "load")
@@ -9,10 +9,12 @@ import (
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/consts"
 	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/portfolio"
 	"github.com/dyike/CortexGo/internal/prompts"
+	"github.com/dyike/CortexGo/internal/regime"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func riskManagerRouter(ctx context.Context, input *schema.Message, opts ...any) (output string, err error) {
@@ -35,9 +37,7 @@ func riskManagerRouter(ctx context.Context, input *schema.Message, opts ...any)
 			// Add the response to the state messages
 			state.Messages = append(state.Messages, input)
 
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "risk_manager_report.md"
-			if err := utils.WriteMarkdown(filePath, fileName, input.Content); err != nil {
+			if err := agents.WriteReport(ctx, state, "risk_manager_report.md", input.Content); err != nil {
 				log.Printf("Failed to write risk manager report: %v", err)
 			}
 
@@ -55,7 +55,7 @@ func riskManagerRouter(ctx context.Context, input *schema.Message, opts ...any)
 }
 
 func loadRiskManagerMessages(ctx context.Context, name string, opts ...any) (output []*schema.Message, err error) {
-	err = compose.ProcessState[*models.TradingState](ctx, func(_ context.Context, state *models.TradingState) error {
+	err = compose.ProcessState[*models.TradingState](ctx, func(ctx context.Context, state *models.TradingState) error {
 		// Extract risk debate state data
 		riskDebateState := state.RiskDebateState
 		history := ""
@@ -84,19 +84,25 @@ func loadRiskManagerMessages(ctx context.Context, name string, opts ...any) (out
 
 		// Create prompt template
 		promptTemp := prompt.FromMessages(schema.FString,
-			schema.SystemMessage("{system_message}"),
+			schema.SystemMessage(systemPrompt),
 			schema.MessagesPlaceholder("user_input", true),
 		)
 
 		// Load prompt context
 		context := map[string]any{
-			"system_message":  systemPrompt,
+			"risk_profile":    config.RiskLimitsFor(state.Config).Prompt(),
+			"portfolio":       portfolio.Context(ctx, state.CompanyOfInterest),
+			"market_context":  regime.Context(state),
 			"trader_plan":     state.InvestmentPlan,
 			"past_memory_str": pastMemoryStr,
 			"history":         history,
 		}
 
 		output, err = promptTemp.Format(ctx, context)
+		// 有分析师的数据源不可用时提示降低其报告权重
+		if gaps := agents.DataGaps(state); err == nil && gaps != "" {
+			output = append(output, schema.SystemMessage(gaps))
+		}
 		return err
 	})
 	return output, err
@@ -106,7 +112,7 @@ func NewRiskManagerNode[I, O any](ctx context.Context, cfg *config.Config) *comp
 	g := compose.NewGraph[I, O]()
 
 	_ = g.AddLambdaNode("load", compose.InvokableLambdaWithOption(loadRiskManagerMessages))
-	_ = g.AddChatModelNode("agent", agents.ChatModel)
+	_ = g.AddChatModelNode("agent", agents.Guard(consts.RiskJudge, agents.DecisionModel(ctx, cfg)))
 	_ = g.AddLambdaNode("router", compose.InvokableLambdaWithOption(riskManagerRouter))
 
 	_ = g.AddEdge(compose.START, "load")
@@ -0,0 +1,33 @@
+package agents
+
+import (
+	"context"
+	"log"
+
+	"github.com/dyike/CortexGo/internal/rundir"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/persist"
+)
+
+// WriteReport 将 agent 的 Markdown 报告写入存储的 results 区域，key 为 <标的>/<交易日>/<run_id>/reports/<文件名>，
+// 同一天的多次运行互不覆盖；本地存储即 results_dir 下的同名文件。未分配 run ID 的状态沿用 <标的>/<交易日>/<文件名>
+func WriteReport(ctx context.Context, state *models.TradingState, fileName, content string) error {
+	key := state.CompanyOfInterest + "/" + state.TradeDate + "/" + fileName
+	if state.RunID != "" {
+		key = rundir.Key(state.CompanyOfInterest, state.TradeDate, state.RunID, rundir.ReportsDir, fileName)
+	}
+	if state.Config != nil {
+		// 配置了 encryption_key 时与运行目录的其他文件一样加密存储
+		if err := rundir.Put(ctx, state.Config, key, []byte(content)); err != nil {
+			return err
+		}
+	} else {
+		// 未带配置时沿用当前目录下的 results
+		s := persist.NewLocal(map[persist.Area]string{persist.Results: "results"})
+		if err := s.Put(ctx, persist.Results, key, []byte(content)); err != nil {
+			return err
+		}
+	}
+	log.Printf("written to: %s/%s", persist.Results, key)
+	return nil
+}
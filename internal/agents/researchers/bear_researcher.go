@@ -3,7 +3,6 @@ package researchers
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log"
 	"strings"
 
@@ -15,13 +14,12 @@ import (
 	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/internal/prompts"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func NewBearResearcherNode[I, O any](ctx context.Context, cfg *config.Config) *compose.Graph[I, O] {
 	g := compose.NewGraph[I, O]()
 	_ = g.AddLambdaNode("load", compose.InvokableLambdaWithOption(loadBearResearcherMessages))
-	_ = g.AddChatModelNode("agent", agents.ChatModel)
+	_ = g.AddChatModelNode("agent", agents.Guard(consts.BearResearcher, agents.ChatModel))
 	_ = g.AddLambdaNode("router", compose.InvokableLambdaWithOption(bearResearcherRouter))
 
 	_ = g.AddEdge(compose.START, "load")
@@ -86,16 +84,14 @@ func bearResearcherRouter(ctx context.Context, input *schema.Message, opts ...an
 			investmentDebateState.Count++
 			state.Messages = append(state.Messages, input)
 
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "bear_researcher_report.md"
-			if err := utils.WriteMarkdown(filePath, fileName, labeledArgument); err != nil {
+			if err := agents.WriteReport(ctx, state, "bear_researcher_report.md", labeledArgument); err != nil {
 				log.Printf("Failed to write bear researcher report: %v", err)
 			}
 		}
 
 		if state.InvestmentDebateState != nil {
 			next := consts.BullResearcher
-			if state.InvestmentDebateState.Count >= 2 {
+			if state.InvestmentDebateState.Count >= agents.PresetFor(state.Config).DebateTurns {
 				next = consts.ResearchManager
 			}
 			state.Goto = next
@@ -2,7 +2,6 @@ package researchers
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"strings"
 
@@ -14,14 +13,13 @@ import (
 	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/internal/prompts"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func NewBullResearcherNode[I, O any](ctx context.Context, cfg *config.Config) *compose.Graph[I, O] {
 	g := compose.NewGraph[I, O]()
 
 	_ = g.AddLambdaNode("load", compose.InvokableLambdaWithOption(loadBullResearcherMessages))
-	_ = g.AddChatModelNode("agent", agents.ChatModel)
+	_ = g.AddChatModelNode("agent", agents.Guard(consts.BullResearcher, agents.ChatModel))
 	_ = g.AddLambdaNode("router", compose.InvokableLambdaWithOption(bullResearcherRouter))
 
 	_ = g.AddEdge(compose.START, "load")
@@ -50,16 +48,14 @@ func bullResearcherRouter(ctx context.Context, input *schema.Message, opts ...an
 			investmentDebateState.Count++
 			state.Messages = append(state.Messages, input)
 
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "bull_researcher_report.md"
-			if err := utils.WriteMarkdown(filePath, fileName, labeledArgument); err != nil {
+			if err := agents.WriteReport(ctx, state, "bull_researcher_report.md", labeledArgument); err != nil {
 				log.Printf("Failed to write bull researcher report: %v", err)
 			}
 		}
 
 		if state.InvestmentDebateState != nil {
 			next := consts.BearResearcher
-			if state.InvestmentDebateState.Count >= 2 {
+			if state.InvestmentDebateState.Count >= agents.PresetFor(state.Config).DebateTurns {
 				next = consts.ResearchManager
 			}
 			state.Goto = next
@@ -12,14 +12,14 @@ import (
 	"github.com/dyike/CortexGo/consts"
 	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/internal/prompts"
+	"github.com/dyike/CortexGo/internal/regime"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func NewNeutralAnalystNode[I, O any](ctx context.Context, cfg *config.Config) *compose.Graph[I, O] {
 	g := compose.NewGraph[I, O]()
 	_ = g.AddLambdaNode("load", compose.InvokableLambdaWithOption(loadNeutralMsg))
-	_ = g.AddChatModelNode("agent", agents.ChatModel)
+	_ = g.AddChatModelNode("agent", agents.Guard(consts.NeutralAnalyst, agents.ChatModel))
 	_ = g.AddLambdaNode("router", compose.InvokableLambdaWithOption(neutralRouter))
 
 	_ = g.AddEdge(compose.START, "load")
@@ -48,18 +48,19 @@ func loadNeutralMsg(ctx context.Context, name string, opts ...any) (output []*sc
 
 		// Create prompt template
 		promptTemp := prompt.FromMessages(schema.FString,
-			schema.SystemMessage("{system_message}"),
+			schema.SystemMessage(systemPrompt),
 			schema.MessagesPlaceholder("user_input", true),
 		)
 
 		// Load prompt context
 		context := map[string]any{
-			"system_message":         systemPrompt,
+			"risk_profile":           config.RiskLimitsFor(state.Config).Prompt(),
 			"trader_decision":        state.TraderInvestmentPlan,
 			"market_research_report": state.MarketReport,
 			"social_media_report":    state.SocialReport,
 			"news_report":            state.NewsReport,
 			"fundamentals_report":    state.FundamentalsReport,
+			"market_context":         regime.Context(state),
 			"history":                history,
 			"current_risky_response": currentRiskyResponse,
 			"current_safe_response":  currentSafeResponse,
@@ -103,16 +104,14 @@ func neutralRouter(ctx context.Context, input *schema.Message, opts ...any) (str
 			// Add the response to the state messages
 			state.Messages = append(state.Messages, input)
 
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "neutral_analyst_report.md"
-			if err := utils.WriteMarkdown(filePath, fileName, argument); err != nil {
+			if err := agents.WriteReport(ctx, state, "neutral_analyst_report.md", argument); err != nil {
 				log.Printf("Failed to write neutral analyst report: %v", err)
 			}
 		}
 
 		next := consts.RiskyAnalyst
 		if state.RiskDebateState != nil {
-			if state.RiskDebateState.Count >= 3 {
+			if state.RiskDebateState.Count >= agents.PresetFor(state.Config).RiskTurns {
 				next = consts.RiskJudge
 			}
 		}
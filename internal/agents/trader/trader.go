@@ -13,7 +13,6 @@ import (
 	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/internal/prompts"
 	"github.com/dyike/CortexGo/models"
-	"github.com/dyike/CortexGo/pkg/utils"
 )
 
 func traderRouter(ctx context.Context, input *schema.Message, opts ...any) (output string, err error) {
@@ -29,9 +28,7 @@ func traderRouter(ctx context.Context, input *schema.Message, opts ...any) (outp
 			// Add the response to the state messages
 			state.Messages = append(state.Messages, input)
 
-			filePath := fmt.Sprintf("results/%s/%s", state.CompanyOfInterest, state.TradeDate)
-			fileName := "trader_report.md"
-			if err := utils.WriteMarkdown(filePath, fileName, input.Content); err != nil {
+			if err := agents.WriteReport(ctx, state, "trader_report.md", input.Content); err != nil {
 				log.Printf("Failed to write trader report: %v", err)
 			}
 
@@ -96,7 +93,7 @@ func NewTraderNode[I, O any](ctx context.Context, cfg *config.Config) *compose.G
 	g := compose.NewGraph[I, O]()
 
 	_ = g.AddLambdaNode("load", compose.InvokableLambdaWithOption(loadTraderMessages))
-	_ = g.AddChatModelNode("agent", agents.ChatModel)
+	_ = g.AddChatModelNode("agent", agents.Guard(consts.Trader, agents.ChatModel))
 	_ = g.AddLambdaNode("router", compose.InvokableLambdaWithOption(traderRouter))
 
 	_ = g.AddEdge(compose.START, "load")
@@ -0,0 +1,409 @@
+// Package agenttest runs a single agent node against fixture tool outputs and
+// scripted model replies, and compares what the agent sent to the model and
+// what it wrote to the trading state with a golden transcript. A prompt or
+// tool description change then shows up as a reviewable diff for exactly the
+// agents it affects, without a full run.
+//
+// A case is a JSON file:
+//
+//	{
+//	  "agent": "market_analyst",
+//	  "state": {"company_of_interest": "AAPL.US", "trade_date": "2025-06-02"},
+//	  "tools": {"get_market_data": "| date | close |\n..."},
+//	  "script": [
+//	    {"tool_calls": [{"name": "get_market_data", "arguments": {"symbol": "AAPL.US"}}]},
+//	    {"content": "## Market Report\n..."}
+//	  ]
+//	}
+//
+// tools answers every call of a tool with the same output; calling a tool the
+// case leaves out fails the run. script is played by the mock LLM; empty uses
+// its built-in reply. Run `go test ./internal/agenttest -update` to rewrite
+// the golden files after an intended change.
+package agenttest
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/graph"
+	"github.com/dyike/CortexGo/internal/mockllm"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/models"
+)
+
+var update = flag.Bool("update", false, "rewrite agent golden transcripts")
+
+// Case is one agent run: the state it starts from, the tool outputs it sees
+// and the model replies it gets.
+type Case struct {
+	Agent string               `json:"agent"`
+	State *models.TradingState `json:"state"`
+	// Tools maps tool names to the output every call of that tool returns.
+	Tools map[string]string `json:"tools,omitempty"`
+	// Script is the agent's model replies in order; empty uses the mock
+	// LLM's built-in reply.
+	Script []mockllm.Turn `json:"script,omitempty"`
+	// Depth and RiskProfile override the configuration; empty keeps it.
+	Depth       string `json:"depth,omitempty"`
+	RiskProfile string `json:"risk_profile,omitempty"`
+}
+
+// LoadCase reads a case from a JSON file.
+func LoadCase(path string) (*Case, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Case
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if c.Agent == "" {
+		return nil, fmt.Errorf("%s: agent is required", path)
+	}
+	if c.State == nil || c.State.CompanyOfInterest == "" || c.State.TradeDate == "" {
+		return nil, fmt.Errorf("%s: state needs company_of_interest and trade_date", path)
+	}
+	return &c, nil
+}
+
+// Transcript is what one agent run did: every model call with its input and
+// reply, the tools the model was offered, where the node routed next and the
+// state fields it changed.
+type Transcript struct {
+	Agent string
+	Tools map[string]string // tool name -> description
+	Calls []Call
+	Next  string
+	State map[string]any
+}
+
+// Call is one model call.
+type Call struct {
+	Input []Message
+	Reply Message
+}
+
+// Message is the part of a chat message that reaches the model.
+type Message struct {
+	Role      string
+	Content   string
+	ToolName  string
+	ToolCalls []string // name and arguments
+}
+
+// volatileKeys are state fields that change on every run.
+var volatileKeys = map[string]bool{"created_at": true, "fetched_at": true, "started_at": true, "at": true}
+
+// droppedKeys are state fields left out of transcripts: the configuration,
+// the message log (already in the calls) and the run inputs.
+var droppedKeys = map[string]bool{"config": true, "messages": true, "run_inputs": true}
+
+// modelMu serialises runs, which swap the process-wide chat model.
+var modelMu sync.Mutex
+
+// Run executes c's agent once with cfg and records the transcript. It swaps
+// agents.ChatModel for the duration of the run, so runs are serialised and
+// must not overlap a real analysis. Agents write their Markdown reports to
+// cfg's storage (results_dir by default), so cfg should point at a scratch
+// directory.
+func Run(ctx context.Context, cfg *config.Config, c *Case) (*Transcript, error) {
+	local := *cfg
+	local.LLMProvider = config.LLMMock
+	local.DataProvider = config.DataSimulated // nothing reaches the network
+	local.SkipMarketContext = true
+	if c.Depth != "" {
+		local.Depth = c.Depth
+	}
+	if c.RiskProfile != "" {
+		local.RiskProfile = c.RiskProfile
+	}
+
+	state := *c.State
+	state.Config = &local
+	if state.InvestmentDebateState == nil {
+		state.InvestmentDebateState = &models.InvestDebateState{}
+	}
+	if state.RiskDebateState == nil {
+		state.RiskDebateState = &models.RiskDebateState{}
+	}
+	before, err := stateFields(&state)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &recorder{inner: mockllm.New(mockllm.Script{c.Agent: c.Script}), log: &callLog{tools: map[string]string{}}}
+	modelMu.Lock()
+	defer modelMu.Unlock()
+	saved := agents.ChatModel
+	agents.ChatModel = rec
+	defer func() { agents.ChatModel = saved }()
+
+	tools := c.Tools
+	if tools == nil {
+		tools = map[string]string{}
+	}
+	next, err := graph.RunAgent(provenance.WithToolOutputs(ctx, tools), &local, c.Agent, &state)
+	if err != nil {
+		return nil, fmt.Errorf("run %s: %w", c.Agent, err)
+	}
+	after, err := stateFields(&state)
+	if err != nil {
+		return nil, err
+	}
+	changed := map[string]any{}
+	for k, v := range after {
+		if !equalJSON(before[k], v) {
+			changed[k] = v
+		}
+	}
+	return &Transcript{Agent: c.Agent, Tools: rec.log.tools, Calls: rec.log.calls, Next: next, State: changed}, nil
+}
+
+// stateFields is the state as a JSON object without dropped and volatile keys.
+func stateFields(state *models.TradingState) (map[string]any, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for k := range droppedKeys {
+		delete(fields, k)
+	}
+	return stripVolatile(fields).(map[string]any), nil
+}
+
+func stripVolatile(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			if volatileKeys[k] {
+				delete(v, k)
+			} else {
+				v[k] = stripVolatile(x)
+			}
+		}
+	case []any:
+		for i, x := range v {
+			v[i] = stripVolatile(x)
+		}
+	}
+	return v
+}
+
+func equalJSON(a, b any) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return string(x) == string(y)
+}
+
+// Render formats the transcript as text for golden files. The current date,
+// which some prompts include, is written as {today}.
+func (t *Transcript) Render() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# agent: %s\n", t.Agent)
+	if len(t.Tools) > 0 {
+		b.WriteString("\n# tools\n")
+		names := make([]string, 0, len(t.Tools))
+		for name := range t.Tools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&b, "- %s: %s\n", name, t.Tools[name])
+		}
+	}
+	for i, c := range t.Calls {
+		input := c.Input
+		if i > 0 && continues(t.Calls[i-1], c) {
+			// a follow-up turn of the same conversation: show only what is new
+			input = input[len(t.Calls[i-1].Input)+1:]
+			fmt.Fprintf(&b, "\n# call %d (continues call %d)\n", i+1, i)
+		} else {
+			fmt.Fprintf(&b, "\n# call %d\n", i+1)
+		}
+		for _, m := range input {
+			renderMessage(&b, m, "")
+		}
+		renderMessage(&b, c.Reply, "reply ")
+	}
+	fmt.Fprintf(&b, "\n# next: %s\n", t.Next)
+	state, _ := json.MarshalIndent(t.State, "", "  ")
+	fmt.Fprintf(&b, "\n# state\n%s\n", state)
+	return strings.ReplaceAll(b.String(), time.Now().Format("2006-01-02"), "{today}")
+}
+
+// continues reports whether next's input is prev's input followed by its reply.
+func continues(prev, next Call) bool {
+	if len(next.Input) <= len(prev.Input) {
+		return false
+	}
+	for i, m := range prev.Input {
+		if !equalJSON(m, next.Input[i]) {
+			return false
+		}
+	}
+	return equalJSON(prev.Reply, next.Input[len(prev.Input)])
+}
+
+func renderMessage(b *strings.Builder, m Message, prefix string) {
+	fmt.Fprintf(b, "\n## %s%s", prefix, m.Role)
+	if m.ToolName != "" {
+		fmt.Fprintf(b, " (%s)", m.ToolName)
+	}
+	b.WriteString("\n")
+	if m.Content != "" {
+		b.WriteString(strings.TrimRight(m.Content, "\n") + "\n")
+	}
+	for _, tc := range m.ToolCalls {
+		fmt.Fprintf(b, "-> %s\n", tc)
+	}
+}
+
+// Golden compares got with the golden file at path, or rewrites the file
+// when the test binary runs with -update.
+func Golden(t testing.TB, path string, got *Transcript) {
+	t.Helper()
+	text := got.Render()
+	if *update {
+		if err := os.WriteFile(path, []byte(text), 0o644); err != nil {
+			t.Fatalf("update %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden: %v (run with -update to create it)", err)
+	}
+	if string(want) != text {
+		t.Errorf("%s differs from the golden transcript (run with -update to accept):\n%s", filepath.Base(path), diff(string(want), text))
+	}
+}
+
+// RunDir runs every *.json case in dir as a subtest and compares it with the
+// .golden file next to it.
+func RunDir(t *testing.T, cfg *config.Config, dir string) {
+	t.Helper()
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no cases in %s", dir)
+	}
+	t.Chdir(t.TempDir())
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		t.Run(name, func(t *testing.T) {
+			c, err := LoadCase(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Run(context.Background(), cfg, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			Golden(t, strings.TrimSuffix(path, ".json")+".golden", got)
+		})
+	}
+}
+
+// diff lists the first differing lines of want and got.
+func diff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	var b strings.Builder
+	shown := 0
+	for i := 0; i < max(len(w), len(g)) && shown < 10; i++ {
+		var wl, gl string
+		if i < len(w) {
+			wl = w[i]
+		}
+		if i < len(g) {
+			gl = g[i]
+		}
+		if wl != gl {
+			fmt.Fprintf(&b, "line %d:\n  - %s\n  + %s\n", i+1, wl, gl)
+			shown++
+		}
+	}
+	return b.String()
+}
+
+// callLog collects the calls of a recorder and the copies WithTools makes.
+type callLog struct {
+	mu    sync.Mutex
+	calls []Call
+	tools map[string]string
+}
+
+// recorder wraps the model an agent talks to and logs every call.
+type recorder struct {
+	inner model.ToolCallingChatModel
+	log   *callLog
+	bound []*schema.ToolInfo
+}
+
+func (r *recorder) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := r.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &recorder{inner: inner, log: r.log, bound: tools}, nil
+}
+
+func (r *recorder) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	reply, err := r.inner.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	call := Call{Reply: message(reply)}
+	for _, m := range input {
+		call.Input = append(call.Input, message(m))
+	}
+	r.log.mu.Lock()
+	defer r.log.mu.Unlock()
+	for _, tool := range model.GetCommonOptions(&model.Options{Tools: r.bound}, opts...).Tools {
+		if tool != nil {
+			r.log.tools[tool.Name] = tool.Desc
+		}
+	}
+	r.log.calls = append(r.log.calls, call)
+	return reply, nil
+}
+
+func (r *recorder) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := r.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func message(m *schema.Message) Message {
+	out := Message{Role: string(m.Role), Content: m.Content, ToolName: m.ToolName}
+	for _, tc := range m.ToolCalls {
+		out.ToolCalls = append(out.ToolCalls, tc.Function.Name+" "+tc.Function.Arguments)
+	}
+	return out
+}
@@ -0,0 +1,11 @@
+package agenttest
+
+import (
+	"testing"
+
+	"github.com/dyike/CortexGo/config"
+)
+
+func TestAgentGoldens(t *testing.T) {
+	RunDir(t, config.DefaultConfigWithRoot(t.TempDir()), "testdata")
+}
@@ -0,0 +1,187 @@
+// Package alerts watches quotes for user-defined price conditions and fires a
+// callback (a fresh analysis plus notifications) when one is met.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+	"github.com/dyike/CortexGo/pkg/utils"
+)
+
+// Bounds of the quote polling interval.
+const (
+	DefaultInterval = time.Minute
+	MinInterval     = 10 * time.Second
+)
+
+// Quote is the price snapshot an alert is evaluated against.
+type Quote struct {
+	Symbol    string
+	Last      float64
+	PrevClose float64
+}
+
+// ChangePct is the move from the previous close in percent.
+func (q Quote) ChangePct() float64 {
+	if q.PrevClose == 0 {
+		return 0
+	}
+	return (q.Last - q.PrevClose) / q.PrevClose * 100
+}
+
+// New builds an alert from add parameters; exactly one of above, below and
+// move must be set. Bare tickers default to the US market, as Longport
+// quotes need a market suffix.
+func New(params models.AlertAddParams) (*models.Alert, error) {
+	symbol := dataflows.NormalizeSymbol(params.Symbol)
+	if err := dataflows.ValidateSymbol(symbol); err != nil {
+		return nil, err
+	}
+	if !strings.Contains(symbol, ".") {
+		symbol += ".US"
+	}
+	a := &models.Alert{Symbol: symbol, Repeat: params.Repeat}
+	set := 0
+	for _, c := range []struct {
+		condition string
+		value     float64
+	}{
+		{models.AlertAbove, params.Above},
+		{models.AlertBelow, params.Below},
+		{models.AlertMove, params.Move},
+	} {
+		if c.value < 0 {
+			return nil, fmt.Errorf("%s must be positive", c.condition)
+		}
+		if c.value > 0 {
+			a.Condition, a.Threshold = c.condition, c.value
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("set exactly one of above, below or move")
+	}
+	return a, nil
+}
+
+// Met reports whether the alert's condition holds for q.
+func Met(a models.Alert, q Quote) bool {
+	if q.Last <= 0 {
+		return false
+	}
+	switch a.Condition {
+	case models.AlertAbove:
+		return q.Last >= a.Threshold
+	case models.AlertBelow:
+		return q.Last <= a.Threshold
+	case models.AlertMove:
+		return q.PrevClose > 0 && math.Abs(q.ChangePct()) >= a.Threshold
+	}
+	return false
+}
+
+// Describe renders the condition for logs and notifications, e.g. "AAPL.US below 150".
+func Describe(a models.Alert) string {
+	if a.Condition == models.AlertMove {
+		return fmt.Sprintf("%s moves %g%%", a.Symbol, a.Threshold)
+	}
+	return fmt.Sprintf("%s %s %g", a.Symbol, a.Condition, a.Threshold)
+}
+
+// Engine polls quotes for the active alerts in Store and fires the ones whose
+// condition is met.
+type Engine struct {
+	Store *storage.Store
+	// Quotes fetches the latest quotes keyed by symbol.
+	Quotes func(ctx context.Context, symbols []string) (map[string]Quote, error)
+	// Fire handles a triggered alert and returns the analysis session it started.
+	Fire func(ctx context.Context, a models.Alert, q Quote) (string, error)
+	// OnTrigger, if set, is called after each trigger has been recorded.
+	OnTrigger func(models.AlertTrigger)
+	// Now defaults to time.Now.
+	Now func() time.Time
+}
+
+// Check runs one polling round. One-shot alerts fire once; repeating alerts
+// fire again only after their condition has cleared in between.
+func (e *Engine) Check(ctx context.Context) ([]models.AlertTrigger, error) {
+	active, err := e.Store.ListAlerts(ctx, "", false)
+	if err != nil || len(active) == 0 {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var symbols []string
+	for _, a := range active {
+		if !seen[a.Symbol] {
+			seen[a.Symbol] = true
+			symbols = append(symbols, a.Symbol)
+		}
+	}
+	quotes, err := e.Quotes(ctx, symbols)
+	if err != nil {
+		return nil, fmt.Errorf("fetch quotes: %w", err)
+	}
+
+	now := time.Now
+	if e.Now != nil {
+		now = e.Now
+	}
+	var fired []models.AlertTrigger
+	for _, a := range active {
+		q, ok := quotes[a.Symbol]
+		if !ok {
+			continue
+		}
+		if !Met(a, q) {
+			if !a.Armed {
+				if err := e.Store.RearmAlert(ctx, a.Id); err != nil {
+					return fired, err
+				}
+			}
+			continue
+		}
+		if !a.Armed {
+			continue
+		}
+
+		trigger := models.AlertTrigger{Alert: a, Price: q.Last, ChangePct: q.ChangePct()}
+		sessionID, err := e.Fire(ctx, a, q)
+		if err != nil {
+			// the alert still counts as triggered so a failing launch is not retried every poll
+			trigger.Error = err.Error()
+		}
+		trigger.SessionId = sessionID
+		at := now()
+		if err := e.Store.MarkAlertTriggered(ctx, a.Id, q.Last, sessionID, at); err != nil {
+			return fired, err
+		}
+		trigger.Alert.Armed, trigger.Alert.TriggeredAt, trigger.Alert.TriggerPrice, trigger.Alert.LastSessionId = false, at, q.Last, sessionID
+		if !a.Repeat {
+			trigger.Alert.Status = models.AlertStatusTriggered
+		}
+		fired = append(fired, trigger)
+		if e.OnTrigger != nil {
+			e.OnTrigger(trigger)
+		}
+	}
+	return fired, nil
+}
+
+// Run calls Check every interval until ctx is done. Failed rounds go to
+// onErr and the loop keeps going, so a flaky quote feed does not stop it.
+func (e *Engine) Run(ctx context.Context, interval time.Duration, onErr func(error)) {
+	if interval < MinInterval {
+		interval = MinInterval
+	}
+	utils.Poll(ctx, interval, func(ctx context.Context) error {
+		_, err := e.Check(ctx)
+		return err
+	}, onErr)
+}
@@ -0,0 +1,96 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+func TestNewValidatesCondition(t *testing.T) {
+	a, err := New(models.AlertAddParams{Symbol: " aapl ", Below: 150})
+	if err != nil || a.Symbol != "AAPL.US" || a.Condition != models.AlertBelow || a.Threshold != 150 {
+		t.Fatalf("alert = %+v, err = %v", a, err)
+	}
+	for _, p := range []models.AlertAddParams{
+		{Symbol: "AAPL"},
+		{Symbol: "AAPL", Below: 150, Above: 200},
+		{Symbol: "AAPL", Move: -5},
+		{Symbol: "", Above: 1},
+	} {
+		if _, err := New(p); err == nil {
+			t.Errorf("want error for %+v", p)
+		}
+	}
+	if !Met(models.Alert{Condition: models.AlertMove, Threshold: 5}, Quote{Last: 94, PrevClose: 100}) {
+		t.Error("a 6% drop should meet a 5% move alert")
+	}
+}
+
+func TestEngineFiresOnceAndRearms(t *testing.T) {
+	store, err := storage.NewStore(filepath.Join(t.TempDir(), "agent.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	oneShot := &models.Alert{Symbol: "AAPL.US", Condition: models.AlertBelow, Threshold: 150}
+	repeat := &models.Alert{Symbol: "700.HK", Condition: models.AlertAbove, Threshold: 400, Repeat: true}
+	for _, a := range []*models.Alert{oneShot, repeat} {
+		if err := store.CreateAlert(ctx, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prices := map[string]float64{"AAPL.US": 149, "700.HK": 401}
+	var fired []string
+	engine := &Engine{
+		Store: store,
+		Quotes: func(_ context.Context, symbols []string) (map[string]Quote, error) {
+			out := map[string]Quote{}
+			for _, s := range symbols {
+				out[s] = Quote{Symbol: s, Last: prices[s], PrevClose: prices[s]}
+			}
+			return out, nil
+		},
+		Fire: func(_ context.Context, a models.Alert, _ Quote) (string, error) {
+			fired = append(fired, a.Symbol)
+			if a.Symbol == "700.HK" {
+				return "", errors.New("no model configured")
+			}
+			return "42", nil
+		},
+	}
+
+	triggers, err := engine.Check(ctx)
+	if err != nil || len(triggers) != 2 {
+		t.Fatalf("triggers = %+v, err = %v", triggers, err)
+	}
+	if triggers[0].SessionId != "42" || triggers[0].Alert.Status != models.AlertStatusTriggered || triggers[1].Error == "" {
+		t.Errorf("triggers = %+v", triggers)
+	}
+
+	// still met: neither fires again, and the one-shot alert is no longer watched
+	if triggers, _ := engine.Check(ctx); len(triggers) != 0 {
+		t.Errorf("fired again while the condition held: %+v", triggers)
+	}
+	if active, _ := store.ListAlerts(ctx, "", false); len(active) != 1 || active[0].Id != repeat.Id || active[0].Armed {
+		t.Fatalf("active = %+v", active)
+	}
+
+	// the repeating alert re-arms once the price falls back, then fires on the next cross
+	prices["700.HK"] = 390
+	if triggers, _ := engine.Check(ctx); len(triggers) != 0 {
+		t.Fatalf("unexpected triggers %+v", triggers)
+	}
+	prices["700.HK"] = 410
+	if triggers, _ := engine.Check(ctx); len(triggers) != 1 || triggers[0].Price != 410 {
+		t.Fatalf("want a second trigger, got %+v", triggers)
+	}
+	if len(fired) != 3 {
+		t.Errorf("fired = %v", fired)
+	}
+}
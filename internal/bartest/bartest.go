@@ -0,0 +1,59 @@
+// Package bartest builds synthetic daily bars and serves them through a
+// dataflows.BarFetcher, for the tests of the layers that read price history
+// (regime, volatility, structure, intermarket, portfolio correlations).
+package bartest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// End is the date of the last bar Bars builds.
+const End = "2025-06-30"
+
+// Bars turns closes into daily bars, one calendar day apart and ending on
+// End, with a one-point high/low band around each close and a small volume
+// cycle.
+func Bars(closes []float64) []*models.MarketData {
+	end, _ := time.Parse("2006-01-02", End)
+	bars := make([]*models.MarketData, len(closes))
+	for i, c := range closes {
+		bars[i] = &models.MarketData{
+			Date:   end.AddDate(0, 0, i-len(closes)+1).Format("2006-01-02"),
+			Open:   c,
+			High:   c + 1,
+			Low:    c - 1,
+			Close:  c,
+			Volume: 1000 + int64(i%3)*100,
+		}
+	}
+	return bars
+}
+
+// Walk returns n closes starting at 100 and compounding the daily return
+// step(i) from the second close on.
+func Walk(n int, step func(i int) float64) []float64 {
+	closes := make([]float64, n)
+	price := 100.0
+	for i := range closes {
+		if i > 0 {
+			price *= 1 + step(i)
+		}
+		closes[i] = price
+	}
+	return closes
+}
+
+// Fetcher serves the bars in data by symbol and fails for any other symbol.
+func Fetcher(data map[string][]*models.MarketData) dataflows.BarFetcher {
+	return func(_ context.Context, symbol string, _ int) ([]*models.MarketData, error) {
+		if bars, ok := data[symbol]; ok {
+			return bars, nil
+		}
+		return nil, fmt.Errorf("no data for %s", symbol)
+	}
+}
@@ -0,0 +1,154 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRunResumeSkipsCompleted(t *testing.T) {
+	dir := t.TempDir()
+	m, err := New(dir, []string{"aapl.us", "MSFT.US", "AAPL.US", "TSLA.US"}, "2025-12-15", "")
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if len(m.Items) != 3 {
+		t.Fatalf("expected 3 de-duplicated items, got %d", len(m.Items))
+	}
+
+	// First run: MSFT fails, the context is cancelled while TSLA is running.
+	ctx, cancel := context.WithCancel(context.Background())
+	err = Run(ctx, m, func(ctx context.Context, symbol string) (Result, error) {
+		switch symbol {
+		case "MSFT.US":
+			return Result{}, errors.New("boom")
+		case "TSLA.US":
+			cancel()
+			return Result{}, ctx.Err()
+		}
+		return Result{Recommendation: "BUY", Confidence: 0.8}, nil
+	}, Options{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	loaded, err := Load(dir, m.ID)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	counts := loaded.Counts()
+	if counts[StatusCompleted] != 1 || counts[StatusFailed] != 1 || counts[StatusPending] != 1 {
+		t.Fatalf("unexpected counts after interrupt: %v", counts)
+	}
+
+	var (
+		mu  sync.Mutex
+		ran []string
+	)
+	err = Run(context.Background(), loaded, func(ctx context.Context, symbol string) (Result, error) {
+		mu.Lock()
+		ran = append(ran, symbol)
+		mu.Unlock()
+		return Result{Recommendation: "HOLD"}, nil
+	}, Options{Limiter: NewLimiter(2, 2, false)})
+	if err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if len(ran) != 2 {
+		t.Fatalf("resume should only rerun MSFT and TSLA, ran %v", ran)
+	}
+	for _, s := range ran {
+		if s == "AAPL.US" {
+			t.Fatal("completed symbol was analyzed again")
+		}
+	}
+	if c := loaded.Counts(); c[StatusCompleted] != 3 {
+		t.Fatalf("expected all completed, got %v", c)
+	}
+}
+
+func TestLoadResetsRunning(t *testing.T) {
+	dir := t.TempDir()
+	m, err := New(dir, []string{"AAPL.US"}, "2025-12-15", "quick")
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := m.Update(m.Items[0], func(it *Item) { it.Status = StatusRunning }); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	loaded, err := Load(dir, m.ID)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Items[0].Status != StatusPending || loaded.Depth != "quick" {
+		t.Fatalf("unexpected manifest after load: %+v", loaded.Items[0])
+	}
+	if _, err := Load(dir, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSummarizeRanksByRecommendationAndConfidence(t *testing.T) {
+	m := &Manifest{ID: "b", Items: []*Item{
+		{Symbol: "SELL1", Status: StatusCompleted, Recommendation: "SELL", Confidence: 0.9},
+		{Symbol: "FAIL", Status: StatusFailed, Error: "boom"},
+		{Symbol: "BUYLO", Status: StatusCompleted, Recommendation: "BUY", Confidence: 0.6},
+		{Symbol: "HOLD", Status: StatusCompleted, Recommendation: "HOLD", Confidence: 0.7},
+		{Symbol: "BUYHI", Status: StatusCompleted, Recommendation: "BUY", Confidence: 0.8},
+	}}
+	rows := Summarize(m)
+	want := []string{"BUYHI", "BUYLO", "HOLD", "SELL1", "FAIL"}
+	for i, r := range rows {
+		if r.Symbol != want[i] || r.Rank != i+1 {
+			t.Fatalf("row %d = %s (rank %d), want %s", i, r.Symbol, r.Rank, want[i])
+		}
+	}
+}
+
+func TestLimiterAIMD(t *testing.T) {
+	l := NewLimiter(1, 4, true)
+	l.backoff = 0
+	ctx := context.Background()
+	step := func(o Outcome) {
+		if err := l.Acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+		l.Release(o)
+	}
+
+	// Additive increase: a streak as long as the limit raises it by one.
+	step(OutcomeOK)
+	if l.Limit() != 2 {
+		t.Fatalf("expected 2 after one success, got %d", l.Limit())
+	}
+	step(OutcomeOK)
+	step(OutcomeOK)
+	if l.Limit() != 3 {
+		t.Fatalf("expected 3, got %d", l.Limit())
+	}
+	for i := 0; i < 10; i++ {
+		step(OutcomeOK)
+	}
+	if l.Limit() != 4 {
+		t.Fatalf("expected cap at 4, got %d", l.Limit())
+	}
+
+	// Multiplicative decrease on a rate limit.
+	step(OutcomeThrottled)
+	if l.Limit() != 2 {
+		t.Fatalf("expected 2 after throttle, got %d", l.Limit())
+	}
+
+	// Sustained errors shrink the limit by one.
+	for i := 0; i < errorWindow/2; i++ {
+		step(OutcomeError)
+	}
+	if l.Limit() != 1 {
+		t.Fatalf("expected 1 after errors, got %d", l.Limit())
+	}
+
+	if fixed := NewLimiter(1, 3, false); fixed.Limit() != 3 {
+		t.Fatalf("fixed limiter should use max, got %d", fixed.Limit())
+	}
+}
@@ -0,0 +1,164 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Outcome classifies a finished analysis for the concurrency controller.
+type Outcome int
+
+const (
+	OutcomeOK Outcome = iota
+	OutcomeError
+	OutcomeThrottled
+)
+
+const (
+	// errorWindow is how many recent outcomes the error rate is computed over.
+	errorWindow = 6
+	// maxErrorRate above which concurrency is reduced by one.
+	maxErrorRate = 0.5
+	// DefaultThrottleBackoff pauses new work after a rate limit.
+	DefaultThrottleBackoff = 30 * time.Second
+)
+
+// Limiter bounds how many analyses run at once. When adaptive it follows
+// AIMD: the limit halves on a rate limit (and new work pauses for the backoff),
+// drops by one when the recent error rate is high, and grows by one after a
+// streak of successes as long as the limit, up to max.
+type Limiter struct {
+	mu       sync.Mutex
+	wake     chan struct{}
+	limit    int
+	max      int
+	active   int
+	adaptive bool
+	backoff  time.Duration
+	paused   time.Time
+	streak   int
+	recent   []Outcome
+	onChange func(from, to int, reason string)
+}
+
+// NewLimiter returns a fixed limiter of size max, or an adaptive one that
+// starts at start and never exceeds max.
+func NewLimiter(start, max int, adaptive bool) *Limiter {
+	if max < 1 {
+		max = 1
+	}
+	if start < 1 || start > max || !adaptive {
+		start = max
+	}
+	return &Limiter{wake: make(chan struct{}), limit: start, max: max, adaptive: adaptive, backoff: DefaultThrottleBackoff}
+}
+
+// OnChange registers a callback for limit changes. It runs with the limiter
+// locked and must not call back into it.
+func (l *Limiter) OnChange(fn func(from, to int, reason string)) {
+	l.mu.Lock()
+	l.onChange = fn
+	l.mu.Unlock()
+}
+
+// Limit returns the current concurrency limit.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// Acquire blocks until a slot is free and any rate-limit pause has passed.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		wait := time.Until(l.paused)
+		if wait <= 0 && l.active < l.limit {
+			l.active++
+			l.mu.Unlock()
+			return nil
+		}
+		wake := l.wake
+		l.mu.Unlock()
+
+		var (
+			timer *time.Timer
+			fire  <-chan time.Time
+		)
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			fire = timer.C
+		}
+		select {
+		case <-wake:
+		case <-fire:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Release frees a slot and, when adaptive, adjusts the limit from outcome.
+func (l *Limiter) Release(outcome Outcome) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	if l.adaptive {
+		l.adjust(outcome)
+	}
+	close(l.wake)
+	l.wake = make(chan struct{})
+}
+
+func (l *Limiter) adjust(outcome Outcome) {
+	l.recent = append(l.recent, outcome)
+	if len(l.recent) > errorWindow {
+		l.recent = l.recent[1:]
+	}
+
+	switch outcome {
+	case OutcomeThrottled:
+		l.streak = 0
+		l.recent = nil
+		l.paused = time.Now().Add(l.backoff)
+		l.setLimit(l.limit/2, "rate limited")
+	case OutcomeError:
+		l.streak = 0
+		failed := 0
+		for _, o := range l.recent {
+			if o != OutcomeOK {
+				failed++
+			}
+		}
+		if len(l.recent) >= errorWindow/2 && float64(failed)/float64(len(l.recent)) > maxErrorRate {
+			l.recent = nil
+			l.setLimit(l.limit-1, "high error rate")
+		}
+	default:
+		l.streak++
+		if l.streak >= l.limit && l.limit < l.max && time.Now().After(l.paused) {
+			l.streak = 0
+			l.setLimit(l.limit+1, "healthy")
+		}
+	}
+}
+
+func (l *Limiter) setLimit(n int, reason string) {
+	if n < 1 {
+		n = 1
+	}
+	if n == l.limit {
+		return
+	}
+	from := l.limit
+	l.limit = n
+	if l.onChange != nil {
+		l.onChange(from, n, reason)
+	}
+}
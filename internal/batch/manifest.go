@@ -0,0 +1,173 @@
+// Package batch runs analyses for many symbols and checkpoints progress to a
+// manifest on disk so an interrupted batch can be resumed.
+package batch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/internal/rundir"
+	"github.com/dyike/CortexGo/models"
+)
+
+// Status is the state of a single symbol in a batch.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// ErrNotFound is returned by Load when no manifest exists for the batch id.
+var ErrNotFound = errors.New("batch not found")
+
+// Item tracks one symbol of a batch.
+type Item struct {
+	Symbol         string     `json:"symbol"`
+	Status         Status     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	Error          string     `json:"error,omitempty"`
+	Recommendation string     `json:"recommendation,omitempty"`
+	Confidence     float64    `json:"confidence,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+}
+
+// Manifest is the persisted progress of a batch. All mutations go through
+// Update so every change is flushed to disk before the next one.
+type Manifest struct {
+	ID        string    `json:"id"`
+	TradeDate string    `json:"trade_date"`
+	Depth     string    `json:"depth,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Items     []*Item   `json:"items"`
+
+	// Correlation is the return correlation check across the completed
+	// symbols, set by the caller once the run has finished.
+	Correlation *models.CorrelationMatrix `json:"correlation,omitempty"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// Dir is where manifests for dataDir are stored.
+func Dir(dataDir string) string {
+	return filepath.Join(dataDir, "batches")
+}
+
+// New creates and saves a manifest for symbols. Symbols are upper-cased and
+// de-duplicated, keeping their order.
+func New(dir string, symbols []string, tradeDate, depth string) (*Manifest, error) {
+	m := &Manifest{
+		ID:        rundir.NewID(time.Now()),
+		TradeDate: tradeDate,
+		Depth:     depth,
+		CreatedAt: time.Now(),
+	}
+	seen := map[string]bool{}
+	for _, s := range symbols {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		m.Items = append(m.Items, &Item{Symbol: s, Status: StatusPending})
+	}
+	if len(m.Items) == 0 {
+		return nil, errors.New("batch needs at least one symbol")
+	}
+	m.path = filepath.Join(dir, m.ID+".json")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return m, m.save()
+}
+
+// Load reads the manifest for id. Items left running by a crashed or
+// interrupted process are reset to pending.
+func Load(dir, id string) (*Manifest, error) {
+	path := filepath.Join(dir, filepath.Base(id)+".json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("decode manifest %s: %w", path, err)
+	}
+	m.path = path
+	for _, it := range m.Items {
+		if it.Status == StatusRunning {
+			it.Status = StatusPending
+		}
+	}
+	return &m, nil
+}
+
+// Remaining returns the items a run still has to process: pending ones and
+// those that failed previously.
+func (m *Manifest) Remaining() []*Item {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*Item
+	for _, it := range m.Items {
+		if it.Status != StatusCompleted {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+// Counts returns the number of items in each status.
+func (m *Manifest) Counts() map[Status]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := map[Status]int{}
+	for _, it := range m.Items {
+		counts[it.Status]++
+	}
+	return counts
+}
+
+// Update applies fn to item and saves the manifest.
+func (m *Manifest) Update(item *Item, fn func(*Item)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(item)
+	return m.save()
+}
+
+// SetCorrelation records the correlation check and saves the manifest.
+func (m *Manifest) SetCorrelation(c *models.CorrelationMatrix) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Correlation = c
+	return m.save()
+}
+
+// save writes the manifest atomically so a crash never leaves a torn file.
+// Callers must hold m.mu (or own m exclusively).
+func (m *Manifest) save() error {
+	m.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, m.path)
+}
@@ -0,0 +1,154 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// Result is what a single analysis reports back to the batch.
+type Result struct {
+	Recommendation string
+	Confidence     float64
+}
+
+// AnalyzeFunc runs the analysis for one symbol.
+type AnalyzeFunc func(ctx context.Context, symbol string) (Result, error)
+
+// Options controls how Run schedules work.
+type Options struct {
+	// Limiter bounds concurrency; nil runs one symbol at a time.
+	Limiter *Limiter
+	// Classify maps an analysis error to an outcome for the limiter. By
+	// default rate limit errors are OutcomeThrottled and others OutcomeError.
+	Classify func(error) Outcome
+	// Probe returns cumulative data source counters. Rate limits or a high
+	// error rate seen between two completions are reported to the limiter
+	// even when the analysis itself succeeded.
+	Probe func() dataflows.RequestStats
+	// OnDone is called after an item finishes.
+	OnDone func(*Item)
+}
+
+// DefaultClassify treats provider rate limits as throttling and any other
+// error as a failure.
+func DefaultClassify(err error) Outcome {
+	switch {
+	case err == nil:
+		return OutcomeOK
+	case dataflows.IsRateLimited(err):
+		return OutcomeThrottled
+	}
+	return OutcomeError
+}
+
+// Run analyzes every remaining item of m, checkpointing after each state
+// change. When ctx is cancelled no new items are started and items interrupted
+// mid-run go back to pending; Run then returns ctx.Err().
+func Run(ctx context.Context, m *Manifest, analyze AnalyzeFunc, opts Options) error {
+	lim := opts.Limiter
+	if lim == nil {
+		lim = NewLimiter(1, 1, false)
+	}
+	classify := opts.Classify
+	if classify == nil {
+		classify = DefaultClassify
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		saveErr error
+		last    dataflows.RequestStats
+	)
+	if opts.Probe != nil {
+		last = opts.Probe()
+	}
+	record := func(err error) {
+		if err != nil {
+			mu.Lock()
+			if saveErr == nil {
+				saveErr = err
+			}
+			mu.Unlock()
+		}
+	}
+	// outcome combines the analysis result with data source health since the
+	// previous completion.
+	outcome := func(err error) Outcome {
+		o := classify(err)
+		if opts.Probe == nil || o == OutcomeThrottled {
+			return o
+		}
+		mu.Lock()
+		now := opts.Probe()
+		delta := dataflows.RequestStats{
+			Requests:  now.Requests - last.Requests,
+			Errors:    now.Errors - last.Errors,
+			Throttled: now.Throttled - last.Throttled,
+		}
+		last = now
+		mu.Unlock()
+		switch {
+		case delta.Throttled > 0:
+			return OutcomeThrottled
+		case delta.Requests >= errorWindow && float64(delta.Errors)/float64(delta.Requests) > maxErrorRate:
+			return OutcomeError
+		}
+		return o
+	}
+
+	for _, item := range m.Remaining() {
+		if err := lim.Acquire(ctx); err != nil {
+			break
+		}
+		wg.Add(1)
+		go func(item *Item) {
+			defer wg.Done()
+			started := time.Now()
+			record(m.Update(item, func(it *Item) {
+				it.Status = StatusRunning
+				it.Attempts++
+				it.Error = ""
+				it.StartedAt = &started
+				it.FinishedAt = nil
+			}))
+
+			res, err := analyze(ctx, item.Symbol)
+			interrupted := err != nil && ctx.Err() != nil
+			finished := time.Now()
+			record(m.Update(item, func(it *Item) {
+				switch {
+				case interrupted:
+					it.Status = StatusPending
+					it.StartedAt = nil
+					return
+				case err != nil:
+					it.Status = StatusFailed
+					it.Error = err.Error()
+				default:
+					it.Status = StatusCompleted
+					it.Recommendation = res.Recommendation
+					it.Confidence = res.Confidence
+				}
+				it.FinishedAt = &finished
+			}))
+			if interrupted {
+				lim.Release(OutcomeOK)
+				return
+			}
+			lim.Release(outcome(err))
+			if opts.OnDone != nil {
+				opts.OnDone(item)
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return saveErr
+}
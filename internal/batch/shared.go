@@ -0,0 +1,37 @@
+package batch
+
+import (
+	"context"
+	"log"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/regime"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// Shared returns the session the analyses of m share market-wide data
+// through: the market regime (indices, volatility, sectors, breadth and
+// rates) of every market in the batch is loaded up front, and outputs of
+// the market-wide tools are reused across symbols. Attach it to the
+// context analyses run under with dataflows.WithSession.
+func Shared(ctx context.Context, cfg *config.Config, m *Manifest) *dataflows.Session {
+	s := dataflows.NewSession(tools.MarketWideTools...)
+	if cfg.SkipMarketContext {
+		return s
+	}
+	ctx = dataflows.WithSession(ctx, s)
+	seen := map[string]bool{}
+	for _, it := range m.Remaining() {
+		market := regime.MarketOf(it.Symbol)
+		if seen[market] {
+			continue
+		}
+		seen[market] = true
+		// a failure is not kept; the first analysis of the market retries
+		if _, err := regime.Load(ctx, cfg, it.Symbol, m.TradeDate); err != nil {
+			log.Printf("batch %s: market context for %s: %v", m.ID, market, err)
+		}
+	}
+	return s
+}
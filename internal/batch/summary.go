@@ -0,0 +1,167 @@
+package batch
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dyike/CortexGo/internal/portfolio"
+	"github.com/dyike/CortexGo/pkg/utils"
+)
+
+// Row is one line of the consolidated batch report.
+type Row struct {
+	Rank           int     `json:"rank"`
+	Symbol         string  `json:"symbol"`
+	Status         Status  `json:"status"`
+	Recommendation string  `json:"recommendation"`
+	Confidence     float64 `json:"confidence"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// recommendationOrder ranks BUY ideas first and symbols without a decision last.
+var recommendationOrder = map[string]int{"BUY": 0, "HOLD": 1, "SELL": 2}
+
+func recommendationRank(r Row) int {
+	if r.Status != StatusCompleted {
+		return len(recommendationOrder) + 1
+	}
+	if rank, ok := recommendationOrder[strings.ToUpper(r.Recommendation)]; ok {
+		return rank
+	}
+	return len(recommendationOrder)
+}
+
+// Summarize ranks the batch by recommendation (BUY, HOLD, SELL, then
+// undecided and unfinished symbols) and by confidence within each group.
+func Summarize(m *Manifest) []Row {
+	m.mu.Lock()
+	rows := make([]Row, 0, len(m.Items))
+	for _, it := range m.Items {
+		rows = append(rows, Row{
+			Symbol:         it.Symbol,
+			Status:         it.Status,
+			Recommendation: it.Recommendation,
+			Confidence:     it.Confidence,
+			Error:          it.Error,
+		})
+	}
+	m.mu.Unlock()
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		ri, rj := recommendationRank(rows[i]), recommendationRank(rows[j])
+		if ri != rj {
+			return ri < rj
+		}
+		if rows[i].Confidence != rows[j].Confidence {
+			return rows[i].Confidence > rows[j].Confidence
+		}
+		return rows[i].Symbol < rows[j].Symbol
+	})
+	for i := range rows {
+		rows[i].Rank = i + 1
+	}
+	return rows
+}
+
+// WriteCSV writes rows with a header line.
+func WriteCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"rank", "symbol", "status", "recommendation", "confidence", "error"})
+	for _, r := range rows {
+		_ = cw.Write([]string{
+			strconv.Itoa(r.Rank),
+			r.Symbol,
+			string(r.Status),
+			r.Recommendation,
+			formatConfidence(r.Confidence),
+			firstLine(r.Error),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Markdown renders the consolidated report: a recommendation tally followed by
+// the ranked table and, when checked, the correlation across the symbols.
+func Markdown(m *Manifest, rows []Row) string {
+	tally := map[string]int{}
+	for _, r := range rows {
+		key := strings.ToUpper(r.Recommendation)
+		if r.Status != StatusCompleted {
+			key = strings.ToUpper(string(r.Status))
+		} else if key == "" {
+			key = "N/A"
+		}
+		tally[key]++
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Batch %s\n\n", m.ID)
+	fmt.Fprintf(&b, "- Trade date: %s\n", m.TradeDate)
+	if m.Depth != "" {
+		fmt.Fprintf(&b, "- Depth: %s\n", m.Depth)
+	}
+	fmt.Fprintf(&b, "- Symbols: %d\n", len(rows))
+	for _, key := range []string{"BUY", "HOLD", "SELL", "N/A", "FAILED", "PENDING"} {
+		if tally[key] > 0 {
+			fmt.Fprintf(&b, "- %s: %d\n", key, tally[key])
+		}
+	}
+	b.WriteString("\n| Rank | Symbol | Recommendation | Confidence | Status |\n")
+	b.WriteString("|---:|---|---|---:|---|\n")
+	for _, r := range rows {
+		status := string(r.Status)
+		if r.Error != "" {
+			status += ": " + firstLine(r.Error)
+		}
+		fmt.Fprintf(&b, "| %d | %s | %s | %s | %s |\n",
+			r.Rank, r.Symbol, utils.OrDash(r.Recommendation), utils.OrDash(formatConfidence(r.Confidence)), strings.ReplaceAll(status, "|", "\\|"))
+	}
+	if m.Correlation != nil {
+		b.WriteString("\n## Correlation\n\n")
+		b.WriteString(portfolio.RenderCorrelation(m.Correlation))
+	}
+	return b.String()
+}
+
+// WriteReport writes summary.md and summary.csv for the batch under
+// <resultsDir>/batches/<id>/ and returns that directory.
+func WriteReport(resultsDir string, m *Manifest) (string, error) {
+	rows := Summarize(m)
+	dir := filepath.Join(resultsDir, "batches", m.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "summary.md"), []byte(Markdown(m, rows)), 0644); err != nil {
+		return "", err
+	}
+	f, err := os.Create(filepath.Join(dir, "summary.csv"))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := WriteCSV(f, rows); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func formatConfidence(c float64) string {
+	if c == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(c, 'f', 2, 64)
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,40 @@
+// Package buildinfo reports which build of CortexGo is running: the release
+// version and the git state the binary was built from. Runs record it so a
+// result can be traced back to the code that produced it.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// Version is the library version; release builds set it with
+// -ldflags "-X github.com/dyike/CortexGo/internal/buildinfo.Version=x.y.z".
+var Version = "1.0.0"
+
+// Get returns the version, the git revision, commit time and dirty flag
+// recorded by the Go toolchain (absent when built outside a checkout), and
+// the Go runtime.
+func Get() models.SystemVersion {
+	v := models.SystemVersion{
+		Version:   Version,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				v.Commit = s.Value
+			case "vcs.time":
+				v.BuildTime = s.Value
+			case "vcs.modified":
+				v.Modified = s.Value == "true"
+			}
+		}
+	}
+	return v
+}
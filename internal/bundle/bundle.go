@@ -0,0 +1,321 @@
+// Package bundle writes the raw tool results of one analysis run to a zip data
+// bundle, so the inputs behind a report can be audited later or loaded into a
+// notebook. It is only populated when the run sets export_tool_data.
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rundir"
+	"github.com/dyike/CortexGo/models"
+)
+
+// Manifest describes the run a bundle belongs to; it is stored as manifest.json.
+type Manifest struct {
+	Symbol      string            `json:"symbol"`
+	TradeDate   string            `json:"trade_date"`
+	GeneratedAt time.Time         `json:"generated_at"`
+	ToolCalls   int               `json:"tool_calls"`
+	RunInputs   *models.RunInputs `json:"run_inputs,omitempty"`
+	Files       []string          `json:"files"`
+}
+
+// call is one tool result as stored under calls/.
+type call struct {
+	ID         string             `json:"id"`
+	Agent      string             `json:"agent"`
+	Tool       string             `json:"tool"`
+	Arguments  json.RawMessage    `json:"arguments,omitempty"`
+	Output     json.RawMessage    `json:"output"`
+	Provenance *models.Provenance `json:"provenance,omitempty"`
+	Digest     string             `json:"digest,omitempty"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// table is a grid extracted from a tool output, header first.
+type table [][]string
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// Write saves the tool results in state to tools/tool_data.zip in the run's
+// directory, in the results area of cfg's storage, encrypted when an
+// encryption key is configured, and returns where it went
+// (see rundir.Location). A state without a run ID goes to
+// <symbol>/<trade date>/tool_data_<run>.zip instead, where run names the
+// bundle, e.g. a session ID, and empty uses the current time. It returns ""
+// without writing anything when the run recorded no tool results.
+func Write(ctx context.Context, cfg *config.Config, state *models.TradingState, run string) (string, error) {
+	if state == nil || len(state.ToolResults) == 0 {
+		return "", nil
+	}
+	var key string
+	if state.RunID != "" {
+		key = rundir.Key(state.CompanyOfInterest, state.TradeDate, state.RunID, rundir.ToolsDir, "tool_data.zip")
+	} else {
+		if run == "" {
+			run = time.Now().Format("20060102_150405")
+		}
+		key = state.CompanyOfInterest + "/" + state.TradeDate + "/tool_data_" + unsafeName.ReplaceAllString(run, "_") + ".zip"
+	}
+	var buf bytes.Buffer
+	if err := writeZip(&buf, state); err != nil {
+		return "", err
+	}
+	// sealed like the rest of the run directory when encryption_key is set
+	if err := rundir.Put(ctx, cfg, key, buf.Bytes()); err != nil {
+		return "", fmt.Errorf("write bundle: %w", err)
+	}
+	return rundir.Location(cfg, key), nil
+}
+
+func writeZip(w io.Writer, state *models.TradingState) error {
+	evidence := make(map[string]*models.Evidence, len(state.Evidence))
+	for _, e := range state.Evidence {
+		evidence[e.ID] = e
+	}
+
+	zw := zip.NewWriter(w)
+	m := Manifest{
+		Symbol:      state.CompanyOfInterest,
+		TradeDate:   state.TradeDate,
+		GeneratedAt: time.Now(),
+		ToolCalls:   len(state.ToolResults),
+		RunInputs:   state.RunInputs,
+	}
+	index := table{{"id", "agent", "tool", "source", "mode", "as_of", "digest", "bytes", "created_at", "arguments", "file", "tables"}}
+	for _, r := range state.ToolResults {
+		e := evidence[r.EvidenceID]
+		if e == nil {
+			e = &models.Evidence{ID: r.EvidenceID}
+		}
+		base := e.ID + "_" + unsafeName.ReplaceAllString(e.Tool, "_")
+		name := "calls/" + base + ".json"
+		data, err := json.MarshalIndent(call{
+			ID:         e.ID,
+			Agent:      e.Agent,
+			Tool:       e.Tool,
+			Arguments:  rawJSON(r.Arguments),
+			Output:     rawJSON(r.Output),
+			Provenance: e.Provenance,
+			Digest:     e.Digest,
+			CreatedAt:  e.CreatedAt,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", name, err)
+		}
+		if err := add(zw, name, data); err != nil {
+			return err
+		}
+		m.Files = append(m.Files, name)
+
+		var tableFiles []string
+		tables := extractTables(r.Output)
+		for i, t := range tables {
+			tname := "tables/" + base + ".csv"
+			if len(tables) > 1 {
+				tname = fmt.Sprintf("tables/%s_%d.csv", base, i+1)
+			}
+			if err := addCSV(zw, tname, t); err != nil {
+				return err
+			}
+			tableFiles = append(tableFiles, tname)
+		}
+		m.Files = append(m.Files, tableFiles...)
+
+		var source, mode, asOf string
+		if p := e.Provenance; p != nil {
+			source, mode, asOf = p.Source, p.Mode, p.AsOf
+		}
+		created := ""
+		if !e.CreatedAt.IsZero() {
+			created = e.CreatedAt.Format(time.RFC3339)
+		}
+		index = append(index, []string{e.ID, e.Agent, e.Tool, source, mode, asOf, e.Digest, fmt.Sprint(len(r.Output)), created, r.Arguments, name, strings.Join(tableFiles, " ")})
+	}
+	if err := addCSV(zw, "index.csv", index); err != nil {
+		return err
+	}
+	m.Files = append([]string{"index.csv"}, m.Files...)
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := add(zw, "manifest.json", data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("close bundle: %w", err)
+	}
+	return nil
+}
+
+// rawJSON keeps s as-is when it is JSON, so structured outputs stay queryable,
+// and stores it as a JSON string otherwise.
+func rawJSON(s string) json.RawMessage {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	data, _ := json.Marshal(s)
+	return data
+}
+
+func add(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func addCSV(zw *zip.Writer, name string, t table) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(t); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// extractTables finds the tabular data in a tool output: arrays of JSON
+// objects (top-level or one level down, e.g. the bars of get_market_data) and
+// markdown pipe tables. Each is returned header first.
+func extractTables(output string) []table {
+	trimmed := strings.TrimSpace(output)
+	if json.Valid([]byte(trimmed)) {
+		return jsonTables(trimmed)
+	}
+	return markdownTables(output)
+}
+
+func jsonTables(s string) []table {
+	var top any
+	if err := json.Unmarshal([]byte(s), &top); err != nil {
+		return nil
+	}
+	if t := objectTable(top); t != nil {
+		return []table{t}
+	}
+	obj, ok := top.(map[string]any)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var tables []table
+	for _, k := range keys {
+		if t := objectTable(obj[k]); t != nil {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+// objectTable turns an array of JSON objects into rows; columns are the
+// union of their keys, sorted. It returns nil for anything else.
+func objectTable(v any) table {
+	items, ok := v.([]any)
+	if !ok || len(items) == 0 {
+		return nil
+	}
+	seen := map[string]bool{}
+	var columns []string
+	for _, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for k := range obj {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+	}
+	sort.Strings(columns)
+	t := table{columns}
+	for _, item := range items {
+		obj := item.(map[string]any)
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = cell(obj[c])
+		}
+		t = append(t, row)
+	}
+	return t
+}
+
+func cell(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
+
+var separatorRow = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+
+// markdownTables parses pipe tables: a header row, a --- separator row and
+// the rows that follow until the first line that is not part of the table.
+func markdownTables(s string) []table {
+	lines := strings.Split(s, "\n")
+	var tables []table
+	for i := 0; i+1 < len(lines); i++ {
+		header := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(header, "|") || !separatorRow.MatchString(strings.TrimSpace(lines[i+1])) {
+			continue
+		}
+		t := table{pipeCells(header)}
+		j := i + 2
+		for ; j < len(lines); j++ {
+			row := strings.TrimSpace(lines[j])
+			if !strings.HasPrefix(row, "|") {
+				break
+			}
+			t = append(t, pipeCells(row))
+		}
+		tables = append(tables, t)
+		i = j - 1
+	}
+	return tables
+}
+
+func pipeCells(row string) []string {
+	row = strings.TrimSuffix(strings.TrimPrefix(row, "|"), "|")
+	cells := strings.Split(row, "|")
+	for i, c := range cells {
+		cells[i] = strings.TrimSpace(c)
+	}
+	return cells
+}
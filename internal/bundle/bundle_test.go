@@ -0,0 +1,155 @@
+package bundle
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rundir"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/secure"
+)
+
+func TestWriteBundle(t *testing.T) {
+	state := &models.TradingState{
+		CompanyOfInterest: "AAPL.US",
+		TradeDate:         "2025-06-02",
+		Evidence: []*models.Evidence{
+			{ID: "E1", Agent: "Market Analyst", Tool: "get_market_data", Provenance: &models.Provenance{Source: "longport", Mode: models.ProvenanceCache, AsOf: "2025-05-30"}},
+			{ID: "E2", Agent: "Fundamentals Analyst", Tool: "get_insider_sentiment"},
+			{ID: "E3", Agent: "News Analyst", Tool: "get_google_news"},
+		},
+		ToolResults: []*models.ToolResult{
+			{EvidenceID: "E1", Arguments: `{"symbol":"AAPL.US"}`, Output: `{"data":[{"date":"2025-05-29","close":199.95,"volume":51396800},{"date":"2025-05-30","close":200.85}]}`},
+			{EvidenceID: "E2", Arguments: `{"symbol":"AAPL.US"}`, Output: "# Insider Sentiment\n\n| Month | MSPR | Net shares |\n|---|---|---|\n| 2025-05 | -12.5 | -4000 |\n| 2025-04 | +3.0 | +100 |\n\nnotes"},
+			{EvidenceID: "E3", Output: "Apple unveils new products"},
+		},
+	}
+	dir := t.TempDir()
+	cfg := &config.Config{ResultsDir: dir}
+	path, err := Write(context.Background(), cfg, state, "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "AAPL.US", "2025-06-02", "tool_data_42.zip"); path != want {
+		t.Fatalf("path = %s, want %s", path, want)
+	}
+
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name], _ = io.ReadAll(rc)
+		rc.Close()
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(files["manifest.json"], &m); err != nil {
+		t.Fatal(err)
+	}
+	wantFiles := []string{
+		"index.csv",
+		"calls/E1_get_market_data.json", "tables/E1_get_market_data.csv",
+		"calls/E2_get_insider_sentiment.json", "tables/E2_get_insider_sentiment.csv",
+		"calls/E3_get_google_news.json",
+	}
+	if m.ToolCalls != 3 || !reflect.DeepEqual(m.Files, wantFiles) {
+		t.Fatalf("manifest = %+v", m)
+	}
+
+	var c call
+	if err := json.Unmarshal(files["calls/E1_get_market_data.json"], &c); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(c.Output, &out); err != nil || len(out.Data) != 2 {
+		t.Fatalf("JSON output not kept as JSON: %s", c.Output)
+	}
+	if err := json.Unmarshal(files["calls/E3_get_google_news.json"], &c); err != nil {
+		t.Fatal(err)
+	}
+	var text string
+	if err := json.Unmarshal(c.Output, &text); err != nil || text != "Apple unveils new products" {
+		t.Fatalf("text output = %s", c.Output)
+	}
+
+	checkCSV(t, files["tables/E1_get_market_data.csv"], [][]string{
+		{"close", "date", "volume"},
+		{"199.95", "2025-05-29", "51396800"},
+		{"200.85", "2025-05-30", ""},
+	})
+	checkCSV(t, files["tables/E2_get_insider_sentiment.csv"], [][]string{
+		{"Month", "MSPR", "Net shares"},
+		{"2025-05", "-12.5", "-4000"},
+		{"2025-04", "+3.0", "+100"},
+	})
+	index, _ := csv.NewReader(bytes.NewReader(files["index.csv"])).ReadAll()
+	if len(index) != 4 || index[1][3] != "longport" || index[1][5] != "2025-05-30" || index[2][11] != "tables/E2_get_insider_sentiment.csv" {
+		t.Fatalf("index = %v", index)
+	}
+}
+
+func TestWriteWithoutToolResults(t *testing.T) {
+	path, err := Write(context.Background(), &config.Config{ResultsDir: t.TempDir()}, &models.TradingState{CompanyOfInterest: "AAPL.US"}, "")
+	if err != nil || path != "" {
+		t.Fatalf("Write = %q, %v; want no bundle", path, err)
+	}
+}
+
+func TestWriteEncryptsTheBundle(t *testing.T) {
+	key, err := secure.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	cfg := &config.Config{ResultsDir: dir, EncryptionKey: key}
+	state := &models.TradingState{
+		CompanyOfInterest: "AAPL.US",
+		TradeDate:         "2025-06-02",
+		RunID:             "20250602-093000-abcdef",
+		ToolResults:       []*models.ToolResult{{EvidenceID: "E1", Output: "{}"}},
+	}
+	path, err := Write(context.Background(), cfg, state, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil || !secure.IsSealed(raw) {
+		t.Fatalf("bundle stored in plaintext: %v", err)
+	}
+	data, err := rundir.Get(context.Background(), cfg, rundir.Key("AAPL.US", "2025-06-02", state.RunID, rundir.ToolsDir, "tool_data.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := zip.NewReader(bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatalf("opened bundle is not a zip: %v", err)
+	}
+}
+
+func checkCSV(t *testing.T, data []byte, want [][]string) {
+	t.Helper()
+	got, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("csv = %v, want %v", got, want)
+	}
+}
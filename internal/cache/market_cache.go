@@ -37,6 +37,12 @@ func GetMarketDataCache() *MarketDataCache {
 }
 
 func (c *MarketDataCache) Get(ctx context.Context, symbol string, count int) ([]*models.MarketData, bool) {
+	data, _, ok := c.Lookup(ctx, symbol, count)
+	return data, ok
+}
+
+// Lookup 同 Get，另返回缓存数据的获取时间
+func (c *MarketDataCache) Lookup(ctx context.Context, symbol string, count int) ([]*models.MarketData, time.Time, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -46,7 +52,7 @@ func (c *MarketDataCache) Get(ctx context.Context, symbol string, count int) ([]
 	if cached, exists := c.memoryCache[key]; exists {
 		if time.Since(cached.Timestamp) <= cached.TTL {
 			log.Printf("Using memory cache for %s (count: %d)", symbol, count)
-			return cached.Data, true
+			return cached.Data, cached.Timestamp, true
 		}
 		// 内存缓存过期，删除
 		delete(c.memoryCache, key)
@@ -68,7 +74,7 @@ func (c *MarketDataCache) Get(ctx context.Context, symbol string, count int) ([]
 					TTL:       5 * time.Minute,
 				}
 
-				return data[:min(count, len(data))], true
+				return data[:min(count, len(data))], fileTime, true
 			} else if time.Since(fileTime) > 30*time.Minute {
 				log.Printf("CSV cache expired for %s (age: %v)", symbol, time.Since(fileTime))
 			} else if len(data) < count {
@@ -79,7 +85,35 @@ func (c *MarketDataCache) Get(ctx context.Context, symbol string, count int) ([]
 		}
 	}
 
-	return nil, false
+	return nil, time.Time{}, false
+}
+
+// GetArchived 离线模式使用：忽略 TTL，读取记录数足够的最新 CSV 归档，并返回归档写入时间
+func (c *MarketDataCache) GetArchived(symbol string, count int) ([]*models.MarketData, time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	csvFile, err := c.csvManager.FindLatestCSV(symbol, count)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	data, fileTime, err := c.csvManager.ReadMarketDataFromCSV(csvFile)
+	if err != nil || len(data) == 0 {
+		return nil, time.Time{}, false
+	}
+	log.Printf("Using archived market data for %s (count: %d) from file: %s", symbol, count, filepath.Base(csvFile))
+	return data[:min(count, len(data))], fileTime, true
+}
+
+// HasArchive 是否存在该标的的任意 CSV 归档
+func (c *MarketDataCache) HasArchive(symbol string) bool {
+	_, err := c.csvManager.FindLatestCSV(symbol, 1)
+	return err == nil
+}
+
+// ArchiveDir 标的 CSV 归档所在目录
+func (c *MarketDataCache) ArchiveDir(symbol string) string {
+	return filepath.Join(c.basePath, "csv", "market", symbol)
 }
 
 func (c *MarketDataCache) Set(ctx context.Context, symbol string, count int, data []*models.MarketData) {
@@ -123,11 +157,6 @@ func (c *MarketDataCache) Clear() {
 	log.Printf("Cleared memory cache")
 }
 
-// SaveIndicatorsToCSV 保存技术指标到CSV
-func (c *MarketDataCache) SaveIndicatorsToCSV(symbol string, indicators map[string][]models.IndicatorValue) error {
-	return c.csvManager.WriteIndicatorResultToCSV(symbol, indicators)
-}
-
 // CleanExpiredFiles 清理过期文件
 func (c *MarketDataCache) CleanExpiredFiles(maxAge time.Duration) error {
 	return c.csvManager.CleanOldCSVFiles(maxAge)
@@ -0,0 +1,269 @@
+// Package calibration compares the confidence agents state with how often
+// their calls turn out right, and maps stated confidences onto realized
+// accuracy with Platt scaling or isotonic regression.
+package calibration
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// Sample thresholds: below MinSamples no mapping is fitted; isotonic
+// regression needs more data than Platt's two parameters before it stops
+// overfitting.
+const (
+	MinSamples         = 20
+	IsotonicMinSamples = 50
+)
+
+// OverconfidenceMargin is the smallest gap between mean stated confidence and
+// hit rate that flags an agent as overconfident.
+const OverconfidenceMargin = 0.1
+
+// Methods reported in models.AgentCalibration.
+const (
+	MethodNone     = "none"
+	MethodPlatt    = "platt"
+	MethodIsotonic = "isotonic"
+)
+
+// binCount is the number of equal-width bins in the reliability curve.
+const binCount = 5
+
+// Sample is one stated confidence and whether the call was right.
+type Sample struct {
+	Confidence float64
+	Correct    bool
+}
+
+// Mapper turns a stated confidence into a calibrated probability.
+type Mapper interface {
+	Map(confidence float64) float64
+}
+
+// Fit picks the calibration method the sample size supports: isotonic
+// regression for large histories, Platt scaling for moderate ones, and none
+// (nil mapper) when there is too little data to trust either.
+func Fit(samples []Sample) (Mapper, string) {
+	switch {
+	case len(samples) >= IsotonicMinSamples:
+		return FitIsotonic(samples), MethodIsotonic
+	case len(samples) >= MinSamples:
+		return FitPlatt(samples), MethodPlatt
+	}
+	return nil, MethodNone
+}
+
+// Platt is a logistic map on the log-odds of the stated confidence:
+// p = sigmoid(A*logit(c) + B). A=1, B=0 is the identity.
+type Platt struct {
+	A, B float64
+}
+
+// plattPrior is the weight of an L2 pull towards the identity map. It keeps the
+// fit defined when every sample states the same confidence, where only
+// A*logit(c)+B is identifiable.
+const plattPrior = 1e-3
+
+// FitPlatt fits A and B by damped Newton steps on the log loss, using Platt's
+// smoothed targets so a perfect record does not push p to exactly 0 or 1.
+func FitPlatt(samples []Sample) Platt {
+	var pos, neg float64
+	for _, s := range samples {
+		if s.Correct {
+			pos++
+		} else {
+			neg++
+		}
+	}
+	hi, lo := (pos+1)/(pos+2), 1/(neg+2)
+	target := func(s Sample) float64 {
+		if s.Correct {
+			return hi
+		}
+		return lo
+	}
+	loss := func(p Platt) float64 {
+		l := plattPrior * ((p.A-1)*(p.A-1) + p.B*p.B)
+		for _, s := range samples {
+			q := math.Min(math.Max(sigmoid(p.A*logit(s.Confidence)+p.B), 1e-12), 1-1e-12)
+			t := target(s)
+			l -= t*math.Log(q) + (1-t)*math.Log(1-q)
+		}
+		return l
+	}
+
+	p := Platt{A: 1}
+	cur := loss(p)
+	for iter := 0; iter < 100; iter++ {
+		ga, gb := 2*plattPrior*(p.A-1), 2*plattPrior*p.B
+		haa, hab, hbb := 2*plattPrior, 0.0, 2*plattPrior
+		for _, s := range samples {
+			f := logit(s.Confidence)
+			q := sigmoid(p.A*f + p.B)
+			w := q * (1 - q)
+			ga += (q - target(s)) * f
+			gb += q - target(s)
+			haa += w * f * f
+			hab += w * f
+			hbb += w
+		}
+		det := haa*hbb - hab*hab
+		if det <= 0 {
+			break
+		}
+		da := (hbb*ga - hab*gb) / det
+		db := (haa*gb - hab*ga) / det
+		// halve the step until the loss stops increasing
+		step := 1.0
+		next := Platt{A: p.A - da, B: p.B - db}
+		nextLoss := loss(next)
+		for nextLoss > cur && step > 1e-6 {
+			step /= 2
+			next = Platt{A: p.A - step*da, B: p.B - step*db}
+			nextLoss = loss(next)
+		}
+		if nextLoss > cur {
+			break
+		}
+		converged := cur-nextLoss < 1e-10
+		p, cur = next, nextLoss
+		if converged {
+			break
+		}
+	}
+	return p
+}
+
+// Map implements Mapper.
+func (p Platt) Map(confidence float64) float64 {
+	return clamp(sigmoid(p.A*logit(confidence) + p.B))
+}
+
+// Isotonic is a monotone step function through the pooled blocks found by
+// pool-adjacent-violators, interpolated linearly between block centres.
+type Isotonic struct {
+	X, Y []float64
+}
+
+// FitIsotonic fits a non-decreasing map from confidence to hit rate.
+func FitIsotonic(samples []Sample) Isotonic {
+	sorted := append([]Sample(nil), samples...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Confidence < sorted[j].Confidence })
+
+	type block struct{ sumX, sumY, n float64 }
+	var blocks []block
+	for _, s := range sorted {
+		y := 0.0
+		if s.Correct {
+			y = 1
+		}
+		// equal confidences are one observation of the same x
+		if k := len(blocks) - 1; k >= 0 && blocks[k].sumX/blocks[k].n == s.Confidence {
+			blocks[k].sumX += s.Confidence
+			blocks[k].sumY += y
+			blocks[k].n++
+		} else {
+			blocks = append(blocks, block{s.Confidence, y, 1})
+		}
+		for len(blocks) > 1 {
+			k := len(blocks) - 1
+			if blocks[k-1].sumY/blocks[k-1].n <= blocks[k].sumY/blocks[k].n {
+				break
+			}
+			blocks[k-1].sumX += blocks[k].sumX
+			blocks[k-1].sumY += blocks[k].sumY
+			blocks[k-1].n += blocks[k].n
+			blocks = blocks[:k]
+		}
+	}
+	iso := Isotonic{X: make([]float64, len(blocks)), Y: make([]float64, len(blocks))}
+	for i, b := range blocks {
+		iso.X[i] = b.sumX / b.n
+		iso.Y[i] = b.sumY / b.n
+	}
+	return iso
+}
+
+// Map implements Mapper.
+func (iso Isotonic) Map(confidence float64) float64 {
+	n := len(iso.X)
+	switch {
+	case n == 0:
+		return clamp(confidence)
+	case confidence <= iso.X[0]:
+		return clamp(iso.Y[0])
+	case confidence >= iso.X[n-1]:
+		return clamp(iso.Y[n-1])
+	}
+	i := sort.SearchFloat64s(iso.X, confidence)
+	x0, x1, y0, y1 := iso.X[i-1], iso.X[i], iso.Y[i-1], iso.Y[i]
+	return clamp(y0 + (y1-y0)*(confidence-x0)/(x1-x0))
+}
+
+// Assess summarizes how well an agent's stated confidence matches its record.
+// An agent is overconfident when, with enough samples, its mean confidence
+// exceeds its hit rate by at least OverconfidenceMargin and by more than two
+// standard errors of the hit rate.
+func Assess(agent string, samples []Sample) models.AgentCalibration {
+	out := models.AgentCalibration{Agent: agent, Samples: len(samples), Method: MethodNone}
+	if len(samples) == 0 {
+		return out
+	}
+	n := float64(len(samples))
+	var sumConf, hits, brier float64
+	bins := make([]models.CalibrationBin, binCount)
+	for i := range bins {
+		bins[i].Lower = float64(i) / binCount
+		bins[i].Upper = float64(i+1) / binCount
+	}
+	for _, s := range samples {
+		y := 0.0
+		if s.Correct {
+			y = 1
+		}
+		sumConf += s.Confidence
+		hits += y
+		brier += (s.Confidence - y) * (s.Confidence - y)
+
+		b := &bins[min(int(s.Confidence*binCount), binCount-1)]
+		b.Count++
+		b.MeanConfidence += s.Confidence
+		b.HitRate += y
+	}
+	out.MeanConfidence = sumConf / n
+	out.HitRate = hits / n
+	out.Gap = out.MeanConfidence - out.HitRate
+	out.Brier = brier / n
+	_, out.Method = Fit(samples)
+
+	stderr := math.Sqrt(out.HitRate * (1 - out.HitRate) / n)
+	out.Overconfident = len(samples) >= MinSamples && out.Gap >= OverconfidenceMargin && out.Gap > 2*stderr
+
+	for _, b := range bins {
+		if b.Count == 0 {
+			continue
+		}
+		b.MeanConfidence /= float64(b.Count)
+		b.HitRate /= float64(b.Count)
+		out.Bins = append(out.Bins, b)
+	}
+	return out
+}
+
+func sigmoid(x float64) float64 {
+	return 1 / (1 + math.Exp(-x))
+}
+
+func logit(p float64) float64 {
+	p = clamp(p)
+	return math.Log(p / (1 - p))
+}
+
+// clamp keeps probabilities away from 0 and 1, which would read as certainty
+// (and as "no calibration" for a zero).
+func clamp(p float64) float64 {
+	return math.Min(math.Max(p, 0.01), 0.99)
+}
@@ -0,0 +1,135 @@
+package calibration
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+// overconfident states 0.9 on every call but is right 55% of the time.
+func overconfident(n int) []Sample {
+	samples := make([]Sample, n)
+	for i := range samples {
+		samples[i] = Sample{Confidence: 0.9, Correct: i%20 < 11}
+	}
+	return samples
+}
+
+func TestFitMapsOntoRealizedAccuracy(t *testing.T) {
+	if m, method := Fit(overconfident(MinSamples - 1)); m != nil || method != MethodNone {
+		t.Fatalf("too few samples fitted %s", method)
+	}
+
+	platt, method := Fit(overconfident(40))
+	if method != MethodPlatt {
+		t.Fatalf("method = %s, want platt", method)
+	}
+	if got := platt.Map(0.9); math.Abs(got-0.55) > 0.03 {
+		t.Errorf("platt 0.9 -> %.3f, want about 0.55", got)
+	}
+
+	// a well-ranked but overconfident agent: hit rate rises with confidence
+	var samples []Sample
+	for i := 0; i < 60; i++ {
+		conf := 0.6 + 0.1*float64(i%4)
+		samples = append(samples, Sample{Confidence: conf, Correct: i%10 < 3+i%4})
+	}
+	iso, method := Fit(samples)
+	if method != MethodIsotonic {
+		t.Fatalf("method = %s, want isotonic", method)
+	}
+	prev := 0.0
+	for c := 0.5; c <= 1.0; c += 0.05 {
+		got := iso.Map(c)
+		if got < prev-1e-12 {
+			t.Fatalf("isotonic map not monotone at %.2f: %.3f < %.3f", c, got, prev)
+		}
+		prev = got
+	}
+	if got := iso.Map(0.9); got >= 0.9 {
+		t.Errorf("isotonic 0.9 -> %.3f, want below the stated confidence", got)
+	}
+}
+
+func TestAssessFlagsOverconfidence(t *testing.T) {
+	a := Assess("risk_judge", overconfident(40))
+	if !a.Overconfident || a.Method != MethodPlatt || math.Abs(a.Gap-0.35) > 1e-9 {
+		t.Errorf("assessment = %+v", a)
+	}
+	if len(a.Bins) != 1 || a.Bins[0].Lower != 0.8 || a.Bins[0].Count != 40 {
+		t.Errorf("bins = %+v", a.Bins)
+	}
+
+	honest := make([]Sample, 40)
+	for i := range honest {
+		honest[i] = Sample{Confidence: 0.6, Correct: i%5 < 3}
+	}
+	if a := Assess("trader", honest); a.Overconfident || math.Abs(a.Gap) > 1e-9 {
+		t.Errorf("honest agent flagged: %+v", a)
+	}
+	if a := Assess("trader", overconfident(MinSamples-1)); a.Overconfident {
+		t.Errorf("flagged on too few samples: %+v", a)
+	}
+}
+
+func TestAnnotateFromStoredOutcomes(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewStore(filepath.Join(t.TempDir(), "agent.db"))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	for i := 0; i < 30; i++ {
+		id, err := store.CreateSession(ctx, &models.SessionRecord{Symbol: "AAPL.US", TradeDate: "2024-05-10", Status: storage.StatusDone})
+		if err != nil {
+			t.Fatalf("CreateSession: %v", err)
+		}
+		if err := store.SaveAgentConfidences(ctx, id, []models.AgentConfidence{
+			{Agent: consts.RiskJudge, Action: "BUY", Confidence: 0.9},
+			{Agent: consts.Trader, Action: "SELL", Confidence: 0.7},
+		}); err != nil {
+			t.Fatalf("SaveAgentConfidences: %v", err)
+		}
+		ret := -1.0 // the judge's BUY is right one time in three
+		if i%3 == 0 {
+			ret = 2.0
+		}
+		if err := store.SaveOutcome(ctx, &models.OutcomeRecord{SessionId: id, HorizonDays: DefaultHorizon, ReturnPct: ret}); err != nil {
+			t.Fatalf("SaveOutcome: %v", err)
+		}
+	}
+
+	summary, err := Summary(ctx, store, DefaultHorizon, "")
+	if err != nil {
+		t.Fatalf("Summary: %v", err)
+	}
+	if len(summary.Agents) != 2 {
+		t.Fatalf("agents = %+v", summary.Agents)
+	}
+	for _, a := range summary.Agents {
+		switch a.Agent {
+		case consts.RiskJudge:
+			if !a.Overconfident || a.Samples != 30 {
+				t.Errorf("risk judge = %+v", a)
+			}
+		case consts.Trader:
+			if a.Overconfident || math.Abs(a.HitRate-2.0/3) > 1e-9 {
+				t.Errorf("trader = %+v", a)
+			}
+		}
+	}
+
+	rep := &report.Report{Sections: []report.Section{{Key: "final_trade_decision", Content: "FINAL TRANSACTION PROPOSAL: **BUY**\nCONFIDENCE: 0.9"}}}
+	rep.Decision = report.ExtractDecision(rep)
+	if err := Annotate(ctx, store, rep); err != nil {
+		t.Fatalf("Annotate: %v", err)
+	}
+	if got := rep.Decision.CalibratedConfidence; math.Abs(got-1.0/3) > 0.05 {
+		t.Errorf("calibrated confidence = %.3f, want about 0.33", got)
+	}
+}
@@ -0,0 +1,68 @@
+package calibration
+
+import (
+	"context"
+
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+// DefaultHorizon is the holding period, in trading days, whose outcome decides
+// whether a call was right; it matches the results.evaluate default.
+const DefaultHorizon = 5
+
+// Load returns the evaluated samples per agent, in the order agents first
+// appear. Samples with an unknown action are dropped.
+func Load(ctx context.Context, store *storage.Store, horizonDays int, agent string) (map[string][]Sample, []string, error) {
+	rows, err := store.ListCalibrationSamples(ctx, horizonDays, agent)
+	if err != nil {
+		return nil, nil, err
+	}
+	byAgent := map[string][]Sample{}
+	var order []string
+	for _, r := range rows {
+		correct, err := report.OutcomeCorrect(r.Action, r.ReturnPct/100)
+		if err != nil {
+			continue
+		}
+		if _, ok := byAgent[r.Agent]; !ok {
+			order = append(order, r.Agent)
+		}
+		byAgent[r.Agent] = append(byAgent[r.Agent], Sample{Confidence: r.Confidence, Correct: correct})
+	}
+	return byAgent, order, nil
+}
+
+// Summary assesses every agent (or just agent) at the given horizon.
+func Summary(ctx context.Context, store *storage.Store, horizonDays int, agent string) (*models.ResultsCalibrationResponse, error) {
+	byAgent, order, err := Load(ctx, store, horizonDays, agent)
+	if err != nil {
+		return nil, err
+	}
+	resp := &models.ResultsCalibrationResponse{HorizonDays: horizonDays, Agents: []models.AgentCalibration{}}
+	for _, a := range order {
+		resp.Agents = append(resp.Agents, Assess(a, byAgent[a]))
+	}
+	return resp, nil
+}
+
+// Annotate sets rep.Decision.CalibratedConfidence from the history of the
+// agent that made the decision. It leaves the decision untouched when the
+// confidence is missing or the history is too short to fit a mapping.
+func Annotate(ctx context.Context, store *storage.Store, rep *report.Report) error {
+	if rep == nil || rep.Decision == nil || rep.Decision.Confidence <= 0 {
+		return nil
+	}
+	agent := report.DecisionAgent(rep)
+	byAgent, _, err := Load(ctx, store, DefaultHorizon, agent)
+	if err != nil {
+		return err
+	}
+	mapper, _ := Fit(byAgent[agent])
+	if mapper == nil {
+		return nil
+	}
+	rep.Decision.CalibratedConfidence = mapper.Map(rep.Decision.Confidence)
+	return nil
+}
@@ -0,0 +1,199 @@
+// Package catalysts collects the scheduled events after a trade date that can
+// move a stock: its earnings releases, the expiry of its IPO lockup and the
+// FOMC rate decisions. The report lists them and exports them as an .ics
+// calendar.
+package catalysts
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// Window bounds, in calendar days after the trade date.
+const (
+	DefaultDays = 90
+	MaxDays     = 365
+)
+
+// lockupDays is the customary IPO lockup; the actual term is in the
+// prospectus, so expiries are marked estimated.
+const lockupDays = 180
+
+// fomcDecisions are the days the FOMC announces its rate decision (the second
+// day of each meeting), as published on federalreserve.gov.
+var fomcDecisions = []string{
+	"2024-01-31", "2024-03-20", "2024-05-01", "2024-06-12", "2024-07-31", "2024-09-18", "2024-11-07", "2024-12-18",
+	"2025-01-29", "2025-03-19", "2025-05-07", "2025-06-18", "2025-07-30", "2025-09-17", "2025-10-29", "2025-12-10",
+	"2026-01-28", "2026-03-18", "2026-04-29", "2026-06-17", "2026-07-29", "2026-09-16", "2026-10-28", "2026-12-09",
+}
+
+// Source is the corporate calendar Collect reads; *dataflows.CalendarClient
+// implements it.
+type Source interface {
+	GetEarningsDates(symbol, from, to string) ([]models.Catalyst, error)
+	GetIPODate(symbol string) (string, error)
+}
+
+// Collect returns the catalysts from the trade date from (YYYY-MM-DD) through
+// days after it, earliest first, with notes on what could not be looked up.
+// Missing corporate data does not fail the lookup, since the FOMC schedule is
+// built in; only offline cache misses are returned as errors so the run can
+// report them. src may be nil to list economic events only.
+func Collect(src Source, symbol, from string, days int) ([]models.Catalyst, []string, error) {
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid date %q: want YYYY-MM-DD", from)
+	}
+	if days <= 0 {
+		days = DefaultDays
+	}
+	days = min(days, MaxDays)
+	to := start.AddDate(0, 0, days).Format("2006-01-02")
+
+	var out []models.Catalyst
+	var notes []string
+	if src != nil {
+		earnings, err := src.GetEarningsDates(symbol, from, to)
+		if errors.Is(err, dataflows.ErrOffline) {
+			return nil, nil, err
+		}
+		if err != nil {
+			notes = append(notes, fmt.Sprintf("earnings dates unavailable: %v", err))
+		}
+		out = append(out, earnings...)
+
+		if err == nil {
+			ipo, err := src.GetIPODate(symbol)
+			switch {
+			case errors.Is(err, dataflows.ErrOffline):
+				return nil, nil, err
+			case err != nil:
+				notes = append(notes, fmt.Sprintf("listing date unavailable: %v", err))
+			default:
+				if c, ok := lockupExpiry(symbol, ipo, from, to); ok {
+					out = append(out, c)
+				}
+			}
+		}
+	}
+	out = append(out, fomc(from, to)...)
+	if last := fomcDecisions[len(fomcDecisions)-1]; to > last {
+		notes = append(notes, fmt.Sprintf("the built-in FOMC schedule ends on %s", last))
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out, notes, nil
+}
+
+// lockupExpiry estimates when the IPO lockup of a listing that went public on
+// ipo ends, if that falls between from and to.
+func lockupExpiry(symbol, ipo, from, to string) (models.Catalyst, bool) {
+	listed, err := time.Parse("2006-01-02", ipo)
+	if err != nil {
+		return models.Catalyst{}, false
+	}
+	date := listed.AddDate(0, 0, lockupDays).Format("2006-01-02")
+	if date < from || date > to {
+		return models.Catalyst{}, false
+	}
+	ticker := strings.TrimSuffix(dataflows.NormalizeSymbol(symbol), ".US")
+	return models.Catalyst{
+		Date:      date,
+		Kind:      models.CatalystLockup,
+		Symbol:    ticker + ".US",
+		Title:     ticker + " IPO lockup expiry",
+		Detail:    fmt.Sprintf("%d days after the %s listing; check the prospectus for the actual terms", lockupDays, ipo),
+		Estimated: true,
+		Source:    "finnhub",
+	}, true
+}
+
+// fomc lists the FOMC rate decisions between from and to.
+func fomc(from, to string) []models.Catalyst {
+	var out []models.Catalyst
+	for _, d := range fomcDecisions {
+		if d < from || d > to {
+			continue
+		}
+		out = append(out, models.Catalyst{
+			Date:   d,
+			Time:   "14:00",
+			Kind:   models.CatalystEconomic,
+			Title:  "FOMC rate decision",
+			Detail: "Federal Reserve statement at 14:00 ET, press conference at 14:30 ET",
+			Source: "federalreserve.gov",
+		})
+	}
+	return out
+}
+
+// Merge adds the catalysts in add that are not in into yet, keeping the
+// result in date order.
+func Merge(into, add []models.Catalyst) []models.Catalyst {
+	seen := make(map[string]bool, len(into))
+	for _, c := range into {
+		seen[Key(c)] = true
+	}
+	for _, c := range add {
+		if k := Key(c); !seen[k] {
+			seen[k] = true
+			into = append(into, c)
+		}
+	}
+	sort.SliceStable(into, func(i, j int) bool { return into[i].Date < into[j].Date })
+	return into
+}
+
+// Key identifies a catalyst across runs, e.g. for calendar event UIDs.
+func Key(c models.Catalyst) string {
+	return strings.Join([]string{c.Date, c.Kind, c.Symbol, c.Title}, "|")
+}
+
+var sessionLabels = map[string]string{
+	"bmo": "before the open",
+	"amc": "after the close",
+	"dmh": "during market hours",
+}
+
+// When describes the time of day of a catalyst, "" when it is not known.
+func When(c models.Catalyst) string {
+	if label, ok := sessionLabels[c.Time]; ok {
+		return label
+	}
+	if c.Time != "" {
+		return c.Time + " ET"
+	}
+	return ""
+}
+
+// Render formats the catalysts for the model as a markdown table.
+func Render(symbol, from string, days int, cs []models.Catalyst, notes []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Upcoming Catalysts for %s\n\n", symbol)
+	fmt.Fprintf(&b, "**Window:** %d days after %s\n\n", days, from)
+	if len(cs) == 0 {
+		b.WriteString("No scheduled earnings, lockup expiries or FOMC decisions in the window.\n")
+	} else {
+		b.WriteString("| Date | Time | Kind | Event | Detail |\n|---|---|---|---|---|\n")
+		for _, c := range cs {
+			title := c.Title
+			if c.Estimated {
+				title += " (estimated)"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", c.Date, When(c), c.Kind, title, c.Detail)
+		}
+	}
+	if len(notes) > 0 {
+		b.WriteString("\n")
+		for _, n := range notes {
+			fmt.Fprintf(&b, "- %s\n", n)
+		}
+	}
+	return b.String()
+}
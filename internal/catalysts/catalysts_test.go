@@ -0,0 +1,69 @@
+package catalysts
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+type fakeSource struct {
+	earnings []models.Catalyst
+	ipo      string
+	err      error
+}
+
+func (f *fakeSource) GetEarningsDates(symbol, from, to string) ([]models.Catalyst, error) {
+	return f.earnings, f.err
+}
+
+func (f *fakeSource) GetIPODate(symbol string) (string, error) {
+	return f.ipo, f.err
+}
+
+func TestCollect(t *testing.T) {
+	src := &fakeSource{
+		earnings: []models.Catalyst{{Date: "2024-08-06", Time: "amc", Kind: models.CatalystEarnings, Symbol: "RDDT.US", Title: "RDDT Q2 2024 earnings"}},
+		ipo:      "2024-03-21",
+	}
+	got, notes, err := Collect(src, "RDDT", "2024-07-01", 90)
+	if err != nil || len(notes) != 0 {
+		t.Fatalf("Collect: %v, notes %v", err, notes)
+	}
+	var dates []string
+	for _, c := range got {
+		dates = append(dates, c.Date+" "+c.Kind)
+	}
+	want := "[2024-07-31 economic 2024-08-06 earnings 2024-09-17 lockup 2024-09-18 economic]"
+	if fmt.Sprint(dates) != want {
+		t.Fatalf("catalysts = %v, want %v", dates, want)
+	}
+	if lockup := got[2]; !lockup.Estimated || lockup.Symbol != "RDDT.US" {
+		t.Errorf("lockup = %+v", lockup)
+	}
+	if out := Render("RDDT.US", "2024-07-01", 90, got, nil); !strings.Contains(out, "| 2024-08-06 | after the close | earnings | RDDT Q2 2024 earnings |") ||
+		!strings.Contains(out, "RDDT IPO lockup expiry (estimated)") {
+		t.Errorf("render:\n%s", out)
+	}
+
+	// without corporate data the FOMC schedule is still listed
+	got, notes, err = Collect(&fakeSource{err: dataflows.ErrNoCalendarProvider}, "700.HK", "2026-10-01", 0)
+	if err != nil || len(got) != 2 || len(notes) != 2 {
+		t.Fatalf("no provider: %v, %+v, notes %v", err, got, notes)
+	}
+	if _, _, err := Collect(&fakeSource{err: fmt.Errorf("cache: %w", dataflows.ErrOffline)}, "RDDT", "2024-07-01", 30); !errors.Is(err, dataflows.ErrOffline) {
+		t.Errorf("offline miss: %v", err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := models.Catalyst{Date: "2024-08-06", Kind: models.CatalystEarnings, Title: "x"}
+	b := models.Catalyst{Date: "2024-07-31", Kind: models.CatalystEconomic, Title: "FOMC rate decision"}
+	got := Merge([]models.Catalyst{a}, []models.Catalyst{b, a})
+	if len(got) != 2 || got[0] != b {
+		t.Fatalf("merge = %+v", got)
+	}
+}
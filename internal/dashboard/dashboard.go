@@ -0,0 +1,306 @@
+// Package dashboard serves a small local web UI over the analysis history
+// stored in agent.db.
+package dashboard
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/internal/catalysts"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+	"pct":   func(v float64) string { return strconv.FormatFloat(v*100, 'f', 1, 64) + "%" },
+}).ParseFS(templateFiles, "templates/*.html"))
+
+// Server is a local HTTP server for browsing results.
+type Server struct {
+	store *storage.Store
+
+	mu   sync.Mutex
+	srv  *http.Server
+	addr string
+}
+
+// New creates a dashboard server backed by store.
+func New(store *storage.Store) *Server {
+	return &Server{store: store}
+}
+
+// Start listens on addr (e.g. 127.0.0.1:8765) and serves in the background.
+// It returns the URL the dashboard is reachable at.
+func (s *Server) Start(addr string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.srv != nil {
+		return "http://" + s.addr, nil
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("listen %s: %w", addr, err)
+	}
+	s.srv = &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	s.addr = ln.Addr().String()
+	go func(srv *http.Server) {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("dashboard serve err=%v\n", err)
+		}
+	}(s.srv)
+	return "http://" + s.addr, nil
+}
+
+// Stop shuts the server down if it is running.
+func (s *Server) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	srv := s.srv
+	s.srv = nil
+	s.addr = ""
+	s.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
+}
+
+// Addr returns the listening address, empty when stopped.
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addr
+}
+
+// Handler returns the dashboard routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /runs/{id}", s.handleRun)
+	mux.HandleFunc("GET /api/runs", s.handleAPIRuns)
+	mux.HandleFunc("GET /api/runs/{id}", s.handleAPIRun)
+	mux.HandleFunc("GET /catalysts.ics", s.handleCatalysts)
+	return mux
+}
+
+type runRow struct {
+	ID             int64
+	Symbol         string
+	TradeDate      string
+	Status         string
+	Recommendation string
+	Confidence     float64
+	CreatedAt      string
+}
+
+type scoreRow struct {
+	Label string
+	Count int
+}
+
+type indexPage struct {
+	Filter     models.RunFilter
+	Runs       []runRow
+	Scoreboard []scoreRow
+	Stats      *models.ResultsStats
+}
+
+func filterFromQuery(r *http.Request) models.RunFilter {
+	q := r.URL.Query()
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 || limit > 500 {
+		limit = 200
+	}
+	minConfidence, _ := strconv.ParseFloat(q.Get("min_confidence"), 64)
+	return models.RunFilter{
+		Symbol:         strings.TrimSpace(q.Get("symbol")),
+		Recommendation: strings.ToUpper(strings.TrimSpace(q.Get("recommendation"))),
+		Status:         strings.TrimSpace(q.Get("status")),
+		Since:          strings.TrimSpace(q.Get("since")),
+		MinConfidence:  minConfidence,
+		Limit:          limit,
+	}
+}
+
+func (s *Server) listRuns(r *http.Request) (models.RunFilter, []runRow, error) {
+	filter := filterFromQuery(r)
+	recs, err := s.store.ListRuns(r.Context(), filter)
+	if err != nil {
+		return filter, nil, err
+	}
+	rows := make([]runRow, 0, len(recs))
+	for _, rec := range recs {
+		rows = append(rows, runRow{
+			ID:             rec.Id,
+			Symbol:         rec.Symbol,
+			TradeDate:      rec.TradeDate,
+			Status:         rec.Status,
+			Recommendation: rec.Recommendation,
+			Confidence:     rec.Confidence,
+			CreatedAt:      rec.CreatedAt.Format("2006-01-02 15:04"),
+		})
+	}
+	return filter, rows, nil
+}
+
+// scoreboard counts runs per recommendation.
+func scoreboard(rows []runRow) []scoreRow {
+	counts := map[string]int{}
+	for _, r := range rows {
+		label := r.Recommendation
+		if label == "" {
+			label = "N/A"
+		}
+		counts[label]++
+	}
+	out := make([]scoreRow, 0, len(counts))
+	for label, n := range counts {
+		out = append(out, scoreRow{Label: label, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Label < out[j].Label })
+	return out
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	filter, rows, err := s.listRuns(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	stats, err := s.store.ResultsStats(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	render(w, "index.html", indexPage{Filter: filter, Runs: rows, Scoreboard: scoreboard(rows), Stats: stats})
+}
+
+type runPage struct {
+	Session  *models.SessionRecord
+	Report   *report.Report
+	Outcomes []models.OutcomeRecord
+	Journal  []models.JournalEntry
+}
+
+func (s *Server) loadRun(r *http.Request) (*runPage, int, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return nil, http.StatusBadRequest, errors.New("invalid session id")
+	}
+	sess, err := s.store.GetSession(r.Context(), id)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if sess == nil {
+		return nil, http.StatusNotFound, errors.New("session not found")
+	}
+	page := &runPage{Session: sess}
+	rec, err := s.store.GetReport(r.Context(), id)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if rec != nil {
+		if page.Report, err = report.Decode(rec.Content); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+	if page.Outcomes, err = s.store.ListOutcomes(r.Context(), id); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if page.Journal, err = s.store.ListJournalEntries(r.Context(), "", id, false); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return page, http.StatusOK, nil
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	page, code, err := s.loadRun(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	render(w, "run.html", page)
+}
+
+func (s *Server) handleAPIRuns(w http.ResponseWriter, r *http.Request) {
+	_, rows, err := s.listRuns(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, rows)
+}
+
+func (s *Server) handleAPIRun(w http.ResponseWriter, r *http.Request) {
+	page, code, err := s.loadRun(r)
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	writeJSON(w, page)
+}
+
+// handleCatalysts serves the upcoming catalysts of the stored reports as a
+// calendar feed that calendar apps can subscribe to. It takes the same
+// filters as the run list, e.g. /catalysts.ics?symbol=AAPL.US.
+func (s *Server) handleCatalysts(w http.ResponseWriter, r *http.Request) {
+	filter := filterFromQuery(r)
+	recs, err := s.store.ListRuns(r.Context(), filter)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	today := time.Now().Format("2006-01-02")
+	var upcoming []models.Catalyst
+	for _, rec := range recs {
+		stored, err := s.store.GetReport(r.Context(), rec.Id)
+		if err != nil || stored == nil {
+			continue
+		}
+		rep, err := report.Decode(stored.Content)
+		if err != nil {
+			continue
+		}
+		var add []models.Catalyst
+		for _, c := range rep.Catalysts {
+			if c.Date >= today {
+				add = append(add, c)
+			}
+		}
+		upcoming = catalysts.Merge(upcoming, add)
+	}
+	name := "CortexGo catalysts"
+	if filter.Symbol != "" {
+		name = "CortexGo " + filter.Symbol + " catalysts"
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	_, _ = w.Write(report.CalendarICS(name, upcoming, "From CortexGo analyses.", time.Now()))
+}
+
+func render(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := templates.ExecuteTemplate(w, name, data); err != nil {
+		fmt.Printf("dashboard render %s err=%v\n", name, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
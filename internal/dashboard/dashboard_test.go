@@ -0,0 +1,149 @@
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+func newTestStore(t *testing.T) *storage.Store {
+	t.Helper()
+	store, err := storage.NewStore(filepath.Join(t.TempDir(), "agent.db"))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func seedRun(t *testing.T, store *storage.Store, symbol, rec string) int64 {
+	t.Helper()
+	ctx := context.Background()
+	sess := &models.SessionRecord{Symbol: symbol, TradeDate: "2024-05-10", Status: storage.StatusDone}
+	id, err := store.CreateSession(ctx, sess)
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	content := `{"symbol":"` + symbol + `","trade_date":"2024-05-10","recommendation":"` + rec +
+		`","sections":[{"key":"final_trade_decision","title":"Final Trade Decision","content":"go ` + rec + `"}]}`
+	if err := store.SaveReport(ctx, &models.ReportRecord{SessionId: id, Symbol: symbol, TradeDate: "2024-05-10", Recommendation: rec, Content: content}); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+	return id
+}
+
+func get(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+	body, _ := io.ReadAll(rr.Body)
+	return rr.Code, string(body)
+}
+
+func TestDashboardListAndDetail(t *testing.T) {
+	store := newTestStore(t)
+	aapl := seedRun(t, store, "AAPL.US", "BUY")
+	seedRun(t, store, "TSLA.US", "SELL")
+	if err := store.SaveDecision(context.Background(), aapl, &models.TradingDecision{Symbol: "AAPL.US", Date: "2024-05-10", Action: "BUY", Confidence: 0.8}); err != nil {
+		t.Fatalf("SaveDecision: %v", err)
+	}
+	if err := store.SaveOutcome(context.Background(), &models.OutcomeRecord{SessionId: aapl, HorizonDays: 5, EvalDate: "2024-05-17", EntryClose: 100, ExitClose: 103, ReturnPct: 3, Correct: true}); err != nil {
+		t.Fatalf("SaveOutcome: %v", err)
+	}
+	h := New(store).Handler()
+
+	code, body := get(t, h, "/?recommendation=buy")
+	if code != http.StatusOK {
+		t.Fatalf("index status = %d", code)
+	}
+	if !strings.Contains(body, "AAPL.US") || strings.Contains(body, "TSLA.US") {
+		t.Fatalf("filter not applied:\n%s", body)
+	}
+	if !strings.Contains(body, "<td>100.0%</td>") {
+		t.Fatalf("expected outcome hit rate on scoreboard:\n%s", body)
+	}
+
+	if _, body = get(t, h, "/?min_confidence=0.9"); strings.Contains(body, "/runs/"+strconv.FormatInt(aapl, 10)) {
+		t.Fatalf("min_confidence filter not applied:\n%s", body)
+	}
+	if _, body = get(t, h, "/?since=2024-05-01&min_confidence=0.7"); !strings.Contains(body, "<td>80.0%</td>") {
+		t.Fatalf("expected AAPL run with confidence:\n%s", body)
+	}
+
+	code, body = get(t, h, "/runs/"+strconv.FormatInt(aapl, 10))
+	if code != http.StatusOK || !strings.Contains(body, "go BUY") {
+		t.Fatalf("detail status=%d body:\n%s", code, body)
+	}
+
+	code, body = get(t, h, "/api/runs?symbol=TSLA")
+	if code != http.StatusOK {
+		t.Fatalf("api status = %d", code)
+	}
+	var rows []runRow
+	if err := json.Unmarshal([]byte(body), &rows); err != nil || len(rows) != 1 || rows[0].Recommendation != "SELL" {
+		t.Fatalf("api rows = %+v err=%v", rows, err)
+	}
+
+	if code, _ = get(t, h, "/runs/999"); code != http.StatusNotFound {
+		t.Fatalf("missing run status = %d", code)
+	}
+}
+
+func TestDashboardJournalPnL(t *testing.T) {
+	store := newTestStore(t)
+	aapl := seedRun(t, store, "AAPL.US", "BUY")
+	ctx := context.Background()
+	for _, e := range []*models.JournalEntry{
+		{SessionId: aapl, Symbol: "AAPL.US", Side: models.JournalLong, Recommendation: "BUY", Quantity: 10, EntryPrice: 100, EntryDate: "2024-05-10",
+			ExitPrice: 110, ExitDate: "2024-05-20", Currency: "USD", RealizedPnL: 100, ReturnPct: 10, Notes: "followed the call"},
+		{Symbol: "700.HK", Side: models.JournalLong, Quantity: 100, EntryPrice: 300, EntryDate: "2024-05-11", Currency: "HKD"},
+	} {
+		if err := store.CreateJournalEntry(ctx, e); err != nil {
+			t.Fatalf("CreateJournalEntry: %v", err)
+		}
+	}
+	h := New(store).Handler()
+
+	_, body := get(t, h, "/")
+	for _, want := range []string{"Trades taken", "<td>2</td><td>1</td><td>1</td><td>100.0%</td>", "100.00 USD", "1 (10.00%)"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("scoreboard missing %q:\n%s", want, body)
+		}
+	}
+	_, body = get(t, h, "/runs/"+strconv.FormatInt(aapl, 10))
+	if !strings.Contains(body, "followed the call") || !strings.Contains(body, "100.00 USD") {
+		t.Fatalf("run page missing journal entry:\n%s", body)
+	}
+}
+
+func TestDashboardCatalystFeed(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	id, err := store.CreateSession(ctx, &models.SessionRecord{Symbol: "AAPL.US", TradeDate: "2024-05-10", Status: storage.StatusDone})
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	content := `{"symbol":"AAPL.US","trade_date":"2024-05-10","sections":[],"catalysts":[
+{"date":"2024-06-12","kind":"economic","title":"FOMC rate decision","source":"federalreserve.gov"},
+{"date":"2999-01-28","time":"amc","kind":"earnings","symbol":"AAPL.US","title":"AAPL Q1 2999 earnings","source":"finnhub"}]}`
+	if err := store.SaveReport(ctx, &models.ReportRecord{SessionId: id, Symbol: "AAPL.US", TradeDate: "2024-05-10", Content: content}); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+
+	code, body := get(t, New(store).Handler(), "/catalysts.ics?symbol=AAPL.US")
+	if code != http.StatusOK || !strings.Contains(body, "SUMMARY:AAPL Q1 2999 earnings (after the close)") {
+		t.Fatalf("feed status %d:\n%s", code, body)
+	}
+	if strings.Contains(body, "FOMC") {
+		t.Fatalf("past catalysts should be left out:\n%s", body)
+	}
+}
@@ -0,0 +1,138 @@
+// Package experiment runs two configurations over the same symbols and dates
+// and compares their decisions, costs and latency, so a config or prompt
+// change can be judged on more than a single run.
+package experiment
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rundir"
+)
+
+// Case is one symbol and trade date both arms analyze.
+type Case struct {
+	Symbol    string `json:"symbol"`
+	TradeDate string `json:"trade_date"`
+}
+
+// Outcome is what one arm produced for a case.
+type Outcome struct {
+	Recommendation string  `json:"recommendation,omitempty"`
+	Confidence     float64 `json:"confidence,omitempty"`
+	PositionSize   float64 `json:"position_size,omitempty"`
+	InputTokens    int     `json:"input_tokens"`
+	OutputTokens   int     `json:"output_tokens"`
+	CostUSD        float64 `json:"cost_usd"`
+	LatencySeconds float64 `json:"latency_seconds"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// Arm is a configuration under test.
+type Arm struct {
+	Name       string
+	ConfigPath string
+	Config     *config.Config
+}
+
+// RunFunc analyzes one case with cfg. Errors are reported in the outcome so
+// one failing case does not stop the experiment.
+type RunFunc func(ctx context.Context, cfg *config.Config, c Case) Outcome
+
+// Pair is a case with the outcome of each arm.
+type Pair struct {
+	Case
+	Baseline  Outcome `json:"baseline"`
+	Candidate Outcome `json:"candidate"`
+	// Changed is set when both arms completed with different recommendations.
+	Changed bool `json:"changed"`
+}
+
+// Result is a finished experiment.
+type Result struct {
+	ID        string    `json:"id"`
+	Baseline  string    `json:"baseline"`
+	Candidate string    `json:"candidate"`
+	StartedAt time.Time `json:"started_at"`
+	Pairs     []*Pair   `json:"pairs"`
+	Summary   Summary   `json:"summary"`
+}
+
+// ParseCases reads one case per line: a symbol optionally followed by a trade
+// date (YYYY-MM-DD), separated by whitespace or a comma. Lines without a date
+// use defaultDate; blank lines and # comments are skipped, as are repeats.
+func ParseCases(r io.Reader, defaultDate string) ([]Case, error) {
+	var cases []Case
+	seen := map[Case]bool{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: want \"SYMBOL [YYYY-MM-DD]\", got %q", n, strings.TrimSpace(line))
+		}
+		c := Case{Symbol: strings.ToUpper(fields[0]), TradeDate: defaultDate}
+		if len(fields) == 2 {
+			c.TradeDate = fields[1]
+		}
+		if _, err := time.Parse("2006-01-02", c.TradeDate); err != nil {
+			return nil, fmt.Errorf("line %d: invalid trade date %q", n, c.TradeDate)
+		}
+		if !seen[c] {
+			seen[c] = true
+			cases = append(cases, c)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, errors.New("experiment needs at least one symbol")
+	}
+	return cases, nil
+}
+
+// Run analyzes every case with both arms, one run at a time. The arm that goes
+// first alternates between cases so neither always runs against a cold cache.
+// onDone, when set, is called after each case. A cancelled ctx stops the
+// experiment after the current run; the cases finished so far are kept.
+func Run(ctx context.Context, baseline, candidate Arm, cases []Case, run RunFunc, onDone func(*Pair)) *Result {
+	res := &Result{ID: rundir.NewID(time.Now()), Baseline: baseline.Name, Candidate: candidate.Name, StartedAt: time.Now()}
+	for i, c := range cases {
+		if ctx.Err() != nil {
+			break
+		}
+		p := &Pair{Case: c}
+		first, second := &p.Baseline, &p.Candidate
+		firstCfg, secondCfg := baseline.Config, candidate.Config
+		if i%2 == 1 {
+			first, second = second, first
+			firstCfg, secondCfg = secondCfg, firstCfg
+		}
+		*first = run(ctx, firstCfg, c)
+		if ctx.Err() != nil {
+			break
+		}
+		*second = run(ctx, secondCfg, c)
+		p.Changed = p.Baseline.Error == "" && p.Candidate.Error == "" &&
+			!strings.EqualFold(p.Baseline.Recommendation, p.Candidate.Recommendation)
+		res.Pairs = append(res.Pairs, p)
+		if onDone != nil {
+			onDone(p)
+		}
+	}
+	res.Summary = Summarize(res.Pairs)
+	return res
+}
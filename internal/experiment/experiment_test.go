@@ -0,0 +1,111 @@
+package experiment
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/config"
+)
+
+func TestParseCases(t *testing.T) {
+	in := `# watchlist
+aapl.us
+MSFT.US, 2025-11-03
+AAPL.US   # repeat
+700.HK	2025-11-04
+`
+	cases, err := ParseCases(strings.NewReader(in), "2025-12-15")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []Case{{"AAPL.US", "2025-12-15"}, {"MSFT.US", "2025-11-03"}, {"700.HK", "2025-11-04"}}
+	if len(cases) != len(want) {
+		t.Fatalf("expected %d cases, got %+v", len(want), cases)
+	}
+	for i := range want {
+		if cases[i] != want[i] {
+			t.Errorf("case %d: expected %+v, got %+v", i, want[i], cases[i])
+		}
+	}
+
+	for _, bad := range []string{"", "# only a comment\n", "AAPL.US 2025-13-01\n", "AAPL.US 2025-12-15 extra\n"} {
+		if _, err := ParseCases(strings.NewReader(bad), "2025-12-15"); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
+
+func TestRunAlternatesArmsAndSummarizes(t *testing.T) {
+	baseline := Arm{Name: "a.json", Config: &config.Config{Depth: "quick"}}
+	candidate := Arm{Name: "b.json", Config: &config.Config{Depth: "deep"}}
+	cases := []Case{{"AAPL.US", "2025-12-15"}, {"MSFT.US", "2025-12-15"}, {"TSLA.US", "2025-12-15"}}
+
+	var order []string
+	run := func(ctx context.Context, cfg *config.Config, c Case) Outcome {
+		order = append(order, c.Symbol+":"+cfg.Depth)
+		o := Outcome{Recommendation: "BUY", Confidence: 0.6, InputTokens: 1000, OutputTokens: 100, CostUSD: 0.01, LatencySeconds: 10}
+		if cfg.Depth == "deep" {
+			o.CostUSD, o.LatencySeconds = 0.03, 30
+			switch c.Symbol {
+			case "MSFT.US":
+				o.Recommendation = "SELL"
+			case "TSLA.US":
+				return Outcome{Error: "rate limited\nretry later", LatencySeconds: 1}
+			}
+		}
+		return o
+	}
+	var done int
+	res := Run(context.Background(), baseline, candidate, cases, run, func(*Pair) { done++ })
+
+	wantOrder := "AAPL.US:quick AAPL.US:deep MSFT.US:deep MSFT.US:quick TSLA.US:quick TSLA.US:deep"
+	if got := strings.Join(order, " "); got != wantOrder {
+		t.Errorf("run order:\n got %s\nwant %s", got, wantOrder)
+	}
+	if done != 3 || len(res.Pairs) != 3 {
+		t.Fatalf("expected 3 finished pairs, got %d (onDone %d)", len(res.Pairs), done)
+	}
+	if res.Pairs[0].Changed || !res.Pairs[1].Changed || res.Pairs[2].Changed {
+		t.Errorf("only MSFT.US should be changed: %v %v %v", res.Pairs[0].Changed, res.Pairs[1].Changed, res.Pairs[2].Changed)
+	}
+
+	s := res.Summary
+	if s.Cases != 3 || s.Compared != 2 || s.Agreed != 1 || s.Changed != 1 {
+		t.Errorf("unexpected agreement: %+v", s)
+	}
+	if s.Candidate.Failed != 1 || s.Candidate.Completed != 2 || s.Candidate.Recommendations["SELL"] != 1 {
+		t.Errorf("unexpected candidate summary: %+v", s.Candidate)
+	}
+	if s.Baseline.MeanLatencySeconds != 10 || s.Candidate.MaxLatencySeconds != 30 {
+		t.Errorf("unexpected latency: baseline %+v candidate %+v", s.Baseline, s.Candidate)
+	}
+
+	md := Markdown(res)
+	for _, want := range []string{
+		"Same decision: 1 of 2 (50%); changed: 1",
+		"| Cost | $0.0300 | $0.0600 | +100% |",
+		"- MSFT.US 2025-12-15: BUY (60%) → SELL (60%)",
+		"failed: rate limited |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("report missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestRunStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	arm := Arm{Config: &config.Config{}}
+	calls := 0
+	res := Run(ctx, arm, arm, []Case{{"AAPL.US", "2025-12-15"}, {"MSFT.US", "2025-12-15"}}, func(ctx context.Context, _ *config.Config, _ Case) Outcome {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return Outcome{Recommendation: "HOLD"}
+	}, nil)
+	if calls != 3 || len(res.Pairs) != 1 {
+		t.Fatalf("expected to stop after the third run with one finished pair, got %d runs and %d pairs", calls, len(res.Pairs))
+	}
+}
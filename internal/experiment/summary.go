@@ -0,0 +1,196 @@
+package experiment
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dyike/CortexGo/pkg/utils"
+)
+
+// ArmSummary aggregates one arm's outcomes.
+type ArmSummary struct {
+	Completed          int            `json:"completed"`
+	Failed             int            `json:"failed"`
+	Recommendations    map[string]int `json:"recommendations"`
+	MeanConfidence     float64        `json:"mean_confidence"`
+	InputTokens        int            `json:"input_tokens"`
+	OutputTokens       int            `json:"output_tokens"`
+	CostUSD            float64        `json:"cost_usd"`
+	MeanLatencySeconds float64        `json:"mean_latency_seconds"`
+	MaxLatencySeconds  float64        `json:"max_latency_seconds"`
+}
+
+// Summary compares the arms over all cases. Agreement is counted only over
+// cases both arms completed.
+type Summary struct {
+	Cases     int        `json:"cases"`
+	Compared  int        `json:"compared"`
+	Agreed    int        `json:"agreed"`
+	Changed   int        `json:"changed"`
+	Baseline  ArmSummary `json:"baseline"`
+	Candidate ArmSummary `json:"candidate"`
+}
+
+// Summarize aggregates pairs into per-arm totals and the decision agreement.
+func Summarize(pairs []*Pair) Summary {
+	s := Summary{Cases: len(pairs)}
+	var baseline, candidate []Outcome
+	for _, p := range pairs {
+		baseline = append(baseline, p.Baseline)
+		candidate = append(candidate, p.Candidate)
+		if p.Baseline.Error != "" || p.Candidate.Error != "" {
+			continue
+		}
+		s.Compared++
+		if p.Changed {
+			s.Changed++
+		} else {
+			s.Agreed++
+		}
+	}
+	s.Baseline, s.Candidate = summarizeArm(baseline), summarizeArm(candidate)
+	return s
+}
+
+func summarizeArm(outcomes []Outcome) ArmSummary {
+	a := ArmSummary{Recommendations: map[string]int{}}
+	var confidence, latency float64
+	for _, o := range outcomes {
+		a.InputTokens += o.InputTokens
+		a.OutputTokens += o.OutputTokens
+		a.CostUSD += o.CostUSD
+		latency += o.LatencySeconds
+		if o.LatencySeconds > a.MaxLatencySeconds {
+			a.MaxLatencySeconds = o.LatencySeconds
+		}
+		if o.Error != "" {
+			a.Failed++
+			continue
+		}
+		a.Completed++
+		rec := strings.ToUpper(o.Recommendation)
+		if rec == "" {
+			rec = "N/A"
+		}
+		a.Recommendations[rec]++
+		confidence += o.Confidence
+	}
+	if a.Completed > 0 {
+		a.MeanConfidence = confidence / float64(a.Completed)
+	}
+	if len(outcomes) > 0 {
+		a.MeanLatencySeconds = latency / float64(len(outcomes))
+	}
+	return a
+}
+
+// Markdown renders the diff report: the arms side by side, the cases whose
+// decision changed, then every case.
+func Markdown(res *Result) string {
+	s := res.Summary
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Experiment %s\n\n", res.ID)
+	fmt.Fprintf(&b, "- Baseline: `%s`\n- Candidate: `%s`\n- Cases: %d\n", res.Baseline, res.Candidate, s.Cases)
+	if s.Compared > 0 {
+		fmt.Fprintf(&b, "- Same decision: %d of %d (%.0f%%); changed: %d\n", s.Agreed, s.Compared, float64(s.Agreed)/float64(s.Compared)*100, s.Changed)
+	}
+
+	b.WriteString("\n| | Baseline | Candidate | Delta |\n|---|---:|---:|---:|\n")
+	row := func(name, base, cand, delta string) {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", name, base, cand, delta)
+	}
+	bs, cs := s.Baseline, s.Candidate
+	row("Completed", fmt.Sprint(bs.Completed), fmt.Sprint(cs.Completed), signedInt(cs.Completed-bs.Completed))
+	row("Failed", fmt.Sprint(bs.Failed), fmt.Sprint(cs.Failed), signedInt(cs.Failed-bs.Failed))
+	for _, rec := range []string{"BUY", "HOLD", "SELL", "N/A"} {
+		if bs.Recommendations[rec]+cs.Recommendations[rec] > 0 {
+			row(rec, fmt.Sprint(bs.Recommendations[rec]), fmt.Sprint(cs.Recommendations[rec]), signedInt(cs.Recommendations[rec]-bs.Recommendations[rec]))
+		}
+	}
+	row("Mean confidence", percent(bs.MeanConfidence), percent(cs.MeanConfidence), fmt.Sprintf("%+.0f pp", (cs.MeanConfidence-bs.MeanConfidence)*100))
+	row("Tokens (in/out)", fmt.Sprintf("%d / %d", bs.InputTokens, bs.OutputTokens), fmt.Sprintf("%d / %d", cs.InputTokens, cs.OutputTokens), relative(float64(bs.InputTokens+bs.OutputTokens), float64(cs.InputTokens+cs.OutputTokens)))
+	row("Cost", fmt.Sprintf("$%.4f", bs.CostUSD), fmt.Sprintf("$%.4f", cs.CostUSD), relative(bs.CostUSD, cs.CostUSD))
+	row("Mean latency", seconds(bs.MeanLatencySeconds), seconds(cs.MeanLatencySeconds), relative(bs.MeanLatencySeconds, cs.MeanLatencySeconds))
+	row("Max latency", seconds(bs.MaxLatencySeconds), seconds(cs.MaxLatencySeconds), relative(bs.MaxLatencySeconds, cs.MaxLatencySeconds))
+
+	if s.Changed > 0 {
+		b.WriteString("\n## Changed decisions\n\n")
+		for _, p := range res.Pairs {
+			if p.Changed {
+				fmt.Fprintf(&b, "- %s %s: %s (%s) → %s (%s)\n", p.Symbol, p.TradeDate,
+					utils.OrDash(p.Baseline.Recommendation), percent(p.Baseline.Confidence),
+					utils.OrDash(p.Candidate.Recommendation), percent(p.Candidate.Confidence))
+			}
+		}
+	}
+
+	b.WriteString("\n## Cases\n\n| Symbol | Date | Baseline | Candidate | Cost | Latency |\n|---|---|---|---|---:|---:|\n")
+	for _, p := range res.Pairs {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | $%.4f → $%.4f | %s → %s |\n", p.Symbol, p.TradeDate,
+			describe(p.Baseline), describe(p.Candidate),
+			p.Baseline.CostUSD, p.Candidate.CostUSD,
+			seconds(p.Baseline.LatencySeconds), seconds(p.Candidate.LatencySeconds))
+	}
+	return b.String()
+}
+
+// WriteReport writes report.md and result.json under
+// <resultsDir>/experiments/<id>/ and returns that directory.
+func WriteReport(resultsDir string, res *Result) (string, error) {
+	dir := filepath.Join(resultsDir, "experiments", res.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report.md"), []byte(Markdown(res)), 0644); err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return dir, os.WriteFile(filepath.Join(dir, "result.json"), data, 0644)
+}
+
+// describe is an outcome's table cell: the decision with its confidence, or
+// the error.
+func describe(o Outcome) string {
+	if o.Error != "" {
+		msg := o.Error
+		if i := strings.IndexByte(msg, '\n'); i >= 0 {
+			msg = msg[:i]
+		}
+		return "failed: " + strings.ReplaceAll(msg, "|", "\\|")
+	}
+	if o.Confidence > 0 {
+		return fmt.Sprintf("%s (%s)", utils.OrDash(o.Recommendation), percent(o.Confidence))
+	}
+	return utils.OrDash(o.Recommendation)
+}
+
+func relative(base, cand float64) string {
+	if base == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%+.0f%%", (cand-base)/base*100)
+}
+
+func signedInt(n int) string {
+	if n == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%+d", n)
+}
+
+func percent(c float64) string {
+	if c == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", c*100)
+}
+
+func seconds(s float64) string {
+	return fmt.Sprintf("%.1fs", s)
+}
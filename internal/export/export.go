@@ -0,0 +1,184 @@
+// Package export writes the candle and indicator series the tools compute to
+// data_dir/export as CSV or Parquet, one file per symbol, series and date
+// range, so they can be loaded into pandas, DuckDB or a spreadsheet.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/parquet"
+)
+
+// Formats accepted by series_export.
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+	FormatOff     = "off"
+)
+
+// Exporter writes series in one format under one directory.
+type Exporter struct {
+	dir    string
+	format string
+}
+
+// New returns the exporter configured by cfg, or nil when series_export is
+// off. An empty format means CSV.
+func New(cfg *config.Config) *Exporter {
+	format := strings.ToLower(strings.TrimSpace(cfg.SeriesExport))
+	switch format {
+	case FormatOff:
+		return nil
+	case "":
+		format = FormatCSV
+	}
+	return &Exporter{dir: filepath.Join(cfg.DataDir, "export"), format: format}
+}
+
+// Candles writes daily bars as date, open, high, low, close, volume, oldest
+// first, and returns the file path. Writing the same range again replaces the
+// file, so repeated runs do not pile up copies.
+func (e *Exporter) Candles(symbol string, bars []*models.MarketData) (string, error) {
+	var kept []*models.MarketData
+	for _, b := range bars {
+		if b != nil && b.Date != "" {
+			kept = append(kept, b)
+		}
+	}
+	if len(kept) == 0 {
+		return "", fmt.Errorf("no candles for %s", symbol)
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Date < kept[j].Date })
+
+	columns := []parquet.Column{
+		{Name: "date", Type: parquet.String},
+		{Name: "open", Type: parquet.Double},
+		{Name: "high", Type: parquet.Double},
+		{Name: "low", Type: parquet.Double},
+		{Name: "close", Type: parquet.Double},
+		{Name: "volume", Type: parquet.Int64},
+	}
+	rows := make([][]any, len(kept))
+	for i, b := range kept {
+		rows[i] = []any{b.Date, b.Open, b.High, b.Low, b.Close, b.Volume}
+	}
+	return e.write(symbol, "candles", kept[0].Date, kept[len(kept)-1].Date, columns, rows)
+}
+
+// Indicators writes indicator series as one row per date and one column per
+// indicator, sorted by name. Dates an indicator has no value for are left
+// empty in CSV and NaN in Parquet, whose columns cannot be null.
+func (e *Exporter) Indicators(symbol string, indicators map[string][]models.IndicatorValue) (string, error) {
+	names := make([]string, 0, len(indicators))
+	values := map[string]map[string]float64{}
+	dates := map[string]bool{}
+	for name, series := range indicators {
+		if len(series) == 0 {
+			continue
+		}
+		names = append(names, name)
+		byDate := make(map[string]float64, len(series))
+		for _, v := range series {
+			byDate[v.Date] = v.Value
+			dates[v.Date] = true
+		}
+		values[name] = byDate
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("no indicator values for %s", symbol)
+	}
+	sort.Strings(names)
+	ordered := make([]string, 0, len(dates))
+	for d := range dates {
+		ordered = append(ordered, d)
+	}
+	sort.Strings(ordered)
+
+	columns := []parquet.Column{{Name: "date", Type: parquet.String}}
+	for _, name := range names {
+		columns = append(columns, parquet.Column{Name: name, Type: parquet.Double})
+	}
+	rows := make([][]any, len(ordered))
+	for i, d := range ordered {
+		row := []any{d}
+		for _, name := range names {
+			v, ok := values[name][d]
+			if !ok {
+				v = math.NaN()
+			}
+			row = append(row, v)
+		}
+		rows[i] = row
+	}
+	return e.write(symbol, "indicators", ordered[0], ordered[len(ordered)-1], columns, rows)
+}
+
+// write stores a table at <dir>/<symbol>/<series>_<from>_<to>.<format>. The
+// file is written next to its final name and renamed, so readers never see a
+// partial file.
+func (e *Exporter) write(symbol, series, from, to string, columns []parquet.Column, rows [][]any) (string, error) {
+	dir := filepath.Join(e.dir, symbol)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create export dir: %w", err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s_%s_%s.%s", series, from, to, e.format))
+	f, err := os.CreateTemp(dir, "."+series+"-*")
+	if err != nil {
+		return "", fmt.Errorf("create export: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if e.format == FormatParquet {
+		err = parquet.Write(f, columns, rows)
+	} else {
+		err = writeCSV(f, columns, rows)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", fmt.Errorf("write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return "", fmt.Errorf("save %s: %w", filepath.Base(path), err)
+	}
+	return path, nil
+}
+
+func writeCSV(w io.Writer, columns []parquet.Column, rows [][]any) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			switch val := v.(type) {
+			case float64:
+				if !math.IsNaN(val) {
+					record[i] = strconv.FormatFloat(val, 'f', -1, 64)
+				}
+			default:
+				record[i] = fmt.Sprint(val)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,78 @@
+package export
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+)
+
+func TestNewFormats(t *testing.T) {
+	if New(&config.Config{SeriesExport: "off"}) != nil {
+		t.Fatal("series_export off should disable the exporter")
+	}
+	if e := New(&config.Config{DataDir: "/d"}); e == nil || e.format != FormatCSV || e.dir != filepath.Join("/d", "export") {
+		t.Fatalf("default exporter = %+v", e)
+	}
+}
+
+func TestCandlesAndIndicatorsCSV(t *testing.T) {
+	e := New(&config.Config{DataDir: t.TempDir()})
+	bars := []*models.MarketData{
+		{Date: "2025-06-03", Open: 2, High: 3, Low: 1.5, Close: 2.5, Volume: 200},
+		{Date: "2025-06-02", Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 100},
+	}
+	path, err := e.Candles("AAPL.US", bars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "candles_2025-06-02_2025-06-03.csv" {
+		t.Fatalf("path = %s", path)
+	}
+	got, _ := os.ReadFile(path)
+	want := "date,open,high,low,close,volume\n2025-06-02,1,2,0.5,1.5,100\n2025-06-03,2,3,1.5,2.5,200\n"
+	if string(got) != want {
+		t.Fatalf("candles =\n%s\nwant\n%s", got, want)
+	}
+
+	path, err = e.Indicators("AAPL.US", map[string][]models.IndicatorValue{
+		"rsi":          {{Date: "2025-06-02", Value: 55.5}, {Date: "2025-06-03", Value: 60}},
+		"close_50_sma": {{Date: "2025-06-03", Value: 1.75}},
+		"atr":          nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = os.ReadFile(path)
+	want = "date,close_50_sma,rsi\n2025-06-02,,55.5\n2025-06-03,1.75,60\n"
+	if string(got) != want {
+		t.Fatalf("indicators =\n%s\nwant\n%s", got, want)
+	}
+
+	// the same range is rewritten in place
+	if _, err := e.Candles("AAPL.US", bars); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 2 {
+		t.Fatalf("export dir has %d entries, want 2", len(entries))
+	}
+}
+
+func TestCandlesParquet(t *testing.T) {
+	e := New(&config.Config{DataDir: t.TempDir(), SeriesExport: "parquet"})
+	path, err := e.Candles("700.HK", []*models.MarketData{{Date: "2025-06-02", Close: 380, Volume: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if filepath.Ext(path) != ".parquet" || !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatalf("not a parquet file: %s", path)
+	}
+	if _, err := e.Candles("700.HK", nil); err == nil {
+		t.Fatal("want an error for no candles")
+	}
+}
@@ -0,0 +1,166 @@
+// Package fx converts price series and amounts between currencies at daily
+// reference rates, so comparisons across markets (an HKD stock against a USD
+// sector ETF, a book holding Hong Kong and US names) are made in one currency.
+package fx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// rateMargin is how many calendar days of rates are fetched before the first
+// bar, so a bar on a day without a fixing can take an earlier one.
+const rateMargin = 10
+
+// RateSource returns the price of one unit of from in to for each day between
+// start and end (YYYY-MM-DD), keyed by date; dataflows.FXClient is one.
+type RateSource interface {
+	GetRates(from, to, start, end string) (map[string]float64, error)
+}
+
+// Converter converts through one rate source and remembers what it converted,
+// so callers can say so next to the numbers.
+type Converter struct {
+	src RateSource
+
+	mu    sync.Mutex
+	notes map[string]string
+	now   func() time.Time
+}
+
+// New returns a converter reading rates from src.
+func New(src RateSource) *Converter {
+	return &Converter{src: src, notes: map[string]string{}, now: time.Now}
+}
+
+// CurrencyOf returns the currency bars are quoted in: the one they carry, or
+// the one their symbol's market trades in.
+func CurrencyOf(symbol string, bars []*models.MarketData) string {
+	for _, b := range bars {
+		if b != nil && b.Currency != "" {
+			return strings.ToUpper(b.Currency)
+		}
+	}
+	return dataflows.CurrencyOf(symbol)
+}
+
+// Bars returns copies of bars with open, high, low and close converted to the
+// to currency at each bar's daily rate; bars already in it are returned as-is.
+// Volume is left in shares.
+func (c *Converter) Bars(symbol string, bars []*models.MarketData, to string) ([]*models.MarketData, error) {
+	to = strings.ToUpper(to)
+	from := CurrencyOf(symbol, bars)
+	if from == to || len(bars) == 0 {
+		return bars, nil
+	}
+	first, last := "", ""
+	for _, b := range bars {
+		if b == nil {
+			continue
+		}
+		if first == "" || b.Date < first {
+			first = b.Date
+		}
+		if b.Date > last {
+			last = b.Date
+		}
+	}
+	start, err := time.Parse("2006-01-02", first)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bar date %q", first)
+	}
+	rates, err := c.src.GetRates(from, to, start.AddDate(0, 0, -rateMargin).Format("2006-01-02"), last)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*models.MarketData, 0, len(bars))
+	for _, b := range bars {
+		if b == nil {
+			continue
+		}
+		rate, ok := dataflows.RateOn(rates, b.Date)
+		if !ok {
+			return nil, fmt.Errorf("no %s/%s rate on or before %s", from, to, b.Date)
+		}
+		cp := *b
+		cp.Open, cp.High, cp.Low, cp.Close = b.Open*rate, b.High*rate, b.Low*rate, b.Close*rate
+		cp.Currency = to
+		out = append(out, &cp)
+	}
+	return out, nil
+}
+
+// Fetcher wraps fetch so every series comes back in the to currency. A series
+// whose rates cannot be had is returned unconverted and noted as such rather
+// than dropped.
+func (c *Converter) Fetcher(fetch dataflows.BarFetcher, to string) dataflows.BarFetcher {
+	to = strings.ToUpper(to)
+	return func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+		bars, err := fetch(ctx, symbol, count)
+		if err != nil {
+			return nil, err
+		}
+		from := CurrencyOf(symbol, bars)
+		if from == to {
+			return bars, nil
+		}
+		converted, err := c.Bars(symbol, bars, to)
+		if err != nil {
+			c.note(symbol, fmt.Sprintf("%s left in %s, not converted to %s: %v", symbol, from, to, err))
+			return bars, nil
+		}
+		c.note(symbol, fmt.Sprintf("%s converted from %s to %s at daily ECB reference rates", symbol, from, to))
+		return converted, nil
+	}
+}
+
+// Amount converts amount from one currency to another at the latest rate on
+// or before date (YYYY-MM-DD, empty for today).
+func (c *Converter) Amount(amount float64, from, to, date string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return amount, nil
+	}
+	day := c.now()
+	if date != "" {
+		var err error
+		if day, err = time.Parse("2006-01-02", date); err != nil {
+			return 0, fmt.Errorf("invalid date %q: want YYYY-MM-DD", date)
+		}
+	}
+	end := day.Format("2006-01-02")
+	rates, err := c.src.GetRates(from, to, day.AddDate(0, 0, -rateMargin).Format("2006-01-02"), end)
+	if err != nil {
+		return 0, err
+	}
+	rate, ok := dataflows.RateOn(rates, end)
+	if !ok {
+		return 0, fmt.Errorf("no %s/%s rate on or before %s", from, to, end)
+	}
+	return amount * rate, nil
+}
+
+// Notes lists what was converted, or left unconverted, one line per symbol.
+func (c *Converter) Notes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	notes := make([]string, 0, len(c.notes))
+	for _, n := range c.notes {
+		notes = append(notes, n)
+	}
+	sort.Strings(notes)
+	return notes
+}
+
+func (c *Converter) note(symbol, text string) {
+	c.mu.Lock()
+	c.notes[symbol] = text
+	c.mu.Unlock()
+}
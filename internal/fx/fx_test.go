@@ -0,0 +1,62 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+type fakeRates map[string]float64
+
+func (f fakeRates) GetRates(from, to, start, end string) (map[string]float64, error) {
+	if from != "HKD" || to != "USD" {
+		return nil, errors.New("no rates")
+	}
+	return f, nil
+}
+
+func TestBarsConvertsAtDailyRate(t *testing.T) {
+	c := New(fakeRates{"2025-06-05": 0.128, "2025-06-06": 0.127})
+	bars := []*models.MarketData{
+		{Symbol: "700.HK", Date: "2025-06-06", Open: 500, High: 510, Low: 490, Close: 500, Volume: 7, Currency: "HKD"},
+		{Symbol: "700.HK", Date: "2025-06-09", Close: 400},
+	}
+	out, err := c.Bars("700.HK", bars, "usd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out[0].Close != 63.5 || out[0].High != 510*0.127 || out[0].Volume != 7 || out[0].Currency != "USD" {
+		t.Errorf("bar = %+v", out[0])
+	}
+	// Monday takes the last fixing before it
+	if math.Abs(out[1].Close-400*0.127) > 1e-9 || bars[0].Close != 500 {
+		t.Errorf("converted %+v, original %+v", out[1], bars[0])
+	}
+	if same, _ := c.Bars("AAPL.US", bars[1:], "USD"); same[0] != bars[1] {
+		t.Error("bars already in the currency should be returned as-is")
+	}
+}
+
+func TestFetcherNotesConversions(t *testing.T) {
+	c := New(fakeRates{"2025-06-06": 0.128})
+	fetch := c.Fetcher(func(_ context.Context, symbol string, _ int) ([]*models.MarketData, error) {
+		return []*models.MarketData{{Symbol: symbol, Date: "2025-06-06", Close: 100}}, nil
+	}, "USD")
+	hk, _ := fetch(context.Background(), "700.HK", 1)
+	us, _ := fetch(context.Background(), "XLK.US", 1)
+	cn, _ := fetch(context.Background(), "600519.SH", 1)
+	if hk[0].Close != 12.8 || us[0].Close != 100 || cn[0].Close != 100 {
+		t.Errorf("closes = %v %v %v", hk[0].Close, us[0].Close, cn[0].Close)
+	}
+	notes := c.Notes()
+	if len(notes) != 2 || !strings.Contains(notes[0], "600519.SH left in CNY") || !strings.Contains(notes[1], "700.HK converted from HKD to USD") {
+		t.Errorf("notes = %q", notes)
+	}
+	if v, err := c.Amount(1000, "HKD", "USD", "2025-06-08"); err != nil || v != 128 {
+		t.Errorf("Amount = %v, %v", v, err)
+	}
+}
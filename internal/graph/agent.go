@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+)
+
+// AgentNames 列出可单独运行的全部 agent
+func AgentNames() []string {
+	var names []string
+	for name := range agentBuilders[string, string](context.Background(), nil) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunAgent 以 state 为状态单独运行一个 agent 节点，节点对状态的修改直接写入 state，
+// 返回节点选择的下一步；用于按 agent 回归测试提示词，不经过完整编排图。
+// 调用前需先 agents.InitChatModel（或直接设置 agents.ChatModel）
+func RunAgent(ctx context.Context, cfg *config.Config, name string, state *models.TradingState, opts ...compose.Option) (string, error) {
+	build, ok := agentBuilders[string, string](ctx, cfg)[name]
+	if !ok {
+		return "", fmt.Errorf("unknown agent %q (want one of %s)", name, strings.Join(AgentNames(), ", "))
+	}
+	if state.Config == nil {
+		state.Config = cfg
+	}
+	g := compose.NewGraph[string, string](compose.WithGenLocalState(func(context.Context) *models.TradingState {
+		return state
+	}))
+	_ = g.AddGraphNode(name, build(), compose.WithNodeName(name))
+	_ = g.AddEdge(compose.START, name)
+	_ = g.AddEdge(name, compose.END)
+	r, err := g.Compile(ctx, compose.WithGraphName("CortexGo-"+name))
+	if err != nil {
+		return "", fmt.Errorf("compile %s: %w", name, err)
+	}
+	return r.Invoke(ctx, name, opts...)
+}
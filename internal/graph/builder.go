@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"time"
 
 	"github.com/cloudwego/eino/compose"
 	"github.com/dyike/CortexGo/config"
@@ -12,6 +13,8 @@ import (
 	"github.com/dyike/CortexGo/internal/agents/researchers"
 	"github.com/dyike/CortexGo/internal/agents/risk_mgmt"
 	"github.com/dyike/CortexGo/internal/agents/trader"
+	"github.com/dyike/CortexGo/internal/rundir"
+	"github.com/dyike/CortexGo/models"
 )
 
 func NewTradingOrchestrator[I, O, S any](ctx context.Context, genFunc compose.GenLocalState[S], cfg *config.Config) compose.Runnable[I, O] {
@@ -19,36 +22,47 @@ func NewTradingOrchestrator[I, O, S any](ctx context.Context, genFunc compose.Ge
 		panic(err)
 	}
 
+	// 创建状态时记录本次运行的不确定输入，报告据此说明两次运行是否可比；
+	// 同时分配 run ID，本次运行的产物写入各自的运行目录
+	genState := func(ctx context.Context) S {
+		state := genFunc(ctx)
+		if ts, ok := any(state).(*models.TradingState); ok && ts != nil {
+			if ts.RunInputs == nil {
+				ts.RunInputs = agents.RunInputsFor(cfg)
+			}
+			if ts.RunID == "" {
+				ts.RunID = rundir.NewID(time.Now())
+			}
+		}
+		return state
+	}
 	g := compose.NewGraph[I, O](
-		compose.WithGenLocalState(genFunc),
+		compose.WithGenLocalState(genState),
 	)
 
-	// 创建分析师节点 - use new ReAct-based MarketAnalyst
-	marketAnalystGraph := analysts.NewMarketAnalyst[I, O](ctx, cfg)
-	socialAnalystGraph := analysts.NewSocialAnalyst[I, O](ctx, cfg)
-	newsAnalystGraph := analysts.NewNewsAnalyst[I, O](ctx, cfg)
-	fundamentalsAnalystGraph := analysts.NewFundamentalsAnalystNode[I, O](ctx, cfg)
+	preset := agents.PresetFor(cfg)
+	builders := agentBuilders[I, O](ctx, cfg)
 
 	// 创建研究员节点 - use simple nodes with proper type adapters
-	bullResearcherGraph := researchers.NewBullResearcherNode[I, O](ctx, cfg)
-	bearResearcherGraph := researchers.NewBearResearcherNode[I, O](ctx, cfg)
-	researchManagerGraph := managers.NewResearchManagerNode[I, O](ctx, cfg)
+	bullResearcherGraph := builders[consts.BullResearcher]()
+	bearResearcherGraph := builders[consts.BearResearcher]()
+	researchManagerGraph := builders[consts.ResearchManager]()
 
 	// 创建交易员节点 - use simple nodes with proper type adapters
-	traderGraph := trader.NewTraderNode[I, O](ctx, cfg)
+	traderGraph := builders[consts.Trader]()
 
-	// 创建风险分析节点 - use simple nodes with proper type adapters
-	riskyAnalystGraph := risk_mgmt.NewRiskyAnalystNode[I, O](ctx, cfg)
-	neutralAnalystGraph := risk_mgmt.NewNeutralAnalystNode[I, O](ctx, cfg)
-	safeAnalystGraph := risk_mgmt.NewSafeAnalystNode[I, O](ctx, cfg)
-	riskManagerGraph := managers.NewRiskManagerNode[I, O](ctx, cfg)
+	// 创建风险裁判节点
+	riskManagerGraph := builders[consts.RiskJudge]()
 
 	// 添加所有节点
-	// Analyst
-	_ = g.AddGraphNode(consts.MarketAnalyst, marketAnalystGraph, compose.WithNodeName(consts.MarketAnalyst))
-	_ = g.AddGraphNode(consts.SocialAnalyst, socialAnalystGraph, compose.WithNodeName(consts.SocialAnalyst))
-	_ = g.AddGraphNode(consts.NewsAnalyst, newsAnalystGraph, compose.WithNodeName(consts.NewsAnalyst))
-	_ = g.AddGraphNode(consts.FundamentalsAnalyst, fundamentalsAnalystGraph, compose.WithNodeName(consts.FundamentalsAnalyst))
+	// 大盘环境
+	if !cfg.SkipMarketContext {
+		_ = g.AddLambdaNode(consts.MarketContext, compose.InvokableLambda(loadMarketContext[I]), compose.WithNodeName(consts.MarketContext))
+	}
+	// Analyst - 按分析深度选择参与的分析师
+	for _, name := range preset.Analysts {
+		_ = g.AddGraphNode(name, builders[name](), compose.WithNodeName(name))
+	}
 	// Research
 	_ = g.AddGraphNode(consts.BullResearcher, bullResearcherGraph, compose.WithNodeName(consts.BullResearcher))
 	_ = g.AddGraphNode(consts.BearResearcher, bearResearcherGraph, compose.WithNodeName(consts.BearResearcher))
@@ -56,17 +70,19 @@ func NewTradingOrchestrator[I, O, S any](ctx context.Context, genFunc compose.Ge
 	// Trader
 	_ = g.AddGraphNode(consts.Trader, traderGraph, compose.WithNodeName(consts.Trader))
 	// Risk
-	_ = g.AddGraphNode(consts.RiskyAnalyst, riskyAnalystGraph, compose.WithNodeName(consts.RiskyAnalyst))
-	_ = g.AddGraphNode(consts.SafeAnalyst, safeAnalystGraph, compose.WithNodeName(consts.SafeAnalyst))
-	_ = g.AddGraphNode(consts.NeutralAnalyst, neutralAnalystGraph, compose.WithNodeName(consts.NeutralAnalyst))
 	_ = g.AddGraphNode(consts.RiskJudge, riskManagerGraph, compose.WithNodeName(consts.RiskJudge))
 
 	// Sequential edges for analysis phase (linear flow)
-	_ = g.AddEdge(compose.START, consts.MarketAnalyst)
-	_ = g.AddEdge(consts.MarketAnalyst, consts.SocialAnalyst)
-	_ = g.AddEdge(consts.SocialAnalyst, consts.NewsAnalyst)
-	_ = g.AddEdge(consts.NewsAnalyst, consts.FundamentalsAnalyst)
-	_ = g.AddEdge(consts.FundamentalsAnalyst, consts.BullResearcher)
+	prev := compose.START
+	if !cfg.SkipMarketContext {
+		_ = g.AddEdge(compose.START, consts.MarketContext)
+		prev = consts.MarketContext
+	}
+	for _, name := range preset.Analysts {
+		_ = g.AddEdge(prev, name)
+		prev = name
+	}
+	_ = g.AddEdge(prev, consts.BullResearcher)
 
 	// Conditional branches for debate phase (bull/bear cycle)
 	_ = g.AddBranch(consts.BullResearcher, compose.NewGraphBranch(ShouldContinueDebate, map[string]bool{
@@ -80,21 +96,34 @@ func NewTradingOrchestrator[I, O, S any](ctx context.Context, genFunc compose.Ge
 
 	// Sequential edge to trading phase
 	_ = g.AddEdge(consts.ResearchManager, consts.Trader)
-	_ = g.AddEdge(consts.Trader, consts.RiskyAnalyst)
 
-	// Conditional branches for risk phase (three-way cycle)
-	_ = g.AddBranch(consts.RiskyAnalyst, compose.NewGraphBranch(ShouldContinueRiskAnalysis, map[string]bool{
-		consts.SafeAnalyst: true,
-		consts.RiskJudge:   true,
-	}))
-	_ = g.AddBranch(consts.SafeAnalyst, compose.NewGraphBranch(ShouldContinueRiskAnalysis, map[string]bool{
-		consts.NeutralAnalyst: true,
-		consts.RiskJudge:      true,
-	}))
-	_ = g.AddBranch(consts.NeutralAnalyst, compose.NewGraphBranch(ShouldContinueRiskAnalysis, map[string]bool{
-		consts.RiskJudge:    true,
-		consts.RiskyAnalyst: true,
-	}))
+	if preset.RiskTurns > 0 {
+		// 创建风险分析节点 - use simple nodes with proper type adapters
+		riskyAnalystGraph := builders[consts.RiskyAnalyst]()
+		neutralAnalystGraph := builders[consts.NeutralAnalyst]()
+		safeAnalystGraph := builders[consts.SafeAnalyst]()
+		_ = g.AddGraphNode(consts.RiskyAnalyst, riskyAnalystGraph, compose.WithNodeName(consts.RiskyAnalyst))
+		_ = g.AddGraphNode(consts.SafeAnalyst, safeAnalystGraph, compose.WithNodeName(consts.SafeAnalyst))
+		_ = g.AddGraphNode(consts.NeutralAnalyst, neutralAnalystGraph, compose.WithNodeName(consts.NeutralAnalyst))
+		_ = g.AddEdge(consts.Trader, consts.RiskyAnalyst)
+
+		// Conditional branches for risk phase (three-way cycle)
+		_ = g.AddBranch(consts.RiskyAnalyst, compose.NewGraphBranch(ShouldContinueRiskAnalysis, map[string]bool{
+			consts.SafeAnalyst: true,
+			consts.RiskJudge:   true,
+		}))
+		_ = g.AddBranch(consts.SafeAnalyst, compose.NewGraphBranch(ShouldContinueRiskAnalysis, map[string]bool{
+			consts.NeutralAnalyst: true,
+			consts.RiskJudge:      true,
+		}))
+		_ = g.AddBranch(consts.NeutralAnalyst, compose.NewGraphBranch(ShouldContinueRiskAnalysis, map[string]bool{
+			consts.RiskJudge:    true,
+			consts.RiskyAnalyst: true,
+		}))
+	} else {
+		// quick 模式跳过风险辩论，交易员直接交给风险裁判
+		_ = g.AddEdge(consts.Trader, consts.RiskJudge)
+	}
 
 	// Final edge to end
 	_ = g.AddEdge(consts.RiskJudge, compose.END)
@@ -108,3 +137,21 @@ func NewTradingOrchestrator[I, O, S any](ctx context.Context, genFunc compose.Ge
 	}
 	return r
 }
+
+// agentBuilders 各 agent 子图的构造函数，按 agent 名索引；子图在调用时才创建
+func agentBuilders[I, O any](ctx context.Context, cfg *config.Config) map[string]func() *compose.Graph[I, O] {
+	return map[string]func() *compose.Graph[I, O]{
+		consts.MarketAnalyst:       func() *compose.Graph[I, O] { return analysts.NewMarketAnalyst[I, O](ctx, cfg) },
+		consts.SocialAnalyst:       func() *compose.Graph[I, O] { return analysts.NewSocialAnalyst[I, O](ctx, cfg) },
+		consts.NewsAnalyst:         func() *compose.Graph[I, O] { return analysts.NewNewsAnalyst[I, O](ctx, cfg) },
+		consts.FundamentalsAnalyst: func() *compose.Graph[I, O] { return analysts.NewFundamentalsAnalystNode[I, O](ctx, cfg) },
+		consts.BullResearcher:      func() *compose.Graph[I, O] { return researchers.NewBullResearcherNode[I, O](ctx, cfg) },
+		consts.BearResearcher:      func() *compose.Graph[I, O] { return researchers.NewBearResearcherNode[I, O](ctx, cfg) },
+		consts.ResearchManager:     func() *compose.Graph[I, O] { return managers.NewResearchManagerNode[I, O](ctx, cfg) },
+		consts.Trader:              func() *compose.Graph[I, O] { return trader.NewTraderNode[I, O](ctx, cfg) },
+		consts.RiskyAnalyst:        func() *compose.Graph[I, O] { return risk_mgmt.NewRiskyAnalystNode[I, O](ctx, cfg) },
+		consts.SafeAnalyst:         func() *compose.Graph[I, O] { return risk_mgmt.NewSafeAnalystNode[I, O](ctx, cfg) },
+		consts.NeutralAnalyst:      func() *compose.Graph[I, O] { return risk_mgmt.NewNeutralAnalystNode[I, O](ctx, cfg) },
+		consts.RiskJudge:           func() *compose.Graph[I, O] { return managers.NewRiskManagerNode[I, O](ctx, cfg) },
+	}
+}
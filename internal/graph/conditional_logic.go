@@ -6,6 +6,7 @@ import (
 
 	"github.com/cloudwego/eino/compose"
 	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/internal/agents"
 	"github.com/dyike/CortexGo/models"
 )
 
@@ -18,7 +19,7 @@ func ShouldContinueDebate(ctx context.Context, _ string) (string, error) {
 	if state == nil || state.InvestmentDebateState == nil {
 		return consts.BullResearcher, nil
 	}
-	if state.InvestmentDebateState.Count >= 2 {
+	if state.InvestmentDebateState.Count >= agents.PresetFor(state.Config).DebateTurns {
 		return consts.ResearchManager, nil
 	}
 	curResp := state.InvestmentDebateState.CurrentResponse
@@ -37,7 +38,7 @@ func ShouldContinueRiskAnalysis(ctx context.Context, _ string) (string, error) {
 	if state == nil || state.RiskDebateState == nil {
 		return consts.RiskyAnalyst, nil
 	}
-	if state.RiskDebateState.Count >= 3 {
+	if state.RiskDebateState.Count >= agents.PresetFor(state.Config).RiskTurns {
 		return consts.RiskJudge, nil
 	}
 	latestSpeaker := state.RiskDebateState.LatestSpeaker
@@ -2,9 +2,7 @@ package graph
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"io"
 	"strings"
 	"sync"
 
@@ -31,6 +29,9 @@ type LoggerCallback struct {
 	currentContent strings.Builder
 	toolCalls      map[string]*toolCallInfo // key: tool_call ID
 	stateLock      sync.Mutex
+
+	// streams 追踪仍在读取模型流式输出的 goroutine，Wait 等待其全部结束
+	streams sync.WaitGroup
 }
 
 func NewLoggerCallback(emit func(event string, data *models.ChatResp)) *LoggerCallback {
@@ -77,7 +78,9 @@ func (cb *LoggerCallback) OnEndWithStreamOutput(ctx context.Context, info *callb
 
 	msgID := utils.RandStr(20)
 
+	cb.streams.Add(1)
 	go func() {
+		defer cb.streams.Done()
 		defer output.Close() // remember to close the stream in defer
 		defer func() {
 			if err := recover(); err != nil {
@@ -91,12 +94,11 @@ func (cb *LoggerCallback) OnEndWithStreamOutput(ctx context.Context, info *callb
 		})
 		for {
 			frame, err := output.Recv()
-			if errors.Is(err, io.EOF) {
-				cb.flushCurrentAssistantMessage(agentName, true)
-				break
-			}
 			if err != nil {
+				// io.EOF 为正常结束，其余错误同样落地已收到的内容
+				cb.stateLock.Lock()
 				cb.flushCurrentAssistantMessage(agentName, true)
+				cb.stateLock.Unlock()
 				return
 			}
 
@@ -118,6 +120,12 @@ func (cb *LoggerCallback) OnEndWithStreamOutput(ctx context.Context, info *callb
 	return ctx
 }
 
+// Wait 阻塞到所有流式输出处理完毕、事件全部发出为止；编排结束后、写入运行目录或返回结果之前调用，
+// 否则最后一批事件可能在调用方返回之后才到达
+func (cb *LoggerCallback) Wait() {
+	cb.streams.Wait()
+}
+
 func (cb *LoggerCallback) pushMsg(ctx context.Context, agentName, msgID string, msg *schema.Message) error {
 	if msg == nil {
 		return nil
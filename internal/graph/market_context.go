@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"context"
+	"log"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/dyike/CortexGo/internal/regime"
+	"github.com/dyike/CortexGo/models"
+)
+
+// loadMarketContext 在分析师之前读取一次大盘环境写入 state，输入原样传给下一个节点；
+// 读取失败只记录日志，分析师会看到"大盘环境不可用"的提示
+func loadMarketContext[I any](ctx context.Context, input I) (I, error) {
+	err := compose.ProcessState[*models.TradingState](ctx, func(ctx context.Context, state *models.TradingState) error {
+		r, err := regime.Load(ctx, state.Config, state.CompanyOfInterest, state.TradeDate)
+		if err != nil {
+			log.Printf("market context for %s: %v", state.CompanyOfInterest, err)
+			return nil
+		}
+		state.MarketRegime = r
+		return nil
+	})
+	return input, err
+}
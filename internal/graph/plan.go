@@ -0,0 +1,266 @@
+package graph
+
+import (
+	"context"
+	"slices"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/mockllm"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// DeepSeek 标价（美元 / 百万 token），用于 dry-run 费用估算与按实际用量计费；chat 与 reasoner 同价
+const (
+	deepSeekInputPrice  = 0.28
+	deepSeekOutputPrice = 0.42
+)
+
+// planStep 单个节点的估算参数：每次调用的输入/输出 token
+type planStep struct {
+	stage        string
+	agent        string
+	model        string
+	tools        func(*config.Config) []tool.BaseTool
+	calls        int
+	inputTokens  int
+	outputTokens int
+}
+
+// reactCalls ReAct 分析师通常的模型调用次数：选择工具、读取结果、提交报告
+const reactCalls = 3
+
+func marketTools(cfg *config.Config) []tool.BaseTool {
+	return []tool.BaseTool{tools.NewMarketool(cfg), tools.NewStockIndicatorTool(cfg), tools.NewCorrelationTool(cfg), tools.NewMarketStructureTool(cfg), tools.NewVolatilityTool(cfg), tools.NewIntermarketTool(cfg)}
+}
+
+func socialTools(cfg *config.Config) []tool.BaseTool {
+	return []tool.BaseTool{
+		tools.NewRedditSubredditTool(cfg),
+		tools.NewRedditSearchTool(cfg),
+		tools.NewRedditStockMentionsTool(cfg),
+		tools.NewRedditFinanceNewsTool(cfg),
+	}
+}
+
+func newsTools(cfg *config.Config) []tool.BaseTool {
+	return []tool.BaseTool{
+		tools.NewGoogleFinanceNewsTool(cfg),
+		tools.NewGoogleNewsSearchTool(cfg),
+		tools.NewGoogleStockNewsTool(cfg),
+		tools.NewTopHeadlinesTool(cfg),
+		tools.NewNewsTimelineTool(cfg),
+		tools.NewSearchPastAnalysesTool(cfg),
+		tools.NewEarningsCallTool(cfg),
+		tools.NewAnomalyTool(cfg),
+		tools.NewYieldCurveTool(cfg),
+		tools.NewCatalystsTool(cfg),
+	}
+}
+
+func fundamentalsTools(cfg *config.Config) []tool.BaseTool {
+	return []tool.BaseTool{tools.NewQueryDocumentsTool(cfg), tools.NewEarningsCallTool(cfg), tools.NewInsiderSentimentTool(cfg)}
+}
+
+var analystPlanSteps = map[string]planStep{
+	consts.MarketAnalyst:       {stage: "analysis", agent: consts.MarketAnalyst, tools: marketTools, calls: reactCalls, inputTokens: 6000, outputTokens: 800},
+	consts.SocialAnalyst:       {stage: "analysis", agent: consts.SocialAnalyst, tools: socialTools, calls: reactCalls, inputTokens: 5000, outputTokens: 700},
+	consts.NewsAnalyst:         {stage: "analysis", agent: consts.NewsAnalyst, tools: newsTools, calls: reactCalls, inputTokens: 5000, outputTokens: 700},
+	consts.FundamentalsAnalyst: {stage: "analysis", agent: consts.FundamentalsAnalyst, tools: fundamentalsTools, calls: reactCalls, inputTokens: 4000, outputTokens: 1500},
+}
+
+// planSteps 与 NewTradingOrchestrator 按同一深度预设生成的节点顺序保持一致
+func planSteps(preset agents.DepthPreset) []planStep {
+	var steps []planStep
+	for _, name := range preset.Analysts {
+		step := analystPlanSteps[name]
+		step.model = preset.Model
+		steps = append(steps, step)
+	}
+	debaters := []string{consts.BullResearcher, consts.BearResearcher}
+	for i := 0; i < preset.DebateTurns; i++ {
+		steps = append(steps, planStep{stage: "debate", agent: debaters[i%len(debaters)], model: preset.Model, calls: 1, inputTokens: 6000, outputTokens: 1000})
+	}
+	steps = append(steps,
+		planStep{stage: "debate", agent: consts.ResearchManager, model: preset.DecisionModel, calls: 1, inputTokens: 8000, outputTokens: 1200},
+		planStep{stage: "trading", agent: consts.Trader, model: preset.Model, calls: 1, inputTokens: 5000, outputTokens: 800},
+	)
+	riskers := []string{consts.RiskyAnalyst, consts.SafeAnalyst, consts.NeutralAnalyst}
+	for i := 0; i < preset.RiskTurns; i++ {
+		steps = append(steps, planStep{stage: "risk", agent: riskers[i%len(riskers)], model: preset.Model, calls: 1, inputTokens: 7000, outputTokens: 900})
+	}
+	return append(steps, planStep{stage: "risk", agent: consts.RiskJudge, model: preset.DecisionModel, calls: 1, inputTokens: 9000, outputTokens: 1200})
+}
+
+// BuildPlan 生成一次分析的执行计划：节点、工具、模型与 token/费用估算，不调用模型或数据源
+func BuildPlan(ctx context.Context, cfg *config.Config, symbol, tradeDate string) *models.AgentPlanResponse {
+	preset := agents.PresetFor(cfg)
+	plan := &models.AgentPlanResponse{
+		Symbol:       symbol,
+		TradeDate:    tradeDate,
+		Depth:        preset.Name,
+		Offline:      cfg.Offline,
+		MaxToolSteps: preset.MaxToolSteps,
+	}
+	toolNames := map[string][]string{}
+	for _, s := range planSteps(preset) {
+		step := models.AgentPlanStep{
+			Stage:        s.stage,
+			Agent:        s.agent,
+			Model:        s.model,
+			Calls:        s.calls,
+			InputTokens:  s.calls * s.inputTokens,
+			OutputTokens: s.calls * s.outputTokens,
+		}
+		if s.tools != nil {
+			step.Tools = toolInfoNames(ctx, s.tools(cfg))
+			toolNames[s.agent] = step.Tools
+		}
+		if cfg.MockLLM() {
+			step.Model = mockllm.Name
+		}
+		plan.Steps = append(plan.Steps, step)
+		plan.LLMCalls += step.Calls
+		plan.InputTokens += step.InputTokens
+		plan.OutputTokens += step.OutputTokens
+	}
+	plan.EstimatedCostUSD = CostUSD(plan.InputTokens, plan.OutputTokens)
+	plan.Sources = planSources(cfg, toolNames)
+
+	switch {
+	case cfg.MockLLM():
+		// mock 提供方不调用模型，也不产生费用
+		plan.EstimatedCostUSD = 0
+		plan.Warnings = append(plan.Warnings, "llm_provider is mock: agents return canned responses, not analysis")
+	case cfg.DeepSeekAPIKey == "":
+		plan.Warnings = append(plan.Warnings, "deepseek_api_key is not set; the run would fail")
+	}
+	if cfg.SimulatedData() {
+		plan.Warnings = append(plan.Warnings, "data_provider is simulated: market data, news and social posts are generated, not real")
+	}
+	if cfg.Offline {
+		for _, missing := range tools.OfflinePreflight(cfg, symbol) {
+			plan.Warnings = append(plan.Warnings, "offline: missing "+missing)
+		}
+	}
+	return plan
+}
+
+// DataSources 列出全部数据源、当前模式及其工具，不受深度预设影响（system.capabilities）
+func DataSources(ctx context.Context, cfg *config.Config) []models.AgentPlanSource {
+	toolNames := map[string][]string{}
+	for agent, s := range analystPlanSteps {
+		if s.tools != nil {
+			toolNames[agent] = toolInfoNames(ctx, s.tools(cfg))
+		}
+	}
+	return planSources(cfg, toolNames)
+}
+
+func toolInfoNames(ctx context.Context, ts []tool.BaseTool) []string {
+	var names []string
+	for _, t := range ts {
+		info, err := t.Info(ctx)
+		if err != nil {
+			continue
+		}
+		names = append(names, info.Name)
+	}
+	return names
+}
+
+func planSources(cfg *config.Config, toolNames map[string][]string) []models.AgentPlanSource {
+	live := func(detail string) (string, string) {
+		if cfg.Offline {
+			return "cache", "offline: cache and local archives only"
+		}
+		if cfg.SimulatedData() {
+			return "cache", "simulated data mode: cache only, no network"
+		}
+		return "live", detail
+	}
+
+	marketMode, marketDetail := live("Longport OpenAPI")
+	if !cfg.Offline && !cfg.LongportConfigured() {
+		marketMode, marketDetail = "mock", "longport credentials missing; mock market data"
+	}
+	newsMode, newsDetail := live("Google News search and RSS")
+	redditMode, redditDetail := live("Reddit public JSON API")
+	// 模拟数据模式下行情、新闻与社交数据均由本地生成，优先于离线模式
+	if cfg.SimulatedData() {
+		marketMode, marketDetail = models.ProvenanceSimulated, "seeded random-walk daily candles"
+		newsMode, newsDetail = models.ProvenanceSimulated, "templated news articles"
+		redditMode, redditDetail = models.ProvenanceSimulated, "templated Reddit posts"
+	}
+	transcriptMode, transcriptDetail := live("Finnhub earnings call transcripts")
+	treasuryMode, treasuryDetail := live("US Treasury daily par yield curve")
+	insiderMode, insiderDetail := live("Finnhub insider sentiment (MSPR)")
+	if !cfg.Offline && cfg.FinnhubAPIKey == "" {
+		insiderMode, insiderDetail = "off", "finnhub_api_key missing; insider sentiment unavailable"
+	}
+	calendarMode, calendarDetail := live("Finnhub earnings calendar and the built-in FOMC schedule")
+	if !cfg.Offline && cfg.FinnhubAPIKey == "" {
+		calendarDetail = "finnhub_api_key missing; built-in FOMC schedule only"
+	}
+	switch {
+	case cfg.Offline:
+	case cfg.FinnhubAPIKey == "" && cfg.FMPAPIKey == "":
+		transcriptMode, transcriptDetail = "off", "finnhub_api_key and fmp_api_key missing; transcripts unavailable"
+	case cfg.FinnhubAPIKey == "":
+		transcriptDetail = "Financial Modeling Prep earnings call transcripts"
+	}
+
+	// 历史检索、电话会、内部人交易、异常交易日、国债收益率与催化剂日历工具挂在新闻/基本面分析师上，但数据来源不同，单独列出
+	sourceOf := map[string]string{
+		tools.SearchPastAnalysesToolName: "past_analyses",
+		tools.EarningsCallToolName:       "transcripts",
+		tools.AnomalyToolName:            "longport",
+		tools.YieldCurveToolName:         "treasury",
+		tools.InsiderSentimentToolName:   "insider",
+		tools.CatalystsToolName:          "calendar",
+	}
+	bySource := map[string][]string{}
+	add := func(source string, names []string) {
+		for _, name := range names {
+			s, ok := sourceOf[name]
+			if !ok {
+				s = source
+			}
+			if !slices.Contains(bySource[s], name) {
+				bySource[s] = append(bySource[s], name)
+			}
+		}
+	}
+	add("longport", toolNames[consts.MarketAnalyst])
+	add("google_news", toolNames[consts.NewsAnalyst])
+	add("reddit", toolNames[consts.SocialAnalyst])
+	add("documents", toolNames[consts.FundamentalsAnalyst])
+
+	all := []models.AgentPlanSource{
+		{Name: "longport", Mode: marketMode, Detail: marketDetail, Tools: bySource["longport"]},
+		{Name: "google_news", Mode: newsMode, Detail: newsDetail, Tools: bySource["google_news"]},
+		{Name: "reddit", Mode: redditMode, Detail: redditDetail, Tools: bySource["reddit"]},
+		{Name: "transcripts", Mode: transcriptMode, Detail: transcriptDetail, Tools: bySource["transcripts"]},
+		{Name: "treasury", Mode: treasuryMode, Detail: treasuryDetail, Tools: bySource["treasury"]},
+		{Name: "insider", Mode: insiderMode, Detail: insiderDetail, Tools: bySource["insider"]},
+		{Name: "calendar", Mode: calendarMode, Detail: calendarDetail, Tools: bySource["calendar"]},
+		{Name: "past_analyses", Mode: "local", Detail: "earlier reports in agent.db", Tools: bySource["past_analyses"]},
+		{Name: "documents", Mode: "local", Detail: "ingested filings and research in agent.db", Tools: bySource["documents"]},
+	}
+	// 深度预设未启用对应分析师时不会访问该数据源
+	var sources []models.AgentPlanSource
+	for _, s := range all {
+		// 连续失败已熔断的数据源在冷却期内不会被请求，工具返回降级结果
+		if s.Mode == "live" && dataflows.SourceDegraded(s.Name) {
+			s.Mode, s.Detail = "degraded", s.Detail+"; failing repeatedly, skipped until the breaker cools down"
+		}
+		if len(s.Tools) > 0 {
+			sources = append(sources, s)
+		}
+	}
+	return sources
+}
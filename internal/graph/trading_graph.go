@@ -72,11 +72,11 @@ func (g *TradingAgentsGraph) Propagate(symbol string, date string) (*models.Trad
 		fmt.Printf("Processing %s for date %s using eino orchestrator\n", symbol, date)
 	}
 
-	_, err = g.orchestrator.Stream(ctx, state,
-		compose.WithCallbacks(&LoggerCallback{
-			Emit: g.emit,
-		}),
-	)
+	logger := &LoggerCallback{
+		Emit: g.emit,
+	}
+	_, err = g.orchestrator.Stream(ctx, state, compose.WithCallbacks(logger))
+	logger.Wait()
 	if err != nil {
 		return nil, fmt.Errorf("orchestrator failed: %v", err)
 	}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	ecmodel "github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	cbutils "github.com/cloudwego/eino/utils/callbacks"
+)
+
+// UsageCallback 累计一次运行中各模型调用上报的 token 用量，用于统计实际费用
+type UsageCallback struct {
+	mu           sync.Mutex
+	wg           sync.WaitGroup
+	inputTokens  int
+	outputTokens int
+	calls        int
+}
+
+// Handler 返回只关注模型节点的回调，配合 compose.WithCallbacks 使用
+func (u *UsageCallback) Handler() callbacks.Handler {
+	return cbutils.NewHandlerHelper().ChatModel(&cbutils.ModelCallbackHandler{
+		OnEnd: func(ctx context.Context, _ *callbacks.RunInfo, output *ecmodel.CallbackOutput) context.Context {
+			u.add(output)
+			return ctx
+		},
+		OnEndWithStreamOutput: func(ctx context.Context, _ *callbacks.RunInfo, output *schema.StreamReader[*ecmodel.CallbackOutput]) context.Context {
+			// 用量在流的最后一帧，异步读完，不阻塞模型输出
+			u.wg.Add(1)
+			go func() {
+				defer u.wg.Done()
+				defer output.Close()
+				for {
+					frame, err := output.Recv()
+					if err != nil {
+						return
+					}
+					u.add(frame)
+				}
+			}()
+			return ctx
+		},
+	}).Handler()
+}
+
+func (u *UsageCallback) add(output *ecmodel.CallbackOutput) {
+	if output == nil || output.TokenUsage == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.inputTokens += output.TokenUsage.PromptTokens
+	u.outputTokens += output.TokenUsage.CompletionTokens
+	u.calls++
+}
+
+// Totals 等待所有流读完后返回累计的输入、输出 token 与上报用量的模型调用次数
+func (u *UsageCallback) Totals() (inputTokens, outputTokens, calls int) {
+	u.wg.Wait()
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.inputTokens, u.outputTokens, u.calls
+}
+
+// CostUSD 按 DeepSeek 标价计算 token 费用
+func CostUSD(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*deepSeekInputPrice + float64(outputTokens)*deepSeekOutputPrice) / 1e6
+}
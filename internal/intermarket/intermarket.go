@@ -0,0 +1,333 @@
+// Package intermarket compares a stock with the markets that usually drive
+// it: its sector ETF, the US dollar and the commodities tied to its sector.
+// Each driver is read as a tailwind or headwind for a long position from its
+// move over the look-back window and the usual direction of its influence.
+package intermarket
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// Bounds of the look-back window, in daily bars.
+const (
+	DefaultLookback = 60
+	MinLookback     = 20
+	MaxLookback     = 250
+)
+
+// fetchMargin covers holidays between the stock's and the drivers' calendars.
+const fetchMargin = 30
+
+// minBars is the fewest bars in the window a series needs to be used.
+const minBars = 10
+
+// Move thresholds, in percent over the window, for a driver to count as a
+// tailwind or headwind; the dollar moves less than equities and commodities.
+const (
+	driverMove = 2.0
+	dollarMove = 1.0
+)
+
+// Thresholds for the notes.
+const (
+	bigMove         = 5.0 // stock or relative move worth explaining
+	looseSectorCorr = 0.3 // below this the sector says little about the stock
+)
+
+type driver struct {
+	Symbol   string
+	Name     string
+	Relation int
+	Why      string
+}
+
+type sector struct {
+	Name        string
+	ETF         string
+	Keywords    []string
+	Commodities []driver
+}
+
+var (
+	crude  = driver{Symbol: "USO.US", Name: "Crude oil (USO)"}
+	natgas = driver{Symbol: "UNG.US", Name: "Natural gas (UNG)"}
+	copper = driver{Symbol: "CPER.US", Name: "Copper (CPER)"}
+	gold   = driver{Symbol: "GLD.US", Name: "Gold (GLD)"}
+)
+
+func with(d driver, relation int, why string) driver {
+	d.Relation, d.Why = relation, why
+	return d
+}
+
+// dollar is read against every stock: a stronger dollar cuts the value of
+// overseas earnings and tightens global financial conditions.
+var dollar = driver{Symbol: "UUP.US", Name: "US dollar (UUP)", Relation: -1, Why: "a stronger dollar cuts overseas earnings and tightens global financial conditions"}
+
+// sectors maps sector names, as given by GICS or an index provider, to a US
+// SPDR sector ETF and the commodities that move the sector's earnings.
+var sectors = []sector{
+	{Name: "Energy", ETF: "XLE.US", Keywords: []string{"energy", "oil", "gas"}, Commodities: []driver{
+		with(crude, 1, "producers' revenue moves with crude"),
+		with(natgas, 1, "gas prices drive upstream and LNG earnings"),
+	}},
+	{Name: "Materials", ETF: "XLB.US", Keywords: []string{"material", "chemical", "mining", "metal"}, Commodities: []driver{
+		with(copper, 1, "industrial metals prices drive miners' margins"),
+		with(gold, 1, "precious metal miners are leveraged to gold"),
+	}},
+	{Name: "Industrials", ETF: "XLI.US", Keywords: []string{"industr", "aerospace", "transport", "machinery"}, Commodities: []driver{
+		with(copper, 1, "rising copper signals stronger industrial activity"),
+		with(crude, -1, "fuel and inputs get more expensive"),
+	}},
+	{Name: "Consumer Discretionary", ETF: "XLY.US", Keywords: []string{"discretionary", "consumer cyclical", "retail", "auto"}, Commodities: []driver{
+		with(crude, -1, "higher fuel prices squeeze consumer spending"),
+	}},
+	{Name: "Consumer Staples", ETF: "XLP.US", Keywords: []string{"staples", "consumer defensive", "food", "beverage"}, Commodities: []driver{
+		with(crude, -1, "energy and freight are input costs"),
+	}},
+	{Name: "Technology", ETF: "XLK.US", Keywords: []string{"tech", "semiconductor", "software"}, Commodities: []driver{
+		with(copper, 1, "copper tracks global electronics and capex demand"),
+	}},
+	{Name: "Communication Services", ETF: "XLC.US", Keywords: []string{"communication", "media", "telecom", "internet"}},
+	{Name: "Financials", ETF: "XLF.US", Keywords: []string{"financ", "bank", "insurance"}, Commodities: []driver{
+		with(copper, 1, "rising copper signals growth and loan demand"),
+	}},
+	{Name: "Health Care", ETF: "XLV.US", Keywords: []string{"health", "pharma", "biotech", "medical"}},
+	{Name: "Real Estate", ETF: "XLRE.US", Keywords: []string{"real estate", "reit", "property"}},
+	{Name: "Utilities", ETF: "XLU.US", Keywords: []string{"utilit"}, Commodities: []driver{
+		with(natgas, -1, "natural gas is a generation input cost"),
+	}},
+}
+
+// contextCommodities are shown when the sector has no commodity of its own;
+// they have no fixed direction for the stock.
+var contextCommodities = []driver{
+	with(crude, 0, "oil shapes inflation expectations and rates"),
+	with(gold, 0, "gold rises on fear and falling real yields"),
+}
+
+// benchmarks stand in for the sector when it is unknown.
+var benchmarks = map[string]driver{
+	"US": {Symbol: "SPY.US", Name: "S&P 500 (SPY)"},
+	"HK": {Symbol: "2800.HK", Name: "Hang Seng Index (2800.HK)"},
+	"CN": {Symbol: "510300.SH", Name: "CSI 300 (510300.SH)"},
+}
+
+// lookup matches a free-form sector name to a known sector, nil when none
+// matches.
+func lookup(name string) *sector {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return nil
+	}
+	for i, s := range sectors {
+		for _, k := range s.Keywords {
+			if strings.Contains(name, k) {
+				return &sectors[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Analyze compares symbol with its drivers over the last lookback bars on or
+// before asOf. An unknown sector falls back to the market benchmark and the
+// context commodities. Drivers without data are listed as missing; it fails
+// only when the stock itself has too little history.
+func Analyze(ctx context.Context, fetch dataflows.BarFetcher, symbol, sectorName string, lookback int, asOf string) (*models.IntermarketReport, error) {
+	if err := checkLookback(lookback); err != nil {
+		return nil, err
+	}
+	if lookback == 0 {
+		lookback = DefaultLookback
+	}
+	bars, err := fetch(ctx, symbol, lookback+fetchMargin)
+	if err != nil {
+		return nil, err
+	}
+	bars = dataflows.BarsUpTo(bars, asOf)
+	if len(bars) > lookback+1 {
+		bars = bars[len(bars)-lookback-1:]
+	}
+	if len(bars) < minBars {
+		return nil, fmt.Errorf("not enough price history for %s: %d bars", symbol, len(bars))
+	}
+	start, end := bars[0].Date, bars[len(bars)-1].Date
+	stock := dataflows.DailyReturns(bars)
+
+	r := &models.IntermarketReport{Symbol: symbol, AsOf: end, Lookback: lookback, Return: change(bars)}
+	first := driver{Relation: 1, Why: "the sector's flows and earnings cycle carry its members"}
+	commodities := contextCommodities
+	if s := lookup(sectorName); s != nil {
+		r.Sector = s.Name
+		first.Symbol, first.Name = s.ETF, s.Name+" sector ("+strings.TrimSuffix(s.ETF, ".US")+")"
+		if len(s.Commodities) > 0 {
+			commodities = s.Commodities
+		}
+	} else {
+		b := benchmarks[marketOf(symbol)]
+		first.Symbol, first.Name, first.Why = b.Symbol, b.Name, "the broad market sets the tide for single stocks"
+	}
+
+	for i, d := range append([]driver{first, dollar}, commodities...) {
+		role := models.DriverCommodity
+		switch {
+		case i == 0:
+			role = models.DriverSector
+		case d.Symbol == dollar.Symbol:
+			role = models.DriverDollar
+		}
+		db, err := fetch(ctx, d.Symbol, lookback+fetchMargin)
+		if err != nil {
+			r.Missing = append(r.Missing, d.Symbol)
+			continue
+		}
+		db = between(db, start, end)
+		if len(db) < minBars {
+			r.Missing = append(r.Missing, d.Symbol)
+			continue
+		}
+		corr, _ := dataflows.Correlation(stock, dataflows.DailyReturns(db), minBars)
+		entry := models.IntermarketDriver{
+			Symbol:      d.Symbol,
+			Name:        d.Name,
+			Role:        role,
+			Relation:    d.Relation,
+			Return:      change(db),
+			Correlation: corr,
+			Why:         d.Why,
+		}
+		entry.Signal = signal(entry)
+		switch entry.Signal {
+		case models.SignalTailwind:
+			r.Supportive++
+		case models.SignalHeadwind:
+			r.Conflicting++
+		}
+		if role == models.DriverSector {
+			r.RelativeToSector = r.Return - entry.Return
+		}
+		r.Drivers = append(r.Drivers, entry)
+	}
+	if len(r.Drivers) == 0 {
+		return nil, fmt.Errorf("no intermarket data for %s", symbol)
+	}
+	switch {
+	case r.Supportive > r.Conflicting:
+		r.Verdict = models.IntermarketSupportive
+	case r.Conflicting > r.Supportive:
+		r.Verdict = models.IntermarketConflicting
+	default:
+		r.Verdict = models.IntermarketMixed
+	}
+	annotate(r)
+	return r, nil
+}
+
+func checkLookback(lookback int) error {
+	if lookback != 0 && (lookback < MinLookback || lookback > MaxLookback) {
+		return fmt.Errorf("lookback must be between %d and %d bars, got %d", MinLookback, MaxLookback, lookback)
+	}
+	return nil
+}
+
+// signal reads a driver's move as a tailwind or headwind for a long.
+func signal(d models.IntermarketDriver) string {
+	if d.Relation == 0 {
+		return models.SignalContext
+	}
+	threshold := driverMove
+	if d.Role == models.DriverDollar {
+		threshold = dollarMove
+	}
+	switch effect := float64(d.Relation) * d.Return; {
+	case effect >= threshold:
+		return models.SignalTailwind
+	case effect <= -threshold:
+		return models.SignalHeadwind
+	}
+	return models.SignalNeutral
+}
+
+// annotate adds notes where the stock and its drivers disagree.
+func annotate(r *models.IntermarketReport) {
+	switch {
+	case r.Return >= bigMove && r.Conflicting > r.Supportive:
+		r.Notes = append(r.Notes, fmt.Sprintf("%s rose %.1f%% against intermarket headwinds: either a strong company-specific story or a move that may fade when its drivers reassert", r.Symbol, r.Return))
+	case r.Return <= -bigMove && r.Supportive > r.Conflicting:
+		r.Notes = append(r.Notes, fmt.Sprintf("%s fell %.1f%% despite intermarket tailwinds: the weakness is company-specific", r.Symbol, -r.Return))
+	}
+	for _, d := range r.Drivers {
+		if d.Role != models.DriverSector {
+			continue
+		}
+		switch {
+		case r.RelativeToSector >= bigMove:
+			r.Notes = append(r.Notes, fmt.Sprintf("outperformed %s by %.1f points: a relative-strength leader", d.Name, r.RelativeToSector))
+		case r.RelativeToSector <= -bigMove:
+			r.Notes = append(r.Notes, fmt.Sprintf("lagged %s by %.1f points: a relative-strength laggard", d.Name, -r.RelativeToSector))
+		}
+		if d.Correlation != 0 && d.Correlation < looseSectorCorr {
+			r.Notes = append(r.Notes, fmt.Sprintf("daily returns correlate only %.2f with %s, so its signal carries less weight", d.Correlation, d.Name))
+		}
+	}
+}
+
+// Render formats the report as markdown for the agents.
+func Render(r *models.IntermarketReport) string {
+	var b strings.Builder
+	sectorName := r.Sector
+	if sectorName == "" {
+		sectorName = "unknown sector, compared with the market"
+	}
+	fmt.Fprintf(&b, "# Intermarket Analysis: %s (%s)\n\n", r.Symbol, sectorName)
+	fmt.Fprintf(&b, "**Window:** %d bars to %s | **%s:** %+.1f%% | **vs sector/benchmark:** %+.1f pts\n\n", r.Lookback, r.AsOf, r.Symbol, r.Return, r.RelativeToSector)
+	b.WriteString("| Driver | Return | Correlation | For a long | Why |\n|---|---|---|---|---|\n")
+	for _, d := range r.Drivers {
+		fmt.Fprintf(&b, "| %s | %+.1f%% | %.2f | %s | %s |\n", d.Name, d.Return, d.Correlation, d.Signal, d.Why)
+	}
+	fmt.Fprintf(&b, "\n**Verdict:** %s (%d tailwinds, %d headwinds)\n", r.Verdict, r.Supportive, r.Conflicting)
+	if len(r.Missing) > 0 {
+		fmt.Fprintf(&b, "\nNo data for: %s\n", strings.Join(r.Missing, ", "))
+	}
+	if len(r.Notes) > 0 {
+		b.WriteString("\n## Notes\n\n")
+		for _, n := range r.Notes {
+			fmt.Fprintf(&b, "- %s\n", n)
+		}
+	}
+	return b.String()
+}
+
+// marketOf maps a ticker's suffix to its market; bare tickers are US.
+func marketOf(symbol string) string {
+	i := strings.LastIndex(symbol, ".")
+	if i < 0 {
+		return "US"
+	}
+	switch strings.ToUpper(symbol[i+1:]) {
+	case "HK":
+		return "HK"
+	case "SH", "SZ":
+		return "CN"
+	}
+	return "US"
+}
+
+// between keeps the bars dated from start to end, sorted oldest first.
+func between(bars []*models.MarketData, start, end string) []*models.MarketData {
+	kept := dataflows.BarsUpTo(bars, end)
+	i := sort.Search(len(kept), func(i int) bool { return kept[i].Date >= start })
+	return kept[i:]
+}
+
+// change is the percent move from the first to the last close.
+func change(bars []*models.MarketData) float64 {
+	return (bars[len(bars)-1].Close/bars[0].Close - 1) * 100
+}
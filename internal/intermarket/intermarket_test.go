@@ -0,0 +1,64 @@
+package intermarket
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/internal/bartest"
+	"github.com/dyike/CortexGo/models"
+)
+
+func walk(n int, step func(i int) float64) []*models.MarketData {
+	return bartest.Bars(bartest.Walk(n, step))
+}
+
+func TestAnalyzeEnergyStock(t *testing.T) {
+	wiggle := func(i int) float64 { return 0.01 * math.Sin(float64(i)) }
+	data := map[string][]*models.MarketData{
+		// the stock rallies with its sector and crude while the dollar firms
+		"XOM.US": walk(90, func(i int) float64 { return 0.004 + wiggle(i) }),
+		"XLE.US": walk(90, func(i int) float64 { return 0.002 + wiggle(i) }),
+		"USO.US": walk(90, func(i int) float64 { return 0.003 + 0.5*wiggle(i) }),
+		"UUP.US": walk(90, func(i int) float64 { return 0.0005 }),
+	}
+	r, err := Analyze(context.Background(), bartest.Fetcher(data), "XOM.US", "Energy", 40, "")
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if r.Sector != "Energy" || r.Lookback != 40 || len(r.Drivers) != 3 || len(r.Missing) != 1 || r.Missing[0] != "UNG.US" {
+		t.Fatalf("report = %+v", r)
+	}
+	signals := map[string]string{}
+	for _, d := range r.Drivers {
+		signals[d.Symbol] = d.Signal
+	}
+	if signals["XLE.US"] != models.SignalTailwind || signals["USO.US"] != models.SignalTailwind || signals["UUP.US"] != models.SignalHeadwind {
+		t.Errorf("signals = %v", signals)
+	}
+	if r.Supportive != 2 || r.Conflicting != 1 || r.Verdict != models.IntermarketSupportive || r.Drivers[0].Correlation < 0.9 {
+		t.Errorf("verdict %s (%d/%d), sector correlation %.2f", r.Verdict, r.Supportive, r.Conflicting, r.Drivers[0].Correlation)
+	}
+	if r.RelativeToSector < bigMove || !strings.Contains(strings.Join(r.Notes, "\n"), "relative-strength leader") {
+		t.Errorf("relative %.1f, notes %q", r.RelativeToSector, r.Notes)
+	}
+	if out := Render(r); !strings.Contains(out, "| Energy sector (XLE) |") || !strings.Contains(out, "No data for: UNG.US") {
+		t.Errorf("render:\n%s", out)
+	}
+}
+
+func TestAnalyzeFallsBackToBenchmark(t *testing.T) {
+	flat := walk(90, func(int) float64 { return 0 })
+	data := map[string][]*models.MarketData{"0700.HK": flat, "2800.HK": flat, "UUP.US": flat, "USO.US": flat, "GLD.US": flat}
+	r, err := Analyze(context.Background(), bartest.Fetcher(data), "0700.HK", "", 0, flat[70].Date)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if r.Sector != "" || r.Drivers[0].Symbol != "2800.HK" || r.Drivers[2].Signal != models.SignalContext || r.Verdict != models.IntermarketMixed || r.AsOf != flat[70].Date {
+		t.Errorf("report = %+v", r)
+	}
+	if _, err := Analyze(context.Background(), bartest.Fetcher(data), "0700.HK", "", 5, ""); err == nil {
+		t.Error("want error for a lookback below the minimum")
+	}
+}
@@ -0,0 +1,137 @@
+// Package journal records the trades users actually made on the back of an
+// analysis, so the scoreboard can show realized results next to the
+// recommendations.
+package journal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+const dateLayout = "2006-01-02"
+
+// New builds an entry from add parameters. When the entry is linked to a run,
+// session and recommendation fill in the symbol and the side the user left
+// out; today is the default entry date.
+func New(params models.JournalAddParams, session *models.SessionRecord, recommendation, today string) (*models.JournalEntry, error) {
+	e := &models.JournalEntry{
+		Symbol:         dataflows.NormalizeSymbol(params.Symbol),
+		Side:           strings.ToLower(strings.TrimSpace(params.Side)),
+		Recommendation: strings.ToUpper(recommendation),
+		Quantity:       params.Quantity,
+		EntryPrice:     params.EntryPrice,
+		EntryDate:      strings.TrimSpace(params.EntryDate),
+		Fees:           params.Fees,
+		Currency:       strings.ToUpper(strings.TrimSpace(params.Currency)),
+		Notes:          strings.TrimSpace(params.Notes),
+	}
+	if session != nil {
+		e.SessionId = session.Id
+		if e.Symbol == "" {
+			e.Symbol = session.Symbol
+		} else if !strings.EqualFold(withMarket(e.Symbol), withMarket(session.Symbol)) {
+			return nil, fmt.Errorf("symbol %s does not match run %d (%s)", e.Symbol, session.Id, session.Symbol)
+		}
+	}
+	if err := dataflows.ValidateSymbol(e.Symbol); err != nil {
+		return nil, err
+	}
+	e.Symbol = withMarket(e.Symbol)
+
+	if e.Side == "" {
+		e.Side = models.JournalLong
+		if e.Recommendation == "SELL" {
+			e.Side = models.JournalShort
+		}
+	}
+	if e.Side != models.JournalLong && e.Side != models.JournalShort {
+		return nil, fmt.Errorf("invalid side %q: want long or short", params.Side)
+	}
+	if e.Quantity <= 0 {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+	if e.EntryPrice <= 0 {
+		return nil, fmt.Errorf("entry price must be positive")
+	}
+	if e.Fees < 0 {
+		return nil, fmt.Errorf("fees must not be negative")
+	}
+	if e.EntryDate == "" {
+		e.EntryDate = today
+	}
+	if _, err := time.Parse(dateLayout, e.EntryDate); err != nil {
+		return nil, fmt.Errorf("invalid entry date %q: want YYYY-MM-DD", e.EntryDate)
+	}
+	if e.Currency == "" {
+		e.Currency = dataflows.CurrencyOf(e.Symbol)
+	}
+	if params.ExitPrice != 0 || params.ExitDate != "" {
+		if err := Close(e, models.JournalCloseParams{ExitPrice: params.ExitPrice, ExitDate: params.ExitDate}, today); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Close records the exit of an open entry and settles its P&L. Exit fees add
+// to the entry fees and notes are appended.
+func Close(e *models.JournalEntry, params models.JournalCloseParams, today string) error {
+	if e.Closed() {
+		return fmt.Errorf("journal entry %d is already closed", e.Id)
+	}
+	if params.ExitPrice <= 0 {
+		return fmt.Errorf("exit price must be positive")
+	}
+	if params.Fees < 0 {
+		return fmt.Errorf("fees must not be negative")
+	}
+	date := strings.TrimSpace(params.ExitDate)
+	if date == "" {
+		date = today
+	}
+	if _, err := time.Parse(dateLayout, date); err != nil {
+		return fmt.Errorf("invalid exit date %q: want YYYY-MM-DD", date)
+	}
+	// Dates are YYYY-MM-DD, so string order is date order.
+	if date < e.EntryDate {
+		return fmt.Errorf("exit date %s is before entry date %s", date, e.EntryDate)
+	}
+	e.ExitPrice, e.ExitDate = params.ExitPrice, date
+	e.Fees += params.Fees
+	if notes := strings.TrimSpace(params.Notes); notes != "" {
+		if e.Notes != "" {
+			e.Notes += "\n"
+		}
+		e.Notes += notes
+	}
+	Settle(e)
+	return nil
+}
+
+// Settle computes the realized P&L net of fees and the return on the entry
+// value of a closed entry; open entries are left at zero.
+func Settle(e *models.JournalEntry) {
+	e.RealizedPnL, e.ReturnPct = 0, 0
+	if !e.Closed() || e.EntryPrice <= 0 || e.Quantity <= 0 {
+		return
+	}
+	move := e.ExitPrice - e.EntryPrice
+	if e.Side == models.JournalShort {
+		move = -move
+	}
+	e.RealizedPnL = move*e.Quantity - e.Fees
+	e.ReturnPct = e.RealizedPnL / (e.EntryPrice * e.Quantity) * 100
+}
+
+// withMarket defaults bare tickers to the US market, as elsewhere.
+func withMarket(symbol string) string {
+	symbol = dataflows.NormalizeSymbol(symbol)
+	if symbol != "" && !strings.Contains(symbol, ".") {
+		symbol += ".US"
+	}
+	return symbol
+}
@@ -0,0 +1,65 @@
+package journal
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+func TestNewFillsFromLinkedRun(t *testing.T) {
+	session := &models.SessionRecord{Id: 7, Symbol: "700.HK"}
+	e, err := New(models.JournalAddParams{Quantity: 100, EntryPrice: 400}, session, "sell", "2025-12-15")
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if e.SessionId != 7 || e.Symbol != "700.HK" || e.Side != models.JournalShort || e.Recommendation != "SELL" ||
+		e.Currency != "HKD" || e.EntryDate != "2025-12-15" || e.Closed() {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+
+	if _, err := New(models.JournalAddParams{Symbol: "MSFT", Quantity: 1, EntryPrice: 1}, &models.SessionRecord{Id: 8, Symbol: "AAPL.US"}, "BUY", "2025-12-15"); err == nil {
+		t.Error("expected an error for a symbol that does not match the run")
+	}
+	e, err = New(models.JournalAddParams{Symbol: "aapl", Quantity: 1, EntryPrice: 1}, &models.SessionRecord{Id: 8, Symbol: "AAPL.US"}, "", "2025-12-15")
+	if err != nil || e.Symbol != "AAPL.US" || e.Side != models.JournalLong || e.Currency != "USD" {
+		t.Fatalf("bare ticker should match the US run: %+v, %v", e, err)
+	}
+
+	for name, p := range map[string]models.JournalAddParams{
+		"quantity":   {Symbol: "AAPL.US", EntryPrice: 1},
+		"price":      {Symbol: "AAPL.US", Quantity: 1},
+		"side":       {Symbol: "AAPL.US", Quantity: 1, EntryPrice: 1, Side: "flat"},
+		"date":       {Symbol: "AAPL.US", Quantity: 1, EntryPrice: 1, EntryDate: "15/12/2025"},
+		"exit order": {Symbol: "AAPL.US", Quantity: 1, EntryPrice: 1, EntryDate: "2025-12-15", ExitPrice: 2, ExitDate: "2025-12-01"},
+	} {
+		if _, err := New(p, nil, "", "2025-12-15"); err == nil {
+			t.Errorf("%s: expected a validation error", name)
+		}
+	}
+}
+
+func TestCloseSettlesPnL(t *testing.T) {
+	long, err := New(models.JournalAddParams{Symbol: "AAPL.US", Quantity: 10, EntryPrice: 100, Fees: 1, Notes: "earnings"}, nil, "BUY", "2025-12-01")
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := Close(long, models.JournalCloseParams{ExitPrice: 110, Fees: 1, Notes: "took profit"}, "2025-12-15"); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	// (110 - 100) * 10 - 2 = 98 on 1000 entry value
+	if long.ExitDate != "2025-12-15" || long.RealizedPnL != 98 || math.Abs(long.ReturnPct-9.8) > 1e-9 || long.Notes != "earnings\ntook profit" {
+		t.Fatalf("unexpected long settlement: %+v", long)
+	}
+	if err := Close(long, models.JournalCloseParams{ExitPrice: 120}, "2025-12-16"); err == nil {
+		t.Error("closing twice should fail")
+	}
+
+	short, err := New(models.JournalAddParams{Symbol: "TSLA.US", Side: "short", Quantity: 5, EntryPrice: 200, ExitPrice: 220, ExitDate: "2025-12-10"}, nil, "", "2025-12-01")
+	if err != nil {
+		t.Fatalf("new closed short: %v", err)
+	}
+	if short.RealizedPnL != -100 || short.ReturnPct != -10 {
+		t.Fatalf("unexpected short settlement: %+v", short)
+	}
+}
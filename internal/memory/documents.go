@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/pdftext"
+)
+
+// maxDocumentBytes bounds the files IngestFile accepts.
+const maxDocumentBytes = 200 << 20
+
+// DocumentExtensions lists the file types IngestFile can read.
+var DocumentExtensions = []string{".pdf", ".txt", ".md", ".markdown", ".html", ".htm"}
+
+// DocumentQuery selects ingested document passages to search.
+type DocumentQuery struct {
+	Text   string
+	Symbol string // optional; documents ingested without a symbol always match
+	Kind   string // optional, one of the models.DocumentKind* values
+	Limit  int    // default 5
+}
+
+// IngestFile extracts the text of the file at path, chunks and embeds it and
+// stores it with the metadata in meta. Ingesting the same file again
+// replaces the earlier copy.
+func IngestFile(ctx context.Context, store *storage.Store, embedder Embedder, path string, meta models.DocumentRecord) (*models.DocumentRecord, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxDocumentBytes {
+		return nil, fmt.Errorf("%s is %d MB, larger than the %d MB limit", path, info.Size()>>20, maxDocumentBytes>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := ExtractText(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var chunks []models.DocumentChunk
+	for _, p := range pages {
+		for _, text := range splitParagraphs(p.Text, maxChunkRunes) {
+			chunks = append(chunks, models.DocumentChunk{Seq: len(chunks), Page: p.Number, Content: text})
+		}
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("no text found in %s; scanned documents need OCR before ingestion", path)
+	}
+
+	doc := meta
+	doc.Source = path
+	sum := sha256.Sum256(data)
+	doc.Sha256 = hex.EncodeToString(sum[:])
+	if strings.EqualFold(filepath.Ext(path), ".pdf") {
+		doc.Pages = len(pages)
+	}
+	if doc.Title == "" {
+		doc.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if doc.Kind == "" {
+		doc.Kind = models.DocumentKindOther
+	}
+
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		texts[i] = doc.Title + "\n" + c.Content
+	}
+	vectors, err := embedder.Embed(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed document: %w", err)
+	}
+	for i := range chunks {
+		chunks[i].Embedder = embedder.Name()
+		chunks[i].Vector = vectors[i]
+	}
+	if err := store.SaveDocument(ctx, &doc, chunks); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// ExtractText returns the text of a document by file extension. PDF pages
+// are numbered from 1; other formats are returned as a single page 0.
+func ExtractText(path string, data []byte) ([]pdftext.Page, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pdf":
+		return pdftext.Extract(data)
+	case ".txt", ".md", ".markdown":
+		return []pdftext.Page{{Text: strings.ReplaceAll(string(data), "\r\n", "\n")}}, nil
+	case ".html", ".htm":
+		text, err := htmlText(data)
+		if err != nil {
+			return nil, err
+		}
+		return []pdftext.Page{{Text: text}}, nil
+	}
+	return nil, fmt.Errorf("unsupported document type %q (want %s)", filepath.Ext(path), strings.Join(DocumentExtensions, ", "))
+}
+
+// htmlText keeps block boundaries of an HTML filing as paragraph breaks.
+func htmlText(data []byte) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("parse html: %w", err)
+	}
+	doc.Find("script, style, noscript, head").Remove()
+	doc.Find("p, div, li, tr, br, h1, h2, h3, h4, h5, h6, table, section, article").Each(func(_ int, s *goquery.Selection) {
+		s.AfterHtml("\n\n")
+	})
+	var paras []string
+	for _, para := range strings.Split(doc.Text(), "\n\n") {
+		if para = strings.Join(strings.Fields(para), " "); para != "" {
+			paras = append(paras, para)
+		}
+	}
+	return strings.Join(paras, "\n\n"), nil
+}
+
+// SearchDocuments returns the ingested passages most similar to q.Text, at
+// most three per document.
+func SearchDocuments(ctx context.Context, store *storage.Store, embedder Embedder, q DocumentQuery) ([]models.DocumentPassage, error) {
+	if strings.TrimSpace(q.Text) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if q.Limit <= 0 {
+		q.Limit = 5
+	}
+	vectors, err := embedder.Embed(ctx, []string{q.Text})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	chunks, err := store.ListDocumentChunks(ctx, models.DocumentChunkFilter{Symbol: q.Symbol, Kind: q.Kind, Embedder: embedder.Name()})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.DocumentPassage, 0, len(chunks))
+	for _, c := range chunks {
+		score := cosine(vectors[0], c.Vector)
+		if score <= 0 {
+			continue
+		}
+		hits = append(hits, models.DocumentPassage{
+			DocumentID: c.DocumentId,
+			Title:      c.Title,
+			Symbol:     c.Symbol,
+			Kind:       c.Kind,
+			Page:       c.Page,
+			Content:    c.Content,
+			Score:      score,
+		})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+
+	perDocument := map[int64]int{}
+	out := hits[:0]
+	for _, h := range hits {
+		if perDocument[h.DocumentID] == 3 {
+			continue
+		}
+		perDocument[h.DocumentID]++
+		out = append(out, h)
+		if len(out) == q.Limit {
+			break
+		}
+	}
+	return out, nil
+}
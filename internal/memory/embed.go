@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Embedder turns text into fixed-length, L2-normalized vectors.
+type Embedder interface {
+	// Name identifies the model; vectors from different embedders are never compared.
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// HashEmbedder is a local feature-hashing embedder: words (and character
+// bigrams for CJK text) are hashed into Dim signed buckets weighted by
+// 1+log(tf). It needs no model or network, works offline, and is good enough
+// to find past reports on the same topic; swap in a neural embedder for
+// semantic recall.
+type HashEmbedder struct {
+	Dim int
+}
+
+// DefaultEmbedder is used when no other embedder is configured.
+var DefaultEmbedder Embedder = HashEmbedder{Dim: 1024}
+
+func (h HashEmbedder) Name() string { return fmt.Sprintf("hash-%d", h.Dim) }
+
+func (h HashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = h.embed(text)
+	}
+	return out, nil
+}
+
+func (h HashEmbedder) embed(text string) []float32 {
+	counts := map[string]int{}
+	for _, tok := range tokenize(text) {
+		counts[tok]++
+	}
+	vec := make([]float32, h.Dim)
+	for tok, n := range counts {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(tok))
+		sum := hasher.Sum64()
+		weight := float32(1 + math.Log(float64(n)))
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		vec[sum%uint64(h.Dim)] += weight
+	}
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v) * float64(v)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range vec {
+			vec[i] *= scale
+		}
+	}
+	return vec
+}
+
+// tokenize lowercases latin words and digits, drops stop words, and emits
+// overlapping bigrams for runs of Han characters, which have no spaces.
+func tokenize(text string) []string {
+	var (
+		tokens []string
+		word   []rune
+		han    []rune
+	)
+	flushWord := func() {
+		if len(word) > 1 {
+			if w := string(word); !stopWords[w] {
+				tokens = append(tokens, w)
+			}
+		}
+		word = word[:0]
+	}
+	flushHan := func() {
+		if len(han) == 1 {
+			tokens = append(tokens, string(han))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			tokens = append(tokens, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return tokens
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"are": true, "was": true, "were": true, "has": true, "have": true, "from": true,
+	"its": true, "but": true, "not": true, "will": true, "been": true, "into": true,
+	"of": true, "to": true, "in": true, "on": true, "is": true, "as": true, "at": true,
+	"by": true, "an": true, "or": true, "be": true, "it": true,
+}
+
+// cosine of two normalized vectors is their dot product.
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
@@ -0,0 +1,209 @@
+// Package memory indexes stored analysis reports and ingested documents as
+// embedded chunks so agents can retrieve relevant past findings
+// (search_past_analyses) and ground claims in filings (query_documents).
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+// maxChunkRunes bounds a chunk; sections are split on paragraph breaks.
+const maxChunkRunes = 1200
+
+// backfillBatch caps how many unindexed reports one search embeds.
+const backfillBatch = 200
+
+// Query selects past analyses to search.
+type Query struct {
+	Text   string
+	Symbol string // optional, matched like the history filter
+	Before string // optional YYYY-MM-DD; only analyses traded before it, to avoid look-ahead
+	Limit  int    // default 5
+}
+
+// Index replaces the stored chunks of sessionID with those of rep. With a
+// cfg it also keeps the chunks as a document in cfg's storage, and reuses the
+// vectors of an earlier document for the same text instead of embedding again.
+func Index(ctx context.Context, cfg *config.Config, store *storage.Store, embedder Embedder, sessionID int64, rep *report.Report) error {
+	chunks := Chunk(rep)
+	if len(chunks) == 0 {
+		return nil
+	}
+	vectors := storedVectors(ctx, cfg, embedder.Name(), sessionID, chunks)
+	if vectors == nil {
+		texts := make([]string, len(chunks))
+		for i, c := range chunks {
+			texts[i] = c.Section + "\n" + c.Content
+		}
+		var err error
+		if vectors, err = embedder.Embed(ctx, texts); err != nil {
+			return fmt.Errorf("embed report %d: %w", sessionID, err)
+		}
+		defer func() {
+			// the document is a copy; agent.db already has the chunks
+			if err := saveChunks(ctx, cfg, embedder.Name(), sessionID, chunks); err != nil {
+				fmt.Printf("save memory document session=%d err=%v\n", sessionID, err)
+			}
+		}()
+	}
+	for i := range chunks {
+		chunks[i].SessionId = sessionID
+		chunks[i].Embedder = embedder.Name()
+		chunks[i].Vector = vectors[i]
+	}
+	return store.ReplaceChunks(ctx, sessionID, chunks)
+}
+
+// Chunk splits a report into section chunks of at most maxChunkRunes.
+func Chunk(rep *report.Report) []models.ReportChunk {
+	if rep == nil {
+		return nil
+	}
+	var chunks []models.ReportChunk
+	for _, sec := range rep.Sections {
+		for _, text := range splitParagraphs(sec.Content, maxChunkRunes) {
+			chunks = append(chunks, models.ReportChunk{
+				Seq:       len(chunks),
+				Symbol:    rep.Symbol,
+				TradeDate: rep.TradeDate,
+				Section:   sec.Title,
+				Content:   text,
+			})
+		}
+	}
+	return chunks
+}
+
+// splitParagraphs packs paragraphs into chunks of at most limit runes. An
+// oversized paragraph is split on line breaks, then on rune boundaries.
+func splitParagraphs(text string, limit int) []string {
+	return pack(text, []string{"\n\n", "\n"}, limit)
+}
+
+func pack(text string, seps []string, limit int) []string {
+	var (
+		out []string
+		cur strings.Builder
+		sep = seps[0]
+	)
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			out = append(out, s)
+		}
+		cur.Reset()
+	}
+	for _, part := range strings.Split(text, sep) {
+		if utf8.RuneCountInString(part) > limit {
+			flush()
+			if len(seps) > 1 {
+				out = append(out, pack(part, seps[1:], limit)...)
+				continue
+			}
+			runes := []rune(part)
+			for len(runes) > limit {
+				cur.WriteString(string(runes[:limit]))
+				flush()
+				runes = runes[limit:]
+			}
+			part = string(runes)
+		}
+		if cur.Len() > 0 && utf8.RuneCountInString(cur.String())+len(sep)+utf8.RuneCountInString(part) > limit {
+			flush()
+		}
+		if cur.Len() > 0 {
+			cur.WriteString(sep)
+		}
+		cur.WriteString(part)
+	}
+	flush()
+	return out
+}
+
+// Search embeds reports that have not been indexed yet, then returns the
+// chunks most similar to q.Text, at most two per past analysis.
+func Search(ctx context.Context, cfg *config.Config, store *storage.Store, embedder Embedder, q Query) ([]models.PastAnalysis, error) {
+	if strings.TrimSpace(q.Text) == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if q.Limit <= 0 {
+		q.Limit = 5
+	}
+	if err := Backfill(ctx, cfg, store, embedder); err != nil {
+		return nil, err
+	}
+
+	vectors, err := embedder.Embed(ctx, []string{q.Text})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	chunks, err := store.ListChunks(ctx, models.ChunkFilter{Symbol: q.Symbol, Before: q.Before, Embedder: embedder.Name()})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.PastAnalysis, 0, len(chunks))
+	for _, c := range chunks {
+		score := cosine(vectors[0], c.Vector)
+		if score <= 0 {
+			continue
+		}
+		hits = append(hits, models.PastAnalysis{
+			SessionID:      c.SessionId,
+			Symbol:         c.Symbol,
+			TradeDate:      c.TradeDate,
+			Recommendation: c.Recommendation,
+			Section:        c.Section,
+			Content:        c.Content,
+			Score:          score,
+		})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+
+	perSession := map[int64]int{}
+	out := hits[:0]
+	for _, h := range hits {
+		if perSession[h.SessionID] == 2 {
+			continue
+		}
+		perSession[h.SessionID]++
+		out = append(out, h)
+		if len(out) == q.Limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Backfill indexes stored reports that have no chunks for embedder, such as
+// reports saved before indexing existed or imported by results.sync.
+func Backfill(ctx context.Context, cfg *config.Config, store *storage.Store, embedder Embedder) error {
+	recs, err := store.ListUnindexedReports(ctx, embedder.Name(), backfillBatch)
+	if err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		rep, err := report.Decode(rec.Content)
+		if err != nil {
+			continue
+		}
+		if rep.Symbol == "" {
+			rep.Symbol = rec.Symbol
+		}
+		if rep.TradeDate == "" {
+			rep.TradeDate = rec.TradeDate
+		}
+		if err := Index(ctx, cfg, store, embedder, rec.SessionId, rep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
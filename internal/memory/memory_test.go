@@ -0,0 +1,187 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+func seedReport(t *testing.T, store *storage.Store, rep *report.Report) int64 {
+	t.Helper()
+	ctx := context.Background()
+	id, err := store.CreateSession(ctx, &models.SessionRecord{Symbol: rep.Symbol, TradeDate: rep.TradeDate, Status: storage.StatusDone})
+	if err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+	content, _ := json.Marshal(rep)
+	if err := store.SaveReport(ctx, &models.ReportRecord{SessionId: id, Symbol: rep.Symbol, TradeDate: rep.TradeDate, Recommendation: rep.Recommendation, Content: string(content)}); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+	return id
+}
+
+func TestChunkSplitsLongSections(t *testing.T) {
+	para := strings.Repeat("x", 700)
+	rep := &report.Report{Symbol: "AAPL.US", TradeDate: "2024-05-10", Sections: []report.Section{
+		{Title: "News", Content: para + "\n\n" + para},
+		{Title: "Empty"},
+		{Title: "Huge", Content: strings.Repeat("y", 2*maxChunkRunes+1)},
+	}}
+	chunks := Chunk(rep)
+	if len(chunks) != 5 {
+		t.Fatalf("chunks = %d, want 5", len(chunks))
+	}
+	for i, c := range chunks {
+		if c.Seq != i || c.Symbol != "AAPL.US" {
+			t.Errorf("chunk %d = %+v", i, c)
+		}
+		if n := len([]rune(c.Content)); n > maxChunkRunes {
+			t.Errorf("chunk %d has %d runes", i, n)
+		}
+	}
+}
+
+func TestSearchRanksAndFiltersByDate(t *testing.T) {
+	store, err := storage.NewStore(filepath.Join(t.TempDir(), "agent.db"))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	earnings := seedReport(t, store, &report.Report{Symbol: "AAPL.US", TradeDate: "2024-02-02", Recommendation: "SELL", Sections: []report.Section{
+		{Title: "News", Content: "Earnings missed guidance; iPhone revenue fell in China and margins compressed."},
+		{Title: "Social", Content: "Retail chatter focused on the Vision Pro launch."},
+	}})
+	seedReport(t, store, &report.Report{Symbol: "AAPL.US", TradeDate: "2024-06-12", Recommendation: "BUY", Sections: []report.Section{
+		{Title: "News", Content: "Earnings beat guidance after strong iPhone revenue in China."},
+	}})
+	seedReport(t, store, &report.Report{Symbol: "TSLA.US", TradeDate: "2024-01-25", Sections: []report.Section{
+		{Title: "News", Content: "Deliveries slowed and price cuts weighed on margins."},
+	}})
+
+	hits, err := Search(ctx, nil, store, DefaultEmbedder, Query{Text: "iPhone earnings guidance China", Symbol: "AAPL", Before: "2024-05-10"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(hits) == 0 || hits[0].SessionID != earnings || hits[0].Section != "News" {
+		t.Fatalf("hits = %+v", hits)
+	}
+	for _, h := range hits {
+		if h.TradeDate >= "2024-05-10" || h.Symbol != "AAPL.US" {
+			t.Errorf("hit leaks past filter: %+v", h)
+		}
+	}
+	if hits[0].Recommendation != "SELL" {
+		t.Errorf("recommendation = %q", hits[0].Recommendation)
+	}
+
+	// backfill is idempotent once every report is indexed
+	if err := Backfill(ctx, nil, store, DefaultEmbedder); err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	chunks, err := store.ListChunks(ctx, models.ChunkFilter{Embedder: DefaultEmbedder.Name()})
+	if err != nil {
+		t.Fatalf("ListChunks: %v", err)
+	}
+	if len(chunks) != 4 {
+		t.Errorf("chunks = %d, want 4", len(chunks))
+	}
+}
+
+func TestIngestAndSearchDocuments(t *testing.T) {
+	store, err := storage.NewStore(filepath.Join(t.TempDir(), "agent.db"))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	annual := filepath.Join(dir, "aapl-10k.md")
+	os.WriteFile(annual, []byte("# Risk factors\n\nSupply chain concentration in China.\n\n# Segments\n\nServices gross margin reached 74% while products gross margin was 36%."), 0o644)
+	sector := filepath.Join(dir, "semis.html")
+	os.WriteFile(sector, []byte("<html><head><style>p{}</style></head><body><h1>Sector outlook</h1><p>Foundry capacity is tight.</p><p>Gross margin pressure from pricing.</p></body></html>"), 0o644)
+	other := filepath.Join(dir, "tsla.txt")
+	os.WriteFile(other, []byte("Automotive gross margin excluding credits fell."), 0o644)
+
+	doc, err := IngestFile(ctx, store, DefaultEmbedder, annual, models.DocumentRecord{Symbol: "AAPL.US", Kind: models.DocumentKindAnnualReport})
+	if err != nil {
+		t.Fatalf("IngestFile: %v", err)
+	}
+	if doc.Title != "aapl-10k" || doc.Chunks != 1 {
+		t.Errorf("doc = %+v", doc)
+	}
+	if _, err := IngestFile(ctx, store, DefaultEmbedder, sector, models.DocumentRecord{}); err != nil {
+		t.Fatalf("IngestFile html: %v", err)
+	}
+	if _, err := IngestFile(ctx, store, DefaultEmbedder, other, models.DocumentRecord{Symbol: "TSLA.US"}); err != nil {
+		t.Fatalf("IngestFile txt: %v", err)
+	}
+	// ingesting the same file again replaces it
+	if again, err := IngestFile(ctx, store, DefaultEmbedder, annual, models.DocumentRecord{Symbol: "AAPL.US", Title: "FY24 10-K"}); err != nil || again.Id != doc.Id {
+		t.Fatalf("re-ingest = %+v, %v", again, err)
+	}
+
+	hits, err := SearchDocuments(ctx, store, DefaultEmbedder, DocumentQuery{Text: "services gross margin", Symbol: "AAPL"})
+	if err != nil {
+		t.Fatalf("SearchDocuments: %v", err)
+	}
+	if len(hits) != 2 || hits[0].Title != "FY24 10-K" || !strings.Contains(hits[0].Content, "74%") {
+		t.Fatalf("hits = %+v", hits)
+	}
+	if hits[1].Symbol != "" || strings.Contains(hits[1].Content, "p{}") {
+		t.Errorf("unscoped html hit = %+v", hits[1])
+	}
+
+	docs, err := store.ListDocuments(ctx, "")
+	if err != nil || len(docs) != 3 {
+		t.Fatalf("ListDocuments = %+v, %v", docs, err)
+	}
+	if _, err := IngestFile(ctx, store, DefaultEmbedder, filepath.Join(dir, "deck.pptx"), models.DocumentRecord{}); err == nil {
+		t.Error("want error for a missing, unsupported file")
+	}
+}
+
+// failingEmbedder shares DefaultEmbedder's name but cannot embed.
+type failingEmbedder struct{}
+
+func (failingEmbedder) Name() string { return DefaultEmbedder.Name() }
+func (failingEmbedder) Embed(context.Context, []string) ([][]float32, error) {
+	return nil, errors.New("embedder unavailable")
+}
+
+func TestIndexReusesStoredVectors(t *testing.T) {
+	cfg := config.DefaultConfigWithRoot(t.TempDir())
+	store, err := storage.NewStore(filepath.Join(cfg.DataDir, "agent.db"))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	rep := &report.Report{Symbol: "AAPL.US", TradeDate: "2024-02-02", Sections: []report.Section{
+		{Title: "News", Content: "Services revenue grew while iPhone sales slowed."},
+	}}
+	id := seedReport(t, store, rep)
+
+	if err := Index(ctx, cfg, store, DefaultEmbedder, id, rep); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	// a rebuilt index finds the vectors in the memory document
+	if err := Index(ctx, cfg, store, failingEmbedder{}, id, rep); err != nil {
+		t.Fatalf("re-Index: %v", err)
+	}
+	rep.Sections[0].Content += " Margins expanded."
+	if err := Index(ctx, cfg, store, failingEmbedder{}, id, rep); err == nil {
+		t.Fatal("changed text reused stale vectors")
+	}
+}
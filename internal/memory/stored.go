@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/persist"
+	"github.com/dyike/CortexGo/pkg/secure"
+)
+
+// chunkDocument is the stored copy of one report's chunks and vectors, kept
+// in the memory area as <embedder>/<session>.json. agent.db remains the
+// search index; the document lets a rebuilt index reuse the vectors instead
+// of embedding the same text again.
+type chunkDocument struct {
+	Embedder string               `json:"embedder"`
+	Chunks   []models.ReportChunk `json:"chunks"`
+}
+
+func chunkKey(embedder string, sessionID int64) string {
+	return embedder + "/" + strconv.FormatInt(sessionID, 10) + ".json"
+}
+
+// storedVectors returns the vectors saved for sessionID when the saved chunks
+// have exactly the texts of chunks, else nil. A nil cfg keeps no documents.
+func storedVectors(ctx context.Context, cfg *config.Config, embedder string, sessionID int64, chunks []models.ReportChunk) [][]float32 {
+	if cfg == nil {
+		return nil
+	}
+	s, err := persist.For(cfg)
+	if err != nil {
+		return nil
+	}
+	data, _, err := s.Get(ctx, persist.Memory, chunkKey(embedder, sessionID))
+	if err != nil {
+		return nil
+	}
+	cipher, err := secure.ForConfig(cfg)
+	if err != nil {
+		return nil
+	}
+	if data, err = cipher.Open(data); err != nil {
+		return nil
+	}
+	var doc chunkDocument
+	if json.Unmarshal(data, &doc) != nil || doc.Embedder != embedder || len(doc.Chunks) != len(chunks) {
+		return nil
+	}
+	vectors := make([][]float32, len(chunks))
+	for i, c := range doc.Chunks {
+		if c.Section != chunks[i].Section || c.Content != chunks[i].Content || len(c.Vector) == 0 {
+			return nil
+		}
+		vectors[i] = c.Vector
+	}
+	return vectors
+}
+
+// saveChunks writes the chunk document of sessionID, encrypted when an
+// encryption key is configured. A nil cfg keeps no documents.
+func saveChunks(ctx context.Context, cfg *config.Config, embedder string, sessionID int64, chunks []models.ReportChunk) error {
+	if cfg == nil {
+		return nil
+	}
+	s, err := persist.For(cfg)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(chunkDocument{Embedder: embedder, Chunks: chunks})
+	if err != nil {
+		return err
+	}
+	cipher, err := secure.ForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("load encryption key: %w", err)
+	}
+	if data, err = cipher.Seal(data); err != nil {
+		return err
+	}
+	return s.Put(ctx, persist.Memory, chunkKey(embedder, sessionID), data)
+}
+
+// Forget removes the chunk documents of sessionID for every embedder.
+func Forget(ctx context.Context, cfg *config.Config, sessionID int64) error {
+	s, err := persist.For(cfg)
+	if err != nil {
+		return err
+	}
+	keys, err := s.List(ctx, persist.Memory, "")
+	if err != nil {
+		return err
+	}
+	suffix := "/" + strconv.FormatInt(sessionID, 10) + ".json"
+	for _, key := range keys {
+		if strings.HasSuffix(key, suffix) {
+			if err := s.Delete(ctx, persist.Memory, key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
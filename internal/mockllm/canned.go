@@ -0,0 +1,37 @@
+package mockllm
+
+import "github.com/dyike/CortexGo/consts"
+
+// mockNote marks every built-in response so nobody mistakes it for analysis.
+const mockNote = "_Mock LLM response: no model was called and no data was analysed._"
+
+// Canned returns the built-in responses: one final answer per agent, no tool
+// calls, and a HOLD at 50% confidence with a full plan from the trader and the
+// risk judge, so every report section and the decision parser are exercised.
+func Canned() Script {
+	report := func(title string) []Turn {
+		return []Turn{{Content: "## " + title + "\n\n" + mockNote + "\n\n| Item | Reading |\n|---|---|\n| Trend | Neutral |\n| Risk | Moderate |\n"}}
+	}
+	argument := func(side string) []Turn {
+		return []Turn{{Content: side + ": the mock reports point both ways, so this side argues its case on placeholders only.\n\n" + mockNote}}
+	}
+	return Script{
+		DefaultAgent:               {{Content: mockNote}},
+		consts.MarketAnalyst:       report("Market Report"),
+		consts.SocialAnalyst:       report("Social Sentiment Report"),
+		consts.NewsAnalyst:         report("News Report"),
+		consts.FundamentalsAnalyst: report("Fundamentals Report"),
+		consts.BullResearcher:      argument("Bull Analyst"),
+		consts.BearResearcher:      argument("Bear Analyst"),
+		consts.ResearchManager:     {{Content: "## Investment Plan\n\nRecommendation: **HOLD**\n\nNeither side carried the debate.\n\nCONFIDENCE: 0.5\n\n" + mockNote}},
+		consts.Trader: {{Content: "## Trading Plan\n\nENTRY PRICE: 100\nSTOP LOSS: 95\nTAKE PROFIT: 110\nPOSITION SIZE: 5%\nHOLDING PERIOD: 20\n\n" +
+			mockNote + "\n\nFINAL TRANSACTION PROPOSAL: **HOLD**\nCONFIDENCE: 0.5"}},
+		consts.RiskyAnalyst:   argument("Risky Analyst"),
+		consts.SafeAnalyst:    argument("Safe Analyst"),
+		consts.NeutralAnalyst: argument("Neutral Analyst"),
+		consts.RiskJudge: {{Content: "## Final Decision\n\nThe trader's plan stands.\n\nENTRY PRICE: 100\nSTOP LOSS: 95\nTAKE PROFIT: 110\nPOSITION SIZE: 5%\nHOLDING PERIOD: 20\n\n" +
+			mockNote + "\n\nFINAL TRANSACTION PROPOSAL: **HOLD**\nCONFIDENCE: 0.5"}},
+		consts.DeltaAnalyst: {{Content: "## Update\n\n- The new data does not change the thesis.\n\nENTRY PRICE: 100\nSTOP LOSS: 95\nTAKE PROFIT: 110\n\n" +
+			mockNote + "\n\nFINAL TRANSACTION PROPOSAL: **HOLD**\nCONFIDENCE: 0.5"}},
+	}
+}
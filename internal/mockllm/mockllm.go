@@ -0,0 +1,233 @@
+// Package mockllm is a chat model that answers from canned or scripted
+// responses instead of calling an LLM, so the graph, the CLI and the library
+// can be run end to end in CI, or to check a setup, without API keys.
+//
+// Each call is attributed to an agent by its prompt. The agent's turns are
+// played in order within one conversation: the first call gets the first
+// turn, a call that follows tool results gets the next one, and the last turn
+// is repeated (without tool calls) once the script runs out. An agent that
+// runs again, e.g. in a second debate round, starts from its first turn.
+package mockllm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/internal/prompts"
+)
+
+// Name is the model name recorded for runs on the mock provider.
+const Name = "mock"
+
+// DefaultAgent is the script key whose turns answer agents the script does
+// not list, and calls no agent prompt matches.
+const DefaultAgent = "default"
+
+// Turn is one response of an agent: text, tool calls, or both.
+type Turn struct {
+	Content   string     `json:"content,omitempty"`
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+}
+
+// ToolCall asks for a tool by name; Arguments is the JSON object passed to it.
+type ToolCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// Script maps agent names (market_analyst, bull_researcher, trader,
+// risk_judge, ...) to their turns.
+type Script map[string][]Turn
+
+// agentPrompts maps each agent to the prompt file that identifies its calls.
+var agentPrompts = []struct {
+	Agent  string
+	Prompt string
+}{
+	{consts.MarketAnalyst, "analysts/market_analyst"},
+	{consts.SocialAnalyst, "analysts/social_analyst"},
+	{consts.NewsAnalyst, "analysts/news_analyst"},
+	{consts.FundamentalsAnalyst, "analysts/fundamentals_analyst"},
+	{consts.BullResearcher, "researchers/bull_researcher"},
+	{consts.BearResearcher, "researchers/bear_researcher"},
+	{consts.ResearchManager, "managers/research_manager"},
+	{consts.Trader, "trader/trader"},
+	{consts.RiskyAnalyst, "risk_mgmt/risky_debate"},
+	{consts.SafeAnalyst, "risk_mgmt/safe_debate"},
+	{consts.NeutralAnalyst, "risk_mgmt/neutral_debate"},
+	{consts.RiskJudge, "managers/risk_manager"},
+	{consts.DeltaAnalyst, "delta/delta_analyst"},
+}
+
+// Agents lists the agent names a script may use, DefaultAgent included.
+func Agents() []string {
+	names := []string{DefaultAgent}
+	for _, a := range agentPrompts {
+		names = append(names, a.Agent)
+	}
+	return names
+}
+
+// LoadScript reads a script from a JSON file and checks its agent names and
+// tool arguments.
+func LoadScript(path string) (Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read mock script: %w", err)
+	}
+	var s Script
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse mock script %s: %w", path, err)
+	}
+	known := Agents()
+	for agent, turns := range s {
+		if !slices.Contains(known, agent) {
+			return nil, fmt.Errorf("mock script %s: unknown agent %q (want one of %s)", path, agent, strings.Join(known, ", "))
+		}
+		for i, t := range turns {
+			for _, tc := range t.ToolCalls {
+				if tc.Name == "" {
+					return nil, fmt.Errorf("mock script %s: %s turn %d calls a tool without a name", path, agent, i+1)
+				}
+				if len(tc.Arguments) > 0 && !json.Valid(tc.Arguments) {
+					return nil, fmt.Errorf("mock script %s: %s turn %d: invalid arguments for %s", path, agent, i+1, tc.Name)
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// Model answers every call from a script layered over the built-in responses.
+// It is safe for concurrent use; WithTools returns a copy.
+type Model struct {
+	script     Script
+	signatures map[string]string
+	tools      []*schema.ToolInfo
+}
+
+// New returns a model playing script; agents it leaves out get the built-in
+// responses.
+func New(script Script) *Model {
+	merged := Script{}
+	for agent, turns := range Canned() {
+		merged[agent] = turns
+	}
+	for agent, turns := range script {
+		if len(turns) > 0 {
+			merged[agent] = turns
+		}
+	}
+	signatures := map[string]string{}
+	for _, a := range agentPrompts {
+		if p, err := prompts.LoadPrompt(a.Prompt); err == nil {
+			signatures[a.Agent] = signature(p)
+		}
+	}
+	return &Model{script: merged, signatures: signatures}
+}
+
+// signature is the prompt's first line up to any template placeholder, so it
+// reads the same before and after the prompt is filled in.
+func signature(prompt string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(prompt), "\n")
+	line, _, _ = strings.Cut(line, "{")
+	return strings.TrimSpace(line)
+}
+
+// GetType names the component in callbacks.
+func (m *Model) GetType() string { return "Mock" }
+
+// WithTools returns a copy of the model that may call tools.
+func (m *Model) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	cp := *m
+	cp.tools = tools
+	return &cp, nil
+}
+
+// Generate returns the next turn of the agent the input belongs to.
+func (m *Model) Generate(_ context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	agent := m.Agent(input)
+	turns := m.script[agent]
+	if len(turns) == 0 {
+		turns = m.script[DefaultAgent]
+	}
+	n := 0
+	for _, msg := range input {
+		if msg.Role == schema.Assistant {
+			n++
+		}
+	}
+	var turn Turn
+	switch {
+	case len(turns) == 0:
+	case n < len(turns):
+		turn = turns[n]
+	default:
+		// out of script: repeat the final answer so a tool loop ends
+		turn = Turn{Content: turns[len(turns)-1].Content}
+	}
+
+	tools := model.GetCommonOptions(&model.Options{Tools: m.tools}, opts...).Tools
+	msg := schema.AssistantMessage(turn.Content, nil)
+	for i, tc := range turn.ToolCalls {
+		if !hasTool(tools, tc.Name) {
+			return nil, fmt.Errorf("mock script: %s calls %s, which it has no access to", agent, tc.Name)
+		}
+		args := "{}"
+		if len(tc.Arguments) > 0 {
+			args = string(tc.Arguments)
+		}
+		msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+			ID:       fmt.Sprintf("mock_%s_%d_%d", agent, n, i),
+			Type:     "function",
+			Function: schema.FunctionCall{Name: tc.Name, Arguments: args},
+		})
+	}
+	msg.ResponseMeta = &schema.ResponseMeta{FinishReason: "stop"}
+	if len(msg.ToolCalls) > 0 {
+		msg.ResponseMeta.FinishReason = "tool_calls"
+	}
+	return msg, nil
+}
+
+// Stream returns the Generate response as a single chunk.
+func (m *Model) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+// Agent names the agent whose prompt appears in input, or DefaultAgent.
+func (m *Model) Agent(input []*schema.Message) string {
+	for _, a := range agentPrompts {
+		sig := m.signatures[a.Agent]
+		if sig == "" {
+			continue
+		}
+		for _, msg := range input {
+			if (msg.Role == schema.System || msg.Role == schema.User) && strings.Contains(msg.Content, sig) {
+				return a.Agent
+			}
+		}
+	}
+	return DefaultAgent
+}
+
+func hasTool(tools []*schema.ToolInfo, name string) bool {
+	for _, t := range tools {
+		if t != nil && t.Name == name {
+			return true
+		}
+	}
+	return false
+}
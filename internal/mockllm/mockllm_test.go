@@ -0,0 +1,85 @@
+package mockllm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/internal/prompts"
+)
+
+func TestAgentFromPrompt(t *testing.T) {
+	m := New(nil)
+	trader, _ := prompts.LoadPrompt("trader/trader")
+	bear, _ := prompts.LoadPrompt("researchers/bear_researcher")
+	for _, tc := range []struct {
+		input []*schema.Message
+		want  string
+	}{
+		{[]*schema.Message{schema.SystemMessage(strings.ReplaceAll(trader, "{past_memory_str}", "none")), schema.UserMessage("plan")}, consts.Trader},
+		{[]*schema.Message{schema.UserMessage(bear)}, consts.BearResearcher},
+		{[]*schema.Message{schema.UserMessage("hello")}, DefaultAgent},
+	} {
+		if got := m.Agent(tc.input); got != tc.want {
+			t.Errorf("Agent = %s, want %s", got, tc.want)
+		}
+	}
+	msg, err := m.Generate(context.Background(), []*schema.Message{schema.SystemMessage(trader)})
+	if err != nil || !strings.Contains(msg.Content, "FINAL TRANSACTION PROPOSAL: **HOLD**") {
+		t.Errorf("canned trader = %v, %v", msg, err)
+	}
+}
+
+func TestScriptedToolTurns(t *testing.T) {
+	market, _ := prompts.LoadPrompt("analysts/market_analyst")
+	m := New(Script{consts.MarketAnalyst: {
+		{ToolCalls: []ToolCall{{Name: "get_market_data", Arguments: []byte(`{"symbol":"AAPL.US"}`)}}},
+		{Content: "done", ToolCalls: []ToolCall{{Name: "get_market_data"}}},
+	}})
+	input := []*schema.Message{schema.SystemMessage("You are a helpful AI assistant.\n" + market)}
+
+	if _, err := m.Generate(context.Background(), input); err == nil {
+		t.Fatal("want an error for a tool the agent is not bound to")
+	}
+	bound, _ := m.WithTools([]*schema.ToolInfo{{Name: "get_market_data"}})
+	first, err := bound.Generate(context.Background(), input)
+	if err != nil || len(first.ToolCalls) != 1 || first.ToolCalls[0].Function.Arguments != `{"symbol":"AAPL.US"}` {
+		t.Fatalf("first turn = %+v, %v", first, err)
+	}
+	input = append(input, first, schema.ToolMessage("bars", first.ToolCalls[0].ID))
+	second, _ := bound.Generate(context.Background(), input)
+	if second.Content != "done" || len(second.ToolCalls) != 1 || second.ToolCalls[0].Function.Arguments != "{}" {
+		t.Errorf("second turn = %+v", second)
+	}
+	// past the end of the script the final answer repeats without tool calls
+	input = append(input, second, schema.ToolMessage("bars", second.ToolCalls[0].ID))
+	third, _ := bound.Generate(context.Background(), input)
+	if third.Content != "done" || len(third.ToolCalls) != 0 {
+		t.Errorf("third turn = %+v", third)
+	}
+}
+
+func TestLoadScript(t *testing.T) {
+	dir := t.TempDir()
+	write := func(body string) string {
+		path := filepath.Join(dir, "script.json")
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	s, err := LoadScript(write(`{"trader":[{"content":"FINAL TRANSACTION PROPOSAL: **SELL**"}],"default":[{"content":"x"}]}`))
+	if err != nil || s[consts.Trader][0].Content != "FINAL TRANSACTION PROPOSAL: **SELL**" {
+		t.Fatalf("LoadScript = %v, %v", s, err)
+	}
+	if _, err := LoadScript(write(`{"cfo":[{"content":"x"}]}`)); err == nil || !strings.Contains(err.Error(), `unknown agent "cfo"`) {
+		t.Errorf("unknown agent: %v", err)
+	}
+	if _, err := LoadScript(write(`{"trader":[{"tool_calls":[{"arguments":{}}]}]}`)); err == nil {
+		t.Error("want an error for a tool call without a name")
+	}
+}
@@ -0,0 +1,282 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// Bounds of the correlation window, in daily returns.
+const (
+	DefaultCorrelationWindow = 60
+	MinCorrelationWindow     = 20
+	MaxCorrelationWindow     = 250
+)
+
+// Concentration thresholds: pairs at or above HighCorrelation are treated as
+// the same bet, and a cluster of such names above ConcentratedWeight of the
+// book, or a list whose average correlation reaches crowdedAverage, is flagged.
+const (
+	HighCorrelation    = 0.8
+	ConcentratedWeight = 0.4
+	crowdedAverage     = 0.6
+)
+
+// minOverlap is the fewest shared return days a pair needs to be scored.
+const minOverlap = 10
+
+// Correlations computes pairwise correlations of daily returns over the last
+// window returns up to asOf (all bars when empty). weights, keyed by symbol,
+// are optional and let clusters report their share of the book. Symbols
+// without enough history are listed in Missing rather than failing the run.
+func Correlations(ctx context.Context, fetch dataflows.BarFetcher, symbols []string, window int, asOf string, weights map[string]float64) (*models.CorrelationMatrix, error) {
+	if window == 0 {
+		window = DefaultCorrelationWindow
+	}
+	if window < MinCorrelationWindow || window > MaxCorrelationWindow {
+		return nil, fmt.Errorf("window must be between %d and %d days", MinCorrelationWindow, MaxCorrelationWindow)
+	}
+	var unique []string
+	for _, s := range symbols {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s != "" && !slices.Contains(unique, s) {
+			unique = append(unique, s)
+		}
+	}
+	if len(unique) < 2 {
+		return nil, fmt.Errorf("need at least two symbols, got %d", len(unique))
+	}
+
+	m := &models.CorrelationMatrix{Window: window}
+	var series []map[string]float64
+	for _, s := range unique {
+		// a small margin so bars after asOf can be dropped without shortening the window
+		bars, err := fetch(ctx, s, window+30)
+		if err != nil {
+			m.Missing = append(m.Missing, s)
+			continue
+		}
+		returns, last := dailyReturns(bars, asOf, window)
+		if len(returns) < minOverlap {
+			m.Missing = append(m.Missing, s)
+			continue
+		}
+		if last > m.AsOf {
+			m.AsOf = last
+		}
+		m.Symbols = append(m.Symbols, s)
+		series = append(series, returns)
+	}
+	if len(m.Symbols) < 2 {
+		return nil, fmt.Errorf("not enough price history: %s", strings.Join(m.Missing, ", "))
+	}
+
+	n := len(m.Symbols)
+	m.Matrix = make([][]float64, n)
+	for i := range m.Matrix {
+		m.Matrix[i] = make([]float64, n)
+		m.Matrix[i][i] = 1
+	}
+	sum, pairs := 0.0, 0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			c, ok := dataflows.Correlation(series[i], series[j], minOverlap)
+			if !ok {
+				m.Warnings = append(m.Warnings, fmt.Sprintf("%s and %s share fewer than %d trading days; their correlation is left at 0", m.Symbols[i], m.Symbols[j], minOverlap))
+				continue
+			}
+			c = math.Round(c*1000) / 1000
+			m.Matrix[i][j], m.Matrix[j][i] = c, c
+			sum += c
+			pairs++
+			if c >= HighCorrelation {
+				m.HighPairs = append(m.HighPairs, models.CorrelatedPair{A: m.Symbols[i], B: m.Symbols[j], Correlation: c})
+			}
+		}
+	}
+	if pairs > 0 {
+		m.AverageCorrelation = math.Round(sum/float64(pairs)*1000) / 1000
+	}
+	sort.SliceStable(m.HighPairs, func(i, j int) bool { return m.HighPairs[i].Correlation > m.HighPairs[j].Correlation })
+	m.Clusters = clusters(m.Symbols, m.HighPairs, weights)
+	flagConcentration(m, weights != nil)
+	return m, nil
+}
+
+// ConvertFunc converts an amount from one currency to another.
+type ConvertFunc func(amount float64, from, to string) (float64, error)
+
+// BaseCurrency is the currency a book is compared in: that of its net assets,
+// or of its first position when the account does not report one.
+func BaseCurrency(p *models.Portfolio) string {
+	if p == nil {
+		return ""
+	}
+	if p.Currency != "" {
+		return strings.ToUpper(p.Currency)
+	}
+	if len(p.Positions) > 0 {
+		return strings.ToUpper(p.Positions[0].Currency)
+	}
+	return ""
+}
+
+// Weights returns each position's share of the stock book at cost, in the
+// book's base currency. Positions in other currencies are converted with
+// convert; when it is nil or a rate is missing, mixed books get nil and
+// clusters are judged by count instead.
+func Weights(p *models.Portfolio, convert ConvertFunc) map[string]float64 {
+	if p == nil || len(p.Positions) == 0 {
+		return nil
+	}
+	base := BaseCurrency(p)
+	values := make([]float64, len(p.Positions))
+	total := 0.0
+	for i, pos := range p.Positions {
+		v := pos.Quantity * pos.CostPrice
+		if cur := strings.ToUpper(pos.Currency); cur != "" && cur != base {
+			if convert == nil {
+				return nil
+			}
+			var err error
+			if v, err = convert(v, cur, base); err != nil {
+				return nil
+			}
+		}
+		values[i] = v
+		total += v
+	}
+	if total <= 0 {
+		return nil
+	}
+	weights := map[string]float64{}
+	for i, pos := range p.Positions {
+		weights[strings.ToUpper(pos.Symbol)] += values[i] / total
+	}
+	return weights
+}
+
+// RenderCorrelation formats the matrix and its flags as markdown for reports
+// and the agents.
+func RenderCorrelation(m *models.CorrelationMatrix) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Correlation of daily returns over %d days", m.Window)
+	if m.AsOf != "" {
+		fmt.Fprintf(&b, " up to %s", m.AsOf)
+	}
+	fmt.Fprintf(&b, " (average %.2f):\n\n", m.AverageCorrelation)
+	b.WriteString("| |")
+	for _, s := range m.Symbols {
+		fmt.Fprintf(&b, " %s |", s)
+	}
+	b.WriteString("\n|---|" + strings.Repeat("---:|", len(m.Symbols)) + "\n")
+	for i, row := range m.Matrix {
+		fmt.Fprintf(&b, "| %s |", m.Symbols[i])
+		for _, c := range row {
+			fmt.Fprintf(&b, " %.2f |", c)
+		}
+		b.WriteString("\n")
+	}
+	if len(m.Missing) > 0 {
+		fmt.Fprintf(&b, "\nNot enough price history: %s\n", strings.Join(m.Missing, ", "))
+	}
+	if len(m.Notes) > 0 {
+		fmt.Fprintf(&b, "\nReturns compared in %s:\n", m.Currency)
+		for _, n := range m.Notes {
+			fmt.Fprintf(&b, "- %s\n", n)
+		}
+	}
+	if len(m.Warnings) == 0 {
+		fmt.Fprintf(&b, "\nNo concentration flags: no group of names moves together at %.2f or above.\n", HighCorrelation)
+		return b.String()
+	}
+	b.WriteString("\nConcentration flags:\n")
+	for _, w := range m.Warnings {
+		fmt.Fprintf(&b, "- %s\n", w)
+	}
+	return b.String()
+}
+
+// dailyReturns maps each date up to asOf to its close-to-close return, keeping
+// the last window returns, and reports the latest date used.
+func dailyReturns(bars []*models.MarketData, asOf string, window int) (map[string]float64, string) {
+	kept := dataflows.BarsUpTo(bars, asOf)
+	if len(kept) > window+1 {
+		kept = kept[len(kept)-window-1:]
+	}
+	returns := dataflows.DailyReturns(kept)
+	if len(kept) == 0 {
+		return returns, ""
+	}
+	return returns, kept[len(kept)-1].Date
+}
+
+// clusters groups symbols linked by highly correlated pairs, largest first.
+func clusters(symbols []string, pairs []models.CorrelatedPair, weights map[string]float64) []models.CorrelationCluster {
+	parent := map[string]string{}
+	var find func(string) string
+	find = func(s string) string {
+		if p, ok := parent[s]; ok && p != s {
+			parent[s] = find(p)
+			return parent[s]
+		}
+		return s
+	}
+	for _, p := range pairs {
+		if ra, rb := find(p.A), find(p.B); ra != rb {
+			parent[ra] = rb
+		}
+	}
+	groups := map[string][]string{}
+	var roots []string
+	for _, s := range symbols {
+		root := find(s)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], s)
+	}
+	var out []models.CorrelationCluster
+	for _, root := range roots {
+		members := groups[root]
+		if len(members) < 2 {
+			continue
+		}
+		c := models.CorrelationCluster{Symbols: members}
+		for _, s := range members {
+			c.Weight += weights[s]
+		}
+		c.Weight = math.Round(c.Weight*1000) / 1000
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Weight != out[j].Weight {
+			return out[i].Weight > out[j].Weight
+		}
+		return len(out[i].Symbols) > len(out[j].Symbols)
+	})
+	return out
+}
+
+// flagConcentration turns clusters and the average correlation into warnings.
+func flagConcentration(m *models.CorrelationMatrix, weighted bool) {
+	n := len(m.Symbols)
+	for _, c := range m.Clusters {
+		names := strings.Join(c.Symbols, ", ")
+		switch {
+		case weighted && c.Weight >= ConcentratedWeight:
+			m.Warnings = append(m.Warnings, fmt.Sprintf("%s move together (correlation >= %.2f) and make up %.0f%% of the book: treat them as one position", names, HighCorrelation, c.Weight*100))
+		case !weighted && len(c.Symbols)*2 >= n && len(c.Symbols) >= 3:
+			m.Warnings = append(m.Warnings, fmt.Sprintf("%d of %d names move together (correlation >= %.2f): %s", len(c.Symbols), n, HighCorrelation, names))
+		}
+	}
+	if m.AverageCorrelation >= crowdedAverage {
+		m.Warnings = append(m.Warnings, fmt.Sprintf("average pairwise correlation is %.2f: the list behaves like a single bet and offers little diversification", m.AverageCorrelation))
+	}
+}
@@ -0,0 +1,275 @@
+// Package portfolio imports the account's current holdings, either from the
+// Longport account API or a CSV export, and renders them for the agents.
+package portfolio
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+	"github.com/longportapp/openapi-go/trade"
+)
+
+// CashSymbol marks a CSV row that holds a cash balance instead of a position.
+const CashSymbol = "CASH"
+
+// csvColumns maps accepted header names to the field they fill.
+var csvColumns = map[string]string{
+	"symbol":             "symbol",
+	"ticker":             "symbol",
+	"code":               "symbol",
+	"name":               "name",
+	"quantity":           "quantity",
+	"qty":                "quantity",
+	"shares":             "quantity",
+	"available_quantity": "available",
+	"available":          "available",
+	"cost_price":         "cost",
+	"cost":               "cost",
+	"avg_cost":           "cost",
+	"average_cost":       "cost",
+	"currency":           "currency",
+	"market":             "market",
+}
+
+// FromLongport pulls stock positions and cash balances from a Longport
+// account: the named one, or longport_profile from cfg when account is empty.
+func FromLongport(ctx context.Context, cfg *config.Config, account string) (*models.Portfolio, error) {
+	if cfg.Offline {
+		return nil, fmt.Errorf("sync portfolio: %w", dataflows.ErrOffline)
+	}
+	client, err := dataflows.NewLongportClient(dataflows.LongportConfigFrom(cfg))
+	if err != nil {
+		return nil, err
+	}
+	if client, err = client.WithProfile(account); err != nil {
+		return nil, err
+	}
+	channels, err := client.GetStockPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch positions: %w", err)
+	}
+	balances, err := client.GetAccountBalance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch account balance: %w", err)
+	}
+	return fromLongport(channels, balances), nil
+}
+
+func fromLongport(channels []*trade.StockPositionChannel, balances []*trade.AccountBalance) *models.Portfolio {
+	p := &models.Portfolio{Source: models.PortfolioSourceLongport, SyncedAt: time.Now()}
+	for _, ch := range channels {
+		for _, pos := range ch.Positions {
+			p.Positions = append(p.Positions, models.PortfolioPosition{
+				Symbol:            strings.ToUpper(pos.Symbol),
+				Name:              pos.SymbolName,
+				Quantity:          parseNumber(pos.Quantity),
+				AvailableQuantity: parseNumber(pos.AvailableQuantity),
+				CostPrice:         dataflows.DecimalFloat(pos.CostPrice),
+				Currency:          pos.Currency,
+				Market:            string(pos.Market),
+			})
+		}
+	}
+	// every balance entry repeats the per-currency cash, so keep the first of each
+	seen := map[string]bool{}
+	for i, b := range balances {
+		if i == 0 {
+			p.Currency, p.NetAssets = b.Currency, dataflows.DecimalFloat(b.NetAssets)
+		}
+		for _, c := range b.CashInfos {
+			if seen[c.Currency] {
+				continue
+			}
+			seen[c.Currency] = true
+			p.Cash = append(p.Cash, models.PortfolioCash{
+				Currency:  c.Currency,
+				Available: dataflows.DecimalFloat(c.AvailableCash),
+				Frozen:    dataflows.DecimalFloat(c.FrozenCash),
+			})
+		}
+	}
+	return p
+}
+
+// ParseCSV reads holdings exported from a broker or spreadsheet. The header
+// needs symbol and quantity columns; cost_price, currency, name, market and
+// available_quantity are optional. Rows whose symbol is CASH carry a cash
+// balance in the quantity column.
+func ParseCSV(r io.Reader) (*models.Portfolio, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("csv is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := csvColumns[strings.ReplaceAll(name, " ", "_")]; ok {
+			if _, dup := index[field]; !dup {
+				index[field] = i
+			}
+		}
+	}
+	for _, field := range []string{"symbol", "quantity"} {
+		if _, ok := index[field]; !ok {
+			return nil, fmt.Errorf("csv header needs a %s column", field)
+		}
+	}
+
+	p := &models.Portfolio{Source: models.PortfolioSourceCSV, SyncedAt: time.Now()}
+	cash := map[string]int{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read csv: %w", err)
+		}
+		get := func(field string) string {
+			if i, ok := index[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		symbol := strings.ToUpper(get("symbol"))
+		if symbol == "" {
+			continue
+		}
+		quantity, err := strconv.ParseFloat(strings.ReplaceAll(get("quantity"), ",", ""), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid quantity %q", line, get("quantity"))
+		}
+		currency := strings.ToUpper(get("currency"))
+		if symbol == CashSymbol {
+			if i, ok := cash[currency]; ok {
+				p.Cash[i].Available += quantity
+				continue
+			}
+			cash[currency] = len(p.Cash)
+			p.Cash = append(p.Cash, models.PortfolioCash{Currency: currency, Available: quantity})
+			continue
+		}
+		pos := models.PortfolioPosition{
+			Symbol:            symbol,
+			Name:              get("name"),
+			Quantity:          quantity,
+			AvailableQuantity: quantity,
+			Currency:          currency,
+			Market:            strings.ToUpper(get("market")),
+		}
+		if v := get("cost"); v != "" {
+			if pos.CostPrice, err = strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid cost price %q", line, v)
+			}
+		}
+		if v := get("available"); v != "" {
+			if pos.AvailableQuantity, err = strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid available quantity %q", line, v)
+			}
+		}
+		p.Positions = append(p.Positions, pos)
+	}
+	return p, nil
+}
+
+// Context loads the stored portfolio and renders it for symbol; holdings are
+// optional, so a missing snapshot yields a note instead of an error.
+func Context(ctx context.Context, symbol string) string {
+	const missing = "No portfolio data is available; size the position as if starting from cash."
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return missing
+	}
+	p, err := store.GetPortfolio(ctx)
+	if err != nil {
+		return missing
+	}
+	return Prompt(p, symbol)
+}
+
+// Prompt summarizes the holdings for the decision maker, calling out any
+// existing position in symbol and its share of the book in that currency.
+// Book value is cost basis plus cash since the snapshot has no live prices.
+func Prompt(p *models.Portfolio, symbol string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Current portfolio (%s snapshot, %s):\n", p.Source, p.SyncedAt.Format("2006-01-02 15:04"))
+	if p.NetAssets > 0 {
+		fmt.Fprintf(&b, "- Net assets: %s %.2f\n", p.Currency, p.NetAssets)
+	}
+	book := map[string]float64{}
+	for _, c := range p.Cash {
+		book[c.Currency] += c.Available + c.Frozen
+		fmt.Fprintf(&b, "- Cash %s: %.2f available", c.Currency, c.Available)
+		if c.Frozen > 0 {
+			fmt.Fprintf(&b, ", %.2f frozen", c.Frozen)
+		}
+		b.WriteString("\n")
+	}
+	positions := append([]models.PortfolioPosition(nil), p.Positions...)
+	for _, pos := range positions {
+		book[pos.Currency] += pos.Quantity * pos.CostPrice
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		return positions[i].Quantity*positions[i].CostPrice > positions[j].Quantity*positions[j].CostPrice
+	})
+
+	var held *models.PortfolioPosition
+	for i, pos := range positions {
+		fmt.Fprintf(&b, "- %s", pos.Symbol)
+		if pos.Name != "" {
+			fmt.Fprintf(&b, " (%s)", pos.Name)
+		}
+		fmt.Fprintf(&b, ": %g shares at %.2f %s", pos.Quantity, pos.CostPrice, pos.Currency)
+		if total := book[pos.Currency]; total > 0 && pos.CostPrice > 0 {
+			fmt.Fprintf(&b, ", %.1f%% of %s book", pos.Quantity*pos.CostPrice/total*100, pos.Currency)
+		}
+		b.WriteString("\n")
+		if held == nil && SameSymbol(pos.Symbol, symbol) {
+			held = &positions[i]
+		}
+	}
+	if len(positions) == 0 {
+		b.WriteString("- No open stock positions.\n")
+	}
+	if held != nil {
+		fmt.Fprintf(&b, "The account already holds %g shares of %s (%g available to sell); treat the decision as adjusting this position, not opening a new one.\n",
+			held.Quantity, held.Symbol, held.AvailableQuantity)
+	} else {
+		fmt.Fprintf(&b, "The account holds no %s; a SELL means staying out rather than selling shares.\n", symbol)
+	}
+	return b.String()
+}
+
+// SameSymbol reports whether two tickers name the same listing, treating a
+// bare ticker such as AAPL as matching AAPL.US.
+func SameSymbol(a, b string) bool {
+	a, b = strings.ToUpper(strings.TrimSpace(a)), strings.ToUpper(strings.TrimSpace(b))
+	if a == b {
+		return a != ""
+	}
+	baseA, _, okA := strings.Cut(a, ".")
+	baseB, _, okB := strings.Cut(b, ".")
+	return !(okA && okB) && baseA == baseB
+}
+
+func parseNumber(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return v
+}
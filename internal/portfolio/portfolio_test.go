@@ -0,0 +1,157 @@
+package portfolio
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/internal/bartest"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+const holdingsCSV = "\ufeffSymbol,Name,Qty,Avg Cost,Currency\n" +
+	"aapl.us,Apple,100,150,USD\n" +
+	"AAPL.US,Apple,50,180,USD\n" +
+	"700.HK,Tencent,\"1,000\",300,HKD\n" +
+	"CASH,,5000,,USD\n" +
+	",,,,\n"
+
+func TestParseCSVAndSave(t *testing.T) {
+	p, err := ParseCSV(strings.NewReader(holdingsCSV))
+	if err != nil {
+		t.Fatalf("ParseCSV: %v", err)
+	}
+	if len(p.Positions) != 3 || len(p.Cash) != 1 || p.Cash[0].Available != 5000 {
+		t.Fatalf("portfolio = %+v", p)
+	}
+	if pos := p.Positions[2]; pos.Symbol != "700.HK" || pos.Quantity != 1000 || pos.CostPrice != 300 {
+		t.Errorf("position = %+v", pos)
+	}
+	if _, err := ParseCSV(strings.NewReader("symbol,cost\nAAPL,1\n")); err == nil {
+		t.Error("want error without a quantity column")
+	}
+	if _, err := ParseCSV(strings.NewReader("symbol,qty\nAAPL,ten\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("want line number in error, got %v", err)
+	}
+
+	store, err := storage.NewStore(filepath.Join(t.TempDir(), "agent.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	if _, err := store.GetPortfolio(ctx); err == nil {
+		t.Fatal("want error before the first sync")
+	}
+	if err := store.SavePortfolio(ctx, p); err != nil {
+		t.Fatalf("SavePortfolio: %v", err)
+	}
+	got, err := store.GetPortfolio(ctx)
+	if err != nil {
+		t.Fatalf("GetPortfolio: %v", err)
+	}
+	// the two AAPL rows merge with a quantity-weighted cost
+	if len(got.Positions) != 2 || got.Positions[1].Symbol != "AAPL.US" || got.Positions[1].Quantity != 150 || got.Positions[1].CostPrice != 160 {
+		t.Fatalf("stored positions = %+v", got.Positions)
+	}
+
+	// a later sync replaces the snapshot
+	if err := store.SavePortfolio(ctx, &models.Portfolio{Source: models.PortfolioSourceLongport}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := store.GetPortfolio(ctx); got.Source != models.PortfolioSourceLongport || len(got.Positions) != 0 || len(got.Cash) != 0 {
+		t.Errorf("snapshot not replaced: %+v", got)
+	}
+}
+
+func TestPromptCallsOutHeldSymbol(t *testing.T) {
+	p := &models.Portfolio{
+		Source: models.PortfolioSourceCSV,
+		Positions: []models.PortfolioPosition{
+			{Symbol: "MSFT.US", Quantity: 10, AvailableQuantity: 10, CostPrice: 300, Currency: "USD"},
+			{Symbol: "AAPL.US", Quantity: 20, AvailableQuantity: 15, CostPrice: 150, Currency: "USD"},
+		},
+		Cash: []models.PortfolioCash{{Currency: "USD", Available: 4000}},
+	}
+	text := Prompt(p, "AAPL")
+	if !strings.Contains(text, "AAPL.US: 20 shares at 150.00 USD, 30.0% of USD book") {
+		t.Errorf("missing weight:\n%s", text)
+	}
+	if !strings.Contains(text, "already holds 20 shares of AAPL.US (15 available") {
+		t.Errorf("held position not called out:\n%s", text)
+	}
+	if text := Prompt(p, "700.HK"); !strings.Contains(text, "holds no 700.HK") {
+		t.Errorf("want no-position note:\n%s", text)
+	}
+	if SameSymbol("AAPL.US", "AAPL.HK") || !SameSymbol("aapl", "AAPL.US") {
+		t.Error("SameSymbol mismatch")
+	}
+}
+
+// bars builds daily bars ending 2025-06-30 from the given daily returns.
+func bars(returns func(i int) float64, n int) []*models.MarketData {
+	return bartest.Bars(bartest.Walk(n, returns))
+}
+
+func TestCorrelationsFlagsConcentratedCluster(t *testing.T) {
+	wave := func(i int) float64 { return 0.02 * math.Sin(float64(i)) }
+	series := map[string][]*models.MarketData{
+		"NVDA.US": bars(wave, 90),
+		"AMD.US":  bars(func(i int) float64 { return 1.5*wave(i) + 0.001*math.Cos(float64(i*7)) }, 90),
+		"XOM.US":  bars(func(i int) float64 { return 0.02 * math.Cos(float64(i)*2.3) }, 90),
+	}
+	fetch := bartest.Fetcher(series)
+	weights := map[string]float64{"NVDA.US": 0.3, "AMD.US": 0.25, "XOM.US": 0.45}
+
+	m, err := Correlations(context.Background(), fetch, []string{"nvda.us", "AMD.US", "XOM.US", "GONE.US"}, 60, "2025-06-30", weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Symbols) != 3 || len(m.Missing) != 1 || m.AsOf != "2025-06-30" {
+		t.Fatalf("matrix = %+v", m)
+	}
+	if len(m.HighPairs) != 1 || m.HighPairs[0].A != "NVDA.US" || m.HighPairs[0].B != "AMD.US" || m.Matrix[0][1] < 0.9 {
+		t.Errorf("high pairs = %+v, matrix = %v", m.HighPairs, m.Matrix)
+	}
+	if len(m.Clusters) != 1 || m.Clusters[0].Weight != 0.55 || len(m.Warnings) != 1 || !strings.Contains(m.Warnings[0], "55% of the book") {
+		t.Errorf("clusters = %+v, warnings = %v", m.Clusters, m.Warnings)
+	}
+	if text := RenderCorrelation(m); !strings.Contains(text, "| NVDA.US | 1.00 |") || !strings.Contains(text, "GONE.US") {
+		t.Errorf("render:\n%s", text)
+	}
+
+	if _, err := Correlations(context.Background(), fetch, []string{"NVDA.US"}, 60, "", nil); err == nil {
+		t.Error("want error for a single symbol")
+	}
+	if _, err := Correlations(context.Background(), fetch, []string{"NVDA.US", "AMD.US"}, 5, "", nil); err == nil {
+		t.Error("want error for a window below the minimum")
+	}
+}
+
+func TestWeightsConvertsMixedBook(t *testing.T) {
+	p := &models.Portfolio{Currency: "USD", Positions: []models.PortfolioPosition{
+		{Symbol: "AAPL.US", Quantity: 10, CostPrice: 200, Currency: "USD"},
+		{Symbol: "700.HK", Quantity: 100, CostPrice: 390, Currency: "HKD"},
+	}}
+	if w := Weights(p, nil); w != nil {
+		t.Errorf("mixed book without rates = %v, want nil", w)
+	}
+	toUSD := func(amount float64, from, to string) (float64, error) {
+		if from != "HKD" || to != "USD" {
+			return 0, errors.New("unexpected pair")
+		}
+		return amount * 0.128, nil
+	}
+	w := Weights(p, toUSD)
+	// 39000 HKD is 4992 USD against 2000 USD of AAPL
+	if math.Abs(w["700.HK"]-4992.0/6992) > 1e-9 || math.Abs(w["AAPL.US"]-2000.0/6992) > 1e-9 {
+		t.Errorf("weights = %v", w)
+	}
+	if BaseCurrency(&models.Portfolio{Positions: p.Positions[1:]}) != "HKD" {
+		t.Error("base currency falls back to the first position")
+	}
+}
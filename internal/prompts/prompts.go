@@ -1,15 +1,20 @@
 package prompts
 
 import (
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"fmt"
+	"io/fs"
 	"strings"
 )
 
 //go:embed **/*.md
 var promptFiles embed.FS
 
-// LoadPrompt loads a prompt from the embedded markdown files
+// LoadPrompt loads a prompt from the embedded markdown files. Agents use the
+// file itself as the schema.FString template, so its {placeholders} are
+// filled from the agent's context and literal braces must be doubled.
 func LoadPrompt(path string) (string, error) {
 	content, err := promptFiles.ReadFile(fmt.Sprintf("%s.md", path))
 	if err != nil {
@@ -33,3 +38,41 @@ func LoadPromptWithContext(path string, context map[string]string) (string, erro
 
 	return content, nil
 }
+
+// Digest is a short hash over every prompt template, so runs can tell whether
+// they used the same prompts.
+func Digest() string {
+	h := sha256.New()
+	_ = fs.WalkDir(promptFiles, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := promptFiles.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", path, len(content))
+		h.Write(content)
+		return nil
+	})
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// Digests returns a short hash per prompt template, keyed by the path
+// LoadPrompt takes, so a run records which version of each prompt it used.
+func Digests() map[string]string {
+	out := map[string]string{}
+	_ = fs.WalkDir(promptFiles, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := promptFiles.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		out[strings.TrimSuffix(path, ".md")] = hex.EncodeToString(sum[:])[:12]
+		return nil
+	})
+	return out
+}
@@ -1,7 +1,14 @@
 package prompts
 
 import (
+	"context"
+	"io/fs"
+	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
 )
 
 func TestLoadPrompt(t *testing.T) {
@@ -9,3 +16,37 @@ func TestLoadPrompt(t *testing.T) {
 	t.Log("val", val)
 	t.Log("err", err)
 }
+
+var placeholderRe = regexp.MustCompile(`\{(\w+)\}`)
+
+// Every prompt file is rendered as an FString template by its agent, so a
+// literal brace that is not doubled breaks the agent at run time.
+func TestEveryPromptRendersAsTemplate(t *testing.T) {
+	err := fs.WalkDir(promptFiles, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := LoadPrompt(strings.TrimSuffix(path, ".md"))
+		if err != nil {
+			return err
+		}
+		vars := map[string]any{}
+		for _, m := range placeholderRe.FindAllStringSubmatch(content, -1) {
+			vars[m[1]] = "<" + m[1] + ">"
+		}
+		msgs, err := prompt.FromMessages(schema.FString, schema.SystemMessage(content)).Format(context.Background(), vars)
+		if err != nil {
+			t.Errorf("%s: %v", path, err)
+			return nil
+		}
+		for name := range vars {
+			if !strings.Contains(msgs[0].Content, "<"+name+">") {
+				t.Errorf("%s: placeholder {%s} was not filled", path, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
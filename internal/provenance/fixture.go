@@ -0,0 +1,27 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+)
+
+type outputsKey struct{}
+
+// WithToolOutputs makes the wrapped tools called under ctx answer with
+// outputs, keyed by tool name, instead of reaching their data sources; a
+// tool missing from outputs fails. The outputs are recorded as evidence like
+// real ones, without provenance. Agent tests use it to replay fixture data.
+func WithToolOutputs(ctx context.Context, outputs map[string]string) context.Context {
+	if outputs == nil {
+		outputs = map[string]string{}
+	}
+	return context.WithValue(ctx, outputsKey{}, outputs)
+}
+
+func fixtureOutput(outputs map[string]string, name string) (string, error) {
+	out, ok := outputs[name]
+	if !ok {
+		return "", fmt.Errorf("no fixture output for tool %s", name)
+	}
+	return out, nil
+}
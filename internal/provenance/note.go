@@ -0,0 +1,105 @@
+package provenance
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// collectorKey carries the provenance collector of the tool call in progress.
+type collectorKey struct{}
+
+type collector struct {
+	mu sync.Mutex
+	p  *models.Provenance
+}
+
+// Note records where the data of the tool call running in ctx came from. A
+// tool reading several sources calls it once per source and the notes are
+// merged: counts add up, the oldest fetch time and the latest as-of date win,
+// and differing modes become "mixed". Outside a recorded tool call it does
+// nothing.
+func Note(ctx context.Context, p models.Provenance) {
+	c, ok := ctx.Value(collectorKey{}).(*collector)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.p = Merge(c.p, p)
+}
+
+// Merge folds p into into as Note does and returns the result; into may be
+// nil.
+func Merge(into *models.Provenance, p models.Provenance) *models.Provenance {
+	if into == nil {
+		return &p
+	}
+	if p.Source != "" && !strings.Contains("+"+into.Source+"+", "+"+p.Source+"+") {
+		if into.Source == "" {
+			into.Source = p.Source
+		} else {
+			into.Source += "+" + p.Source
+		}
+	}
+	if into.Mode != p.Mode {
+		into.Mode = models.ProvenanceMixed
+	}
+	if !p.FetchedAt.IsZero() && (into.FetchedAt.IsZero() || p.FetchedAt.Before(into.FetchedAt)) {
+		into.FetchedAt = p.FetchedAt
+	}
+	if p.AsOf > into.AsOf {
+		into.AsOf = p.AsOf
+	}
+	into.CacheHits += p.CacheHits
+	into.CacheMisses += p.CacheMisses
+	return into
+}
+
+func (c *collector) result() *models.Provenance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.p != nil && c.p.FetchedAt.IsZero() {
+		c.p.FetchedAt = time.Now()
+	}
+	return c.p
+}
+
+// Describe renders a provenance as one line, e.g.
+// "google_news (cache, fetched 2026-10-17 09:30, 35m ago; as of 2026-10-16)".
+// The age is left out when now is zero.
+func Describe(p *models.Provenance, now time.Time) string {
+	if p == nil {
+		return ""
+	}
+	parts := []string{p.Mode}
+	if !p.FetchedAt.IsZero() {
+		fetched := "fetched " + p.FetchedAt.Format("2006-01-02 15:04")
+		if !now.IsZero() {
+			fetched += ", " + Age(now.Sub(p.FetchedAt)) + " ago"
+		}
+		parts = append(parts, fetched)
+	}
+	s := p.Source + " (" + strings.Join(parts, ", ")
+	if p.AsOf != "" {
+		s += "; as of " + p.AsOf
+	}
+	return s + ")"
+}
+
+// Age formats a duration coarsely: 45s, 35m, 5h, 3d.
+func Age(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
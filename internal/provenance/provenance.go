@@ -0,0 +1,220 @@
+// Package provenance records which tool outputs each agent saw and traces the
+// claims in its report back to them, so a recommendation can be audited.
+package provenance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// Bounds for what is kept of each tool call in the trading state.
+const (
+	excerptRunes   = 280
+	argumentsRunes = 200
+	maxDataPoints  = 300
+)
+
+// WrapTools makes every invokable tool record its output as evidence for agent.
+// Outputs are prefixed with the evidence ID (e.g. "[E3]") so the agent can cite
+// it, followed by where and when the data was fetched when the tool noted it;
+// tools that are not invokable are returned unchanged.
+func WrapTools(agent string, tools []tool.BaseTool) []tool.BaseTool {
+	wrapped := make([]tool.BaseTool, len(tools))
+	for i, t := range tools {
+		if inv, ok := t.(tool.InvokableTool); ok {
+			wrapped[i] = &recordingTool{InvokableTool: inv, agent: agent}
+		} else {
+			wrapped[i] = t
+		}
+	}
+	return wrapped
+}
+
+// recordingTool forwards to the wrapped tool and records its successful outputs.
+type recordingTool struct {
+	tool.InvokableTool
+	agent string
+}
+
+func (t *recordingTool) InvokableRun(ctx context.Context, arguments string, opts ...tool.Option) (string, error) {
+	name, version := "", ""
+	if info, ierr := t.Info(ctx); ierr == nil {
+		name, version = info.Name, ToolVersion(info)
+	}
+	c := &collector{}
+	var (
+		out string
+		err error
+	)
+	var p *models.Provenance
+	if outputs, ok := ctx.Value(outputsKey{}).(map[string]string); ok {
+		out, err = fixtureOutput(outputs, name)
+	} else if s := dataflows.SessionFrom(ctx); s.SharesTool(name) {
+		// market-wide tools answer every analysis of a batch from one call;
+		// each analysis still records the output as its own evidence
+		var v any
+		v, err = s.Do("tool:"+name+":"+canonicalArguments(arguments), func() (any, error) {
+			out, err := t.InvokableTool.InvokableRun(context.WithValue(ctx, collectorKey{}, c), arguments, opts...)
+			return sharedOutput{out: out, provenance: c.result()}, err
+		})
+		shared, _ := v.(sharedOutput)
+		out, p = shared.out, shared.provenance
+	} else {
+		out, err = t.InvokableTool.InvokableRun(context.WithValue(ctx, collectorKey{}, c), arguments, opts...)
+		p = c.result()
+	}
+	if err != nil {
+		return out, err
+	}
+	if id, seeded := Record(ctx, t.agent, name, version, arguments, out, p); id != "" {
+		header := "[" + id + "]"
+		if p != nil {
+			// a seeded run replays the same text, so no age relative to now
+			now := time.Now()
+			if seeded {
+				now = time.Time{}
+			}
+			header += " source: " + Describe(p, now)
+		}
+		out = header + "\n" + out
+	}
+	return out, nil
+}
+
+// sharedOutput is a tool output kept in a batch session.
+type sharedOutput struct {
+	out        string
+	provenance *models.Provenance
+}
+
+// canonicalArguments rewrites JSON arguments with sorted keys and no
+// whitespace, so equivalent calls share one output.
+func canonicalArguments(arguments string) string {
+	var v any
+	if err := json.Unmarshal([]byte(arguments), &v); err != nil {
+		return arguments
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return arguments
+	}
+	return string(b)
+}
+
+// Record appends a tool output to the trading state in ctx and returns its
+// evidence ID, and whether the run is seeded. It returns "" when ctx carries
+// no trading state, e.g. when a tool is invoked outside the graph. toolVersion
+// is the tool's ToolVersion and p is where the output's data came from, both
+// empty when unknown. The untruncated arguments and output are kept as well
+// when the run exports its tool data.
+func Record(ctx context.Context, agent, toolName, toolVersion, arguments, output string, p *models.Provenance) (id string, seeded bool) {
+	_ = compose.ProcessState[*models.TradingState](ctx, func(_ context.Context, state *models.TradingState) error {
+		seeded = state.Config != nil && state.Config.Seed > 0
+		id = fmt.Sprintf("E%d", len(state.Evidence)+1)
+		state.Evidence = append(state.Evidence, &models.Evidence{
+			ID:          id,
+			Agent:       agent,
+			Tool:        toolName,
+			Arguments:   truncate(compact(arguments), argumentsRunes),
+			Excerpt:     truncate(compact(output), excerptRunes),
+			DataPoints:  limit(DataPoints(output), maxDataPoints),
+			Provenance:  p,
+			Digest:      digest(output),
+			ToolVersion: toolVersion,
+			CreatedAt:   time.Now(),
+		})
+		if state.Config != nil && state.Config.ExportToolData {
+			state.ToolResults = append(state.ToolResults, &models.ToolResult{EvidenceID: id, Arguments: arguments, Output: output})
+		}
+		return nil
+	})
+	return id, seeded
+}
+
+// ToolVersion is a short hash of a tool's definition: its name, description
+// and parameters. It changes whenever what the model is told about the tool
+// changes, so two runs can be checked for having used the same tools.
+func ToolVersion(info *schema.ToolInfo) string {
+	if info == nil {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", info.Name, info.Desc)
+	if info.ParamsOneOf != nil {
+		if js, err := info.ParamsOneOf.ToJSONSchema(); err == nil {
+			h.Write(canonicalJSON(js))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:6])
+}
+
+// canonicalJSON marshals v with object keys and "required" lists sorted.
+// Schemas built from map params list properties and required fields in map
+// iteration order, which would otherwise change the hash from run to run.
+func canonicalJSON(v any) []byte {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return raw
+	}
+	sortRequired(generic)
+	out, _ := json.Marshal(generic)
+	return out
+}
+
+func sortRequired(v any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if list, ok := child.([]any); ok && k == "required" {
+				sort.Slice(list, func(i, j int) bool { return fmt.Sprint(list[i]) < fmt.Sprint(list[j]) })
+			}
+			sortRequired(child)
+		}
+	case []any:
+		for _, child := range t {
+			sortRequired(child)
+		}
+	}
+}
+
+// digest is a short hash of a full tool output.
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:6])
+}
+
+// compact collapses whitespace so excerpts stay on one line.
+func compact(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "..."
+}
+
+func limit(s []string, n int) []string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
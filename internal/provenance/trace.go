@@ -0,0 +1,220 @@
+package provenance
+
+import (
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// Bounds that keep the evidence chain readable for long reports.
+const (
+	maxClaimsPerSource = 5
+	claimRunes         = 240
+	minClaimRunes      = 8
+)
+
+var (
+	numberRe   = regexp.MustCompile(`[-+]?\$?\d[\d,]*(?:\.\d+)?%?`)
+	citationRe = regexp.MustCompile(`\[(E\d+)\]`)
+	// sentenceEndRe splits on CJK and ASCII sentence ends; a period only ends a
+	// sentence when followed by a space so decimals stay intact.
+	sentenceEndRe = regexp.MustCompile(`[。！？；!?;\n]|\.\s`)
+)
+
+// Source is a block of agent output whose claims should be traced.
+type Source struct {
+	Key   string // report section key
+	Agent string // agent whose own tool calls the claims should match first; empty matches any
+	Text  string
+}
+
+// DataPoints extracts the figures in text that are specific enough to match
+// across documents: decimals, percentages, dollar amounts and integers of three
+// or more digits that are not years. Values are normalized (no sign, thousands
+// separators or trailing zeros) and deduplicated in order of appearance.
+func DataPoints(text string) []string {
+	var points []string
+	seen := map[string]bool{}
+	for _, raw := range numberRe.FindAllString(text, -1) {
+		p, ok := normalizePoint(raw)
+		if !ok || seen[p] {
+			continue
+		}
+		seen[p] = true
+		points = append(points, p)
+	}
+	return points
+}
+
+func normalizePoint(raw string) (string, bool) {
+	dollar := strings.Contains(raw, "$")
+	percent := strings.HasSuffix(raw, "%")
+	s := strings.NewReplacer("$", "", ",", "", "+", "", "-", "", "%", "").Replace(raw)
+	whole, frac, decimal := strings.Cut(s, ".")
+	if decimal {
+		frac = strings.TrimRight(frac, "0")
+	}
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		whole = "0"
+	}
+	s = whole
+	if frac != "" {
+		s += "." + frac
+	}
+	if s == "0" {
+		return "", false
+	}
+	switch {
+	case percent:
+		return s + "%", true
+	case frac != "" || dollar:
+		return s, true
+	case len(whole) >= 3 && !isYear(whole):
+		return s, true
+	}
+	return "", false
+}
+
+func isYear(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	y, _ := strconv.Atoi(s)
+	return y >= 1900 && y <= 2100
+}
+
+// pointMatches reports whether a figure quoted in a claim matches one in a
+// tool output, allowing the claim to round to fewer decimals.
+func pointMatches(claim, evidence string) bool {
+	if claim == evidence {
+		return true
+	}
+	if strings.HasSuffix(claim, "%") != strings.HasSuffix(evidence, "%") {
+		return false
+	}
+	_, frac, ok := strings.Cut(strings.TrimSuffix(claim, "%"), ".")
+	if !ok {
+		return false
+	}
+	c, err1 := strconv.ParseFloat(strings.TrimSuffix(claim, "%"), 64)
+	e, err2 := strconv.ParseFloat(strings.TrimSuffix(evidence, "%"), 64)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	scale := math.Pow10(len(frac))
+	return math.Round(c*scale) == math.Round(e*scale)
+}
+
+// Trace links the claims in each source to the evidence that supports them.
+// A claim is supported when it cites an evidence ID explicitly or quotes a
+// figure that appears in a tool output; the agent's own tool calls are
+// preferred over other agents'. At most a handful of claims per source are
+// kept, explicit citations and data-rich sentences first.
+func Trace(sources []Source, evidence []*models.Evidence) []models.EvidenceClaim {
+	byID := make(map[string]*models.Evidence, len(evidence))
+	for _, e := range evidence {
+		byID[e.ID] = e
+	}
+	var claims []models.EvidenceClaim
+	for _, src := range sources {
+		var own, others []*models.Evidence
+		for _, e := range evidence {
+			if src.Agent == "" || e.Agent == src.Agent {
+				own = append(own, e)
+			} else {
+				others = append(others, e)
+			}
+		}
+		var found []models.EvidenceClaim
+		for _, sentence := range splitSentences(src.Text) {
+			claim := models.EvidenceClaim{Section: src.Key}
+			var cited []*models.Evidence
+			for _, m := range citationRe.FindAllStringSubmatch(sentence, -1) {
+				if e, ok := byID[m[1]]; ok && !slices.Contains(claim.Evidence, e.ID) {
+					claim.Evidence = append(claim.Evidence, e.ID)
+					cited = append(cited, e)
+				}
+			}
+			claim.Cited = len(cited) > 0
+			for _, p := range DataPoints(citationRe.ReplaceAllString(sentence, "")) {
+				e := findPoint(p, cited, own, others)
+				if e == nil {
+					continue
+				}
+				claim.DataPoints = append(claim.DataPoints, p)
+				if !slices.Contains(claim.Evidence, e.ID) {
+					claim.Evidence = append(claim.Evidence, e.ID)
+				}
+			}
+			if len(claim.Evidence) == 0 {
+				continue
+			}
+			claim.Text = truncate(sentence, claimRunes)
+			found = append(found, claim)
+		}
+		claims = append(claims, strongest(found, maxClaimsPerSource)...)
+	}
+	return claims
+}
+
+// findPoint returns the first evidence, searching the pools in order, whose
+// output contains the figure.
+func findPoint(point string, pools ...[]*models.Evidence) *models.Evidence {
+	for _, pool := range pools {
+		for _, e := range pool {
+			for _, p := range e.DataPoints {
+				if pointMatches(point, p) {
+					return e
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// strongest keeps the n best-supported claims in their original order.
+func strongest(claims []models.EvidenceClaim, n int) []models.EvidenceClaim {
+	if len(claims) <= n {
+		return claims
+	}
+	idx := make([]int, len(claims))
+	for i := range idx {
+		idx[i] = i
+	}
+	score := func(c models.EvidenceClaim) int {
+		s := len(c.DataPoints) + len(c.Evidence)
+		if c.Cited {
+			s += 100
+		}
+		return s
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return score(claims[idx[a]]) > score(claims[idx[b]]) })
+	idx = idx[:n]
+	sort.Ints(idx)
+	out := make([]models.EvidenceClaim, n)
+	for i, j := range idx {
+		out[i] = claims[j]
+	}
+	return out
+}
+
+// splitSentences breaks agent markdown into trimmed sentences, dropping list
+// markers, emphasis and fragments too short to be a claim.
+func splitSentences(text string) []string {
+	var out []string
+	for _, part := range sentenceEndRe.Split(text, -1) {
+		part = strings.Trim(compact(strings.ReplaceAll(part, "**", "")), "-*#>|: ")
+		if utf8.RuneCountInString(part) < minClaimRunes {
+			continue
+		}
+		out = append(out, part)
+	}
+	return out
+}
@@ -0,0 +1,222 @@
+// Package rates summarizes the US Treasury yield curve: the 2s10s and 3m10y
+// spreads, how the 2- and 10-year yields moved over the last week and month,
+// and whether the curve is steepening or flattening. It is the macro input
+// the regime step and the risk debate read rates from.
+package rates
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// historyDays covers a month of moves plus slack for holidays.
+const historyDays = 45
+
+// Thresholds in basis points.
+const (
+	flatSpread   = 25 // 2s10s below this (and not negative) is a flat curve
+	stableMove   = 10 // both ends moved less than this over the month
+	parallelBand = 10 // long and short ends moved within this of each other
+	sharpMove    = 40 // a monthly 10-year move worth flagging on its own
+)
+
+// Load fetches the curve history up to tradeDate (empty for today) and
+// summarizes it, noting the fetch in ctx's provenance.
+func Load(ctx context.Context, cfg *config.Config, tradeDate string) (*models.RatesSummary, error) {
+	client := dataflows.NewTreasuryClient(cfg)
+	curves, err := client.GetYieldCurves(tradeDate, historyDays)
+	if err != nil {
+		return nil, err
+	}
+	p := client.Provenance()
+	p.AsOf = curves[len(curves)-1].Date
+	provenance.Note(ctx, p)
+	return Summarize(curves, tradeDate)
+}
+
+// Summarize reads the latest curve on or before asOf and compares it with
+// the curves about a week and a month earlier. Changes stay zero, and Move
+// empty, when the history does not reach that far back.
+func Summarize(curves []models.YieldCurve, asOf string) (*models.RatesSummary, error) {
+	var kept []models.YieldCurve
+	for _, c := range curves {
+		if asOf == "" || c.Date <= asOf {
+			kept = append(kept, c)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].Date < kept[j].Date })
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("no treasury yields on or before %s", asOf)
+	}
+	latest := kept[len(kept)-1]
+	y2, ok2 := latest.Yields["2Y"]
+	y10, ok10 := latest.Yields["10Y"]
+	if !ok2 || !ok10 {
+		return nil, fmt.Errorf("treasury curve of %s lacks the 2- or 10-year yield", latest.Date)
+	}
+
+	s := &models.RatesSummary{AsOf: latest.Date, Spread2s10s: bp(y10 - y2)}
+	for tenor, y := range latest.Yields {
+		s.Yields = append(s.Yields, models.TenorYield{Tenor: tenor, Yield: y})
+	}
+	sort.Slice(s.Yields, func(i, j int) bool {
+		return dataflows.TenorYears(s.Yields[i].Tenor) < dataflows.TenorYears(s.Yields[j].Tenor)
+	})
+	if y3m, ok := latest.Yields["3M"]; ok {
+		s.Spread3m10y = bp(y10 - y3m)
+	}
+	switch {
+	case s.Spread2s10s < 0:
+		s.Shape = models.CurveInverted
+	case s.Spread2s10s < flatSpread:
+		s.Shape = models.CurveFlat
+	default:
+		s.Shape = models.CurveNormal
+	}
+
+	if week := before(kept, latest.Date, 7); week != nil {
+		s.Change2Y1W, s.Change10Y1W = change(latest, *week, "2Y"), change(latest, *week, "10Y")
+	}
+	month := before(kept, latest.Date, 28)
+	if month == nil {
+		return s, nil
+	}
+	s.Change2Y1M, s.Change10Y1M = change(latest, *month, "2Y"), change(latest, *month, "10Y")
+	s.Move = move(s.Change2Y1M, s.Change10Y1M)
+
+	if s.Shape == models.CurveInverted {
+		s.Notes = append(s.Notes, fmt.Sprintf("2s10s is inverted by %.0f bp; inversions have preceded most US recessions, usually by a year or more", -s.Spread2s10s))
+	} else if bp(month.Yields["10Y"]-month.Yields["2Y"]) < 0 {
+		s.Notes = append(s.Notes, "2s10s turned positive within the month; the re-steepening after an inversion has historically come close to the start of recessions")
+	}
+	switch d := s.Change10Y1M; {
+	case d >= sharpMove:
+		s.Notes = append(s.Notes, fmt.Sprintf("the 10-year yield rose %.0f bp in a month; fast rate rises pressure equity valuations, long-duration growth stocks most", d))
+	case d <= -sharpMove:
+		s.Notes = append(s.Notes, fmt.Sprintf("the 10-year yield fell %.0f bp in a month; falling long yields support valuations but can reflect growth fears", -d))
+	}
+	return s, nil
+}
+
+// before returns the latest curve dated at least days calendar days before
+// date, or nil when the history is shorter.
+func before(curves []models.YieldCurve, date string, days int) *models.YieldCurve {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil
+	}
+	cutoff := t.AddDate(0, 0, -days).Format("2006-01-02")
+	for i := len(curves) - 1; i >= 0; i-- {
+		if curves[i].Date <= cutoff {
+			return &curves[i]
+		}
+	}
+	return nil
+}
+
+func change(now, then models.YieldCurve, tenor string) float64 {
+	a, okA := now.Yields[tenor]
+	b, okB := then.Yields[tenor]
+	if !okA || !okB {
+		return 0
+	}
+	return bp(a - b)
+}
+
+// move labels the month's curve change from the 2- and 10-year moves.
+func move(d2, d10 float64) string {
+	if math.Abs(d2) < stableMove && math.Abs(d10) < stableMove {
+		return models.CurveStable
+	}
+	rising := d2+d10 > 0
+	twist := d10 - d2
+	switch {
+	case math.Abs(twist) < parallelBand && rising:
+		return models.CurveParallelRise
+	case math.Abs(twist) < parallelBand:
+		return models.CurveParallelFall
+	case rising && twist > 0:
+		return models.CurveBearSteepening
+	case rising:
+		return models.CurveBearFlattening
+	case twist > 0:
+		return models.CurveBullSteepening
+	default:
+		return models.CurveBullFlattening
+	}
+}
+
+// bp converts a difference of percentage yields to basis points, rounded
+// to a tenth so float noise does not show in reports.
+func bp(pct float64) float64 {
+	return math.Round(pct*1000) / 10
+}
+
+// moveMeaning is the usual macro reading of each curve move.
+var moveMeaning = map[string]string{
+	models.CurveBearSteepening: "long yields rising faster: term premium, inflation or supply worries",
+	models.CurveBearFlattening: "short yields rising faster: the market is pricing tighter policy",
+	models.CurveBullSteepening: "short yields falling faster: the market is pricing rate cuts",
+	models.CurveBullFlattening: "long yields falling faster: growth worries or a flight to safety",
+	models.CurveParallelRise:   "yields rising across the curve: tighter financial conditions",
+	models.CurveParallelFall:   "yields falling across the curve: easier financial conditions",
+	models.CurveStable:         "little change in rates",
+}
+
+// Render formats the summary as markdown for the agents.
+func Render(s *models.RatesSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# US Treasury Yield Curve (as of %s)\n\n", s.AsOf)
+	tenors, yields := make([]string, len(s.Yields)), make([]string, len(s.Yields))
+	for i, y := range s.Yields {
+		tenors[i], yields[i] = y.Tenor, fmt.Sprintf("%.2f", y.Yield)
+	}
+	fmt.Fprintf(&b, "| Tenor | %s |\n|%s\n| Yield %% | %s |\n\n", strings.Join(tenors, " | "), strings.Repeat("---|", len(tenors)+1), strings.Join(yields, " | "))
+
+	fmt.Fprintf(&b, "- **2s10s spread:** %+.0f bp (%s curve)\n", s.Spread2s10s, s.Shape)
+	if s.Spread3m10y != 0 {
+		fmt.Fprintf(&b, "- **3m10y spread:** %+.0f bp\n", s.Spread3m10y)
+	}
+	fmt.Fprintf(&b, "- **2-year:** %.2f%% (1w %+.0f bp, 1m %+.0f bp)\n", yieldOf(s, "2Y"), s.Change2Y1W, s.Change2Y1M)
+	fmt.Fprintf(&b, "- **10-year:** %.2f%% (1w %+.0f bp, 1m %+.0f bp)\n", yieldOf(s, "10Y"), s.Change10Y1W, s.Change10Y1M)
+	if s.Move != "" {
+		fmt.Fprintf(&b, "- **Curve over the month:** %s (%s)\n", s.Move, moveMeaning[s.Move])
+	}
+	if len(s.Notes) > 0 {
+		b.WriteString("\n## Notes\n\n")
+		for _, n := range s.Notes {
+			fmt.Fprintf(&b, "- %s\n", n)
+		}
+	}
+	return b.String()
+}
+
+// Brief is the one-line version of the summary for prompts.
+func Brief(s *models.RatesSummary) string {
+	line := fmt.Sprintf("US Treasuries (as of %s): 2Y %.2f%%, 10Y %.2f%%, 2s10s %+.0f bp (%s)", s.AsOf, yieldOf(s, "2Y"), yieldOf(s, "10Y"), s.Spread2s10s, s.Shape)
+	if s.Move != "" {
+		line += fmt.Sprintf("; 10Y %+.0f bp over a month, %s", s.Change10Y1M, s.Move)
+	}
+	if len(s.Notes) > 0 {
+		line += "; " + strings.Join(s.Notes, "; ")
+	}
+	return line
+}
+
+func yieldOf(s *models.RatesSummary, tenor string) float64 {
+	for _, y := range s.Yields {
+		if y.Tenor == tenor {
+			return y.Yield
+		}
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+package rates
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// curves builds one curve per weekday from 2025-01-01 with the 2- and
+// 10-year yields moving linearly by the given daily steps.
+func curves(days int, y2, y10, step2, step10 float64) []models.YieldCurve {
+	var out []models.YieldCurve
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; len(out) < days; i++ {
+		d := day.AddDate(0, 0, i)
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			continue
+		}
+		n := float64(len(out))
+		out = append(out, models.YieldCurve{Date: d.Format("2006-01-02"), Yields: map[string]float64{
+			"3M": 4.3, "2Y": y2 + n*step2, "10Y": y10 + n*step10, "30Y": 4.9,
+		}})
+	}
+	return out
+}
+
+func TestSummarize(t *testing.T) {
+	// 10Y rising 2 bp a day, 2Y flat: a bear steepening out of an inversion
+	c := curves(30, 4.30, 4.10, 0, 0.02)
+	s, err := Summarize(c, "")
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if s.AsOf != c[len(c)-1].Date || s.Yields[0].Tenor != "3M" || s.Yields[len(s.Yields)-1].Tenor != "30Y" {
+		t.Errorf("summary = %+v", s)
+	}
+	if s.Spread2s10s != 38 || s.Shape != models.CurveNormal || s.Move != models.CurveBearSteepening {
+		t.Errorf("spread %.1f, shape %s, move %s", s.Spread2s10s, s.Shape, s.Move)
+	}
+	if s.Change10Y1M < 38 || s.Change2Y1M != 0 || s.Change10Y1W != 10 {
+		t.Errorf("changes: 10Y 1m %.1f, 2Y 1m %.1f, 10Y 1w %.1f", s.Change10Y1M, s.Change2Y1M, s.Change10Y1W)
+	}
+	if len(s.Notes) != 2 || !strings.Contains(s.Notes[0], "turned positive") || !strings.Contains(s.Notes[1], "rose") {
+		t.Errorf("notes = %q", s.Notes)
+	}
+	if out := Render(s); !strings.Contains(out, "2s10s spread:** +38 bp (normal curve)") || !strings.Contains(out, "bear steepening") {
+		t.Errorf("render:\n%s", out)
+	}
+
+	// asOf cuts the history: an inverted curve with no month to compare
+	s, err = Summarize(c, c[4].Date)
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	if s.Shape != models.CurveInverted || s.Move != "" || s.Change2Y1M != 0 {
+		t.Errorf("short history: %+v", s)
+	}
+
+	if _, err := Summarize(c, "2024-12-31"); err == nil {
+		t.Error("want error before the first curve")
+	}
+}
+
+func TestMove(t *testing.T) {
+	for _, tc := range []struct {
+		d2, d10 float64
+		want    string
+	}{
+		{3, -4, models.CurveStable},
+		{20, 25, models.CurveParallelRise},
+		{-20, -15, models.CurveParallelFall},
+		{30, 5, models.CurveBearFlattening},
+		{-40, -10, models.CurveBullSteepening},
+		{-5, -30, models.CurveBullFlattening},
+	} {
+		if got := move(tc.d2, tc.d10); got != tc.want {
+			t.Errorf("move(%v, %v) = %s, want %s", tc.d2, tc.d10, got, tc.want)
+		}
+	}
+}
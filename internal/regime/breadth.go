@@ -0,0 +1,146 @@
+package regime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// breadthBars covers 52 weeks of sessions for new highs and lows.
+const breadthBars = 260
+
+// breadthWorkers bounds concurrent fetches; an index has up to 500 members.
+const breadthWorkers = 8
+
+// Breadth measures how broad a move is across an index's members: how many
+// advanced on the day, sit above their 50- and 200-day averages and closed
+// at 52-week highs or lows. Bars after tradeDate are ignored; members
+// without 50 bars are listed as missing.
+func Breadth(ctx context.Context, fetch dataflows.BarFetcher, index string, members []models.IndexMember, tradeDate string) (*models.IndexBreadth, error) {
+	type stat struct {
+		asOf                    string
+		change                  int // sign of the last close-to-close move
+		above50, above200       bool
+		has200, newHigh, newLow bool
+		ok                      bool
+	}
+	stats := make([]stat, len(members))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(breadthWorkers, len(members)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				bars, err := fetch(ctx, members[i].Symbol, breadthBars)
+				if err != nil {
+					continue
+				}
+				bars = dataflows.BarsUpTo(bars, tradeDate)
+				if len(bars) < 50 {
+					continue
+				}
+				closes := dataflows.Closes(bars)
+				last, prev := closes[len(closes)-1], closes[len(closes)-2]
+				s := stat{asOf: bars[len(bars)-1].Date, ok: true, above50: last > sma(closes, 50)}
+				switch {
+				case last > prev:
+					s.change = 1
+				case last < prev:
+					s.change = -1
+				}
+				if len(closes) >= 200 {
+					s.has200, s.above200 = true, last > sma(closes, 200)
+				}
+				year := closes[max(0, len(closes)-252):]
+				hi, lo := year[0], year[0]
+				for _, c := range year {
+					hi, lo = max(hi, c), min(lo, c)
+				}
+				s.newHigh, s.newLow = last >= hi, last <= lo
+				stats[i] = s
+			}
+		}()
+	}
+	for i := range members {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	b := &models.IndexBreadth{Index: index, Members: len(members)}
+	above50, above200, with200 := 0, 0, 0
+	type sectorCount struct{ counted, above int }
+	sectors := map[string]*sectorCount{}
+	for i, s := range stats {
+		if !s.ok {
+			b.Missing = append(b.Missing, members[i].Symbol)
+			continue
+		}
+		b.Counted++
+		if s.asOf > b.AsOf {
+			b.AsOf = s.asOf
+		}
+		switch s.change {
+		case 1:
+			b.Advancers++
+		case -1:
+			b.Decliners++
+		default:
+			b.Unchanged++
+		}
+		if s.above50 {
+			above50++
+		}
+		if s.has200 {
+			with200++
+			if s.above200 {
+				above200++
+			}
+		}
+		if s.newHigh {
+			b.NewHighs++
+		}
+		if s.newLow {
+			b.NewLows++
+		}
+		if name := members[i].Sector; name != "" {
+			sc := sectors[name]
+			if sc == nil {
+				sc = &sectorCount{}
+				sectors[name] = sc
+			}
+			sc.counted++
+			if s.above50 {
+				sc.above++
+			}
+		}
+	}
+	if b.Counted == 0 {
+		return nil, fmt.Errorf("no price data for the members of %s", index)
+	}
+	b.Above50 = float64(above50) / float64(b.Counted)
+	if with200 > 0 {
+		b.Above200 = float64(above200) / float64(with200)
+	}
+	for name, sc := range sectors {
+		b.Sectors = append(b.Sectors, models.SectorBreadth{Sector: name, Counted: sc.counted, Above50: float64(sc.above) / float64(sc.counted)})
+	}
+	sort.Slice(b.Sectors, func(i, j int) bool {
+		if b.Sectors[i].Above50 != b.Sectors[j].Above50 {
+			return b.Sectors[i].Above50 > b.Sectors[j].Above50
+		}
+		return b.Sectors[i].Sector < b.Sectors[j].Sector
+	})
+	return b, nil
+}
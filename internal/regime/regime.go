@@ -0,0 +1,345 @@
+// Package regime summarizes the broad market on the trade date (index trend,
+// volatility, sector leadership and breadth) into a risk-on/risk-off label so
+// single-stock analysis accounts for the tape.
+package regime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rates"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/internal/volatility"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// lookbackBars covers the 200-day average plus a margin for holidays.
+const lookbackBars = 220
+
+// Score thresholds for the label.
+const (
+	riskOnScore  = 2
+	riskOffScore = -2
+)
+
+type benchmark struct {
+	Symbol    string
+	Name      string
+	Defensive bool
+}
+
+type universe struct {
+	Indices []benchmark
+	VIX     bool // the CBOE VIX family prices this market's volatility
+	Sectors []benchmark
+}
+
+// universes lists the benchmarks per market; indices are tracked through
+// liquid ETFs so every market uses the same daily bar source.
+var universes = map[string]universe{
+	"US": {
+		Indices: []benchmark{
+			{Symbol: "SPY.US", Name: "S&P 500"},
+			{Symbol: "QQQ.US", Name: "Nasdaq 100"},
+			{Symbol: "IWM.US", Name: "Russell 2000"},
+		},
+		VIX: true,
+		Sectors: []benchmark{
+			{Symbol: "XLK.US", Name: "Technology"},
+			{Symbol: "XLY.US", Name: "Consumer Discretionary"},
+			{Symbol: "XLC.US", Name: "Communication Services"},
+			{Symbol: "XLF.US", Name: "Financials"},
+			{Symbol: "XLI.US", Name: "Industrials"},
+			{Symbol: "XLB.US", Name: "Materials"},
+			{Symbol: "XLE.US", Name: "Energy"},
+			{Symbol: "XLRE.US", Name: "Real Estate"},
+			{Symbol: "XLV.US", Name: "Health Care", Defensive: true},
+			{Symbol: "XLP.US", Name: "Consumer Staples", Defensive: true},
+			{Symbol: "XLU.US", Name: "Utilities", Defensive: true},
+		},
+	},
+	"HK": {
+		Indices: []benchmark{
+			{Symbol: "2800.HK", Name: "Hang Seng Index"},
+			{Symbol: "2828.HK", Name: "Hang Seng China Enterprises"},
+			{Symbol: "3033.HK", Name: "Hang Seng TECH"},
+		},
+	},
+	"CN": {
+		Indices: []benchmark{
+			{Symbol: "510300.SH", Name: "CSI 300"},
+			{Symbol: "510500.SH", Name: "CSI 500"},
+			{Symbol: "159915.SZ", Name: "ChiNext"},
+		},
+	},
+}
+
+// MarketOf maps a ticker's suffix to the market whose benchmarks apply;
+// bare tickers are treated as US listings.
+func MarketOf(symbol string) string {
+	i := strings.LastIndex(symbol, ".")
+	if i < 0 {
+		return "US"
+	}
+	switch strings.ToUpper(symbol[i+1:]) {
+	case "HK":
+		return "HK"
+	case "SH", "SZ":
+		return "CN"
+	}
+	return "US"
+}
+
+// Load builds the regime for symbol's market on tradeDate from the market
+// data tools, so cache and offline mode apply as for the analysts. US
+// Treasury yields are attached for every market as the global rates
+// backdrop; without them the regime is still returned. Within a batch
+// session the regime of each market is built once and shared by every
+// analysis; callers must not modify it.
+func Load(ctx context.Context, cfg *config.Config, symbol, tradeDate string) (*models.MarketRegime, error) {
+	market := MarketOf(symbol)
+	v, err := dataflows.SessionFrom(ctx).Do("regime:"+market+":"+tradeDate, func() (any, error) {
+		r, err := Build(ctx, func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+			return tools.FetchMarketData(ctx, cfg, symbol, count)
+		}, market, tradeDate)
+		if err != nil {
+			return nil, err
+		}
+		r.Rates, _ = rates.Load(ctx, cfg, tradeDate)
+		return r, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*models.MarketRegime), nil
+}
+
+// Build fetches the benchmarks of market and scores them. Bars after
+// tradeDate are ignored so backtests do not see the future. Missing
+// benchmarks are skipped; it fails only when no index has data.
+func Build(ctx context.Context, fetch dataflows.BarFetcher, market, tradeDate string) (*models.MarketRegime, error) {
+	u, ok := universes[market]
+	if !ok {
+		return nil, fmt.Errorf("no benchmarks for market %q", market)
+	}
+	r := &models.MarketRegime{Market: market}
+	load := func(symbol string) []*models.MarketData {
+		bars, err := fetch(ctx, symbol, lookbackBars)
+		if err != nil {
+			return nil
+		}
+		bars = dataflows.BarsUpTo(bars, tradeDate)
+		if len(bars) > 0 && bars[len(bars)-1].Date > r.AsOf {
+			r.AsOf = bars[len(bars)-1].Date
+		}
+		return bars
+	}
+
+	for _, b := range u.Indices {
+		bars := load(b.Symbol)
+		if len(bars) < 50 {
+			continue
+		}
+		closes := dataflows.Closes(bars)
+		last := closes[len(closes)-1]
+		r.Indices = append(r.Indices, models.IndexTrend{
+			Symbol:      b.Symbol,
+			Name:        b.Name,
+			Close:       last,
+			Return20d:   returnPct(closes, 20),
+			AboveSMA50:  last > sma(closes, 50),
+			AboveSMA200: len(closes) >= 200 && last > sma(closes, 200),
+		})
+	}
+	if len(r.Indices) == 0 {
+		return nil, fmt.Errorf("no index data for market %s", market)
+	}
+	if u.VIX {
+		if v, err := volatility.Assess(ctx, fetch, tradeDate); err == nil {
+			r.VIX, r.Volatility = v.VIX, v
+			if v.AsOf > r.AsOf {
+				r.AsOf = v.AsOf
+			}
+		}
+	}
+	for _, b := range u.Sectors {
+		bars := load(b.Symbol)
+		if len(bars) < 50 {
+			continue
+		}
+		closes := dataflows.Closes(bars)
+		r.Sectors = append(r.Sectors, models.SectorTrend{
+			Symbol:     b.Symbol,
+			Name:       b.Name,
+			Return20d:  returnPct(closes, 20),
+			AboveSMA50: closes[len(closes)-1] > sma(closes, 50),
+			Defensive:  b.Defensive,
+		})
+	}
+	sort.SliceStable(r.Sectors, func(i, j int) bool { return r.Sectors[i].Return20d > r.Sectors[j].Return20d })
+
+	score(r)
+	return r, nil
+}
+
+// score adds one point per supportive signal and subtracts one per
+// hostile one, then labels the total.
+func score(r *models.MarketRegime) {
+	add := func(points int, format string, args ...any) {
+		r.Score += points
+		r.Signals = append(r.Signals, fmt.Sprintf("%+d ", points)+fmt.Sprintf(format, args...))
+	}
+
+	above50, above200 := 0, 0
+	for _, idx := range r.Indices {
+		if idx.AboveSMA50 {
+			above50++
+		}
+		if idx.AboveSMA200 {
+			above200++
+		}
+	}
+	switch n := len(r.Indices); {
+	case above200*2 > n:
+		add(1, "%d/%d indices above their 200-day average", above200, n)
+	case above200*2 < n:
+		add(-1, "%d/%d indices above their 200-day average", above200, n)
+	}
+	switch n := len(r.Indices); {
+	case above50*2 > n:
+		add(1, "%d/%d indices above their 50-day average", above50, n)
+	case above50*2 < n:
+		add(-1, "%d/%d indices above their 50-day average", above50, n)
+	}
+
+	switch v := r.VIX; {
+	case v == 0:
+	case v < 15:
+		add(1, "VIX %.1f is calm", v)
+	case v >= 30:
+		add(-2, "VIX %.1f signals stress", v)
+	case v >= 20:
+		add(-1, "VIX %.1f is elevated", v)
+	}
+
+	// breadth and leadership use sectors when the market has them, else the indices
+	var offense, defense []float64
+	healthy, total := 0, 0
+	for _, s := range r.Sectors {
+		total++
+		if s.AboveSMA50 {
+			healthy++
+		}
+		if s.Defensive {
+			defense = append(defense, s.Return20d)
+		} else {
+			offense = append(offense, s.Return20d)
+		}
+	}
+	if total == 0 {
+		healthy, total = above50, len(r.Indices)
+	}
+	r.Breadth = float64(healthy) / float64(total)
+	switch {
+	case r.Breadth >= 0.7:
+		add(1, "breadth %.0f%% above the 50-day average", r.Breadth*100)
+	case r.Breadth <= 0.3:
+		add(-1, "breadth %.0f%% above the 50-day average", r.Breadth*100)
+	}
+	if len(offense) > 0 && len(defense) > 0 {
+		spread := mean(offense) - mean(defense)
+		switch {
+		case spread > 1:
+			add(1, "cyclical sectors lead defensives by %.1f pts over 20 days", spread)
+		case spread < -1:
+			add(-1, "defensive sectors lead cyclicals by %.1f pts over 20 days", -spread)
+		}
+	}
+
+	switch {
+	case r.Score >= riskOnScore:
+		r.Label = models.RegimeRiskOn
+	case r.Score <= riskOffScore:
+		r.Label = models.RegimeRiskOff
+	default:
+		r.Label = models.RegimeNeutral
+	}
+}
+
+// Context is the briefing for the analysts of state; empty when the regime
+// step is disabled in the config.
+func Context(state *models.TradingState) string {
+	if state.Config != nil && state.Config.SkipMarketContext {
+		return ""
+	}
+	return Prompt(state.MarketRegime)
+}
+
+// Prompt renders the regime as a short briefing for the analyst prompts.
+func Prompt(r *models.MarketRegime) string {
+	if r == nil {
+		return "Market regime: unavailable; judge the stock on its own data."
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Market regime (%s market, as of %s): %s (score %+d).\n", r.Market, r.AsOf, strings.ToUpper(r.Label), r.Score)
+	for _, idx := range r.Indices {
+		trend := "below"
+		if idx.AboveSMA50 {
+			trend = "above"
+		}
+		fmt.Fprintf(&b, "- %s: %.2f, %+.1f%% over 20 days, %s its 50-day average\n", idx.Name, idx.Close, idx.Return20d, trend)
+	}
+	switch {
+	case r.Volatility != nil:
+		fmt.Fprintf(&b, "- %s\n", volatility.Brief(r.Volatility))
+	case r.VIX > 0:
+		fmt.Fprintf(&b, "- VIX: %.1f\n", r.VIX)
+	}
+	if n := len(r.Sectors); n > 0 {
+		top, bottom := r.Sectors[:min(3, n)], r.Sectors[max(n-3, 0):]
+		fmt.Fprintf(&b, "- Leading sectors: %s; lagging: %s\n", sectorList(top), sectorList(bottom))
+	}
+	fmt.Fprintf(&b, "- Breadth: %.0f%% above the 50-day average\n", r.Breadth*100)
+	for _, s := range r.Signals {
+		fmt.Fprintf(&b, "  %s\n", s)
+	}
+	if r.Rates != nil {
+		fmt.Fprintf(&b, "- %s\n", rates.Brief(r.Rates))
+	}
+	b.WriteString("Weigh the stock's signals against this backdrop: in risk-off tapes demand stronger evidence for longs and expect high-beta names to follow the market; in risk-on tapes, relative weakness is a warning.")
+	return b.String()
+}
+
+func sectorList(sectors []models.SectorTrend) string {
+	parts := make([]string, len(sectors))
+	for i, s := range sectors {
+		parts[i] = fmt.Sprintf("%s %+.1f%%", s.Name, s.Return20d)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sma(closes []float64, n int) float64 {
+	return mean(closes[max(len(closes)-n, 0):])
+}
+
+func returnPct(closes []float64, n int) float64 {
+	if len(closes) <= n || closes[len(closes)-1-n] == 0 {
+		return 0
+	}
+	return (closes[len(closes)-1]/closes[len(closes)-1-n] - 1) * 100
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
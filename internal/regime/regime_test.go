@@ -0,0 +1,155 @@
+package regime
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyike/CortexGo/internal/bartest"
+	"github.com/dyike/CortexGo/models"
+)
+
+// trend returns n daily bars ending 2025-06-30 that move by step per day.
+func trend(n int, start, step float64) []*models.MarketData {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = start + step*float64(i)
+	}
+	return bartest.Bars(closes)
+}
+
+func TestBuildLabelsRiskOnAndOff(t *testing.T) {
+	up, down := map[string][]*models.MarketData{}, map[string][]*models.MarketData{}
+	for _, b := range universes["US"].Indices {
+		up[b.Symbol] = trend(220, 100, 0.5)
+		down[b.Symbol] = trend(220, 300, -0.5)
+	}
+	for _, b := range universes["US"].Sectors {
+		if b.Defensive {
+			up[b.Symbol] = trend(220, 100, 0.05)
+			down[b.Symbol] = trend(220, 100, 0.1)
+		} else {
+			up[b.Symbol] = trend(220, 100, 0.3)
+			down[b.Symbol] = trend(220, 300, -0.5)
+		}
+	}
+	up[".VIX.US"] = trend(5, 13, 0)
+	down[".VIX.US"] = trend(5, 32, 0)
+
+	ctx := context.Background()
+	r, err := Build(ctx, bartest.Fetcher(up), "US", "2025-06-30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Label != models.RegimeRiskOn || r.Breadth != 1 || r.AsOf != "2025-06-30" {
+		t.Errorf("up tape = %+v", r)
+	}
+	r, err = Build(ctx, bartest.Fetcher(down), "US", "2025-06-30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Label != models.RegimeRiskOff || !r.Sectors[0].Defensive {
+		t.Errorf("down tape = %+v", r)
+	}
+	if text := Prompt(r); !strings.Contains(text, "RISK-OFF") || !strings.Contains(text, "VIX 32.0 signals stress") {
+		t.Errorf("prompt:\n%s", text)
+	}
+}
+
+func TestBuildIgnoresBarsAfterTradeDate(t *testing.T) {
+	// the index rallied until mid-June and then crashed; a run dated before the crash must not see it
+	bars := append(trend(200, 100, 0.5), trend(10, 50, -1)...)
+	for i, bar := range bars[200:] {
+		bar.Date = time.Date(2025, 7, 1+i, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	}
+	r, err := Build(context.Background(), bartest.Fetcher(map[string][]*models.MarketData{"2800.HK": bars}), "HK", "2025-06-30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.AsOf != "2025-06-30" || r.Indices[0].Close != bars[199].Close || !r.Indices[0].AboveSMA50 {
+		t.Errorf("regime = %+v", r)
+	}
+	if _, err := Build(context.Background(), bartest.Fetcher(nil), "HK", "2025-06-30"); err == nil {
+		t.Error("want error without index data")
+	}
+	if MarketOf("600519.SH") != "CN" || MarketOf("700.HK") != "HK" || MarketOf("AAPL") != "US" {
+		t.Error("MarketOf mismatch")
+	}
+}
+
+func TestBreadthCountsMembers(t *testing.T) {
+	members := []models.IndexMember{
+		{Symbol: "AAA.US", Sector: "Tech"},
+		{Symbol: "BBB.US", Sector: "Tech"},
+		{Symbol: "CCC.US", Sector: "Energy"},
+		{Symbol: "NEW.US", Sector: "Energy"},
+	}
+	series := map[string][]*models.MarketData{
+		"AAA.US": trend(260, 100, 0.5),  // steady riser at a 52-week high
+		"BBB.US": trend(260, 300, -0.5), // steady faller at a 52-week low
+		"CCC.US": trend(120, 100, 0.2),  // too short for the 200-day average
+		"NEW.US": trend(20, 10, 0.1),    // listed too recently to count
+	}
+	b, err := Breadth(context.Background(), bartest.Fetcher(series), "SP500", members, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Counted != 3 || len(b.Missing) != 1 || b.Missing[0] != "NEW.US" || b.AsOf != "2025-06-30" {
+		t.Fatalf("breadth = %+v", b)
+	}
+	if b.Advancers != 2 || b.Decliners != 1 || b.NewHighs != 2 || b.NewLows != 1 {
+		t.Errorf("advance/decline = %+v", b)
+	}
+	if math.Abs(b.Above50-2.0/3) > 1e-9 || b.Above200 != 0.5 {
+		t.Errorf("above 50 = %.2f, above 200 = %.2f", b.Above50, b.Above200)
+	}
+	if len(b.Sectors) != 2 || b.Sectors[0].Sector != "Energy" || b.Sectors[0].Above50 != 1 || b.Sectors[1].Above50 != 0.5 {
+		t.Errorf("sectors = %+v", b.Sectors)
+	}
+}
+
+func TestRotationRanksSectorsAgainstBenchmark(t *testing.T) {
+	series := map[string][]*models.MarketData{"SPY.US": trend(220, 100, 0.2)}
+	for _, b := range universes["US"].Sectors {
+		series[b.Symbol] = trend(220, 100, 0.1)
+	}
+	// technology leads on every window; utilities lagged but turned up over the last month
+	series["XLK.US"] = trend(220, 100, 0.5)
+	utilities := trend(220, 200, -0.3)
+	for i, bar := range utilities[len(utilities)-20:] {
+		bar.Close = utilities[len(utilities)-21].Close * (1 + 0.005*float64(i+1))
+	}
+	series["XLU.US"] = utilities
+	series["XLE.US"] = trend(60, 100, 0.1)
+
+	r, err := Rotation(context.Background(), bartest.Fetcher(series), "US", "2025-06-30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Benchmark != "SPY.US" || r.AsOf != "2025-06-30" || len(r.Missing) != 1 || r.Missing[0] != "XLE.US" {
+		t.Fatalf("rotation = %+v", r)
+	}
+	if top := r.Sectors[0]; top.Symbol != "XLK.US" || top.Rank != 1 || top.Phase != models.RotationLeading {
+		t.Errorf("leader = %+v", top)
+	}
+	for _, s := range r.Sectors {
+		switch s.Symbol {
+		case "XLU.US":
+			if s.Phase != models.RotationImproving {
+				t.Errorf("utilities phase = %s, relative %v", s.Phase, s.Relative)
+			}
+		case "XLF.US":
+			if s.Phase != models.RotationLagging {
+				t.Errorf("financials phase = %s, relative %v", s.Phase, s.Relative)
+			}
+		}
+	}
+	if got := Leaders(r, 2); len(got) != 2 || got[0] != "XLK.US" {
+		t.Errorf("leaders = %v", got)
+	}
+	if _, err := Rotation(context.Background(), bartest.Fetcher(series), "HK", "2025-06-30"); err == nil {
+		t.Error("HK has no sector ETFs")
+	}
+}
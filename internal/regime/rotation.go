@@ -0,0 +1,176 @@
+package regime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// RotationWindows are the look-back windows of the rotation ranking, in
+// trading days: about a week, a month, a quarter and half a year.
+var RotationWindows = []int{5, 20, 60, 120}
+
+// Windows that decide a sector's rotation phase: the medium-term window
+// gives its relative strength, the short-term one its momentum.
+const (
+	StrengthWindow = 60
+	MomentumWindow = 20
+)
+
+// Rotation ranks the sector ETFs of market by their returns relative to the
+// market's first index over each of RotationWindows. A sector's score is its
+// average rank across the windows, so a sector has to lead on several
+// horizons to come out on top. Bars after tradeDate are ignored; sectors
+// without enough history are listed as missing.
+func Rotation(ctx context.Context, fetch dataflows.BarFetcher, market, tradeDate string) (*models.SectorRotation, error) {
+	u, ok := universes[market]
+	if !ok || len(u.Sectors) == 0 || len(u.Indices) == 0 {
+		return nil, fmt.Errorf("no sector ETFs for market %q", market)
+	}
+	need := RotationWindows[len(RotationWindows)-1] + 1
+	load := func(symbol string) []float64 {
+		bars, err := fetch(ctx, symbol, lookbackBars)
+		if err != nil {
+			return nil
+		}
+		bars = dataflows.BarsUpTo(bars, tradeDate)
+		if len(bars) < need {
+			return nil
+		}
+		return dataflows.Closes(bars)
+	}
+	returns := func(closes []float64) []float64 {
+		out := make([]float64, len(RotationWindows))
+		for i, n := range RotationWindows {
+			out[i] = returnPct(closes, n)
+		}
+		return out
+	}
+
+	bench := u.Indices[0]
+	benchBars, err := fetch(ctx, bench.Symbol, lookbackBars)
+	if err != nil {
+		return nil, fmt.Errorf("benchmark %s: %w", bench.Symbol, err)
+	}
+	benchBars = dataflows.BarsUpTo(benchBars, tradeDate)
+	if len(benchBars) < need {
+		return nil, fmt.Errorf("benchmark %s has %d bars, need %d", bench.Symbol, len(benchBars), need)
+	}
+	r := &models.SectorRotation{
+		Market:           market,
+		AsOf:             benchBars[len(benchBars)-1].Date,
+		Benchmark:        bench.Symbol,
+		Windows:          RotationWindows,
+		BenchmarkReturns: returns(dataflows.Closes(benchBars)),
+	}
+
+	for _, b := range u.Sectors {
+		closes := load(b.Symbol)
+		if closes == nil {
+			r.Missing = append(r.Missing, b.Symbol)
+			continue
+		}
+		s := models.SectorPerformance{
+			Symbol:     b.Symbol,
+			Name:       b.Name,
+			Defensive:  b.Defensive,
+			Close:      closes[len(closes)-1],
+			Returns:    returns(closes),
+			AboveSMA50: closes[len(closes)-1] > sma(closes, 50),
+		}
+		s.Relative = make([]float64, len(s.Returns))
+		for i := range s.Returns {
+			s.Relative[i] = s.Returns[i] - r.BenchmarkReturns[i]
+		}
+		s.Phase = phase(s.Relative[windowIndex(StrengthWindow)], s.Relative[windowIndex(MomentumWindow)])
+		r.Sectors = append(r.Sectors, s)
+	}
+	if len(r.Sectors) == 0 {
+		return nil, fmt.Errorf("no sector data for market %s", market)
+	}
+
+	for w := range RotationWindows {
+		order := make([]int, len(r.Sectors))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return r.Sectors[order[a]].Relative[w] > r.Sectors[order[b]].Relative[w] })
+		for rank, i := range order {
+			r.Sectors[i].Score += float64(rank+1) / float64(len(RotationWindows))
+		}
+	}
+	// ties go to the stronger month
+	m := windowIndex(MomentumWindow)
+	sort.SliceStable(r.Sectors, func(i, j int) bool {
+		if r.Sectors[i].Score != r.Sectors[j].Score {
+			return r.Sectors[i].Score < r.Sectors[j].Score
+		}
+		return r.Sectors[i].Relative[m] > r.Sectors[j].Relative[m]
+	})
+	for i := range r.Sectors {
+		r.Sectors[i].Rank = i + 1
+	}
+	r.Tilt = tilt(r.Sectors)
+	return r, nil
+}
+
+// Leaders returns the symbols of the n best ranked sectors.
+func Leaders(r *models.SectorRotation, n int) []string {
+	var out []string
+	for _, s := range r.Sectors {
+		if len(out) == n {
+			break
+		}
+		out = append(out, s.Symbol)
+	}
+	return out
+}
+
+// phase places a sector in its rotation cycle from its relative strength
+// over the medium term and its relative momentum over the short term.
+func phase(strength, momentum float64) string {
+	switch {
+	case strength > 0 && momentum > 0:
+		return models.RotationLeading
+	case strength > 0:
+		return models.RotationWeakening
+	case momentum > 0:
+		return models.RotationImproving
+	}
+	return models.RotationLagging
+}
+
+// tilt reads the style of the leadership: defensive sectors on top point to
+// a risk-off rotation, defensive sectors at the bottom to a cyclical one.
+func tilt(sectors []models.SectorPerformance) string {
+	n := min(3, len(sectors))
+	defensive := func(list []models.SectorPerformance) int {
+		c := 0
+		for _, s := range list {
+			if s.Defensive {
+				c++
+			}
+		}
+		return c
+	}
+	top, bottom := defensive(sectors[:n]), defensive(sectors[len(sectors)-n:])
+	switch {
+	case top*2 > n:
+		return models.TiltDefensive
+	case top == 0 && bottom*2 > n:
+		return models.TiltCyclical
+	}
+	return models.TiltMixed
+}
+
+func windowIndex(n int) int {
+	for i, w := range RotationWindows {
+		if w == n {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,191 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Comparison describes what changed between two runs of the same symbol.
+type Comparison struct {
+	Symbol          string           `json:"symbol"`
+	From            string           `json:"from"`
+	To              string           `json:"to"`
+	RatingFrom      string           `json:"rating_from"`
+	RatingTo        string           `json:"rating_to"`
+	RatingChanged   bool             `json:"rating_changed"`
+	ConfidenceFrom  float64          `json:"confidence_from"`
+	ConfidenceTo    float64          `json:"confidence_to"`
+	NewRisks        []string         `json:"new_risks"`
+	ResolvedRisks   []string         `json:"resolved_risks"`
+	IndicatorShifts []IndicatorShift `json:"indicator_shifts"`
+	Summary         string           `json:"summary"`
+}
+
+// IndicatorShift is the change of one technical indicator between the two trade dates.
+type IndicatorShift struct {
+	Name      string  `json:"name"`
+	From      float64 `json:"from"`
+	To        float64 `json:"to"`
+	ChangePct float64 `json:"change_pct"`
+}
+
+// maxRiskLines caps how many new/resolved risks are reported.
+const maxRiskLines = 10
+
+var (
+	riskKeywords = []string{"risk", "downside", "volatil", "uncertain", "headwind", "concern", "风险", "下行", "波动", "不确定", "担忧", "隐患"}
+	bulletRe     = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)、])\s+`)
+	tokenRe      = regexp.MustCompile(`[\p{Han}]|[\p{L}\p{N}]+`)
+)
+
+// Compare diffs two reports. indicatorsA/B are optional latest indicator values
+// at each run's trade date, keyed by indicator name.
+func Compare(a, b *Report, indicatorsA, indicatorsB map[string]float64) *Comparison {
+	c := &Comparison{
+		Symbol:     b.Symbol,
+		From:       a.TradeDate,
+		To:         b.TradeDate,
+		RatingFrom: a.Recommendation,
+		RatingTo:   b.Recommendation,
+	}
+	c.RatingChanged = c.RatingFrom != c.RatingTo
+	if a.Decision != nil {
+		c.ConfidenceFrom = a.Decision.Confidence
+	}
+	if b.Decision != nil {
+		c.ConfidenceTo = b.Decision.Confidence
+	}
+
+	risksA, risksB := riskLines(a), riskLines(b)
+	c.NewRisks = diffLines(risksB, risksA)
+	c.ResolvedRisks = diffLines(risksA, risksB)
+
+	names := make([]string, 0, len(indicatorsB))
+	for name := range indicatorsB {
+		if _, ok := indicatorsA[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		from, to := indicatorsA[name], indicatorsB[name]
+		shift := IndicatorShift{Name: name, From: from, To: to}
+		if from != 0 {
+			shift.ChangePct = (to - from) / math.Abs(from) * 100
+		}
+		c.IndicatorShifts = append(c.IndicatorShifts, shift)
+	}
+	c.Summary = c.markdown()
+	return c
+}
+
+// riskLines collects bullet points mentioning risk from the risk debate and final decision.
+func riskLines(r *Report) []string {
+	var out []string
+	for _, key := range []string{"risk_debate", "final_trade_decision"} {
+		for _, line := range strings.Split(r.Section(key), "\n") {
+			if !bulletRe.MatchString(line) {
+				continue
+			}
+			text := strings.TrimSpace(bulletRe.ReplaceAllString(line, ""))
+			lower := strings.ToLower(text)
+			for _, kw := range riskKeywords {
+				if strings.Contains(lower, kw) {
+					out = append(out, text)
+					break
+				}
+			}
+		}
+	}
+	return out
+}
+
+// diffLines returns lines in src that have no similar line in other. LLM output
+// never repeats verbatim, so similarity is token overlap rather than equality.
+func diffLines(src, other []string) []string {
+	var out []string
+	for _, line := range src {
+		novel := true
+		for _, o := range other {
+			if similarity(line, o) >= 0.5 {
+				novel = false
+				break
+			}
+		}
+		if novel {
+			out = append(out, line)
+			if len(out) == maxRiskLines {
+				break
+			}
+		}
+	}
+	return out
+}
+
+// similarity is the Jaccard index of the word (or Han character) sets of a and b.
+func similarity(a, b string) float64 {
+	ta, tb := tokenSet(a), tokenSet(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	inter := 0
+	for t := range ta {
+		if tb[t] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(ta)+len(tb)-inter)
+}
+
+func tokenSet(s string) map[string]bool {
+	set := map[string]bool{}
+	for _, t := range tokenRe.FindAllString(strings.ToLower(s), -1) {
+		set[t] = true
+	}
+	return set
+}
+
+func (c *Comparison) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# What changed: %s %s → %s\n\n", c.Symbol, c.From, c.To)
+	from, to := orNA(c.RatingFrom), orNA(c.RatingTo)
+	if c.RatingChanged {
+		fmt.Fprintf(&b, "- **Rating:** %s → %s\n", from, to)
+	} else {
+		fmt.Fprintf(&b, "- **Rating:** unchanged (%s)\n", to)
+	}
+	if c.ConfidenceFrom > 0 || c.ConfidenceTo > 0 {
+		fmt.Fprintf(&b, "- **Confidence:** %.0f%% → %.0f%%\n", c.ConfidenceFrom*100, c.ConfidenceTo*100)
+	}
+
+	writeList := func(title string, items []string) {
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		if len(items) == 0 {
+			b.WriteString("- none\n")
+			return
+		}
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	writeList("New risks", c.NewRisks)
+	writeList("Resolved risks", c.ResolvedRisks)
+
+	if len(c.IndicatorShifts) > 0 {
+		b.WriteString("\n## Indicator shifts\n\n| Indicator | From | To | Change |\n| --- | --- | --- | --- |\n")
+		for _, s := range c.IndicatorShifts {
+			fmt.Fprintf(&b, "| %s | %.4f | %.4f | %+.2f%% |\n", s.Name, s.From, s.To, s.ChangePct)
+		}
+	}
+	return b.String()
+}
+
+func orNA(s string) string {
+	if s == "" {
+		return "N/A"
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/models"
+)
+
+var (
+	confidenceRe = regexp.MustCompile(`(?i)(?:CONFIDENCE|置信度|信心)\s*[：:]\s*\**\s*([0-9]+(?:\.[0-9]+)?)\s*(%)?`)
+	entryRe      = regexp.MustCompile(`(?i)(?:ENTRY PRICE|入场价|建仓价)\s*[：:]\s*\**\s*\$?([0-9]+(?:\.[0-9]+)?)`)
+	stopLossRe   = regexp.MustCompile(`(?i)(?:STOP LOSS|止损价?)\s*[：:]\s*\**\s*\$?([0-9]+(?:\.[0-9]+)?)`)
+	takeProfitRe = regexp.MustCompile(`(?i)(?:TAKE PROFIT|止盈价?|目标价)\s*[：:]\s*\**\s*\$?([0-9]+(?:\.[0-9]+)?)`)
+	positionRe   = regexp.MustCompile(`(?i)(?:POSITION SIZE|仓位)\s*[：:]\s*\**\s*([0-9]+(?:\.[0-9]+)?)\s*(%)?`)
+	holdingRe    = regexp.MustCompile(`(?i)(?:HOLDING PERIOD|持有期)\s*[：:]\s*\**\s*([0-9]+)`)
+)
+
+// ExtractDecision parses the structured trailer of the risk judge's output into
+// a trading decision. Missing fields stay zero.
+func ExtractDecision(r *Report) *models.TradingDecision {
+	text := r.Section("final_trade_decision")
+	if text == "" {
+		text = r.Section("trader_investment_plan")
+	}
+	d := &models.TradingDecision{
+		Symbol:     r.Symbol,
+		Date:       r.TradeDate,
+		Action:     r.Recommendation,
+		Reasoning:  text,
+		Confidence: ParseConfidence(text),
+		EntryPrice: parseNumber(entryRe, text),
+		StopLoss:   parseNumber(stopLossRe, text),
+		TakeProfit: parseNumber(takeProfitRe, text),
+
+		PositionSize: parseFraction(positionRe, text),
+		HoldingDays:  int(parseNumber(holdingRe, text)),
+	}
+	if !r.GeneratedAt.IsZero() {
+		d.Timestamp = r.GeneratedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return d
+}
+
+// confidenceSections maps the sections whose authors state a confidence to the
+// agent that wrote them.
+var confidenceSections = []struct {
+	Key   string
+	Agent string
+}{
+	{Key: "final_trade_decision", Agent: consts.RiskJudge},
+	{Key: "trader_investment_plan", Agent: consts.Trader},
+	{Key: "investment_plan", Agent: consts.ResearchManager},
+	{Key: "market_report", Agent: consts.MarketAnalyst},
+	{Key: "social_report", Agent: consts.SocialAnalyst},
+	{Key: "news_report", Agent: consts.NewsAnalyst},
+	{Key: "fundamentals_report", Agent: consts.FundamentalsAnalyst},
+}
+
+// AgentConfidences returns the recommendation and confidence each agent stated
+// in its own section. Agents that gave no confidence or no clear call are skipped.
+func AgentConfidences(r *Report) []models.AgentConfidence {
+	var out []models.AgentConfidence
+	for _, s := range confidenceSections {
+		text := r.Section(s.Key)
+		conf := ParseConfidence(text)
+		action := ParseRecommendation(text)
+		if conf <= 0 || action == "" {
+			continue
+		}
+		out = append(out, models.AgentConfidence{Agent: s.Agent, Action: action, Confidence: conf})
+	}
+	return out
+}
+
+// DecisionAgent returns the agent whose section ExtractDecision reads.
+func DecisionAgent(r *Report) string {
+	if r.Section("final_trade_decision") == "" {
+		return consts.Trader
+	}
+	return consts.RiskJudge
+}
+
+// ParseConfidence returns the stated confidence normalised to [0, 1], or 0 when absent.
+func ParseConfidence(text string) float64 {
+	return parseFraction(confidenceRe, text)
+}
+
+// parseFraction reads a ratio written either as a fraction or a percentage
+// (values above 1 count as percent) and returns it in [0, 1], or 0.
+func parseFraction(re *regexp.Regexp, text string) float64 {
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	v, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0
+	}
+	if m[2] == "%" || v > 1 {
+		v /= 100
+	}
+	if v < 0 || v > 1 {
+		return 0
+	}
+	return v
+}
+
+func parseNumber(re *regexp.Regexp, text string) float64 {
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return 0
+	}
+	v, _ := strconv.ParseFloat(strings.TrimSpace(m[1]), 64)
+	return v
+}
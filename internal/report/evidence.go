@@ -0,0 +1,161 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/models"
+)
+
+// evidenceSources lists the sections whose claims are traced, from the final
+// decision back to the analysts, paired with the agent that called the tools.
+// Debate transcripts are skipped: their conclusions reach the plans anyway.
+var evidenceSources = []struct {
+	Key   string
+	Agent string
+}{
+	{Key: "final_trade_decision"},
+	{Key: "trader_investment_plan"},
+	{Key: "investment_plan"},
+	{Key: "market_report", Agent: consts.MarketAnalyst},
+	{Key: "social_report", Agent: consts.SocialAnalyst},
+	{Key: "news_report", Agent: consts.NewsAnalyst},
+	{Key: "fundamentals_report", Agent: consts.FundamentalsAnalyst},
+}
+
+// traceEvidence fills rep.Claims and rep.Evidence from the tool outputs recorded
+// during the run and appends an "Evidence Chain" section rendering them.
+func traceEvidence(rep *Report, evidence []*models.Evidence) {
+	if len(evidence) == 0 {
+		return
+	}
+	var sources []provenance.Source
+	for _, src := range evidenceSources {
+		if text := rep.Section(src.Key); text != "" {
+			sources = append(sources, provenance.Source{Key: src.Key, Agent: src.Agent, Text: text})
+		}
+	}
+	rep.Claims = provenance.Trace(sources, evidence)
+	if len(rep.Claims) == 0 {
+		return
+	}
+	used := map[string]bool{}
+	for _, c := range rep.Claims {
+		for _, id := range c.Evidence {
+			used[id] = true
+		}
+	}
+	for _, e := range evidence {
+		if used[e.ID] {
+			kept := *e
+			kept.DataPoints = nil // only needed for matching
+			rep.Evidence = append(rep.Evidence, &kept)
+		}
+	}
+	rep.Sections = append(rep.Sections, Section{Key: "evidence_chain", Title: "Evidence Chain", Content: rep.evidenceChain()})
+}
+
+// evidenceChain renders the traced claims grouped by section, followed by the
+// tool calls they cite, so a reader can follow the recommendation to its data.
+func (r *Report) evidenceChain() string {
+	var b strings.Builder
+	rec := r.Recommendation
+	if rec == "" {
+		rec = "N/A"
+	}
+	fmt.Fprintf(&b, "Recommendation **%s** rests on %d traced claims backed by %d tool outputs.\n", rec, len(r.Claims), len(r.Evidence))
+
+	titles := map[string]string{}
+	for _, s := range r.Sections {
+		titles[s.Key] = s.Title
+	}
+	section := ""
+	for _, c := range r.Claims {
+		if c.Section != section {
+			section = c.Section
+			fmt.Fprintf(&b, "\n### %s\n\n", titles[section])
+		}
+		refs := make([]string, len(c.Evidence))
+		for i, id := range c.Evidence {
+			refs[i] = "[" + id + "]"
+		}
+		fmt.Fprintf(&b, "- %s ← %s", c.Text, strings.Join(refs, " "))
+		if len(c.DataPoints) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(c.DataPoints, ", "))
+		}
+		if !c.Cited {
+			b.WriteString(" _inferred_")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n### Sources\n\n")
+	for _, e := range r.Evidence {
+		fmt.Fprintf(&b, "- **[%s]** %s via `%s`", e.ID, e.Agent, e.Tool)
+		if e.Arguments != "" {
+			fmt.Fprintf(&b, " `%s`", e.Arguments)
+		}
+		fmt.Fprintf(&b, ": %s", e.Excerpt)
+		if e.Provenance != nil {
+			fmt.Fprintf(&b, " — %s", provenance.Describe(e.Provenance, r.GeneratedAt))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// staleAfter is the age past which an input is flagged in the freshness
+// summary; every source's own cache TTL is shorter.
+const staleAfter = 24 * time.Hour
+
+// summarizeFreshness fills rep.Freshness with one merged provenance per data
+// source and appends a "Data Freshness" section, so a reader can tell
+// half-hour-old news from a week-old cache.
+func summarizeFreshness(rep *Report, evidence []*models.Evidence) {
+	bySource := map[string]*models.Provenance{}
+	calls := map[string]int{}
+	for _, e := range evidence {
+		if e.Provenance == nil {
+			continue
+		}
+		src := e.Provenance.Source
+		if p, ok := bySource[src]; ok {
+			provenance.Merge(p, *e.Provenance)
+		} else {
+			p := *e.Provenance
+			bySource[src] = &p
+			rep.Freshness = append(rep.Freshness, &p)
+		}
+		calls[src]++
+	}
+	if len(rep.Freshness) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	var oldest time.Time
+	hits, lookups := 0, 0
+	for _, p := range rep.Freshness {
+		if oldest.IsZero() || p.FetchedAt.Before(oldest) {
+			oldest = p.FetchedAt
+		}
+		hits += p.CacheHits
+		lookups += p.CacheHits + p.CacheMisses
+	}
+	fmt.Fprintf(&b, "The oldest input was fetched %s before this report", provenance.Age(rep.GeneratedAt.Sub(oldest)))
+	if lookups > 0 {
+		fmt.Fprintf(&b, "; %d of %d data lookups were served from cache", hits, lookups)
+	}
+	b.WriteString(".\n\n")
+	for _, p := range rep.Freshness {
+		fmt.Fprintf(&b, "- %s, %d tool calls", provenance.Describe(p, rep.GeneratedAt), calls[p.Source])
+		if p.Mode != models.ProvenanceLocal && rep.GeneratedAt.Sub(p.FetchedAt) > staleAfter {
+			b.WriteString(" _stale_")
+		}
+		b.WriteString("\n")
+	}
+	rep.Sections = append(rep.Sections, Section{Key: "data_freshness", Title: "Data Freshness", Content: b.String()})
+}
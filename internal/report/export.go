@@ -0,0 +1,108 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Exporter renders a report into a single file.
+type Exporter struct {
+	Ext         string
+	ContentType string
+	Render      func(r *Report) ([]byte, error)
+	// Levels marks formats that draw chart levels, which include supports
+	// and resistances when Report.Structure is attached first.
+	Levels bool
+}
+
+var exporters = map[string]Exporter{
+	"json": {
+		Ext:         ".json",
+		ContentType: "application/json",
+		Render: func(r *Report) ([]byte, error) {
+			return json.MarshalIndent(r, "", "  ")
+		},
+	},
+	"html": {
+		Ext:         ".html",
+		ContentType: "text/html; charset=utf-8",
+		Render: func(r *Report) ([]byte, error) {
+			return []byte(r.HTML()), nil
+		},
+	},
+	"md": {
+		Ext:         ".md",
+		ContentType: "text/markdown; charset=utf-8",
+		Render: func(r *Report) ([]byte, error) {
+			return []byte(r.Markdown()), nil
+		},
+	},
+	"pdf": {
+		Ext:         ".pdf",
+		ContentType: "application/pdf",
+		Render: func(r *Report) ([]byte, error) {
+			return r.PDF()
+		},
+	},
+	"ics": {
+		Ext:         ".ics",
+		ContentType: "text/calendar; charset=utf-8",
+		Render: func(r *Report) ([]byte, error) {
+			return r.ICS(), nil
+		},
+	},
+	"pine": {
+		Ext:         ".pine",
+		ContentType: "text/plain; charset=utf-8",
+		Levels:      true,
+		Render: func(r *Report) ([]byte, error) {
+			return []byte(r.PineScript()), nil
+		},
+	},
+	"tv_csv": {
+		Ext:         ".csv",
+		ContentType: "text/csv; charset=utf-8",
+		Levels:      true,
+		Render: func(r *Report) ([]byte, error) {
+			return r.LevelsCSV()
+		},
+	},
+	"tv_alerts": {
+		Ext:         ".json",
+		ContentType: "application/json",
+		Levels:      true,
+		Render: func(r *Report) ([]byte, error) {
+			return json.MarshalIndent(r.Alerts(), "", "  ")
+		},
+	},
+}
+
+// Formats lists the supported export formats.
+func Formats() []string {
+	out := make([]string, 0, len(exporters))
+	for name := range exporters {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// LookupExporter returns the exporter registered for format.
+func LookupExporter(format string) (Exporter, error) {
+	exp, ok := exporters[strings.ToLower(strings.TrimSpace(format))]
+	if !ok {
+		return Exporter{}, fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return exp, nil
+}
+
+// Export renders r in the given format.
+func Export(r *Report, format string) ([]byte, error) {
+	exp, err := LookupExporter(format)
+	if err != nil {
+		return nil, err
+	}
+	return exp.Render(r)
+}
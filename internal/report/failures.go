@@ -0,0 +1,29 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// recordFailures copies the panics recovered during the run into the report
+// and appends a "Node Failures" section naming the steps whose output is
+// missing.
+func recordFailures(rep *Report, failures []*models.NodeFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	rep.NodeFailures = failures
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d step(s) crashed during the run and were skipped; the sections they feed are incomplete.\n\n", len(failures))
+	for _, f := range failures {
+		step := f.Agent + " model call"
+		if f.Tool != "" {
+			step = f.Agent + " → `" + f.Tool + "`"
+		}
+		fmt.Fprintf(&b, "- %s at %s: %s\n", step, f.At.Format("15:04:05"), f.Error)
+	}
+	rep.Sections = append(rep.Sections, Section{Key: "node_failures", Title: "Node Failures", Content: strings.TrimSpace(b.String())})
+}
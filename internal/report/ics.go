@@ -0,0 +1,128 @@
+package report
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/internal/catalysts"
+	"github.com/dyike/CortexGo/models"
+)
+
+// icsLineLimit is the longest content line RFC 5545 allows, in octets.
+const icsLineLimit = 75
+
+// addCatalysts lists the catalysts the run found in a "Upcoming Catalysts"
+// section, so they are visible in every format and not only the calendar.
+func addCatalysts(rep *Report, cs []models.Catalyst) {
+	if len(cs) == 0 {
+		return
+	}
+	rep.Catalysts = cs
+	var b strings.Builder
+	b.WriteString("| Date | Time | Event | Detail |\n|---|---|---|---|\n")
+	for _, c := range cs {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", c.Date, catalysts.When(c), catalystTitle(c), c.Detail)
+	}
+	rep.Sections = append(rep.Sections, Section{Key: "upcoming_catalysts", Title: "Upcoming Catalysts", Content: b.String()})
+}
+
+func catalystTitle(c models.Catalyst) string {
+	if c.Estimated {
+		return c.Title + " (estimated)"
+	}
+	return c.Title
+}
+
+// ICS renders the report's catalysts as an iCalendar file to import into or
+// subscribe to from a calendar app.
+func (r *Report) ICS() []byte {
+	name := fmt.Sprintf("CortexGo %s catalysts", r.Symbol)
+	from := fmt.Sprintf("From the CortexGo analysis of %s on %s.", r.Symbol, r.TradeDate)
+	return CalendarICS(name, r.Catalysts, from, time.Now())
+}
+
+// CalendarICS renders catalysts as all-day events of a calendar called name.
+// note is appended to every event description. Event UIDs depend only on the
+// catalyst, so re-importing an updated calendar replaces events instead of
+// duplicating them.
+func CalendarICS(name string, cs []models.Catalyst, note string, stamp time.Time) []byte {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICS(s))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//CortexGo//Catalysts//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICS(name))
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+	for _, c := range cs {
+		day, err := time.Parse("2006-01-02", c.Date)
+		if err != nil {
+			continue
+		}
+		summary := catalystTitle(c)
+		if when := catalysts.When(c); when != "" {
+			summary += " (" + when + ")"
+		}
+		var desc []string
+		if c.Detail != "" {
+			desc = append(desc, c.Detail+".")
+		}
+		if c.Source != "" {
+			desc = append(desc, "Source: "+c.Source+".")
+		}
+		if note != "" {
+			desc = append(desc, note)
+		}
+		sum := sha1.Sum([]byte(catalysts.Key(c)))
+
+		line("BEGIN:VEVENT")
+		line("UID:" + hex.EncodeToString(sum[:8]) + "@cortexgo")
+		line("DTSTAMP:" + dtstamp)
+		line("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escapeICS(summary))
+		if len(desc) > 0 {
+			line("DESCRIPTION:" + escapeICS(strings.Join(desc, "\n")))
+		}
+		line("CATEGORIES:" + escapeICS(c.Kind))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICS(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// foldICS splits a content line longer than 75 octets into continuation
+// lines starting with a space, without cutting a UTF-8 character in two.
+func foldICS(s string) string {
+	if len(s) <= icsLineLimit {
+		return s
+	}
+	var b strings.Builder
+	limit := icsLineLimit
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > limit {
+			b.WriteString("\r\n ")
+			n = 0
+			limit = icsLineLimit - 1 // the leading space counts
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
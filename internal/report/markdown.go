@@ -0,0 +1,105 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// markdownGroups mirrors the order agents run in the graph, so the exported
+// note reads like the analysis itself: analysts, debate, plan, risk, decision.
+var markdownGroups = []struct {
+	Title string
+	Keys  []string
+}{
+	{Title: "Analyst Reports", Keys: []string{"market_report", "social_report", "news_report", "fundamentals_report"}},
+	{Title: "Research Debate", Keys: []string{"investment_debate", "investment_plan"}},
+	{Title: "Trading Plan", Keys: []string{"trader_investment_plan"}},
+	{Title: "Risk Management", Keys: []string{"risk_debate"}},
+	{Title: "Final Decision", Keys: []string{"final_trade_decision"}},
+}
+
+// Markdown renders the report with YAML front matter so it can be dropped
+// into Obsidian/Notion vaults and queried by symbol, date or recommendation.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "symbol: %q\n", r.Symbol)
+	fmt.Fprintf(&b, "trade_date: %q\n", r.TradeDate)
+	if r.Recommendation != "" {
+		fmt.Fprintf(&b, "recommendation: %s\n", r.Recommendation)
+	}
+	if r.SessionID != "" {
+		fmt.Fprintf(&b, "session_id: %q\n", r.SessionID)
+	}
+	if r.RiskProfile != "" {
+		fmt.Fprintf(&b, "risk_profile: %s\n", r.RiskProfile)
+	}
+	if r.MarketRegime != "" {
+		fmt.Fprintf(&b, "market_regime: %s\n", r.MarketRegime)
+	}
+	if !r.GeneratedAt.IsZero() {
+		fmt.Fprintf(&b, "generated_at: %s\n", r.GeneratedAt.Format("2006-01-02T15:04:05Z07:00"))
+	}
+	b.WriteString("tags: [cortexgo, trading-analysis]\n")
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s %s\n\n", r.Symbol, r.TradeDate)
+	rec := r.Recommendation
+	if rec == "" {
+		rec = "N/A"
+	}
+	fmt.Fprintf(&b, "**Recommendation:** %s\n", rec)
+
+	seen := make(map[string]bool)
+	for _, group := range markdownGroups {
+		var parts []Section
+		for _, key := range group.Keys {
+			for _, s := range r.Sections {
+				if s.Key == key {
+					parts = append(parts, s)
+					seen[key] = true
+				}
+			}
+		}
+		if len(parts) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", group.Title)
+		for _, s := range parts {
+			fmt.Fprintf(&b, "\n### %s\n\n%s\n", s.Title, demoteHeadings(s.Content, 3))
+		}
+	}
+	for _, s := range r.Sections {
+		if !seen[s.Key] {
+			fmt.Fprintf(&b, "\n## %s\n\n%s\n", s.Title, demoteHeadings(s.Content, 2))
+		}
+	}
+	return b.String()
+}
+
+// demoteHeadings pushes markdown headings inside agent output below the given
+// level so they nest under the export's own headings. Code fences are left alone.
+func demoteHeadings(content string, level int) string {
+	lines := strings.Split(content, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		hashes := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+		if hashes > 6 || len(trimmed) == hashes || trimmed[hashes] != ' ' {
+			continue
+		}
+		newLevel := hashes + level
+		if newLevel > 6 {
+			newLevel = 6
+		}
+		lines[i] = strings.Repeat("#", newLevel) + trimmed[hashes:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// HoldBand is the absolute return within which a HOLD call counts as correct.
+const HoldBand = 0.02
+
+// EvaluateOutcome scores a recommendation against daily bars: entry is the
+// close on (or last before) tradeDate, exit is the close horizon bars later.
+func EvaluateOutcome(action string, bars []*models.MarketData, tradeDate string, horizon int) (*models.OutcomeRecord, error) {
+	if horizon <= 0 {
+		return nil, fmt.Errorf("horizon must be positive")
+	}
+	sorted := make([]*models.MarketData, 0, len(bars))
+	for _, b := range bars {
+		if b != nil && b.Close > 0 {
+			sorted = append(sorted, b)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	entry := -1
+	for i, b := range sorted {
+		if b.Date > tradeDate {
+			break
+		}
+		entry = i
+	}
+	if entry < 0 {
+		return nil, fmt.Errorf("no bar on or before %s", tradeDate)
+	}
+	exit := entry + horizon
+	if exit >= len(sorted) {
+		return nil, fmt.Errorf("horizon %d not yet reached for %s", horizon, tradeDate)
+	}
+
+	ret := sorted[exit].Close/sorted[entry].Close - 1
+	correct, err := OutcomeCorrect(action, ret)
+	if err != nil {
+		return nil, err
+	}
+	return &models.OutcomeRecord{
+		HorizonDays: horizon,
+		EvalDate:    sorted[exit].Date,
+		EntryClose:  sorted[entry].Close,
+		ExitClose:   sorted[exit].Close,
+		ReturnPct:   ret * 100,
+		Correct:     correct,
+	}, nil
+}
+
+// OutcomeCorrect reports whether action was right given the fractional return
+// over the holding period.
+func OutcomeCorrect(action string, ret float64) (bool, error) {
+	switch action {
+	case "BUY":
+		return ret > 0, nil
+	case "SELL":
+		return ret < 0, nil
+	case "HOLD":
+		return math.Abs(ret) <= HoldBand, nil
+	}
+	return false, fmt.Errorf("unknown action %q", action)
+}
@@ -0,0 +1,286 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"strings"
+	"unicode/utf8"
+)
+
+// The PDF writer is deliberately small: A4 pages, Courier for ASCII text,
+// Helvetica-Bold for headings and the Adobe STSong-Light CID font for
+// everything else so Chinese analysis output renders without embedding fonts.
+
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+	pdfBodySize   = 9.5
+	pdfLeading    = 1.35
+)
+
+const (
+	fontBody    = "F1"
+	fontHeading = "F2"
+	fontCJK     = "F3"
+)
+
+type pdfWriter struct {
+	pages  []*bytes.Buffer
+	cur    *bytes.Buffer
+	y      float64
+	images []image.Image
+}
+
+// PDF renders the report as a PDF document.
+func (r *Report) PDF() ([]byte, error) {
+	w := &pdfWriter{}
+	w.newPage()
+
+	w.heading(r.Symbol+"  "+r.TradeDate, 18)
+	rec := r.Recommendation
+	if rec == "" {
+		rec = "N/A"
+	}
+	w.heading("Recommendation: "+rec, 13)
+	w.paragraph("Generated at "+r.GeneratedAt.Format("2006-01-02 15:04:05"), pdfBodySize)
+	w.space(8)
+	if r.ChartImage != nil {
+		w.image(r.ChartImage)
+		w.space(8)
+	}
+
+	for _, s := range r.Sections {
+		w.heading(s.Title, 12)
+		w.paragraph(s.Content, pdfBodySize)
+		w.space(6)
+	}
+	return w.bytes(), nil
+}
+
+func (w *pdfWriter) newPage() {
+	w.cur = &bytes.Buffer{}
+	w.pages = append(w.pages, w.cur)
+	w.y = pdfPageHeight - pdfMargin
+}
+
+func (w *pdfWriter) ensure(height float64) {
+	if w.y-height < pdfMargin {
+		w.newPage()
+	}
+}
+
+func (w *pdfWriter) space(h float64) {
+	w.y -= h
+}
+
+func (w *pdfWriter) heading(text string, size float64) {
+	w.ensure(size * pdfLeading * 1.5)
+	w.y -= size * 0.5
+	for _, line := range wrapText(text, size, pdfPageWidth-2*pdfMargin) {
+		w.ensure(size * pdfLeading)
+		w.y -= size * pdfLeading
+		w.showLine(line, size, fontHeading)
+	}
+}
+
+func (w *pdfWriter) paragraph(text string, size float64) {
+	text = strings.ReplaceAll(text, "\t", "    ")
+	for _, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		lines := wrapText(raw, size, pdfPageWidth-2*pdfMargin)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		for _, line := range lines {
+			w.ensure(size * pdfLeading)
+			w.y -= size * pdfLeading
+			w.showLine(line, size, fontBody)
+		}
+	}
+}
+
+// image draws img scaled to the text width.
+func (w *pdfWriter) image(img image.Image) {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return
+	}
+	width := pdfPageWidth - 2*pdfMargin
+	height := width * float64(bounds.Dy()) / float64(bounds.Dx())
+	w.ensure(height)
+	w.y -= height
+	w.images = append(w.images, img)
+	fmt.Fprintf(w.cur, "q %.2f 0 0 %.2f %.2f %.2f cm /Im%d Do Q\n", width, height, pdfMargin, w.y, len(w.images))
+}
+
+// showLine writes one line, switching to the CID font for non-ASCII runs.
+func (w *pdfWriter) showLine(line string, size float64, asciiFont string) {
+	if line == "" {
+		return
+	}
+	fmt.Fprintf(w.cur, "BT %.2f %.2f Td\n", pdfMargin, w.y)
+	for _, run := range splitRuns(line) {
+		if run.ascii {
+			fmt.Fprintf(w.cur, "/%s %.2f Tf (%s) Tj\n", asciiFont, size, escapePDFString(run.text))
+		} else {
+			fmt.Fprintf(w.cur, "/%s %.2f Tf <%s> Tj\n", fontCJK, size, ucs2Hex(run.text))
+		}
+	}
+	w.cur.WriteString("ET\n")
+}
+
+type textRun struct {
+	text  string
+	ascii bool
+}
+
+func splitRuns(s string) []textRun {
+	var runs []textRun
+	var b strings.Builder
+	curASCII := true
+	for i, r := range s {
+		isASCII := r < utf8.RuneSelf
+		if i > 0 && isASCII != curASCII && b.Len() > 0 {
+			runs = append(runs, textRun{text: b.String(), ascii: curASCII})
+			b.Reset()
+		}
+		curASCII = isASCII
+		b.WriteRune(r)
+	}
+	if b.Len() > 0 {
+		runs = append(runs, textRun{text: b.String(), ascii: curASCII})
+	}
+	return runs
+}
+
+// runeWidth approximates glyph advance in em: Courier is 0.6em, CJK glyphs are full width.
+func runeWidth(r rune) float64 {
+	if r < utf8.RuneSelf {
+		return 0.6
+	}
+	return 1.0
+}
+
+// wrapText breaks s into lines no wider than maxWidth points, preferring spaces.
+func wrapText(s string, size, maxWidth float64) []string {
+	var lines []string
+	runes := []rune(s)
+	for len(runes) > 0 {
+		width := 0.0
+		cut := len(runes)
+		lastSpace := -1
+		for i, r := range runes {
+			width += runeWidth(r) * size
+			if r == ' ' {
+				lastSpace = i
+			}
+			if width > maxWidth {
+				cut = i
+				if lastSpace > 0 && runes[i] < utf8.RuneSelf {
+					cut = lastSpace + 1
+				}
+				break
+			}
+		}
+		if cut == 0 {
+			cut = 1
+		}
+		lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+		runes = runes[cut:]
+	}
+	return lines
+}
+
+func escapePDFString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`)
+	return r.Replace(s)
+}
+
+// ucs2Hex encodes s for the UniGB-UCS2-H CMap; runes outside the BMP become '?'.
+func ucs2Hex(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 0xFFFF {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	return b.String()
+}
+
+func (w *pdfWriter) bytes() []byte {
+	var objs []string
+	add := func(body string) int {
+		objs = append(objs, body)
+		return len(objs)
+	}
+
+	catalog := add("") // patched once the pages object id is known
+	pagesID := add("")
+	f1 := add("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	f2 := add("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	desc := add("<< /Type /FontDescriptor /FontName /STSong-Light /Flags 6 /FontBBox [-25 -254 1000 880] " +
+		"/ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>")
+	cid := add(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /STSong-Light "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (GB1) /Supplement 2 >> /FontDescriptor %d 0 R /DW 1000 >>", desc))
+	f3 := add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /STSong-Light /Encoding /UniGB-UCS2-H /DescendantFonts [%d 0 R] >>", cid))
+	var xobjects strings.Builder
+	for i, img := range w.images {
+		data := imageRGB(img)
+		id := add(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB "+
+			"/BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+			img.Bounds().Dx(), img.Bounds().Dy(), len(data), data))
+		fmt.Fprintf(&xobjects, " /Im%d %d 0 R", i+1, id)
+	}
+	resources := fmt.Sprintf("<< /Font << /%s %d 0 R /%s %d 0 R /%s %d 0 R >>", fontBody, f1, fontHeading, f2, fontCJK, f3)
+	if xobjects.Len() > 0 {
+		resources += " /XObject <<" + xobjects.String() + " >>"
+	}
+	resources += " >>"
+
+	kids := make([]string, 0, len(w.pages))
+	for _, page := range w.pages {
+		content := page.Bytes()
+		streamID := add(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+		pageID := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources %s /Contents %d 0 R >>",
+			pagesID, pdfPageWidth, pdfPageHeight, resources, streamID))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID))
+	}
+	objs[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID)
+	objs[pagesID-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	offsets := make([]int, len(objs))
+	for i, body := range objs {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, catalog, xref)
+	return out.Bytes()
+}
+
+// imageRGB returns the zlib-compressed 8-bit RGB samples of img.
+func imageRGB(img image.Image) []byte {
+	b := img.Bounds()
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	row := make([]byte, 0, b.Dx()*3)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row = row[:0]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			row = append(row, byte(r>>8), byte(g>>8), byte(bl>>8))
+		}
+		zw.Write(row)
+	}
+	zw.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,294 @@
+package report
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+)
+
+// Redactions report_redact can list.
+const (
+	RedactURLs    = "urls"
+	RedactSocial  = "social"
+	RedactSources = "sources"
+)
+
+// Placeholders left where redacted content was.
+const (
+	linkRemoved   = "[link removed]"
+	postRemoved   = "[social media post removed]"
+	userRemoved   = "[user]"
+	sourceLabelAs = "[source %d]"
+)
+
+var (
+	markdownLinkRe = regexp.MustCompile(`\[([^\]\n]*)\]\((?:https?://|www\.)[^)\s]*\)`)
+	bareURLRe      = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"'()\[\]]+`)
+	socialUserRe   = regexp.MustCompile(`(^|[^\w/])/?u/[A-Za-z0-9_-]{2,}`)
+	postLineRe     = regexp.MustCompile(`(?m)^([ \t]*)>.*$`)
+	postContentRe  = regexp.MustCompile(`(?m)^\s*\*\*Content:\*\*.*\n?`)
+	subredditRe    = `\br/[A-Za-z0-9_]{2,}\b`
+
+	// publisherRes pick the outlet names out of the news tool outputs kept
+	// as evidence (Google News "**Source:** Reuters [wire] |", the event
+	// timeline "— Reuters (sentiment", transcript sources).
+	publisherRes = []*regexp.Regexp{
+		regexp.MustCompile(`\*\*(?:Most Active )?Source:\*\*\s*([^|\[\n(]+)`),
+		regexp.MustCompile(` — ([^—\n(]+?) \(sentiment`),
+	}
+
+	// providerNames are the data providers the tools call, as they appear in
+	// prose; each matches its structured source ID after normalizing.
+	providerNames = []string{`google[ _-]?news`, `long[ _-]?port`, `finnhub`, `fmp`, `financial modeling prep`, `reddit`, `yahoo[ _-]?finance`}
+	providerAlias = map[string]string{"financialmodelingprep": "fmp"}
+)
+
+// Redact returns a copy of r without what cfg.ReportRedact lists, for
+// reports that are redistributed and must respect source licensing:
+//
+//	urls     links are dropped, markdown links keep their text
+//	social   social media posts (reddit evidence, quoted posts, post
+//	         bodies) and user names are removed
+//	sources  names of news outlets, data providers and subreddits, and
+//	         cfg.ReportRedactTerms, become numbered labels ([source 1]),
+//	         so a reader can still tell that two claims share a source
+//
+// Outlet names are found in the evidence kept with the report and in the
+// catalysts; names only the analysts saw are caught through
+// report_redact_terms. r itself is not changed, and is returned as is when
+// nothing is to be redacted.
+func Redact(r *Report, cfg *config.Config) *Report {
+	if r == nil || cfg == nil || len(cfg.ReportRedact) == 0 {
+		return r
+	}
+	rd := &redactor{labels: make(map[string]string)}
+	for _, what := range cfg.ReportRedact {
+		switch strings.ToLower(strings.TrimSpace(what)) {
+		case RedactURLs:
+			rd.urls = true
+		case RedactSocial:
+			rd.social = true
+		case RedactSources:
+			rd.sources = true
+		}
+	}
+	if !rd.urls && !rd.social && !rd.sources {
+		return r
+	}
+	if rd.sources {
+		rd.compileNames(r, cfg.ReportRedactTerms)
+	}
+
+	out := *r
+	out.Sections = slices.Clone(r.Sections)
+	for i := range out.Sections {
+		sec := &out.Sections[i]
+		if rd.social && sec.Key == "social_report" {
+			// the social analyst quotes the posts it read
+			sec.Content = postLineRe.ReplaceAllString(sec.Content, "${1}> "+postRemoved)
+		}
+		sec.Content = rd.text(sec.Content)
+	}
+	if r.Decision != nil {
+		d := *r.Decision
+		d.Reason, d.Reasoning = rd.text(d.Reason), rd.text(d.Reasoning)
+		out.Decision = &d
+	}
+	out.Claims = slices.Clone(r.Claims)
+	for i := range out.Claims {
+		out.Claims[i].Text = rd.text(out.Claims[i].Text)
+	}
+	out.Evidence = make([]*models.Evidence, 0, len(r.Evidence))
+	for _, e := range r.Evidence {
+		out.Evidence = append(out.Evidence, rd.evidence(e))
+	}
+	out.Freshness = make([]*models.Provenance, 0, len(r.Freshness))
+	for _, p := range r.Freshness {
+		out.Freshness = append(out.Freshness, rd.provenance(p))
+	}
+	out.Catalysts = slices.Clone(r.Catalysts)
+	for i := range out.Catalysts {
+		c := &out.Catalysts[i]
+		c.Title, c.Detail = rd.text(c.Title), rd.text(c.Detail)
+		c.Source = rd.source(c.Source)
+	}
+	out.NodeFailures = make([]*models.NodeFailure, 0, len(r.NodeFailures))
+	for _, f := range r.NodeFailures {
+		if f == nil {
+			continue
+		}
+		nf := *f
+		nf.Error = rd.text(nf.Error)
+		out.NodeFailures = append(out.NodeFailures, &nf)
+	}
+	if r.RunInputs != nil && rd.sources {
+		in := *r.RunInputs
+		in.DataAsOf = make(map[string]string, len(r.RunInputs.DataAsOf))
+		for _, src := range slices.Sorted(maps.Keys(r.RunInputs.DataAsOf)) {
+			in.DataAsOf[rd.source(src)] = r.RunInputs.DataAsOf[src]
+		}
+		out.RunInputs = &in
+	}
+	return &out
+}
+
+type redactor struct {
+	urls, social, sources bool
+
+	names *regexp.Regexp
+	// known are the normalized names that are replaced; labels maps them to
+	// their label, numbered in the order the names are first met.
+	known  map[string]bool
+	labels map[string]string
+}
+
+// compileNames collects the source names of r and the configured terms into
+// one case-insensitive pattern.
+func (rd *redactor) compileNames(r *Report, terms []string) {
+	rd.known = make(map[string]bool)
+	alts := []string{subredditRe}
+	for _, p := range providerNames {
+		alts = append(alts, `\b`+p+`\b`)
+		rd.known[normalizeSource(strings.NewReplacer(`[ _-]?`, "", `\b`, "").Replace(p))] = true
+	}
+
+	var names []string
+	for _, e := range r.Evidence {
+		if e == nil {
+			continue
+		}
+		for _, re := range publisherRes {
+			for _, m := range re.FindAllStringSubmatch(e.Excerpt, -1) {
+				names = append(names, m[1])
+			}
+		}
+	}
+	for _, c := range r.Catalysts {
+		names = append(names, c.Source)
+	}
+	names = append(names, terms...)
+
+	var literal []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := normalizeSource(name)
+		if len([]rune(name)) < 3 || rd.known[key] {
+			continue
+		}
+		rd.known[key] = true
+		literal = append(literal, name)
+	}
+	// longer names first, so "Reuters Breakingviews" wins over "Reuters"
+	sort.SliceStable(literal, func(i, j int) bool { return len(literal[i]) > len(literal[j]) })
+	for _, name := range literal {
+		alt := regexp.QuoteMeta(name)
+		if isWordRune(name, true) {
+			alt = `\b` + alt
+		}
+		if isWordRune(name, false) {
+			alt += `\b`
+		}
+		alts = append([]string{alt}, alts...)
+	}
+	rd.names = regexp.MustCompile(`(?i)(?:` + strings.Join(alts, "|") + `)`)
+}
+
+// text applies the redactions to free text (markdown).
+func (rd *redactor) text(s string) string {
+	if s == "" {
+		return s
+	}
+	if rd.urls {
+		s = markdownLinkRe.ReplaceAllString(s, "$1")
+		s = bareURLRe.ReplaceAllString(s, linkRemoved)
+	}
+	if rd.social {
+		s = postContentRe.ReplaceAllString(s, "")
+		s = socialUserRe.ReplaceAllString(s, "${1}"+userRemoved)
+	}
+	if rd.sources {
+		s = rd.names.ReplaceAllStringFunc(s, rd.label)
+	}
+	return s
+}
+
+func (rd *redactor) evidence(e *models.Evidence) *models.Evidence {
+	if e == nil {
+		return nil
+	}
+	out := *e
+	if rd.social && isSocial(e) {
+		out.Excerpt, out.DataPoints = postRemoved, nil
+	} else {
+		out.Excerpt = rd.text(e.Excerpt)
+	}
+	out.Arguments = rd.text(e.Arguments)
+	out.Provenance = rd.provenance(e.Provenance)
+	return &out
+}
+
+func (rd *redactor) provenance(p *models.Provenance) *models.Provenance {
+	if p == nil {
+		return nil
+	}
+	out := *p
+	out.Source = rd.source(p.Source)
+	return &out
+}
+
+// source labels a structured source ID when it names an outlet or provider;
+// generic IDs (market, rss, documents) are kept.
+func (rd *redactor) source(id string) string {
+	if !rd.sources || !rd.known[normalizeSource(id)] {
+		return id
+	}
+	return rd.label(id)
+}
+
+func (rd *redactor) label(name string) string {
+	key := normalizeSource(name)
+	if label, ok := rd.labels[key]; ok {
+		return label
+	}
+	label := fmt.Sprintf(sourceLabelAs, len(rd.labels)+1)
+	rd.labels[key] = label
+	return label
+}
+
+// isSocial reports whether e holds social media posts.
+func isSocial(e *models.Evidence) bool {
+	return strings.HasPrefix(e.Tool, "get_reddit") || (e.Provenance != nil && e.Provenance.Source == "reddit")
+}
+
+// normalizeSource folds the spellings of a name ("Google News",
+// "google_news") together.
+func normalizeSource(name string) string {
+	key := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, strings.TrimSpace(name))
+	if alias, ok := providerAlias[key]; ok {
+		return alias
+	}
+	return key
+}
+
+// isWordRune reports whether the first (or last) rune of s is a word
+// character, where \b can anchor the match.
+func isWordRune(s string, first bool) bool {
+	r := []rune(s)
+	c := r[len(r)-1]
+	if first {
+		c = r[0]
+	}
+	return c == '_' || c < unicode.MaxASCII && (unicode.IsLetter(c) || unicode.IsDigit(c))
+}
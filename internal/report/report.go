@@ -0,0 +1,205 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"image"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+)
+
+// Section is one titled block of the final report, usually the output of a single agent.
+type Section struct {
+	Key     string `json:"key"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// Report aggregates the outcome of one analysis run for delivery and export.
+type Report struct {
+	SessionID      string    `json:"session_id,omitempty"`
+	RunID          string    `json:"run_id,omitempty"`
+	Symbol         string    `json:"symbol"`
+	TradeDate      string    `json:"trade_date"`
+	Recommendation string    `json:"recommendation"`
+	RiskProfile    string    `json:"risk_profile,omitempty"`
+	MarketRegime   string    `json:"market_regime,omitempty"`
+	Sections       []Section `json:"sections"`
+	GeneratedAt    time.Time `json:"generated_at"`
+
+	Decision *models.TradingDecision `json:"decision,omitempty"`
+
+	// Claims links statements in the sections to the tool outputs in Evidence
+	// that support them; only evidence referenced by a claim is kept.
+	Claims   []models.EvidenceClaim `json:"claims,omitempty"`
+	Evidence []*models.Evidence     `json:"evidence,omitempty"`
+
+	// Freshness summarises, per data source, how old the inputs of the run
+	// were and whether they came from cache.
+	Freshness []*models.Provenance `json:"freshness,omitempty"`
+
+	// RunInputs records what made the run nondeterministic (seed, models,
+	// prompt and data digests), so two runs can be checked for comparability.
+	RunInputs *models.RunInputs `json:"run_inputs,omitempty"`
+
+	// Catalysts are the dated events after the trade date the analysts found,
+	// exported as an .ics calendar.
+	Catalysts []models.Catalyst `json:"catalysts,omitempty"`
+
+	// NodeFailures are the tool and model calls that panicked and were
+	// skipped so the run could finish.
+	NodeFailures []*models.NodeFailure `json:"node_failures,omitempty"`
+
+	// DataBundle is the zip of the run's raw tool results, written when the
+	// run sets export_tool_data.
+	DataBundle string `json:"data_bundle,omitempty"`
+
+	// ChartSVG / ChartImage are optional price charts attached at export time.
+	ChartSVG   string      `json:"-"`
+	ChartImage image.Image `json:"-"`
+
+	// Structure is the price structure on the trade date, attached at export
+	// time for the support and resistance levels of the TradingView formats.
+	Structure *models.MarketStructure `json:"-"`
+}
+
+var (
+	recommendationRe        = regexp.MustCompile(`(?i)FINAL TRANSACTION PROPOSAL:\s*\**\s*(BUY|HOLD|SELL)`)
+	chineseRecommendationRe = regexp.MustCompile(`(?:最终|明确)?(?:建议|决策|推荐)[：:\s*]*\**\s*(买入|卖出|持有)`)
+	chineseActions          = map[string]string{"买入": "BUY", "卖出": "SELL", "持有": "HOLD"}
+)
+
+// FromState builds a report from the trading state after the graph has finished.
+func FromState(state *models.TradingState) *Report {
+	if state == nil {
+		return nil
+	}
+	rep := &Report{
+		RunID:       state.RunID,
+		Symbol:      state.CompanyOfInterest,
+		TradeDate:   state.TradeDate,
+		GeneratedAt: time.Now(),
+	}
+
+	add := func(key, title, content string) {
+		if strings.TrimSpace(content) == "" {
+			return
+		}
+		rep.Sections = append(rep.Sections, Section{Key: key, Title: title, Content: strings.TrimSpace(content)})
+	}
+	add("final_trade_decision", "Final Trade Decision", state.FinalTradeDecision)
+	add("market_report", "Market Analysis", state.MarketReport)
+	add("social_report", "Social Sentiment", state.SocialReport)
+	add("news_report", "News Analysis", state.NewsReport)
+	add("fundamentals_report", "Fundamentals Analysis", state.FundamentalsReport)
+	if state.InvestmentDebateState != nil {
+		add("investment_debate", "Bull/Bear Debate", state.InvestmentDebateState.History)
+	}
+	add("investment_plan", "Research Manager Plan", state.InvestmentPlan)
+	add("trader_investment_plan", "Trader Plan", state.TraderInvestmentPlan)
+	if state.RiskDebateState != nil {
+		add("risk_debate", "Risk Debate", state.RiskDebateState.History)
+	}
+
+	rep.Recommendation = ParseRecommendation(state.FinalTradeDecision)
+	if rep.Recommendation == "" {
+		rep.Recommendation = ParseRecommendation(state.TraderInvestmentPlan)
+	}
+	rep.Decision = ExtractDecision(rep)
+	// the judge is told the profile's cap, scaled down in volatile markets;
+	// enforce it in case the model overshoots
+	limits := config.RiskLimitsFor(state.Config)
+	rep.RiskProfile = limits.Profile
+	maxSize := limits.MaxPositionPct / 100
+	if r := state.MarketRegime; r != nil && r.Volatility != nil && r.Volatility.SizeMultiplier > 0 {
+		maxSize *= r.Volatility.SizeMultiplier
+	}
+	if rep.Decision.PositionSize > maxSize {
+		rep.Decision.PositionSize = maxSize
+	}
+	if state.MarketRegime != nil {
+		rep.MarketRegime = state.MarketRegime.Label
+	}
+	addCatalysts(rep, state.Catalysts)
+	summarizeFreshness(rep, state.Evidence)
+	recordRunInputs(rep, state.RunInputs, state.Evidence)
+	traceEvidence(rep, state.Evidence)
+	recordFailures(rep, state.NodeFailures)
+	return rep
+}
+
+// ParseRecommendation extracts BUY/HOLD/SELL from agent output, preferring the
+// explicit "FINAL TRANSACTION PROPOSAL" marker.
+func ParseRecommendation(text string) string {
+	if m := recommendationRe.FindStringSubmatch(text); len(m) > 1 {
+		return strings.ToUpper(m[1])
+	}
+	upper := strings.ToUpper(text)
+	for _, action := range []string{"BUY", "SELL", "HOLD"} {
+		if strings.Contains(upper, "**"+action+"**") {
+			return action
+		}
+	}
+	// 风控结论默认输出中文
+	if m := chineseRecommendationRe.FindStringSubmatch(text); len(m) > 1 {
+		return chineseActions[m[1]]
+	}
+	return ""
+}
+
+// Headline returns a one-line summary suitable for subjects and notifications.
+func (r *Report) Headline() string {
+	rec := r.Recommendation
+	if rec == "" {
+		rec = "N/A"
+	}
+	return fmt.Sprintf("%s %s: %s", r.Symbol, r.TradeDate, rec)
+}
+
+// Section returns the content of the section with the given key.
+func (r *Report) Section(key string) string {
+	for _, s := range r.Sections {
+		if s.Key == key {
+			return s.Content
+		}
+	}
+	return ""
+}
+
+// HTML renders the report as a standalone HTML document.
+func (r *Report) HTML() string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\">")
+	b.WriteString("<title>" + html.EscapeString(r.Headline()) + "</title></head>\n<body style=\"font-family:sans-serif;max-width:960px;margin:auto\">\n")
+	b.WriteString(fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(r.Symbol+" · "+r.TradeDate)))
+	rec := r.Recommendation
+	if rec == "" {
+		rec = "N/A"
+	}
+	b.WriteString(fmt.Sprintf("<p><strong>Recommendation:</strong> <span style=\"font-size:1.4em\">%s</span></p>\n", html.EscapeString(rec)))
+	if r.ChartSVG != "" {
+		b.WriteString("<div>" + r.ChartSVG + "</div>\n")
+	}
+	for _, s := range r.Sections {
+		b.WriteString(fmt.Sprintf("<h2>%s</h2>\n", html.EscapeString(s.Title)))
+		b.WriteString("<pre style=\"white-space:pre-wrap;font-family:inherit\">")
+		b.WriteString(html.EscapeString(s.Content))
+		b.WriteString("</pre>\n")
+	}
+	b.WriteString("</body></html>\n")
+	return b.String()
+}
+
+// Decode parses a report previously stored as JSON.
+func Decode(content string) (*Report, error) {
+	var rep Report
+	if err := json.Unmarshal([]byte(content), &rep); err != nil {
+		return nil, fmt.Errorf("decode report: %w", err)
+	}
+	return &rep, nil
+}
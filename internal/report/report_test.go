@@ -0,0 +1,552 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+)
+
+func sampleReport() *Report {
+	return &Report{
+		Symbol:         "AAPL.US",
+		TradeDate:      "2024-05-10",
+		Recommendation: "BUY",
+		GeneratedAt:    time.Date(2024, 5, 10, 16, 0, 0, 0, time.UTC),
+		Sections: []Section{
+			{Key: "final_trade_decision", Title: "Final Trade Decision", Content: "FINAL TRANSACTION PROPOSAL: **BUY** (strong momentum)"},
+			{Key: "market_report", Title: "Market Analysis", Content: strings.Repeat("RSI is rising above 50. ", 40) + "\n市场情绪偏多"},
+		},
+	}
+}
+
+func TestParseRecommendation(t *testing.T) {
+	cases := map[string]string{
+		"FINAL TRANSACTION PROPOSAL: **SELL**": "SELL",
+		"final transaction proposal: hold":     "HOLD",
+		"We recommend **BUY** given the setup": "BUY",
+		"no explicit call in this text":        "",
+	}
+	for in, want := range cases {
+		if got := ParseRecommendation(in); got != want {
+			t.Errorf("ParseRecommendation(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestPDFStructure(t *testing.T) {
+	data, err := Export(sampleReport(), "pdf")
+	if err != nil {
+		t.Fatalf("Export pdf: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	// 中文内容需走 UCS2 编码的 CID 字体
+	if !bytes.Contains(data, []byte("<5E02573A60C57EEA504F591A>")) {
+		t.Fatal("expected UCS2 hex encoding of chinese text")
+	}
+
+	m := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("startxref not found")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at xref table", xref)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		want := strconv.Itoa(i+1) + " 0 obj"
+		if !bytes.HasPrefix(data[off:], []byte(want)) {
+			t.Fatalf("xref entry %d points at %q", i+1, data[off:off+10])
+		}
+	}
+}
+
+func TestLookupExporterUnknown(t *testing.T) {
+	if _, err := LookupExporter("docx"); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+}
+
+func TestMarkdownFrontMatterAndGroups(t *testing.T) {
+	rep := sampleReport()
+	rep.Sections[1].Content = "# Overview\nprice up\n```\n# not a heading\n```"
+	md := rep.Markdown()
+
+	if !strings.HasPrefix(md, "---\nsymbol: \"AAPL.US\"\ntrade_date: \"2024-05-10\"\nrecommendation: BUY\n") {
+		t.Fatalf("unexpected front matter:\n%s", md)
+	}
+	analysts := strings.Index(md, "## Analyst Reports")
+	decision := strings.Index(md, "## Final Decision")
+	if analysts < 0 || decision < 0 || analysts > decision {
+		t.Fatalf("sections out of order:\n%s", md)
+	}
+	if !strings.Contains(md, "\n#### Overview\n") {
+		t.Fatalf("expected agent heading demoted under section:\n%s", md)
+	}
+	if !strings.Contains(md, "\n# not a heading\n") {
+		t.Fatalf("heading inside code fence should be untouched:\n%s", md)
+	}
+}
+
+func TestReportTemplates(t *testing.T) {
+	dir := t.TempDir()
+	md := filepath.Join(dir, "house.md.tmpl")
+	if err := os.WriteFile(md, []byte(`# Acme Research · {{.Symbol}}
+Call: {{.Recommendation}} ({{.Decision.Action}})
+{{range .Pick "final_trade_decision" "market_report"}}
+## {{upper .Title}}
+{{demote 2 .Content}}
+{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{ReportTemplateMD: md}
+	exp, err := ExporterFor(cfg, "md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := sampleReport()
+	rep.Sections[1].Content = "# Overview\nprice up"
+	out, err := exp.Render(rep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(out)
+	decision, market := strings.Index(text, "## FINAL TRADE DECISION"), strings.Index(text, "## MARKET ANALYSIS")
+	if !strings.HasPrefix(text, "# Acme Research · AAPL.US\nCall: BUY (BUY)\n") || decision < 0 || decision > market || !strings.Contains(text, "\n### Overview\n") {
+		t.Errorf("md template output:\n%s", text)
+	}
+
+	// html templates escape report text; other formats keep the built-in layout
+	html, err := ParseTemplate("html", "house.html", `<h1>{{.Symbol}}</h1>{{range .Except "market_report"}}<p>{{.Content}}</p>{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep.Sections[0].Content = "<script>BUY</script>"
+	out, err = html.Render(rep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "<h1>AAPL.US</h1><p>&lt;script&gt;BUY&lt;/script&gt;</p>" {
+		t.Errorf("html template output: %s", out)
+	}
+	if data := Rendered(cfg, rep, "html"); !bytes.HasPrefix(data, []byte("<!DOCTYPE html>")) {
+		t.Errorf("html without a template should use the built-in layout")
+	}
+
+	// a broken template fails the export but not the fallback rendering
+	cfg.ReportTemplateMD = filepath.Join(dir, "missing.tmpl")
+	if _, err := ExporterFor(cfg, "md"); err == nil {
+		t.Error("missing template file should fail the export")
+	}
+	if data := Rendered(cfg, rep, "md"); !bytes.HasPrefix(data, []byte("---\nsymbol:")) {
+		t.Errorf("fallback should be the built-in markdown:\n%s", data)
+	}
+}
+
+func TestExtractDecisionAndOutcome(t *testing.T) {
+	rep := sampleReport()
+	rep.Sections[0].Content = "综合判断，最终建议：**买入**\nFINAL TRANSACTION PROPOSAL: **BUY**\nCONFIDENCE: 72%\nENTRY PRICE: 182.5\nSTOP LOSS: 171\nTAKE PROFIT: N/A"
+	d := ExtractDecision(rep)
+	if d.Action != "BUY" || d.Confidence != 0.72 || d.EntryPrice != 182.5 || d.StopLoss != 171 || d.TakeProfit != 0 {
+		t.Fatalf("unexpected decision: %+v", d)
+	}
+	if got := ParseRecommendation("风险评估后，最终决策：卖出"); got != "SELL" {
+		t.Fatalf("chinese recommendation = %q", got)
+	}
+
+	bars := []*models.MarketData{
+		{Date: "2024-05-09", Close: 100},
+		{Date: "2024-05-13", Close: 101},
+		{Date: "2024-05-14", Close: 99},
+		{Date: "2024-05-15", Close: 104},
+	}
+	out, err := EvaluateOutcome("BUY", bars, "2024-05-10", 3)
+	if err != nil {
+		t.Fatalf("EvaluateOutcome: %v", err)
+	}
+	if out.EntryClose != 100 || out.ExitClose != 104 || !out.Correct || out.EvalDate != "2024-05-15" {
+		t.Fatalf("unexpected outcome: %+v", out)
+	}
+	if _, err := EvaluateOutcome("BUY", bars, "2024-05-10", 4); err == nil {
+		t.Fatal("expected error when horizon not reached")
+	}
+	if out, _ := EvaluateOutcome("HOLD", bars, "2024-05-13", 1); !out.Correct {
+		t.Fatalf("HOLD within band should be correct: %+v", out)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := &Report{Symbol: "AAPL.US", TradeDate: "2024-03-01", Recommendation: "HOLD", Sections: []Section{
+		{Key: "risk_debate", Content: "- Valuation risk remains elevated versus peers\n- 供应链风险仍需关注"},
+	}}
+	b := &Report{Symbol: "AAPL.US", TradeDate: "2024-03-15", Recommendation: "BUY", Sections: []Section{
+		{Key: "risk_debate", Content: "- Valuation risk remains elevated compared to peers\n- Regulatory risk from the EU DMA ruling\nplain text risk line"},
+	}}
+	c := Compare(a, b, map[string]float64{"rsi": 40, "macd": 1}, map[string]float64{"rsi": 60, "atr": 2})
+
+	if !c.RatingChanged || c.RatingFrom != "HOLD" || c.RatingTo != "BUY" {
+		t.Fatalf("rating change not detected: %+v", c)
+	}
+	if len(c.NewRisks) != 1 || !strings.Contains(c.NewRisks[0], "Regulatory") {
+		t.Fatalf("new risks = %v", c.NewRisks)
+	}
+	if len(c.ResolvedRisks) != 1 || !strings.Contains(c.ResolvedRisks[0], "供应链") {
+		t.Fatalf("resolved risks = %v", c.ResolvedRisks)
+	}
+	if len(c.IndicatorShifts) != 1 || c.IndicatorShifts[0].Name != "rsi" || c.IndicatorShifts[0].ChangePct != 50 {
+		t.Fatalf("indicator shifts = %+v", c.IndicatorShifts)
+	}
+	if !strings.Contains(c.Summary, "**Rating:** HOLD → BUY") {
+		t.Fatalf("summary:\n%s", c.Summary)
+	}
+}
+
+func TestFromStateEvidenceChain(t *testing.T) {
+	state := models.NewTradingState("AAPL.US", time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), "", nil)
+	state.MarketReport = "RSI 升至 61.2 [E2]，动能偏强。\n成交量放大。\n收盘价 $182.50 高于 50 日均线。"
+	state.NewsReport = "新闻面中性，没有重大事件。"
+	state.FinalTradeDecision = "FINAL TRANSACTION PROPOSAL: **BUY**\n上涨 3.5% 后突破阻力位，建议买入。"
+	state.Evidence = []*models.Evidence{
+		{ID: "E1", Agent: "market_analyst", Tool: "get_market_data", DataPoints: []string{"182.5", "3.5%"}},
+		{ID: "E2", Agent: "market_analyst", Tool: "get_stock_stats_indicators_window", DataPoints: []string{"61.2345"}},
+		{ID: "E3", Agent: "news_analyst", Tool: "get_google_stock_news", DataPoints: []string{"182.5"}},
+	}
+
+	rep := FromState(state)
+	if len(rep.Claims) != 3 {
+		t.Fatalf("claims = %+v", rep.Claims)
+	}
+	decision := rep.Claims[0]
+	if decision.Section != "final_trade_decision" || strings.Join(decision.Evidence, ",") != "E1" || decision.Cited {
+		t.Errorf("decision claim = %+v", decision)
+	}
+	if rsi := rep.Claims[1]; !rsi.Cited || strings.Join(rsi.Evidence, ",") != "E2" || strings.Join(rsi.DataPoints, ",") != "61.2" {
+		t.Errorf("cited claim = %+v", rsi)
+	}
+	// the analyst's own tool call wins over another agent's matching figure
+	if price := rep.Claims[2]; strings.Join(price.Evidence, ",") != "E1" {
+		t.Errorf("price claim = %+v", price)
+	}
+	if len(rep.Evidence) != 2 || rep.Evidence[0].DataPoints != nil {
+		t.Errorf("evidence = %+v", rep.Evidence)
+	}
+	chain := rep.Section("evidence_chain")
+	for _, want := range []string{"Recommendation **BUY**", "### Market Analysis", "**[E2]** market_analyst via `get_stock_stats_indicators_window`"} {
+		if !strings.Contains(chain, want) {
+			t.Errorf("evidence chain missing %q:\n%s", want, chain)
+		}
+	}
+
+	state.Evidence = nil
+	if rep := FromState(state); rep.Claims != nil || rep.Section("evidence_chain") != "" {
+		t.Errorf("no evidence should produce no chain")
+	}
+}
+
+func TestFromStateDataFreshness(t *testing.T) {
+	state := models.NewTradingState("AAPL.US", time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), "", nil)
+	state.MarketReport = "收盘价 $182.50。"
+	now := time.Now()
+	state.Evidence = []*models.Evidence{
+		{ID: "E1", Tool: "get_market_data", Provenance: &models.Provenance{Source: "longport", Mode: models.ProvenanceLive, FetchedAt: now, AsOf: "2024-05-09", CacheMisses: 1}},
+		{ID: "E2", Tool: "get_google_stock_news", Provenance: &models.Provenance{Source: "google_news", Mode: models.ProvenanceCache, FetchedAt: now.Add(-72 * time.Hour), AsOf: "2024-05-06", CacheHits: 1}},
+		{ID: "E3", Tool: "get_google_finance_news", Provenance: &models.Provenance{Source: "google_news", Mode: models.ProvenanceLive, FetchedAt: now, AsOf: "2024-05-09", CacheMisses: 1}},
+		{ID: "E4", Tool: "get_past_analyses"},
+	}
+
+	rep := FromState(state)
+	if len(rep.Freshness) != 2 {
+		t.Fatalf("freshness = %+v", rep.Freshness)
+	}
+	news := rep.Freshness[1]
+	if news.Source != "google_news" || news.Mode != models.ProvenanceMixed || news.AsOf != "2024-05-09" || news.CacheHits != 1 || news.CacheMisses != 1 {
+		t.Errorf("news provenance = %+v", news)
+	}
+	section := rep.Section("data_freshness")
+	for _, want := range []string{"fetched 3d before this report", "1 of 3 data lookups were served from cache", "google_news (mixed,", "2 tool calls _stale_"} {
+		if !strings.Contains(section, want) {
+			t.Errorf("freshness section missing %q:\n%s", want, section)
+		}
+	}
+	if strings.Contains(strings.Split(section, "google_news")[0], "_stale_") {
+		t.Errorf("fresh source flagged stale:\n%s", section)
+	}
+}
+
+func TestFromStateRunInputs(t *testing.T) {
+	build := func(seed int, output string) *Report {
+		state := models.NewTradingState("AAPL.US", time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), "", nil)
+		state.RunInputs = &models.RunInputs{Seed: seed, Models: []string{"deepseek-chat"}, Depth: "standard", PromptsDigest: "abc123"}
+		state.Evidence = []*models.Evidence{
+			{ID: "E1", Agent: "market_analyst", Tool: "get_market_data", Arguments: `{"symbol":"AAPL.US"}`, Digest: output},
+		}
+		return FromState(state)
+	}
+
+	a, b := build(42, "d1"), build(42, "d1")
+	if a.RunInputs == nil || a.RunInputs.ToolCalls != 1 || a.RunInputs.DataDigest == "" {
+		t.Fatalf("run inputs = %+v", a.RunInputs)
+	}
+	if a.RunInputs.DataDigest != b.RunInputs.DataDigest {
+		t.Errorf("same tool outputs, different digests: %s vs %s", a.RunInputs.DataDigest, b.RunInputs.DataDigest)
+	}
+	if c := build(42, "d2"); c.RunInputs.DataDigest == a.RunInputs.DataDigest {
+		t.Errorf("different tool outputs, same digest")
+	}
+	section := a.Section("run_inputs")
+	for _, want := range []string{"seed 42", "`abc123`", "over 1 tool calls"} {
+		if !strings.Contains(section, want) {
+			t.Errorf("run inputs section missing %q:\n%s", want, section)
+		}
+	}
+	if build(0, "d1").Section("run_inputs") != "" {
+		t.Errorf("unseeded run should not render run inputs")
+	}
+}
+
+func TestFromStateRunInputsVersionsAndDataDates(t *testing.T) {
+	state := models.NewTradingState("AAPL.US", time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), "", nil)
+	state.RunInputs = &models.RunInputs{ConfigDigest: "cfg123", PromptDigests: map[string]string{"trader/trader": "p1"}}
+	state.Evidence = []*models.Evidence{
+		{ID: "E1", Tool: "get_market_data", ToolVersion: "v1", Provenance: &models.Provenance{Source: "longport", AsOf: "2024-05-09"}},
+		{ID: "E2", Tool: "get_market_data", ToolVersion: "v1", Provenance: &models.Provenance{Source: "longport", AsOf: "2024-05-10"}},
+		{ID: "E3", Tool: "get_news", ToolVersion: "v2", Provenance: &models.Provenance{Source: "rss", AsOf: "2024-05-08"}},
+		{ID: "E4", Tool: "get_news"},
+	}
+
+	ri := FromState(state).RunInputs
+	if ri.ConfigDigest != "cfg123" || ri.PromptDigests["trader/trader"] != "p1" {
+		t.Errorf("recorded inputs not kept: %+v", ri)
+	}
+	if len(ri.ToolVersions) != 2 || ri.ToolVersions["get_market_data"] != "v1" || ri.ToolVersions["get_news"] != "v2" {
+		t.Errorf("tool versions = %v", ri.ToolVersions)
+	}
+	if len(ri.DataAsOf) != 2 || ri.DataAsOf["longport"] != "2024-05-10" || ri.DataAsOf["rss"] != "2024-05-08" {
+		t.Errorf("data as of = %v", ri.DataAsOf)
+	}
+	if state.RunInputs.ToolVersions != nil {
+		t.Errorf("state's run inputs modified")
+	}
+}
+
+func TestFromStateCapsPositionToRiskProfile(t *testing.T) {
+	cfg := &config.Config{RiskProfile: config.RiskConservative}
+	state := models.NewTradingState("AAPL.US", time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC), "", cfg)
+	state.FinalTradeDecision = "FINAL TRANSACTION PROPOSAL: **BUY**\nCONFIDENCE: 0.7\nPOSITION SIZE: 12%\nHOLDING PERIOD: 30"
+
+	rep := FromState(state)
+	if rep.RiskProfile != config.RiskConservative || rep.Decision.PositionSize != 0.05 || rep.Decision.HoldingDays != 30 {
+		t.Fatalf("profile = %q, decision = %+v", rep.RiskProfile, rep.Decision)
+	}
+	if !strings.Contains(rep.Markdown(), "risk_profile: conservative\n") {
+		t.Error("markdown front matter misses risk_profile")
+	}
+
+	cfg.RiskProfile = config.RiskAggressive
+	if rep := FromState(state); rep.Decision.PositionSize != 0.12 {
+		t.Errorf("aggressive position = %v, want 0.12", rep.Decision.PositionSize)
+	}
+
+	// a stressed volatility regime halves the balanced 10% cap
+	cfg.RiskProfile = config.RiskBalanced
+	state.MarketRegime = &models.MarketRegime{Volatility: &models.VolatilityRegime{Regime: models.VolStressed, SizeMultiplier: 0.5}}
+	if rep := FromState(state); rep.Decision.PositionSize != 0.05 {
+		t.Errorf("stressed position = %v, want 0.05", rep.Decision.PositionSize)
+	}
+}
+
+func TestTradingViewLevels(t *testing.T) {
+	rep := sampleReport()
+	rep.Symbol = "700.HK"
+	rep.Recommendation = "SELL"
+	rep.Decision = &models.TradingDecision{EntryPrice: 380, StopLoss: 395, TakeProfit: 350}
+	rep.Structure = &models.MarketStructure{
+		Close: 382,
+		Range: &models.PriceRange{High: 400, Low: 360, End: "2024-05-10"},
+		Swings: []models.SwingPoint{
+			{Date: "2024-04-20", Kind: "high", Price: 401},
+			{Date: "2024-04-28", Kind: "low", Price: 371},
+			{Date: "2024-05-06", Kind: "high", Price: 390},
+		},
+	}
+
+	var kinds []string
+	for _, l := range rep.Levels() {
+		kinds = append(kinds, l.Kind+" "+formatPrice(l.Price))
+	}
+	// 401 merges into the 400 range high; supports and resistances nearest first
+	want := "entry 380,stop 395,target 350,support 371,support 360,resistance 390,resistance 400"
+	if got := strings.Join(kinds, ","); got != want {
+		t.Fatalf("levels = %s, want %s", got, want)
+	}
+
+	pine, err := Export(rep, "pine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(pine), "//@version=5\n") || !strings.Contains(string(pine), `hline(395, "Stop loss 395", color=color.red`) || !strings.Contains(string(pine), "HKEX:700") {
+		t.Fatalf("pine script:\n%s", pine)
+	}
+
+	alerts := rep.Alerts()
+	if alerts.TradingViewSymbol != "HKEX:700" || len(alerts.Alerts) != 7 {
+		t.Fatalf("alerts = %+v", alerts)
+	}
+	// a short is stopped out when price rises through the stop
+	if a := alerts.Alerts[1]; a.Kind != LevelStop || a.Condition != "crossing_up" {
+		t.Fatalf("stop alert = %+v", a)
+	}
+	if a := alerts.Alerts[3]; a.Kind != LevelSupport || a.Condition != "crossing_down" {
+		t.Fatalf("support alert = %+v", a)
+	}
+
+	csvData, err := Export(rep, "tv_csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(csvData)), "\n"); len(lines) != 8 || lines[1] != "700.HK,HKEX:700,entry,380,Entry," {
+		t.Fatalf("csv:\n%s", csvData)
+	}
+}
+
+func TestTradingViewSymbol(t *testing.T) {
+	cases := map[string]string{"AAPL.US": "AAPL", "BRK.B.US": "BRK.B", "00700.HK": "HKEX:700", "600519.SH": "SSE:600519", "000001.SZ": "SZSE:000001", "SPY": "SPY"}
+	for in, want := range cases {
+		if got := TradingViewSymbol(in); got != want {
+			t.Errorf("TradingViewSymbol(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCatalystsICS(t *testing.T) {
+	state := &models.TradingState{
+		CompanyOfInterest: "RDDT.US",
+		TradeDate:         "2024-07-01",
+		Catalysts: []models.Catalyst{
+			{Date: "2024-07-31", Time: "14:00", Kind: models.CatalystEconomic, Title: "FOMC rate decision", Detail: "Federal Reserve statement at 14:00 ET, press conference at 14:30 ET", Source: "federalreserve.gov"},
+			{Date: "2024-09-17", Kind: models.CatalystLockup, Symbol: "RDDT.US", Title: "RDDT IPO lockup expiry", Estimated: true, Source: "finnhub"},
+		},
+	}
+	rep := FromState(state)
+	if len(rep.Catalysts) != 2 || !strings.Contains(rep.Section("upcoming_catalysts"), "| 2024-09-17 |  | RDDT IPO lockup expiry (estimated) |") {
+		t.Fatalf("catalysts section:\n%s", rep.Section("upcoming_catalysts"))
+	}
+
+	data, err := Export(rep, "ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ics := string(data)
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:CortexGo RDDT.US catalysts\r\n",
+		"DTSTART;VALUE=DATE:20240731\r\nDTEND;VALUE=DATE:20240801\r\n",
+		"SUMMARY:FOMC rate decision (14:00 ET)\r\n",
+		"SUMMARY:RDDT IPO lockup expiry (estimated)\r\n",
+		"CATEGORIES:lockup\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("ics missing %q:\n%s", want, ics)
+		}
+	}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line not folded: %q", line)
+		}
+	}
+	// the description is escaped and folded across lines
+	if !strings.Contains(strings.ReplaceAll(ics, "\r\n ", ""), `DESCRIPTION:Federal Reserve statement at 14:00 ET\, press conference at 14:30 ET.\nSource: federalreserve.gov.\nFrom the CortexGo analysis of RDDT.US on 2024-07-01.`) {
+		t.Errorf("description not escaped:\n%s", ics)
+	}
+	// UIDs are stable across exports
+	if again, _ := Export(rep, "ics"); strings.Count(string(again), "UID:") != 2 || uids(string(again)) != uids(ics) {
+		t.Error("event UIDs changed between exports")
+	}
+}
+
+func uids(ics string) string {
+	var out []string
+	for _, line := range strings.Split(ics, "\r\n") {
+		if strings.HasPrefix(line, "UID:") {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, ",")
+}
+
+func TestRedact(t *testing.T) {
+	r := &Report{
+		Symbol:    "AAPL.US",
+		TradeDate: "2024-05-10",
+		Sections: []Section{
+			{Key: "news_report", Content: "Reuters reported strong iPhone demand ([story](https://www.reuters.com/x)); Google News coverage is broad, see https://example.com/a."},
+			{Key: "social_report", Content: "r/wallstreetbets is bullish:\n> AAPL to the moon, loading calls\nu/sim_trader_7 posted the top thread."},
+		},
+		Claims: []models.EvidenceClaim{{Section: "news_report", Text: "Reuters reported strong iPhone demand", Evidence: []string{"E1"}}},
+		Evidence: []*models.Evidence{
+			{ID: "E1", Tool: "get_google_news", Excerpt: "## 1. iPhone demand\n**Source:** Reuters [wire] | **Published:** 2024-05-09 10:00\n**URL:** https://www.reuters.com/x", Provenance: &models.Provenance{Source: "google_news"}},
+			{ID: "E2", Tool: "get_reddit_stock_mentions", Excerpt: "**Content:** loading calls", DataPoints: []string{"42"}, Provenance: &models.Provenance{Source: "reddit"}},
+		},
+		Freshness: []*models.Provenance{{Source: "google_news"}, {Source: "market"}},
+		Catalysts: []models.Catalyst{{Date: "2024-05-20", Title: "Newsletter preview", Source: "Acme Research"}},
+	}
+	orig := r.Sections[0].Content
+
+	if got := Redact(r, &config.Config{}); got != r {
+		t.Fatal("nothing configured should return the report itself")
+	}
+
+	cfg := &config.Config{ReportRedact: []string{RedactURLs, RedactSocial, RedactSources}, ReportRedactTerms: []string{"iPhone"}}
+	got := Redact(r, cfg)
+	if r.Sections[0].Content != orig || r.Evidence[1].Excerpt != "**Content:** loading calls" {
+		t.Fatal("Redact changed the original report")
+	}
+
+	news := got.Section("news_report")
+	for _, gone := range []string{"http", "www.", "Reuters", "Google News", "iPhone"} {
+		if strings.Contains(news, gone) {
+			t.Errorf("news_report still contains %q: %s", gone, news)
+		}
+	}
+	if !strings.Contains(news, "[source 1] reported") || !strings.Contains(news, "[link removed]") {
+		t.Errorf("news_report = %s", news)
+	}
+	if got.Claims[0].Text != "[source 1] reported strong [source 2] demand" {
+		t.Errorf("claim = %q", got.Claims[0].Text)
+	}
+
+	social := got.Section("social_report")
+	for _, gone := range []string{"wallstreetbets", "to the moon", "sim_trader_7"} {
+		if strings.Contains(social, gone) {
+			t.Errorf("social_report still contains %q: %s", gone, social)
+		}
+	}
+	if !strings.Contains(social, "> "+postRemoved) || !strings.Contains(social, userRemoved) {
+		t.Errorf("social_report = %s", social)
+	}
+
+	if e := got.Evidence[1]; e.Excerpt != postRemoved || e.DataPoints != nil {
+		t.Errorf("reddit evidence = %+v", e)
+	}
+	if e := got.Evidence[0]; strings.Contains(e.Excerpt, "Reuters") || strings.Contains(e.Excerpt, "https://") || e.Provenance.Source == "google_news" {
+		t.Errorf("news evidence = %+v %+v", e, e.Provenance)
+	}
+	if got.Freshness[0].Source != got.Evidence[0].Provenance.Source || got.Freshness[1].Source != "market" {
+		t.Errorf("freshness sources = %s, %s", got.Freshness[0].Source, got.Freshness[1].Source)
+	}
+	if got.Catalysts[0].Source == "Acme Research" {
+		t.Errorf("catalyst source kept: %+v", got.Catalysts[0])
+	}
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// recordRunInputs copies the run's recorded inputs into the report, completed
+// with a digest of every tool call and its output, the version of each tool
+// called and how recent each source's data was. Seeded runs also get a
+// "Run Inputs" section, since they exist to be compared.
+func recordRunInputs(rep *Report, inputs *models.RunInputs, evidence []*models.Evidence) {
+	if inputs == nil {
+		return
+	}
+	ri := *inputs
+	ri.Models = append([]string(nil), inputs.Models...)
+	ri.ToolCalls = len(evidence)
+	if len(evidence) > 0 {
+		h := sha256.New()
+		for _, e := range evidence {
+			fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\n", e.Agent, e.Tool, e.Arguments, e.Digest)
+		}
+		ri.DataDigest = hex.EncodeToString(h.Sum(nil))[:12]
+	}
+	ri.ToolVersions, ri.DataAsOf = nil, nil
+	for _, e := range evidence {
+		if e.Tool != "" && e.ToolVersion != "" {
+			if ri.ToolVersions == nil {
+				ri.ToolVersions = map[string]string{}
+			}
+			ri.ToolVersions[e.Tool] = e.ToolVersion
+		}
+		// as-of values are dates or timestamps, which order as strings
+		if p := e.Provenance; p != nil && p.Source != "" && p.AsOf != "" {
+			if ri.DataAsOf == nil {
+				ri.DataAsOf = map[string]string{}
+			}
+			if p.AsOf > ri.DataAsOf[p.Source] {
+				ri.DataAsOf[p.Source] = p.AsOf
+			}
+		}
+	}
+	rep.RunInputs = &ri
+	if ri.Seed <= 0 {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Seeded run: seed %d, temperature 0, models %s, depth %s.\n\n", ri.Seed, strings.Join(ri.Models, ", "), ri.Depth)
+	fmt.Fprintf(&b, "- Prompts digest: `%s`\n", ri.PromptsDigest)
+	if ri.ConfigDigest != "" {
+		fmt.Fprintf(&b, "- Config digest: `%s`\n", ri.ConfigDigest)
+	}
+	if ri.Build != nil && ri.Build.Commit != "" {
+		fmt.Fprintf(&b, "- Build: %s (commit `%s`)\n", ri.Build.Version, ri.Build.Commit)
+	}
+	if ri.DataDigest != "" {
+		fmt.Fprintf(&b, "- Data digest: `%s` over %d tool calls\n", ri.DataDigest, ri.ToolCalls)
+	}
+	fmt.Fprintf(&b, "- Cached data pinned regardless of age; started %s\n", ri.StartedAt.Format("2006-01-02 15:04:05"))
+	b.WriteString("\nRuns with equal digests saw the same prompts and data; remaining differences come from the model.\n")
+	rep.Sections = append(rep.Sections, Section{Key: "run_inputs", Title: "Run Inputs", Content: b.String()})
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/pkg/persist"
+	"github.com/dyike/CortexGo/pkg/secure"
+)
+
+// SessionDocument is the stored copy of a finished session: what agent.db
+// indexes, kept whole in the sessions area of the configured storage.
+type SessionDocument struct {
+	SessionID int64     `json:"session_id"`
+	SavedAt   time.Time `json:"saved_at"`
+	Report    *Report   `json:"report"`
+}
+
+// SaveSession writes rep as <sessionID>.json to the sessions area of cfg's
+// storage, encrypted when an encryption key is configured.
+func SaveSession(ctx context.Context, cfg *config.Config, sessionID int64, rep *Report) error {
+	s, err := persist.For(cfg)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(SessionDocument{SessionID: sessionID, SavedAt: time.Now(), Report: rep})
+	if err != nil {
+		return fmt.Errorf("marshal session %d: %w", sessionID, err)
+	}
+	cipher, err := secure.ForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("load encryption key: %w", err)
+	}
+	if data, err = cipher.Seal(data); err != nil {
+		return fmt.Errorf("encrypt session %d: %w", sessionID, err)
+	}
+	return s.Put(ctx, persist.Sessions, strconv.FormatInt(sessionID, 10)+".json", data)
+}
+
+// LoadSession reads a document written by SaveSession.
+func LoadSession(ctx context.Context, cfg *config.Config, sessionID int64) (*SessionDocument, error) {
+	s, err := persist.For(cfg)
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := s.Get(ctx, persist.Sessions, strconv.FormatInt(sessionID, 10)+".json")
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := secure.ForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("load encryption key: %w", err)
+	}
+	if data, err = cipher.Open(data); err != nil {
+		return nil, fmt.Errorf("decrypt session %d: %w", sessionID, err)
+	}
+	var doc SessionDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse session %d: %w", sessionID, err)
+	}
+	return &doc, nil
+}
+
+// DeleteSession removes the document written by SaveSession, if any.
+func DeleteSession(ctx context.Context, cfg *config.Config, sessionID int64) error {
+	s, err := persist.For(cfg)
+	if err != nil {
+		return err
+	}
+	return s.Delete(ctx, persist.Sessions, strconv.FormatInt(sessionID, 10)+".json")
+}
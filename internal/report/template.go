@@ -0,0 +1,225 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+)
+
+// TemplateData is what a report template is executed with: the report's
+// fields and methods (.Section "key" among them), plus the decision (parsed
+// from the final decision text when the report carries none), the attached
+// chart and the sections grouped the way the built-in layout groups them.
+type TemplateData struct {
+	*Report
+	Decision *models.TradingDecision
+	// Chart is the attached price chart as inline SVG, empty without one.
+	Chart htmltemplate.HTML
+	// Groups are the built-in layout's groups that have sections, followed
+	// by one untitled group for the sections outside them.
+	Groups []SectionGroup
+	Now    time.Time
+
+	format string
+}
+
+// Pick returns the sections with the given keys, in the order of keys, so a
+// template decides which sections appear and in which order.
+func (d *TemplateData) Pick(keys ...string) []Section {
+	return d.sections(keys)
+}
+
+// Except returns the sections without the given keys, in report order.
+func (d *TemplateData) Except(keys ...string) []Section {
+	var out []Section
+	for _, s := range d.Report.Sections {
+		if !slices.Contains(keys, s.Key) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Builtin is the built-in rendering of the template's format, for templates
+// that only add branding around it.
+func (d *TemplateData) Builtin() htmltemplate.HTML {
+	if d.format == "html" {
+		return htmltemplate.HTML(d.HTML())
+	}
+	return htmltemplate.HTML(d.Markdown())
+}
+
+// SectionGroup is a titled run of sections.
+type SectionGroup struct {
+	Title    string
+	Sections []Section
+}
+
+// Template renders reports of one format with a user supplied Go template
+// instead of the built-in layout: text/template for md, html/template (with
+// contextual escaping) for html.
+type Template struct {
+	format string
+	text   *texttemplate.Template
+	html   *htmltemplate.Template
+}
+
+// templateFormats are the formats a template can replace, with the config
+// field naming the template file.
+var templateFormats = map[string]func(*config.Config) string{
+	"md":   func(c *config.Config) string { return c.ReportTemplateMD },
+	"html": func(c *config.Config) string { return c.ReportTemplateHTML },
+}
+
+// ParseTemplate parses a report template for format (md or html).
+func ParseTemplate(format, name, content string) (*Template, error) {
+	t := &Template{format: format}
+	var err error
+	switch format {
+	case "md":
+		t.text, err = texttemplate.New(name).Funcs(templateFuncs()).Parse(content)
+	case "html":
+		t.html, err = htmltemplate.New(name).Funcs(templateFuncs()).Parse(content)
+	default:
+		return nil, fmt.Errorf("format %q has no templates (supported: md, html)", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse report template: %w", err)
+	}
+	return t, nil
+}
+
+// LoadTemplate reads and parses the template file at path for format.
+func LoadTemplate(format, path string) (*Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read report template: %w", err)
+	}
+	return ParseTemplate(format, filepath.Base(path), string(content))
+}
+
+// Render executes the template for r.
+func (t *Template) Render(r *Report) ([]byte, error) {
+	data := newTemplateData(r, t.format)
+	var buf bytes.Buffer
+	var err error
+	if t.html != nil {
+		err = t.html.Execute(&buf, data)
+	} else {
+		err = t.text.Execute(&buf, data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("render report template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ExporterFor is LookupExporter with the template configured for format in
+// cfg (report_template_md, report_template_html) replacing the built-in
+// layout. The template is read when the exporter is looked up, so edits to
+// it apply to the next export without a restart.
+func ExporterFor(cfg *config.Config, format string) (Exporter, error) {
+	exp, err := LookupExporter(format)
+	if err != nil || cfg == nil {
+		return exp, err
+	}
+	format = strings.ToLower(strings.TrimSpace(format))
+	path := ""
+	if field, ok := templateFormats[format]; ok {
+		path = strings.TrimSpace(field(cfg))
+	}
+	if path == "" {
+		return exp, nil
+	}
+	t, err := LoadTemplate(format, path)
+	if err != nil {
+		return Exporter{}, err
+	}
+	exp.Render = t.Render
+	return exp, nil
+}
+
+// Rendered renders r as format with the configured template, falling back
+// to the built-in layout when the template fails, for callers that must not
+// fail on a broken template (email delivery, results.info).
+func Rendered(cfg *config.Config, r *Report, format string) []byte {
+	exp, err := ExporterFor(cfg, format)
+	var data []byte
+	if err == nil {
+		data, err = exp.Render(r)
+	}
+	if err != nil {
+		fmt.Printf("report template format=%s err=%v\n", format, err)
+		data, _ = Export(r, format)
+	}
+	return data
+}
+
+func newTemplateData(r *Report, format string) *TemplateData {
+	d := &TemplateData{Report: r, Decision: r.Decision, Chart: htmltemplate.HTML(r.ChartSVG), Now: time.Now(), format: format}
+	if d.Decision == nil {
+		d.Decision = ExtractDecision(r)
+	}
+	seen := make(map[string]bool)
+	for _, g := range markdownGroups {
+		group := SectionGroup{Title: g.Title, Sections: r.sections(g.Keys)}
+		for _, s := range group.Sections {
+			seen[s.Key] = true
+		}
+		if len(group.Sections) > 0 {
+			d.Groups = append(d.Groups, group)
+		}
+	}
+	var rest SectionGroup
+	for _, s := range r.Sections {
+		if !seen[s.Key] {
+			rest.Sections = append(rest.Sections, s)
+		}
+	}
+	if len(rest.Sections) > 0 {
+		d.Groups = append(d.Groups, rest)
+	}
+	return d
+}
+
+// sections returns the sections with the given keys, in the order of keys.
+func (r *Report) sections(keys []string) []Section {
+	var out []Section
+	for _, key := range keys {
+		for _, s := range r.Sections {
+			if s.Key == key {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// templateFuncs are available in every report template besides the methods
+// of TemplateData:
+//
+//	demote 2 .Content      push markdown headings in the content 2 levels down
+//	date "2006-01-02" t    format a time
+//	percent 0.125          "12.5%"
+//	upper, lower, trim, join, replace
+func templateFuncs() map[string]any {
+	return map[string]any{
+		"demote":  func(level int, content string) string { return demoteHeadings(content, level) },
+		"date":    func(layout string, t time.Time) string { return t.Format(layout) },
+		"percent": func(v float64) string { return fmt.Sprintf("%.1f%%", v*100) },
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"trim":    strings.TrimSpace,
+		"join":    strings.Join,
+		"replace": strings.ReplaceAll,
+	}
+}
@@ -0,0 +1,261 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// Kinds of chart level.
+const (
+	LevelEntry      = "entry"
+	LevelStop       = "stop"
+	LevelTarget     = "target"
+	LevelSupport    = "support"
+	LevelResistance = "resistance"
+)
+
+// maxStructureLevels is how many supports and how many resistances are kept.
+const maxStructureLevels = 3
+
+// levelMergePct is how close, in percent, two structure levels may be before
+// the farther one from the close is dropped as a duplicate.
+const levelMergePct = 0.5
+
+// Level is one price to draw on a chart: the plan's entry, stop or target, or
+// a support or resistance from the price structure.
+type Level struct {
+	Kind  string  `json:"kind"`
+	Price float64 `json:"price"`
+	Label string  `json:"label"`
+	Date  string  `json:"date,omitempty"` // when the structure level formed
+}
+
+// Levels returns the plan's entry, stop and target followed by the nearest
+// supports below and resistances above the last close of the price structure
+// attached at export time. Prices the report does not state are left out.
+func (r *Report) Levels() []Level {
+	d := r.Decision
+	if d == nil {
+		d = ExtractDecision(r)
+	}
+	var levels []Level
+	for _, l := range []Level{
+		{Kind: LevelEntry, Price: d.EntryPrice, Label: "Entry"},
+		{Kind: LevelStop, Price: d.StopLoss, Label: "Stop loss"},
+		{Kind: LevelTarget, Price: d.TakeProfit, Label: "Take profit"},
+	} {
+		if l.Price > 0 {
+			levels = append(levels, l)
+		}
+	}
+	if r.Structure != nil {
+		levels = append(levels, structureLevels(r.Structure)...)
+	}
+	return levels
+}
+
+// structureLevels turns the trading range bounds and swing points into
+// supports and resistances relative to the last close, nearest first.
+func structureLevels(s *models.MarketStructure) []Level {
+	var candidates []Level
+	if s.Range != nil {
+		candidates = append(candidates,
+			Level{Price: s.Range.High, Label: "Range high", Date: s.Range.End},
+			Level{Price: s.Range.Low, Label: "Range low", Date: s.Range.End},
+		)
+	}
+	for i := len(s.Swings) - 1; i >= 0; i-- {
+		sw := s.Swings[i]
+		candidates = append(candidates, Level{Price: sw.Price, Label: "Swing " + sw.Kind, Date: sw.Date})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return math.Abs(candidates[i].Price-s.Close) < math.Abs(candidates[j].Price-s.Close)
+	})
+
+	var supports, resistances []Level
+	for _, c := range candidates {
+		if c.Price <= 0 || c.Price == s.Close {
+			continue
+		}
+		side := &supports
+		c.Kind = LevelSupport
+		if c.Price > s.Close {
+			side = &resistances
+			c.Kind = LevelResistance
+		}
+		if len(*side) >= maxStructureLevels || nearAny(c.Price, *side) {
+			continue
+		}
+		*side = append(*side, c)
+	}
+	return append(supports, resistances...)
+}
+
+func nearAny(price float64, levels []Level) bool {
+	for _, l := range levels {
+		if math.Abs(price-l.Price)/l.Price*100 < levelMergePct {
+			return true
+		}
+	}
+	return false
+}
+
+// TradingViewSymbol maps a CortexGo symbol to TradingView's EXCHANGE:TICKER
+// form. US tickers are left bare so TradingView picks the primary listing.
+func TradingViewSymbol(symbol string) string {
+	i := strings.LastIndex(symbol, ".")
+	if i < 0 {
+		return symbol
+	}
+	ticker, market := symbol[:i], strings.ToUpper(symbol[i+1:])
+	switch market {
+	case "US":
+		return ticker
+	case "HK":
+		if t := strings.TrimLeft(ticker, "0"); t != "" {
+			ticker = t
+		}
+		return "HKEX:" + ticker
+	case "SH":
+		return "SSE:" + ticker
+	case "SZ":
+		return "SZSE:" + ticker
+	case "SG":
+		return "SGX:" + ticker
+	}
+	return symbol
+}
+
+// short reports whether the plan is a short, which flips the direction the
+// stop and target are crossed in.
+func (r *Report) short() bool {
+	return strings.EqualFold(r.Recommendation, "SELL")
+}
+
+var pineStyles = map[string]string{
+	LevelEntry:      "color=color.blue, linestyle=hline.style_solid, linewidth=2",
+	LevelStop:       "color=color.red, linestyle=hline.style_solid, linewidth=2",
+	LevelTarget:     "color=color.green, linestyle=hline.style_solid, linewidth=2",
+	LevelSupport:    "color=color.teal, linestyle=hline.style_dashed",
+	LevelResistance: "color=color.orange, linestyle=hline.style_dashed",
+}
+
+// PineScript renders the levels as a Pine Script v5 overlay that draws one
+// horizontal line per level; paste it into TradingView's Pine Editor.
+func (r *Report) PineScript() string {
+	var b strings.Builder
+	b.WriteString("//@version=5\n")
+	fmt.Fprintf(&b, "// CortexGo plan for %s on %s: %s\n", r.Symbol, r.TradeDate, recommendationOrNone(r.Recommendation))
+	fmt.Fprintf(&b, "// Add to a %s chart in TradingView.\n", TradingViewSymbol(r.Symbol))
+	fmt.Fprintf(&b, "indicator(%s, overlay=true)\n", pineString(fmt.Sprintf("CortexGo %s %s %s", r.Symbol, r.TradeDate, recommendationOrNone(r.Recommendation))))
+	levels := r.Levels()
+	if len(levels) == 0 {
+		b.WriteString("// The report states no price levels.\n")
+		return b.String()
+	}
+	for _, l := range levels {
+		title := fmt.Sprintf("%s %s", l.Label, formatPrice(l.Price))
+		if l.Date != "" {
+			title += " (" + l.Date + ")"
+		}
+		fmt.Fprintf(&b, "hline(%s, %s, %s)\n", formatPrice(l.Price), pineString(title), pineStyles[l.Kind])
+	}
+	return b.String()
+}
+
+// LevelsCSV renders the levels as symbol, tradingview_symbol, kind, price,
+// label, date rows, e.g. to paste into a Pine array or a watchlist note.
+func (r *Report) LevelsCSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"symbol", "tradingview_symbol", "kind", "price", "label", "date"})
+	tv := TradingViewSymbol(r.Symbol)
+	for _, l := range r.Levels() {
+		_ = w.Write([]string{r.Symbol, tv, l.Kind, formatPrice(l.Price), l.Label, l.Date})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// TradingViewAlert is one price alert to set up in TradingView. Condition is
+// the TradingView condition name; Message uses its {{ticker}} and {{close}}
+// placeholders so it can be pasted as the alert or webhook message.
+type TradingViewAlert struct {
+	Name      string  `json:"name"`
+	Kind      string  `json:"kind"`
+	Condition string  `json:"condition"` // crossing, crossing_up or crossing_down
+	Price     float64 `json:"price"`
+	Message   string  `json:"message"`
+}
+
+// TradingViewAlerts is the alert JSON export of a report.
+type TradingViewAlerts struct {
+	Symbol            string             `json:"symbol"`
+	TradingViewSymbol string             `json:"tradingview_symbol"`
+	TradeDate         string             `json:"trade_date"`
+	Recommendation    string             `json:"recommendation"`
+	Alerts            []TradingViewAlert `json:"alerts"`
+}
+
+// Alerts turns every level into a price alert: the stop and target fire when
+// price crosses them in the direction that closes the trade, supports when
+// price falls through them and resistances when it breaks above.
+func (r *Report) Alerts() TradingViewAlerts {
+	out := TradingViewAlerts{
+		Symbol:            r.Symbol,
+		TradingViewSymbol: TradingViewSymbol(r.Symbol),
+		TradeDate:         r.TradeDate,
+		Recommendation:    r.Recommendation,
+		Alerts:            []TradingViewAlert{},
+	}
+	for _, l := range r.Levels() {
+		condition := "crossing"
+		switch l.Kind {
+		case LevelStop:
+			condition = "crossing_down"
+			if r.short() {
+				condition = "crossing_up"
+			}
+		case LevelTarget:
+			condition = "crossing_up"
+			if r.short() {
+				condition = "crossing_down"
+			}
+		case LevelSupport:
+			condition = "crossing_down"
+		case LevelResistance:
+			condition = "crossing_up"
+		}
+		out.Alerts = append(out.Alerts, TradingViewAlert{
+			Name:      fmt.Sprintf("%s %s %s", r.Symbol, strings.ToLower(l.Label), formatPrice(l.Price)),
+			Kind:      l.Kind,
+			Condition: condition,
+			Price:     l.Price,
+			Message:   fmt.Sprintf("{{ticker}} at {{close}} crossed the %s %s of the CortexGo %s plan from %s", strings.ToLower(l.Label), formatPrice(l.Price), recommendationOrNone(r.Recommendation), r.TradeDate),
+		})
+	}
+	return out
+}
+
+func recommendationOrNone(rec string) string {
+	if rec == "" {
+		return "no recommendation"
+	}
+	return rec
+}
+
+func formatPrice(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// pineString quotes s as a Pine string literal.
+func pineString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s) + `"`
+}
@@ -0,0 +1,163 @@
+// Package rpc is the method registry behind libcortex Call: handlers register
+// with a typed params struct, params are checked before the handler runs, and
+// failures carry a machine-readable error kind next to the message.
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Handler serves one method; params is the raw JSON and may be empty.
+type Handler func(params string) (any, error)
+
+// Method describes a registered method.
+type Method struct {
+	Name        string
+	Description string
+	// Params is a zero value of the params struct (e.g. models.HistoryParams{}),
+	// used to type-check the JSON and describe it in system.methods; nil means
+	// the method takes no params.
+	Params  any
+	Handler Handler
+}
+
+// Error kinds returned in Response.Error.
+const (
+	KindInvalidParams  = "invalid_params"
+	KindMethodNotFound = "method_not_found"
+	KindNotFound       = "not_found"
+	KindConflict       = "conflict"
+	KindInternal       = "internal"
+)
+
+var kindCodes = map[string]int{
+	KindInvalidParams:  400,
+	KindMethodNotFound: 404,
+	KindNotFound:       404,
+	KindConflict:       409,
+	KindInternal:       500,
+}
+
+// Error is a handler failure with an explicit kind; other errors are internal.
+type Error struct {
+	Kind    string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// InvalidParams reports a bad or missing parameter.
+func InvalidParams(format string, args ...any) error {
+	return &Error{Kind: KindInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// NotFound reports a missing session, report or other resource.
+func NotFound(format string, args ...any) error {
+	return &Error{Kind: KindNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// Conflict reports a request that clashes with the current state, such as
+// deleting a running analysis.
+func Conflict(format string, args ...any) error {
+	return &Error{Kind: KindConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// Response is the JSON envelope returned by Call. Error is empty on success.
+type Response struct {
+	Code  int    `json:"code"`
+	Msg   string `json:"msg"`
+	Error string `json:"error,omitempty"`
+	Data  any    `json:"data,omitempty"`
+}
+
+var (
+	mu      sync.RWMutex
+	methods = map[string]Method{}
+)
+
+// Register adds m; registering a name twice panics, as it is a programming error.
+func Register(m Method) {
+	if m.Name == "" || m.Handler == nil {
+		panic("rpc: method needs a name and a handler")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if _, dup := methods[m.Name]; dup {
+		panic("rpc: duplicate method " + m.Name)
+	}
+	methods[m.Name] = m
+}
+
+// Dispatch runs method with params and wraps the outcome in a Response.
+func Dispatch(method, params string) Response {
+	mu.RLock()
+	m, ok := methods[method]
+	mu.RUnlock()
+	if !ok {
+		return ErrorResponse(&Error{Kind: KindMethodNotFound, Message: "Method not found"})
+	}
+	if err := checkParams(m.Params, params); err != nil {
+		return ErrorResponse(err)
+	}
+	data, err := m.Handler(params)
+	if err != nil {
+		return ErrorResponse(err)
+	}
+	return Response{Code: 200, Msg: "Ok", Data: data}
+}
+
+// checkParams decodes params into a fresh value of the declared type so that
+// malformed or mistyped JSON fails with invalid_params before the handler runs.
+func checkParams(typ any, params string) error {
+	if typ == nil || strings.TrimSpace(params) == "" {
+		return nil
+	}
+	v := reflect.New(reflect.TypeOf(typ)).Interface()
+	if err := json.Unmarshal([]byte(params), v); err != nil {
+		return InvalidParams("invalid params: %v", err)
+	}
+	return nil
+}
+
+// ErrorResponse maps err to its status code and kind; errors that are not an
+// *Error are reported as internal.
+func ErrorResponse(err error) Response {
+	var rerr *Error
+	if !errors.As(err, &rerr) {
+		rerr = &Error{Kind: KindInternal, Message: err.Error()}
+	}
+	code, ok := kindCodes[rerr.Kind]
+	if !ok {
+		code = 500
+	}
+	return Response{Code: code, Msg: err.Error(), Error: rerr.Kind}
+}
+
+// MethodInfo describes a method for system.methods.
+type MethodInfo struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Params      map[string]any `json:"params,omitempty"`
+}
+
+// Methods lists every registered method by name with its params schema.
+func Methods() []MethodInfo {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make([]MethodInfo, 0, len(methods))
+	for _, m := range methods {
+		info := MethodInfo{Name: m.Name, Description: m.Description}
+		if m.Params != nil {
+			info.Params = Schema(m.Params)
+		}
+		out = append(out, info)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
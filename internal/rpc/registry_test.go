@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type echoParams struct {
+	Symbol string   `json:"symbol" rpc:"required"`
+	Days   int      `json:"days,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+func TestDispatchErrorKinds(t *testing.T) {
+	Register(Method{Name: "test.echo", Params: echoParams{}, Handler: func(string) (any, error) {
+		return "ok", nil
+	}})
+	Register(Method{Name: "test.missing", Handler: func(string) (any, error) {
+		return nil, NotFound("session not found: %d", 7)
+	}})
+	Register(Method{Name: "test.broken", Handler: func(string) (any, error) {
+		return nil, errors.New("disk full")
+	}})
+
+	cases := []struct {
+		method, params string
+		code           int
+		kind           string
+	}{
+		{"test.echo", `{"symbol":"AAPL.US","days":3}`, 200, ""},
+		{"test.echo", "", 200, ""},
+		{"test.echo", `{"days":"three"}`, 400, KindInvalidParams},
+		{"test.echo", `{`, 400, KindInvalidParams},
+		{"test.missing", "", 404, KindNotFound},
+		{"test.broken", "", 500, KindInternal},
+		{"test.nope", "", 404, KindMethodNotFound},
+	}
+	for _, c := range cases {
+		resp := Dispatch(c.method, c.params)
+		if resp.Code != c.code || resp.Error != c.kind {
+			t.Errorf("%s %s: got %d/%q, want %d/%q (%s)", c.method, c.params, resp.Code, resp.Error, c.code, c.kind, resp.Msg)
+		}
+	}
+
+	var echo *MethodInfo
+	for _, m := range Methods() {
+		if m.Name == "test.echo" {
+			echo = &m
+		}
+	}
+	if echo == nil {
+		t.Fatal("test.echo not listed")
+	}
+	props := echo.Params["properties"].(map[string]any)
+	if props["days"].(map[string]any)["type"] != "integer" || props["tags"].(map[string]any)["type"] != "array" {
+		t.Errorf("params schema = %v", echo.Params)
+	}
+	if !reflect.DeepEqual(echo.Params["required"], []string{"symbol"}) {
+		t.Errorf("required = %v", echo.Params["required"])
+	}
+}
@@ -0,0 +1,64 @@
+package rpc
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Schema returns a JSON Schema object for the params struct v, derived from
+// its json tags. Fields tagged `rpc:"required"` are listed as required.
+func Schema(v any) map[string]any {
+	return typeSchema(reflect.TypeOf(v))
+}
+
+func typeSchema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		props := map[string]any{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if !sf.IsExported() || name == "-" {
+				continue
+			}
+			if sf.Anonymous && name == "" {
+				if embedded := typeSchema(sf.Type); embedded["properties"] != nil {
+					for k, p := range embedded["properties"].(map[string]any) {
+						props[k] = p
+					}
+				}
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			props[name] = typeSchema(sf.Type)
+			if sf.Tag.Get("rpc") == "required" {
+				required = append(required, name)
+			}
+		}
+		out := map[string]any{"type": "object", "properties": props}
+		if len(required) > 0 {
+			out["required"] = required
+		}
+		return out
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,306 @@
+// Package rundir lays out the working directory of one analysis run:
+//
+//	<results_dir>/<symbol>/<trade date>/<run id>/
+//	  manifest.json   what the run was and which files it left
+//	  state.json      the final trading state, config removed
+//	  report.json     the final report
+//	  events.jsonl    the callback events of the run, one per line
+//	  reports/        each agent's markdown report
+//	  tools/          the raw tool data bundle (export_tool_data)
+//	  charts/         price charts rendered for exports
+//	  exports/        reports exported with agent.report.export
+//
+// Several runs of a symbol on the same day no longer overwrite each other's
+// files. The run directory lives in the results area of the configured
+// storage, so every file is written through Put with a key from Key and is
+// encrypted like the session documents when encryption_key is set; Path is
+// the matching local path when the storage is local.
+package rundir
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/persist"
+	"github.com/dyike/CortexGo/pkg/secure"
+)
+
+// Files and subdirectories of a run directory.
+const (
+	ManifestFile = "manifest.json"
+	StateFile    = "state.json"
+	ReportFile   = "report.json"
+	EventsFile   = "events.jsonl"
+	ReportsDir   = "reports"
+	ToolsDir     = "tools"
+	ChartsDir    = "charts"
+	ExportsDir   = "exports"
+)
+
+// Run statuses recorded in the manifest.
+const (
+	StatusCompleted = "completed"
+	StatusError     = "error"
+	StatusCancelled = "cancelled"
+)
+
+// NewID returns a run ID that sorts by start time: 20060102-150405-<random>.
+func NewID(now time.Time) string {
+	var b [3]byte
+	_, _ = rand.Read(b[:])
+	return now.Format("20060102-150405") + "-" + hex.EncodeToString(b[:])
+}
+
+// Key returns the key of name inside the run's directory, relative to the
+// results area; without names it is the directory itself.
+func Key(symbol, tradeDate, runID string, name ...string) string {
+	return strings.Join(append([]string{symbol, tradeDate, runID}, name...), "/")
+}
+
+// Path returns the local path of name inside the run's directory under
+// resultsDir.
+func Path(resultsDir, symbol, tradeDate, runID string, name ...string) string {
+	return filepath.Join(resultsDir, filepath.FromSlash(Key(symbol, tradeDate, runID, name...)))
+}
+
+// Location shows where the run directory, or a file in it, at key is: a path
+// under results_dir for local storage, the backend and key otherwise.
+func Location(cfg *config.Config, key string) string {
+	if persist.Local(cfg) {
+		return filepath.Join(cfg.ResultsDir, filepath.FromSlash(key))
+	}
+	backend := cfg.StorageBackend
+	if backend == "" || backend == config.StorageLocal {
+		// an embedder's own storage, set with persist.SetDefault
+		backend = "storage"
+	}
+	return backend + ":" + string(persist.Results) + "/" + key
+}
+
+// Manifest describes a run and lists the files in its directory.
+type Manifest struct {
+	RunID          string            `json:"run_id"`
+	SessionID      string            `json:"session_id,omitempty"`
+	Symbol         string            `json:"symbol"`
+	TradeDate      string            `json:"trade_date"`
+	Status         string            `json:"status"`
+	Error          string            `json:"error,omitempty"`
+	Recommendation string            `json:"recommendation,omitempty"`
+	StartedAt      time.Time         `json:"started_at"`
+	FinishedAt     time.Time         `json:"finished_at"`
+	RunInputs      *models.RunInputs `json:"run_inputs,omitempty"`
+	// Files are relative to the run directory, sorted.
+	Files []string `json:"files"`
+}
+
+// Events collects the callback events of a run for events.jsonl. Streamed
+// chunks are left out: the complete messages they add up to are recorded.
+// It is safe for concurrent use.
+type Events struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+type event struct {
+	At    time.Time        `json:"at"`
+	Event string           `json:"event"`
+	Data  *models.ChatResp `json:"data,omitempty"`
+}
+
+// Record appends one event; its signature matches graph.LoggerCallback.Emit.
+func (e *Events) Record(name string, data *models.ChatResp) {
+	if e == nil || name == "message_chunk" {
+		return
+	}
+	line, err := json.Marshal(event{At: time.Now(), Event: name, Data: data})
+	if err != nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.buf.Write(line)
+	e.buf.WriteByte('\n')
+}
+
+func (e *Events) bytes() []byte {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return bytes.Clone(e.buf.Bytes())
+}
+
+// Finish writes the state, the report (nil when the run produced none), the
+// events and the manifest of a finished run to its directory and returns the
+// directory's key. runErr is the run's error, if any; sessionID is empty for
+// runs without a session.
+func Finish(ctx context.Context, cfg *config.Config, state *models.TradingState, rep *report.Report, events *Events, sessionID string, runErr error) (string, error) {
+	if state == nil || state.RunID == "" {
+		return "", errors.New("run has no run ID")
+	}
+	rs, err := openStore(cfg)
+	if err != nil {
+		return "", err
+	}
+	key := func(name ...string) string {
+		return Key(state.CompanyOfInterest, state.TradeDate, state.RunID, name...)
+	}
+
+	// the config holds API keys and has no place next to the results
+	st := *state
+	st.Config = nil
+	if err := rs.putJSON(ctx, key(StateFile), &st); err != nil {
+		return "", err
+	}
+	if rep != nil {
+		if err := rs.putJSON(ctx, key(ReportFile), rep); err != nil {
+			return "", err
+		}
+	}
+	if data := events.bytes(); len(data) > 0 {
+		if err := rs.put(ctx, key(EventsFile), data); err != nil {
+			return "", err
+		}
+	}
+
+	m := &Manifest{
+		RunID:      state.RunID,
+		SessionID:  sessionID,
+		Symbol:     state.CompanyOfInterest,
+		TradeDate:  state.TradeDate,
+		Status:     StatusCompleted,
+		FinishedAt: time.Now(),
+		RunInputs:  state.RunInputs,
+	}
+	if state.RunInputs != nil {
+		m.StartedAt = state.RunInputs.StartedAt
+	}
+	switch {
+	case errors.Is(runErr, context.Canceled):
+		m.Status, m.Error = StatusCancelled, runErr.Error()
+	case runErr != nil:
+		m.Status, m.Error = StatusError, runErr.Error()
+	}
+	if rep != nil {
+		m.Recommendation = rep.Recommendation
+		// the report's copy is completed with the tool versions and data dates
+		if rep.RunInputs != nil {
+			m.RunInputs = rep.RunInputs
+		}
+	}
+	return key(), rs.writeManifest(ctx, key(), m)
+}
+
+// Refresh relists the files of the run directory at dirKey in its manifest,
+// after a file was added to a finished run (an export, a chart). A run
+// without a manifest is left alone.
+func Refresh(ctx context.Context, cfg *config.Config, dirKey string) error {
+	rs, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	data, err := rs.get(ctx, dirKey+"/"+ManifestFile)
+	if errors.Is(err, persist.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("parse %s/%s: %w", dirKey, ManifestFile, err)
+	}
+	return rs.writeManifest(ctx, dirKey, &m)
+}
+
+// Put writes data to key in the results area of cfg's storage, encrypted
+// when an encryption key is configured, as every file of a run directory is.
+func Put(ctx context.Context, cfg *config.Config, key string, data []byte) error {
+	rs, err := openStore(cfg)
+	if err != nil {
+		return err
+	}
+	return rs.put(ctx, key, data)
+}
+
+// Get reads a file written by Put or Finish.
+func Get(ctx context.Context, cfg *config.Config, key string) ([]byte, error) {
+	rs, err := openStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return rs.get(ctx, key)
+}
+
+// store is the results area of a config's storage with its cipher; like
+// the session documents, files are sealed at rest.
+type store struct {
+	s      persist.Storage
+	cipher *secure.Cipher
+}
+
+func openStore(cfg *config.Config) (*store, error) {
+	s, err := persist.For(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := secure.ForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("load encryption key: %w", err)
+	}
+	return &store{s: s, cipher: cipher}, nil
+}
+
+func (rs *store) put(ctx context.Context, key string, data []byte) error {
+	data, err := rs.cipher.Seal(data)
+	if err != nil {
+		return fmt.Errorf("encrypt %s: %w", key, err)
+	}
+	return rs.s.Put(ctx, persist.Results, key, data)
+}
+
+func (rs *store) get(ctx context.Context, key string) ([]byte, error) {
+	data, _, err := rs.s.Get(ctx, persist.Results, key)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = rs.cipher.Open(data); err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", key, err)
+	}
+	return data, nil
+}
+
+func (rs *store) writeManifest(ctx context.Context, dirKey string, m *Manifest) error {
+	keys, err := rs.s.List(ctx, persist.Results, dirKey+"/")
+	if err != nil {
+		return err
+	}
+	m.Files = []string{}
+	for _, k := range keys {
+		if name := strings.TrimPrefix(k, dirKey+"/"); name != ManifestFile {
+			m.Files = append(m.Files, name)
+		}
+	}
+	return rs.putJSON(ctx, dirKey+"/"+ManifestFile, m)
+}
+
+func (rs *store) putJSON(ctx context.Context, key string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", key, err)
+	}
+	return rs.put(ctx, key, data)
+}
@@ -0,0 +1,179 @@
+package rundir_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/bundle"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rundir"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/persist"
+	"github.com/dyike/CortexGo/pkg/secure"
+)
+
+func TestFinishLaysOutTheRunDirectory(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{ResultsDir: dir, DataDir: dir, DataCacheDir: dir, DeepSeekAPIKey: "sk-secret"}
+	state := &models.TradingState{
+		CompanyOfInterest: "AAPL.US",
+		TradeDate:         "2025-06-02",
+		RunID:             "20250602-093000-abcdef",
+		Config:            cfg,
+		Evidence:          []*models.Evidence{{ID: "E1", Tool: "get_market_data"}},
+		ToolResults:       []*models.ToolResult{{EvidenceID: "E1", Output: "{}"}},
+	}
+	runDir := rundir.Path(dir, "AAPL.US", "2025-06-02", state.RunID)
+
+	if err := os.MkdirAll(filepath.Join(runDir, rundir.ReportsDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(runDir, rundir.ReportsDir, "trader_report.md"), []byte("# plan"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := bundle.Write(context.Background(), cfg, state, "7"); err != nil || path != filepath.Join(runDir, rundir.ToolsDir, "tool_data.zip") {
+		t.Fatalf("bundle = %s, %v; want it in the run directory", path, err)
+	}
+
+	events := &rundir.Events{}
+	events.Record("message_chunk", &models.ChatResp{Content: "par"})
+	events.Record("text_final", &models.ChatResp{AgentName: "trader", Content: "partial plan"})
+	rep := &report.Report{RunID: state.RunID, Symbol: "AAPL.US", TradeDate: "2025-06-02", Recommendation: "BUY"}
+
+	key, err := rundir.Finish(context.Background(), cfg, state, rep, events, "7", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rundir.Location(cfg, key); got != runDir {
+		t.Errorf("location = %s, want %s", got, runDir)
+	}
+
+	m := readManifest(t, runDir)
+	want := []string{"events.jsonl", "report.json", "reports/trader_report.md", "state.json", "tools/tool_data.zip"}
+	if m.RunID != state.RunID || m.SessionID != "7" || m.Status != rundir.StatusCompleted || m.Recommendation != "BUY" || !reflect.DeepEqual(m.Files, want) {
+		t.Errorf("manifest = %+v, want files %v", m, want)
+	}
+
+	stateJSON, _ := os.ReadFile(filepath.Join(runDir, rundir.StateFile))
+	if strings.Contains(string(stateJSON), "sk-secret") || !strings.Contains(string(stateJSON), state.RunID) {
+		t.Errorf("state.json leaks the config or lacks the run ID:\n%s", stateJSON)
+	}
+	eventLines, _ := os.ReadFile(filepath.Join(runDir, rundir.EventsFile))
+	if n := strings.Count(string(eventLines), "\n"); n != 1 || !strings.Contains(string(eventLines), "text_final") {
+		t.Errorf("events.jsonl = %s, want only the complete message", eventLines)
+	}
+
+	// an export added later shows up once the manifest is refreshed
+	if err := os.MkdirAll(filepath.Join(runDir, rundir.ExportsDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(runDir, rundir.ExportsDir, "report.md"), []byte("# report"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := rundir.Refresh(context.Background(), cfg, key); err != nil {
+		t.Fatal(err)
+	}
+	if m := readManifest(t, runDir); len(m.Files) != len(want)+1 || m.Files[0] != "events.jsonl" || m.Files[1] != "exports/report.md" {
+		t.Errorf("refreshed files = %v", m.Files)
+	}
+}
+
+func TestRunDirectoryFollowsTheStorage(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{ResultsDir: filepath.Join(dir, "results"), DataDir: dir, StorageBackend: config.StorageSQLite}
+	state := &models.TradingState{
+		CompanyOfInterest: "AAPL.US",
+		TradeDate:         "2025-06-02",
+		RunID:             "20250602-093000-abcdef",
+		Evidence:          []*models.Evidence{{ID: "E1", Tool: "get_market_data"}},
+		ToolResults:       []*models.ToolResult{{EvidenceID: "E1", Output: "{}"}},
+	}
+	ctx := context.Background()
+	path, err := bundle.Write(ctx, cfg, state, "7")
+	if err != nil || path != "sqlite:results/AAPL.US/2025-06-02/"+state.RunID+"/tools/tool_data.zip" {
+		t.Fatalf("bundle = %s, %v", path, err)
+	}
+	key, err := rundir.Finish(ctx, cfg, state, nil, nil, "7", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := persist.For(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := s.Get(ctx, persist.Results, key+"/"+rundir.ManifestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m rundir.Manifest
+	if err := json.Unmarshal(data, &m); err != nil || !reflect.DeepEqual(m.Files, []string{"state.json", "tools/tool_data.zip"}) {
+		t.Fatalf("manifest files = %v, %v", m.Files, err)
+	}
+	if _, err := os.Stat(cfg.ResultsDir); !os.IsNotExist(err) {
+		t.Errorf("results_dir written with sqlite storage: %v", err)
+	}
+
+	// an embedder's storage is not results_dir either
+	persist.SetDefault(persist.NewLocal(map[persist.Area]string{"": t.TempDir()}))
+	t.Cleanup(func() { persist.SetDefault(nil) })
+	cfg.StorageBackend = config.StorageLocal
+	if got := rundir.Location(cfg, key); got != "storage:results/"+key {
+		t.Errorf("location with a default storage = %s", got)
+	}
+}
+
+func TestRunDirectoryIsEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	key, err := secure.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{ResultsDir: dir, DataDir: dir, EncryptionKey: key}
+	state := &models.TradingState{CompanyOfInterest: "AAPL.US", TradeDate: "2025-06-02", RunID: "20250602-093000-abcdef", FinalTradeDecision: "BUY, the plan"}
+	ctx := context.Background()
+	events := &rundir.Events{}
+	events.Record("text_final", &models.ChatResp{AgentName: "trader", Content: "partial plan"})
+	dirKey, err := rundir.Finish(ctx, cfg, state, &report.Report{RunID: state.RunID, Recommendation: "BUY"}, events, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{rundir.StateFile, rundir.ReportFile, rundir.EventsFile, rundir.ManifestFile} {
+		raw, err := os.ReadFile(filepath.Join(rundir.Path(dir, "AAPL.US", "2025-06-02", state.RunID), name))
+		if err != nil || !secure.IsSealed(raw) {
+			t.Errorf("%s stored in plaintext: %v", name, err)
+		}
+	}
+	data, err := rundir.Get(ctx, cfg, dirKey+"/"+rundir.StateFile)
+	if err != nil || !strings.Contains(string(data), "the plan") {
+		t.Fatalf("state = %s, %v", data, err)
+	}
+	if err := rundir.Put(ctx, cfg, dirKey+"/exports/report.md", []byte("# report")); err != nil {
+		t.Fatal(err)
+	}
+	if err := rundir.Refresh(ctx, cfg, dirKey); err != nil {
+		t.Fatal(err)
+	}
+	data, err = rundir.Get(ctx, cfg, dirKey+"/"+rundir.ManifestFile)
+	if err != nil || !strings.Contains(string(data), "exports/report.md") {
+		t.Fatalf("refreshed manifest = %s, %v", data, err)
+	}
+}
+
+func readManifest(t *testing.T, runDir string) rundir.Manifest {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(runDir, rundir.ManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m rundir.Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
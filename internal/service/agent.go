@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"time"
@@ -11,8 +12,13 @@ import (
 	"github.com/cloudwego/eino/compose"
 	"github.com/dyike/CortexGo/config"
 	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/bundle"
 	"github.com/dyike/CortexGo/internal/graph"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/rundir"
 	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/internal/tools"
 	"github.com/dyike/CortexGo/models"
 	"github.com/dyike/CortexGo/pkg/bridge"
 )
@@ -21,12 +27,16 @@ import (
 func StartAgentStream(paramsJson string) (any, error) {
 	var params models.AgentInitParams
 	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
-		return nil, fmt.Errorf("invalid params: %w", err)
+		return nil, rpc.InvalidParams("invalid params: %v", err)
 	}
+	return startAgent(params, func(string) bridge.NotifyFunc { return bridge.Notify })
+}
 
+// startAgent 创建会话并在后台运行编排；sinkFor 按会话 ID 返回接收本次事件的回调
+func startAgent(params models.AgentInitParams, sinkFor func(sessionID string) bridge.NotifyFunc) (map[string]string, error) {
 	params.Symbol = strings.TrimSpace(params.Symbol)
 	if params.Symbol == "" {
-		return nil, fmt.Errorf("symbol is required")
+		return nil, rpc.InvalidParams("symbol is required")
 	}
 
 	if strings.TrimSpace(params.TradeDate) == "" {
@@ -34,7 +44,13 @@ func StartAgentStream(paramsJson string) (any, error) {
 	}
 	parsedDate, err := time.Parse("2006-01-02", params.TradeDate)
 	if err != nil {
-		return nil, fmt.Errorf("invalid trade_date: %w", err)
+		return nil, rpc.InvalidParams("invalid trade_date: %v", err)
+	}
+	// 本次运行的 webhook 地址同样以配置的 webhook_secret 签名，须与 webhook_urls 一样是 http(s) 地址
+	for _, u := range params.WebhookURLs {
+		if !config.ValidURL(u) {
+			return nil, rpc.InvalidParams("invalid webhook_urls entry %q: want an http(s) URL", u)
+		}
 	}
 
 	if strings.TrimSpace(params.Prompt) == "" {
@@ -42,8 +58,31 @@ func StartAgentStream(paramsJson string) (any, error) {
 	}
 
 	cfg := config.Get()
-	if cfg.DeepSeekAPIKey == "" {
-		return nil, fmt.Errorf("deepseek api key is required")
+	if err := cfg.LLMReady(); err != nil {
+		return nil, err
+	}
+	if params.Offline {
+		cfg.Offline = true
+	}
+	if params.Depth != "" {
+		if !config.ValidDepth(params.Depth) {
+			return nil, rpc.InvalidParams("invalid depth %q: want quick, standard or deep", params.Depth)
+		}
+		cfg.Depth = params.Depth
+	}
+	if params.RiskProfile != "" {
+		if !config.ValidRiskProfile(params.RiskProfile) {
+			return nil, rpc.InvalidParams("invalid risk_profile %q: want conservative, balanced or aggressive", params.RiskProfile)
+		}
+		cfg.RiskProfile = params.RiskProfile
+	}
+	if params.ExportToolData {
+		cfg.ExportToolData = true
+	}
+	if cfg.Offline {
+		if missing := tools.OfflinePreflight(&cfg, params.Symbol); len(missing) > 0 {
+			return nil, fmt.Errorf("offline mode: missing local data:\n  - %s", strings.Join(missing, "\n  - "))
+		}
 	}
 
 	ctx := context.Background()
@@ -73,13 +112,16 @@ func StartAgentStream(paramsJson string) (any, error) {
 		return nil, fmt.Errorf("record user prompt: %w", err)
 	}
 
+	var finalState *models.TradingState
 	genFunc := func(ctx context.Context) *models.TradingState {
-		return models.NewTradingState(params.Symbol, parsedDate, params.Prompt, &cfg)
+		finalState = models.NewTradingState(params.Symbol, parsedDate, params.Prompt, &cfg)
+		return finalState
 	}
 
 	orchestrator := graph.NewTradingOrchestrator[string, string, *models.TradingState](ctx, genFunc, &cfg)
 	sessionID := sessionRec.Id
 	sessionIDStr := strconv.FormatInt(sessionID, 10)
+	notify := sinkFor(sessionIDStr)
 	persistStreamEvent := func(event string, data *models.ChatResp) {
 		if data == nil {
 			return
@@ -135,21 +177,51 @@ func StartAgentStream(paramsJson string) (any, error) {
 		}
 	}
 
+	runCtx, release := registerRun(models.AgentRunInfo{
+		SessionId: sessionIDStr,
+		Symbol:    params.Symbol,
+		TradeDate: params.TradeDate,
+		Depth:     agents.PresetFor(&cfg).Name,
+		Offline:   cfg.Offline,
+	})
 	go func() {
-		_, streamErr := orchestrator.Stream(ctx, params.Prompt,
-			compose.WithCallbacks(&graph.LoggerCallback{
-				Emit: func(event string, data *models.ChatResp) {
-					persistStreamEvent(event, data)
-					if data == nil {
-						return
-					}
-					payload, _ := json.Marshal(data)
-					bridge.Notify("agent."+event, string(payload))
-				},
-			}),
-		)
+		defer release()
+		// 图内的 panic 已由工具与模型包装恢复；这里兜住图之外的收尾代码（报告、投递），
+		// 会话记为失败而不拖垮托管 libcortex 的进程
+		defer func() {
+			if r := recover(); r != nil {
+				fmt.Printf("analysis session=%s panic: %v\n%s", sessionIDStr, r, debug.Stack())
+				if err := store.UpdateSessionStatus(ctx, sessionID, storage.StatusError); err != nil {
+					fmt.Printf("update session status err=%v\n", err)
+				}
+				errPayload, _ := json.Marshal(map[string]string{"error": fmt.Sprintf("internal error: %v", r)})
+				notify("agent.error", string(errPayload))
+			}
+		}()
+		events := &rundir.Events{}
+		logger := &graph.LoggerCallback{
+			Emit: func(event string, data *models.ChatResp) {
+				persistStreamEvent(event, data)
+				events.Record(event, data)
+				if data == nil {
+					return
+				}
+				touchRun(sessionIDStr, data.AgentName)
+				resp := *data
+				resp.SessionId = sessionIDStr
+				payload, _ := json.Marshal(&resp)
+				notify("agent."+event, string(payload))
+			},
+		}
+		_, streamErr := orchestrator.Stream(runCtx, params.Prompt, compose.WithCallbacks(logger))
+		// 等待最后几段流式输出发出事件，再记录状态并写入运行目录
+		logger.Wait()
+		cancelled := streamErr != nil && runCtx.Err() != nil
 		status := storage.StatusDone
-		if streamErr != nil {
+		switch {
+		case cancelled:
+			status = storage.StatusCancelled
+		case streamErr != nil:
 			status = storage.StatusError
 			if err := store.SaveMessage(ctx, &models.MessageRecord{
 				SessionId: sessionID,
@@ -163,14 +235,53 @@ func StartAgentStream(paramsJson string) (any, error) {
 		if err := store.UpdateSessionStatus(ctx, sessionID, status); err != nil {
 			fmt.Printf("update session status err=%v\n", err)
 		}
+		// 运行目录写入失败不影响会话结果
+		finishRun := func(rep *report.Report) {
+			if finalState == nil {
+				return
+			}
+			if _, err := rundir.Finish(ctx, &cfg, finalState, rep, events, sessionIDStr, streamErr); err != nil {
+				fmt.Printf("write run directory err=%v\n", err)
+			}
+		}
 
+		if cancelled {
+			finishRun(nil)
+			notify("agent.cancelled", `{"status":"cancelled"}`)
+			return
+		}
 		if streamErr != nil {
+			finishRun(nil)
 			errPayload, _ := json.Marshal(map[string]string{"error": streamErr.Error()})
-			bridge.Notify("agent.error", string(errPayload))
+			notify("agent.error", string(errPayload))
 			return
 		}
 
-		bridge.Notify("agent.finished", `{"status":"completed"}`)
+		rep := report.FromState(finalState)
+		if rep != nil {
+			rep.SessionID = sessionIDStr
+			if cfg.ExportToolData {
+				// 数据包写入失败不影响报告保存
+				if path, err := bundle.Write(ctx, &cfg, finalState, sessionIDStr); err != nil {
+					fmt.Printf("write tool data bundle err=%v\n", err)
+				} else {
+					rep.DataBundle = path
+				}
+			}
+			if err := saveReport(ctx, store, sessionID, rep); err != nil {
+				fmt.Printf("save report err=%v\n", err)
+			}
+		}
+		finishRun(rep)
+		if rep != nil {
+			notify("agent.decision", decisionPayload(rep))
+		}
+		notify("agent.finished", `{"status":"completed"}`)
+
+		if rep != nil {
+			deliverReport(cfg, rep, deliveryParams{EmailTo: params.EmailTo, WebhookURLs: params.WebhookURLs})
+			uploadReport(cfg, store, sessionID, rep)
+		}
 	}()
 
 	return map[string]string{"status": "started", "session_id": sessionIDStr}, nil
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dyike/CortexGo/internal/rpc"
+)
+
+func TestStartAgentStreamRejectsInvalidWebhookURLs(t *testing.T) {
+	for _, u := range []string{"ftp://example.com/hook", "example.com/hook", "http://"} {
+		_, err := StartAgentStream(`{"symbol":"AAPL.US","webhook_urls":["` + u + `"]}`)
+		var rerr *rpc.Error
+		if !errors.As(err, &rerr) || rerr.Kind != rpc.KindInvalidParams {
+			t.Errorf("webhook_urls %q: err = %v, want invalid params", u, err)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/alerts"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/bridge"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+	"github.com/dyike/CortexGo/pkg/notify"
+)
+
+var (
+	alertsMu       sync.Mutex
+	alertsCancel   context.CancelFunc
+	alertsInterval time.Duration
+)
+
+// AddAlert 新建价格提醒（alerts.add）
+func AddAlert(paramsJson string) (any, error) {
+	var params models.AlertAddParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	return CreateAlert(context.Background(), params)
+}
+
+// CreateAlert 同 AddAlert，供命令行直接调用
+func CreateAlert(ctx context.Context, params models.AlertAddParams) (*models.Alert, error) {
+	a, err := alerts.New(params)
+	if err != nil {
+		return nil, rpc.InvalidParams("%v", err)
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	if err := store.CreateAlert(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListAlerts 列出价格提醒（alerts.list）
+func ListAlerts(paramsJson string) (any, error) {
+	var params models.AlertListParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	return LoadAlerts(context.Background(), params)
+}
+
+// LoadAlerts 同 ListAlerts，供命令行直接调用
+func LoadAlerts(ctx context.Context, params models.AlertListParams) ([]models.Alert, error) {
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	items, err := store.ListAlerts(ctx, strings.ToUpper(strings.TrimSpace(params.Symbol)), params.All)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []models.Alert{}
+	}
+	return items, nil
+}
+
+// DeleteAlert 删除价格提醒（alerts.del）
+func DeleteAlert(paramsJson string) (any, error) {
+	var params models.AlertDeleteParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	if err := RemoveAlert(context.Background(), params.Id); err != nil {
+		return nil, err
+	}
+	return map[string]any{"id": params.Id, "deleted": true}, nil
+}
+
+// RemoveAlert 同 DeleteAlert，供命令行直接调用
+func RemoveAlert(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return rpc.InvalidParams("invalid id")
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return fmt.Errorf("open sqlite: %w", err)
+	}
+	if err := store.DeleteAlert(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rpc.NotFound("alert not found: %d", id)
+		}
+		return err
+	}
+	return nil
+}
+
+// StartAlerts 在后台启动价格提醒守护（alerts.start），重复调用返回运行中的状态
+func StartAlerts(paramsJson string) (any, error) {
+	var params models.AlertWatchParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	interval := alertInterval(params.Interval)
+
+	alertsMu.Lock()
+	defer alertsMu.Unlock()
+	if alertsCancel == nil {
+		cfg := config.Get()
+		engine, err := NewAlertEngine(&cfg, nil)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		alertsCancel, alertsInterval = cancel, interval
+		go engine.Run(ctx, interval, func(err error) {
+			fmt.Printf("alerts check err=%v\n", err)
+		})
+	}
+	return alertStatus(true, alertsInterval)
+}
+
+// StopAlerts 停止价格提醒守护（alerts.stop）
+func StopAlerts(string) (any, error) {
+	alertsMu.Lock()
+	defer alertsMu.Unlock()
+	if alertsCancel != nil {
+		alertsCancel()
+		alertsCancel = nil
+	}
+	return alertStatus(false, 0)
+}
+
+func alertStatus(running bool, interval time.Duration) (models.AlertWatchResponse, error) {
+	active, err := LoadAlerts(context.Background(), models.AlertListParams{})
+	if err != nil {
+		return models.AlertWatchResponse{}, err
+	}
+	return models.AlertWatchResponse{Running: running, Interval: int(interval / time.Second), Alerts: len(active)}, nil
+}
+
+// alertInterval 秒数转为轮询间隔，未指定时使用默认值，过短时取下限
+func alertInterval(seconds int) time.Duration {
+	if seconds <= 0 {
+		return alerts.DefaultInterval
+	}
+	return max(time.Duration(seconds)*time.Second, alerts.MinInterval)
+}
+
+// NewAlertEngine 创建提醒引擎：行情来自长桥，触发时启动一次新的分析（沿用 cfg 的深度、风险偏好与投递配置），
+// 并推送 alert.triggered 事件与 webhook；onTrigger 非空时额外回调，供命令行输出
+func NewAlertEngine(cfg *config.Config, onTrigger func(models.AlertTrigger)) (*alerts.Engine, error) {
+	if cfg.Offline {
+		return nil, fmt.Errorf("price alerts need live quotes: %w", dataflows.ErrOffline)
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	client, err := dataflows.NewLongportClient(dataflows.LongportConfigFrom(cfg))
+	if err != nil {
+		return nil, err
+	}
+	depth, risk := cfg.Depth, cfg.RiskProfile
+	webhookURLs, webhookSecret := cfg.WebhookURLs, cfg.WebhookSecret
+
+	return &alerts.Engine{
+		Store: store,
+		Quotes: func(ctx context.Context, symbols []string) (map[string]alerts.Quote, error) {
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+			quotes, err := client.GetQuote(ctx, symbols)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[string]alerts.Quote, len(quotes))
+			for _, q := range quotes {
+				out[q.Symbol] = alerts.Quote{Symbol: q.Symbol, Last: dataflows.DecimalFloat(q.LastDone), PrevClose: dataflows.DecimalFloat(q.PrevClose)}
+			}
+			return out, nil
+		},
+		Fire: func(_ context.Context, a models.Alert, q alerts.Quote) (string, error) {
+			started, err := startAgent(models.AgentInitParams{
+				Symbol: a.Symbol,
+				Prompt: fmt.Sprintf("Price alert triggered: %s (last %.2f, %+.2f%% on the day). Analyze trading opportunities for %s on %s",
+					alerts.Describe(a), q.Last, q.ChangePct(), a.Symbol, time.Now().Format("2006-01-02")),
+				Depth:       depth,
+				RiskProfile: risk,
+			}, func(string) bridge.NotifyFunc { return bridge.Notify })
+			if err != nil {
+				return "", err
+			}
+			return started["session_id"], nil
+		},
+		OnTrigger: func(t models.AlertTrigger) {
+			payload, _ := json.Marshal(t)
+			bridge.Notify("alert.triggered", string(payload))
+			if len(webhookURLs) > 0 {
+				if err := notify.NewWebhook(webhookURLs, webhookSecret).Post(context.Background(), "alert.triggered", payload); err != nil {
+					fmt.Printf("webhook alert id=%d err=%v\n", t.Alert.Id, err)
+				}
+			}
+			if onTrigger != nil {
+				onTrigger(t)
+			}
+		},
+	}, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/bridge"
+)
+
+// 分析阶段，用于 analysis.phase_complete
+const (
+	PhaseAnalysts = "analysts"
+	PhaseResearch = "research"
+	PhaseTrading  = "trading"
+	PhaseRisk     = "risk"
+)
+
+var agentPhases = map[string]string{
+	consts.MarketAnalyst:       PhaseAnalysts,
+	consts.SocialAnalyst:       PhaseAnalysts,
+	consts.NewsAnalyst:         PhaseAnalysts,
+	consts.FundamentalsAnalyst: PhaseAnalysts,
+	consts.BullResearcher:      PhaseResearch,
+	consts.BearResearcher:      PhaseResearch,
+	consts.ResearchManager:     PhaseResearch,
+	consts.Trader:              PhaseTrading,
+	consts.RiskyAnalyst:        PhaseRisk,
+	consts.SafeAnalyst:         PhaseRisk,
+	consts.NeutralAnalyst:      PhaseRisk,
+	consts.RiskJudge:           PhaseRisk,
+}
+
+// AnalyzeAsync 启动一次分析，并把高层事件（agent 开始、报告分片、阶段完成、最终决策）推送给 notify，
+// 而不是 agent.stream 的底层 agent.* 事件
+func AnalyzeAsync(params models.AgentInitParams, notify bridge.NotifyFunc) (any, error) {
+	return startAgent(params, func(sessionID string) bridge.NotifyFunc {
+		return newAnalysisTracker(sessionID, notify).handle
+	})
+}
+
+// StartAnalysis 同 AnalyzeAsync，参数为 agent.stream 的 JSON（可指定 depth、offline、prompt、收件人等），
+// 每个分析使用独立的配置副本与事件回调
+func StartAnalysis(paramsJson string, notify bridge.NotifyFunc) (any, error) {
+	var params models.AgentInitParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	return AnalyzeAsync(params, notify)
+}
+
+// decisionPayload agent.decision 事件内容
+func decisionPayload(rep *report.Report) string {
+	out := map[string]any{
+		"session_id":     rep.SessionID,
+		"symbol":         rep.Symbol,
+		"trade_date":     rep.TradeDate,
+		"recommendation": rep.Recommendation,
+	}
+	if d := report.ExtractDecision(rep); d != nil {
+		out["confidence"] = d.Confidence
+		if rep.Decision != nil && rep.Decision.CalibratedConfidence > 0 {
+			out["calibrated_confidence"] = rep.Decision.CalibratedConfidence
+		}
+		// 仓位以 FromState 中按风险偏好截断后的值为准
+		if rep.Decision != nil && rep.Decision.PositionSize > 0 {
+			out["position_size"] = rep.Decision.PositionSize
+			out["holding_days"] = rep.Decision.HoldingDays
+		}
+		out["entry_price"] = d.EntryPrice
+		out["stop_loss"] = d.StopLoss
+		out["take_profit"] = d.TakeProfit
+	}
+	b, _ := json.Marshal(out)
+	return string(b)
+}
+
+// analysisTracker 将 agent.* 事件转换为 analysis.* 事件；图回调可能并发触发，需加锁
+type analysisTracker struct {
+	sessionID string
+	notify    bridge.NotifyFunc
+
+	mu     sync.Mutex
+	agent  string
+	phase  string
+	closed bool
+}
+
+func newAnalysisTracker(sessionID string, notify bridge.NotifyFunc) *analysisTracker {
+	return &analysisTracker{sessionID: sessionID, notify: notify}
+}
+
+func (t *analysisTracker) handle(topic, payload string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+
+	switch topic {
+	case "agent.decision":
+		t.completePhase()
+		t.notify("analysis.decision", payload)
+		return
+	case "agent.finished":
+		t.completePhase()
+		t.emit("analysis.finished", map[string]any{"status": "completed"})
+		t.closed = true
+		return
+	case "agent.cancelled":
+		t.emit("analysis.cancelled", map[string]any{"status": "cancelled"})
+		t.closed = true
+		return
+	case "agent.error":
+		var msg models.ChatResp
+		var failure struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal([]byte(payload), &failure)
+		if failure.Error == "" {
+			// 模型回调中的错误不终止分析，只转发
+			_ = json.Unmarshal([]byte(payload), &msg)
+			t.emit("analysis.error", map[string]any{"agent": msg.AgentName, "error": msg.Content, "fatal": false})
+			return
+		}
+		t.emit("analysis.error", map[string]any{"error": failure.Error, "fatal": true})
+		t.closed = true
+		return
+	}
+
+	var msg models.ChatResp
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil || msg.AgentName == "" {
+		return
+	}
+	if msg.AgentName != t.agent {
+		// 未归类的 agent 沿用当前阶段
+		if phase := agentPhases[msg.AgentName]; phase != "" && phase != t.phase {
+			t.completePhase()
+			t.phase = phase
+		}
+		t.agent = msg.AgentName
+		t.emit("analysis.agent_started", map[string]any{"agent": msg.AgentName, "phase": t.phase})
+	}
+	if topic == "agent.message_chunk" && strings.TrimSpace(msg.Content) != "" && msg.Role != "tool" {
+		t.emit("analysis.report_chunk", map[string]any{"agent": msg.AgentName, "phase": t.phase, "content": msg.Content})
+	}
+}
+
+// completePhase 在进入下一阶段或结束时发出 analysis.phase_complete
+func (t *analysisTracker) completePhase() {
+	if t.phase == "" {
+		return
+	}
+	t.emit("analysis.phase_complete", map[string]any{"phase": t.phase, "last_agent": t.agent})
+	t.phase = ""
+}
+
+func (t *analysisTracker) emit(topic string, fields map[string]any) {
+	fields["session_id"] = t.sessionID
+	b, _ := json.Marshal(fields)
+	t.notify(topic, string(b))
+}
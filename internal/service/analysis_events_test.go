@@ -0,0 +1,70 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dyike/CortexGo/consts"
+	"github.com/dyike/CortexGo/models"
+)
+
+func TestAnalysisTrackerTranslatesAgentEvents(t *testing.T) {
+	var topics []string
+	var last map[string]any
+	tr := newAnalysisTracker("7", func(topic, payload string) {
+		topics = append(topics, topic)
+		last = map[string]any{}
+		_ = json.Unmarshal([]byte(payload), &last)
+	})
+	chunk := func(agent, content string) {
+		b, _ := json.Marshal(models.ChatResp{AgentName: agent, Role: "assistant", Content: content})
+		tr.handle("agent.message_chunk", string(b))
+	}
+
+	chunk(consts.MarketAnalyst, "price ")
+	chunk(consts.MarketAnalyst, "up")
+	chunk(consts.NewsAnalyst, "news")
+	chunk(consts.BullResearcher, "bull")
+	tr.handle("agent.decision", `{"session_id":"7","recommendation":"BUY"}`)
+	tr.handle("agent.finished", `{"status":"completed"}`)
+	tr.handle("agent.message_chunk", `{"agent_name":"trader","content":"late"}`)
+
+	want := []string{
+		"analysis.agent_started", "analysis.report_chunk", "analysis.report_chunk",
+		"analysis.agent_started", "analysis.report_chunk",
+		"analysis.phase_complete", "analysis.agent_started", "analysis.report_chunk",
+		"analysis.phase_complete", "analysis.decision", "analysis.finished",
+	}
+	if len(topics) != len(want) {
+		t.Fatalf("got %v\nwant %v", topics, want)
+	}
+	for i := range want {
+		if topics[i] != want[i] {
+			t.Fatalf("event %d = %s, want %s (all: %v)", i, topics[i], want[i], topics)
+		}
+	}
+	if last["session_id"] != "7" || last["status"] != "completed" {
+		t.Fatalf("unexpected finished payload %v", last)
+	}
+}
+
+func TestCancelAnalysis(t *testing.T) {
+	ctx, release := registerRun(models.AgentRunInfo{SessionId: "42"})
+	if CancelAnalysis("41") {
+		t.Fatal("unknown session reported as cancelled")
+	}
+	if !CancelAnalysis(" 42 ") {
+		t.Fatal("running session not cancelled")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("run context still active after cancel")
+	}
+	touchRun("42", consts.Trader)
+	if info, ok := GetRun("42"); !ok || info.Phase != PhaseTrading || info.Events != 1 {
+		t.Fatalf("unexpected run info %+v", info)
+	}
+	release()
+	if CancelAnalysis("42") {
+		t.Fatal("finished session should no longer be cancellable")
+	}
+}
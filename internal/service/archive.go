@@ -0,0 +1,168 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/parquet"
+)
+
+// archiveTable 导出的一张扁平表
+type archiveTable struct {
+	name    string
+	columns []parquet.Column
+	rows    [][]any
+}
+
+// ArchiveResults 将全部历史分析及结构化决策、表现打包为 zip（csv 或 parquet），便于 pandas/DuckDB 分析
+func ArchiveResults(paramsJson string) (any, error) {
+	var params models.ResultsArchiveParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	format := strings.ToLower(strings.TrimSpace(params.Format))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "parquet" {
+		return nil, rpc.InvalidParams("unsupported format %q (supported: csv, parquet)", format)
+	}
+
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	runs, err := collectExportRuns(context.Background(), store, models.RunFilter{Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+
+	outPath := strings.TrimSpace(params.Output)
+	if outPath == "" {
+		cfg := config.Get()
+		outPath = filepath.Join(cfg.ResultsDir, "archive_"+time.Now().Format("20060102_150405")+".zip")
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return nil, fmt.Errorf("create output dir: %w", err)
+	}
+	f, err := os.Create(outPath)
+	if err != nil {
+		return nil, fmt.Errorf("create archive: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	var files []string
+	for _, table := range archiveTables(runs) {
+		name := table.name + "." + format
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, fmt.Errorf("add %s: %w", name, err)
+		}
+		if format == "parquet" {
+			err = parquet.Write(w, table.columns, table.rows)
+		} else {
+			err = writeCSVTable(w, table)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("write %s: %w", name, err)
+		}
+		files = append(files, name)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+
+	return models.ResultsArchiveResponse{Path: outPath, Format: format, Files: files, Runs: len(runs)}, nil
+}
+
+// archiveTables 将导出记录拆成 runs / decisions / outcomes 三张表，以 session_id 关联
+func archiveTables(runs []models.ResultsExportRun) []archiveTable {
+	runsT := archiveTable{name: "runs", columns: []parquet.Column{
+		{Name: "session_id", Type: parquet.Int64},
+		{Name: "symbol", Type: parquet.String},
+		{Name: "trade_date", Type: parquet.String},
+		{Name: "status", Type: parquet.String},
+		{Name: "recommendation", Type: parquet.String},
+		{Name: "created_at", Type: parquet.String},
+	}}
+	decisionsT := archiveTable{name: "decisions", columns: []parquet.Column{
+		{Name: "session_id", Type: parquet.Int64},
+		{Name: "symbol", Type: parquet.String},
+		{Name: "trade_date", Type: parquet.String},
+		{Name: "action", Type: parquet.String},
+		{Name: "confidence", Type: parquet.Double},
+		{Name: "entry_price", Type: parquet.Double},
+		{Name: "stop_loss", Type: parquet.Double},
+		{Name: "take_profit", Type: parquet.Double},
+	}}
+	outcomesT := archiveTable{name: "outcomes", columns: []parquet.Column{
+		{Name: "session_id", Type: parquet.Int64},
+		{Name: "horizon_days", Type: parquet.Int64},
+		{Name: "eval_date", Type: parquet.String},
+		{Name: "entry_close", Type: parquet.Double},
+		{Name: "exit_close", Type: parquet.Double},
+		{Name: "return_pct", Type: parquet.Double},
+		{Name: "correct", Type: parquet.Boolean},
+	}}
+
+	for _, run := range runs {
+		id, _ := strconv.ParseInt(run.SessionID, 10, 64)
+		runsT.rows = append(runsT.rows, []any{id, run.Symbol, run.TradeDate, run.Status, run.Recommendation, run.CreatedAt})
+		if d := run.Decision; d != nil {
+			decisionsT.rows = append(decisionsT.rows, []any{id, d.Symbol, d.Date, d.Action, d.Confidence, d.EntryPrice, d.StopLoss, d.TakeProfit})
+		}
+		for _, o := range run.Outcomes {
+			outcomesT.rows = append(outcomesT.rows, []any{id, int64(o.HorizonDays), o.EvalDate, o.EntryClose, o.ExitClose, o.ReturnPct, o.Correct})
+		}
+	}
+	return []archiveTable{runsT, decisionsT, outcomesT}
+}
+
+func writeCSVTable(w io.Writer, table archiveTable) error {
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	header := make([]string, len(table.columns))
+	for i, c := range table.columns {
+		header[i] = c.Name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range table.rows {
+		record := make([]string, len(row))
+		for i, v := range row {
+			switch val := v.(type) {
+			case float64:
+				record[i] = strconv.FormatFloat(val, 'f', -1, 64)
+			default:
+				record[i] = fmt.Sprint(val)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
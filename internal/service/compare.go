@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// CompareResults 对比同一标的两次分析：评级变化、新增/消除的风险以及技术指标变化
+func CompareResults(paramsJson string) (any, error) {
+	var params models.ResultsCompareParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	ctx := context.Background()
+
+	var a, b *report.Report
+	if strings.TrimSpace(params.SessionA) != "" || strings.TrimSpace(params.SessionB) != "" {
+		if a, err = reportBySessionID(ctx, store, params.SessionA); err != nil {
+			return nil, err
+		}
+		if b, err = reportBySessionID(ctx, store, params.SessionB); err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(a.Symbol, b.Symbol) {
+			return nil, rpc.InvalidParams("sessions belong to different symbols: %s vs %s", a.Symbol, b.Symbol)
+		}
+	} else {
+		symbol := strings.TrimSpace(params.Symbol)
+		if symbol == "" {
+			return nil, rpc.InvalidParams("symbol is required")
+		}
+		if a, err = reportByDate(ctx, store, symbol, params.DateA); err != nil {
+			return nil, err
+		}
+		if b, err = reportByDate(ctx, store, symbol, params.DateB); err != nil {
+			return nil, err
+		}
+	}
+	if a.TradeDate > b.TradeDate {
+		a, b = b, a
+	}
+
+	cfg := config.Get()
+	indA, indB := compareIndicators(ctx, &cfg, b.Symbol, a.TradeDate, b.TradeDate)
+	return report.Compare(a, b, indA, indB), nil
+}
+
+func reportBySessionID(ctx context.Context, store *storage.Store, id string) (*report.Report, error) {
+	sessionInt, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+	if err != nil || sessionInt <= 0 {
+		return nil, rpc.InvalidParams("invalid session_id: %q", id)
+	}
+	return loadReport(ctx, store, sessionInt)
+}
+
+func reportByDate(ctx context.Context, store *storage.Store, symbol, date string) (*report.Report, error) {
+	date = strings.TrimSpace(date)
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, rpc.InvalidParams("invalid date %q: %v", date, err)
+	}
+	rec, err := store.LatestReportFor(ctx, symbol, date)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, rpc.NotFound("no analysis found for %s on %s", symbol, date)
+	}
+	return report.Decode(rec.Content)
+}
+
+// compareIndicators 计算两个交易日的最新指标值，行情不可用时返回 nil
+func compareIndicators(ctx context.Context, cfg *config.Config, symbol, dateA, dateB string) (map[string]float64, map[string]float64) {
+	ta, errA := time.Parse("2006-01-02", dateA)
+	tb, errB := time.Parse("2006-01-02", dateB)
+	if errA != nil || errB != nil {
+		return nil, nil
+	}
+	count := int(time.Since(ta).Hours()/24) + chartWarmupBars
+	data, err := tools.FetchMarketData(ctx, cfg, symbol, count)
+	if err != nil {
+		fmt.Printf("compare indicators symbol=%s err=%v\n", symbol, err)
+		return nil, nil
+	}
+	latest := func(day time.Time) map[string]float64 {
+		out := map[string]float64{}
+		// 取截至该日最近 10 天内的最后一个值，兼容非交易日
+		for name, values := range dataflows.CalculateAllIndicators(data, day.AddDate(0, 0, -10), day) {
+			if len(values) > 0 {
+				out[name] = values[len(values)-1].Value
+			}
+		}
+		return out
+	}
+	return latest(ta), latest(tb)
+}
@@ -0,0 +1,8 @@
+package service
+
+import "github.com/dyike/CortexGo/config"
+
+// GetConfigSchema 返回配置的 JSON Schema，供设置页渲染表单并在 UpdateConfig 前校验
+func GetConfigSchema() any {
+	return config.Schema()
+}
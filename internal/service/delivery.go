@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/pkg/notify"
+)
+
+// deliverReport 在分析成功结束后投递报告（邮件等），失败只记录日志不影响会话状态
+func deliverReport(cfg config.Config, rep *report.Report, params deliveryParams) {
+	if rep == nil {
+		return
+	}
+	// 邮件与 webhook 发出的报告按 report_redact 脱敏
+	rep = report.Redact(rep, &cfg)
+	if err := emailReport(cfg, rep, params.EmailTo); err != nil {
+		fmt.Printf("email report session=%s err=%v\n", rep.SessionID, err)
+	}
+	if err := webhookReport(cfg, rep, params.WebhookURLs); err != nil {
+		fmt.Printf("webhook report session=%s err=%v\n", rep.SessionID, err)
+	}
+}
+
+// deliveryParams 单次分析的投递选项，覆盖配置中的默认值
+type deliveryParams struct {
+	EmailTo     []string
+	WebhookURLs []string
+}
+
+// analysisResponse webhook 推送的负载，包含完整报告
+type analysisResponse struct {
+	Event  string         `json:"event"`
+	Status string         `json:"status"`
+	Report *report.Report `json:"report"`
+}
+
+func webhookReport(cfg config.Config, rep *report.Report, urls []string) error {
+	if len(urls) == 0 {
+		urls = cfg.WebhookURLs
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(analysisResponse{Event: "analysis.completed", Status: "completed", Report: rep})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	return notify.NewWebhook(urls, cfg.WebhookSecret).Post(context.Background(), "analysis.completed", body)
+}
+
+func emailReport(cfg config.Config, rep *report.Report, to []string) error {
+	recipients := to
+	if len(recipients) == 0 {
+		recipients = cfg.EmailRecipients
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+	smtpCfg := notify.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	}
+	if !smtpCfg.Enabled() {
+		return fmt.Errorf("smtp_host and smtp_from are required for email delivery")
+	}
+
+	fileName := fmt.Sprintf("%s_%s_report.html", strings.ReplaceAll(rep.Symbol, "/", "_"), rep.TradeDate)
+	return notify.SendEmail(smtpCfg, &notify.Email{
+		To:       recipients,
+		Subject:  "[CortexGo] " + rep.Headline(),
+		HTMLBody: emailBody(rep),
+		Attachments: []notify.Attachment{{
+			Name:        fileName,
+			ContentType: "text/html; charset=utf-8",
+			Data:        report.Rendered(&cfg, rep, "html"),
+		}},
+	})
+}
+
+// emailBody 邮件正文：标题建议 + 最终决策摘要，完整报告在附件中
+func emailBody(rep *report.Report) string {
+	rec := rep.Recommendation
+	if rec == "" {
+		rec = "N/A"
+	}
+	var b strings.Builder
+	b.WriteString("<html><body style=\"font-family:sans-serif\">")
+	b.WriteString(fmt.Sprintf("<h2>%s · %s</h2>", html.EscapeString(rep.Symbol), html.EscapeString(rep.TradeDate)))
+	b.WriteString(fmt.Sprintf("<p>Recommendation: <strong style=\"font-size:1.3em\">%s</strong></p>", html.EscapeString(rec)))
+	if decision := rep.Section("final_trade_decision"); decision != "" {
+		b.WriteString("<h3>Final Trade Decision</h3><pre style=\"white-space:pre-wrap;font-family:inherit\">")
+		b.WriteString(html.EscapeString(decision))
+		b.WriteString("</pre>")
+	}
+	b.WriteString("<p style=\"color:#888\">The full report is attached.</p></body></html>")
+	return b.String()
+}
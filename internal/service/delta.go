@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/agents/delta"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// DeltaAgent 以一次已完成的分析为基准运行增量分析：取最新行情与基准之后发布的新闻，
+// 单次模型调用给出更新后的结论，不创建新会话，用于两次完整分析之间的盘中刷新
+func DeltaAgent(paramsJson string) (any, error) {
+	var params models.AgentDeltaParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	sessionInt, err := strconv.ParseInt(strings.TrimSpace(params.SessionId), 10, 64)
+	if err != nil || sessionInt <= 0 {
+		return nil, rpc.InvalidParams("invalid session_id")
+	}
+	var since time.Time
+	if s := strings.TrimSpace(params.Since); s != "" {
+		if since, err = time.Parse(time.RFC3339, s); err != nil {
+			return nil, rpc.InvalidParams("invalid since: %v", err)
+		}
+	}
+
+	cfg := config.Get()
+	if err := cfg.LLMReady(); err != nil {
+		return nil, err
+	}
+	if cfg.Offline {
+		return nil, fmt.Errorf("delta analysis needs live quotes and news: %w", dataflows.ErrOffline)
+	}
+	ctx := context.Background()
+	if err := agents.InitChatModel(ctx, &cfg); err != nil {
+		return nil, fmt.Errorf("init chat model: %w", err)
+	}
+
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	prev, err := loadReport(ctx, store, sessionInt)
+	if err != nil {
+		return nil, err
+	}
+	if since.IsZero() {
+		since = prev.GeneratedAt
+	}
+	return RunDelta(ctx, &cfg, prev, since, params.BasePrice)
+}
+
+// RunDelta 拉取 prev 对应标的的最新行情与 since 之后发布的新闻并运行增量分析；
+// basePrice 为 0 时以基准计划的入场价代替
+func RunDelta(ctx context.Context, cfg *config.Config, prev *report.Report, since time.Time, basePrice float64) (*models.DeltaAnalysis, error) {
+	quoteCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	quotes, err := FetchQuotes(quoteCtx, cfg, []string{prev.Symbol})
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("fetch quote: %w", err)
+	}
+	if len(quotes.Quotes) == 0 {
+		return nil, fmt.Errorf("no quote for %s", prev.Symbol)
+	}
+
+	days := 1
+	if !since.IsZero() {
+		days = int(time.Since(since).Hours()/24) + 1
+	}
+	resp, err := FetchNews(cfg, models.NewsListParams{Symbol: prev.Symbol, Source: "rss", Days: days})
+	if err != nil {
+		return nil, fmt.Errorf("fetch news: %w", err)
+	}
+	var news []models.NewsItem
+	for _, item := range resp.Items {
+		if item.PublishedAt.After(since) {
+			news = append(news, item)
+		}
+	}
+
+	if basePrice <= 0 && prev.Decision != nil {
+		basePrice = prev.Decision.EntryPrice
+	}
+	return delta.Analyze(ctx, prev, delta.Input{Quote: quotes.Quotes[0], BasePrice: basePrice, News: news})
+}
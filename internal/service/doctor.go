@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/mockllm"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+	"github.com/dyike/CortexGo/pkg/persist"
+	"github.com/dyike/CortexGo/pkg/secure"
+)
+
+const (
+	doctorDefaultTimeout = 10 * time.Second
+	googleNewsRSSProbe   = "https://news.google.com/rss?hl=en-US&gl=US&ceid=US:en"
+	redditProbe          = "https://www.reddit.com/r/stocks/hot.json?limit=1"
+	// clockSkewWarn 超过后签名请求（对象存储、Longport）可能被拒绝
+	clockSkewWarn = 30 * time.Second
+	clockSkewFail = 5 * time.Minute
+)
+
+// doctorCheck 一项诊断；返回状态、说明与修复建议
+type doctorCheck struct {
+	name string
+	run  func(ctx context.Context, cfg *config.Config) (status, detail, fix string)
+}
+
+var doctorChecks = []doctorCheck{
+	{"config", checkConfig},
+	{"directories", checkDirectories},
+	{"sqlite", checkSQLite},
+	{"storage", checkStorage},
+	{"encryption", checkEncryption},
+	{"llm", checkLLM},
+	{"longport", checkLongport},
+	{"reddit", checkReddit},
+	{"google_news", checkGoogleNews},
+	{"transcripts", checkTranscripts},
+	{"clock", checkClock},
+}
+
+// RunDoctor 实际探测各数据源与本地环境，给出可执行的修复建议
+func RunDoctor(paramsJson string) (any, error) {
+	var params models.DoctorParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	cfg := config.Get()
+	return Diagnose(context.Background(), &cfg, params)
+}
+
+// Diagnose 并发运行诊断项，结果按固定顺序返回
+func Diagnose(ctx context.Context, cfg *config.Config, params models.DoctorParams) (*models.DoctorResponse, error) {
+	checks := doctorChecks
+	if len(params.Checks) > 0 {
+		checks = nil
+		for _, name := range params.Checks {
+			check, ok := lookupDoctorCheck(name)
+			if !ok {
+				return nil, rpc.InvalidParams("unknown check %q", name)
+			}
+			checks = append(checks, check)
+		}
+	}
+	timeout := doctorDefaultTimeout
+	if params.TimeoutSec > 0 {
+		timeout = time.Duration(params.TimeoutSec) * time.Second
+	}
+
+	resp := &models.DoctorResponse{OK: true, Checks: make([]models.DoctorCheck, len(checks))}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check doctorCheck) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			start := time.Now()
+			status, detail, fix := check.run(checkCtx, cfg)
+			resp.Checks[i] = models.DoctorCheck{
+				Name:      check.name,
+				Status:    status,
+				Detail:    detail,
+				Fix:       fix,
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	for _, c := range resp.Checks {
+		if c.Status == models.DoctorFail {
+			resp.OK = false
+		}
+	}
+	return resp, nil
+}
+
+func lookupDoctorCheck(name string) (doctorCheck, bool) {
+	for _, c := range doctorChecks {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return doctorCheck{}, false
+}
+
+func checkConfig(_ context.Context, cfg *config.Config) (string, string, string) {
+	if err := cfg.Validate(); err != nil {
+		return models.DoctorFail, err.Error(), "fix the field in config.json or the matching environment variable"
+	}
+	return models.DoctorOK, "config is valid", ""
+}
+
+func checkDirectories(_ context.Context, cfg *config.Config) (string, string, string) {
+	dirs := map[string]string{"data_dir": cfg.DataDir, "results_dir": cfg.ResultsDir, "data_cache_dir": cfg.DataCacheDir}
+	var problems []string
+	for name, dir := range dirs {
+		if strings.TrimSpace(dir) == "" {
+			problems = append(problems, name+" is empty")
+			continue
+		}
+		if err := probeWritable(dir); err != nil {
+			problems = append(problems, fmt.Sprintf("%s (%s): %v", name, dir, err))
+		}
+	}
+	if len(problems) > 0 {
+		return models.DoctorFail, strings.Join(problems, "; "), "create the directories or point the config at a writable location (check ownership and permissions)"
+	}
+	return models.DoctorOK, "data, results and cache directories are writable", ""
+}
+
+func probeWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name)
+}
+
+// checkStorage 往 storage_backend 选定的存储写入、读回并删除一个探测对象
+func checkStorage(ctx context.Context, cfg *config.Config) (string, string, string) {
+	backend := cfg.StorageBackend
+	if backend == "" {
+		backend = config.StorageLocal
+	}
+	s, err := persist.For(cfg)
+	if err != nil {
+		return models.DoctorFail, err.Error(), "set storage_backend to local, sqlite or s3 (s3 needs the objstore_* settings)"
+	}
+	const key = "doctor/probe"
+	if err := s.Put(ctx, persist.Cache, key, []byte("ok")); err != nil {
+		return models.DoctorFail, fmt.Sprintf("%s: %v", backend, err), "check the storage location is writable (for s3, the bucket, keys and clock)"
+	}
+	defer s.Delete(context.WithoutCancel(ctx), persist.Cache, key)
+	if data, _, err := s.Get(ctx, persist.Cache, key); err != nil || string(data) != "ok" {
+		return models.DoctorFail, fmt.Sprintf("%s: written object could not be read back (%v)", backend, err), "check the storage location is readable"
+	}
+	return models.DoctorOK, backend + " storage is readable and writable", ""
+}
+
+func checkSQLite(ctx context.Context, cfg *config.Config) (string, string, string) {
+	if strings.TrimSpace(cfg.DataDir) == "" {
+		return models.DoctorFail, storage.ErrDataDirNotConfigured.Error(), "set data_dir"
+	}
+	dbPath := filepath.Join(cfg.DataDir, "agent.db")
+	store, err := storage.NewStore(dbPath)
+	if err != nil {
+		return models.DoctorFail, err.Error(), "check that " + dbPath + " is not locked by another process or corrupted; move it aside to start fresh"
+	}
+	defer store.Close()
+	stats, err := store.ResultsStats(ctx)
+	if err != nil {
+		return models.DoctorWarn, err.Error(), "the database opened but could not be queried; consider results.archive and recreating it"
+	}
+	return models.DoctorOK, fmt.Sprintf("%s (%d runs)", dbPath, stats.TotalRuns), ""
+}
+
+func checkEncryption(_ context.Context, cfg *config.Config) (string, string, string) {
+	source := secure.KeySource(cfg)
+	if source == "" {
+		return models.DoctorSkip, "encryption at rest is disabled", ""
+	}
+	if _, err := secure.ForConfig(cfg); err != nil {
+		return models.DoctorFail, err.Error(), "provide a 32 byte key encoded as base64 or hex (openssl rand -base64 32)"
+	}
+	return models.DoctorOK, "key loaded from " + source, ""
+}
+
+func checkLLM(ctx context.Context, cfg *config.Config) (string, string, string) {
+	if cfg.MockLLM() {
+		if cfg.MockLLMScript != "" {
+			if _, err := mockllm.LoadScript(cfg.MockLLMScript); err != nil {
+				return models.DoctorFail, err.Error(), "fix mock_llm_script or clear it to use the built-in responses"
+			}
+			return models.DoctorWarn, "mock provider playing " + cfg.MockLLMScript, "set llm_provider to deepseek for real analysis"
+		}
+		return models.DoctorWarn, "mock provider: agents return canned responses", "set llm_provider to deepseek for real analysis"
+	}
+	if cfg.DeepSeekAPIKey == "" {
+		return models.DoctorFail, "deepseek_api_key is not set", "set DEEPSEEK_API_KEY in .env or deepseek_api_key in config.json"
+	}
+	status, _, err := probeHTTP(ctx, agents.DeepSeekBaseURL+"/models", map[string]string{"Authorization": "Bearer " + cfg.DeepSeekAPIKey})
+	switch {
+	case err != nil:
+		return models.DoctorFail, err.Error(), "check network access to api.deepseek.com (proxy, firewall, DNS)"
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return models.DoctorFail, fmt.Sprintf("HTTP %d: API key rejected", status), "regenerate the key at platform.deepseek.com and update DEEPSEEK_API_KEY"
+	case status == http.StatusPaymentRequired:
+		return models.DoctorFail, "HTTP 402: insufficient balance", "top up the DeepSeek account"
+	case status/100 != 2:
+		return models.DoctorWarn, fmt.Sprintf("HTTP %d", status), "the API is reachable but returned an unexpected status; retry later"
+	}
+	return models.DoctorOK, "API key accepted", ""
+}
+
+func checkLongport(ctx context.Context, cfg *config.Config) (string, string, string) {
+	accounts := cfg.LongportAccountList()
+	if len(accounts) == 0 {
+		return models.DoctorWarn, "credentials not configured, market data falls back to mock data", "set LONGPORT_APP_KEY, LONGPORT_APP_SECRET and LONGPORT_ACCESS_TOKEN, or longport_accounts"
+	}
+	// 逐个账户检查，避免故障切换掩盖失效的账户
+	var failed []string
+	for _, a := range accounts {
+		client, err := dataflows.NewLongportClient(dataflows.LongportConfig{Accounts: []config.LongportAccount{a}})
+		if err == nil {
+			_, err = client.GetStaticInfo(ctx, []string{probeSymbol(a)})
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", a.Name, err))
+		}
+	}
+	switch {
+	case len(failed) == len(accounts):
+		return models.DoctorFail, strings.Join(failed, "; "), "the token may lack quote permission or have expired; renew it in the Longport developer console"
+	case len(failed) > 0:
+		return models.DoctorWarn, strings.Join(failed, "; "), "requests fail over to the other accounts; renew the failing token in the Longport developer console"
+	}
+	return models.DoctorOK, fmt.Sprintf("authenticated %d account(s), quote API reachable", len(accounts)), ""
+}
+
+// doctorProbeSymbols 各市场用于探测行情权限的标的
+var doctorProbeSymbols = map[string]string{
+	"US": "AAPL.US",
+	"HK": "700.HK",
+	"SG": "D05.SG",
+	"SH": "600519.SH",
+	"SZ": "000001.SZ",
+}
+
+// probeSymbol 取账户优先覆盖的第一个市场的代表性标的
+func probeSymbol(a config.LongportAccount) string {
+	if markets := a.MarketList(); len(markets) > 0 {
+		if s, ok := doctorProbeSymbols[markets[0]]; ok {
+			return s
+		}
+	}
+	return "AAPL.US"
+}
+
+func checkReddit(ctx context.Context, _ *config.Config) (string, string, string) {
+	status, _, err := probeHTTP(ctx, redditProbe, map[string]string{"User-Agent": "CortexGo/1.0 (by /u/cortexgo)"})
+	switch {
+	case err != nil:
+		return models.DoctorWarn, err.Error(), "check network access to www.reddit.com; social sentiment will be empty"
+	case status == http.StatusTooManyRequests:
+		return models.DoctorWarn, "HTTP 429: rate limited", "wait a few minutes or enable cache_enabled to reduce requests"
+	case status/100 != 2:
+		return models.DoctorWarn, fmt.Sprintf("HTTP %d", status), "Reddit may be blocking this IP; social sentiment will be empty"
+	}
+	return models.DoctorOK, "reachable", ""
+}
+
+func checkGoogleNews(ctx context.Context, _ *config.Config) (string, string, string) {
+	status, _, err := probeHTTP(ctx, googleNewsRSSProbe, nil)
+	switch {
+	case err != nil:
+		return models.DoctorWarn, err.Error(), "check network access to news.google.com; news analysis will be empty"
+	case status/100 != 2:
+		return models.DoctorWarn, fmt.Sprintf("HTTP %d", status), "Google News may be throttling this IP; retry later"
+	}
+	return models.DoctorOK, "RSS feed reachable", ""
+}
+
+// checkTranscripts 查询 AAPL 最近一场财报电话会，验证 Finnhub/FMP 密钥及套餐权限
+func checkTranscripts(_ context.Context, cfg *config.Config) (string, string, string) {
+	client := dataflows.NewTranscriptsClient(cfg)
+	if client.Provider() == "" {
+		return models.DoctorSkip, "no transcript provider configured", "set FINNHUB_API_KEY or FMP_API_KEY to give analysts earnings call Q&A"
+	}
+	if cfg.Offline {
+		return models.DoctorSkip, "offline mode", ""
+	}
+	transcript, err := client.GetLatestTranscript("AAPL.US", "", cfg)
+	if err != nil {
+		return models.DoctorWarn, err.Error(), "check the " + client.Provider() + " API key and that the plan includes earnings call transcripts"
+	}
+	return models.DoctorOK, fmt.Sprintf("%s returned AAPL Q%d %d", client.Provider(), transcript.Quarter, transcript.Year), ""
+}
+
+// checkClock 用 HTTPS 响应的 Date 头估算本机时钟偏差
+func checkClock(ctx context.Context, _ *config.Config) (string, string, string) {
+	start := time.Now()
+	_, serverDate, err := probeHTTP(ctx, googleNewsRSSProbe, nil)
+	if err != nil {
+		return models.DoctorSkip, "no reference time available: " + err.Error(), ""
+	}
+	if serverDate.IsZero() {
+		return models.DoctorSkip, "reference server sent no Date header", ""
+	}
+	// Date 头精度为秒，取请求中点作为本地参考时间
+	local := start.Add(time.Since(start) / 2)
+	skew := local.Sub(serverDate).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	detail := fmt.Sprintf("local clock differs from server by %s", skew)
+	switch {
+	case skew > clockSkewFail:
+		return models.DoctorFail, detail, "enable NTP time sync; signed requests (object storage, Longport) are rejected with this skew"
+	case skew > clockSkewWarn:
+		return models.DoctorWarn, detail, "enable NTP time sync"
+	}
+	return models.DoctorOK, detail, ""
+}
+
+// probeHTTP 发送 GET 请求，返回状态码与服务端 Date 头
+func probeHTTP(ctx context.Context, url string, headers map[string]string) (int, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return 0, time.Time{}, errors.New("timed out")
+		}
+		return 0, time.Time{}, err
+	}
+	defer resp.Body.Close()
+	date, _ := http.ParseTime(resp.Header.Get("Date"))
+	return resp.StatusCode, date, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dyike/CortexGo/internal/memory"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/pdftext"
+)
+
+var documentKinds = []string{
+	models.DocumentKindAnnualReport,
+	models.DocumentKindBrokerReport,
+	models.DocumentKindEarningsSlides,
+	models.DocumentKindFiling,
+	models.DocumentKindOther,
+}
+
+// IngestDocument 导入年报、研报等文档，切块向量化后供 query_documents 检索（documents.ingest）
+func IngestDocument(paramsJson string) (any, error) {
+	var params models.DocumentIngestParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	return IngestDocumentFile(context.Background(), params)
+}
+
+// IngestDocumentFile 同 IngestDocument，供命令行直接调用
+func IngestDocumentFile(ctx context.Context, params models.DocumentIngestParams) (*models.DocumentRecord, error) {
+	path := strings.TrimSpace(params.Path)
+	if path == "" {
+		return nil, rpc.InvalidParams("path is required")
+	}
+	kind := strings.ToLower(strings.TrimSpace(params.Kind))
+	if kind != "" && !slices.Contains(documentKinds, kind) {
+		return nil, rpc.InvalidParams("invalid kind %q: want one of %s", params.Kind, strings.Join(documentKinds, ", "))
+	}
+	if ext := strings.ToLower(filepath.Ext(path)); !slices.Contains(memory.DocumentExtensions, ext) {
+		return nil, rpc.InvalidParams("unsupported document type %q: want %s", ext, strings.Join(memory.DocumentExtensions, ", "))
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	doc, err := memory.IngestFile(ctx, store, memory.DefaultEmbedder, path, models.DocumentRecord{
+		Symbol: strings.ToUpper(strings.TrimSpace(params.Symbol)),
+		Title:  strings.TrimSpace(params.Title),
+		Kind:   kind,
+	})
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, rpc.NotFound("file not found: %s", path)
+	case errors.Is(err, pdftext.ErrEncrypted), errors.Is(err, pdftext.ErrNotPDF):
+		return nil, rpc.InvalidParams("%v", err)
+	case err != nil:
+		return nil, err
+	}
+	return doc, nil
+}
+
+// ListDocuments 列出已导入的文档（documents.list）
+func ListDocuments(paramsJson string) (any, error) {
+	var params models.DocumentListParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	docs, err := store.ListDocuments(context.Background(), strings.TrimSpace(params.Symbol))
+	if err != nil {
+		return nil, err
+	}
+	if docs == nil {
+		docs = []models.DocumentRecord{}
+	}
+	return docs, nil
+}
+
+// DeleteDocument 删除已导入的文档及其切块（documents.del）
+func DeleteDocument(paramsJson string) (any, error) {
+	var params models.DocumentDeleteParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	if params.Id <= 0 {
+		return nil, rpc.InvalidParams("invalid id")
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	if err := store.DeleteDocument(context.Background(), params.Id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, rpc.NotFound("document not found: %d", params.Id)
+		}
+		return nil, err
+	}
+	return models.DocumentDeleteResponse{Id: params.Id, Deleted: true}, nil
+}
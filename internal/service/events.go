@@ -0,0 +1,67 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/bridge"
+)
+
+// 订阅过滤只作用于 RegisterCallback 注册的全局回调；CortexGoAnalyzeAsync 等按次回调不受影响
+
+// ListEventTopics 返回全部 topic 及其分类与详细程度（events.topics）
+func ListEventTopics(string) (any, error) {
+	return bridge.Topics(), nil
+}
+
+// SubscribeEvents 全局回调只接收匹配的事件（events.subscribe）
+func SubscribeEvents(paramsJson string) (any, error) {
+	return updateSubscriptions(paramsJson, bridge.Subscribe)
+}
+
+// UnsubscribeEvents 全局回调不再接收匹配的事件（events.unsubscribe）
+func UnsubscribeEvents(paramsJson string) (any, error) {
+	return updateSubscriptions(paramsJson, bridge.Unsubscribe)
+}
+
+func updateSubscriptions(paramsJson string, apply func(...string) error) (any, error) {
+	var params models.EventSubscribeParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	var topics []string
+	for _, t := range params.Topics {
+		if t = strings.TrimSpace(t); t != "" {
+			topics = append(topics, t)
+		}
+	}
+	if len(topics) == 0 {
+		return nil, rpc.InvalidParams("topics is required")
+	}
+	if err := apply(topics...); err != nil {
+		return nil, rpc.InvalidParams("%v", err)
+	}
+	return bridge.Subscriptions(), nil
+}
+
+// SetEventVerbosity 丢弃比 level 更详细的事件（events.verbosity）
+func SetEventVerbosity(paramsJson string) (any, error) {
+	var params models.EventVerbosityParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	level, ok := bridge.ParseLevel(strings.ToLower(strings.TrimSpace(params.Level)))
+	if !ok {
+		return nil, rpc.InvalidParams("invalid level %q: want error, info or debug", params.Level)
+	}
+	bridge.SetVerbosity(level)
+	return bridge.Subscriptions(), nil
+}
+
+// ResetEvents 恢复接收全部事件（events.reset）
+func ResetEvents(string) (any, error) {
+	bridge.ResetSubscriptions()
+	return bridge.Subscriptions(), nil
+}
@@ -10,6 +10,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/memory"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rpc"
 	"github.com/dyike/CortexGo/internal/storage"
 	"github.com/dyike/CortexGo/models"
 )
@@ -19,7 +23,7 @@ func GetAgentHistory(paramsJson string) (any, error) {
 	var params models.HistoryParams
 	if strings.TrimSpace(paramsJson) != "" {
 		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
-			return nil, fmt.Errorf("invalid params: %w", err)
+			return nil, rpc.InvalidParams("invalid params: %v", err)
 		}
 	}
 
@@ -35,7 +39,7 @@ func GetAgentHistory(paramsJson string) (any, error) {
 	if strings.TrimSpace(params.Cursor) != "" {
 		val, err := strconv.ParseInt(params.Cursor, 10, 64)
 		if err != nil || val < 0 {
-			return nil, fmt.Errorf("invalid cursor")
+			return nil, rpc.InvalidParams("invalid cursor")
 		}
 		cursor = val
 	}
@@ -54,7 +58,30 @@ func GetAgentHistory(paramsJson string) (any, error) {
 		tradeDate = ""
 	}
 
-	sessions, err := store.ListSessions(ctx, cursor, limit, symbol, tradeDate, query)
+	recommendation := strings.ToUpper(strings.TrimSpace(params.Recommendation))
+	if recommendation != "" && recommendation != "BUY" && recommendation != "HOLD" && recommendation != "SELL" {
+		return nil, rpc.InvalidParams("invalid recommendation: %s", params.Recommendation)
+	}
+	since := strings.TrimSpace(params.Since)
+	if since != "" {
+		if _, err := time.Parse("2006-01-02", since); err != nil {
+			return nil, rpc.InvalidParams("invalid since: %v", err)
+		}
+	}
+	if params.MinConfidence < 0 || params.MinConfidence > 1 {
+		return nil, rpc.InvalidParams("min_confidence must be between 0 and 1")
+	}
+
+	sessions, err := store.ListSessions(ctx, models.SessionFilter{
+		Cursor:         cursor,
+		Limit:          limit,
+		Symbol:         symbol,
+		TradeDate:      tradeDate,
+		Query:          query,
+		Recommendation: recommendation,
+		Since:          since,
+		MinConfidence:  params.MinConfidence,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +96,9 @@ func GetAgentHistory(paramsJson string) (any, error) {
 			Status:    s.Status,
 			CreatedAt: formatTime(s.CreatedAt),
 			UpdatedAt: formatTime(s.UpdatedAt),
+
+			Recommendation: s.Recommendation,
+			Confidence:     s.Confidence,
 		})
 	}
 
@@ -88,33 +118,36 @@ func GetAgentHistory(paramsJson string) (any, error) {
 func GetHistoryInfo(paramsJson string) (any, error) {
 	var params models.HistoryInfoParams
 	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
-		return nil, fmt.Errorf("invalid params: %w", err)
+		return nil, rpc.InvalidParams("invalid params: %v", err)
 	}
 
 	sessionID := strings.TrimSpace(params.SessionID)
 	if sessionID == "" {
-		return nil, errors.New("session_id is required")
+		return nil, rpc.InvalidParams("session_id is required")
 	}
 	sessionInt, err := strconv.ParseInt(sessionID, 10, 64)
 	if err != nil || sessionInt <= 0 {
-		return nil, fmt.Errorf("invalid session_id")
+		return nil, rpc.InvalidParams("invalid session_id")
 	}
+	return LoadHistoryInfo(context.Background(), sessionInt)
+}
 
+// LoadHistoryInfo 同 GetHistoryInfo，供命令行直接调用（如 replay 回放）
+func LoadHistoryInfo(ctx context.Context, sessionID int64) (*models.HistoryInfoResponse, error) {
 	store, err := storage.GetSQLiteStore()
 	if err != nil {
 		return nil, fmt.Errorf("open sqlite: %w", err)
 	}
 
-	ctx := context.Background()
-	sessionRec, err := store.GetSession(ctx, sessionInt)
+	sessionRec, err := store.GetSession(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
 	if sessionRec == nil {
-		return nil, fmt.Errorf("session not found: %s", sessionID)
+		return nil, rpc.NotFound("session not found: %d", sessionID)
 	}
 
-	msgRecs, err := store.ListMessages(ctx, sessionInt)
+	msgRecs, err := store.ListMessages(ctx, sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +170,7 @@ func GetHistoryInfo(paramsJson string) (any, error) {
 		})
 	}
 
-	return models.HistoryInfoResponse{
+	return &models.HistoryInfoResponse{
 		Session: models.HistorySession{
 			SessionID: strconv.FormatInt(sessionRec.Id, 10),
 			Symbol:    sessionRec.Symbol,
@@ -155,16 +188,20 @@ func GetHistoryInfo(paramsJson string) (any, error) {
 func DeleteHistory(paramsJson string) (any, error) {
 	var params models.HistoryDeleteParams
 	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
-		return nil, fmt.Errorf("invalid params: %w", err)
+		return nil, rpc.InvalidParams("invalid params: %v", err)
 	}
 
 	sessionID := strings.TrimSpace(params.SessionID)
 	if sessionID == "" {
-		return nil, errors.New("session_id is required")
+		return nil, rpc.InvalidParams("session_id is required")
 	}
 	sessionInt, err := strconv.ParseInt(sessionID, 10, 64)
 	if err != nil || sessionInt <= 0 {
-		return nil, fmt.Errorf("invalid session_id")
+		return nil, rpc.InvalidParams("invalid session_id")
+	}
+
+	if _, running := GetRun(sessionID); running {
+		return nil, rpc.Conflict("session %s is still running; cancel it first", sessionID)
 	}
 
 	store, err := storage.GetSQLiteStore()
@@ -175,10 +212,18 @@ func DeleteHistory(paramsJson string) (any, error) {
 	ctx := context.Background()
 	if err := store.DeleteSession(ctx, sessionInt); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("session not found: %s", sessionID)
+			return nil, rpc.NotFound("session not found: %s", sessionID)
 		}
 		return nil, err
 	}
+	// 同时删除存储中的会话文档与检索文档，失败只记录
+	cfg := config.Get()
+	if err := report.DeleteSession(ctx, &cfg, sessionInt); err != nil {
+		fmt.Printf("delete session document session=%d err=%v\n", sessionInt, err)
+	}
+	if err := memory.Forget(ctx, &cfg, sessionInt); err != nil {
+		fmt.Printf("delete memory documents session=%d err=%v\n", sessionInt, err)
+	}
 
 	return models.HistoryDeleteResponse{
 		SessionID: sessionID,
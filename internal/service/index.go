@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/regime"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// ListIndexConstituents 指数成分股列表（index.constituents）
+func ListIndexConstituents(paramsJson string) (any, error) {
+	var params models.IndexConstituentsParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	cfg := config.Get()
+	return LoadIndexConstituents(&cfg, params)
+}
+
+// LoadIndexConstituents 同 ListIndexConstituents，供命令行直接调用；按行业过滤后没有成分股时返回 not_found
+func LoadIndexConstituents(cfg *config.Config, params models.IndexConstituentsParams) (*models.IndexConstituents, error) {
+	if _, err := dataflows.ResolveIndex(params.Index); err != nil {
+		return nil, rpc.InvalidParams("%v", err)
+	}
+	c, err := dataflows.NewConstituentsClient(cfg).GetConstituents(params.Index)
+	if err != nil {
+		return nil, err
+	}
+	sector := strings.ToLower(strings.TrimSpace(params.Sector))
+	if sector == "" {
+		return c, nil
+	}
+	filtered := *c
+	filtered.Members = nil
+	for _, m := range c.Members {
+		if strings.Contains(strings.ToLower(m.Sector), sector) {
+			filtered.Members = append(filtered.Members, m)
+		}
+	}
+	if len(filtered.Members) == 0 {
+		return nil, rpc.NotFound("no %s constituents in sector %q", c.Index, params.Sector)
+	}
+	return &filtered, nil
+}
+
+// GetIndexBreadth 指数成分股的市场广度（index.breadth）
+func GetIndexBreadth(paramsJson string) (any, error) {
+	var params models.IndexBreadthParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	cfg := config.Get()
+	// 成分股多达 500 只，首次拉取行情较慢
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	return ComputeIndexBreadth(ctx, &cfg, params)
+}
+
+// ComputeIndexBreadth 同 GetIndexBreadth，供命令行直接调用
+func ComputeIndexBreadth(ctx context.Context, cfg *config.Config, params models.IndexBreadthParams) (*models.IndexBreadth, error) {
+	c, err := LoadIndexConstituents(cfg, models.IndexConstituentsParams{Index: params.Index, Sector: params.Sector})
+	if err != nil {
+		return nil, err
+	}
+	return regime.Breadth(ctx, func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+		return tools.FetchMarketData(ctx, cfg, symbol, count)
+	}, c.Index, c.Members, strings.TrimSpace(params.TradeDate))
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+	"github.com/dyike/CortexGo/pkg/parquet"
+)
+
+const (
+	indicatorsDefaultLookback = 60
+	indicatorsMaxLookback     = 750
+)
+
+// GetMarketIndicators 单独运行指标引擎，便于核对计算结果或导入表格
+func GetMarketIndicators(paramsJson string) (any, error) {
+	var params models.MarketIndicatorsParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	cfg := config.Get()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	return ComputeIndicators(ctx, &cfg, params)
+}
+
+// ComputeIndicators 拉取日K线（含 200 日均线预热），输出最近 lookback 个交易日的指标表
+func ComputeIndicators(ctx context.Context, cfg *config.Config, params models.MarketIndicatorsParams) (*models.MarketIndicatorsResponse, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(params.Symbol))
+	if symbol == "" {
+		return nil, rpc.InvalidParams("symbol is required")
+	}
+	lookback := params.Lookback
+	if lookback <= 0 {
+		lookback = indicatorsDefaultLookback
+	}
+	if lookback > indicatorsMaxLookback {
+		return nil, rpc.InvalidParams("lookback must be at most %d", indicatorsMaxLookback)
+	}
+	columns, err := indicatorColumns(params.Indicators)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	if strings.TrimSpace(params.EndDate) != "" {
+		if end, err = time.Parse("2006-01-02", params.EndDate); err != nil {
+			return nil, rpc.InvalidParams("invalid end_date: %v", err)
+		}
+	}
+	// Longport 按条数返回截至今天的K线，end_date 之后的交易日也需要计入
+	count := lookback + chartWarmupBars + int(time.Since(end).Hours()/24)
+	data, err := tools.FetchMarketData(ctx, cfg, symbol, min(count, 1000))
+	if err != nil {
+		return nil, fmt.Errorf("fetch market data: %w", err)
+	}
+
+	resp, err := IndicatorTable(symbol, data, end.Format("2006-01-02"), lookback, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if out := strings.TrimSpace(params.Output); out != "" {
+		if err := writeIndicatorsFile(out, resp); err != nil {
+			return nil, err
+		}
+		resp.Path = out
+	}
+	return resp, nil
+}
+
+// IndicatorTable 用调用方提供的日K线计算指标表：只取 endDate（为空时不限）及之前的K线，
+// 输出最近 lookback 个交易日；indicators 为空时输出全部指标。
+// wasm 等无法直连行情的环境由宿主传入K线
+func IndicatorTable(symbol string, data []*models.MarketData, endDate string, lookback int, indicators []string) (*models.MarketIndicatorsResponse, error) {
+	columns, err := indicatorColumns(indicators)
+	if err != nil {
+		return nil, err
+	}
+	if lookback <= 0 {
+		lookback = indicatorsDefaultLookback
+	}
+	var bars []*models.MarketData
+	for _, d := range data {
+		if d != nil && (endDate == "" || d.Date <= endDate) {
+			bars = append(bars, d)
+		}
+	}
+	sort.Slice(bars, func(i, j int) bool { return bars[i].Date < bars[j].Date })
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no market data for %s up to %s", symbol, endDate)
+	}
+	window := bars[max(0, len(bars)-lookback):]
+	start, _ := time.Parse("2006-01-02", window[0].Date)
+	last, _ := time.Parse("2006-01-02", window[len(window)-1].Date)
+
+	resp := &models.MarketIndicatorsResponse{Symbol: symbol, Columns: columns, Rows: make([]models.IndicatorRow, len(window))}
+	index := make(map[string]int, len(window))
+	for i, bar := range window {
+		resp.Rows[i] = models.IndicatorRow{Date: bar.Date, Close: bar.Close, Values: map[string]float64{}}
+		index[bar.Date] = i
+	}
+	for name, values := range dataflows.CalculateAllIndicators(bars, start, last) {
+		if !slices.Contains(columns, name) {
+			continue
+		}
+		for _, v := range values {
+			if i, ok := index[v.Date]; ok {
+				resp.Rows[i].Values[name] = v.Value
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+func indicatorColumns(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return dataflows.IndicatorNames, nil
+	}
+	var columns []string
+	for _, name := range requested {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(dataflows.IndicatorNames, name) {
+			return nil, rpc.InvalidParams("unknown indicator %q (supported: %s)", name, strings.Join(dataflows.IndicatorNames, ", "))
+		}
+		if !slices.Contains(columns, name) {
+			columns = append(columns, name)
+		}
+	}
+	return columns, nil
+}
+
+// WriteIndicatorsCSV 输出 date,close,<指标...>；缺失值留空
+func WriteIndicatorsCSV(w io.Writer, resp *models.MarketIndicatorsResponse) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(append([]string{"date", "close"}, resp.Columns...))
+	for _, row := range resp.Rows {
+		record := []string{row.Date, strconv.FormatFloat(row.Close, 'f', -1, 64)}
+		for _, col := range resp.Columns {
+			if v, ok := row.Values[col]; ok {
+				record = append(record, strconv.FormatFloat(v, 'f', 4, 64))
+			} else {
+				record = append(record, "")
+			}
+		}
+		_ = cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeIndicatorsParquet 列同 CSV；parquet 列不可为空，缺失值写为 NaN
+func writeIndicatorsParquet(w io.Writer, resp *models.MarketIndicatorsResponse) error {
+	columns := []parquet.Column{{Name: "date", Type: parquet.String}, {Name: "close", Type: parquet.Double}}
+	for _, col := range resp.Columns {
+		columns = append(columns, parquet.Column{Name: col, Type: parquet.Double})
+	}
+	rows := make([][]any, len(resp.Rows))
+	for i, row := range resp.Rows {
+		record := []any{row.Date, row.Close}
+		for _, col := range resp.Columns {
+			v, ok := row.Values[col]
+			if !ok {
+				v = math.NaN()
+			}
+			record = append(record, v)
+		}
+		rows[i] = record
+	}
+	return parquet.Write(w, columns, rows)
+}
+
+func writeIndicatorsFile(path string, resp *models.MarketIndicatorsResponse) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create output: %w", err)
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return WriteIndicatorsCSV(f, resp)
+	case ".parquet":
+		return writeIndicatorsParquet(f, resp)
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(resp)
+}
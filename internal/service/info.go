@@ -1,8 +1,74 @@
 package service
 
+import (
+	"context"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/buildinfo"
+	"github.com/dyike/CortexGo/internal/graph"
+	"github.com/dyike/CortexGo/internal/mockllm"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/bridge"
+	"github.com/dyike/CortexGo/pkg/secure"
+)
+
+// healthChecks CortexGoHealth 运行的本地检查项，不访问网络
+var healthChecks = []string{"config", "directories", "sqlite", "encryption"}
+
 func GetSystemInfo() any {
 	return map[string]any{
-		"version": "1.0.0",
+		"version": buildinfo.Version,
 		"os":      "android/ios",
 	}
 }
+
+// GetVersion 返回版本与构建信息（system.version）
+func GetVersion() models.SystemVersion {
+	return buildinfo.Get()
+}
+
+// GetCapabilities 按当前配置列出可用的数据源、工具、可选功能、方法与事件（system.capabilities）
+func GetCapabilities() models.SystemCapabilities {
+	cfg := config.Get()
+	caps := models.SystemCapabilities{
+		Version: buildinfo.Version,
+		LLM:     models.CapabilityFeature{Name: "deepseek", Enabled: cfg.DeepSeekAPIKey != ""},
+		Sources: graph.DataSources(context.Background(), &cfg),
+		Depths:  []string{config.DepthQuick, config.DepthStandard, config.DepthDeep},
+
+		RiskProfiles: config.RiskProfiles(),
+	}
+	if cfg.MockLLM() {
+		caps.LLM = models.CapabilityFeature{Name: mockllm.Name, Enabled: true, Detail: "canned responses, no API calls"}
+	} else if !caps.LLM.Enabled {
+		caps.LLM.Detail = "deepseek_api_key is not set"
+	}
+	for _, s := range caps.Sources {
+		caps.Tools = append(caps.Tools, s.Tools...)
+	}
+	keySource := secure.KeySource(&cfg)
+	caps.Features = []models.CapabilityFeature{
+		{Name: "cache", Enabled: cfg.CacheEnabled},
+		{Name: "offline", Enabled: cfg.Offline},
+		{Name: "simulated_data", Enabled: cfg.SimulatedData()},
+		{Name: "email", Enabled: cfg.SMTPHost != "" && cfg.SMTPFrom != "" && len(cfg.EmailRecipients) > 0},
+		{Name: "webhook", Enabled: len(cfg.WebhookURLs) > 0},
+		{Name: "objstore", Enabled: cfg.ObjstoreEnabled()},
+		{Name: "encryption", Enabled: keySource != "", Detail: keySource},
+		{Name: "eino_debug", Enabled: cfg.EinoDebugEnabled},
+	}
+	for _, t := range bridge.Topics() {
+		caps.Events = append(caps.Events, t.Name)
+	}
+	for _, m := range rpc.Methods() {
+		caps.Methods = append(caps.Methods, m.Name)
+	}
+	return caps
+}
+
+// CheckHealth 只运行本地检查（配置、目录、数据库、加密密钥），用于启动时快速自检（system.health）
+func CheckHealth() (*models.DoctorResponse, error) {
+	cfg := config.Get()
+	return Diagnose(context.Background(), &cfg, models.DoctorParams{Checks: healthChecks})
+}
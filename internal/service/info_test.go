@@ -0,0 +1,26 @@
+package service
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCapabilitiesReportMethodsAndSources(t *testing.T) {
+	caps := GetCapabilities()
+	for _, m := range []string{"system.version", "system.capabilities", "system.health", "system.methods", "listMethods", "agent.stream"} {
+		if !slices.Contains(caps.Methods, m) {
+			t.Errorf("methods missing %s", m)
+		}
+	}
+	if len(caps.Sources) != 9 {
+		t.Fatalf("sources = %+v", caps.Sources)
+	}
+	for _, s := range caps.Sources {
+		if len(s.Tools) == 0 {
+			t.Errorf("source %s lists no tools", s.Name)
+		}
+	}
+	if !slices.Contains(caps.Events, "analysis.decision") || !slices.Contains(caps.Events, "agent.finished") {
+		t.Errorf("events = %v", caps.Events)
+	}
+}
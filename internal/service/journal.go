@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/internal/journal"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+// AddJournal 记录一笔实际执行的交易（journal.add）
+func AddJournal(paramsJson string) (any, error) {
+	var params models.JournalAddParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	return CreateJournalEntry(context.Background(), params)
+}
+
+// CreateJournalEntry 同 AddJournal，供命令行直接调用；关联会话时按该次分析补全标的与方向
+func CreateJournalEntry(ctx context.Context, params models.JournalAddParams) (*models.JournalEntry, error) {
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	var (
+		session        *models.SessionRecord
+		recommendation string
+	)
+	if id := strings.TrimSpace(params.SessionID); id != "" {
+		sessionID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || sessionID <= 0 {
+			return nil, rpc.InvalidParams("invalid session_id")
+		}
+		if session, err = store.GetSession(ctx, sessionID); err != nil {
+			return nil, err
+		}
+		if session == nil {
+			return nil, rpc.NotFound("session not found: %d", sessionID)
+		}
+		rec, err := store.GetReport(ctx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil {
+			recommendation = rec.Recommendation
+		}
+	} else if strings.TrimSpace(params.Symbol) == "" {
+		return nil, rpc.InvalidParams("symbol or session_id is required")
+	}
+
+	e, err := journal.New(params, session, recommendation, time.Now().Format("2006-01-02"))
+	if err != nil {
+		return nil, rpc.InvalidParams("%v", err)
+	}
+	if err := store.CreateJournalEntry(ctx, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// CloseJournal 记录平仓并计算已实现盈亏（journal.close）
+func CloseJournal(paramsJson string) (any, error) {
+	var params models.JournalCloseParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	return CloseJournalEntry(context.Background(), params)
+}
+
+// CloseJournalEntry 同 CloseJournal，供命令行直接调用
+func CloseJournalEntry(ctx context.Context, params models.JournalCloseParams) (*models.JournalEntry, error) {
+	if params.Id <= 0 {
+		return nil, rpc.InvalidParams("invalid id")
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	e, err := store.GetJournalEntry(ctx, params.Id)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, rpc.NotFound("journal entry not found: %d", params.Id)
+	}
+	if err := journal.Close(e, params, time.Now().Format("2006-01-02")); err != nil {
+		return nil, rpc.InvalidParams("%v", err)
+	}
+	if err := store.UpdateJournalExit(ctx, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ListJournal 列出交易日志（journal.list）
+func ListJournal(paramsJson string) (any, error) {
+	var params models.JournalListParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	return LoadJournal(context.Background(), params)
+}
+
+// LoadJournal 同 ListJournal，供命令行直接调用
+func LoadJournal(ctx context.Context, params models.JournalListParams) ([]models.JournalEntry, error) {
+	var sessionID int64
+	if id := strings.TrimSpace(params.SessionID); id != "" {
+		var err error
+		if sessionID, err = strconv.ParseInt(id, 10, 64); err != nil || sessionID <= 0 {
+			return nil, rpc.InvalidParams("invalid session_id")
+		}
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	items, err := store.ListJournalEntries(ctx, strings.ToUpper(strings.TrimSpace(params.Symbol)), sessionID, params.Open)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []models.JournalEntry{}
+	}
+	return items, nil
+}
+
+// DeleteJournal 删除交易日志（journal.del）
+func DeleteJournal(paramsJson string) (any, error) {
+	var params models.JournalDeleteParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	if err := RemoveJournalEntry(context.Background(), params.Id); err != nil {
+		return nil, err
+	}
+	return map[string]any{"id": params.Id, "deleted": true}, nil
+}
+
+// RemoveJournalEntry 同 DeleteJournal，供命令行直接调用
+func RemoveJournalEntry(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return rpc.InvalidParams("invalid id")
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return fmt.Errorf("open sqlite: %w", err)
+	}
+	if err := store.DeleteJournalEntry(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return rpc.NotFound("journal entry not found: %d", id)
+		}
+		return err
+	}
+	return nil
+}
+
+// LoadJournalStats 汇总交易日志的胜率与已实现盈亏，供命令行直接调用（RPC 中包含在 results.stats 内）
+func LoadJournalStats(ctx context.Context) (*models.JournalStats, error) {
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	return store.JournalStats(ctx)
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/chart"
+)
+
+// chartWarmupBars 额外拉取的历史K线，保证 SMA200 等指标在图表起点可用
+const chartWarmupBars = 220
+
+// GetMarketChart 生成 K 线 + 均线/布林带/RSI 图表，输出 svg 或 png 文件
+func GetMarketChart(paramsJson string) (any, error) {
+	var params models.MarketChartParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	params.Symbol = strings.TrimSpace(params.Symbol)
+	if params.Symbol == "" {
+		return nil, rpc.InvalidParams("symbol is required")
+	}
+
+	end := time.Now()
+	if strings.TrimSpace(params.EndDate) != "" {
+		t, err := time.Parse("2006-01-02", params.EndDate)
+		if err != nil {
+			return nil, rpc.InvalidParams("invalid end_date: %v", err)
+		}
+		end = t
+	}
+	start := end.AddDate(0, 0, -120)
+	if strings.TrimSpace(params.StartDate) != "" {
+		t, err := time.Parse("2006-01-02", params.StartDate)
+		if err != nil {
+			return nil, rpc.InvalidParams("invalid start_date: %v", err)
+		}
+		start = t
+	}
+	if !start.Before(end) {
+		return nil, rpc.InvalidParams("start_date must be before end_date")
+	}
+
+	format := strings.ToLower(strings.TrimSpace(params.Format))
+	if format == "" {
+		format = "svg"
+	}
+	if format != "svg" && format != "png" {
+		return nil, rpc.InvalidParams("unsupported format %q (supported: svg, png)", format)
+	}
+
+	cfg := config.Get()
+	c, err := buildChart(context.Background(), &cfg, params.Symbol, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := chart.Options{Width: params.Width, Height: params.Height}
+	var data []byte
+	if format == "png" {
+		if data, err = c.PNG(opts); err != nil {
+			return nil, fmt.Errorf("render png: %w", err)
+		}
+	} else {
+		data = []byte(c.SVG(opts))
+	}
+
+	outPath := strings.TrimSpace(params.Output)
+	if outPath == "" {
+		name := fmt.Sprintf("chart_%s_%s.%s", start.Format("20060102"), end.Format("20060102"), format)
+		outPath = filepath.Join(cfg.ResultsDir, params.Symbol, "charts", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return nil, fmt.Errorf("create output dir: %w", err)
+	}
+	if err := os.WriteFile(outPath, data, 0o644); err != nil {
+		return nil, fmt.Errorf("write chart: %w", err)
+	}
+
+	return models.MarketChartResponse{
+		Symbol:  params.Symbol,
+		Format:  format,
+		Path:    outPath,
+		Candles: len(c.Candles),
+	}, nil
+}
+
+// buildChart 拉取日K线并生成 [start, end] 区间的图表
+func buildChart(ctx context.Context, cfg *config.Config, symbol string, start, end time.Time) (*chart.Chart, error) {
+	// Longport 按条数返回截至今天的K线，按日历天数估算所需条数
+	count := int(time.Since(start).Hours()/24) + chartWarmupBars
+	if count > 1000 {
+		count = 1000
+	}
+	data, err := tools.FetchMarketData(ctx, cfg, symbol, count)
+	if err != nil {
+		return nil, fmt.Errorf("fetch market data: %w", err)
+	}
+	c := chart.New(symbol, data, start, end)
+	if len(c.Candles) == 0 {
+		return nil, fmt.Errorf("no market data for %s between %s and %s", symbol, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+	return c, nil
+}
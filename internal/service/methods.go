@@ -0,0 +1,79 @@
+package service
+
+import (
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/models"
+)
+
+// 所有 Call 方法在此注册；Params 声明参数类型，Dispatch 先按类型解析参数，
+// 解析失败直接返回 invalid_params，system.methods 据此输出参数 JSON Schema
+func init() {
+	noParams := func(fn func() any) rpc.Handler {
+		return func(string) (any, error) { return fn(), nil }
+	}
+	for _, m := range []rpc.Method{
+		{Name: "system.info", Description: "版本与运行环境信息", Handler: noParams(GetSystemInfo)},
+		{Name: "system.version", Description: "版本与构建信息", Handler: noParams(func() any { return GetVersion() })},
+		{Name: "system.capabilities", Description: "可用数据源、工具、功能、方法与事件", Handler: noParams(func() any { return GetCapabilities() })},
+		{Name: "system.health", Description: "本地快速自检（不访问网络）", Handler: func(string) (any, error) { return CheckHealth() }},
+		{Name: "system.methods", Description: "列出全部方法及参数 JSON Schema", Handler: noParams(ListMethods)},
+		// listMethods 是 system.methods 的别名：内省方法最初约定的名字，客户端按此名探测；
+		// 其余方法均按 <领域>.<动作> 命名，故以 system.methods 为正式名称
+		{Name: "listMethods", Description: "system.methods 的别名", Handler: noParams(ListMethods)},
+		{Name: "system.doctor", Description: "环境与数据源诊断", Params: models.DoctorParams{}, Handler: RunDoctor},
+		{Name: "config.schema", Description: "配置 JSON Schema", Handler: noParams(GetConfigSchema)},
+		{Name: "events.topics", Description: "回调 topic 及其分类与详细程度", Handler: ListEventTopics},
+		{Name: "events.subscribe", Description: "全局回调只接收匹配的事件", Params: models.EventSubscribeParams{}, Handler: SubscribeEvents},
+		{Name: "events.unsubscribe", Description: "全局回调不再接收匹配的事件", Params: models.EventSubscribeParams{}, Handler: UnsubscribeEvents},
+		{Name: "events.verbosity", Description: "设置全局回调的最低详细程度", Params: models.EventVerbosityParams{}, Handler: SetEventVerbosity},
+		{Name: "events.reset", Description: "恢复接收全部事件", Handler: ResetEvents},
+		{Name: "agent.stream", Description: "启动分析，进度通过回调推送", Params: models.AgentInitParams{}, Handler: StartAgentStream},
+		{Name: "agent.runs", Description: "运行中的分析", Handler: ListRunningAgents},
+		{Name: "agent.cancel", Description: "中止运行中的分析", Params: models.AgentCancelParams{}, Handler: CancelAgent},
+		{Name: "agent.delta", Description: "以已完成的分析为基准，根据新行情与新闻做一次增量刷新", Params: models.AgentDeltaParams{}, Handler: DeltaAgent},
+		{Name: "agent.plan", Description: "dry-run 执行计划与费用估算", Params: models.AgentPlanParams{}, Handler: PlanAgent},
+		{Name: "agent.history.list", Description: "历史会话列表", Params: models.HistoryParams{}, Handler: GetAgentHistory},
+		{Name: "agent.history.info", Description: "历史会话详情", Params: models.HistoryInfoParams{}, Handler: GetHistoryInfo},
+		{Name: "agent.history.del", Description: "删除历史会话", Params: models.HistoryDeleteParams{}, Handler: DeleteHistory},
+		{Name: "agent.report.export", Description: "导出 json/html/md/pdf 报告或 TradingView 价位", Params: models.ReportExportParams{}, Handler: ExportReport},
+		{Name: "market.chart", Description: "K 线与指标图表", Params: models.MarketChartParams{}, Handler: GetMarketChart},
+		{Name: "market.quote", Description: "实时行情与 52 周区间", Params: models.MarketQuoteParams{}, Handler: GetMarketQuote},
+		{Name: "market.sectors", Description: "板块 ETF 多周期表现与轮动排名", Params: models.SectorRotationParams{}, Handler: GetSectorRotation},
+		{Name: "market.indicators", Description: "计算技术指标", Params: models.MarketIndicatorsParams{}, Handler: GetMarketIndicators},
+		{Name: "index.constituents", Description: "指数成分股（S&P 500 / NASDAQ-100 / 恒生指数）", Params: models.IndexConstituentsParams{}, Handler: ListIndexConstituents},
+		{Name: "index.breadth", Description: "指数成分股的涨跌家数、均线广度与新高新低", Params: models.IndexBreadthParams{}, Handler: GetIndexBreadth},
+		{Name: "news.list", Description: "新闻/Reddit 标题与情绪分", Params: models.NewsListParams{}, Handler: ListNews},
+		{Name: "documents.ingest", Description: "导入年报、研报等文档供 query_documents 检索", Params: models.DocumentIngestParams{}, Handler: IngestDocument},
+		{Name: "documents.list", Description: "已导入的文档", Params: models.DocumentListParams{}, Handler: ListDocuments},
+		{Name: "documents.del", Description: "删除已导入的文档", Params: models.DocumentDeleteParams{}, Handler: DeleteDocument},
+		{Name: "portfolio.sync", Description: "从长桥账户或 CSV 同步持仓与现金", Params: models.PortfolioSyncParams{}, Handler: SyncPortfolio},
+		{Name: "portfolio.get", Description: "当前持仓与现金快照", Handler: GetPortfolio},
+		{Name: "portfolio.risk", Description: "持仓或自选列表的收益相关性矩阵与集中度风险", Params: models.PortfolioRiskParams{}, Handler: PortfolioRisk},
+		{Name: "alerts.add", Description: "新建价格提醒", Params: models.AlertAddParams{}, Handler: AddAlert},
+		{Name: "alerts.list", Description: "价格提醒列表", Params: models.AlertListParams{}, Handler: ListAlerts},
+		{Name: "alerts.del", Description: "删除价格提醒", Params: models.AlertDeleteParams{}, Handler: DeleteAlert},
+		{Name: "alerts.start", Description: "后台监控行情，触发时启动分析并推送通知", Params: models.AlertWatchParams{}, Handler: StartAlerts},
+		{Name: "alerts.stop", Description: "停止价格提醒监控", Handler: StopAlerts},
+		{Name: "journal.add", Description: "记录一笔实际执行的交易", Params: models.JournalAddParams{}, Handler: AddJournal},
+		{Name: "journal.close", Description: "记录平仓并计算已实现盈亏", Params: models.JournalCloseParams{}, Handler: CloseJournal},
+		{Name: "journal.list", Description: "交易日志列表", Params: models.JournalListParams{}, Handler: ListJournal},
+		{Name: "journal.del", Description: "删除交易日志", Params: models.JournalDeleteParams{}, Handler: DeleteJournal},
+		{Name: "results.serve", Description: "启动本地结果看板", Params: models.ResultsServeParams{}, Handler: ServeResults},
+		{Name: "results.stop", Description: "停止本地结果看板", Handler: StopResults},
+		{Name: "results.stats", Description: "决策统计", Handler: GetResultsStats},
+		{Name: "results.info", Description: "单次分析的决策、表现与报告", Params: models.ResultInfoParams{}, Handler: GetResultInfo},
+		{Name: "results.calibration", Description: "各 agent 置信度校准与过度自信检测", Params: models.ResultsCalibrationParams{}, Handler: GetResultsCalibration},
+		{Name: "results.evaluate", Description: "按实际行情评估历史建议", Params: models.ResultsEvaluateParams{}, Handler: EvaluateResults},
+		{Name: "results.export", Description: "导出历史结果", Params: models.ResultsExportParams{}, Handler: ExportResults},
+		{Name: "results.compare", Description: "两次分析对比", Params: models.ResultsCompareParams{}, Handler: CompareResults},
+		{Name: "results.archive", Description: "csv/parquet 打包导出", Params: models.ResultsArchiveParams{}, Handler: ArchiveResults},
+		{Name: "results.sync", Description: "S3/GCS 对象存储同步", Params: models.ResultsSyncParams{}, Handler: SyncResults},
+	} {
+		rpc.Register(m)
+	}
+}
+
+// ListMethods 返回已注册方法及其参数 Schema（system.methods）
+func ListMethods() any {
+	return rpc.Methods()
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+const (
+	newsDefaultDays  = 3
+	newsDefaultLimit = 20
+)
+
+// ListNews 单独运行新闻/社交数据源，返回带情绪分的标题列表，可选导出
+func ListNews(paramsJson string) (any, error) {
+	var params models.NewsListParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	cfg := config.Get()
+	return FetchNews(&cfg, params)
+}
+
+// FetchNews 按来源拉取新闻，过滤回看窗口与低可信度来源，打情绪分并标注发布时的交易时段
+func FetchNews(cfg *config.Config, params models.NewsListParams) (*models.NewsListResponse, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(params.Symbol))
+	if symbol == "" {
+		return nil, rpc.InvalidParams("symbol is required")
+	}
+	days := params.Days
+	if days <= 0 {
+		days = newsDefaultDays
+	}
+	limit := params.Limit
+	if limit <= 0 {
+		limit = newsDefaultLimit
+	}
+	source := strings.ToLower(strings.TrimSpace(params.Source))
+	if source == "" {
+		source = "google"
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	var (
+		items []models.NewsItem
+		err   error
+	)
+	switch source {
+	case "google":
+		items, err = googleNewsItems(cfg, symbol, limit)
+	case "rss":
+		items, err = rssNewsItems(cfg, symbol, since, limit, params.Incremental)
+	case "reddit":
+		items, err = redditNewsItems(cfg, symbol)
+	default:
+		return nil, rpc.InvalidParams("unsupported source %q (supported: google, rss, reddit)", params.Source)
+	}
+	if params.Incremental && source != "rss" {
+		return nil, rpc.InvalidParams("incremental polling is only supported for the rss source")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s news: %w", source, err)
+	}
+
+	resp := &models.NewsListResponse{Symbol: symbol, Source: source, Items: make([]models.NewsItem, 0, len(items))}
+	var total float64
+	for _, item := range items {
+		// 没有发布时间的条目保留，避免误删
+		if !item.PublishedAt.IsZero() && item.PublishedAt.Before(since) {
+			continue
+		}
+		if params.MinQuality > 0 && item.SourceTier != "" && item.Quality < params.MinQuality {
+			continue
+		}
+		item.Session = dataflows.MarketSession(symbol, item.PublishedAt)
+		resp.Items = append(resp.Items, item)
+		total += item.Sentiment
+		if len(resp.Items) >= limit {
+			break
+		}
+	}
+	if len(resp.Items) > 0 {
+		resp.AvgSentiment = math.Round(total/float64(len(resp.Items))*100) / 100
+	}
+
+	if out := strings.TrimSpace(params.Output); out != "" {
+		if err := writeNews(out, resp); err != nil {
+			return nil, err
+		}
+		resp.Path = out
+	}
+	return resp, nil
+}
+
+func googleNewsItems(cfg *config.Config, symbol string, limit int) ([]models.NewsItem, error) {
+	articles, err := dataflows.NewGoogleNewsClient(cfg).GetStockNews(symbol, limit, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return articleItems(articles), nil
+}
+
+// rssNewsItems 拉取 Google News RSS；incremental 时跳过缓存，只返回上次轮询之后的新条目
+func rssNewsItems(cfg *config.Config, symbol string, since time.Time, limit int, incremental bool) ([]models.NewsItem, error) {
+	client := dataflows.NewGoogleNewsClient(cfg)
+	params := dataflows.EnhancedGoogleNewsParams{
+		Query:      symbol + " stock",
+		Language:   "en",
+		Country:    "US",
+		StartDate:  since,
+		EndDate:    time.Now(),
+		MaxResults: limit * 2,
+	}
+	if !incremental {
+		articles, err := client.GetGoogleNewsRSS(params, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return articleItems(articles), nil
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+	articles, err := client.PollGoogleNewsRSS(context.Background(), params, store)
+	if err != nil {
+		return nil, err
+	}
+	return articleItems(articles), nil
+}
+
+func redditNewsItems(cfg *config.Config, symbol string) ([]models.NewsItem, error) {
+	posts, err := dataflows.NewRedditClient(cfg).GetStockMentions(symbol, cfg)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]models.NewsItem, 0, len(posts))
+	for _, p := range posts {
+		if p == nil {
+			continue
+		}
+		items = append(items, models.NewsItem{
+			Title:       p.Title,
+			URL:         p.URL,
+			Source:      "r/" + p.Subreddit,
+			PublishedAt: p.CreatedAt,
+			Sentiment:   dataflows.ScoreSentiment(p.Title + " " + p.Content),
+			Score:       p.Score,
+		})
+	}
+	return items, nil
+}
+
+func articleItems(articles []*dataflows.NewsArticle) []models.NewsItem {
+	items := make([]models.NewsItem, 0, len(articles))
+	for _, a := range articles {
+		if a == nil {
+			continue
+		}
+		items = append(items, models.NewsItem{
+			Title:       a.Title,
+			URL:         a.URL,
+			Source:      a.Source,
+			PublishedAt: a.PublishedAt,
+			Sentiment:   dataflows.ScoreSentiment(a.Title + " " + a.Content),
+			SourceTier:  a.SourceTier,
+			Quality:     a.Quality,
+		})
+	}
+	return items
+}
+
+// writeNews 按扩展名导出 CSV 或 JSON
+func writeNews(path string, resp *models.NewsListResponse) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create output: %w", err)
+	}
+	defer f.Close()
+
+	if !strings.EqualFold(filepath.Ext(path), ".csv") {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(resp)
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"published_at", "source", "title", "url", "sentiment", "score", "session", "source_tier", "quality"})
+	for _, item := range resp.Items {
+		published := ""
+		if !item.PublishedAt.IsZero() {
+			published = item.PublishedAt.Format(time.RFC3339)
+		}
+		_ = w.Write([]string{published, item.Source, item.Title, item.URL,
+			strconv.FormatFloat(item.Sentiment, 'f', 2, 64), strconv.Itoa(item.Score), item.Session,
+			item.SourceTier, strconv.FormatFloat(item.Quality, 'f', 2, 64)})
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/graph"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/models"
+)
+
+// PlanAgent dry-run：输出 agent.stream 将执行的节点、工具、模型、数据源与 token/费用估算，不实际运行
+func PlanAgent(paramsJson string) (any, error) {
+	var params models.AgentPlanParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	cfg := config.Get()
+	if params.Offline {
+		cfg.Offline = true
+	}
+	return BuildPlan(&cfg, params)
+}
+
+// BuildPlan 校验参数并生成执行计划，demo 的 -dry-run 共用
+func BuildPlan(cfg *config.Config, params models.AgentPlanParams) (*models.AgentPlanResponse, error) {
+	params.Symbol = strings.TrimSpace(params.Symbol)
+	if params.Symbol == "" {
+		return nil, rpc.InvalidParams("symbol is required")
+	}
+	if strings.TrimSpace(params.TradeDate) == "" {
+		params.TradeDate = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", params.TradeDate); err != nil {
+		return nil, rpc.InvalidParams("invalid trade_date: %v", err)
+	}
+	if params.Depth != "" {
+		if !config.ValidDepth(params.Depth) {
+			return nil, rpc.InvalidParams("invalid depth %q: want quick, standard or deep", params.Depth)
+		}
+		c := *cfg
+		c.Depth = params.Depth
+		cfg = &c
+	}
+	return graph.BuildPlan(context.Background(), cfg, params.Symbol, params.TradeDate), nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/portfolio"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+)
+
+// SyncPortfolio 从长桥账户拉取或从 CSV 导入持仓与现金，替换本地快照（portfolio.sync）
+func SyncPortfolio(paramsJson string) (any, error) {
+	var params models.PortfolioSyncParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	cfg := config.Get()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return SyncPortfolioFrom(ctx, &cfg, params)
+}
+
+// SyncPortfolioFrom 同 SyncPortfolio，供命令行直接调用
+func SyncPortfolioFrom(ctx context.Context, cfg *config.Config, params models.PortfolioSyncParams) (*models.Portfolio, error) {
+	var (
+		p   *models.Portfolio
+		err error
+	)
+	switch source := strings.ToLower(strings.TrimSpace(params.Source)); source {
+	case "", models.PortfolioSourceLongport:
+		account := strings.TrimSpace(params.Account)
+		if account != "" && !slices.ContainsFunc(cfg.LongportAccountList(), func(a config.LongportAccount) bool { return a.Name == account }) {
+			return nil, rpc.InvalidParams("unknown longport account %q", account)
+		}
+		if p, err = portfolio.FromLongport(ctx, cfg, account); err != nil {
+			return nil, err
+		}
+	case models.PortfolioSourceCSV:
+		path := strings.TrimSpace(params.Path)
+		if path == "" {
+			return nil, rpc.InvalidParams("path is required for csv import")
+		}
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, rpc.NotFound("file not found: %s", path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if p, err = portfolio.ParseCSV(f); err != nil {
+			return nil, rpc.InvalidParams("%v", err)
+		}
+	default:
+		return nil, rpc.InvalidParams("invalid source %q: want %s or %s", params.Source, models.PortfolioSourceLongport, models.PortfolioSourceCSV)
+	}
+
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	if err := store.SavePortfolio(ctx, p); err != nil {
+		return nil, err
+	}
+	// 重新读取，使同一标的多行合并后的结果与 portfolio.get 一致
+	return store.GetPortfolio(ctx)
+}
+
+// GetPortfolio 返回最近一次同步的持仓快照（portfolio.get）
+func GetPortfolio(string) (any, error) {
+	return LoadPortfolio(context.Background())
+}
+
+// LoadPortfolio 同 GetPortfolio，供命令行直接调用
+func LoadPortfolio(ctx context.Context) (*models.Portfolio, error) {
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	p, err := store.GetPortfolio(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, rpc.NotFound("portfolio not synced yet: run portfolio.sync first")
+	}
+	return p, err
+}
+
+// PortfolioRisk 计算持仓（或指定标的列表）日收益的两两相关系数并标记集中度风险（portfolio.risk）
+func PortfolioRisk(paramsJson string) (any, error) {
+	var params models.PortfolioRiskParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	cfg := config.Get()
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	return AssessPortfolioRisk(ctx, &cfg, params)
+}
+
+// AssessPortfolioRisk 同 PortfolioRisk，供命令行直接调用；未指定标的时使用持仓，并按持仓成本计算集群占比
+func AssessPortfolioRisk(ctx context.Context, cfg *config.Config, params models.PortfolioRiskParams) (*models.CorrelationMatrix, error) {
+	if params.Window != 0 && (params.Window < portfolio.MinCorrelationWindow || params.Window > portfolio.MaxCorrelationWindow) {
+		return nil, rpc.InvalidParams("window must be between %d and %d", portfolio.MinCorrelationWindow, portfolio.MaxCorrelationWindow)
+	}
+	symbols := params.Symbols
+	var weights map[string]float64
+	currency := ""
+	if len(symbols) == 0 {
+		p, err := LoadPortfolio(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, pos := range p.Positions {
+			symbols = append(symbols, pos.Symbol)
+		}
+		weights, currency = tools.BookWeights(cfg, p), portfolio.BaseCurrency(p)
+	}
+	if len(symbols) < 2 {
+		return nil, rpc.InvalidParams("need at least two symbols, got %d", len(symbols))
+	}
+	return tools.CompareCorrelations(ctx, cfg, symbols, params.Window, params.TradeDate, weights, currency)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// week52Bars 约一年的交易日数量，用于计算 52 周高低点
+const week52Bars = 252
+
+// GetMarketQuote 查询实时价格、涨跌、成交量与 52 周区间，无需运行完整分析
+func GetMarketQuote(paramsJson string) (any, error) {
+	var params models.MarketQuoteParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	cfg := config.Get()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return FetchQuotes(ctx, &cfg, params.Symbols)
+}
+
+// FetchQuotes 批量查询行情，附带当前交易时段与盘前/盘后/夜盘成交；52 周区间取自日K线，拉取失败时留空
+func FetchQuotes(ctx context.Context, cfg *config.Config, symbols []string) (*models.MarketQuoteResponse, error) {
+	var cleaned []string
+	for _, s := range symbols {
+		if s = strings.ToUpper(strings.TrimSpace(s)); s != "" {
+			cleaned = append(cleaned, s)
+		}
+	}
+	if len(cleaned) == 0 {
+		return nil, rpc.InvalidParams("symbols is required")
+	}
+
+	client, err := dataflows.NewLongportClient(dataflows.LongportConfigFrom(cfg))
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := client.GetQuote(ctx, cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("fetch quote: %w", err)
+	}
+
+	resp := &models.MarketQuoteResponse{Quotes: make([]models.MarketQuote, 0, len(quotes))}
+	for _, q := range quotes {
+		mq := models.MarketQuote{
+			Symbol:    q.Symbol,
+			Last:      dataflows.DecimalFloat(q.LastDone),
+			PrevClose: dataflows.DecimalFloat(q.PrevClose),
+			Open:      dataflows.DecimalFloat(q.Open),
+			High:      dataflows.DecimalFloat(q.High),
+			Low:       dataflows.DecimalFloat(q.Low),
+			Volume:    q.Volume,
+			Turnover:  dataflows.DecimalFloat(q.Turnover),
+			Timestamp: time.Unix(q.Timestamp, 0).Format(time.RFC3339),
+			Session:   dataflows.MarketSession(q.Symbol, time.Now()),
+			Extended:  dataflows.ExtendedQuotes(q),
+		}
+		if mq.PrevClose != 0 {
+			mq.Change = mq.Last - mq.PrevClose
+			mq.ChangePct = mq.Change / mq.PrevClose * 100
+		}
+		if bars, err := tools.FetchMarketData(ctx, cfg, q.Symbol, week52Bars); err == nil {
+			mq.Week52Low, mq.Week52High = priceRange(bars)
+		} else {
+			fmt.Printf("quote 52w range symbol=%s err=%v\n", q.Symbol, err)
+		}
+		resp.Quotes = append(resp.Quotes, mq)
+	}
+	return resp, nil
+}
+
+// priceRange 返回日K线的最低价与最高价
+func priceRange(bars []*models.MarketData) (low, high float64) {
+	for _, b := range bars {
+		if b == nil {
+			continue
+		}
+		if low == 0 || b.Low < low {
+			low = b.Low
+		}
+		if b.High > high {
+			high = b.High
+		}
+	}
+	return low, high
+}
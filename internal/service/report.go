@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/calibration"
+	"github.com/dyike/CortexGo/internal/memory"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/rundir"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/internal/structure"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/chart"
+	"github.com/dyike/CortexGo/pkg/persist"
+)
+
+// ExportReport 将会话的最终报告导出为 json/html/md/pdf 文件、pine/tv_csv/tv_alerts 等 TradingView 价位文件或 ics 催化剂日历
+func ExportReport(paramsJson string) (any, error) {
+	var params models.ReportExportParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+
+	sessionID := strings.TrimSpace(params.SessionID)
+	if sessionID == "" {
+		return nil, rpc.InvalidParams("session_id is required")
+	}
+	sessionInt, err := strconv.ParseInt(sessionID, 10, 64)
+	if err != nil || sessionInt <= 0 {
+		return nil, rpc.InvalidParams("invalid session_id")
+	}
+
+	format := strings.ToLower(strings.TrimSpace(params.Format))
+	if format == "" {
+		format = "pdf"
+	}
+	cfg := config.Get()
+	// md / html 配置了模板时按模板渲染
+	exp, err := report.ExporterFor(&cfg, format)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	rep, err := loadReport(context.Background(), store, sessionInt)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "html" || format == "pdf" {
+		attachChart(&cfg, rep)
+	}
+	if exp.Levels {
+		attachStructure(&cfg, rep)
+	}
+
+	// 配置了 report_redact 时导出脱敏后的副本，库中与运行目录的报告保持原样
+	data, err := exp.Render(report.Redact(rep, &cfg))
+	if err != nil {
+		return nil, fmt.Errorf("render %s: %w", format, err)
+	}
+
+	outPath := strings.TrimSpace(params.Output)
+	if outPath != "" {
+		// 显式指定的输出路径始终是本地文件
+		if err := writeFile(outPath, data); err != nil {
+			return nil, fmt.Errorf("write report: %w", err)
+		}
+	} else {
+		// 默认写入结果存储，与运行目录的其他文件放在一起
+		if outPath, err = putExport(&cfg, rep, sessionID, exp.Ext, data); err != nil {
+			return nil, fmt.Errorf("write report: %w", err)
+		}
+	}
+
+	return models.ReportExportResponse{
+		SessionID: sessionID,
+		Format:    format,
+		Path:      outPath,
+		Size:      len(data),
+	}, nil
+}
+
+// putExport 将导出文件写入结果存储并返回其位置：有运行目录时 exports/ 放导出
+// 文件、charts/ 放附带的K线图，并刷新运行清单；运行目录出现之前保存的报告
+// 写在 <symbol>/<trade date>/ 下
+func putExport(cfg *config.Config, rep *report.Report, sessionID, ext string, data []byte) (string, error) {
+	ctx := context.Background()
+	s, err := persist.For(cfg)
+	if err != nil {
+		return "", err
+	}
+	if rep.RunID == "" {
+		key := rep.Symbol + "/" + rep.TradeDate + "/report_" + sessionID + ext
+		if err := s.Put(ctx, persist.Results, key, data); err != nil {
+			return "", err
+		}
+		return rundir.Location(cfg, key), nil
+	}
+	key := rundir.Key(rep.Symbol, rep.TradeDate, rep.RunID, rundir.ExportsDir, "report"+ext)
+	if err := s.Put(ctx, persist.Results, key, data); err != nil {
+		return "", err
+	}
+	if rep.ChartSVG != "" {
+		chartKey := rundir.Key(rep.Symbol, rep.TradeDate, rep.RunID, rundir.ChartsDir, "price.svg")
+		if err := s.Put(ctx, persist.Results, chartKey, []byte(rep.ChartSVG)); err != nil {
+			fmt.Printf("write chart err=%v\n", err)
+		}
+	}
+	if err := rundir.Refresh(ctx, cfg, rundir.Key(rep.Symbol, rep.TradeDate, rep.RunID)); err != nil {
+		fmt.Printf("refresh run manifest err=%v\n", err)
+	}
+	return rundir.Location(cfg, key), nil
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// attachChart 为 html/pdf 导出附加交易日前 120 天的K线图，行情不可用时跳过
+func attachChart(cfg *config.Config, rep *report.Report) {
+	end, err := time.Parse("2006-01-02", rep.TradeDate)
+	if err != nil {
+		return
+	}
+	c, err := buildChart(context.Background(), cfg, rep.Symbol, end.AddDate(0, 0, -120), end)
+	if err != nil {
+		fmt.Printf("attach chart symbol=%s err=%v\n", rep.Symbol, err)
+		return
+	}
+	opts := chart.Options{Title: rep.Symbol + " daily"}
+	rep.ChartSVG = c.SVG(opts)
+	rep.ChartImage = c.Image(opts)
+}
+
+// attachStructure 为 TradingView 导出附加交易日的价格结构，用于支撑/阻力位；行情不可用时只导出交易计划价位
+func attachStructure(cfg *config.Config, rep *report.Report) {
+	end, err := time.Parse("2006-01-02", rep.TradeDate)
+	if err != nil {
+		return
+	}
+	// Longport 按条数返回截至今天的K线，交易日之后的天数也要计入
+	extra := int(time.Since(end).Hours() / 24)
+	s, err := structure.Analyze(context.Background(), func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+		return tools.FetchMarketData(ctx, cfg, symbol, min(count+extra, 1000))
+	}, rep.Symbol, 0, rep.TradeDate)
+	if err != nil {
+		fmt.Printf("attach structure symbol=%s err=%v\n", rep.Symbol, err)
+		return
+	}
+	rep.Structure = s
+}
+
+// saveReport 持久化最终报告与结构化决策，供后续导出和统计使用
+func saveReport(ctx context.Context, store *storage.Store, sessionID int64, rep *report.Report) error {
+	// 校准失败不影响报告保存，决策保留原始置信度
+	if err := calibration.Annotate(ctx, store, rep); err != nil {
+		fmt.Printf("calibrate decision err=%v\n", err)
+	}
+	content, err := json.Marshal(rep)
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+	if err := store.SaveReport(ctx, &models.ReportRecord{
+		SessionId:      sessionID,
+		Symbol:         rep.Symbol,
+		TradeDate:      rep.TradeDate,
+		Recommendation: rep.Recommendation,
+		Content:        string(content),
+	}); err != nil {
+		return err
+	}
+	cfg := config.Get()
+	// 会话文档是 agent.db 之外的副本，写入失败不影响报告保存
+	if err := report.SaveSession(ctx, &cfg, sessionID, rep); err != nil {
+		fmt.Printf("save session document session=%d err=%v\n", sessionID, err)
+	}
+	// 建立检索索引失败不影响报告保存，下次检索时会补建
+	if err := memory.Index(ctx, &cfg, store, memory.DefaultEmbedder, sessionID, rep); err != nil {
+		fmt.Printf("index report err=%v\n", err)
+	}
+	if err := store.SaveAgentConfidences(ctx, sessionID, report.AgentConfidences(rep)); err != nil {
+		return err
+	}
+	if rep.Decision != nil {
+		return store.SaveDecision(ctx, sessionID, rep.Decision)
+	}
+	return nil
+}
+
+// loadReport 读取会话的最终报告
+func loadReport(ctx context.Context, store *storage.Store, sessionID int64) (*report.Report, error) {
+	rec, err := store.GetReport(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if rec == nil {
+		return nil, rpc.NotFound("report not found for session: %d", sessionID)
+	}
+	return report.Decode(rec.Content)
+}
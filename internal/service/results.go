@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/calibration"
+	"github.com/dyike/CortexGo/internal/dashboard"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+)
+
+const defaultDashboardAddr = "127.0.0.1:8765"
+
+var (
+	dashboardMu  sync.Mutex
+	dashboardSrv *dashboard.Server
+)
+
+// ServeResults 启动本地结果看板（重复调用返回已运行的地址）
+func ServeResults(paramsJson string) (any, error) {
+	var params models.ResultsServeParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	addr := strings.TrimSpace(params.Addr)
+	if addr == "" {
+		addr = defaultDashboardAddr
+	}
+
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	dashboardMu.Lock()
+	defer dashboardMu.Unlock()
+	if dashboardSrv == nil {
+		dashboardSrv = dashboard.New(store)
+	}
+	url, err := dashboardSrv.Start(addr)
+	if err != nil {
+		return nil, err
+	}
+	return models.ResultsServeResponse{Running: true, URL: url}, nil
+}
+
+// StopResults 停止本地结果看板
+func StopResults(paramsJson string) (any, error) {
+	dashboardMu.Lock()
+	defer dashboardMu.Unlock()
+	if dashboardSrv != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := dashboardSrv.Stop(ctx); err != nil {
+			return nil, fmt.Errorf("stop dashboard: %w", err)
+		}
+	}
+	return models.ResultsServeResponse{Running: false}, nil
+}
+
+// GetResultsStats 汇总历史决策数量、平均置信度与各持有期命中率
+func GetResultsStats(paramsJson string) (any, error) {
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	return store.ResultsStats(context.Background())
+}
+
+// GetResultsCalibration 对比各 agent 声明的置信度与实际命中率，标记系统性过度自信的 agent
+func GetResultsCalibration(paramsJson string) (any, error) {
+	var params models.ResultsCalibrationParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	if params.HorizonDays <= 0 {
+		params.HorizonDays = calibration.DefaultHorizon
+	}
+
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	return calibration.Summary(context.Background(), store, params.HorizonDays, strings.TrimSpace(params.Agent))
+}
+
+// EvaluateResults 拉取行情，为到期的历史决策计算持有期收益与是否命中
+func EvaluateResults(paramsJson string) (any, error) {
+	var params models.ResultsEvaluateParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	if params.HorizonDays <= 0 {
+		params.HorizonDays = 5
+	}
+
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	ctx := context.Background()
+	cfg := config.Get()
+
+	// 交易日少于自然日，按 1.5 倍自然日粗略判断是否已到期
+	cutoff := time.Now().AddDate(0, 0, -int(float64(params.HorizonDays)*1.5)-1).Format("2006-01-02")
+	pending, err := store.ListPendingOutcomes(ctx, params.HorizonDays, cutoff, params.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := models.ResultsEvaluateResponse{HorizonDays: params.HorizonDays}
+	for _, run := range pending {
+		tradeDate, err := time.Parse("2006-01-02", run.TradeDate)
+		if err != nil {
+			resp.Skipped++
+			continue
+		}
+		count := int(time.Since(tradeDate).Hours()/24) + 10
+		bars, err := tools.FetchMarketData(ctx, &cfg, run.Symbol, count)
+		if err != nil {
+			fmt.Printf("evaluate outcome session=%d err=%v\n", run.Id, err)
+			resp.Skipped++
+			continue
+		}
+		outcome, err := report.EvaluateOutcome(run.Recommendation, bars, run.TradeDate, params.HorizonDays)
+		if err != nil {
+			resp.Skipped++
+			continue
+		}
+		outcome.SessionId = run.Id
+		if err := store.SaveOutcome(ctx, outcome); err != nil {
+			return nil, err
+		}
+		resp.Evaluated++
+	}
+	return resp, nil
+}
+
+// ExportResults 将全部历史分析（含结构化决策与表现）导出为 JSON 文件
+func ExportResults(paramsJson string) (any, error) {
+	var params models.ResultsExportParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	runs, err := collectExportRuns(context.Background(), store, models.RunFilter{Limit: -1})
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal export: %w", err)
+	}
+
+	outPath := strings.TrimSpace(params.Output)
+	if outPath == "" {
+		cfg := config.Get()
+		outPath = filepath.Join(cfg.ResultsDir, "export_"+time.Now().Format("20060102_150405")+".json")
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		return nil, fmt.Errorf("create output dir: %w", err)
+	}
+	if err := os.WriteFile(outPath, data, 0o644); err != nil {
+		return nil, fmt.Errorf("write export: %w", err)
+	}
+	return models.ResultsExportResponse{Path: outPath, Runs: len(runs)}, nil
+}
+
+// GetResultInfo results.info：读取单次分析的决策、表现与最终报告
+func GetResultInfo(paramsJson string) (any, error) {
+	var params models.ResultInfoParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	sessionID, err := strconv.ParseInt(strings.TrimSpace(params.SessionID), 10, 64)
+	if err != nil || sessionID <= 0 {
+		return nil, rpc.InvalidParams("invalid session_id")
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	ctx := context.Background()
+	sess, err := store.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if sess == nil {
+		return nil, rpc.NotFound("session not found: %d", sessionID)
+	}
+	decision, err := store.GetDecision(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	outcomes, err := store.ListOutcomes(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	resp := models.ResultInfoResponse{ResultsExportRun: models.ResultsExportRun{
+		SessionID: strconv.FormatInt(sess.Id, 10),
+		Symbol:    sess.Symbol,
+		TradeDate: sess.TradeDate,
+		Status:    sess.Status,
+		CreatedAt: formatTime(sess.CreatedAt),
+		Decision:  decision,
+		Outcomes:  outcomes,
+	}}
+	// 未完成的分析没有报告
+	if rep, err := loadReport(ctx, store, sessionID); err == nil {
+		resp.Recommendation = rep.Recommendation
+		resp.Report, _ = json.Marshal(rep)
+		cfg := config.Get()
+		resp.Markdown = string(report.Rendered(&cfg, rep, "md"))
+	}
+	return resp, nil
+}
+
+// collectExportRuns 读取会话及其决策、表现
+func collectExportRuns(ctx context.Context, store *storage.Store, filter models.RunFilter) ([]models.ResultsExportRun, error) {
+	recs, err := store.ListRuns(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]models.ResultsExportRun, 0, len(recs))
+	for _, rec := range recs {
+		decision, err := store.GetDecision(ctx, rec.Id)
+		if err != nil {
+			return nil, err
+		}
+		outcomes, err := store.ListOutcomes(ctx, rec.Id)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, models.ResultsExportRun{
+			SessionID:      strconv.FormatInt(rec.Id, 10),
+			Symbol:         rec.Symbol,
+			TradeDate:      rec.TradeDate,
+			Status:         rec.Status,
+			Recommendation: rec.Recommendation,
+			CreatedAt:      formatTime(rec.CreatedAt),
+			Decision:       decision,
+			Outcomes:       outcomes,
+		})
+	}
+	return runs, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/models"
+)
+
+// analysisRun 一次运行中的分析：取消函数与进度快照
+type analysisRun struct {
+	cancel context.CancelFunc
+	info   models.AgentRunInfo
+}
+
+// runningAnalyses 运行中的分析，session_id -> run；每个分析有独立的 context、配置副本与事件回调
+var (
+	runningMu       sync.Mutex
+	runningAnalyses = map[string]*analysisRun{}
+)
+
+// registerRun 为一次分析创建可取消的 context；release 在分析结束时调用
+func registerRun(info models.AgentRunInfo) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	info.StartedAt = formatTime(time.Now())
+	runningMu.Lock()
+	runningAnalyses[info.SessionId] = &analysisRun{cancel: cancel, info: info}
+	runningMu.Unlock()
+	return ctx, func() {
+		runningMu.Lock()
+		delete(runningAnalyses, info.SessionId)
+		runningMu.Unlock()
+		cancel()
+	}
+}
+
+// touchRun 记录分析当前发言的 agent 与阶段
+func touchRun(sessionID, agent string) {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	run, ok := runningAnalyses[sessionID]
+	if !ok {
+		return
+	}
+	run.info.Events++
+	if agent != "" && agent != run.info.Agent {
+		run.info.Agent = agent
+		if phase := agentPhases[agent]; phase != "" {
+			run.info.Phase = phase
+		}
+	}
+}
+
+// GetRun 返回运行中分析的进度快照
+func GetRun(sessionID string) (models.AgentRunInfo, bool) {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	run, ok := runningAnalyses[strings.TrimSpace(sessionID)]
+	if !ok {
+		return models.AgentRunInfo{}, false
+	}
+	return run.info, true
+}
+
+// ListRunningAgents agent.runs：列出全部运行中的分析
+func ListRunningAgents(paramsJson string) (any, error) {
+	runningMu.Lock()
+	runs := make([]models.AgentRunInfo, 0, len(runningAnalyses))
+	for _, run := range runningAnalyses {
+		runs = append(runs, run.info)
+	}
+	runningMu.Unlock()
+	sort.Slice(runs, func(i, j int) bool {
+		if runs[i].StartedAt != runs[j].StartedAt {
+			return runs[i].StartedAt < runs[j].StartedAt
+		}
+		return runs[i].SessionId < runs[j].SessionId
+	})
+	return models.AgentRunsResponse{Runs: runs}, nil
+}
+
+// CancelAnalysis 取消运行中的分析；会话已结束或不存在时返回 false
+func CancelAnalysis(sessionID string) bool {
+	runningMu.Lock()
+	run, ok := runningAnalyses[strings.TrimSpace(sessionID)]
+	runningMu.Unlock()
+	if ok {
+		run.cancel()
+	}
+	return ok
+}
+
+// CancelAgent agent.cancel：中止 agent.stream 启动的分析
+func CancelAgent(paramsJson string) (any, error) {
+	var params models.AgentCancelParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	if strings.TrimSpace(params.SessionId) == "" {
+		return nil, rpc.InvalidParams("session_id is required")
+	}
+	return models.AgentCancelResponse{SessionId: params.SessionId, Cancelled: CancelAnalysis(params.SessionId)}, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/regime"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+)
+
+// GetSectorRotation 板块 ETF 多周期表现与轮动排名（market.sectors）
+func GetSectorRotation(paramsJson string) (any, error) {
+	var params models.SectorRotationParams
+	if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+		return nil, rpc.InvalidParams("invalid params: %v", err)
+	}
+	cfg := config.Get()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	return ComputeSectorRotation(ctx, &cfg, params)
+}
+
+// ComputeSectorRotation 同 GetSectorRotation，供命令行直接调用
+func ComputeSectorRotation(ctx context.Context, cfg *config.Config, params models.SectorRotationParams) (*models.SectorRotation, error) {
+	market := strings.ToUpper(strings.TrimSpace(params.Market))
+	if market == "" {
+		market = "US"
+	}
+	if market != "US" {
+		return nil, rpc.InvalidParams("sector rotation is only available for the US market")
+	}
+	return regime.Rotation(ctx, func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+		return tools.FetchMarketData(ctx, cfg, symbol, count)
+	}, market, strings.TrimSpace(params.TradeDate))
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rpc"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/objstore"
+	"github.com/dyike/CortexGo/pkg/secure"
+)
+
+const (
+	defaultObjstorePrefix = "cortexgo"
+	syncTimeout           = 5 * time.Minute
+)
+
+// SyncResults 将本地报告上传到对象存储，并导入其他机器上传的报告
+func SyncResults(paramsJson string) (any, error) {
+	var params models.ResultsSyncParams
+	if strings.TrimSpace(paramsJson) != "" {
+		if err := json.Unmarshal([]byte(paramsJson), &params); err != nil {
+			return nil, rpc.InvalidParams("invalid params: %v", err)
+		}
+	}
+	direction := strings.ToLower(strings.TrimSpace(params.Direction))
+	if direction == "" {
+		direction = "both"
+	}
+	if direction != "both" && direction != storage.SyncPush && direction != storage.SyncPull {
+		return nil, rpc.InvalidParams("unsupported direction %q (supported: push, pull, both)", params.Direction)
+	}
+
+	cfg := config.Get()
+	client, err := newObjstoreClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+
+	resp := &models.ResultsSyncResponse{Bucket: cfg.ObjstoreBucket}
+	if direction != storage.SyncPull {
+		resp.Pushed, resp.Errors = pushReports(ctx, cfg, client, store, resp.Errors)
+	}
+	if direction != storage.SyncPush {
+		resp.Pulled, resp.Errors = pullReports(ctx, cfg, client, store, resp.Errors)
+	}
+	return resp, nil
+}
+
+// uploadReport 分析完成后自动上传报告；未配置对象存储时直接跳过
+func uploadReport(cfg config.Config, store *storage.Store, sessionID int64, rep *report.Report) {
+	if rep == nil || !cfg.ObjstoreEnabled() {
+		return
+	}
+	client, err := newObjstoreClient(cfg)
+	if err != nil {
+		fmt.Printf("objstore upload session=%d err=%v\n", sessionID, err)
+		return
+	}
+	content, err := json.Marshal(rep)
+	if err == nil {
+		content, err = sealObject(cfg, content)
+	}
+	if err != nil {
+		fmt.Printf("objstore upload session=%d err=%v\n", sessionID, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	key := reportObjectKey(cfg, rep.Symbol, rep.TradeDate, sessionID)
+	if err := client.Put(ctx, key, content, "application/json"); err != nil {
+		fmt.Printf("objstore upload session=%d err=%v\n", sessionID, err)
+		return
+	}
+	if err := store.MarkSynced(ctx, key, sessionID, storage.SyncPush); err != nil {
+		fmt.Printf("objstore upload session=%d err=%v\n", sessionID, err)
+	}
+}
+
+func pushReports(ctx context.Context, cfg config.Config, client *objstore.Client, store *storage.Store, errs []string) (int, []string) {
+	recs, err := store.ListUnsyncedReports(ctx)
+	if err != nil {
+		return 0, append(errs, err.Error())
+	}
+	pushed := 0
+	for _, rec := range recs {
+		key := reportObjectKey(cfg, rec.Symbol, rec.TradeDate, rec.SessionId)
+		content, err := sealObject(cfg, []byte(rec.Content))
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if err := client.Put(ctx, key, content, "application/json"); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if err := store.MarkSynced(ctx, key, rec.SessionId, storage.SyncPush); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		pushed++
+	}
+	return pushed, errs
+}
+
+func pullReports(ctx context.Context, cfg config.Config, client *objstore.Client, store *storage.Store, errs []string) (int, []string) {
+	objects, err := client.List(ctx, objstorePrefix(cfg)+"/reports/")
+	if err != nil {
+		return 0, append(errs, err.Error())
+	}
+	synced, err := store.SyncedKeys(ctx)
+	if err != nil {
+		return 0, append(errs, err.Error())
+	}
+	pulled := 0
+	for _, obj := range objects {
+		if synced[obj.Key] || !strings.HasSuffix(obj.Key, ".json") {
+			continue
+		}
+		if err := importReport(ctx, cfg, client, store, obj.Key); err != nil {
+			errs = append(errs, fmt.Sprintf("import %s: %v", obj.Key, err))
+			continue
+		}
+		pulled++
+	}
+	return pulled, errs
+}
+
+// importReport 下载远端报告并作为新的本地会话写入
+func importReport(ctx context.Context, cfg config.Config, client *objstore.Client, store *storage.Store, key string) error {
+	data, err := client.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	cipher, err := secure.ForConfig(&cfg)
+	if err != nil {
+		return err
+	}
+	if data, err = cipher.Open(data); err != nil {
+		return err
+	}
+	rep, err := report.Decode(string(data))
+	if err != nil {
+		return err
+	}
+	session := &models.SessionRecord{
+		Symbol:    rep.Symbol,
+		TradeDate: rep.TradeDate,
+		Prompt:    "synced from " + key,
+		Status:    storage.StatusDone,
+	}
+	sessionID, err := store.CreateSession(ctx, session)
+	if err != nil {
+		return err
+	}
+	if err := saveReport(ctx, store, sessionID, rep); err != nil {
+		return err
+	}
+	return store.MarkSynced(ctx, key, sessionID, storage.SyncPull)
+}
+
+// reportObjectKey <prefix>/reports/<symbol>/<trade_date>/<host>-<session_id>.json；
+// 带上主机名，避免不同机器的自增 id 冲突
+func reportObjectKey(cfg config.Config, symbol, tradeDate string, sessionID int64) string {
+	return path.Join(objstorePrefix(cfg), "reports", keySegment(symbol), keySegment(tradeDate),
+		keySegment(hostname())+"-"+strconv.FormatInt(sessionID, 10)+".json")
+}
+
+func objstorePrefix(cfg config.Config) string {
+	prefix := strings.Trim(cfg.ObjstorePrefix, "/")
+	if prefix == "" {
+		return defaultObjstorePrefix
+	}
+	return prefix
+}
+
+func keySegment(s string) string {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "/", "_"))
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+func hostname() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "local"
+	}
+	return host
+}
+
+// sealObject 配置了加密密钥时，上传到 bucket 的报告同样加密；各机器需使用相同密钥
+func sealObject(cfg config.Config, data []byte) ([]byte, error) {
+	cipher, err := secure.ForConfig(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.Seal(data)
+}
+
+func newObjstoreClient(cfg config.Config) (*objstore.Client, error) {
+	if !cfg.ObjstoreEnabled() {
+		return nil, fmt.Errorf("object storage not configured (objstore_endpoint, objstore_bucket)")
+	}
+	return objstore.New(objstore.Config{
+		Endpoint:  cfg.ObjstoreEndpoint,
+		Region:    cfg.ObjstoreRegion,
+		Bucket:    cfg.ObjstoreBucket,
+		AccessKey: cfg.ObjstoreAccessKey,
+		SecretKey: cfg.ObjstoreSecretKey,
+		PathStyle: cfg.ObjstorePathStyle,
+	})
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/watch"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// NewWatcher 创建单个标的的 watch：行情来自长桥，新闻按 RSS 增量轮询（只返回此前未出现过的条目），
+// analyze 负责运行首次分析与重大变化后的刷新
+func NewWatcher(cfg *config.Config, symbol string, analyze func(ctx context.Context, u watch.Update) (string, error)) (*watch.Watcher, error) {
+	if cfg.Offline {
+		return nil, fmt.Errorf("watch needs live quotes and news: %w", dataflows.ErrOffline)
+	}
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if err := dataflows.ValidateSymbol(symbol); err != nil {
+		return nil, err
+	}
+	return &watch.Watcher{
+		Symbol: symbol,
+		Quote: func(ctx context.Context) (models.MarketQuote, error) {
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			defer cancel()
+			resp, err := FetchQuotes(ctx, cfg, []string{symbol})
+			if err != nil {
+				return models.MarketQuote{}, err
+			}
+			if len(resp.Quotes) == 0 {
+				return models.MarketQuote{}, fmt.Errorf("no quote for %s", symbol)
+			}
+			return resp.Quotes[0], nil
+		},
+		News: func(context.Context) ([]models.NewsItem, error) {
+			resp, err := FetchNews(cfg, models.NewsListParams{Symbol: symbol, Source: "rss", Days: 1, Incremental: true})
+			if err != nil {
+				return nil, err
+			}
+			return resp.Items, nil
+		},
+		Analyze: analyze,
+	}, nil
+}
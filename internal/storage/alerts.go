@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// alertDDL 价格提醒；armed 为 0 时条件仍满足，等待解除后才会再次触发
+const alertDDL = `
+	CREATE TABLE IF NOT EXISTS alerts (
+	  id INTEGER PRIMARY KEY AUTOINCREMENT,
+	  symbol TEXT NOT NULL,
+	  condition TEXT NOT NULL,
+	  threshold REAL NOT NULL,
+	  repeat INTEGER DEFAULT 0,
+	  status TEXT DEFAULT 'active',
+	  armed INTEGER DEFAULT 1,
+	  triggered_at DATETIME,
+	  trigger_price REAL,
+	  last_session_id TEXT,
+	  created_at DATETIME DEFAULT (datetime('now', 'localtime'))
+	);`
+
+const alertColumns = `id, symbol, condition, threshold, repeat, status, armed, triggered_at, trigger_price, last_session_id, created_at`
+
+// CreateAlert 新建提醒并回填 Id、Status 与 CreatedAt。
+func (s *Store) CreateAlert(ctx context.Context, a *models.Alert) error {
+	if a == nil {
+		return fmt.Errorf("alert is nil")
+	}
+	a.Status, a.Armed = models.AlertStatusActive, true
+	if err := s.db.QueryRowContext(ctx, `
+		INSERT INTO alerts (symbol, condition, threshold, repeat, status, armed)
+		VALUES (?, ?, ?, ?, ?, 1)
+		RETURNING id, created_at
+	`, a.Symbol, a.Condition, a.Threshold, a.Repeat, a.Status).Scan(&a.Id, &a.CreatedAt); err != nil {
+		return fmt.Errorf("create alert: %w", err)
+	}
+	return nil
+}
+
+// ListAlerts 按创建顺序列出提醒；symbol 为空时不限标的，all 为 false 时只列出监控中的提醒。
+func (s *Store) ListAlerts(ctx context.Context, symbol string, all bool) ([]models.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alerts WHERE 1 = 1`
+	var args []any
+	if symbol != "" {
+		query += " AND symbol LIKE ?"
+		args = append(args, likePattern(symbol))
+	}
+	if !all {
+		query += " AND status = ?"
+		args = append(args, models.AlertStatusActive)
+	}
+	query += " ORDER BY id"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.Alert
+	for rows.Next() {
+		var (
+			a           models.Alert
+			triggeredAt sql.NullTime
+			price       sql.NullFloat64
+			sessionID   sql.NullString
+		)
+		if err := rows.Scan(&a.Id, &a.Symbol, &a.Condition, &a.Threshold, &a.Repeat, &a.Status, &a.Armed,
+			&triggeredAt, &price, &sessionID, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan alert: %w", err)
+		}
+		a.TriggeredAt, a.TriggerPrice, a.LastSessionId = triggeredAt.Time, price.Float64, sessionID.String
+		items = append(items, a)
+	}
+	return items, rows.Err()
+}
+
+// DeleteAlert 删除提醒，不存在时返回 sql.ErrNoRows。
+func (s *Store) DeleteAlert(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM alerts WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete alert: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("delete alert: %w", err)
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MarkAlertTriggered 记录一次触发：一次性提醒转为 triggered，重复提醒解除 armed 直到条件恢复。
+func (s *Store) MarkAlertTriggered(ctx context.Context, id int64, price float64, sessionID string, at time.Time) error {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE alerts SET
+		  status = CASE WHEN repeat = 1 THEN status ELSE ? END,
+		  armed = 0,
+		  triggered_at = ?,
+		  trigger_price = ?,
+		  last_session_id = ?
+		WHERE id = ?
+	`, models.AlertStatusTriggered, at, price, sessionID, id); err != nil {
+		return fmt.Errorf("mark alert triggered: %w", err)
+	}
+	return nil
+}
+
+// RearmAlert 条件解除后重新允许重复提醒触发。
+func (s *Store) RearmAlert(ctx context.Context, id int64) error {
+	if _, err := s.db.ExecContext(ctx, `UPDATE alerts SET armed = 1 WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("rearm alert: %w", err)
+	}
+	return nil
+}
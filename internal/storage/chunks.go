@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// chunkDDL 报告切块与向量，供 search_past_analyses 检索历史分析。
+// content 与报告一样按配置加密；vector 为小端 float32 序列。
+const chunkDDL = `
+	CREATE TABLE IF NOT EXISTS report_chunks (
+	  session_id INTEGER NOT NULL,
+	  seq INTEGER NOT NULL,
+	  symbol TEXT,
+	  trade_date TEXT,
+	  section TEXT,
+	  content TEXT,
+	  embedder TEXT,
+	  vector BLOB,
+	  PRIMARY KEY(session_id, seq),
+	  FOREIGN KEY(session_id) REFERENCES sessions(id) ON DELETE CASCADE
+	);`
+
+// ReplaceChunks 用 chunks 整体替换会话的切块。
+func (s *Store) ReplaceChunks(ctx context.Context, sessionID int64, chunks []models.ReportChunk) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("replace chunks: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM report_chunks WHERE session_id = ?`, sessionID); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("replace chunks: %w", err)
+	}
+	for _, c := range chunks {
+		content, err := s.cipher.SealString(c.Content)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("encrypt chunk: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO report_chunks (session_id, seq, symbol, trade_date, section, content, embedder, vector)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, sessionID, c.Seq, c.Symbol, c.TradeDate, c.Section, content, c.Embedder, encodeVector(c.Vector)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("insert chunk: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("replace chunks: %w", err)
+	}
+	return nil
+}
+
+// ListChunks 按条件列出切块，并带出报告的最终建议。
+func (s *Store) ListChunks(ctx context.Context, filter models.ChunkFilter) ([]models.ReportChunk, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if filter.Symbol != "" {
+		conds = append(conds, "c.symbol LIKE ?")
+		args = append(args, likePattern(filter.Symbol))
+	}
+	if filter.Before != "" {
+		conds = append(conds, "c.trade_date < ?")
+		args = append(args, filter.Before)
+	}
+	if filter.Embedder != "" {
+		conds = append(conds, "c.embedder = ?")
+		args = append(args, filter.Embedder)
+	}
+	query := `
+		SELECT c.session_id, c.seq, c.symbol, c.trade_date, COALESCE(r.recommendation, ''), c.section, c.content, c.embedder, c.vector
+		FROM report_chunks c
+		LEFT JOIN reports r ON r.session_id = c.session_id
+	`
+	if len(conds) > 0 {
+		query += "WHERE " + strings.Join(conds, " AND ") + " "
+	}
+	query += "ORDER BY c.session_id DESC, c.seq"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.ReportChunk
+	for rows.Next() {
+		var (
+			c      models.ReportChunk
+			vector []byte
+		)
+		if err := rows.Scan(&c.SessionId, &c.Seq, &c.Symbol, &c.TradeDate, &c.Recommendation, &c.Section, &c.Content, &c.Embedder, &vector); err != nil {
+			return nil, fmt.Errorf("scan chunk: %w", err)
+		}
+		if c.Content, err = s.cipher.OpenString(c.Content); err != nil {
+			return nil, fmt.Errorf("decrypt chunk %d/%d: %w", c.SessionId, c.Seq, err)
+		}
+		c.Vector = decodeVector(vector)
+		items = append(items, c)
+	}
+	return items, rows.Err()
+}
+
+// ListUnindexedReports 返回还没有 embedder 生成的切块的报告（含更换向量模型后的旧报告）。
+func (s *Store) ListUnindexedReports(ctx context.Context, embedder string, limit int) ([]models.ReportRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT r.session_id, r.symbol, r.trade_date, r.recommendation, r.content, r.created_at
+		FROM reports r
+		WHERE NOT EXISTS (
+			SELECT 1 FROM report_chunks c WHERE c.session_id = r.session_id AND c.embedder = ?
+		)
+		ORDER BY r.session_id
+		LIMIT ?
+	`, embedder, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list unindexed reports: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.ReportRecord
+	for rows.Next() {
+		var rec models.ReportRecord
+		if err := rows.Scan(&rec.SessionId, &rec.Symbol, &rec.TradeDate, &rec.Recommendation, &rec.Content, &rec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan report: %w", err)
+		}
+		opened, err := s.openReport(&rec)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *opened)
+	}
+	return items, rows.Err()
+}
+
+func encodeVector(v []float32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(f))
+	}
+	return b
+}
+
+func decodeVector(b []byte) []float32 {
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// documentDDL 导入的外部文档及其切块，供 query_documents 检索。
+// 同一文件（sha256 相同）重复导入时覆盖原记录。
+const documentDDL = `
+	CREATE TABLE IF NOT EXISTS documents (
+	  id INTEGER PRIMARY KEY AUTOINCREMENT,
+	  symbol TEXT,
+	  title TEXT,
+	  kind TEXT,
+	  source TEXT,
+	  sha256 TEXT UNIQUE,
+	  pages INTEGER,
+	  created_at DATETIME DEFAULT (datetime('now', 'localtime'))
+	);
+	CREATE TABLE IF NOT EXISTS document_chunks (
+	  document_id INTEGER NOT NULL,
+	  seq INTEGER NOT NULL,
+	  page INTEGER,
+	  content TEXT,
+	  embedder TEXT,
+	  vector BLOB,
+	  PRIMARY KEY(document_id, seq),
+	  FOREIGN KEY(document_id) REFERENCES documents(id) ON DELETE CASCADE
+	);`
+
+// SaveDocument 写入文档及其切块并回填 doc.Id；sha256 已存在时替换原文档的元数据与切块。
+func (s *Store) SaveDocument(ctx context.Context, doc *models.DocumentRecord, chunks []models.DocumentChunk) error {
+	if doc == nil {
+		return fmt.Errorf("document is nil")
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("save document: %w", err)
+	}
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO documents (symbol, title, kind, source, sha256, pages)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(sha256) DO UPDATE SET
+		  symbol = excluded.symbol,
+		  title = excluded.title,
+		  kind = excluded.kind,
+		  source = excluded.source,
+		  pages = excluded.pages
+		RETURNING id
+	`, doc.Symbol, doc.Title, doc.Kind, doc.Source, doc.Sha256, doc.Pages).Scan(&doc.Id); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("save document: %w", err)
+	}
+	if err := tx.QueryRowContext(ctx, `SELECT created_at FROM documents WHERE id = ?`, doc.Id).Scan(&doc.CreatedAt); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("save document: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM document_chunks WHERE document_id = ?`, doc.Id); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("save document chunks: %w", err)
+	}
+	for _, c := range chunks {
+		content, err := s.cipher.SealString(c.Content)
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("encrypt document chunk: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO document_chunks (document_id, seq, page, content, embedder, vector)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, doc.Id, c.Seq, c.Page, content, c.Embedder, encodeVector(c.Vector)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("insert document chunk: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("save document: %w", err)
+	}
+	doc.Chunks = len(chunks)
+	return nil
+}
+
+// ListDocuments 按导入时间倒序列出文档，symbol 为空时列出全部。
+func (s *Store) ListDocuments(ctx context.Context, symbol string) ([]models.DocumentRecord, error) {
+	query := `
+		SELECT d.id, d.symbol, d.title, d.kind, d.source, d.sha256, d.pages, d.created_at,
+		  (SELECT COUNT(*) FROM document_chunks c WHERE c.document_id = d.id)
+		FROM documents d
+	`
+	var args []any
+	if symbol != "" {
+		query += "WHERE d.symbol LIKE ? "
+		args = append(args, likePattern(symbol))
+	}
+	query += "ORDER BY d.id DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list documents: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.DocumentRecord
+	for rows.Next() {
+		var d models.DocumentRecord
+		if err := rows.Scan(&d.Id, &d.Symbol, &d.Title, &d.Kind, &d.Source, &d.Sha256, &d.Pages, &d.CreatedAt, &d.Chunks); err != nil {
+			return nil, fmt.Errorf("scan document: %w", err)
+		}
+		items = append(items, d)
+	}
+	return items, rows.Err()
+}
+
+// DeleteDocument 删除文档及其切块，不存在时返回 sql.ErrNoRows。
+func (s *Store) DeleteDocument(ctx context.Context, id int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("delete document: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM document_chunks WHERE document_id = ?`, id); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("delete document chunks: %w", err)
+	}
+	res, err := tx.ExecContext(ctx, `DELETE FROM documents WHERE id = ?`, id)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("delete document: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		_ = tx.Rollback()
+		if err != nil {
+			return fmt.Errorf("delete document: %w", err)
+		}
+		return sql.ErrNoRows
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("delete document: %w", err)
+	}
+	return nil
+}
+
+// ListDocumentChunks 按条件列出文档切块，并带出文档的标题、类型与标的。
+func (s *Store) ListDocumentChunks(ctx context.Context, filter models.DocumentChunkFilter) ([]models.DocumentChunk, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if filter.Symbol != "" {
+		conds = append(conds, "(d.symbol LIKE ? OR d.symbol = '')")
+		args = append(args, likePattern(filter.Symbol))
+	}
+	if filter.Kind != "" {
+		conds = append(conds, "d.kind = ?")
+		args = append(args, filter.Kind)
+	}
+	if filter.Embedder != "" {
+		conds = append(conds, "c.embedder = ?")
+		args = append(args, filter.Embedder)
+	}
+	query := `
+		SELECT c.document_id, c.seq, c.page, c.content, c.embedder, c.vector, d.symbol, d.title, d.kind
+		FROM document_chunks c
+		JOIN documents d ON d.id = c.document_id
+	`
+	if len(conds) > 0 {
+		query += "WHERE " + strings.Join(conds, " AND ") + " "
+	}
+	query += "ORDER BY c.document_id DESC, c.seq"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list document chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.DocumentChunk
+	for rows.Next() {
+		var (
+			c      models.DocumentChunk
+			vector []byte
+		)
+		if err := rows.Scan(&c.DocumentId, &c.Seq, &c.Page, &c.Content, &c.Embedder, &vector, &c.Symbol, &c.Title, &c.Kind); err != nil {
+			return nil, fmt.Errorf("scan document chunk: %w", err)
+		}
+		if c.Content, err = s.cipher.OpenString(c.Content); err != nil {
+			return nil, fmt.Errorf("decrypt document chunk %d/%d: %w", c.DocumentId, c.Seq, err)
+		}
+		c.Vector = decodeVector(vector)
+		items = append(items, c)
+	}
+	return items, rows.Err()
+}
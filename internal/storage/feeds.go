@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// feedDDL 增量轮询 RSS 的状态：已投递条目的 GUID 与每个 feed 的高水位（已投递条目中最新的发布时间）。
+// GUID 保留 feedItemRetention，足以覆盖 feed 中仍会出现的旧条目。
+const feedDDL = `
+	CREATE TABLE IF NOT EXISTS feed_items (
+	  feed TEXT NOT NULL,
+	  guid TEXT NOT NULL,
+	  seen_at DATETIME DEFAULT (datetime('now', 'localtime')),
+	  PRIMARY KEY (feed, guid)
+	);
+	CREATE TABLE IF NOT EXISTS feed_marks (
+	  feed TEXT PRIMARY KEY,
+	  high_water TEXT NOT NULL,
+	  polled_at DATETIME DEFAULT (datetime('now', 'localtime'))
+	);`
+
+const feedItemRetention = "-30 days"
+
+// FeedHighWater 返回 feed 已投递条目中最新的发布时间；从未轮询过时为零值。
+func (s *Store) FeedHighWater(ctx context.Context, feed string) (time.Time, error) {
+	var raw string
+	err := s.db.QueryRowContext(ctx, `SELECT high_water FROM feed_marks WHERE feed = ?`, feed).Scan(&raw)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("get feed high-water mark: %w", err)
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse feed high-water mark %q: %w", raw, err)
+	}
+	return t, nil
+}
+
+// SeenFeedItems 返回 guids 中已投递过的条目。
+func (s *Store) SeenFeedItems(ctx context.Context, feed string, guids []string) (map[string]bool, error) {
+	seen := make(map[string]bool)
+	if len(guids) == 0 {
+		return seen, nil
+	}
+	args := []any{feed}
+	for _, g := range guids {
+		args = append(args, g)
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT guid FROM feed_items WHERE feed = ? AND guid IN (?`+strings.Repeat(", ?", len(guids)-1)+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list seen feed items: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var guid string
+		if err := rows.Scan(&guid); err != nil {
+			return nil, fmt.Errorf("scan seen feed item: %w", err)
+		}
+		seen[guid] = true
+	}
+	return seen, rows.Err()
+}
+
+// MarkFeedItems 记录本次轮询投递的条目并推进高水位（只增不减），同时清理过期的 GUID。
+func (s *Store) MarkFeedItems(ctx context.Context, feed string, guids []string, highWater time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin mark feed items: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, g := range guids {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO feed_items (feed, guid) VALUES (?, ?)`, feed, g); err != nil {
+			return fmt.Errorf("mark feed item: %w", err)
+		}
+	}
+	if !highWater.IsZero() {
+		// RFC3339 UTC 字符串可按字典序比较
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO feed_marks (feed, high_water, polled_at)
+			VALUES (?, ?, datetime('now', 'localtime'))
+			ON CONFLICT(feed) DO UPDATE SET
+				high_water = MAX(feed_marks.high_water, excluded.high_water),
+				polled_at = excluded.polled_at
+		`, feed, highWater.UTC().Format(time.RFC3339)); err != nil {
+			return fmt.Errorf("update feed high-water mark: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM feed_items WHERE feed = ? AND seen_at < datetime('now', 'localtime', ?)`, feed, feedItemRetention); err != nil {
+		return fmt.Errorf("prune feed items: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit mark feed items: %w", err)
+	}
+	return nil
+}
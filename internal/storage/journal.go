@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// journalDDL 交易日志；realized_pnl / return_pct 在平仓时计算后写入，便于直接汇总
+const journalDDL = `
+	CREATE TABLE IF NOT EXISTS journal_entries (
+	  id INTEGER PRIMARY KEY AUTOINCREMENT,
+	  session_id INTEGER DEFAULT 0,
+	  symbol TEXT NOT NULL,
+	  side TEXT NOT NULL,
+	  recommendation TEXT DEFAULT '',
+	  quantity REAL NOT NULL,
+	  entry_price REAL NOT NULL,
+	  entry_date TEXT NOT NULL,
+	  exit_price REAL DEFAULT 0,
+	  exit_date TEXT DEFAULT '',
+	  fees REAL DEFAULT 0,
+	  currency TEXT DEFAULT '',
+	  notes TEXT DEFAULT '',
+	  realized_pnl REAL DEFAULT 0,
+	  return_pct REAL DEFAULT 0,
+	  created_at DATETIME DEFAULT (datetime('now', 'localtime')),
+	  updated_at DATETIME DEFAULT (datetime('now', 'localtime'))
+	);
+	CREATE INDEX IF NOT EXISTS idx_journal_session ON journal_entries(session_id);`
+
+const journalColumns = `id, session_id, symbol, side, recommendation, quantity, entry_price, entry_date,
+	exit_price, exit_date, fees, currency, notes, realized_pnl, return_pct, created_at, updated_at`
+
+// CreateJournalEntry 新建交易日志并回填 Id 与时间。
+func (s *Store) CreateJournalEntry(ctx context.Context, e *models.JournalEntry) error {
+	if e == nil {
+		return fmt.Errorf("journal entry is nil")
+	}
+	notes, err := s.cipher.SealString(e.Notes)
+	if err != nil {
+		return fmt.Errorf("encrypt journal notes: %w", err)
+	}
+	if err := s.db.QueryRowContext(ctx, `
+		INSERT INTO journal_entries
+			(session_id, symbol, side, recommendation, quantity, entry_price, entry_date,
+			 exit_price, exit_date, fees, currency, notes, realized_pnl, return_pct)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, created_at, updated_at
+	`, e.SessionId, e.Symbol, e.Side, e.Recommendation, e.Quantity, e.EntryPrice, e.EntryDate,
+		e.ExitPrice, e.ExitDate, e.Fees, e.Currency, notes, e.RealizedPnL, e.ReturnPct,
+	).Scan(&e.Id, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		return fmt.Errorf("create journal entry: %w", err)
+	}
+	return nil
+}
+
+// UpdateJournalExit 写入平仓价格、日期、手续费、备注与已实现盈亏。
+func (s *Store) UpdateJournalExit(ctx context.Context, e *models.JournalEntry) error {
+	notes, err := s.cipher.SealString(e.Notes)
+	if err != nil {
+		return fmt.Errorf("encrypt journal notes: %w", err)
+	}
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE journal_entries SET
+		  exit_price = ?, exit_date = ?, fees = ?, notes = ?, realized_pnl = ?, return_pct = ?,
+		  updated_at = datetime('now', 'localtime')
+		WHERE id = ?
+	`, e.ExitPrice, e.ExitDate, e.Fees, notes, e.RealizedPnL, e.ReturnPct, e.Id)
+	if err != nil {
+		return fmt.Errorf("update journal entry: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("update journal entry: %w", err)
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetJournalEntry 读取单条交易日志，不存在时返回 nil。
+func (s *Store) GetJournalEntry(ctx context.Context, id int64) (*models.JournalEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+journalColumns+` FROM journal_entries WHERE id = ?`, id)
+	if err != nil {
+		return nil, fmt.Errorf("get journal entry: %w", err)
+	}
+	items, err := s.scanJournal(rows)
+	if err != nil || len(items) == 0 {
+		return nil, err
+	}
+	return &items[0], nil
+}
+
+// ListJournalEntries 按建仓日期倒序列出交易日志；symbol 为空、sessionID 为 0 时不限，openOnly 只列出未平仓的交易。
+func (s *Store) ListJournalEntries(ctx context.Context, symbol string, sessionID int64, openOnly bool) ([]models.JournalEntry, error) {
+	query := `SELECT ` + journalColumns + ` FROM journal_entries WHERE 1 = 1`
+	var args []any
+	if symbol != "" {
+		query += " AND symbol LIKE ?"
+		args = append(args, likePattern(symbol))
+	}
+	if sessionID > 0 {
+		query += " AND session_id = ?"
+		args = append(args, sessionID)
+	}
+	if openOnly {
+		query += " AND exit_price <= 0"
+	}
+	query += " ORDER BY entry_date DESC, id DESC"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list journal entries: %w", err)
+	}
+	return s.scanJournal(rows)
+}
+
+func (s *Store) scanJournal(rows *sql.Rows) ([]models.JournalEntry, error) {
+	defer rows.Close()
+	var items []models.JournalEntry
+	for rows.Next() {
+		var e models.JournalEntry
+		if err := rows.Scan(&e.Id, &e.SessionId, &e.Symbol, &e.Side, &e.Recommendation, &e.Quantity, &e.EntryPrice, &e.EntryDate,
+			&e.ExitPrice, &e.ExitDate, &e.Fees, &e.Currency, &e.Notes, &e.RealizedPnL, &e.ReturnPct, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan journal entry: %w", err)
+		}
+		notes, err := s.cipher.OpenString(e.Notes)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt journal entry %d: %w", e.Id, err)
+		}
+		e.Notes = notes
+		items = append(items, e)
+	}
+	return items, rows.Err()
+}
+
+// DeleteJournalEntry 删除交易日志，不存在时返回 sql.ErrNoRows。
+func (s *Store) DeleteJournalEntry(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM journal_entries WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete journal entry: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("delete journal entry: %w", err)
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// JournalStats 汇总交易日志：笔数、胜率、平均收益、按币种的已实现盈亏，以及照建议方向执行的交易表现。
+func (s *Store) JournalStats(ctx context.Context) (*models.JournalStats, error) {
+	stats := &models.JournalStats{RealizedPnL: map[string]float64{}}
+	var (
+		closed, wins, followed sql.NullInt64
+		avgReturn, followedAvg sql.NullFloat64
+	)
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			SUM(exit_price > 0),
+			SUM(exit_price > 0 AND realized_pnl > 0),
+			AVG(CASE WHEN exit_price > 0 THEN return_pct END),
+			SUM(exit_price > 0 AND `+followedCond+`),
+			AVG(CASE WHEN exit_price > 0 AND `+followedCond+` THEN return_pct END)
+		FROM journal_entries
+	`).Scan(&stats.Trades, &closed, &wins, &avgReturn, &followed, &followedAvg); err != nil {
+		return nil, fmt.Errorf("journal stats: %w", err)
+	}
+	stats.Closed, stats.Wins, stats.Followed = int(closed.Int64), int(wins.Int64), int(followed.Int64)
+	stats.Open = stats.Trades - stats.Closed
+	stats.AvgReturnPct, stats.FollowedReturnPct = avgReturn.Float64, followedAvg.Float64
+	if stats.Closed > 0 {
+		stats.WinRate = float64(stats.Wins) / float64(stats.Closed)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT currency, SUM(realized_pnl)
+		FROM journal_entries
+		WHERE exit_price > 0
+		GROUP BY currency
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("journal pnl: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			currency string
+			pnl      float64
+		)
+		if err := rows.Scan(&currency, &pnl); err != nil {
+			return nil, fmt.Errorf("scan journal pnl: %w", err)
+		}
+		stats.RealizedPnL[currency] = pnl
+	}
+	return stats, rows.Err()
+}
+
+// followedCond 交易方向与关联分析的建议一致
+const followedCond = `((side = 'long' AND recommendation = 'BUY') OR (side = 'short' AND recommendation = 'SELL'))`
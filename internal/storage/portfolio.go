@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// portfolioDDL 最近一次同步或导入的账户快照；portfolio 只有一行，
+// 持仓与现金随每次同步整体替换
+const portfolioDDL = `
+	CREATE TABLE IF NOT EXISTS portfolio (
+	  id INTEGER PRIMARY KEY CHECK (id = 1),
+	  source TEXT,
+	  currency TEXT,
+	  net_assets REAL,
+	  synced_at DATETIME
+	);
+	CREATE TABLE IF NOT EXISTS portfolio_positions (
+	  symbol TEXT PRIMARY KEY,
+	  name TEXT,
+	  quantity REAL,
+	  available_quantity REAL,
+	  cost_price REAL,
+	  currency TEXT,
+	  market TEXT
+	);
+	CREATE TABLE IF NOT EXISTS portfolio_cash (
+	  currency TEXT PRIMARY KEY,
+	  available REAL,
+	  frozen REAL
+	);`
+
+// SavePortfolio 用 p 替换当前持仓快照；同一标的或币种出现多次时数量累加
+func (s *Store) SavePortfolio(ctx context.Context, p *models.Portfolio) error {
+	if p == nil {
+		return fmt.Errorf("portfolio is nil")
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("save portfolio: %w", err)
+	}
+	stmts := []struct {
+		query string
+		args  []any
+	}{
+		{`DELETE FROM portfolio_positions`, nil},
+		{`DELETE FROM portfolio_cash`, nil},
+		{`INSERT OR REPLACE INTO portfolio (id, source, currency, net_assets, synced_at) VALUES (1, ?, ?, ?, ?)`,
+			[]any{p.Source, p.Currency, p.NetAssets, p.SyncedAt}},
+	}
+	for _, st := range stmts {
+		if _, err := tx.ExecContext(ctx, st.query, st.args...); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("save portfolio: %w", err)
+		}
+	}
+	for _, pos := range p.Positions {
+		// 多个账户通道持有同一标的时合并为一行，成本价按数量加权
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO portfolio_positions (symbol, name, quantity, available_quantity, cost_price, currency, market)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(symbol) DO UPDATE SET
+			  cost_price = CASE WHEN quantity + excluded.quantity = 0 THEN excluded.cost_price
+			    ELSE (cost_price * quantity + excluded.cost_price * excluded.quantity) / (quantity + excluded.quantity) END,
+			  quantity = quantity + excluded.quantity,
+			  available_quantity = available_quantity + excluded.available_quantity
+		`, pos.Symbol, pos.Name, pos.Quantity, pos.AvailableQuantity, pos.CostPrice, pos.Currency, pos.Market); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("insert portfolio position: %w", err)
+		}
+	}
+	for _, c := range p.Cash {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO portfolio_cash (currency, available, frozen) VALUES (?, ?, ?)
+			ON CONFLICT(currency) DO UPDATE SET
+			  available = available + excluded.available,
+			  frozen = frozen + excluded.frozen
+		`, c.Currency, c.Available, c.Frozen); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("insert portfolio cash: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("save portfolio: %w", err)
+	}
+	return nil
+}
+
+// GetPortfolio 读取当前持仓快照，从未同步过时返回 sql.ErrNoRows
+func (s *Store) GetPortfolio(ctx context.Context) (*models.Portfolio, error) {
+	p := &models.Portfolio{Positions: []models.PortfolioPosition{}, Cash: []models.PortfolioCash{}}
+	var syncedAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, `SELECT source, currency, net_assets, synced_at FROM portfolio WHERE id = 1`).
+		Scan(&p.Source, &p.Currency, &p.NetAssets, &syncedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get portfolio: %w", err)
+	}
+	p.SyncedAt = syncedAt.Time
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT symbol, name, quantity, available_quantity, cost_price, currency, market
+		FROM portfolio_positions ORDER BY symbol
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list portfolio positions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var pos models.PortfolioPosition
+		if err := rows.Scan(&pos.Symbol, &pos.Name, &pos.Quantity, &pos.AvailableQuantity, &pos.CostPrice, &pos.Currency, &pos.Market); err != nil {
+			return nil, fmt.Errorf("scan portfolio position: %w", err)
+		}
+		p.Positions = append(p.Positions, pos)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	cashRows, err := s.db.QueryContext(ctx, `SELECT currency, available, frozen FROM portfolio_cash ORDER BY currency`)
+	if err != nil {
+		return nil, fmt.Errorf("list portfolio cash: %w", err)
+	}
+	defer cashRows.Close()
+	for cashRows.Next() {
+		var c models.PortfolioCash
+		if err := cashRows.Scan(&c.Currency, &c.Available, &c.Frozen); err != nil {
+			return nil, fmt.Errorf("scan portfolio cash: %w", err)
+		}
+		p.Cash = append(p.Cash, c)
+	}
+	return p, cashRows.Err()
+}
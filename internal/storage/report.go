@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// reportDDL 每个会话一份最终报告，content 为 report.Report 的 JSON。
+const reportDDL = `
+	CREATE TABLE IF NOT EXISTS reports (
+	  session_id INTEGER PRIMARY KEY,
+	  symbol TEXT,
+	  trade_date TEXT,
+	  recommendation TEXT DEFAULT '',
+	  content TEXT,
+	  created_at DATETIME DEFAULT (datetime('now', 'localtime')),
+	  FOREIGN KEY(session_id) REFERENCES sessions(id) ON DELETE CASCADE
+	);`
+
+// SaveReport 写入或覆盖会话的最终报告。
+func (s *Store) SaveReport(ctx context.Context, rec *models.ReportRecord) error {
+	if rec == nil {
+		return fmt.Errorf("report record is nil")
+	}
+	if rec.SessionId <= 0 {
+		return fmt.Errorf("invalid session id: %d", rec.SessionId)
+	}
+	content, err := s.cipher.SealString(rec.Content)
+	if err != nil {
+		return fmt.Errorf("encrypt report: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO reports (session_id, symbol, trade_date, recommendation, content, created_at)
+		VALUES (?, ?, ?, ?, ?, datetime('now', 'localtime'))
+		ON CONFLICT(session_id) DO UPDATE SET
+			symbol = excluded.symbol,
+			trade_date = excluded.trade_date,
+			recommendation = excluded.recommendation,
+			content = excluded.content,
+			created_at = excluded.created_at
+	`, rec.SessionId, rec.Symbol, rec.TradeDate, rec.Recommendation, content)
+	if err != nil {
+		return fmt.Errorf("save report: %w", err)
+	}
+	return nil
+}
+
+// GetReport 读取会话的最终报告，不存在时返回 nil。
+func (s *Store) GetReport(ctx context.Context, sessionID int64) (*models.ReportRecord, error) {
+	if sessionID <= 0 {
+		return nil, fmt.Errorf("invalid session id: %d", sessionID)
+	}
+	row := s.db.QueryRowContext(ctx, `
+		SELECT session_id, symbol, trade_date, recommendation, content, created_at
+		FROM reports
+		WHERE session_id = ?
+	`, sessionID)
+
+	var rec models.ReportRecord
+	if err := row.Scan(&rec.SessionId, &rec.Symbol, &rec.TradeDate, &rec.Recommendation, &rec.Content, &rec.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get report: %w", err)
+	}
+	return s.openReport(&rec)
+}
+
+// ListRuns 按 id 倒序列出会话，并带出报告中的最终建议。
+func (s *Store) ListRuns(ctx context.Context, filter models.RunFilter) ([]models.RunRecord, error) {
+	// Limit < 0 表示不限制条数
+	limit := filter.Limit
+	if limit == 0 {
+		limit = 100
+	}
+	var (
+		conds []string
+		args  []any
+	)
+	if filter.Symbol != "" {
+		conds = append(conds, "s.symbol LIKE ?")
+		args = append(args, likePattern(filter.Symbol))
+	}
+	conds, args = appendDecisionConds(conds, args, filter.Recommendation, filter.Since, filter.MinConfidence)
+	if filter.Status != "" {
+		conds = append(conds, "s.status = ?")
+		args = append(args, filter.Status)
+	}
+	query := `
+		SELECT s.id, s.symbol, s.trade_date, s.prompt, s.status, s.created_at, s.updated_at,
+			COALESCE(r.recommendation, ''), COALESCE(d.confidence, 0)
+		FROM sessions s
+		LEFT JOIN reports r ON r.session_id = s.id
+		LEFT JOIN decisions d ON d.session_id = s.id
+	`
+	if len(conds) > 0 {
+		query += "WHERE " + strings.Join(conds, " AND ") + " "
+	}
+	query += "ORDER BY s.id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list runs: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.RunRecord
+	for rows.Next() {
+		var rec models.RunRecord
+		if err := rows.Scan(&rec.Id, &rec.Symbol, &rec.TradeDate, &rec.Prompt, &rec.Status, &rec.CreatedAt, &rec.UpdatedAt, &rec.Recommendation, &rec.Confidence); err != nil {
+			return nil, fmt.Errorf("scan run: %w", err)
+		}
+		items = append(items, rec)
+	}
+	return items, rows.Err()
+}
+
+// LatestReportFor 返回某标的在指定交易日最近一次分析的报告，不存在时返回 nil。
+func (s *Store) LatestReportFor(ctx context.Context, symbol, tradeDate string) (*models.ReportRecord, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT session_id, symbol, trade_date, recommendation, content, created_at
+		FROM reports
+		WHERE UPPER(symbol) = UPPER(?) AND trade_date = ?
+		ORDER BY session_id DESC
+		LIMIT 1
+	`, symbol, tradeDate)
+
+	var rec models.ReportRecord
+	if err := row.Scan(&rec.SessionId, &rec.Symbol, &rec.TradeDate, &rec.Recommendation, &rec.Content, &rec.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get latest report: %w", err)
+	}
+	return s.openReport(&rec)
+}
+
+// openReport 解密报告内容；未加密的历史数据原样返回。
+func (s *Store) openReport(rec *models.ReportRecord) (*models.ReportRecord, error) {
+	content, err := s.cipher.OpenString(rec.Content)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt report %d: %w", rec.SessionId, err)
+	}
+	rec.Content = content
+	return rec, nil
+}
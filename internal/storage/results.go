@@ -0,0 +1,301 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// decisionDDL 结构化决策，便于按建议/置信度过滤与统计。
+const decisionDDL = `
+	CREATE TABLE IF NOT EXISTS decisions (
+	  session_id INTEGER PRIMARY KEY,
+	  symbol TEXT,
+	  trade_date TEXT,
+	  action TEXT DEFAULT '',
+	  confidence REAL DEFAULT 0,
+	  entry_price REAL DEFAULT 0,
+	  stop_loss REAL DEFAULT 0,
+	  take_profit REAL DEFAULT 0,
+	  created_at DATETIME DEFAULT (datetime('now', 'localtime')),
+	  FOREIGN KEY(session_id) REFERENCES sessions(id) ON DELETE CASCADE
+	);`
+
+// outcomeDDL 决策在不同持有期后的实际表现。
+const outcomeDDL = `
+	CREATE TABLE IF NOT EXISTS outcomes (
+	  id INTEGER PRIMARY KEY AUTOINCREMENT,
+	  session_id INTEGER NOT NULL,
+	  horizon_days INTEGER NOT NULL,
+	  eval_date TEXT,
+	  entry_close REAL,
+	  exit_close REAL,
+	  return_pct REAL,
+	  correct INTEGER,
+	  created_at DATETIME DEFAULT (datetime('now', 'localtime')),
+	  FOREIGN KEY(session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+	  UNIQUE(session_id, horizon_days)
+	);`
+
+// agentConfidenceDDL 各 agent 声明的建议与置信度，结合 outcomes 做置信度校准。
+const agentConfidenceDDL = `
+	CREATE TABLE IF NOT EXISTS agent_confidences (
+	  session_id INTEGER NOT NULL,
+	  agent TEXT NOT NULL,
+	  action TEXT NOT NULL,
+	  confidence REAL NOT NULL,
+	  created_at DATETIME DEFAULT (datetime('now', 'localtime')),
+	  FOREIGN KEY(session_id) REFERENCES sessions(id) ON DELETE CASCADE,
+	  PRIMARY KEY(session_id, agent)
+	);`
+
+// sessionChildTables 删除会话时需要一并清理的表。
+var sessionChildTables = []string{"messages", "reports", "report_chunks", "decisions", "outcomes", "agent_confidences"}
+
+// SaveDecision 写入或覆盖会话的结构化决策。
+func (s *Store) SaveDecision(ctx context.Context, sessionID int64, d *models.TradingDecision) error {
+	if d == nil {
+		return fmt.Errorf("decision is nil")
+	}
+	if sessionID <= 0 {
+		return fmt.Errorf("invalid session id: %d", sessionID)
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO decisions (session_id, symbol, trade_date, action, confidence, entry_price, stop_loss, take_profit)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			symbol = excluded.symbol,
+			trade_date = excluded.trade_date,
+			action = excluded.action,
+			confidence = excluded.confidence,
+			entry_price = excluded.entry_price,
+			stop_loss = excluded.stop_loss,
+			take_profit = excluded.take_profit
+	`, sessionID, d.Symbol, d.Date, d.Action, d.Confidence, d.EntryPrice, d.StopLoss, d.TakeProfit)
+	if err != nil {
+		return fmt.Errorf("save decision: %w", err)
+	}
+	return nil
+}
+
+// GetDecision 读取会话的结构化决策，不存在时返回 nil。
+func (s *Store) GetDecision(ctx context.Context, sessionID int64) (*models.TradingDecision, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT symbol, trade_date, action, confidence, entry_price, stop_loss, take_profit
+		FROM decisions
+		WHERE session_id = ?
+	`, sessionID)
+	var d models.TradingDecision
+	if err := row.Scan(&d.Symbol, &d.Date, &d.Action, &d.Confidence, &d.EntryPrice, &d.StopLoss, &d.TakeProfit); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get decision: %w", err)
+	}
+	return &d, nil
+}
+
+// SaveAgentConfidences 写入或覆盖会话中各 agent 的建议与置信度。
+func (s *Store) SaveAgentConfidences(ctx context.Context, sessionID int64, items []models.AgentConfidence) error {
+	if sessionID <= 0 {
+		return fmt.Errorf("invalid session id: %d", sessionID)
+	}
+	for _, c := range items {
+		_, err := s.db.ExecContext(ctx, `
+			INSERT INTO agent_confidences (session_id, agent, action, confidence)
+			VALUES (?, ?, ?, ?)
+			ON CONFLICT(session_id, agent) DO UPDATE SET
+				action = excluded.action,
+				confidence = excluded.confidence
+		`, sessionID, c.Agent, c.Action, c.Confidence)
+		if err != nil {
+			return fmt.Errorf("save agent confidence: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListCalibrationSamples 返回已评估 horizonDays 表现的各 agent 置信度样本；agent 为空时返回全部。
+func (s *Store) ListCalibrationSamples(ctx context.Context, horizonDays int, agent string) ([]models.CalibrationSample, error) {
+	query := `
+		SELECT c.session_id, c.agent, c.action, c.confidence, o.return_pct
+		FROM agent_confidences c
+		JOIN outcomes o ON o.session_id = c.session_id AND o.horizon_days = ?
+	`
+	args := []any{horizonDays}
+	if agent != "" {
+		query += ` WHERE c.agent = ?`
+		args = append(args, agent)
+	}
+	query += ` ORDER BY c.session_id ASC`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list calibration samples: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.CalibrationSample
+	for rows.Next() {
+		var c models.CalibrationSample
+		if err := rows.Scan(&c.SessionId, &c.Agent, &c.Action, &c.Confidence, &c.ReturnPct); err != nil {
+			return nil, fmt.Errorf("scan calibration sample: %w", err)
+		}
+		items = append(items, c)
+	}
+	return items, rows.Err()
+}
+
+// SaveOutcome 写入或覆盖某个持有期的表现。
+func (s *Store) SaveOutcome(ctx context.Context, o *models.OutcomeRecord) error {
+	if o == nil {
+		return fmt.Errorf("outcome is nil")
+	}
+	if o.SessionId <= 0 || o.HorizonDays <= 0 {
+		return fmt.Errorf("invalid outcome: session=%d horizon=%d", o.SessionId, o.HorizonDays)
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO outcomes (session_id, horizon_days, eval_date, entry_close, exit_close, return_pct, correct)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id, horizon_days) DO UPDATE SET
+			eval_date = excluded.eval_date,
+			entry_close = excluded.entry_close,
+			exit_close = excluded.exit_close,
+			return_pct = excluded.return_pct,
+			correct = excluded.correct
+	`, o.SessionId, o.HorizonDays, o.EvalDate, o.EntryClose, o.ExitClose, o.ReturnPct, o.Correct)
+	if err != nil {
+		return fmt.Errorf("save outcome: %w", err)
+	}
+	return nil
+}
+
+// ListOutcomes 列出会话的所有持有期表现。
+func (s *Store) ListOutcomes(ctx context.Context, sessionID int64) ([]models.OutcomeRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT session_id, horizon_days, eval_date, entry_close, exit_close, return_pct, correct, created_at
+		FROM outcomes
+		WHERE session_id = ?
+		ORDER BY horizon_days ASC
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.OutcomeRecord
+	for rows.Next() {
+		var o models.OutcomeRecord
+		if err := rows.Scan(&o.SessionId, &o.HorizonDays, &o.EvalDate, &o.EntryClose, &o.ExitClose, &o.ReturnPct, &o.Correct, &o.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan outcome: %w", err)
+		}
+		items = append(items, o)
+	}
+	return items, rows.Err()
+}
+
+// ListPendingOutcomes 返回已有决策、但尚未评估 horizonDays 表现的会话，且交易日不晚于 before。
+func (s *Store) ListPendingOutcomes(ctx context.Context, horizonDays int, before string, limit int) ([]models.RunRecord, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT s.id, s.symbol, s.trade_date, s.status, d.action
+		FROM sessions s
+		JOIN decisions d ON d.session_id = s.id
+		LEFT JOIN outcomes o ON o.session_id = s.id AND o.horizon_days = ?
+		WHERE o.id IS NULL AND d.action != '' AND s.trade_date <= ?
+		ORDER BY s.id DESC
+		LIMIT ?
+	`, horizonDays, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list pending outcomes: %w", err)
+	}
+	defer rows.Close()
+
+	var items []models.RunRecord
+	for rows.Next() {
+		var rec models.RunRecord
+		if err := rows.Scan(&rec.Id, &rec.Symbol, &rec.TradeDate, &rec.Status, &rec.Recommendation); err != nil {
+			return nil, fmt.Errorf("scan pending outcome: %w", err)
+		}
+		items = append(items, rec)
+	}
+	return items, rows.Err()
+}
+
+// ResultsStats 汇总各建议的数量、平均置信度以及各持有期命中率。
+func (s *Store) ResultsStats(ctx context.Context) (*models.ResultsStats, error) {
+	stats := &models.ResultsStats{ByRecommendation: map[string]int{}}
+
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sessions`).Scan(&stats.TotalRuns); err != nil {
+		return nil, fmt.Errorf("count sessions: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT action, COUNT(*), AVG(confidence)
+		FROM decisions
+		GROUP BY action
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("decision stats: %w", err)
+	}
+	var weighted float64
+	for rows.Next() {
+		var (
+			action string
+			count  int
+			avg    sql.NullFloat64
+		)
+		if err := rows.Scan(&action, &count, &avg); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan decision stats: %w", err)
+		}
+		if action == "" {
+			action = "N/A"
+		}
+		stats.ByRecommendation[action] += count
+		stats.Decisions += count
+		weighted += avg.Float64 * float64(count)
+	}
+	rows.Close()
+	if stats.Decisions > 0 {
+		stats.AvgConfidence = weighted / float64(stats.Decisions)
+	}
+
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT horizon_days, COUNT(*), SUM(correct), AVG(return_pct)
+		FROM outcomes
+		GROUP BY horizon_days
+		ORDER BY horizon_days
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("outcome stats: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h models.HorizonStats
+		if err := rows.Scan(&h.HorizonDays, &h.Evaluated, &h.Correct, &h.AvgReturnPct); err != nil {
+			return nil, fmt.Errorf("scan outcome stats: %w", err)
+		}
+		if h.Evaluated > 0 {
+			h.HitRate = float64(h.Correct) / float64(h.Evaluated)
+		}
+		stats.Horizons = append(stats.Horizons, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	journal, err := s.JournalStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if journal.Trades > 0 {
+		stats.Journal = journal
+	}
+	return stats, nil
+}
@@ -2,11 +2,13 @@ package storage
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
 	"sync"
 
 	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/pkg/secure"
 )
 
 var (
@@ -27,7 +29,15 @@ func GetSQLiteStore() (*Store, error) {
 			return
 		}
 		dbPath := filepath.Join(dataDir, "agent.db")
+		cipher, err := secure.ForConfig(&cfg)
+		if err != nil {
+			sqliteStoreErr = fmt.Errorf("load encryption key: %w", err)
+			return
+		}
 		sqliteStoreInst, sqliteStoreErr = NewStore(dbPath)
+		if sqliteStoreErr == nil {
+			sqliteStoreInst.SetCipher(cipher)
+		}
 	})
 	return sqliteStoreInst, sqliteStoreErr
 }
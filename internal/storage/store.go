@@ -9,17 +9,21 @@ import (
 	"time"
 
 	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/secure"
 	"github.com/dyike/CortexGo/pkg/sqlite"
 )
 
 const (
-	StatusDone  = "done"
-	StatusError = "error"
-	StatusInit  = "init"
+	StatusDone      = "done"
+	StatusError     = "error"
+	StatusInit      = "init"
+	StatusCancelled = "cancelled"
 )
 
 type Store struct {
 	db *sql.DB
+	// cipher 非空时 reports.content 与 messages.content 加密落盘
+	cipher *secure.Cipher
 }
 
 func NewStore(dbPath string) (*Store, error) {
@@ -42,6 +46,11 @@ func NewStore(dbPath string) (*Store, error) {
 	return s, nil
 }
 
+// SetCipher 开启内容加密；nil 表示不加密（已加密的数据仍需密钥才能读取）。
+func (s *Store) SetCipher(c *secure.Cipher) {
+	s.cipher = c
+}
+
 func (s *Store) Close() error {
 	if s == nil || s.db == nil {
 		return nil
@@ -87,6 +96,39 @@ func (s *Store) InitTable() error {
 	if _, err := s.db.Exec(messageDDL); err != nil {
 		return fmt.Errorf("create messages table: %w", err)
 	}
+	if _, err := s.db.Exec(reportDDL); err != nil {
+		return fmt.Errorf("create reports table: %w", err)
+	}
+	if _, err := s.db.Exec(decisionDDL); err != nil {
+		return fmt.Errorf("create decisions table: %w", err)
+	}
+	if _, err := s.db.Exec(outcomeDDL); err != nil {
+		return fmt.Errorf("create outcomes table: %w", err)
+	}
+	if _, err := s.db.Exec(agentConfidenceDDL); err != nil {
+		return fmt.Errorf("create agent_confidences table: %w", err)
+	}
+	if _, err := s.db.Exec(syncDDL); err != nil {
+		return fmt.Errorf("create synced_objects table: %w", err)
+	}
+	if _, err := s.db.Exec(chunkDDL); err != nil {
+		return fmt.Errorf("create report_chunks table: %w", err)
+	}
+	if _, err := s.db.Exec(documentDDL); err != nil {
+		return fmt.Errorf("create documents tables: %w", err)
+	}
+	if _, err := s.db.Exec(portfolioDDL); err != nil {
+		return fmt.Errorf("create portfolio tables: %w", err)
+	}
+	if _, err := s.db.Exec(alertDDL); err != nil {
+		return fmt.Errorf("create alerts table: %w", err)
+	}
+	if _, err := s.db.Exec(feedDDL); err != nil {
+		return fmt.Errorf("create feed tables: %w", err)
+	}
+	if _, err := s.db.Exec(journalDDL); err != nil {
+		return fmt.Errorf("create journal table: %w", err)
+	}
 
 	// 常用查询索引
 	if _, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_messages_session_seq ON messages(session_id, seq);`); err != nil {
@@ -189,6 +231,11 @@ func (s *Store) SaveMessage(ctx context.Context, msg *models.MessageRecord) erro
 		status = StatusDone
 	}
 
+	content, err := s.cipher.SealString(msg.Content)
+	if err != nil {
+		return fmt.Errorf("encrypt message: %w", err)
+	}
+
 	const maxRetry = 5
 	for i := 0; i < maxRetry; i++ {
 		_, err := s.db.ExecContext(ctx, `
@@ -198,7 +245,7 @@ func (s *Store) SaveMessage(ctx context.Context, msg *models.MessageRecord) erro
 				?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(MAX(seq), 0) + 1, datetime('now', 'localtime')
 			FROM messages
 			WHERE session_id = ?
-		`, msg.SessionId, msg.Role, msg.Agent, msg.Content, msg.ToolCalls, msg.ToolCallId, msg.ToolName, status, msg.FinishReason, msg.SessionId)
+		`, msg.SessionId, msg.Role, msg.Agent, content, msg.ToolCalls, msg.ToolCallId, msg.ToolName, status, msg.FinishReason, msg.SessionId)
 
 		if err == nil {
 			return nil
@@ -215,45 +262,50 @@ func (s *Store) SaveMessage(ctx context.Context, msg *models.MessageRecord) erro
 	return fmt.Errorf("insert message: too many retries due to seq conflicts")
 }
 
-// ListSessions 使用 id 游标倒序分页列出会话。
-func (s *Store) ListSessions(ctx context.Context, cursor int64, limit int, symbol string, tradeDate string, query string) ([]models.SessionRecord, error) {
+// ListSessions 使用 id 游标倒序分页列出会话，并带出最终建议与置信度。
+func (s *Store) ListSessions(ctx context.Context, filter models.SessionFilter) ([]models.RunRecord, error) {
+	limit := filter.Limit
 	if limit <= 0 {
 		limit = 20
 	}
 
-	args := make([]any, 0, 4)
-	conds := make([]string, 0, 3)
+	args := make([]any, 0, 8)
+	conds := make([]string, 0, 5)
 	orConds := make([]string, 0, 2)
 	queryStr := `
-		SELECT id, symbol, trade_date, prompt, status, created_at, updated_at
-		FROM sessions
+		SELECT s.id, s.symbol, s.trade_date, s.prompt, s.status, s.created_at, s.updated_at,
+			COALESCE(r.recommendation, ''), COALESCE(d.confidence, 0)
+		FROM sessions s
+		LEFT JOIN reports r ON r.session_id = s.id
+		LEFT JOIN decisions d ON d.session_id = s.id
 	`
-	if cursor > 0 {
-		conds = append(conds, "id < ?")
-		args = append(args, cursor)
-	}
-	if query != "" {
-		orConds = append(orConds, "symbol LIKE ?")
-		args = append(args, likePattern(query))
-		orConds = append(orConds, "trade_date LIKE ?")
-		args = append(args, likePattern(query))
+	if filter.Cursor > 0 {
+		conds = append(conds, "s.id < ?")
+		args = append(args, filter.Cursor)
+	}
+	if filter.Query != "" {
+		orConds = append(orConds, "s.symbol LIKE ?")
+		args = append(args, likePattern(filter.Query))
+		orConds = append(orConds, "s.trade_date LIKE ?")
+		args = append(args, likePattern(filter.Query))
 	} else {
-		if symbol != "" {
-			orConds = append(orConds, "symbol LIKE ?")
-			args = append(args, likePattern(symbol))
+		if filter.Symbol != "" {
+			orConds = append(orConds, "s.symbol LIKE ?")
+			args = append(args, likePattern(filter.Symbol))
 		}
-		if tradeDate != "" {
-			orConds = append(orConds, "trade_date LIKE ?")
-			args = append(args, likePattern(tradeDate))
+		if filter.TradeDate != "" {
+			orConds = append(orConds, "s.trade_date LIKE ?")
+			args = append(args, likePattern(filter.TradeDate))
 		}
 	}
 	if len(orConds) > 0 {
 		conds = append(conds, "("+strings.Join(orConds, " OR ")+")")
 	}
+	conds, args = appendDecisionConds(conds, args, filter.Recommendation, filter.Since, filter.MinConfidence)
 	if len(conds) > 0 {
 		queryStr += "WHERE " + strings.Join(conds, " AND ") + " "
 	}
-	queryStr += "ORDER BY id DESC LIMIT ?"
+	queryStr += "ORDER BY s.id DESC LIMIT ?"
 	args = append(args, limit)
 
 	rows, err := s.db.QueryContext(ctx, queryStr, args...)
@@ -262,10 +314,10 @@ func (s *Store) ListSessions(ctx context.Context, cursor int64, limit int, symbo
 	}
 	defer rows.Close()
 
-	var items []models.SessionRecord
+	var items []models.RunRecord
 	for rows.Next() {
-		var rec models.SessionRecord
-		if err := rows.Scan(&rec.Id, &rec.Symbol, &rec.TradeDate, &rec.Prompt, &rec.Status, &rec.CreatedAt, &rec.UpdatedAt); err != nil {
+		var rec models.RunRecord
+		if err := rows.Scan(&rec.Id, &rec.Symbol, &rec.TradeDate, &rec.Prompt, &rec.Status, &rec.CreatedAt, &rec.UpdatedAt, &rec.Recommendation, &rec.Confidence); err != nil {
 			return nil, fmt.Errorf("scan session: %w", err)
 		}
 		items = append(items, rec)
@@ -273,6 +325,23 @@ func (s *Store) ListSessions(ctx context.Context, cursor int64, limit int, symbo
 	return items, nil
 }
 
+// appendDecisionConds 追加基于结构化结果的过滤条件（需 JOIN reports r 与 decisions d）。
+func appendDecisionConds(conds []string, args []any, recommendation, since string, minConfidence float64) ([]string, []any) {
+	if recommendation != "" {
+		conds = append(conds, "UPPER(COALESCE(r.recommendation, '')) = UPPER(?)")
+		args = append(args, recommendation)
+	}
+	if since != "" {
+		conds = append(conds, "s.trade_date >= ?")
+		args = append(args, since)
+	}
+	if minConfidence > 0 {
+		conds = append(conds, "COALESCE(d.confidence, 0) >= ?")
+		args = append(args, minConfidence)
+	}
+	return conds, args
+}
+
 func likePattern(input string) string {
 	if strings.ContainsAny(input, "%_") {
 		return input
@@ -351,6 +420,9 @@ func (s *Store) ListMessages(ctx context.Context, sessionID int64) ([]models.Mes
 		); err != nil {
 			return nil, fmt.Errorf("scan message: %w", err)
 		}
+		if rec.Content, err = s.cipher.OpenString(rec.Content); err != nil {
+			return nil, fmt.Errorf("decrypt message %d: %w", rec.Id, err)
+		}
 		items = append(items, rec)
 	}
 	return items, nil
@@ -366,17 +438,14 @@ func (s *Store) DeleteSession(ctx context.Context, sessionID int64) error {
 		return fmt.Errorf("delete session: %w", err)
 	}
 
-	res, err := tx.ExecContext(ctx, `
-		DELETE FROM messages
-		WHERE session_id = ?
-	`, sessionID)
-	if err != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("delete session messages: %w", err)
+	for _, table := range sessionChildTables {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE session_id = ?`, sessionID); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("delete session %s: %w", table, err)
+		}
 	}
-	_, _ = res.RowsAffected()
 
-	res, err = tx.ExecContext(ctx, `
+	res, err := tx.ExecContext(ctx, `
 		DELETE FROM sessions
 		WHERE id = ?
 	`, sessionID)
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// syncDDL 已与对象存储同步的报告对象。
+// 不随会话级联删除：本地删除远端导入的会话后，再次同步不会重新拉回。
+const syncDDL = `
+	CREATE TABLE IF NOT EXISTS synced_objects (
+	  object_key TEXT PRIMARY KEY,
+	  session_id INTEGER NOT NULL,
+	  direction TEXT NOT NULL,
+	  synced_at DATETIME DEFAULT (datetime('now', 'localtime'))
+	);`
+
+const (
+	SyncPush = "push"
+	SyncPull = "pull"
+)
+
+// MarkSynced 记录对象已上传（push）或已导入（pull）。
+func (s *Store) MarkSynced(ctx context.Context, objectKey string, sessionID int64, direction string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO synced_objects (object_key, session_id, direction, synced_at)
+		VALUES (?, ?, ?, datetime('now', 'localtime'))
+		ON CONFLICT(object_key) DO UPDATE SET
+			session_id = excluded.session_id,
+			direction = excluded.direction,
+			synced_at = excluded.synced_at
+	`, objectKey, sessionID, direction)
+	if err != nil {
+		return fmt.Errorf("mark synced: %w", err)
+	}
+	return nil
+}
+
+// SyncedKeys 返回全部已同步对象的 key 集合。
+func (s *Store) SyncedKeys(ctx context.Context) (map[string]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT object_key FROM synced_objects`)
+	if err != nil {
+		return nil, fmt.Errorf("list synced objects: %w", err)
+	}
+	defer rows.Close()
+
+	keys := make(map[string]bool)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("scan synced object: %w", err)
+		}
+		keys[key] = true
+	}
+	return keys, rows.Err()
+}
+
+// ListUnsyncedReports 返回尚未上传、且不是从远端导入的报告。
+func (s *Store) ListUnsyncedReports(ctx context.Context) ([]models.ReportRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT r.session_id, r.symbol, r.trade_date, r.recommendation, r.content, r.created_at
+		FROM reports r
+		WHERE NOT EXISTS (SELECT 1 FROM synced_objects o WHERE o.session_id = r.session_id)
+		ORDER BY r.session_id ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list unsynced reports: %w", err)
+	}
+	defer rows.Close()
+
+	var out []models.ReportRecord
+	for rows.Next() {
+		var rec models.ReportRecord
+		if err := rows.Scan(&rec.SessionId, &rec.Symbol, &rec.TradeDate, &rec.Recommendation, &rec.Content, &rec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan report: %w", err)
+		}
+		if _, err := s.openReport(&rec); err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,189 @@
+package structure
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// Bounds of the anomaly look-back, in daily bars.
+const (
+	DefaultAnomalyLookback = 60
+	MinAnomalyLookback     = 10
+	MaxAnomalyLookback     = 250
+)
+
+// Anomaly thresholds, each against the volumeWindow bars before the day.
+const (
+	gapWidth        = 1.0 // gap in median true ranges
+	minGapPct       = 1.0 // and in percent, so quiet names do not flag every wiggle
+	spikeZ          = 2.5 // volume z-score
+	expansionX      = 2.0 // true range over the median true range
+	recentVolBars   = 5   // bars of the volatility ratio's recent leg
+	expandingVol    = 1.5 // volatility ratio worth calling out
+	maxAnomalies    = 10
+	investigateDays = 3
+)
+
+// DetectAnomalies flags gaps, volume spikes and volatility expansions over
+// the last lookback bars up to asOf (all bars when empty), ranked by how
+// unusual each day was.
+func DetectAnomalies(ctx context.Context, fetch dataflows.BarFetcher, symbol string, lookback int, asOf string) (*models.AnomalyReport, error) {
+	lookback, err := checkAnomalyLookback(lookback)
+	if err != nil {
+		return nil, err
+	}
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	// the baseline window before the first day, plus a margin for bars after asOf
+	bars, err := fetch(ctx, symbol, lookback+volumeWindow+30)
+	if err != nil {
+		return nil, err
+	}
+	return FindAnomalies(symbol, bars, lookback, asOf)
+}
+
+// FindAnomalies is DetectAnomalies over bars already at hand.
+func FindAnomalies(symbol string, bars []*models.MarketData, lookback int, asOf string) (*models.AnomalyReport, error) {
+	lookback, err := checkAnomalyLookback(lookback)
+	if err != nil {
+		return nil, err
+	}
+	kept := dataflows.BarsUpTo(bars, asOf)
+	if len(kept) <= volumeWindow+1 {
+		return nil, fmt.Errorf("not enough price history: %d daily bars, need more than %d", len(kept), volumeWindow+1)
+	}
+	first := max(volumeWindow, len(kept)-lookback)
+	r := &models.AnomalyReport{Symbol: symbol, AsOf: kept[len(kept)-1].Date, Bars: len(kept) - first}
+	for i := first; i < len(kept); i++ {
+		if a, ok := anomaly(kept[i-volumeWindow:i], kept[i]); ok {
+			r.Anomalies = append(r.Anomalies, a)
+		}
+	}
+	sort.SliceStable(r.Anomalies, func(i, j int) bool { return r.Anomalies[i].Score > r.Anomalies[j].Score })
+	if len(r.Anomalies) > maxAnomalies {
+		r.Anomalies = r.Anomalies[:maxAnomalies]
+	}
+	r.VolatilityRatio = volatilityRatio(kept)
+	return r, nil
+}
+
+func checkAnomalyLookback(lookback int) (int, error) {
+	if lookback == 0 {
+		lookback = DefaultAnomalyLookback
+	}
+	if lookback < MinAnomalyLookback || lookback > MaxAnomalyLookback {
+		return 0, fmt.Errorf("lookback must be between %d and %d bars", MinAnomalyLookback, MaxAnomalyLookback)
+	}
+	return lookback, nil
+}
+
+// anomaly scores bar against the baseline bars before it. Each flag adds its
+// size relative to its threshold, so a gap on heavy volume outranks either
+// alone.
+func anomaly(baseline []*models.MarketData, bar *models.MarketData) (models.PriceAnomaly, bool) {
+	prev := baseline[len(baseline)-1]
+	a := models.PriceAnomaly{Date: bar.Date, Close: bar.Close, ChangePct: (bar.Close - prev.Close) / prev.Close * 100, NewsFrom: bar.Date}
+	unit := medianTrueRange(baseline)
+
+	if bar.Open > 0 && unit > 0 {
+		gap := bar.Open - prev.Close
+		pct := gap / prev.Close * 100
+		if math.Abs(gap) >= gapWidth*unit && math.Abs(pct) >= minGapPct {
+			a.GapPct = pct
+			a.Flags = append(a.Flags, models.AnomalyGapUp)
+			if gap < 0 {
+				a.Flags[len(a.Flags)-1] = models.AnomalyGapDown
+			}
+			// the news that caused a gap came out after the previous close
+			a.NewsFrom = prev.Date
+			a.Score += math.Abs(gap) / unit / gapWidth
+		}
+	}
+	if mean, sd := meanStd(baseline); sd > 0 {
+		if z := (float64(bar.Volume) - mean) / sd; z >= spikeZ {
+			a.VolumeZ = z
+			a.Flags = append(a.Flags, models.AnomalyVolumeSpike)
+			a.Score += z / spikeZ
+		}
+	}
+	if unit > 0 {
+		if x := trueRange(bar, prev) / unit; x >= expansionX {
+			a.RangeX = x
+			a.Flags = append(a.Flags, models.AnomalyVolatility)
+			a.Score += x / expansionX
+		}
+	}
+	return a, len(a.Flags) > 0
+}
+
+// volatilityRatio compares the spread of the last recentVolBars daily returns
+// with the volumeWindow returns before them.
+func volatilityRatio(bars []*models.MarketData) float64 {
+	returns := make([]float64, 0, len(bars)-1)
+	for i := 1; i < len(bars); i++ {
+		returns = append(returns, bars[i].Close/bars[i-1].Close-1)
+	}
+	if len(returns) < recentVolBars+volumeWindow {
+		return 0
+	}
+	recent := returns[len(returns)-recentVolBars:]
+	prior := returns[len(returns)-recentVolBars-volumeWindow : len(returns)-recentVolBars]
+	if base := stddev(prior); base > 0 {
+		return stddev(recent) / base
+	}
+	return 0
+}
+
+func stddev(values []float64) float64 {
+	var sum, sq float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sq / float64(len(values)))
+}
+
+// RenderAnomalies formats a report for the agent, highest priority first.
+func RenderAnomalies(r *models.AnomalyReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Price and volume anomalies of %s over %d daily bars up to %s (each day against the %d days before it):\n\n", r.Symbol, r.Bars, r.AsOf, volumeWindow)
+	if len(r.Anomalies) == 0 {
+		b.WriteString("No gaps, volume spikes or volatility expansions stand out.\n")
+	} else {
+		b.WriteString("| Priority | Date | Flags | Change | Gap | Volume z | Range x | Search news from |\n|---:|---|---|---:|---:|---:|---:|---|\n")
+		for i, a := range r.Anomalies {
+			fmt.Fprintf(&b, "| %d | %s | %s | %+.2f%% | %s | %s | %s | %s |\n", i+1, a.Date, strings.Join(a.Flags, ", "), a.ChangePct,
+				figure(a.GapPct, "%+.2f%%"), figure(a.VolumeZ, "%.1f"), figure(a.RangeX, "%.1f"), a.NewsFrom)
+		}
+		n := min(investigateDays, len(r.Anomalies))
+		days := make([]string, n)
+		for i, a := range r.Anomalies[:n] {
+			days[i] = a.Date
+		}
+		fmt.Fprintf(&b, "\nInvestigate the news behind %s first; a large move with no news behind it is itself worth noting.\n", strings.Join(days, ", "))
+	}
+	if r.VolatilityRatio >= expandingVol {
+		fmt.Fprintf(&b, "\nVolatility is expanding: the last %d daily returns are %.1fx as volatile as the %d before them.\n", recentVolBars, r.VolatilityRatio, volumeWindow)
+	} else if r.VolatilityRatio > 0 {
+		fmt.Fprintf(&b, "\nVolatility ratio (last %d days vs the %d before): %.2f.\n", recentVolBars, volumeWindow, r.VolatilityRatio)
+	}
+	return b.String()
+}
+
+func figure(v float64, format string) string {
+	if v == 0 {
+		return "-"
+	}
+	return fmt.Sprintf(format, v)
+}
@@ -0,0 +1,485 @@
+// Package structure labels the recent price structure of a stock from daily
+// candles (swing highs and lows, trading ranges, breakouts and volume
+// climaxes) and combines them into a Wyckoff-style phase assessment the
+// market analyst can cite. It also ranks the unusual days (gaps, volume
+// spikes, volatility expansions) whose news deserves a closer look.
+package structure
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// Bounds of the look-back, in daily bars.
+const (
+	DefaultLookback = 120
+	MinLookback     = 40
+	MaxLookback     = 250
+)
+
+// Detection thresholds. Widths and moves are measured in median true ranges
+// so the same rules apply to quiet and volatile names.
+const (
+	swingSpan      = 3   // bars on each side a swing high or low must exceed
+	minRangeBars   = 20  // shortest base that counts as a trading range
+	rangeWidth     = 5.0 // widest a trading range may be
+	breakoutBars   = 5   // how recent a breakout must be to be reported
+	breakoutVolume = 1.5 // breakout volume over the range average that confirms it
+	volumeWindow   = 20  // bars the volume z-score is measured against
+	climaxZ        = 2.5
+	climaxWidth    = 1.5 // true range of a climax bar
+	priorBars      = 40  // bars before a range that set its context
+	trendMove      = 5.0 // move into a range that counts as a prior trend
+	maxSwings      = 8
+	maxClimaxes    = 3
+)
+
+// Analyze assesses the structure of the last lookback bars up to asOf (all
+// bars when empty).
+func Analyze(ctx context.Context, fetch dataflows.BarFetcher, symbol string, lookback int, asOf string) (*models.MarketStructure, error) {
+	lookback, err := checkLookback(lookback)
+	if err != nil {
+		return nil, err
+	}
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol is required")
+	}
+	// a small margin so bars after asOf can be dropped without shortening the window
+	bars, err := fetch(ctx, symbol, lookback+30)
+	if err != nil {
+		return nil, err
+	}
+	return Assess(symbol, bars, lookback, asOf)
+}
+
+// Assess is Analyze over bars already at hand.
+func Assess(symbol string, bars []*models.MarketData, lookback int, asOf string) (*models.MarketStructure, error) {
+	lookback, err := checkLookback(lookback)
+	if err != nil {
+		return nil, err
+	}
+	kept := dataflows.BarsUpTo(bars, asOf)
+	if len(kept) < MinLookback {
+		return nil, fmt.Errorf("not enough price history: %d daily bars, need %d", len(kept), MinLookback)
+	}
+	if len(kept) > lookback {
+		kept = kept[len(kept)-lookback:]
+	}
+	unit := medianTrueRange(kept)
+	if unit <= 0 {
+		return nil, fmt.Errorf("price did not move over the last %d bars", len(kept))
+	}
+	last := kept[len(kept)-1]
+	s := &models.MarketStructure{Symbol: symbol, AsOf: last.Date, Bars: len(kept), Close: last.Close}
+
+	swings := swingPoints(kept)
+	s.Trend = trendOf(swings)
+	if len(swings) > maxSwings {
+		swings = swings[len(swings)-maxSwings:]
+	}
+	s.Swings = swings
+	var start int
+	s.Range, s.Breakout, start = tradingRange(kept, unit)
+	s.Climaxes = climaxes(kept, unit)
+	s.Phase, s.Confidence, s.Evidence = phase(kept, s, start, unit)
+	return s, nil
+}
+
+func checkLookback(lookback int) (int, error) {
+	if lookback == 0 {
+		lookback = DefaultLookback
+	}
+	if lookback < MinLookback || lookback > MaxLookback {
+		return 0, fmt.Errorf("lookback must be between %d and %d bars", MinLookback, MaxLookback)
+	}
+	return lookback, nil
+}
+
+// swingPoints finds bars whose high (low) exceeds the swingSpan bars on
+// either side and labels each against the previous swing of its kind.
+func swingPoints(bars []*models.MarketData) []models.SwingPoint {
+	var (
+		out               []models.SwingPoint
+		prevHigh, prevLow float64
+	)
+	for i := swingSpan; i < len(bars)-swingSpan; i++ {
+		h, l := highLow(bars[i])
+		isHigh, isLow := true, true
+		for j := i - swingSpan; j <= i+swingSpan; j++ {
+			if j == i {
+				continue
+			}
+			hj, lj := highLow(bars[j])
+			// ties go to the earlier bar
+			if hj > h || (j > i && hj == h) {
+				isHigh = false
+			}
+			if lj < l || (j > i && lj == l) {
+				isLow = false
+			}
+		}
+		if isHigh {
+			p := models.SwingPoint{Date: bars[i].Date, Kind: "high", Price: h}
+			if prevHigh > 0 {
+				p.Label = "LH"
+				if h > prevHigh {
+					p.Label = "HH"
+				}
+			}
+			prevHigh = h
+			out = append(out, p)
+		}
+		if isLow {
+			p := models.SwingPoint{Date: bars[i].Date, Kind: "low", Price: l}
+			if prevLow > 0 {
+				p.Label = "LL"
+				if l > prevLow {
+					p.Label = "HL"
+				}
+			}
+			prevLow = l
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// trendOf reads the trend from the labels of the latest swing high and low.
+func trendOf(swings []models.SwingPoint) string {
+	high, low := lastLabels(swings)
+	switch {
+	case high == "HH" && low == "HL":
+		return models.TrendUp
+	case high == "LH" && low == "LL":
+		return models.TrendDown
+	}
+	return models.TrendSideways
+}
+
+func lastLabels(swings []models.SwingPoint) (high, low string) {
+	for i := len(swings) - 1; i >= 0 && (high == "" || low == ""); i-- {
+		if swings[i].Kind == "high" && high == "" {
+			high = swings[i].Label
+		} else if swings[i].Kind == "low" && low == "" {
+			low = swings[i].Label
+		}
+	}
+	return high, low
+}
+
+// tradingRange finds the base price is trading in, or the one it left within
+// the last breakoutBars bars, and how it left: a close beyond an edge is a
+// breakout (breakdown), a probe beyond an edge that closed back inside is an
+// upthrust (spring). It also returns the index of the range's first bar, or
+// -1 when there is no range.
+func tradingRange(bars []*models.MarketData, unit float64) (*models.PriceRange, *models.StructureEvent, int) {
+	for offset := 0; offset <= breakoutBars && offset < len(bars); offset++ {
+		end := len(bars) - offset
+		start := longestBase(bars[:end], unit)
+		if start < 0 {
+			continue
+		}
+		base := bars[start:end]
+		r := &models.PriceRange{Start: base[0].Date, End: base[len(base)-1].Date, Bars: len(base)}
+		r.High, r.Low = math.Inf(-1), math.Inf(1)
+		var volume float64
+		for _, bar := range base {
+			h, l := highLow(bar)
+			r.High, r.Low = math.Max(r.High, h), math.Min(r.Low, l)
+			volume += float64(bar.Volume)
+		}
+		volume /= float64(len(base))
+		r.WidthPct = (r.High - r.Low) / r.Low * 100
+		last := bars[len(bars)-1].Close
+		r.Position = (last - r.Low) / (r.High - r.Low)
+		if offset == 0 {
+			return r, nil, start
+		}
+		return r, exit(bars[end:], r, volume), start
+	}
+	return nil, nil, -1
+}
+
+// longestBase returns the first index of the longest run of bars ending at
+// the last bar that stays within rangeWidth, or -1 if it is shorter than
+// minRangeBars.
+func longestBase(bars []*models.MarketData, unit float64) int {
+	high, low := math.Inf(-1), math.Inf(1)
+	start := -1
+	for i := len(bars) - 1; i >= 0; i-- {
+		h, l := highLow(bars[i])
+		high, low = math.Max(high, h), math.Min(low, l)
+		if high-low > rangeWidth*unit {
+			break
+		}
+		if len(bars)-i >= minRangeBars {
+			start = i
+		}
+	}
+	return start
+}
+
+// exit classifies the bars after a range.
+func exit(after []*models.MarketData, r *models.PriceRange, rangeVolume float64) *models.StructureEvent {
+	last := after[len(after)-1]
+	event := func(kind string, bar *models.MarketData, level float64) *models.StructureEvent {
+		e := &models.StructureEvent{Kind: kind, Date: bar.Date, Close: bar.Close, Level: level}
+		if rangeVolume > 0 {
+			e.VolumeRatio = float64(bar.Volume) / rangeVolume
+			e.Confirmed = (kind == models.EventBreakout || kind == models.EventBreakdown) && e.VolumeRatio >= breakoutVolume
+		}
+		return e
+	}
+	switch {
+	case last.Close > r.High:
+		for _, bar := range after {
+			if bar.Close > r.High {
+				return event(models.EventBreakout, bar, r.High)
+			}
+		}
+	case last.Close < r.Low:
+		for _, bar := range after {
+			if bar.Close < r.Low {
+				return event(models.EventBreakdown, bar, r.Low)
+			}
+		}
+	}
+	// Back inside: report the deepest probe beyond an edge.
+	var upthrust, spring *models.StructureEvent
+	above, below := 0.0, 0.0
+	for _, bar := range after {
+		h, l := highLow(bar)
+		if h-r.High > above {
+			above, upthrust = h-r.High, event(models.EventUpthrust, bar, r.High)
+		}
+		if r.Low-l > below {
+			below, spring = r.Low-l, event(models.EventSpring, bar, r.Low)
+		}
+	}
+	if below > above {
+		return spring
+	}
+	return upthrust
+}
+
+// climaxes flags bars whose volume is climaxZ standard deviations above the
+// previous volumeWindow bars on a wide true range, keeping the latest few.
+func climaxes(bars []*models.MarketData, unit float64) []models.StructureEvent {
+	var out []models.StructureEvent
+	for i := volumeWindow; i < len(bars); i++ {
+		mean, sd := meanStd(bars[i-volumeWindow : i])
+		if sd == 0 {
+			continue
+		}
+		z := (float64(bars[i].Volume) - mean) / sd
+		if z < climaxZ || trueRange(bars[i], bars[i-1]) < climaxWidth*unit {
+			continue
+		}
+		kind := models.EventBuyingClimax
+		if bars[i].Close < bars[i-1].Close {
+			kind = models.EventSellingClimax
+		}
+		out = append(out, models.StructureEvent{Kind: kind, Date: bars[i].Date, Close: bars[i].Close, VolumeZ: z})
+	}
+	if len(out) > maxClimaxes {
+		out = out[len(out)-maxClimaxes:]
+	}
+	return out
+}
+
+// phase combines the pieces into a phase, a confidence and the evidence
+// behind them. start is the first bar of s.Range.
+func phase(bars []*models.MarketData, s *models.MarketStructure, start int, unit float64) (string, string, []string) {
+	var evidence []string
+	high, low := lastLabels(s.Swings)
+	if high != "" || low != "" {
+		evidence = append(evidence, fmt.Sprintf("latest swing high %s, swing low %s: %s", orNone(high), orNone(low), s.Trend))
+	}
+
+	if b := s.Breakout; b != nil && (b.Kind == models.EventBreakout || b.Kind == models.EventBreakdown) {
+		p, want := models.PhaseMarkup, models.TrendUp
+		if b.Kind == models.EventBreakdown {
+			p, want = models.PhaseMarkdown, models.TrendDown
+		}
+		evidence = append(evidence, fmt.Sprintf("%s of the %s range (%.2f) on %s", b.Kind, rangeSpan(s.Range), b.Level, b.Date)+volumeNote(b))
+		points := 0
+		if b.Confirmed {
+			points++
+		}
+		if s.Trend == want {
+			points++
+		}
+		return p, confidence(points), evidence
+	}
+
+	if s.Range != nil {
+		score := 0
+		from := max(0, start-priorBars)
+		move := bars[start].Close - bars[from].Close
+		pct := move / bars[from].Close * 100
+		switch {
+		case move <= -trendMove*unit:
+			score++
+			evidence = append(evidence, fmt.Sprintf("price fell %.1f%% into the range", -pct))
+		case move >= trendMove*unit:
+			score--
+			evidence = append(evidence, fmt.Sprintf("price rose %.1f%% into the range", pct))
+		}
+		evidence = append(evidence, fmt.Sprintf("trading in a %s range for %d bars since %s, close at %.0f%% of it", rangeSpan(s.Range), s.Range.Bars, s.Range.Start, s.Range.Position*100))
+		// climaxes that stopped the prior move or happened inside the range
+		since := bars[max(0, start-swingSpan)].Date
+		for _, c := range s.Climaxes {
+			if c.Date < since {
+				continue
+			}
+			if c.Kind == models.EventSellingClimax {
+				score++
+			} else {
+				score--
+			}
+			evidence = append(evidence, fmt.Sprintf("%s on %s (volume z %.1f)", strings.ReplaceAll(c.Kind, "_", " "), c.Date, c.VolumeZ))
+		}
+		if b := s.Breakout; b != nil {
+			if b.Kind == models.EventSpring {
+				score++
+			} else {
+				score--
+			}
+			evidence = append(evidence, fmt.Sprintf("%s through %.2f on %s, closed back inside", b.Kind, b.Level, b.Date))
+		}
+		switch {
+		case score > 0:
+			return models.PhaseAccumulation, confidence(score - 1), evidence
+		case score < 0:
+			return models.PhaseDistribution, confidence(-score - 1), evidence
+		}
+		return models.PhaseRange, "low", evidence
+	}
+
+	switch s.Trend {
+	case models.TrendUp:
+		return models.PhaseMarkup, "medium", evidence
+	case models.TrendDown:
+		return models.PhaseMarkdown, "medium", evidence
+	}
+	return models.PhaseTransition, "low", evidence
+}
+
+// RenderStructure formats an assessment for the agent.
+func RenderStructure(s *models.MarketStructure) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Market structure of %s over %d daily bars up to %s (close %.2f):\n\n", s.Symbol, s.Bars, s.AsOf, s.Close)
+	fmt.Fprintf(&b, "- Phase: %s (confidence: %s)\n", s.Phase, s.Confidence)
+	fmt.Fprintf(&b, "- Trend: %s\n", s.Trend)
+	if r := s.Range; r != nil {
+		fmt.Fprintf(&b, "- Trading range: %s (%.1f%% wide, %d bars from %s to %s), close at %.0f%% of the range\n",
+			rangeSpan(r), r.WidthPct, r.Bars, r.Start, r.End, r.Position*100)
+	} else {
+		b.WriteString("- Trading range: none\n")
+	}
+	if e := s.Breakout; e != nil {
+		fmt.Fprintf(&b, "- Range exit: %s through %.2f on %s, close %.2f%s\n", e.Kind, e.Level, e.Date, e.Close, volumeNote(e))
+	}
+	for _, c := range s.Climaxes {
+		fmt.Fprintf(&b, "- Volume climax: %s on %s, close %.2f, volume z %.1f\n", strings.ReplaceAll(c.Kind, "_", " "), c.Date, c.Close, c.VolumeZ)
+	}
+	if len(s.Swings) > 0 {
+		b.WriteString("\n| Date | Swing | Price | Label |\n|---|---|---:|---|\n")
+		for _, p := range s.Swings {
+			fmt.Fprintf(&b, "| %s | %s | %.2f | %s |\n", p.Date, p.Kind, p.Price, orNone(p.Label))
+		}
+	}
+	if len(s.Evidence) > 0 {
+		b.WriteString("\nEvidence:\n")
+		for _, e := range s.Evidence {
+			fmt.Fprintf(&b, "- %s\n", e)
+		}
+	}
+	return b.String()
+}
+
+func confidence(points int) string {
+	switch {
+	case points >= 2:
+		return "high"
+	case points == 1:
+		return "medium"
+	}
+	return "low"
+}
+
+func rangeSpan(r *models.PriceRange) string {
+	if r == nil {
+		return "prior"
+	}
+	return fmt.Sprintf("%.2f–%.2f", r.Low, r.High)
+}
+
+func volumeNote(e *models.StructureEvent) string {
+	switch {
+	case e.VolumeRatio == 0:
+		return ""
+	case e.Confirmed || (e.Kind != models.EventBreakout && e.Kind != models.EventBreakdown):
+		return fmt.Sprintf(" on %.1fx the range's average volume", e.VolumeRatio)
+	}
+	return fmt.Sprintf(" on only %.1fx the range's average volume (unconfirmed)", e.VolumeRatio)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// highLow falls back to the close when a bar has no high or low.
+func highLow(bar *models.MarketData) (float64, float64) {
+	h, l := bar.High, bar.Low
+	if h <= 0 || l <= 0 {
+		return bar.Close, bar.Close
+	}
+	return h, l
+}
+
+func trueRange(bar, prev *models.MarketData) float64 {
+	h, l := highLow(bar)
+	return math.Max(h, prev.Close) - math.Min(l, prev.Close)
+}
+
+// medianTrueRange is the typical daily move, robust to the climax bars the
+// package looks for.
+func medianTrueRange(bars []*models.MarketData) float64 {
+	ranges := make([]float64, 0, len(bars)-1)
+	for i := 1; i < len(bars); i++ {
+		ranges = append(ranges, trueRange(bars[i], bars[i-1]))
+	}
+	sort.Float64s(ranges)
+	n := len(ranges)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return ranges[n/2]
+	}
+	return (ranges[n/2-1] + ranges[n/2]) / 2
+}
+
+func meanStd(bars []*models.MarketData) (float64, float64) {
+	var sum, sq float64
+	for _, bar := range bars {
+		sum += float64(bar.Volume)
+	}
+	mean := sum / float64(len(bars))
+	for _, bar := range bars {
+		d := float64(bar.Volume) - mean
+		sq += d * d
+	}
+	return mean, math.Sqrt(sq / float64(len(bars)))
+}
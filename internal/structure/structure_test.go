@@ -0,0 +1,155 @@
+package structure
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/internal/bartest"
+	"github.com/dyike/CortexGo/models"
+)
+
+// declineThenBase falls from 150 to 100 over 60 bars, ends the fall on a
+// selling climax and then chops between 100 and 104 for 40 bars.
+func declineThenBase() []*models.MarketData {
+	var closes []float64
+	for i := 0; i < 60; i++ {
+		closes = append(closes, 150-50*float64(i)/59)
+	}
+	for i := 0; i < 40; i++ {
+		closes = append(closes, 102+2*math.Sin(float64(i)))
+	}
+	bars := bartest.Bars(closes)
+	climax := bars[59]
+	climax.Low, climax.Volume = 96, 6000
+	return bars
+}
+
+func TestAssessAccumulation(t *testing.T) {
+	s, err := Assess("AAPL.US", declineThenBase(), 0, "")
+	if err != nil {
+		t.Fatalf("Assess: %v", err)
+	}
+	if s.Phase != models.PhaseAccumulation || s.Range == nil || s.Breakout != nil {
+		t.Fatalf("structure = %+v", s)
+	}
+	if len(s.Climaxes) != 1 || s.Climaxes[0].Kind != models.EventSellingClimax {
+		t.Fatalf("climaxes = %+v", s.Climaxes)
+	}
+	out := RenderStructure(s)
+	for _, want := range []string{"Phase: accumulation", "Trading range:", "selling climax", "Evidence:"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestAssessBreakoutAndSpring(t *testing.T) {
+	bars := declineThenBase()
+	last := bars[len(bars)-1]
+	last.Close, last.High, last.Volume = 112, 113, 4000
+	s, err := Assess("AAPL.US", bars, 0, "")
+	if err != nil {
+		t.Fatalf("Assess: %v", err)
+	}
+	if s.Phase != models.PhaseMarkup || s.Breakout == nil || s.Breakout.Kind != models.EventBreakout || !s.Breakout.Confirmed {
+		t.Fatalf("breakout = %+v, phase %s", s.Breakout, s.Phase)
+	}
+	if s.Range.Position <= 1 {
+		t.Errorf("position = %.2f, want above the range", s.Range.Position)
+	}
+
+	bars = declineThenBase()
+	last = bars[len(bars)-1]
+	last.Low = 90
+	if s, err = Assess("AAPL.US", bars, 0, ""); err != nil {
+		t.Fatalf("Assess: %v", err)
+	}
+	if s.Breakout == nil || s.Breakout.Kind != models.EventSpring || s.Phase != models.PhaseAccumulation || s.Confidence != "high" {
+		t.Fatalf("spring = %+v, phase %s (%s)", s.Breakout, s.Phase, s.Confidence)
+	}
+}
+
+func TestAssessTrend(t *testing.T) {
+	// a zigzag that gains more than it gives back
+	var closes []float64
+	price := 100.0
+	for i := 0; i < 90; i++ {
+		if i%10 < 6 {
+			price += 2
+		} else {
+			price -= 1.5
+		}
+		closes = append(closes, price)
+	}
+	s, err := Assess("NVDA.US", bartest.Bars(closes), 0, "")
+	if err != nil {
+		t.Fatalf("Assess: %v", err)
+	}
+	if s.Trend != models.TrendUp || s.Phase != models.PhaseMarkup || s.Range != nil {
+		t.Fatalf("structure = %+v", s)
+	}
+	for _, p := range s.Swings[2:] {
+		if p.Label != "HH" && p.Label != "HL" {
+			t.Errorf("swing %+v, want higher highs and lows", p)
+		}
+	}
+}
+
+func TestAnalyzeBounds(t *testing.T) {
+	fetch := func(_ context.Context, _ string, count int) ([]*models.MarketData, error) {
+		if count != DefaultLookback+30 {
+			t.Errorf("count = %d", count)
+		}
+		return declineThenBase(), nil
+	}
+	if _, err := Analyze(context.Background(), fetch, "AAPL.US", 10, ""); err == nil {
+		t.Error("want lookback error")
+	}
+	// bars after asOf are dropped, leaving too little history
+	if _, err := Analyze(context.Background(), fetch, "AAPL.US", 0, "2025-04-01"); err == nil || !strings.Contains(err.Error(), "not enough price history") {
+		t.Errorf("err = %v", err)
+	}
+	s, err := Analyze(context.Background(), fetch, " aapl.us ", 0, "2025-06-20")
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if s.Symbol != "AAPL.US" || s.AsOf != "2025-06-20" || s.Bars != 90 {
+		t.Fatalf("structure = %+v", s)
+	}
+}
+
+func TestFindAnomaliesRanksGapOnVolume(t *testing.T) {
+	closes := make([]float64, 60)
+	for i := range closes {
+		closes[i] = 100 + math.Sin(float64(i))
+	}
+	bars := bartest.Bars(closes)
+	// an earnings gap on heavy volume outranks a quieter volume spike
+	gap := bars[50]
+	gap.Open, gap.High, gap.Low, gap.Close, gap.Volume = 108, 110, 107, 109, 9000
+	for _, bar := range bars[51:] {
+		bar.Open, bar.High, bar.Low, bar.Close = bar.Open+9, bar.High+9, bar.Low+9, bar.Close+9
+	}
+	bars[40].Volume = 3000
+
+	r, err := FindAnomalies("AAPL.US", bars, 30, "")
+	if err != nil {
+		t.Fatalf("FindAnomalies: %v", err)
+	}
+	if r.Bars != 30 || len(r.Anomalies) < 2 {
+		t.Fatalf("report = %+v", r)
+	}
+	top := r.Anomalies[0]
+	if top.Date != gap.Date || top.NewsFrom != bars[49].Date || top.GapPct < 7 || len(top.Flags) != 3 {
+		t.Fatalf("top = %+v", top)
+	}
+	if r.Anomalies[1].Date != bars[40].Date || r.Anomalies[1].Flags[0] != models.AnomalyVolumeSpike {
+		t.Errorf("second = %+v", r.Anomalies[1])
+	}
+	out := RenderAnomalies(r)
+	if !strings.Contains(out, "Investigate the news behind "+gap.Date) {
+		t.Errorf("render:\n%s", out)
+	}
+}
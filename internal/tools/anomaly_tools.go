@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/structure"
+	"github.com/dyike/CortexGo/models"
+)
+
+// AnomalyToolName is the name agents use to call NewAnomalyTool.
+const AnomalyToolName = "detect_anomalies"
+
+// NewAnomalyTool creates a tool that flags overnight gaps, volume spikes and
+// volatility expansions and ranks the days whose news is worth a look.
+func NewAnomalyTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: AnomalyToolName,
+			Desc: "Flag the unusual trading days of a stock over a look-back window (overnight gaps, volume z-score spikes, volatility expansions) ranked by how unusual they were, with the date to start searching news from for each, so the news behind the biggest moves is investigated first",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"symbol": {
+					Type:     "string",
+					Desc:     "The stock symbol",
+					Required: true,
+				},
+				"lookback": {
+					Type:     "integer",
+					Desc:     fmt.Sprintf("Number of daily bars to scan (%d-%d, default: %d)", structure.MinAnomalyLookback, structure.MaxAnomalyLookback, structure.DefaultAnomalyLookback),
+					Required: false,
+				},
+				"before_date": {
+					Type:     "string",
+					Desc:     "Only use prices on or before this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.AnomalyInput) (*models.AnomalyOutput, error) {
+			r, err := structure.DetectAnomalies(ctx, func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+				return FetchMarketData(ctx, cfg, symbol, count)
+			}, input.Symbol, input.Lookback, strings.TrimSpace(input.BeforeDate))
+			if err != nil {
+				return &models.AnomalyOutput{Result: fmt.Sprintf("Anomaly scan unavailable: %v\n", err)}, nil
+			}
+			return &models.AnomalyOutput{Result: structure.RenderAnomalies(r)}, nil
+		},
+	)
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/catalysts"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// CatalystsToolName is the name agents use to call NewCatalystsTool.
+const CatalystsToolName = "get_upcoming_catalysts"
+
+// NewCatalystsTool creates a tool that lists the earnings dates, IPO lockup
+// expiry and FOMC decisions after the trade date. The catalysts it finds are
+// kept in the trading state, so the report can export them as a calendar.
+func NewCatalystsTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: CatalystsToolName,
+			Desc: "List the scheduled catalysts after the trade date: the stock's next earnings releases with consensus estimates and its estimated IPO lockup expiry (US listings, from Finnhub), and the FOMC rate decisions",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"symbol": {
+					Type:     "string",
+					Desc:     "Stock ticker (e.g. 'AAPL' or 'AAPL.US')",
+					Required: true,
+				},
+				"trade_date": {
+					Type:     "string",
+					Desc:     "List catalysts from this date on (YYYY-MM-DD); pass the current trade date",
+					Required: true,
+				},
+				"days": {
+					Type:     "integer",
+					Desc:     fmt.Sprintf("How many days ahead to look (default %d, max %d)", catalysts.DefaultDays, catalysts.MaxDays),
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.CatalystsInput) (*models.CatalystsOutput, error) {
+			if strings.TrimSpace(input.Symbol) == "" {
+				return nil, fmt.Errorf("symbol parameter is required")
+			}
+			days := input.Days
+			if days <= 0 {
+				days = catalysts.DefaultDays
+			}
+			days = min(days, catalysts.MaxDays)
+			from := strings.TrimSpace(input.TradeDate)
+			symbol := dataflows.NormalizeSymbol(input.Symbol)
+
+			client := dataflows.NewCalendarClient(cfg)
+			found, notes, err := catalysts.Collect(client, symbol, from, days)
+			if errors.Is(err, dataflows.ErrOffline) {
+				return nil, err
+			}
+			if err != nil {
+				return &models.CatalystsOutput{Result: fmt.Sprintf("Catalysts unavailable: %v\n", err)}, nil
+			}
+			p := client.Provenance()
+			p.AsOf = from
+			provenance.Note(ctx, p)
+
+			if len(found) > 0 {
+				_ = compose.ProcessState[*models.TradingState](ctx, func(_ context.Context, state *models.TradingState) error {
+					state.Catalysts = catalysts.Merge(state.Catalysts, found)
+					return nil
+				})
+			}
+			return &models.CatalystsOutput{Result: catalysts.Render(symbol, from, days, found, notes)}, nil
+		},
+	)
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/portfolio"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+// CorrelationToolName is the name agents use to call NewCorrelationTool.
+const CorrelationToolName = "get_correlation_matrix"
+
+// NewCorrelationTool creates a tool that computes pairwise return
+// correlations across a list of symbols and flags groups that move as one.
+// Given a single symbol it compares it against the synced holdings.
+func NewCorrelationTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: CorrelationToolName,
+			Desc: "Compute pairwise correlations of daily returns across several stocks and flag concentration risk (names that move together and add up to a large part of the book). Pass a single symbol to compare it with the account's current holdings. Symbols quoted in different currencies are compared in one currency at daily exchange rates",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"symbols": {
+					Type:     "array",
+					Desc:     "Tickers to compare (e.g. ['NVDA.US', 'AMD.US']); a single ticker is compared with the current holdings",
+					ElemInfo: &schema.ParameterInfo{Type: "string"},
+					Required: true,
+				},
+				"window": {
+					Type:     "integer",
+					Desc:     fmt.Sprintf("Number of daily returns to use (%d-%d, default: %d)", portfolio.MinCorrelationWindow, portfolio.MaxCorrelationWindow, portfolio.DefaultCorrelationWindow),
+					Required: false,
+				},
+				"before_date": {
+					Type:     "string",
+					Desc:     "Only use prices on or before this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.CorrelationInput) (*models.CorrelationOutput, error) {
+			symbols := input.Symbols
+			var weights map[string]float64
+			currency := ""
+			if len(symbols) == 1 {
+				held, w, base := holdings(ctx, cfg)
+				if len(held) == 0 {
+					return &models.CorrelationOutput{Result: "No portfolio holdings are synced; pass at least two symbols to compare.\n"}, nil
+				}
+				symbols, weights, currency = append(symbols, held...), w, base
+			}
+			m, err := CompareCorrelations(ctx, cfg, symbols, input.Window, strings.TrimSpace(input.BeforeDate), weights, currency)
+			if err != nil {
+				return &models.CorrelationOutput{Result: fmt.Sprintf("Correlation matrix unavailable: %v\n", err)}, nil
+			}
+			return &models.CorrelationOutput{Result: portfolio.RenderCorrelation(m)}, nil
+		},
+	)
+}
+
+// holdings returns the synced position symbols, their book weights and the
+// book's base currency.
+func holdings(ctx context.Context, cfg *config.Config) ([]string, map[string]float64, string) {
+	store, err := storage.GetSQLiteStore()
+	if err != nil {
+		return nil, nil, ""
+	}
+	p, err := store.GetPortfolio(ctx)
+	if err != nil {
+		return nil, nil, ""
+	}
+	symbols := make([]string, 0, len(p.Positions))
+	for _, pos := range p.Positions {
+		symbols = append(symbols, pos.Symbol)
+	}
+	return symbols, BookWeights(cfg, p), portfolio.BaseCurrency(p)
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// toolSources maps tools to the remote data source they read. Tools over
+// local data (past analyses, ingested documents) are not listed and never
+// degrade.
+var toolSources = map[string]string{
+	"get_market_data":                   "longport",
+	"get_stock_stats_indicators_window": "longport",
+	CorrelationToolName:                 "longport",
+	MarketStructureToolName:             "longport",
+	AnomalyToolName:                     "longport",
+	VolatilityToolName:                  "longport",
+	IntermarketToolName:                 "longport",
+	"search_google_news":                "google_news",
+	"get_google_finance_news":           "google_news",
+	"get_google_stock_news":             "google_news",
+	TopHeadlinesToolName:                "google_news",
+	NewsTimelineToolName:                "google_news",
+	"get_reddit_subreddit_posts":        "reddit",
+	"search_reddit_posts":               "reddit",
+	"get_reddit_stock_mentions":         "reddit",
+	"get_reddit_finance_news":           "reddit",
+	EarningsCallToolName:                "transcripts",
+	YieldCurveToolName:                  "treasury",
+	InsiderSentimentToolName:            "insider",
+	CatalystsToolName:                   "calendar",
+}
+
+// MarketWideTools are the tools whose output does not depend on the symbol
+// analysed. A batch shares their outputs between its analyses (see
+// dataflows.Session).
+var MarketWideTools = []string{
+	"get_google_finance_news",
+	TopHeadlinesToolName,
+	"get_reddit_finance_news",
+	YieldCurveToolName,
+	VolatilityToolName,
+}
+
+// SourceOf returns the remote data source a tool reads, or "" for local tools.
+func SourceOf(toolName string) string {
+	return toolSources[toolName]
+}
+
+// WithDegradation puts every remote tool of agent behind its data source's
+// circuit breaker. When a source is down (every retry failed, or it failed
+// repeatedly and its breaker is open) the tool does not fail the run:
+// it returns a "degraded" result telling the agent to carry on without that
+// source, and the outage is recorded in the trading state so the decision
+// makers can weight the agent's report down.
+//
+// Every tool, local or remote, is also isolated from panics: a tool that
+// panics (say, a nil dereference while parsing a page) returns a "failed"
+// result instead of taking down the run, and the panic is recorded in the
+// trading state's node failures.
+func WithDegradation(agent string, tools []tool.BaseTool) []tool.BaseTool {
+	wrapped := make([]tool.BaseTool, len(tools))
+	for i, t := range tools {
+		if inv, ok := t.(tool.InvokableTool); ok {
+			wrapped[i] = &degradingTool{InvokableTool: inv, agent: agent}
+		} else {
+			wrapped[i] = t
+		}
+	}
+	return wrapped
+}
+
+type degradingTool struct {
+	tool.InvokableTool
+	agent string
+}
+
+func (t *degradingTool) InvokableRun(ctx context.Context, arguments string, opts ...tool.Option) (string, error) {
+	name := ""
+	if info, err := t.Info(ctx); err == nil {
+		name = info.Name
+	}
+	source := SourceOf(name)
+	if source == "" {
+		return t.run(ctx, name, arguments, opts...)
+	}
+
+	var out string
+	err := dataflows.CallSource(source, func() error {
+		var err error
+		out, err = t.run(ctx, name, arguments, opts...)
+		return err
+	})
+	if err == nil || !dataflows.IsOutage(err) {
+		return out, err
+	}
+
+	_ = compose.ProcessState[*models.TradingState](ctx, func(_ context.Context, state *models.TradingState) error {
+		state.SourceOutages = append(state.SourceOutages, &models.SourceOutage{
+			Agent: t.agent, Tool: name, Source: source, Error: err.Error(), At: time.Now(),
+		})
+		return nil
+	})
+	degraded, _ := json.Marshal(models.DegradedToolOutput{
+		Status: "degraded",
+		Source: source,
+		Error:  err.Error(),
+		Result: fmt.Sprintf("The %s data source is unavailable right now, so %s returned no data. Do not retry tools of this source; continue with your other tools and state in your report which inputs were missing.", source, name),
+	})
+	return string(degraded), nil
+}
+
+// run calls the tool, turning a panic into a "failed" result. The result is
+// not an error, so a panic neither fails the run nor counts against the
+// source's breaker.
+func (t *degradingTool) run(ctx context.Context, name, arguments string, opts ...tool.Option) (out string, err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		agents.RecordFailure(ctx, &models.NodeFailure{
+			Agent: t.agent, Tool: name, Error: fmt.Sprint(r), Stack: string(debug.Stack()),
+		})
+		failed, _ := json.Marshal(models.FailedToolOutput{
+			Status: "failed",
+			Tool:   name,
+			Error:  fmt.Sprint(r),
+			Result: fmt.Sprintf("%s crashed on this input and returned no data. Do not call it again; continue with your other tools and state in your report which inputs were missing.", name),
+		})
+		out, err = string(failed), nil
+	}()
+	return t.InvokableTool.InvokableRun(ctx, arguments, opts...)
+}
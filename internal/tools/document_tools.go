@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/memory"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+// QueryDocumentsToolName is the name agents use to call NewQueryDocumentsTool.
+const QueryDocumentsToolName = "query_documents"
+
+// documentSnippetRunes bounds each passage returned to the model.
+const documentSnippetRunes = 800
+
+// NewQueryDocumentsTool creates a tool that retrieves passages from
+// documents ingested with documents.ingest, such as annual reports, broker
+// notes and earnings slides.
+func NewQueryDocumentsTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: QueryDocumentsToolName,
+			Desc: "Search ingested company documents (annual reports, broker research, earnings slides, filings) for passages that support or refute a fundamental claim, with page references",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"query": {
+					Type:     "string",
+					Desc:     "What to look for, e.g. 'gross margin by segment' or '研发费用 同比'",
+					Required: true,
+				},
+				"symbol": {
+					Type:     "string",
+					Desc:     "Restrict to one ticker (e.g. 'NVDA.US'); documents without a ticker are always included",
+					Required: false,
+				},
+				"kind": {
+					Type:     "string",
+					Desc:     "Restrict to one document type: annual_report, broker_report, earnings_slides, filing or other",
+					Required: false,
+				},
+				"limit": {
+					Type:     "integer",
+					Desc:     "Maximum number of passages to return (1-10, default: 5)",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.QueryDocumentsInput) (*models.QueryDocumentsOutput, error) {
+			if strings.TrimSpace(input.Query) == "" {
+				return nil, fmt.Errorf("query parameter is required")
+			}
+			limit := input.Limit
+			if limit <= 0 {
+				limit = 5
+			}
+			if limit > 10 {
+				limit = 10
+			}
+
+			// documents are optional; report them unavailable instead of failing the run
+			store, err := storage.GetSQLiteStore()
+			if err != nil {
+				return &models.QueryDocumentsOutput{Result: fmt.Sprintf("Ingested documents are unavailable: %v\n", err)}, nil
+			}
+			hits, err := memory.SearchDocuments(ctx, store, memory.DefaultEmbedder, memory.DocumentQuery{
+				Text:   input.Query,
+				Symbol: strings.TrimSpace(input.Symbol),
+				Kind:   strings.TrimSpace(input.Kind),
+				Limit:  limit,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("query documents: %w", err)
+			}
+			provenance.Note(ctx, models.Provenance{Source: "documents", Mode: models.ProvenanceLocal})
+
+			var result strings.Builder
+			result.WriteString(fmt.Sprintf("# Document passages matching \"%s\"\n\n", input.Query))
+			if len(hits) == 0 {
+				result.WriteString("No ingested documents matched. Do not cite filings you have not seen; state which figures are unverified.\n")
+			}
+			for i, h := range hits {
+				result.WriteString(fmt.Sprintf("## %d. %s", i+1, h.Title))
+				if h.Page > 0 {
+					result.WriteString(fmt.Sprintf(", p.%d", h.Page))
+				}
+				result.WriteString("\n")
+				result.WriteString(fmt.Sprintf("**Type:** %s | ", h.Kind))
+				if h.Symbol != "" {
+					result.WriteString(fmt.Sprintf("**Symbol:** %s | ", h.Symbol))
+				}
+				result.WriteString(fmt.Sprintf("**Relevance:** %.2f\n\n", h.Score))
+				content := h.Content
+				if utf8.RuneCountInString(content) > documentSnippetRunes {
+					content = string([]rune(content)[:documentSnippetRunes]) + "..."
+				}
+				result.WriteString(content)
+				result.WriteString("\n\n---\n\n")
+			}
+
+			return &models.QueryDocumentsOutput{Results: hits, Result: result.String()}, nil
+		},
+	)
+}
@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// noteNews records how client served the tool call in ctx, dated by the
+// newest article.
+func noteNews(ctx context.Context, client *dataflows.GoogleNewsClient, articles []*dataflows.NewsArticle) {
+	p := client.Provenance()
+	p.AsOf = dataflows.ArticlesAsOf(articles)
+	provenance.Note(ctx, p)
+}
+
+// notePosts records how client served the tool call in ctx, dated by the
+// newest post.
+func notePosts(ctx context.Context, client *dataflows.RedditClient, posts []*dataflows.RedditPost) {
+	p := client.Provenance()
+	p.AsOf = dataflows.PostsAsOf(posts)
+	provenance.Note(ctx, p)
+}
+
+// noteMarketData records where the bars of the tool call in ctx came from,
+// dated by the latest bar.
+func noteMarketData(ctx context.Context, mode string, fetchedAt time.Time, data []*models.MarketData) {
+	p := models.Provenance{Source: "longport", Mode: mode, FetchedAt: fetchedAt}
+	switch mode {
+	case models.ProvenanceCache:
+		p.CacheHits = 1
+	case models.ProvenanceLive:
+		p.CacheMisses = 1
+	}
+	for _, d := range data {
+		if d != nil && d.Date > p.AsOf {
+			p.AsOf = d.Date
+		}
+	}
+	provenance.Note(ctx, p)
+}
+
+// newsClock is the time article ages are measured against. A seeded run
+// measures them against when its pinned snapshot was fetched, so the same
+// snapshot renders the same text on every run.
+func newsClock(cfg *config.Config, client *dataflows.GoogleNewsClient) time.Time {
+	if cfg.Seed > 0 {
+		return client.Provenance().FetchedAt
+	}
+	return time.Now()
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/fx"
+	"github.com/dyike/CortexGo/internal/portfolio"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// fxSession converts through the daily reference rates for one tool call and
+// records where the rates came from once the call is done.
+type fxSession struct {
+	*fx.Converter
+	client *dataflows.FXClient
+}
+
+func newFXSession(cfg *config.Config) *fxSession {
+	client := dataflows.NewFXClient(cfg)
+	return &fxSession{Converter: fx.New(client), client: client}
+}
+
+// fetcher returns market data in the to currency.
+func (s *fxSession) fetcher(cfg *config.Config, to string) dataflows.BarFetcher {
+	return s.Fetcher(func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+		return FetchMarketData(ctx, cfg, symbol, count)
+	}, to)
+}
+
+// convert converts book amounts at today's rate, for portfolio.Weights.
+func (s *fxSession) convert(amount float64, from, to string) (float64, error) {
+	return s.Amount(amount, from, to, "")
+}
+
+// note adds the FX source to the call's provenance when any rate was read.
+func (s *fxSession) note(ctx context.Context) {
+	if p := s.client.Provenance(); p.CacheHits+p.CacheMisses > 0 {
+		provenance.Note(ctx, p)
+	}
+}
+
+// CompareCorrelations runs portfolio.Correlations with every series in one
+// currency: to, or the first symbol's when empty, so an HKD listing and a USD
+// one are compared on the returns a holder in that currency would see.
+func CompareCorrelations(ctx context.Context, cfg *config.Config, symbols []string, window int, asOf string, weights map[string]float64, to string) (*models.CorrelationMatrix, error) {
+	if to == "" && len(symbols) > 0 {
+		to = dataflows.CurrencyOf(symbols[0])
+	}
+	s := newFXSession(cfg)
+	defer s.note(ctx)
+	m, err := portfolio.Correlations(ctx, s.fetcher(cfg, to), symbols, window, asOf, weights)
+	if err != nil {
+		return nil, err
+	}
+	if notes := s.Notes(); len(notes) > 0 {
+		m.Currency, m.Notes = to, notes
+	}
+	return m, nil
+}
+
+// BookWeights returns the positions' shares of the book with foreign
+// positions converted to its base currency.
+func BookWeights(cfg *config.Config, p *models.Portfolio) map[string]float64 {
+	return portfolio.Weights(p, newFXSession(cfg).convert)
+}
@@ -44,7 +44,7 @@ func NewGoogleNewsSearchTool(cfg *config.Config) tool.BaseTool {
 				},
 				"sort_by": {
 					Type:     "string",
-					Desc:     "Sort method: 'date' or 'relevance' (default: 'date')",
+					Desc:     "Sort method: 'date', 'relevance' or 'quality' (most reliable sources first) (default: 'date')",
 					Required: false,
 				},
 				"category": {
@@ -57,6 +57,11 @@ func NewGoogleNewsSearchTool(cfg *config.Config) tool.BaseTool {
 					Desc:     "Specific news site to search (e.g., 'reuters.com', 'bloomberg.com')",
 					Required: false,
 				},
+				"min_quality": {
+					Type:     "number",
+					Desc:     "Drop articles whose source quality is below this score (0-1): wire services 1.0, major outlets 0.85, aggregators 0.6, press releases 0.5, unknown 0.4, opinion/SEO sites 0.3",
+					Required: false,
+				},
 				"days_back": {
 					Type:     "integer",
 					Desc:     "Number of days to look back for news (default: 7)",
@@ -110,7 +115,7 @@ func NewGoogleNewsSearchTool(cfg *config.Config) tool.BaseTool {
 				StartDate:  startDate,
 				EndDate:    endDate,
 				MaxResults: maxResults,
-				SortBy:     sortBy,
+				SortBy:     searchSortBy(sortBy),
 				Category:   input.Category,
 				Site:       input.Site,
 			}
@@ -121,9 +126,11 @@ func NewGoogleNewsSearchTool(cfg *config.Config) tool.BaseTool {
 			// Search for news
 			articles, err := googleNewsClient.GetGoogleNews(params, cfg)
 			if err != nil {
-				return nil, fmt.Errorf("failed to search Google News: %v", err)
+				return nil, fmt.Errorf("failed to search Google News: %w", err)
 			}
+			noteNews(ctx, googleNewsClient, articles)
 
+			articles = rankByQuality(articles, input.MinQuality, sortBy == "quality")
 			log.Printf("Found %d Google News articles for query: %s", len(articles), input.Query)
 
 			// Format results
@@ -136,8 +143,8 @@ func NewGoogleNewsSearchTool(cfg *config.Config) tool.BaseTool {
 			} else {
 				for i, article := range articles {
 					result.WriteString(fmt.Sprintf("## %d. %s\n", i+1, article.Title))
-					result.WriteString(fmt.Sprintf("**Source:** %s | **Published:** %s\n",
-						article.Source, article.PublishedAt.Format("2006-01-02 15:04")))
+					result.WriteString(fmt.Sprintf("**Source:** %s | **Published:** %s%s\n",
+						sourceLabel(article), article.PublishedAt.Format("2006-01-02 15:04"), sessionNote("", article.PublishedAt)))
 					result.WriteString(fmt.Sprintf("**URL:** %s\n", article.URL))
 
 					if article.Content != "" && len(article.Content) > 200 {
@@ -164,7 +171,7 @@ func NewGoogleNewsSearchTool(cfg *config.Config) tool.BaseTool {
 			}
 
 			return &models.NewsOutput{
-				Articles: convertToModelsNewsArticles(articles),
+				Articles: articles,
 				Result:   result.String(),
 			}, nil
 		},
@@ -183,6 +190,11 @@ func NewGoogleFinanceNewsTool(cfg *config.Config) tool.BaseTool {
 					Desc:     "Maximum number of articles to return (default: 20)",
 					Required: false,
 				},
+				"min_quality": {
+					Type:     "number",
+					Desc:     "Drop articles whose source quality is below this score (0-1): wire services 1.0, major outlets 0.85, aggregators 0.6, press releases 0.5, unknown 0.4, opinion/SEO sites 0.3",
+					Required: false,
+				},
 			}),
 		},
 		func(ctx context.Context, input models.FinanceNewsInput) (*models.NewsOutput, error) {
@@ -200,9 +212,11 @@ func NewGoogleFinanceNewsTool(cfg *config.Config) tool.BaseTool {
 			// Get finance news
 			articles, err := googleNewsClient.GetFinanceNews(maxResults, cfg)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get finance news: %v", err)
+				return nil, fmt.Errorf("failed to get finance news: %w", err)
 			}
+			noteNews(ctx, googleNewsClient, articles)
 
+			articles = rankByQuality(articles, input.MinQuality, true)
 			log.Printf("Retrieved %d finance articles from Google News", len(articles))
 
 			// Format results
@@ -214,7 +228,7 @@ func NewGoogleFinanceNewsTool(cfg *config.Config) tool.BaseTool {
 				result.WriteString("No finance news found.\n")
 			} else {
 				// Group by recency
-				now := time.Now()
+				now := newsClock(cfg, googleNewsClient)
 				var recent, older []*dataflows.NewsArticle
 
 				for _, article := range articles {
@@ -230,8 +244,8 @@ func NewGoogleFinanceNewsTool(cfg *config.Config) tool.BaseTool {
 					result.WriteString("## 🔥 Breaking News (Last 6 Hours)\n\n")
 					for i, article := range recent {
 						result.WriteString(fmt.Sprintf("### %d. %s\n", i+1, article.Title))
-						result.WriteString(fmt.Sprintf("**%s** - %s\n",
-							article.Source, article.PublishedAt.Format("15:04")))
+						result.WriteString(fmt.Sprintf("**%s** - %s%s\n",
+							sourceLabel(article), article.PublishedAt.Format("15:04"), sessionNote("", article.PublishedAt)))
 						result.WriteString(fmt.Sprintf("**URL:** %s\n", article.URL))
 
 						if article.Content != "" && len(article.Content) > 150 {
@@ -248,8 +262,8 @@ func NewGoogleFinanceNewsTool(cfg *config.Config) tool.BaseTool {
 					result.WriteString("## 📈 Recent Financial News\n\n")
 					for i, article := range older {
 						result.WriteString(fmt.Sprintf("### %d. %s\n", i+1, article.Title))
-						result.WriteString(fmt.Sprintf("**%s** - %s\n",
-							article.Source, article.PublishedAt.Format("2006-01-02 15:04")))
+						result.WriteString(fmt.Sprintf("**%s** - %s%s\n",
+							sourceLabel(article), article.PublishedAt.Format("2006-01-02 15:04"), sessionNote("", article.PublishedAt)))
 						result.WriteString(fmt.Sprintf("**URL:** %s\n", article.URL))
 
 						if article.Content != "" && len(article.Content) > 100 {
@@ -262,32 +276,11 @@ func NewGoogleFinanceNewsTool(cfg *config.Config) tool.BaseTool {
 				}
 
 				// Add market insights
-				result.WriteString("## 💡 Market Insights\n\n")
-				result.WriteString("**Key Topics Trending:**\n")
-
-				// Simple keyword analysis
-				keywords := make(map[string]int)
-				for _, article := range articles {
-					words := strings.Fields(strings.ToLower(article.Title))
-					for _, word := range words {
-						if len(word) > 4 && !isCommonWord(word) {
-							keywords[word]++
-						}
-					}
-				}
-
-				// Show top keywords
-				count := 0
-				for word, freq := range keywords {
-					if freq >= 2 && count < 5 {
-						result.WriteString(fmt.Sprintf("- %s (mentioned %d times)\n", word, freq))
-						count++
-					}
-				}
+				result.WriteString(marketInsights(articles))
 			}
 
 			return &models.NewsOutput{
-				Articles: convertToModelsNewsArticles(articles),
+				Articles: articles,
 				Result:   result.String(),
 			}, nil
 		},
@@ -311,6 +304,11 @@ func NewGoogleStockNewsTool(cfg *config.Config) tool.BaseTool {
 					Desc:     "Maximum number of articles to return (default: 15)",
 					Required: false,
 				},
+				"min_quality": {
+					Type:     "number",
+					Desc:     "Drop articles whose source quality is below this score (0-1): wire services 1.0, major outlets 0.85, aggregators 0.6, press releases 0.5, unknown 0.4, opinion/SEO sites 0.3",
+					Required: false,
+				},
 			}),
 		},
 		func(ctx context.Context, input models.StockNewsInput) (*models.NewsOutput, error) {
@@ -332,9 +330,11 @@ func NewGoogleStockNewsTool(cfg *config.Config) tool.BaseTool {
 			// Get stock news
 			articles, err := googleNewsClient.GetStockNews(input.Symbol, maxResults, cfg)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get stock news: %v", err)
+				return nil, fmt.Errorf("failed to get stock news: %w", err)
 			}
+			noteNews(ctx, googleNewsClient, articles)
 
+			articles = rankByQuality(articles, input.MinQuality, true)
 			symbol := strings.ToUpper(input.Symbol)
 			log.Printf("Found %d news articles for %s", len(articles), symbol)
 
@@ -352,7 +352,7 @@ func NewGoogleStockNewsTool(cfg *config.Config) tool.BaseTool {
 				result.WriteString(fmt.Sprintf("*Found %d relevant articles*\n\n", len(articles)))
 
 				// Categorize news by recency
-				now := time.Now()
+				now := newsClock(cfg, googleNewsClient)
 				var breaking, recent, older []*dataflows.NewsArticle
 
 				for _, article := range articles {
@@ -370,8 +370,8 @@ func NewGoogleStockNewsTool(cfg *config.Config) tool.BaseTool {
 					result.WriteString("## 🚨 Breaking News (Last 2 Hours)\n\n")
 					for i, article := range breaking {
 						result.WriteString(fmt.Sprintf("### %d. %s\n", i+1, article.Title))
-						result.WriteString(fmt.Sprintf("**%s** - %s ago\n",
-							article.Source, formatTimeSince(article.PublishedAt)))
+						result.WriteString(fmt.Sprintf("**%s** - %s ago%s\n",
+							sourceLabel(article), formatTimeSince(now, article.PublishedAt), sessionNote(input.Symbol, article.PublishedAt)))
 						result.WriteString(fmt.Sprintf("**URL:** %s\n", article.URL))
 						if article.Content != "" {
 							result.WriteString(fmt.Sprintf("**Summary:** %s\n", article.Content))
@@ -385,8 +385,8 @@ func NewGoogleStockNewsTool(cfg *config.Config) tool.BaseTool {
 					result.WriteString("## 📰 Today's News\n\n")
 					for i, article := range recent {
 						result.WriteString(fmt.Sprintf("### %d. %s\n", i+1, article.Title))
-						result.WriteString(fmt.Sprintf("**%s** - %s\n",
-							article.Source, article.PublishedAt.Format("15:04")))
+						result.WriteString(fmt.Sprintf("**%s** - %s%s\n",
+							sourceLabel(article), article.PublishedAt.Format("15:04"), sessionNote(input.Symbol, article.PublishedAt)))
 						result.WriteString(fmt.Sprintf("**URL:** %s\n", article.URL))
 						if article.Content != "" && len(article.Content) > 150 {
 							result.WriteString(fmt.Sprintf("**Summary:** %s...\n", article.Content[:150]))
@@ -406,8 +406,8 @@ func NewGoogleStockNewsTool(cfg *config.Config) tool.BaseTool {
 							break
 						}
 						result.WriteString(fmt.Sprintf("### %d. %s\n", i+1, article.Title))
-						result.WriteString(fmt.Sprintf("**%s** - %s\n",
-							article.Source, article.PublishedAt.Format("2006-01-02")))
+						result.WriteString(fmt.Sprintf("**%s** - %s%s\n",
+							sourceLabel(article), article.PublishedAt.Format("2006-01-02"), sessionNote(input.Symbol, article.PublishedAt)))
 						result.WriteString(fmt.Sprintf("**URL:** %s\n", article.URL))
 						result.WriteString("\n")
 					}
@@ -439,11 +439,12 @@ func NewGoogleStockNewsTool(cfg *config.Config) tool.BaseTool {
 					if topSource != "" {
 						result.WriteString(fmt.Sprintf("- **Most Active Source:** %s (%d articles)\n", topSource, maxCount))
 					}
+					result.WriteString(fmt.Sprintf("- **Source Quality:** %s\n", tierBreakdown(articles)))
 				}
 			}
 
 			return &models.NewsOutput{
-				Articles: convertToModelsNewsArticles(articles),
+				Articles: articles,
 				Result:   result.String(),
 			}, nil
 		},
@@ -452,25 +453,9 @@ func NewGoogleStockNewsTool(cfg *config.Config) tool.BaseTool {
 
 // Helper functions
 
-// convertToModelsNewsArticles converts dataflows.NewsArticle to models.NewsArticle
-func convertToModelsNewsArticles(articles []*dataflows.NewsArticle) []*models.NewsArticle {
-	modelArticles := make([]*models.NewsArticle, len(articles))
-	for i, a := range articles {
-		modelArticles[i] = &models.NewsArticle{
-			Title:       a.Title,
-			Content:     a.Content,
-			URL:         a.URL,
-			Source:      a.Source,
-			PublishedAt: a.PublishedAt,
-			Keywords:    a.Keywords,
-		}
-	}
-	return modelArticles
-}
-
-// formatTimeSince formats time duration in human-readable format
-func formatTimeSince(t time.Time) string {
-	duration := time.Since(t)
+// formatTimeSince formats the time from t to now in human-readable format
+func formatTimeSince(now, t time.Time) string {
+	duration := now.Sub(t)
 
 	if duration < time.Minute {
 		return "just now"
@@ -495,20 +480,175 @@ func formatTimeSince(t time.Time) string {
 	}
 }
 
-// isCommonWord checks if a word is a common word to filter out
-func isCommonWord(word string) bool {
-	commonWords := map[string]bool{
-		"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
-		"you": true, "all": true, "can": true, "had": true, "her": true, "was": true,
-		"one": true, "our": true, "out": true, "day": true, "get": true, "has": true,
-		"him": true, "how": true, "man": true, "new": true, "now": true, "old": true,
-		"see": true, "two": true, "way": true, "who": true, "boy": true, "did": true,
-		"its": true, "let": true, "put": true, "say": true, "she": true, "too": true,
-		"use": true, "will": true, "with": true, "this": true, "that": true, "from": true,
-		"they": true, "know": true, "want": true, "been": true, "good": true, "much": true,
-		"some": true, "time": true, "very": true, "when": true, "come": true, "here": true,
-		"just": true, "like": true, "long": true, "make": true, "many": true, "over": true,
-		"such": true, "take": true, "than": true, "them": true, "well": true, "were": true,
+// TopHeadlinesToolName is the name agents use to call NewTopHeadlinesTool.
+const TopHeadlinesToolName = "get_top_headlines"
+
+// NewTopHeadlinesTool creates a tool that returns the current front-page or
+// topic stories of a Google News country edition, without a search query,
+// for market-wide context.
+func NewTopHeadlinesTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: TopHeadlinesToolName,
+			Desc: "Get today's top headlines from a Google News edition (front page, world, nation, business, markets, economy, ...) to see what the whole market is reacting to, independent of any single stock",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"topic": {
+					Type:     "string",
+					Desc:     fmt.Sprintf("Topic feed, one of: %s (default: %s)", strings.Join(dataflows.GoogleNewsTopicNames(), ", "), dataflows.DefaultHeadlineTopic),
+					Required: false,
+				},
+				"edition": {
+					Type:     "string",
+					Desc:     fmt.Sprintf("Country edition, one of: %s (default: %s); use the edition of the stock's home market", strings.Join(dataflows.GoogleNewsEditionCodes(), ", "), dataflows.DefaultHeadlineEdition),
+					Required: false,
+				},
+				"max_results": {
+					Type:     "integer",
+					Desc:     "Maximum number of headlines to return (1-30, default: 15)",
+					Required: false,
+				},
+				"min_quality": {
+					Type:     "number",
+					Desc:     "Drop articles whose source quality is below this score (0-1): wire services 1.0, major outlets 0.85, aggregators 0.6, press releases 0.5, unknown 0.4, opinion/SEO sites 0.3",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.TopHeadlinesInput) (*models.NewsOutput, error) {
+			topic := strings.ToLower(strings.TrimSpace(input.Topic))
+			if topic == "" {
+				topic = dataflows.DefaultHeadlineTopic
+			}
+			edition := strings.ToUpper(strings.TrimSpace(input.Edition))
+			if edition == "" {
+				edition = dataflows.DefaultHeadlineEdition
+			}
+			maxResults := input.MaxResults
+			if maxResults <= 0 {
+				maxResults = 15
+			}
+			if maxResults > 30 {
+				maxResults = 30
+			}
+
+			client := dataflows.NewGoogleNewsClient(cfg)
+			articles, err := client.GetTopHeadlines(topic, edition, maxResults)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get top headlines: %w", err)
+			}
+			noteNews(ctx, client, articles)
+			articles = rankByQuality(articles, input.MinQuality, false)
+			log.Printf("Found %d %s headlines for edition %s", len(articles), topic, edition)
+
+			var result strings.Builder
+			result.WriteString(fmt.Sprintf("# Top Headlines: %s (%s edition)\n\n", topic, edition))
+			if len(articles) == 0 {
+				result.WriteString("No headlines found for this topic and edition.\n")
+			}
+			for i, article := range articles {
+				result.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, article.Title))
+				result.WriteString(fmt.Sprintf("   %s | %s%s\n",
+					sourceLabel(article), article.PublishedAt.Format("2006-01-02 15:04"), sessionNote("", article.PublishedAt)))
+				result.WriteString(fmt.Sprintf("   %s\n", article.URL))
+			}
+
+			return &models.NewsOutput{
+				Articles: articles,
+				Result:   result.String(),
+			}, nil
+		},
+	)
+}
+
+// rankByQuality drops articles from sources scoring below minQuality and,
+// when byQuality is set, lists the most reliable sources first.
+func rankByQuality(articles []*dataflows.NewsArticle, minQuality float64, byQuality bool) []*dataflows.NewsArticle {
+	articles = dataflows.FilterByQuality(articles, minQuality)
+	if byQuality {
+		dataflows.SortByQuality(articles)
+	}
+	return articles
+}
+
+// searchSortBy maps the tool's sort option to the client's: quality is
+// applied after the search, which itself runs by date.
+func searchSortBy(sortBy string) string {
+	if sortBy == "quality" {
+		return "date"
+	}
+	return sortBy
+}
+
+// sourceLabel shows an article's source with its reliability tier so the
+// agents can tell a wire report from an opinion piece.
+func sourceLabel(article *dataflows.NewsArticle) string {
+	if article.SourceTier == "" {
+		return article.Source
+	}
+	return fmt.Sprintf("%s [%s]", article.Source, article.SourceTier)
+}
+
+// tierBreakdown counts articles per source tier, e.g. "2 wire, 3 opinion".
+func tierBreakdown(articles []*dataflows.NewsArticle) string {
+	counts := map[string]int{}
+	for _, a := range articles {
+		counts[a.SourceTier]++
+	}
+	var parts []string
+	for _, tier := range []string{dataflows.SourceTierWire, dataflows.SourceTierMajor, dataflows.SourceTierAggregator, dataflows.SourceTierPressRelease, dataflows.SourceTierOpinion, dataflows.SourceTierUnknown} {
+		if counts[tier] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[tier], tier))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// marketInsights summarises the themes running through a set of articles:
+// the top keyphrases across them and the stories several outlets cover.
+func marketInsights(articles []*dataflows.NewsArticle) string {
+	docs := make([]string, len(articles))
+	for i, article := range articles {
+		docs[i] = article.Title + ". " + article.Content
+	}
+
+	var b strings.Builder
+	b.WriteString("## 💡 Market Insights\n\n")
+	keywords := dataflows.ExtractKeywords(docs, 8)
+	if len(keywords) == 0 {
+		b.WriteString("No theme is shared by more than one article.\n")
+		return b.String()
+	}
+	b.WriteString("**Key Topics Trending:**\n")
+	for _, k := range keywords {
+		b.WriteString(fmt.Sprintf("- %s (%d articles)\n", k.Phrase, k.Articles))
+	}
+
+	clusters := dataflows.ClusterTopics(docs)
+	if len(clusters) > 0 {
+		b.WriteString("\n**Story Clusters:**\n")
+	}
+	for i, c := range clusters {
+		if i >= 5 {
+			break
+		}
+		b.WriteString(fmt.Sprintf("- %s (%d articles)\n", c.Label, len(c.Articles)))
+		for j, idx := range c.Articles {
+			if j >= 3 {
+				b.WriteString(fmt.Sprintf("  - ... and %d more\n", len(c.Articles)-3))
+				break
+			}
+			b.WriteString(fmt.Sprintf("  - %s (%s)\n", articles[idx].Title, sourceLabel(articles[idx])))
+		}
+	}
+	return b.String()
+}
+
+// sessionNote labels a publish time with the session of symbol's market it
+// fell in (US hours when symbol is empty): news out of regular hours is first
+// traded in thin extended sessions and priced in fully at the next open.
+func sessionNote(symbol string, t time.Time) string {
+	if session := dataflows.MarketSession(symbol, t); session != "" {
+		return " (" + session + ")"
 	}
-	return commonWords[strings.ToLower(word)]
+	return ""
 }
@@ -0,0 +1,87 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// InsiderSentimentToolName is the name agents use to call NewInsiderSentimentTool.
+const InsiderSentimentToolName = "get_insider_sentiment"
+
+// insiderTableMonths bounds the monthly rows shown to the model.
+const insiderTableMonths = 12
+
+// NewInsiderSentimentTool creates a tool that reports Finnhub's monthly
+// insider sentiment (MSPR and net shares) for a US stock.
+func NewInsiderSentimentTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: InsiderSentimentToolName,
+			Desc: "Get monthly insider sentiment for a US-listed stock from Finnhub: the Monthly Share Purchase Ratio (MSPR, -100 all selling to 100 all buying) and net shares bought or sold by insiders, with 3- and 12-month averages and the buying or selling trend",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"symbol": {
+					Type:     "string",
+					Desc:     "Stock ticker (e.g. 'AAPL' or 'AAPL.US')",
+					Required: true,
+				},
+				"before_date": {
+					Type:     "string",
+					Desc:     "Only months that ended before this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.InsiderSentimentInput) (*models.InsiderSentimentOutput, error) {
+			if strings.TrimSpace(input.Symbol) == "" {
+				return nil, fmt.Errorf("symbol parameter is required")
+			}
+			before := strings.TrimSpace(input.BeforeDate)
+			client := dataflows.NewInsiderClient(cfg)
+			months, err := client.GetInsiderSentiment(input.Symbol, before)
+			if errors.Is(err, dataflows.ErrOffline) {
+				return nil, err
+			}
+			if err != nil {
+				// insider data is optional; tell the model instead of failing the run
+				return &models.InsiderSentimentOutput{Result: fmt.Sprintf("Insider sentiment unavailable: %v\n", err)}, nil
+			}
+			s := dataflows.SummarizeInsiderSentiment(dataflows.NormalizeSymbol(input.Symbol), before, months)
+			p := client.Provenance()
+			p.AsOf = s.AsOf
+			provenance.Note(ctx, p)
+			return &models.InsiderSentimentOutput{Result: formatInsiderSentiment(s)}, nil
+		},
+	)
+}
+
+// formatInsiderSentiment renders the summary and the latest monthly rows.
+func formatInsiderSentiment(s *models.InsiderSentiment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s Insider Sentiment\n\n", s.Symbol)
+	if len(s.Months) == 0 {
+		b.WriteString("No insider transactions were reported in the last two years.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "**Through:** %s | **Trend:** %s | **Source:** Finnhub\n\n", s.AsOf, s.Trend)
+	fmt.Fprintf(&b, "- **MSPR:** %+.1f over 3 months, %+.1f over 12 months\n", s.MSPR3m, s.MSPR12m)
+	fmt.Fprintf(&b, "- **Net shares:** %+d over 3 months, %+d over 12 months\n", s.NetShares3m, s.NetShares12m)
+	fmt.Fprintf(&b, "- **Months of net buying / selling (12 months):** %d / %d\n", s.BuyingMonths, s.SellingMonths)
+	for _, n := range s.Notes {
+		fmt.Fprintf(&b, "- %s\n", n)
+	}
+	b.WriteString("\n| Month | MSPR | Net shares |\n|---|---|---|\n")
+	for _, m := range s.Months[:min(insiderTableMonths, len(s.Months))] {
+		fmt.Fprintf(&b, "| %d-%02d | %+.1f | %+d |\n", m.Year, m.Month, m.MSPR, m.Change)
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/intermarket"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// IntermarketToolName is the name agents use to call NewIntermarketTool.
+const IntermarketToolName = "get_intermarket_analysis"
+
+// NewIntermarketTool creates a tool that compares a stock with its sector
+// ETF, the US dollar and the commodities tied to its sector.
+func NewIntermarketTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: IntermarketToolName,
+			Desc: "Compare a stock over the look-back window with its sector ETF, the US dollar (UUP) and the commodities tied to its sector (oil, natural gas, copper, gold): each driver's move, its correlation with the stock, and whether it is a tailwind or headwind for a long position. Stocks quoted outside the US are converted to USD at daily exchange rates before comparing",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"symbol": {
+					Type:     "string",
+					Desc:     "The stock symbol",
+					Required: true,
+				},
+				"sector": {
+					Type:     "string",
+					Desc:     "The stock's sector (e.g. 'Energy', 'Information Technology'); looked up from the S&P 500 list for US stocks when omitted",
+					Required: false,
+				},
+				"lookback": {
+					Type:     "integer",
+					Desc:     fmt.Sprintf("Number of daily bars to compare (%d-%d, default: %d)", intermarket.MinLookback, intermarket.MaxLookback, intermarket.DefaultLookback),
+					Required: false,
+				},
+				"before_date": {
+					Type:     "string",
+					Desc:     "Only use prices on or before this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.IntermarketInput) (*models.IntermarketOutput, error) {
+			sector := strings.TrimSpace(input.Sector)
+			if sector == "" {
+				sector = lookupSector(cfg, input.Symbol)
+			}
+			// the drivers are US-listed, so a stock quoted elsewhere is compared in dollars
+			fxs := newFXSession(cfg)
+			defer fxs.note(ctx)
+			r, err := intermarket.Analyze(ctx, fxs.fetcher(cfg, dataflows.CurrencyUSD), input.Symbol, sector, input.Lookback, strings.TrimSpace(input.BeforeDate))
+			if err != nil {
+				return &models.IntermarketOutput{Result: fmt.Sprintf("Intermarket analysis unavailable: %v\n", err)}, nil
+			}
+			r.Currency = dataflows.CurrencyUSD
+			r.Notes = append(r.Notes, fxs.Notes()...)
+			return &models.IntermarketOutput{Result: intermarket.Render(r)}, nil
+		},
+	)
+}
+
+// lookupSector finds a US stock's GICS sector in the S&P 500 list; "" when
+// the stock is not a member or the list is unavailable.
+func lookupSector(cfg *config.Config, symbol string) string {
+	symbol = dataflows.NormalizeSymbol(symbol)
+	if !strings.Contains(symbol, ".") {
+		symbol += ".US"
+	}
+	if !strings.HasSuffix(symbol, ".US") {
+		return ""
+	}
+	c, err := dataflows.NewConstituentsClient(cfg).GetConstituents("SP500")
+	if err != nil {
+		return ""
+	}
+	for _, m := range c.Members {
+		if m.Symbol == symbol {
+			return m.Sector
+		}
+	}
+	return ""
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/cloudwego/eino/schema"
 	"github.com/dyike/CortexGo/config"
 	"github.com/dyike/CortexGo/internal/cache"
+	"github.com/dyike/CortexGo/internal/export"
 	"github.com/dyike/CortexGo/models"
 	"github.com/dyike/CortexGo/pkg/dataflows"
 	"github.com/longportapp/openapi-go/quote"
@@ -22,7 +25,7 @@ func NewMarketool(cfg *config.Config) tool.BaseTool {
 	return t_utils.NewTool(
 		&schema.ToolInfo{
 			Name: "get_market_data",
-			Desc: "Get market data for a specific symbol and date range",
+			Desc: "Get daily market data (regular session) for a specific symbol and date range, plus the latest pre-market, after-hours and overnight quotes when available",
 			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
 				"symbol": {
 					Type:     "string",
@@ -45,24 +48,33 @@ func NewMarketool(cfg *config.Config) tool.BaseTool {
 			if count <= 0 {
 				count = 30 // default
 			}
+			if sim := dataflows.NewSimulator(cfg); sim != nil {
+				return &models.MarketDataOutput{Data: simulatedMarketData(ctx, sim, input.Symbol, count)}, nil
+			}
 
 			// 首先检查缓存
 			cacheManager := cache.GetMarketDataCache()
-			if cachedData, found := cacheManager.Get(ctx, input.Symbol, count); found {
+			if cachedData, fetchedAt, found := cacheManager.Lookup(ctx, input.Symbol, count); found {
 				log.Printf("Using cached market data for %s (count: %d)", input.Symbol, count)
+				noteMarketData(ctx, models.ProvenanceCache, fetchedAt, cachedData)
 				return &models.MarketDataOutput{Data: cachedData}, nil
 			}
+			if cfg.Offline {
+				data, err := offlineMarketData(ctx, input.Symbol, count)
+				if err != nil {
+					return nil, err
+				}
+				return &models.MarketDataOutput{Data: data}, nil
+			}
+			if data, ok := pinnedMarketData(ctx, cfg, input.Symbol, count); ok {
+				return &models.MarketDataOutput{Data: data}, nil
+			}
 
 			// 缓存未命中，获取真实数据
-			longportConf := dataflows.LongportConfig{
-				AppKey:      cfg.LongportAppKey,
-				AppSecret:   cfg.LongportAppSecret,
-				AccessToken: cfg.LongportAccessToken,
-			}
-			longportClient, err := dataflows.NewLongportClient(longportConf)
+			longportClient, err := dataflows.NewLongportClient(dataflows.LongportConfigFrom(cfg))
 			if err != nil {
 				log.Printf("Failed to create Longport client, using mock data: %v", err)
-				return getMockMarketData(input.Symbol), nil
+				return mockMarketData(ctx, cfg, input.Symbol, count), nil
 			}
 
 			// Try to get real market data from Longport
@@ -73,12 +85,19 @@ func NewMarketool(cfg *config.Config) tool.BaseTool {
 				// 缓存数据
 				cacheManager.Set(ctx, input.Symbol, count, marketData)
 				log.Printf("Fetched and cached market data for %s (count: %d)", input.Symbol, count)
+				noteMarketData(ctx, models.ProvenanceLive, time.Now(), marketData)
+				exportSeries(cfg, input.Symbol, marketData, nil)
 
-				return &models.MarketDataOutput{Data: marketData}, nil
+				output := &models.MarketDataOutput{Data: marketData}
+				// 日K线不含盘前盘后，另取最新行情中的扩展时段
+				if quotes, err := longportClient.GetQuote(ctx, []string{input.Symbol}); err == nil && len(quotes) > 0 {
+					output.ExtendedHours = dataflows.ExtendedQuotes(quotes[0])
+				}
+				return output, nil
 			}
 			log.Printf("Failed to get real market data for %s: %v", input.Symbol, err)
 
-			return getMockMarketData(input.Symbol), nil
+			return mockMarketData(ctx, cfg, input.Symbol, count), nil
 		},
 	)
 }
@@ -151,7 +170,7 @@ func NewStockIndicatorTool(cfg *config.Config) tool.BaseTool {
 			var marketData []*models.MarketData
 			marketData, err = getOnlineMarketDataForIndicator(ctx, cfg, input.Symbol, input.LookBackDays+bufferDays)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get market data: %v", err)
+				return nil, fmt.Errorf("failed to get market data: %w", err)
 			}
 
 			if len(marketData) == 0 {
@@ -164,15 +183,7 @@ func NewStockIndicatorTool(cfg *config.Config) tool.BaseTool {
 			// Calculate all indicators at once
 			allIndicators := dataflows.CalculateAllIndicators(marketData, startDate, currDate)
 
-			// 保存指标结果到CSV
-			cacheManager := cache.GetMarketDataCache()
-			go func() {
-				if err := cacheManager.SaveIndicatorsToCSV(input.Symbol, allIndicators); err != nil {
-					log.Printf("Failed to save indicators to CSV: %v", err)
-				} else {
-					log.Printf("Successfully saved indicators to CSV for %s", input.Symbol)
-				}
-			}()
+			exportSeries(cfg, input.Symbol, marketData, allIndicators)
 
 			// Format comprehensive result
 			var resultBuilder strings.Builder
@@ -238,20 +249,31 @@ func NewStockIndicatorTool(cfg *config.Config) tool.BaseTool {
 	)
 }
 
+// FetchMarketData returns up to count daily bars for symbol, served from the market data cache when possible.
+func FetchMarketData(ctx context.Context, cfg *config.Config, symbol string, count int) ([]*models.MarketData, error) {
+	return getOnlineMarketDataForIndicator(ctx, cfg, symbol, count)
+}
+
 // getOnlineMarketDataForIndicator fetches market data online for indicator calculations with caching
 func getOnlineMarketDataForIndicator(ctx context.Context, cfg *config.Config, symbol string, count int) ([]*models.MarketData, error) {
+	// 模拟数据模式不读写缓存，避免生成的K线混入真实行情
+	if sim := dataflows.NewSimulator(cfg); sim != nil {
+		return simulatedMarketData(ctx, sim, symbol, count), nil
+	}
 	// 首先检查缓存
 	cacheManager := cache.GetMarketDataCache()
-	if cachedData, found := cacheManager.Get(ctx, symbol, count); found {
+	if cachedData, fetchedAt, found := cacheManager.Lookup(ctx, symbol, count); found {
 		log.Printf("Using cached market data for indicators %s (count: %d)", symbol, count)
+		noteMarketData(ctx, models.ProvenanceCache, fetchedAt, cachedData)
 		return cachedData, nil
 	}
-	longportConf := dataflows.LongportConfig{
-		AppKey:      cfg.LongportAppKey,
-		AppSecret:   cfg.LongportAppSecret,
-		AccessToken: cfg.LongportAccessToken,
+	if cfg.Offline {
+		return offlineMarketData(ctx, symbol, count)
 	}
-	longportClient, err := dataflows.NewLongportClient(longportConf)
+	if data, ok := pinnedMarketData(ctx, cfg, symbol, count); ok {
+		return data, nil
+	}
+	longportClient, err := dataflows.NewLongportClient(dataflows.LongportConfigFrom(cfg))
 	if err != nil {
 		return nil, err
 	}
@@ -266,10 +288,78 @@ func getOnlineMarketDataForIndicator(ctx context.Context, cfg *config.Config, sy
 	// 缓存数据
 	cacheManager.Set(ctx, symbol, count, marketData)
 	log.Printf("Fetched and cached market data for indicators %s (count: %d)", symbol, count)
+	noteMarketData(ctx, models.ProvenanceLive, time.Now(), marketData)
 
 	return marketData, nil
 }
 
+// exportSeries writes the bars, and the indicators computed from them when
+// given, through the series_export layer; failures are only logged.
+func exportSeries(cfg *config.Config, symbol string, bars []*models.MarketData, indicators map[string][]models.IndicatorValue) {
+	e := export.New(cfg)
+	if e == nil {
+		return
+	}
+	if path, err := e.Candles(symbol, bars); err != nil {
+		log.Printf("Failed to export candles for %s: %v", symbol, err)
+	} else {
+		log.Printf("Exported candles for %s to %s", symbol, path)
+	}
+	if len(indicators) == 0 {
+		return
+	}
+	if path, err := e.Indicators(symbol, indicators); err != nil {
+		log.Printf("Failed to export indicators for %s: %v", symbol, err)
+	} else {
+		log.Printf("Exported indicators for %s to %s", symbol, path)
+	}
+}
+
+// offlineMarketData serves market data only from the local CSV archive; it never falls back to mock data
+func offlineMarketData(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+	if data, fetchedAt, ok := cache.GetMarketDataCache().GetArchived(symbol, count); ok {
+		noteMarketData(ctx, models.ProvenanceArchive, fetchedAt, data)
+		return data, nil
+	}
+	return nil, &dataflows.OfflineMissError{Source: "market", Key: fmt.Sprintf("%s (%d bars)", symbol, count)}
+}
+
+// pinnedMarketData serves a seeded run the archived bars whatever their age, so
+// repeated runs see the same prices; the first run fetches and archives them.
+func pinnedMarketData(ctx context.Context, cfg *config.Config, symbol string, count int) ([]*models.MarketData, bool) {
+	if cfg.Seed <= 0 {
+		return nil, false
+	}
+	data, fetchedAt, ok := cache.GetMarketDataCache().GetArchived(symbol, count)
+	if !ok {
+		return nil, false
+	}
+	noteMarketData(ctx, models.ProvenanceCache, fetchedAt, data)
+	return data, true
+}
+
+// OfflinePreflight lists the local data an offline run of symbol is missing.
+// News and social caches are keyed by the queries agents choose, so only their
+// presence can be checked up front; individual misses fail the run when hit.
+// Simulated data mode generates all three and needs nothing.
+func OfflinePreflight(cfg *config.Config, symbol string) []string {
+	if cfg.SimulatedData() {
+		return nil
+	}
+	var missing []string
+	marketCache := cache.GetMarketDataCache()
+	if !marketCache.HasArchive(symbol) {
+		missing = append(missing, fmt.Sprintf("market data archive for %s (%s)", symbol, marketCache.ArchiveDir(symbol)))
+	}
+	for _, source := range []string{"google_news", "reddit"} {
+		dir := filepath.Join(cfg.DataCacheDir, source)
+		if entries, err := os.ReadDir(dir); err != nil || len(entries) == 0 {
+			missing = append(missing, fmt.Sprintf("cached %s data (%s)", source, dir))
+		}
+	}
+	return missing
+}
+
 // generateTechnicalSummary generates a summary of technical analysis
 func generateTechnicalSummary(indicators map[string][]models.IndicatorValue) string {
 	var summary strings.Builder
@@ -394,24 +484,23 @@ func convertSticksToMarketData(sticks []*quote.Candlestick, symbol string) []*mo
 			Low:    low,
 			Close:  close,
 			Volume: stick.Volume,
+			// 日K线按标的所在市场的币种报价
+			Currency: dataflows.CurrencyOf(symbol),
 		})
 	}
 	return marketData
 }
 
-// getMockMarketData 获取模拟市场数据
-func getMockMarketData(symbol string) *models.MarketDataOutput {
-	return &models.MarketDataOutput{
-		Data: []*models.MarketData{
-			{
-				Symbol: symbol,
-				Date:   time.Now().Format("2006-01-02"),
-				Open:   100.0,
-				High:   101.0,
-				Low:    99.0,
-				Close:  100.5,
-				Volume: int64(1000000),
-			},
-		},
-	}
+// simulatedMarketData 返回模拟数据源生成的K线，并记录数据来源为 simulated
+func simulatedMarketData(ctx context.Context, sim *dataflows.Simulator, symbol string, count int) []*models.MarketData {
+	data := sim.Bars(symbol, count)
+	noteMarketData(ctx, models.ProvenanceSimulated, time.Now(), data)
+	return data
+}
+
+// mockMarketData 在 Longport 不可用时以随机游走K线代替真实行情，并记录本次工具调用的数据来源为 mock
+func mockMarketData(ctx context.Context, cfg *config.Config, symbol string, count int) *models.MarketDataOutput {
+	data := dataflows.NewSeededSimulator(cfg.Seed).Bars(symbol, count)
+	noteMarketData(ctx, models.ProvenanceMock, time.Now(), data)
+	return &models.MarketDataOutput{Data: data}
 }
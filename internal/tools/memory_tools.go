@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/memory"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/models"
+)
+
+// SearchPastAnalysesToolName is the name agents use to call NewSearchPastAnalysesTool.
+const SearchPastAnalysesToolName = "search_past_analyses"
+
+// pastAnalysisSnippetRunes bounds each excerpt returned to the model.
+const pastAnalysisSnippetRunes = 600
+
+// NewSearchPastAnalysesTool creates a tool that retrieves relevant excerpts
+// from earlier analysis reports stored in the local history.
+func NewSearchPastAnalysesTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: SearchPastAnalysesToolName,
+			Desc: "Search our own earlier analysis reports for relevant past findings, e.g. what we concluded the last time earnings approached or a similar risk appeared",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"query": {
+					Type:     "string",
+					Desc:     "What to look for, e.g. 'earnings guidance risk' or '财报 指引'",
+					Required: true,
+				},
+				"symbol": {
+					Type:     "string",
+					Desc:     "Restrict to one ticker (e.g. 'NVDA.US'); omit to search all tickers",
+					Required: false,
+				},
+				"before_date": {
+					Type:     "string",
+					Desc:     "Only analyses with a trade date before this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+				"limit": {
+					Type:     "integer",
+					Desc:     "Maximum number of excerpts to return (1-10, default: 5)",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.PastAnalysesInput) (*models.PastAnalysesOutput, error) {
+			if strings.TrimSpace(input.Query) == "" {
+				return nil, fmt.Errorf("query parameter is required")
+			}
+			limit := input.Limit
+			if limit <= 0 {
+				limit = 5
+			}
+			if limit > 10 {
+				limit = 10
+			}
+
+			// history is optional; report it unavailable instead of failing the run
+			store, err := storage.GetSQLiteStore()
+			if err != nil {
+				return &models.PastAnalysesOutput{Result: fmt.Sprintf("Past analyses are unavailable: %v\n", err)}, nil
+			}
+			hits, err := memory.Search(ctx, cfg, store, memory.DefaultEmbedder, memory.Query{
+				Text:   input.Query,
+				Symbol: strings.TrimSpace(input.Symbol),
+				Before: strings.TrimSpace(input.BeforeDate),
+				Limit:  limit,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("search past analyses: %w", err)
+			}
+			provenance.Note(ctx, models.Provenance{Source: "past_analyses", Mode: models.ProvenanceLocal})
+
+			var result strings.Builder
+			result.WriteString(fmt.Sprintf("# Past analyses matching \"%s\"\n\n", input.Query))
+			if len(hits) == 0 {
+				result.WriteString("No earlier analyses matched. This may be the first analysis on this topic.\n")
+			}
+			for i, h := range hits {
+				result.WriteString(fmt.Sprintf("## %d. %s %s — %s\n", i+1, h.Symbol, h.TradeDate, h.Section))
+				if h.Recommendation != "" {
+					result.WriteString(fmt.Sprintf("**Recommendation then:** %s | ", h.Recommendation))
+				}
+				result.WriteString(fmt.Sprintf("**Relevance:** %.2f\n\n", h.Score))
+				content := h.Content
+				if utf8.RuneCountInString(content) > pastAnalysisSnippetRunes {
+					content = string([]rune(content)[:pastAnalysisSnippetRunes]) + "..."
+				}
+				result.WriteString(content)
+				result.WriteString("\n\n---\n\n")
+			}
+
+			return &models.PastAnalysesOutput{Results: hits, Result: result.String()}, nil
+		},
+	)
+}
@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/rates"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// YieldCurveToolName is the name agents use to call NewYieldCurveTool.
+const YieldCurveToolName = "get_yield_curve"
+
+// NewYieldCurveTool creates a tool that summarizes the US Treasury yield
+// curve: spreads, recent 2- and 10-year moves and the curve's shape.
+func NewYieldCurveTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: YieldCurveToolName,
+			Desc: "Get the US Treasury yield curve: yields by tenor, the 2s10s and 3m10y spreads, 2- and 10-year moves over the last week and month, whether the curve is inverted and whether it is steepening or flattening",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"before_date": {
+					Type:     "string",
+					Desc:     "Use the curve on or before this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.YieldCurveInput) (*models.YieldCurveOutput, error) {
+			s, err := rates.Load(ctx, cfg, strings.TrimSpace(input.BeforeDate))
+			if errors.Is(err, dataflows.ErrOffline) {
+				return nil, err
+			}
+			if err != nil {
+				return &models.YieldCurveOutput{Result: fmt.Sprintf("Yield curve unavailable: %v\n", err)}, nil
+			}
+			return &models.YieldCurveOutput{Result: rates.Render(s)}, nil
+		},
+	)
+}
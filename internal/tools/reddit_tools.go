@@ -60,8 +60,9 @@ func NewRedditSubredditTool(cfg *config.Config) tool.BaseTool {
 			// Get posts
 			posts, err := redditClient.GetSubredditPosts(input.Subreddit, sort, limit, cfg)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get Reddit posts: %v", err)
+				return nil, fmt.Errorf("failed to get Reddit posts: %w", err)
 			}
+			notePosts(ctx, redditClient, posts)
 
 			log.Printf("Retrieved %d posts from r/%s (%s)", len(posts), input.Subreddit, sort)
 
@@ -92,27 +93,8 @@ func NewRedditSubredditTool(cfg *config.Config) tool.BaseTool {
 				result.WriteString("\n---\n\n")
 			}
 
-			// Convert dataflows.RedditPost to models.RedditPost
-			modelPosts := make([]*models.RedditPost, len(posts))
-			for i, p := range posts {
-				modelPosts[i] = &models.RedditPost{
-					ID:         p.ID,
-					Title:      p.Title,
-					Content:    p.Content,
-					URL:        p.URL,
-					Subreddit:  p.Subreddit,
-					Author:     p.Author,
-					Score:      p.Score,
-					Comments:   p.Comments,
-					CreatedAt:  p.CreatedAt,
-					Sentiment:  p.Sentiment,
-					IsStickied: p.IsStickied,
-					IsLocked:   p.IsLocked,
-				}
-			}
-
 			return &models.RedditOutput{
-				Posts:  modelPosts,
+				Posts:  posts,
 				Result: result.String(),
 			}, nil
 		},
@@ -182,8 +164,9 @@ func NewRedditSearchTool(cfg *config.Config) tool.BaseTool {
 			// Search posts
 			posts, err := redditClient.SearchReddit(params, cfg)
 			if err != nil {
-				return nil, fmt.Errorf("failed to search Reddit: %v", err)
+				return nil, fmt.Errorf("failed to search Reddit: %w", err)
 			}
+			notePosts(ctx, redditClient, posts)
 
 			log.Printf("Found %d posts for query: %s", len(posts), input.Query)
 
@@ -216,27 +199,8 @@ func NewRedditSearchTool(cfg *config.Config) tool.BaseTool {
 				}
 			}
 
-			// Convert dataflows.RedditPost to models.RedditPost
-			modelPosts := make([]*models.RedditPost, len(posts))
-			for i, p := range posts {
-				modelPosts[i] = &models.RedditPost{
-					ID:         p.ID,
-					Title:      p.Title,
-					Content:    p.Content,
-					URL:        p.URL,
-					Subreddit:  p.Subreddit,
-					Author:     p.Author,
-					Score:      p.Score,
-					Comments:   p.Comments,
-					CreatedAt:  p.CreatedAt,
-					Sentiment:  p.Sentiment,
-					IsStickied: p.IsStickied,
-					IsLocked:   p.IsLocked,
-				}
-			}
-
 			return &models.RedditOutput{
-				Posts:  modelPosts,
+				Posts:  posts,
 				Result: result.String(),
 			}, nil
 		},
@@ -268,8 +232,9 @@ func NewRedditStockMentionsTool(cfg *config.Config) tool.BaseTool {
 			// Get stock mentions
 			posts, err := redditClient.GetStockMentions(input.Symbol, cfg)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get stock mentions: %v", err)
+				return nil, fmt.Errorf("failed to get stock mentions: %w", err)
 			}
+			notePosts(ctx, redditClient, posts)
 
 			maxPosts := 12
 			if len(posts) > maxPosts {
@@ -320,27 +285,8 @@ func NewRedditStockMentionsTool(cfg *config.Config) tool.BaseTool {
 				result.WriteString("- Consider the credibility of sources and authors in investment discussions\n")
 			}
 
-			// Convert dataflows.RedditPost to models.RedditPost
-			modelPosts := make([]*models.RedditPost, len(posts))
-			for i, p := range posts {
-				modelPosts[i] = &models.RedditPost{
-					ID:         p.ID,
-					Title:      p.Title,
-					Content:    p.Content,
-					URL:        p.URL,
-					Subreddit:  p.Subreddit,
-					Author:     p.Author,
-					Score:      p.Score,
-					Comments:   p.Comments,
-					CreatedAt:  p.CreatedAt,
-					Sentiment:  p.Sentiment,
-					IsStickied: p.IsStickied,
-					IsLocked:   p.IsLocked,
-				}
-			}
-
 			return &models.RedditOutput{
-				Posts:  modelPosts,
+				Posts:  posts,
 				Result: result.String(),
 			}, nil
 		},
@@ -373,8 +319,9 @@ func NewRedditFinanceNewsTool(cfg *config.Config) tool.BaseTool {
 			// Get popular finance posts
 			posts, err := redditClient.GetPopularFinancePosts(limit, cfg)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get finance posts: %v", err)
+				return nil, fmt.Errorf("failed to get finance posts: %w", err)
 			}
+			notePosts(ctx, redditClient, posts)
 
 			log.Printf("Retrieved %d popular finance posts", len(posts))
 			if len(posts) > limit {
@@ -477,27 +424,8 @@ func NewRedditFinanceNewsTool(cfg *config.Config) tool.BaseTool {
 				}
 			}
 
-			// Convert dataflows.RedditPost to models.RedditPost
-			modelPosts := make([]*models.RedditPost, len(posts))
-			for i, p := range posts {
-				modelPosts[i] = &models.RedditPost{
-					ID:         p.ID,
-					Title:      p.Title,
-					Content:    p.Content,
-					URL:        p.URL,
-					Subreddit:  p.Subreddit,
-					Author:     p.Author,
-					Score:      p.Score,
-					Comments:   p.Comments,
-					CreatedAt:  p.CreatedAt,
-					Sentiment:  p.Sentiment,
-					IsStickied: p.IsStickied,
-					IsLocked:   p.IsLocked,
-				}
-			}
-
 			return &models.RedditOutput{
-				Posts:  modelPosts,
+				Posts:  posts,
 				Result: result.String(),
 			}, nil
 		},
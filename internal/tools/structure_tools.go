@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/structure"
+	"github.com/dyike/CortexGo/models"
+)
+
+// MarketStructureToolName is the name agents use to call NewMarketStructureTool.
+const MarketStructureToolName = "get_market_structure"
+
+// NewMarketStructureTool creates a tool that labels the recent price
+// structure of a stock (swings, ranges, breakouts, volume climaxes) and
+// assesses its Wyckoff phase.
+func NewMarketStructureTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: MarketStructureToolName,
+			Desc: "Label the recent price structure of a stock from daily candles: higher/lower swing highs and lows, the trading range it is in, recent breakouts, springs and upthrusts, and volume climaxes, with a Wyckoff phase assessment (accumulation, markup, distribution, markdown) and the evidence behind it",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"symbol": {
+					Type:     "string",
+					Desc:     "The stock symbol",
+					Required: true,
+				},
+				"lookback": {
+					Type:     "integer",
+					Desc:     fmt.Sprintf("Number of daily bars to analyze (%d-%d, default: %d)", structure.MinLookback, structure.MaxLookback, structure.DefaultLookback),
+					Required: false,
+				},
+				"before_date": {
+					Type:     "string",
+					Desc:     "Only use prices on or before this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.MarketStructureInput) (*models.MarketStructureOutput, error) {
+			s, err := structure.Analyze(ctx, func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+				return FetchMarketData(ctx, cfg, symbol, count)
+			}, input.Symbol, input.Lookback, strings.TrimSpace(input.BeforeDate))
+			if err != nil {
+				return &models.MarketStructureOutput{Result: fmt.Sprintf("Market structure unavailable: %v\n", err)}, nil
+			}
+			return &models.MarketStructureOutput{Result: structure.RenderStructure(s)}, nil
+		},
+	)
+}
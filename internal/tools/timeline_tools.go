@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// NewsTimelineToolName is the name agents use to call NewNewsTimelineTool.
+const NewsTimelineToolName = "get_news_timeline"
+
+// newsTimelineDefaultDays is the lookback used when the agent passes none.
+const newsTimelineDefaultDays = 14
+
+// NewNewsTimelineTool creates a tool that assembles a stock's recent news
+// into a dated, de-duplicated list of typed events.
+func NewNewsTimelineTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: NewsTimelineToolName,
+			Desc: "Build a chronological timeline of events for a stock from recent news (earnings, guidance, analyst upgrades/downgrades, deals, legal/regulatory, management changes, dividends/buybacks, product launches), merging duplicate reports of the same event",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"symbol": {
+					Type:     "string",
+					Desc:     "Stock ticker (e.g. 'AAPL' or 'AAPL.US')",
+					Required: true,
+				},
+				"days_back": {
+					Type:     "integer",
+					Desc:     fmt.Sprintf("Number of days before before_date to cover (default: %d)", newsTimelineDefaultDays),
+					Required: false,
+				},
+				"before_date": {
+					Type:     "string",
+					Desc:     "Drop news published after this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.NewsTimelineInput) (*models.NewsTimelineOutput, error) {
+			symbol := strings.ToUpper(strings.TrimSpace(input.Symbol))
+			if symbol == "" {
+				return nil, fmt.Errorf("symbol parameter is required")
+			}
+			days := input.DaysBack
+			if days <= 0 {
+				days = newsTimelineDefaultDays
+			}
+			end := time.Now()
+			if input.BeforeDate != "" {
+				d, err := time.Parse("2006-01-02", strings.TrimSpace(input.BeforeDate))
+				if err != nil {
+					return nil, fmt.Errorf("invalid before_date %q: %v", input.BeforeDate, err)
+				}
+				end = d.AddDate(0, 0, 1)
+			}
+			start := end.AddDate(0, 0, -days)
+
+			articles, err := timelineArticles(ctx, cfg, symbol, start, end)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get news for %s: %v", symbol, err)
+			}
+			var kept []*dataflows.NewsArticle
+			for _, a := range articles {
+				if !a.PublishedAt.Before(start) && a.PublishedAt.Before(end) {
+					kept = append(kept, a)
+				}
+			}
+			events := dataflows.BuildTimeline(symbol, kept)
+			return &models.NewsTimelineOutput{Events: events, Result: renderTimeline(symbol, days, len(kept), events)}, nil
+		},
+	)
+}
+
+// timelineArticles gathers the stock's news from the Google News search and
+// RSS feeds; one failing is tolerated, both failing is not.
+func timelineArticles(ctx context.Context, cfg *config.Config, symbol string, start, end time.Time) ([]*dataflows.NewsArticle, error) {
+	client := dataflows.NewGoogleNewsClient(cfg)
+	ticker := strings.SplitN(symbol, ".", 2)[0]
+	scraped, errSearch := client.GetStockNews(ticker, 20, cfg)
+	feed, errRSS := client.GetGoogleNewsRSS(dataflows.EnhancedGoogleNewsParams{
+		Query:      ticker + " stock",
+		Language:   "en",
+		Country:    "US",
+		StartDate:  start,
+		EndDate:    end,
+		MaxResults: 40,
+	}, cfg)
+	if errSearch != nil && errRSS != nil {
+		return nil, errors.Join(errSearch, errRSS)
+	}
+	articles := append(scraped, feed...)
+	noteNews(ctx, client, articles)
+	return articles, nil
+}
+
+// renderTimeline lists events oldest first, one line each, with the outlets
+// that also covered them.
+func renderTimeline(symbol string, days, articles int, events []models.TimelineEvent) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# News Timeline for %s (last %d days)\n\n", symbol, days)
+	if len(events) == 0 {
+		b.WriteString("No news found in this window.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "*%d events from %d articles, oldest first*\n\n", len(events), articles)
+	counts := map[string]int{}
+	for _, e := range events {
+		counts[e.Type]++
+		when := e.Time.Format("2006-01-02 15:04")
+		if e.Session != "" {
+			when += " " + e.Session
+		}
+		fmt.Fprintf(&b, "- **%s** [%s] %s — %s (sentiment %+.2f)", when, e.Type, e.Headline, e.Source, e.Sentiment)
+		if len(e.Coverage) > 0 {
+			fmt.Fprintf(&b, "; also reported by %s", strings.Join(e.Coverage, ", "))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n**Event mix:** ")
+	var parts []string
+	for _, t := range []string{dataflows.EventEarnings, dataflows.EventGuidance, dataflows.EventAnalystRating, dataflows.EventDeal, dataflows.EventLegal, dataflows.EventCapitalReturn, dataflows.EventManagement, dataflows.EventProduct, dataflows.EventOther} {
+		if counts[t] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[t], t))
+		}
+	}
+	b.WriteString(strings.Join(parts, ", ") + "\n")
+	return b.String()
+}
@@ -0,0 +1,171 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/provenance"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// EarningsCallToolName is the name agents use to call NewEarningsCallTool.
+const EarningsCallToolName = "get_earnings_call_transcript"
+
+// Bounds for the extractive summary returned to the model.
+const (
+	earningsCallMaxExchanges   = 12
+	earningsCallQuestionRunes  = 320
+	earningsCallAnswerRunes    = 480
+	earningsCallAnswerSentence = 3
+)
+
+// NewEarningsCallTool creates a tool that retrieves the latest earnings call
+// before the trade date and summarizes its question-and-answer session.
+func NewEarningsCallTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: EarningsCallToolName,
+			Desc: "Get the most recent earnings call transcript for a US-listed stock and summarize the analyst Q&A: who asked what, the topics probed and how management answered",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"symbol": {
+					Type:     "string",
+					Desc:     "Stock ticker (e.g. 'AAPL' or 'AAPL.US')",
+					Required: true,
+				},
+				"before_date": {
+					Type:     "string",
+					Desc:     "Only calls held on or before this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.EarningsCallInput) (*models.EarningsCallOutput, error) {
+			if strings.TrimSpace(input.Symbol) == "" {
+				return nil, fmt.Errorf("symbol parameter is required")
+			}
+
+			if _, err := dataflows.TranscriptSymbol(input.Symbol); err != nil {
+				return &models.EarningsCallOutput{Symbol: input.Symbol, Result: fmt.Sprintf("Earnings call transcript unavailable: %v\n", err)}, nil
+			}
+
+			client := dataflows.NewTranscriptsClient(cfg)
+			transcript, err := client.GetLatestTranscript(input.Symbol, strings.TrimSpace(input.BeforeDate), cfg)
+			switch {
+			case errors.Is(err, dataflows.ErrOffline):
+				return nil, err
+			case errors.Is(err, dataflows.ErrNoTranscriptProvider), errors.Is(err, dataflows.ErrNoTranscript):
+				// transcripts are optional; tell the model instead of failing the run
+				return &models.EarningsCallOutput{Symbol: input.Symbol, Result: fmt.Sprintf("Earnings call transcript unavailable: %v\n", err)}, nil
+			case err != nil:
+				return nil, fmt.Errorf("get earnings call transcript: %w", err)
+			}
+
+			p := client.Provenance()
+			p.AsOf = transcript.Date
+			provenance.Note(ctx, p)
+
+			exchanges := dataflows.QAExchanges(transcript)
+			return &models.EarningsCallOutput{
+				Symbol:    transcript.Symbol,
+				Year:      transcript.Year,
+				Quarter:   transcript.Quarter,
+				Date:      transcript.Date,
+				Source:    transcript.Source,
+				Exchanges: exchanges,
+				Result:    formatEarningsCall(transcript, exchanges),
+			}, nil
+		},
+	)
+}
+
+// formatEarningsCall renders an extractive Q&A digest: topic counts, then each
+// question with the opening sentences of every management answer.
+func formatEarningsCall(t *models.EarningsCallTranscript, exchanges []models.TranscriptExchange) string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("# %s Q%d %d Earnings Call Q&A\n\n", t.Symbol, t.Quarter, t.Year))
+	result.WriteString(fmt.Sprintf("**Call date:** %s | **Source:** %s | **Questions:** %d\n\n", t.Date, t.Source, len(exchanges)))
+	if len(exchanges) == 0 {
+		result.WriteString("The transcript has no identifiable question-and-answer session.\n")
+		return result.String()
+	}
+
+	counts := map[string]int{}
+	for _, ex := range exchanges {
+		for _, topic := range ex.Topics {
+			counts[topic]++
+		}
+	}
+	if len(counts) > 0 {
+		topics := make([]string, 0, len(counts))
+		for topic := range counts {
+			topics = append(topics, topic)
+		}
+		sort.Slice(topics, func(i, j int) bool {
+			if counts[topics[i]] != counts[topics[j]] {
+				return counts[topics[i]] > counts[topics[j]]
+			}
+			return topics[i] < topics[j]
+		})
+		parts := make([]string, len(topics))
+		for i, topic := range topics {
+			parts[i] = fmt.Sprintf("%s (%d)", topic, counts[topic])
+		}
+		result.WriteString(fmt.Sprintf("## Topics analysts raised\n%s\n\n", strings.Join(parts, ", ")))
+	}
+
+	shown := exchanges
+	if len(shown) > earningsCallMaxExchanges {
+		shown = shown[:earningsCallMaxExchanges]
+	}
+	for i, ex := range shown {
+		result.WriteString(fmt.Sprintf("## %d. %s", i+1, ex.Analyst))
+		if ex.Firm != "" {
+			result.WriteString(fmt.Sprintf(" (%s)", ex.Firm))
+		}
+		if len(ex.Topics) > 0 {
+			result.WriteString(fmt.Sprintf(" — %s", strings.Join(ex.Topics, ", ")))
+		}
+		result.WriteString("\n")
+		result.WriteString(fmt.Sprintf("**Q:** %s\n", leadSentences(ex.Question, 2, earningsCallQuestionRunes)))
+		for _, a := range ex.Answers {
+			result.WriteString(fmt.Sprintf("**A (%s):** %s\n", a.Speaker, leadSentences(a.Text, earningsCallAnswerSentence, earningsCallAnswerRunes)))
+		}
+		result.WriteString("\n")
+	}
+	if len(exchanges) > len(shown) {
+		result.WriteString(fmt.Sprintf("_%d more questions omitted._\n", len(exchanges)-len(shown)))
+	}
+	return result.String()
+}
+
+// leadSentences keeps the first n sentences of text, capped at maxRunes.
+func leadSentences(text string, n, maxRunes int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	end, count := len(text), 0
+	for i, r := range text {
+		if r != '.' && r != '?' && r != '!' {
+			continue
+		}
+		if next := i + 1; next == len(text) || text[next] == ' ' {
+			count++
+			if count == n {
+				end = next
+				break
+			}
+		}
+	}
+	text = text[:end]
+	if utf8.RuneCountInString(text) > maxRunes {
+		text = string([]rune(text)[:maxRunes]) + "..."
+	}
+	return text
+}
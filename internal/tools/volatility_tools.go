@@ -0,0 +1,44 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	t_utils "github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/volatility"
+	"github.com/dyike/CortexGo/models"
+)
+
+// VolatilityToolName is the name agents use to call NewVolatilityTool.
+const VolatilityToolName = "get_volatility_regime"
+
+// NewVolatilityTool creates a tool that reports the VIX, its term structure
+// and the volatility regime with the position-size multiplier it implies.
+func NewVolatilityTool(cfg *config.Config) tool.BaseTool {
+	return t_utils.NewTool(
+		&schema.ToolInfo{
+			Name: VolatilityToolName,
+			Desc: "Get the current VIX with its 5-day change and one-year percentile, the VIX term structure (9-day, 30-day, 3-month, 6-month; contango or backwardation) and the volatility regime (calm, normal, elevated, stressed) with the position-size multiplier it implies",
+			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+				"before_date": {
+					Type:     "string",
+					Desc:     "Only use index levels on or before this date (YYYY-MM-DD); pass the current trade date",
+					Required: false,
+				},
+			}),
+		},
+		func(ctx context.Context, input models.VolatilityInput) (*models.VolatilityOutput, error) {
+			v, err := volatility.Assess(ctx, func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error) {
+				return FetchMarketData(ctx, cfg, symbol, count)
+			}, strings.TrimSpace(input.BeforeDate))
+			if err != nil {
+				return &models.VolatilityOutput{Result: fmt.Sprintf("Volatility regime unavailable: %v\n", err)}, nil
+			}
+			return &models.VolatilityOutput{Result: volatility.Render(v)}, nil
+		},
+	)
+}
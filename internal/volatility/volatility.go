@@ -0,0 +1,178 @@
+// Package volatility classifies the US equity volatility regime from the VIX
+// family of indices: the VIX level against its past year, the term structure
+// from 9-day to 6-month implied volatility, and the position-size multiplier
+// the risk manager applies in each regime.
+package volatility
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// historyBars covers a year of sessions for the percentile.
+const historyBars = 260
+
+// Regime thresholds on the VIX level.
+const (
+	calmBelow     = 15
+	normalBelow   = 20
+	elevatedBelow = 30
+)
+
+// Term structure thresholds on VIX / VIX3M.
+const (
+	contangoBelow     = 0.95
+	backwardationOver = 1.0
+)
+
+// spikePct is the 5-day VIX rise worth flagging on its own.
+const spikePct = 30
+
+type tenor struct {
+	Name   string
+	Symbol string
+}
+
+// term lists the CBOE volatility indices from the shortest horizon out.
+var term = []tenor{
+	{Name: "9D", Symbol: ".VIX9D.US"},
+	{Name: "30D", Symbol: ".VIX.US"},
+	{Name: "3M", Symbol: ".VIX3M.US"},
+	{Name: "6M", Symbol: ".VIX6M.US"},
+}
+
+// levels orders the regimes from calm to stressed.
+var levels = []string{models.VolCalm, models.VolNormal, models.VolElevated, models.VolStressed}
+
+// sizeMultipliers scale the mandate's maximum position in each regime.
+var sizeMultipliers = map[string]float64{
+	models.VolCalm:     1,
+	models.VolNormal:   1,
+	models.VolElevated: 0.75,
+	models.VolStressed: 0.5,
+}
+
+// Assess reads the VIX term structure on or before asOf. Missing tenors are
+// skipped; it fails only when the VIX itself has no data.
+func Assess(ctx context.Context, fetch dataflows.BarFetcher, asOf string) (*models.VolatilityRegime, error) {
+	v := &models.VolatilityRegime{}
+	var vix, vix3m []float64
+	for _, t := range term {
+		bars, err := fetch(ctx, t.Symbol, historyBars)
+		if err != nil {
+			continue
+		}
+		bars = dataflows.BarsUpTo(bars, asOf)
+		if len(bars) == 0 {
+			continue
+		}
+		last := bars[len(bars)-1]
+		v.Term = append(v.Term, models.VolTermPoint{Tenor: t.Name, Symbol: t.Symbol, Level: last.Close})
+		switch t.Name {
+		case "30D":
+			v.AsOf = last.Date
+			vix = dataflows.Closes(bars)
+		case "3M":
+			vix3m = dataflows.Closes(bars)
+		}
+	}
+	if len(vix) == 0 {
+		return nil, fmt.Errorf("no VIX data on or before %s", asOf)
+	}
+	classify(v, vix, vix3m)
+	return v, nil
+}
+
+// classify fills the level statistics, term structure, regime and size
+// multiplier from the VIX and VIX3M closes.
+func classify(v *models.VolatilityRegime, vix, vix3m []float64) {
+	v.VIX = vix[len(vix)-1]
+	if n := len(vix); n > 5 && vix[n-6] > 0 {
+		v.Change5d = (v.VIX/vix[n-6] - 1) * 100
+	}
+	year := vix[max(0, len(vix)-252):]
+	below := 0
+	for _, c := range year {
+		if c <= v.VIX {
+			below++
+		}
+	}
+	v.Percentile1y = float64(below) / float64(len(year)) * 100
+
+	level := 0
+	switch {
+	case v.VIX < calmBelow:
+	case v.VIX < normalBelow:
+		level = 1
+	case v.VIX < elevatedBelow:
+		level = 2
+	default:
+		level = 3
+	}
+
+	if len(vix3m) > 0 && vix3m[len(vix3m)-1] > 0 {
+		v.TermRatio = v.VIX / vix3m[len(vix3m)-1]
+		switch {
+		case v.TermRatio < contangoBelow:
+			v.TermStructure = models.TermContango
+		case v.TermRatio > backwardationOver:
+			v.TermStructure = models.TermBackwardation
+		default:
+			v.TermStructure = models.TermFlat
+		}
+	}
+	if v.TermStructure == models.TermBackwardation && level < len(levels)-1 {
+		// near-term protection costs more than three-month: stress is priced now
+		level++
+		v.Notes = append(v.Notes, fmt.Sprintf("term structure inverted (VIX/VIX3M %.2f): the market is paying up for near-term protection, so the regime is raised to %s", v.TermRatio, levels[level]))
+	}
+	v.Regime = levels[level]
+	if v.Change5d >= spikePct {
+		v.Notes = append(v.Notes, fmt.Sprintf("VIX up %.0f%% in 5 sessions: volatility is expanding fast, expect wider stops to be hit", v.Change5d))
+	}
+	if v.Percentile1y >= 90 {
+		v.Notes = append(v.Notes, fmt.Sprintf("VIX at or above %.0f%% of its closes over the past year", v.Percentile1y))
+	} else if v.Percentile1y <= 10 && v.Regime == models.VolCalm {
+		v.Notes = append(v.Notes, "VIX near its one-year low: complacency can leave hedges cheap and shocks under-priced")
+	}
+	v.SizeMultiplier = sizeMultipliers[v.Regime]
+}
+
+// Render formats the regime as markdown for the agents.
+func Render(v *models.VolatilityRegime) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Volatility Regime (as of %s): %s\n\n", v.AsOf, strings.ToUpper(v.Regime))
+	fmt.Fprintf(&b, "- **VIX:** %.2f (%+.1f%% over 5 sessions, at or above %.0f%% of the past year's closes)\n", v.VIX, v.Change5d, v.Percentile1y)
+	if len(v.Term) > 1 {
+		parts := make([]string, len(v.Term))
+		for i, p := range v.Term {
+			parts[i] = fmt.Sprintf("%s %.2f", p.Tenor, p.Level)
+		}
+		fmt.Fprintf(&b, "- **Term structure:** %s", strings.Join(parts, " → "))
+		if v.TermStructure != "" {
+			fmt.Fprintf(&b, " — %s (VIX/VIX3M %.2f)", v.TermStructure, v.TermRatio)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "- **Suggested position size multiplier:** %.2fx of the mandate's maximum\n", v.SizeMultiplier)
+	if len(v.Notes) > 0 {
+		b.WriteString("\n## Notes\n\n")
+		for _, n := range v.Notes {
+			fmt.Fprintf(&b, "- %s\n", n)
+		}
+	}
+	return b.String()
+}
+
+// Brief is the one-line version of the regime for prompts.
+func Brief(v *models.VolatilityRegime) string {
+	line := fmt.Sprintf("Volatility: VIX %.1f (one-year percentile %.0f", v.VIX, v.Percentile1y)
+	if v.TermStructure != "" {
+		line += fmt.Sprintf(", term structure %s", v.TermStructure)
+	}
+	return line + fmt.Sprintf("), regime %s; size positions at %.2fx of the maximum", v.Regime, v.SizeMultiplier)
+}
@@ -0,0 +1,64 @@
+package volatility
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/internal/bartest"
+	"github.com/dyike/CortexGo/models"
+)
+
+// series returns n daily bars flat at base with the last close replaced by
+// last.
+func series(n int, base, last float64) []*models.MarketData {
+	closes := make([]float64, n)
+	for i := range closes {
+		closes[i] = base
+	}
+	closes[n-1] = last
+	return bartest.Bars(closes)
+}
+
+func TestAssessRaisesRegimeOnBackwardation(t *testing.T) {
+	// VIX jumps from 15 to 24 while VIX3M sits at 21: elevated, inverted, so stressed
+	v, err := Assess(context.Background(), bartest.Fetcher(map[string][]*models.MarketData{
+		".VIX9D.US": series(300, 14, 27),
+		".VIX.US":   series(300, 15, 24),
+		".VIX3M.US": series(300, 18, 21),
+	}), "")
+	if err != nil {
+		t.Fatalf("Assess: %v", err)
+	}
+	if len(v.Term) != 3 || v.Term[0].Tenor != "9D" || v.TermStructure != models.TermBackwardation {
+		t.Errorf("term = %+v, structure %s", v.Term, v.TermStructure)
+	}
+	if v.Regime != models.VolStressed || v.SizeMultiplier != 0.5 || v.Percentile1y != 100 || math.Round(v.Change5d) != 60 {
+		t.Errorf("regime = %+v", v)
+	}
+	if len(v.Notes) != 3 || !strings.Contains(v.Notes[0], "raised to stressed") {
+		t.Errorf("notes = %q", v.Notes)
+	}
+	if out := Render(v); !strings.Contains(out, "STRESSED") || !strings.Contains(out, "9D 27.00 → 30D 24.00 → 3M 21.00") {
+		t.Errorf("render:\n%s", out)
+	}
+}
+
+func TestAssessCalmContangoAndCutoff(t *testing.T) {
+	data := map[string][]*models.MarketData{
+		".VIX.US":   series(40, 13, 40),
+		".VIX3M.US": series(40, 16, 30),
+	}
+	// the spike on the last day lies after asOf
+	v, err := Assess(context.Background(), bartest.Fetcher(data), data[".VIX.US"][38].Date)
+	if err != nil {
+		t.Fatalf("Assess: %v", err)
+	}
+	if v.VIX != 13 || v.Regime != models.VolCalm || v.TermStructure != models.TermContango || v.SizeMultiplier != 1 {
+		t.Errorf("regime = %+v", v)
+	}
+	if _, err := Assess(context.Background(), bartest.Fetcher(nil), ""); err == nil {
+		t.Error("want error without VIX data")
+	}
+}
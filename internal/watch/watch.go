@@ -0,0 +1,195 @@
+// Package watch keeps one symbol under observation: it polls the quote and
+// the news feed incrementally and re-analyzes only when material new
+// information arrives, so a live session stays current without paying for a
+// full analysis on every poll.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/utils"
+)
+
+// Polling interval bounds.
+const (
+	DefaultInterval = 15 * time.Minute
+	MinInterval     = time.Minute
+)
+
+// Default materiality thresholds.
+const (
+	// DefaultMove is the price move, in percent since the last analysis,
+	// that warrants a refresh.
+	DefaultMove = 2.0
+	// DefaultMinQuality admits news from wire services and major outlets.
+	DefaultMinQuality = 0.85
+	// DefaultMinSentiment admits strongly worded news from any source.
+	DefaultMinSentiment = 0.5
+)
+
+// Analysis kinds reported in models.WatchEvent.Analysis.
+const (
+	Initial = "initial"
+	Refresh = "refresh"
+)
+
+// Update is what the watcher hands to Analyze: the latest quote and
+// everything that arrived since the previous analysis.
+type Update struct {
+	Kind  string // Initial or Refresh
+	Quote models.MarketQuote
+	// BasePrice is the price at the previous analysis, 0 for the first one.
+	BasePrice float64
+	// News holds every item that arrived since the previous analysis,
+	// material or not.
+	News []models.NewsItem
+	// Reasons say why the analysis runs.
+	Reasons []string
+}
+
+// Watcher polls one symbol. Quote and News are required; News must return
+// only items it has not returned before.
+type Watcher struct {
+	Symbol string
+	Quote  func(ctx context.Context) (models.MarketQuote, error)
+	News   func(ctx context.Context) ([]models.NewsItem, error)
+	// Analyze runs an analysis and returns its recommendation.
+	Analyze func(ctx context.Context, u Update) (string, error)
+	// OnEvent, if set, receives the outcome of every poll.
+	OnEvent func(models.WatchEvent)
+
+	// Thresholds; zero values take the defaults.
+	Move         float64
+	MinQuality   float64
+	MinSentiment float64
+
+	// Now defaults to time.Now.
+	Now func() time.Time
+
+	analyzed  bool
+	basePrice float64
+	pending   []models.NewsItem
+}
+
+// Check runs one poll. The first poll always analyzes; later ones only when
+// the price moved Move percent since the last analysis or a material news
+// item arrived. A failed analysis keeps the collected news for the next
+// attempt. Failing to read the news is reported in the event and the poll
+// carries on with the quote.
+func (w *Watcher) Check(ctx context.Context) (models.WatchEvent, error) {
+	now := time.Now
+	if w.Now != nil {
+		now = w.Now
+	}
+	q, err := w.Quote(ctx)
+	if err != nil {
+		return models.WatchEvent{}, fmt.Errorf("fetch quote: %w", err)
+	}
+	ev := models.WatchEvent{Symbol: w.Symbol, At: now(), Price: q.Last, BasePrice: w.basePrice}
+	fresh, err := w.News(ctx)
+	if err != nil {
+		ev.NewsError = err.Error()
+	}
+	ev.NewItems = fresh
+	w.pending = append(w.pending, fresh...)
+
+	kind := Refresh
+	if !w.analyzed {
+		kind = Initial
+		ev.Reasons = []string{"initial analysis"}
+	} else {
+		ev.Reasons = w.reasons(q, fresh)
+	}
+	if w.basePrice > 0 {
+		ev.MovePct = math.Round((q.Last-w.basePrice)/w.basePrice*10000) / 100
+	}
+	if len(ev.Reasons) == 0 {
+		w.emit(ev)
+		return ev, nil
+	}
+
+	ev.Analysis = kind
+	rec, err := w.Analyze(ctx, Update{Kind: kind, Quote: q, BasePrice: w.basePrice, News: w.pending, Reasons: ev.Reasons})
+	if err != nil {
+		ev.Error = err.Error()
+	} else {
+		ev.Recommendation = rec
+		w.analyzed, w.basePrice, w.pending = true, q.Last, nil
+	}
+	w.emit(ev)
+	return ev, nil
+}
+
+// reasons lists what makes this poll material, if anything.
+func (w *Watcher) reasons(q models.MarketQuote, fresh []models.NewsItem) []string {
+	move, minQuality, minSentiment := w.Move, w.MinQuality, w.MinSentiment
+	if move <= 0 {
+		move = DefaultMove
+	}
+	if minQuality <= 0 {
+		minQuality = DefaultMinQuality
+	}
+	if minSentiment <= 0 {
+		minSentiment = DefaultMinSentiment
+	}
+
+	var reasons []string
+	if w.basePrice > 0 && q.Last > 0 {
+		if pct := (q.Last - w.basePrice) / w.basePrice * 100; math.Abs(pct) >= move {
+			reasons = append(reasons, fmt.Sprintf("price moved %+.2f%% since the last analysis (%.2f -> %.2f)", pct, w.basePrice, q.Last))
+		}
+	}
+	for _, item := range fresh {
+		switch {
+		case item.Quality >= minQuality:
+			reasons = append(reasons, fmt.Sprintf("news from %s: %s", item.Source, item.Title))
+		case math.Abs(item.Sentiment) >= minSentiment:
+			reasons = append(reasons, fmt.Sprintf("strongly worded news (sentiment %+.2f): %s", item.Sentiment, item.Title))
+		}
+	}
+	return reasons
+}
+
+func (w *Watcher) emit(ev models.WatchEvent) {
+	if w.OnEvent != nil {
+		w.OnEvent(ev)
+	}
+}
+
+// Run calls Check every interval until ctx is done. Failed polls go to onErr
+// and the loop keeps going.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration, onErr func(error)) {
+	if interval < MinInterval {
+		interval = MinInterval
+	}
+	utils.Poll(ctx, interval, func(ctx context.Context) error {
+		_, err := w.Check(ctx)
+		return err
+	}, onErr)
+}
+
+// Prompt renders u as the request for a refresh: the usual analysis
+// request followed by why it runs and what arrived since the last analysis.
+func Prompt(symbol, tradeDate string, u Update) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Analyze trading opportunities for %s on %s", symbol, tradeDate)
+	if u.Kind != Refresh {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n\nThis is a refresh of an earlier analysis made at %.2f; the last price is %.2f. It runs because:\n", u.BasePrice, u.Quote.Last)
+	for _, r := range u.Reasons {
+		fmt.Fprintf(&b, "- %s\n", r)
+	}
+	if len(u.News) > 0 {
+		b.WriteString("\nNews since the earlier analysis:\n")
+		for _, item := range u.News {
+			fmt.Fprintf(&b, "- %s (%s, %s, sentiment %+.2f)\n", item.Title, item.Source, item.PublishedAt.Format("2006-01-02 15:04"), item.Sentiment)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
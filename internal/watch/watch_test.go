@@ -0,0 +1,91 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+type feed struct {
+	price float64
+	news  []models.NewsItem
+	fail  error
+	runs  []Update
+}
+
+func (f *feed) watcher() *Watcher {
+	return &Watcher{
+		Symbol: "AAPL.US",
+		Quote: func(context.Context) (models.MarketQuote, error) {
+			return models.MarketQuote{Symbol: "AAPL.US", Last: f.price}, nil
+		},
+		News: func(context.Context) ([]models.NewsItem, error) {
+			out := f.news
+			f.news = nil
+			return out, nil
+		},
+		Analyze: func(_ context.Context, u Update) (string, error) {
+			f.runs = append(f.runs, u)
+			if f.fail != nil {
+				return "", f.fail
+			}
+			return "HOLD", nil
+		},
+	}
+}
+
+func TestWatcherRefreshesOnlyOnMaterialChange(t *testing.T) {
+	f := &feed{price: 100}
+	w := f.watcher()
+	ctx := context.Background()
+
+	ev, err := w.Check(ctx)
+	if err != nil || ev.Analysis != Initial || ev.Recommendation != "HOLD" {
+		t.Fatalf("first poll = %+v, %v; want the initial analysis", ev, err)
+	}
+
+	// small move and a low-quality, neutral headline: nothing to do
+	f.price = 101
+	f.news = []models.NewsItem{{Title: "AAPL trades sideways", Source: "blog", Quality: 0.3, Sentiment: 0.1}}
+	if ev, _ := w.Check(ctx); ev.Analysis != "" || len(f.runs) != 1 {
+		t.Fatalf("quiet poll = %+v, want no analysis", ev)
+	}
+
+	// a wire story is material; the quiet headline is passed along too
+	f.news = []models.NewsItem{{Title: "Apple cuts guidance", Source: "Reuters", Quality: 1}}
+	ev, _ = w.Check(ctx)
+	if ev.Analysis != Refresh || len(f.runs) != 2 {
+		t.Fatalf("news poll = %+v, want a refresh", ev)
+	}
+	if u := f.runs[1]; u.BasePrice != 100 || len(u.News) != 2 || len(u.Reasons) != 1 {
+		t.Errorf("refresh update = %+v", u)
+	}
+
+	// the base moved to 101; a 2% move from there triggers
+	f.price = 103.1
+	ev, _ = w.Check(ctx)
+	if ev.Analysis != Refresh || ev.MovePct != 2.08 || len(f.runs[2].News) != 0 {
+		t.Fatalf("price poll = %+v, update = %+v", ev, f.runs[2])
+	}
+}
+
+func TestWatcherKeepsNewsWhenAnalysisFails(t *testing.T) {
+	f := &feed{price: 50, fail: errors.New("model down")}
+	w := f.watcher()
+	ctx := context.Background()
+
+	f.news = []models.NewsItem{{Title: "first", Quality: 1}}
+	if ev, _ := w.Check(ctx); ev.Error == "" || ev.Analysis != Initial {
+		t.Fatalf("failed poll = %+v", ev)
+	}
+	f.fail = nil
+	f.news = []models.NewsItem{{Title: "second", Quality: 0.2}}
+	if ev, _ := w.Check(ctx); ev.Analysis != Initial {
+		t.Fatalf("retry = %+v, want the initial analysis again", ev)
+	}
+	if got := len(f.runs[1].News); got != 2 {
+		t.Errorf("retry saw %d news items, want both", got)
+	}
+}
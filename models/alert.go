@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// 价格提醒的触发条件
+const (
+	AlertAbove = "above" // 最新价高于等于阈值
+	AlertBelow = "below" // 最新价低于等于阈值
+	AlertMove  = "move"  // 相对昨收的涨跌幅绝对值（百分比）大于等于阈值
+)
+
+// 价格提醒状态
+const (
+	AlertStatusActive    = "active"
+	AlertStatusTriggered = "triggered" // 一次性提醒触发后不再监控
+)
+
+// Alert 价格提醒，守护进程监控行情，条件满足时启动一次新的分析并推送通知
+type Alert struct {
+	Id        int64   `json:"id"`
+	Symbol    string  `json:"symbol"`
+	Condition string  `json:"condition"` // above/below/move
+	Threshold float64 `json:"threshold"`
+	// Repeat 为 true 时触发后保持监控，条件解除后可再次触发；否则触发一次即结束
+	Repeat bool   `json:"repeat"`
+	Status string `json:"status"`
+	// Armed 为 false 表示条件仍处于满足状态，等待解除后才能再次触发
+	Armed         bool      `json:"armed"`
+	TriggeredAt   time.Time `json:"triggered_at,omitempty"`
+	TriggerPrice  float64   `json:"trigger_price,omitempty"`
+	LastSessionId string    `json:"last_session_id,omitempty"` // 最近一次触发启动的分析会话
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// AlertAddParams alerts.add 入参，above/below/move 三选一
+type AlertAddParams struct {
+	Symbol string  `json:"symbol" rpc:"required"`
+	Above  float64 `json:"above,omitempty"`
+	Below  float64 `json:"below,omitempty"`
+	Move   float64 `json:"move,omitempty"` // 涨跌幅百分比，如 5 表示 ±5%
+	Repeat bool    `json:"repeat,omitempty"`
+}
+
+// AlertListParams alerts.list 入参
+type AlertListParams struct {
+	Symbol string `json:"symbol"`
+	All    bool   `json:"all"` // 同时列出已触发的一次性提醒
+}
+
+// AlertDeleteParams alerts.del 入参
+type AlertDeleteParams struct {
+	Id int64 `json:"id" rpc:"required"`
+}
+
+// AlertWatchParams alerts.start 入参
+type AlertWatchParams struct {
+	Interval int `json:"interval"` // 行情轮询间隔（秒），默认 60，最小 10
+}
+
+// AlertWatchResponse alerts.start / alerts.stop 出参
+type AlertWatchResponse struct {
+	Running  bool `json:"running"`
+	Interval int  `json:"interval,omitempty"`
+	Alerts   int  `json:"alerts"` // 监控中的提醒数
+}
+
+// AlertTrigger alert.triggered 事件与 webhook 负载
+type AlertTrigger struct {
+	Alert     Alert   `json:"alert"`
+	Price     float64 `json:"price"`
+	ChangePct float64 `json:"change_pct"`
+	SessionId string  `json:"session_id,omitempty"` // 启动的分析会话，启动失败时为空
+	Error     string  `json:"error,omitempty"`
+}
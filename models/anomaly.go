@@ -0,0 +1,44 @@
+package models
+
+// 异常类型
+const (
+	AnomalyGapUp       = "gap_up"               // 开盘跳空高开
+	AnomalyGapDown     = "gap_down"             // 开盘跳空低开
+	AnomalyVolumeSpike = "volume_spike"         // 成交量异常放大
+	AnomalyVolatility  = "volatility_expansion" // 当日波幅异常放大
+)
+
+// PriceAnomaly 回看窗口内的一个异常交易日；Score 越高越值得新闻分析师排查当天的新闻
+type PriceAnomaly struct {
+	Date      string   `json:"date"`
+	Flags     []string `json:"flags"`
+	Close     float64  `json:"close"`
+	ChangePct float64  `json:"change_pct"`         // 相对前一日收盘的涨跌幅
+	GapPct    float64  `json:"gap_pct,omitempty"`  // 开盘相对前一日收盘的跳空幅度
+	VolumeZ   float64  `json:"volume_z,omitempty"` // 成交量相对前 20 日的标准分
+	RangeX    float64  `json:"range_x,omitempty"`  // 当日真实波幅为前 20 日中位数的倍数
+	NewsFrom  string   `json:"news_from"`          // 应从这一天起排查新闻：跳空时为前一交易日（收盘后的消息）
+	Score     float64  `json:"score"`
+}
+
+// AnomalyReport detect_anomalies 的结果，Anomalies 按 Score 从高到低排列
+type AnomalyReport struct {
+	Symbol    string         `json:"symbol"`
+	AsOf      string         `json:"as_of"`
+	Bars      int            `json:"bars"` // 回看窗口内的交易日数
+	Anomalies []PriceAnomaly `json:"anomalies,omitempty"`
+	// VolatilityRatio 最近 5 日与此前 20 日日收益率标准差之比，大于 1 表示波动正在放大
+	VolatilityRatio float64 `json:"volatility_ratio"`
+}
+
+// AnomalyInput detect_anomalies 工具入参
+type AnomalyInput struct {
+	Symbol     string `json:"symbol"`
+	Lookback   int    `json:"lookback"`
+	BeforeDate string `json:"before_date"`
+}
+
+// AnomalyOutput detect_anomalies 工具出参
+type AnomalyOutput struct {
+	Result string `json:"result"`
+}
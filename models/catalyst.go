@@ -0,0 +1,32 @@
+package models
+
+// 催化剂类型
+const (
+	CatalystEarnings = "earnings"
+	CatalystLockup   = "lockup"
+	CatalystEconomic = "economic"
+)
+
+// Catalyst 交易日之后可能引发股价波动的日程事件（财报、解禁、宏观数据与议息会议）
+type Catalyst struct {
+	Date      string `json:"date"`           // YYYY-MM-DD
+	Time      string `json:"time,omitempty"` // 财报时段 bmo（盘前）/ amc（盘后），或宏观事件的美东时间 HH:MM
+	Kind      string `json:"kind"`
+	Symbol    string `json:"symbol,omitempty"` // 宏观事件为空
+	Title     string `json:"title"`
+	Detail    string `json:"detail,omitempty"`
+	Estimated bool   `json:"estimated,omitempty"` // 日期为推算值（如按 IPO 日期推算的解禁日），需以公告为准
+	Source    string `json:"source"`
+}
+
+// CatalystsInput get_upcoming_catalysts 工具入参
+type CatalystsInput struct {
+	Symbol    string `json:"symbol"`
+	TradeDate string `json:"trade_date"`
+	Days      int    `json:"days"`
+}
+
+// CatalystsOutput get_upcoming_catalysts 工具出参
+type CatalystsOutput struct {
+	Result string `json:"result"`
+}
@@ -0,0 +1,48 @@
+package models
+
+// CorrelationMatrix 一组标的日收益率的两两相关系数及集中度风险提示
+type CorrelationMatrix struct {
+	Symbols            []string             `json:"symbols"`
+	Window             int                  `json:"window"` // 日收益率个数
+	AsOf               string               `json:"as_of,omitempty"`
+	Matrix             [][]float64          `json:"matrix"` // 与 Symbols 同序
+	AverageCorrelation float64              `json:"average_correlation"`
+	HighPairs          []CorrelatedPair     `json:"high_pairs,omitempty"`
+	Clusters           []CorrelationCluster `json:"clusters,omitempty"`
+	Warnings           []string             `json:"warnings,omitempty"`
+	Missing            []string             `json:"missing,omitempty"`  // 行情不足而未参与计算的标的
+	Currency           string               `json:"currency,omitempty"` // 跨币种比较时统一换算成的币种
+	Notes              []string             `json:"notes,omitempty"`    // 汇率换算说明
+}
+
+// CorrelatedPair 相关系数超过阈值的一对标的
+type CorrelatedPair struct {
+	A           string  `json:"a"`
+	B           string  `json:"b"`
+	Correlation float64 `json:"correlation"`
+}
+
+// CorrelationCluster 彼此高度相关、实际上是同一笔押注的一组标的；Weight 为其在持仓中的占比（有持仓权重时）
+type CorrelationCluster struct {
+	Symbols []string `json:"symbols"`
+	Weight  float64  `json:"weight,omitempty"`
+}
+
+// CorrelationInput get_correlation_matrix 工具入参
+type CorrelationInput struct {
+	Symbols    []string `json:"symbols"`
+	Window     int      `json:"window"`
+	BeforeDate string   `json:"before_date"`
+}
+
+// CorrelationOutput get_correlation_matrix 工具出参
+type CorrelationOutput struct {
+	Result string `json:"result"`
+}
+
+// PortfolioRiskParams portfolio.risk 入参；Symbols 为空时使用已同步的持仓
+type PortfolioRiskParams struct {
+	Symbols   []string `json:"symbols,omitempty"`
+	Window    int      `json:"window,omitempty"`
+	TradeDate string   `json:"trade_date,omitempty"`
+}
@@ -16,4 +16,16 @@ type TradingDecision struct {
 	StopLoss     float64 `json:"stop_loss"`
 	TakeProfit   float64 `json:"take_profit"`
 	PositionSize float64 `json:"position_size"`
+
+	// CalibratedConfidence 按该 agent 历史命中率校准后的置信度，样本不足时为 0
+	CalibratedConfidence float64 `json:"calibrated_confidence,omitempty"`
+	// HoldingDays 风控裁判给出的计划持有交易日数
+	HoldingDays int `json:"holding_days,omitempty"`
+}
+
+// AgentConfidence 某个 agent 在一次分析中给出的建议与声明置信度
+type AgentConfidence struct {
+	Agent      string  `json:"agent"`
+	Action     string  `json:"action"`
+	Confidence float64 `json:"confidence"`
 }
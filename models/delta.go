@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// DeltaAnalysis 增量分析的结果：基于上次完整分析与此后的新数据给出的更新结论
+type DeltaAnalysis struct {
+	Symbol    string `json:"symbol"`
+	TradeDate string `json:"trade_date"`
+	// 作为基准的完整分析
+	BaseSessionId   string    `json:"base_session_id,omitempty"`
+	BaseGeneratedAt time.Time `json:"base_generated_at"`
+	BasePrice       float64   `json:"base_price,omitempty"` // 上次分析时的价格
+	Price           float64   `json:"price,omitempty"`      // 本次使用的最新价格
+	NewsCount       int       `json:"news_count"`           // 提供给模型的新新闻条数
+
+	PreviousRecommendation string `json:"previous_recommendation,omitempty"`
+	// Recommendation 更新后的建议；模型未给出时沿用上次的建议
+	Recommendation string  `json:"recommendation"`
+	Changed        bool    `json:"changed"` // 建议是否与上次不同
+	Confidence     float64 `json:"confidence,omitempty"`
+	// Decision 从更新后的计划中解析出的价位与仓位
+	Decision *TradingDecision `json:"decision,omitempty"`
+	Content  string           `json:"content"`
+
+	GeneratedAt time.Time `json:"generated_at"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import "time"
+
+// 文档类型，导入时未指定则为 DocumentKindOther
+const (
+	DocumentKindAnnualReport   = "annual_report"
+	DocumentKindBrokerReport   = "broker_report"
+	DocumentKindEarningsSlides = "earnings_slides"
+	DocumentKindFiling         = "filing"
+	DocumentKindOther          = "other"
+)
+
+// DocumentRecord 导入的外部文档（年报、券商研报、业绩演示稿等）
+type DocumentRecord struct {
+	Id        int64     `json:"id"`
+	Symbol    string    `json:"symbol"`
+	Title     string    `json:"title"`
+	Kind      string    `json:"kind"`
+	Source    string    `json:"source"` // 导入时的文件路径
+	Sha256    string    `json:"sha256"`
+	Pages     int       `json:"pages"`
+	Chunks    int       `json:"chunks"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DocumentChunk 文档切块及其向量，Page 从 1 开始，非 PDF 文档为 0
+type DocumentChunk struct {
+	DocumentId int64
+	Seq        int
+	Page       int
+	Content    string
+	Embedder   string
+	Vector     []float32
+	// 查询时从 documents 表带出
+	Symbol string
+	Title  string
+	Kind   string
+}
+
+// DocumentChunkFilter 文档切块查询条件
+type DocumentChunkFilter struct {
+	Symbol   string // 模糊匹配，同 RunFilter；未指定标的的文档（如行业研报）始终匹配
+	Kind     string
+	Embedder string
+}
+
+// DocumentIngestParams documents.ingest 入参
+type DocumentIngestParams struct {
+	Path   string `json:"path" rpc:"required"` // pdf、txt、md 或 html 文件
+	Symbol string `json:"symbol"`
+	Title  string `json:"title"` // 默认取文件名
+	Kind   string `json:"kind"`  // annual_report/broker_report/earnings_slides/filing/other
+}
+
+// DocumentListParams documents.list 入参
+type DocumentListParams struct {
+	Symbol string `json:"symbol"`
+}
+
+// DocumentDeleteParams documents.del 入参
+type DocumentDeleteParams struct {
+	Id int64 `json:"id" rpc:"required"`
+}
+
+// DocumentDeleteResponse 删除文档的结果
+type DocumentDeleteResponse struct {
+	Id      int64 `json:"id"`
+	Deleted bool  `json:"deleted"`
+}
+
+// QueryDocumentsInput query_documents 工具入参
+type QueryDocumentsInput struct {
+	Query  string `json:"query"`
+	Symbol string `json:"symbol"`
+	Kind   string `json:"kind"`
+	Limit  int    `json:"limit"`
+}
+
+// DocumentPassage 文档中与查询相关的一段
+type DocumentPassage struct {
+	DocumentID int64   `json:"document_id"`
+	Title      string  `json:"title"`
+	Symbol     string  `json:"symbol"`
+	Kind       string  `json:"kind"`
+	Page       int     `json:"page,omitempty"`
+	Content    string  `json:"content"`
+	Score      float64 `json:"score"`
+}
+
+// QueryDocumentsOutput query_documents 工具出参
+type QueryDocumentsOutput struct {
+	Results []DocumentPassage `json:"results"`
+	Result  string            `json:"result"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Evidence 一次工具调用的输出，是 agent 结论可追溯的出处
+type Evidence struct {
+	ID         string      `json:"id"`                    // 证据编号，如 E3，工具输出以 [E3] 开头供 agent 引用
+	Agent      string      `json:"agent"`                 // 调用工具的 agent
+	Tool       string      `json:"tool"`                  // 工具名
+	Arguments  string      `json:"arguments,omitempty"`   // 调用参数（JSON）
+	Excerpt    string      `json:"excerpt"`               // 输出摘录
+	DataPoints []string    `json:"data_points,omitempty"` // 输出中的数值（价格、百分比等），用于匹配结论中的数据
+	Provenance *Provenance `json:"provenance,omitempty"`  // 数据来源与新鲜度，工具未上报时为空
+	Digest     string      `json:"digest,omitempty"`      // 完整输出的摘要，用于比较两次运行的数据是否一致
+	// ToolVersion 工具定义（描述与参数）的摘要，工具改动后不同
+	ToolVersion string    `json:"tool_version,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ToolResult 一次工具调用的完整参数与输出，开启 export_tool_data 时记录，运行结束后写入数据包
+type ToolResult struct {
+	EvidenceID string `json:"evidence_id"`         // 对应的证据编号
+	Arguments  string `json:"arguments,omitempty"` // 调用参数（JSON，未截断）
+	Output     string `json:"output"`              // 工具原始输出（未截断）
+}
+
+// 数据获取方式
+const (
+	ProvenanceLive      = "live"      // 实时请求数据源
+	ProvenanceCache     = "cache"     // 全部来自本地缓存
+	ProvenanceMixed     = "mixed"     // 部分缓存、部分实时
+	ProvenanceArchive   = "archive"   // 离线数据集或网页存档
+	ProvenanceMock      = "mock"      // 模拟数据
+	ProvenanceLocal     = "local"     // 本地资料（历史分析、用户文档等）
+	ProvenanceSimulated = "simulated" // 模拟数据源生成的数据
+)
+
+// Provenance 工具输出的数据来源：来自哪个数据源、何时获取、是否命中缓存、数据截至哪天
+type Provenance struct {
+	Source      string    `json:"source"`
+	Mode        string    `json:"mode"`                   // live / cache / mixed / archive / mock / local / simulated
+	FetchedAt   time.Time `json:"fetched_at"`             // 数据获取时间，缓存命中时为最早一条缓存的写入时间
+	AsOf        string    `json:"as_of,omitempty"`        // 数据截至日期（最新一条新闻、K线等的日期）
+	CacheHits   int       `json:"cache_hits,omitempty"`   // 缓存命中次数
+	CacheMisses int       `json:"cache_misses,omitempty"` // 缓存未命中次数
+}
+
+// EvidenceClaim 报告中的一句结论及其引用的证据
+type EvidenceClaim struct {
+	Section    string   `json:"section"`               // 结论所在报告段落的 key
+	Text       string   `json:"text"`                  // 结论原文
+	Evidence   []string `json:"evidence"`              // 支撑该结论的证据编号
+	DataPoints []string `json:"data_points,omitempty"` // 与证据吻合的数值
+	Cited      bool     `json:"cited"`                 // agent 是否显式标注了 [E#]，否则为数值匹配推断
+}
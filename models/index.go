@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// IndexMember 指数成分股；Symbol 为长桥格式（AAPL.US、700.HK）
+type IndexMember struct {
+	Symbol string `json:"symbol"`
+	Name   string `json:"name,omitempty"`
+	Sector string `json:"sector,omitempty"`
+}
+
+// IndexConstituents 指数成分股列表
+type IndexConstituents struct {
+	Index     string        `json:"index"` // SP500 / NDX / HSI
+	Name      string        `json:"name"`
+	Source    string        `json:"source"` // 成分股列表的来源页面
+	FetchedAt time.Time     `json:"fetched_at"`
+	Members   []IndexMember `json:"members"`
+}
+
+// IndexConstituentsParams index.constituents 入参
+type IndexConstituentsParams struct {
+	Index  string `json:"index" rpc:"required"`
+	Sector string `json:"sector,omitempty"` // 只保留行业名包含该文本的成分股（不区分大小写）
+}
+
+// IndexBreadthParams index.breadth 入参
+type IndexBreadthParams struct {
+	Index     string `json:"index" rpc:"required"`
+	Sector    string `json:"sector,omitempty"`
+	TradeDate string `json:"trade_date,omitempty"` // YYYY-MM-DD，只使用当天及之前的行情
+}
+
+// IndexBreadth 指数成分股的市场广度统计；比例均为 0–1，分母为有足够行情的成分股
+type IndexBreadth struct {
+	Index     string   `json:"index"`
+	AsOf      string   `json:"as_of"`
+	Members   int      `json:"members"`
+	Counted   int      `json:"counted"`
+	Missing   []string `json:"missing,omitempty"` // 行情不足而未计入的成分股
+	Advancers int      `json:"advancers"`
+	Decliners int      `json:"decliners"`
+	Unchanged int      `json:"unchanged"`
+	Above50   float64  `json:"above_50"`  // 站上 50 日均线的比例
+	Above200  float64  `json:"above_200"` // 站上 200 日均线的比例（不足 200 根K线的不计入）
+	NewHighs  int      `json:"new_highs"` // 收盘创 52 周新高
+	NewLows   int      `json:"new_lows"`
+	// Sectors 按行业的 50 日均线广度，按比例从高到低
+	Sectors []SectorBreadth `json:"sectors,omitempty"`
+}
+
+// SectorBreadth 单个行业的广度
+type SectorBreadth struct {
+	Sector  string  `json:"sector"`
+	Counted int     `json:"counted"`
+	Above50 float64 `json:"above_50"`
+}
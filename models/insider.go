@@ -0,0 +1,44 @@
+package models
+
+// 内部人交易倾向
+const (
+	InsiderNetBuying  = "net buying"
+	InsiderNetSelling = "net selling"
+	InsiderNeutral    = "neutral"
+)
+
+// InsiderSentimentMonth Finnhub 按月汇总的内部人交易：Change 为净买入股数（负数为净卖出），
+// MSPR（Monthly Share Purchase Ratio）取值 -100 到 100，越高买入越积极
+type InsiderSentimentMonth struct {
+	Year   int     `json:"year"`
+	Month  int     `json:"month"`
+	Change int64   `json:"change"`
+	MSPR   float64 `json:"mspr"`
+}
+
+// InsiderSentiment 内部人交易倾向摘要；只统计交易日之前已结束的月份
+type InsiderSentiment struct {
+	Symbol string                  `json:"symbol"`
+	AsOf   string                  `json:"as_of"`  // 最后一个统计月份，YYYY-MM
+	Months []InsiderSentimentMonth `json:"months"` // 由近及远
+	// 最近 3 个月与 12 个月（有交易的月份）的平均 MSPR 与净买入股数合计
+	MSPR3m        float64  `json:"mspr_3m"`
+	MSPR12m       float64  `json:"mspr_12m"`
+	NetShares3m   int64    `json:"net_shares_3m"`
+	NetShares12m  int64    `json:"net_shares_12m"`
+	BuyingMonths  int      `json:"buying_months"`  // 最近 12 个月中净买入的月数
+	SellingMonths int      `json:"selling_months"` // 最近 12 个月中净卖出的月数
+	Trend         string   `json:"trend"`
+	Notes         []string `json:"notes,omitempty"`
+}
+
+// InsiderSentimentInput get_insider_sentiment 工具入参
+type InsiderSentimentInput struct {
+	Symbol     string `json:"symbol"`
+	BeforeDate string `json:"before_date"`
+}
+
+// InsiderSentimentOutput get_insider_sentiment 工具出参
+type InsiderSentimentOutput struct {
+	Result string `json:"result"`
+}
@@ -0,0 +1,67 @@
+package models
+
+// 跨市场因素的角色
+const (
+	DriverSector    = "sector"
+	DriverDollar    = "dollar"
+	DriverCommodity = "commodity"
+)
+
+// 跨市场信号：对做多是顺风、逆风、无明显影响，或只作背景参考（与个股无固定方向关系）
+const (
+	SignalTailwind = "tailwind"
+	SignalHeadwind = "headwind"
+	SignalNeutral  = "neutral"
+	SignalContext  = "context"
+)
+
+// 跨市场结论
+const (
+	IntermarketSupportive  = "supportive"
+	IntermarketConflicting = "conflicting"
+	IntermarketMixed       = "mixed"
+)
+
+// IntermarketDriver 一个跨市场因素（行业 ETF、美元或商品）在回看窗口内的表现
+type IntermarketDriver struct {
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+	Role   string `json:"role"` // DriverSector/DriverDollar/DriverCommodity
+	// Relation 该因素上涨对个股的通常影响：1 利好、-1 利空、0 无固定方向
+	Relation    int     `json:"relation"`
+	Return      float64 `json:"return"`      // 窗口内涨跌幅，百分比
+	Correlation float64 `json:"correlation"` // 与个股日收益的相关系数
+	Signal      string  `json:"signal"`
+	Why         string  `json:"why"` // 影响机制的简短说明
+}
+
+// IntermarketReport 个股与其行业 ETF、美元和相关商品的对比
+type IntermarketReport struct {
+	Symbol   string  `json:"symbol"`
+	Sector   string  `json:"sector"` // 未识别行业时为空，以大盘 ETF 作对照
+	AsOf     string  `json:"as_of"`
+	Lookback int     `json:"lookback"`
+	Return   float64 `json:"return"` // 个股窗口内涨跌幅，百分比
+	// RelativeToSector 个股相对行业 ETF 的超额涨跌幅，百分比
+	RelativeToSector float64             `json:"relative_to_sector"`
+	Drivers          []IntermarketDriver `json:"drivers"`
+	Supportive       int                 `json:"supportive"`  // 顺风因素数
+	Conflicting      int                 `json:"conflicting"` // 逆风因素数
+	Verdict          string              `json:"verdict"`
+	Missing          []string            `json:"missing,omitempty"`  // 取不到行情的因素
+	Currency         string              `json:"currency,omitempty"` // 比较所用的计价币种，非该币种的个股行情已按汇率换算
+	Notes            []string            `json:"notes,omitempty"`
+}
+
+// IntermarketInput get_intermarket_analysis 工具入参
+type IntermarketInput struct {
+	Symbol     string `json:"symbol"`
+	Sector     string `json:"sector"`
+	Lookback   int    `json:"lookback"`
+	BeforeDate string `json:"before_date"`
+}
+
+// IntermarketOutput get_intermarket_analysis 工具出参
+type IntermarketOutput struct {
+	Result string `json:"result"`
+}
@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// 交易方向
+const (
+	JournalLong  = "long"
+	JournalShort = "short"
+)
+
+// JournalEntry 交易日志：用户实际执行的一笔交易，可关联触发它的分析会话
+type JournalEntry struct {
+	Id        int64  `json:"id"`
+	SessionId int64  `json:"session_id,omitempty"` // 关联的分析会话，0 表示未关联
+	Symbol    string `json:"symbol"`
+	Side      string `json:"side"` // long/short
+	// Recommendation 关联会话的最终建议，用于统计“照建议执行”的交易
+	Recommendation string    `json:"recommendation,omitempty"`
+	Quantity       float64   `json:"quantity"`
+	EntryPrice     float64   `json:"entry_price"`
+	EntryDate      string    `json:"entry_date"`
+	ExitPrice      float64   `json:"exit_price,omitempty"` // 0 表示仍持有
+	ExitDate       string    `json:"exit_date,omitempty"`
+	Fees           float64   `json:"fees,omitempty"`
+	Currency       string    `json:"currency"`
+	Notes          string    `json:"notes,omitempty"`
+	RealizedPnL    float64   `json:"realized_pnl,omitempty"` // 平仓后按数量与手续费计算
+	ReturnPct      float64   `json:"return_pct,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Closed 是否已平仓
+func (e JournalEntry) Closed() bool {
+	return e.ExitPrice > 0
+}
+
+// JournalAddParams journal.add 入参；给出 session_id 时标的与方向可省略，按该次分析的标的与建议补全
+type JournalAddParams struct {
+	SessionID  string  `json:"session_id,omitempty"`
+	Symbol     string  `json:"symbol,omitempty"`
+	Side       string  `json:"side,omitempty"` // long/short，默认按建议：SELL 为 short，其余为 long
+	Quantity   float64 `json:"quantity" rpc:"required"`
+	EntryPrice float64 `json:"entry_price" rpc:"required"`
+	EntryDate  string  `json:"entry_date,omitempty"` // YYYY-MM-DD，默认今天
+	ExitPrice  float64 `json:"exit_price,omitempty"`
+	ExitDate   string  `json:"exit_date,omitempty"`
+	Fees       float64 `json:"fees,omitempty"`
+	Currency   string  `json:"currency,omitempty"` // 默认按市场后缀：US 为 USD、HK 为 HKD、SH/SZ 为 CNY
+	Notes      string  `json:"notes,omitempty"`
+}
+
+// JournalCloseParams journal.close 入参，记录平仓
+type JournalCloseParams struct {
+	Id        int64   `json:"id" rpc:"required"`
+	ExitPrice float64 `json:"exit_price" rpc:"required"`
+	ExitDate  string  `json:"exit_date,omitempty"` // 默认今天
+	Fees      float64 `json:"fees,omitempty"`      // 平仓手续费，累加到已有手续费
+	Notes     string  `json:"notes,omitempty"`     // 追加到已有备注
+}
+
+// JournalListParams journal.list 入参
+type JournalListParams struct {
+	Symbol    string `json:"symbol,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	Open      bool   `json:"open,omitempty"` // 只列出未平仓的交易
+}
+
+// JournalDeleteParams journal.del 入参
+type JournalDeleteParams struct {
+	Id int64 `json:"id" rpc:"required"`
+}
+
+// JournalStats 交易日志汇总，计入结果统计的计分板
+type JournalStats struct {
+	Trades int `json:"trades"`
+	Open   int `json:"open"`
+	Closed int `json:"closed"`
+	Wins   int `json:"wins"`
+	// WinRate 已平仓交易中盈利的比例
+	WinRate      float64 `json:"win_rate"`
+	AvgReturnPct float64 `json:"avg_return_pct"`
+	// RealizedPnL 按币种汇总的已实现盈亏（扣除手续费）
+	RealizedPnL map[string]float64 `json:"realized_pnl"`
+	// Followed 已平仓交易中方向与关联分析建议一致的笔数，FollowedReturnPct 为其平均收益
+	Followed          int     `json:"followed"`
+	FollowedReturnPct float64 `json:"followed_return_pct"`
+}
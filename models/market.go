@@ -9,6 +9,8 @@ type MarketDataInput struct {
 
 type MarketDataOutput struct {
 	Data []*MarketData `json:"data"`
+	// 日K线只含常规交易时段；实时拉取时附带最新的盘前/盘后/夜盘行情
+	ExtendedHours []ExtendedQuote `json:"extended_hours,omitempty"`
 }
 
 type MarketData struct {
@@ -19,6 +21,8 @@ type MarketData struct {
 	Low    float64 `json:"low"`
 	Open   float64 `json:"open"`
 	Close  float64 `json:"close"`
+	// 报价币种（USD、HKD、CNY、SGD），由标的市场后缀推断；旧缓存数据可能为空
+	Currency string `json:"currency,omitempty"`
 }
 
 // StockIndicatorInput represents the input for technical indicator analysis
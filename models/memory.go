@@ -0,0 +1,26 @@
+package models
+
+// PastAnalysesInput search_past_analyses 工具入参
+type PastAnalysesInput struct {
+	Query      string `json:"query"`
+	Symbol     string `json:"symbol"`
+	BeforeDate string `json:"before_date"`
+	Limit      int    `json:"limit"`
+}
+
+// PastAnalysis 历史报告中与查询相关的一段
+type PastAnalysis struct {
+	SessionID      int64   `json:"session_id"`
+	Symbol         string  `json:"symbol"`
+	TradeDate      string  `json:"trade_date"`
+	Recommendation string  `json:"recommendation,omitempty"`
+	Section        string  `json:"section"`
+	Content        string  `json:"content"`
+	Score          float64 `json:"score"`
+}
+
+// PastAnalysesOutput search_past_analyses 工具出参
+type PastAnalysesOutput struct {
+	Results []PastAnalysis `json:"results"`
+	Result  string         `json:"result"`
+}
@@ -2,6 +2,8 @@ package models
 
 // ChatResp represents a chat response from an agent
 type ChatResp struct {
+	// SessionId 推送给 App 时填充，用于区分并发运行的多个分析
+	SessionId  string      `json:"session_id,omitempty"`
 	AgentName  string      `json:"agent_name"`
 	Role       string      `json:"role"`
 	Content    string      `json:"content"`
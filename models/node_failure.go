@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// NodeFailure 分析中一次被恢复的 panic：工具或 agent 的模型调用崩溃后记录在此，分析继续进行
+type NodeFailure struct {
+	Agent string    `json:"agent"`          // 发生 panic 的 agent
+	Tool  string    `json:"tool,omitempty"` // 发生 panic 的工具；模型调用崩溃时为空
+	Error string    `json:"error"`          // panic 的值
+	Stack string    `json:"stack,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// FailedToolOutput 工具 panic 时代替原结果返回给 agent 的内容
+type FailedToolOutput struct {
+	Status string `json:"status"` // 固定为 failed
+	Tool   string `json:"tool"`
+	Error  string `json:"error"`
+	Result string `json:"result"` // 给 agent 的说明
+}
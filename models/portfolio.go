@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// 持仓来源
+const (
+	PortfolioSourceLongport = "longport"
+	PortfolioSourceCSV      = "csv"
+)
+
+// Portfolio 账户持仓与现金快照，每次同步或导入整体替换
+type Portfolio struct {
+	Source    string              `json:"source"`             // longport/csv
+	Currency  string              `json:"currency,omitempty"` // NetAssets 的币种
+	NetAssets float64             `json:"net_assets,omitempty"`
+	Positions []PortfolioPosition `json:"positions"`
+	Cash      []PortfolioCash     `json:"cash"`
+	SyncedAt  time.Time           `json:"synced_at"`
+}
+
+// PortfolioPosition 单个股票持仓，CostPrice 为持仓均价
+type PortfolioPosition struct {
+	Symbol            string  `json:"symbol"`
+	Name              string  `json:"name,omitempty"`
+	Quantity          float64 `json:"quantity"`
+	AvailableQuantity float64 `json:"available_quantity"`
+	CostPrice         float64 `json:"cost_price"`
+	Currency          string  `json:"currency"`
+	Market            string  `json:"market,omitempty"`
+}
+
+// PortfolioCash 单一币种的现金余额
+type PortfolioCash struct {
+	Currency  string  `json:"currency"`
+	Available float64 `json:"available"`
+	Frozen    float64 `json:"frozen,omitempty"`
+}
+
+// PortfolioSyncParams portfolio.sync 入参
+type PortfolioSyncParams struct {
+	Source string `json:"source"` // longport（默认）或 csv
+	Path   string `json:"path"`   // source 为 csv 时的文件路径
+	// source 为 longport 时读取的账户名，为空时使用配置的 longport_profile
+	Account string `json:"account"`
+}
@@ -0,0 +1,58 @@
+package models
+
+// 收益率曲线形态（按 2s10s 利差）
+const (
+	CurveNormal   = "normal"
+	CurveFlat     = "flat"
+	CurveInverted = "inverted"
+)
+
+// 近一个月收益率曲线的变化方式：bear 为收益率上行，bull 为下行；steepening/flattening 看长短端谁动得多
+const (
+	CurveBearSteepening = "bear steepening"
+	CurveBearFlattening = "bear flattening"
+	CurveBullSteepening = "bull steepening"
+	CurveBullFlattening = "bull flattening"
+	CurveParallelRise   = "parallel rise"
+	CurveParallelFall   = "parallel fall"
+	CurveStable         = "stable"
+)
+
+// YieldCurve 某个交易日的美国国债收益率曲线；Yields 按期限（1M、3M、2Y、10Y……）给出年化收益率百分比
+type YieldCurve struct {
+	Date   string             `json:"date"`
+	Yields map[string]float64 `json:"yields"`
+}
+
+// TenorYield 单个期限的收益率
+type TenorYield struct {
+	Tenor string  `json:"tenor"`
+	Yield float64 `json:"yield"` // 百分比
+}
+
+// RatesSummary 收益率曲线摘要：利差、近期变动与曲线形态；变动与利差单位均为基点
+type RatesSummary struct {
+	AsOf        string       `json:"as_of"`
+	Yields      []TenorYield `json:"yields"` // 按期限由短到长
+	Spread2s10s float64      `json:"spread_2s10s"`
+	Spread3m10y float64      `json:"spread_3m10y"`
+	// 2 年期、10 年期收益率相对约一周、一个月前的变动
+	Change2Y1W  float64 `json:"change_2y_1w"`
+	Change10Y1W float64 `json:"change_10y_1w"`
+	Change2Y1M  float64 `json:"change_2y_1m"`
+	Change10Y1M float64 `json:"change_10y_1m"`
+	Shape       string  `json:"shape"` // CurveNormal/CurveFlat/CurveInverted
+	// Move 近一个月曲线的变化，Curve* 常量之一；历史不足一个月时为空
+	Move  string   `json:"move,omitempty"`
+	Notes []string `json:"notes,omitempty"`
+}
+
+// YieldCurveInput get_yield_curve 工具入参
+type YieldCurveInput struct {
+	BeforeDate string `json:"before_date"`
+}
+
+// YieldCurveOutput get_yield_curve 工具出参
+type YieldCurveOutput struct {
+	Result string `json:"result"`
+}
@@ -2,7 +2,7 @@ package models
 
 import "time"
 
-// RedditPost represents a Reddit post (duplicated to avoid import cycle)
+// RedditPost represents a Reddit post; pkg/dataflows aliases this type
 type RedditPost struct {
 	ID         string    `json:"id"`
 	Title      string    `json:"title"`
@@ -38,7 +38,8 @@ type StockMentionsInput struct {
 }
 
 type FinanceNewsInput struct {
-	Limit int `json:"limit"`
+	Limit      int     `json:"limit"`
+	MinQuality float64 `json:"min_quality"`
 }
 
 type RedditOutput struct {
@@ -47,29 +48,45 @@ type RedditOutput struct {
 }
 
 // Google News models
+
+// NewsArticle represents a news article; pkg/dataflows aliases this type
 type NewsArticle struct {
-	Title       string    `json:"title"`
-	Content     string    `json:"content"`
-	URL         string    `json:"url"`
-	Source      string    `json:"source"`
-	PublishedAt time.Time `json:"published_at"`
-	Keywords    []string  `json:"keywords,omitempty"`
+	Title       string            `json:"title"`
+	Content     string            `json:"content"`
+	URL         string            `json:"url"`
+	Source      string            `json:"source"`
+	PublishedAt time.Time         `json:"published_at"`
+	Sentiment   float64           `json:"sentiment,omitempty"`
+	Keywords    []string          `json:"keywords,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	SourceTier  string            `json:"source_tier,omitempty"` // wire/major/aggregator/press_release/opinion/unknown
+	Quality     float64           `json:"quality,omitempty"`     // 来源可信度 0~1
 }
 
 type GoogleNewsSearchInput struct {
-	Query      string `json:"query"`
-	Language   string `json:"language"`
-	Country    string `json:"country"`
-	MaxResults int    `json:"max_results"`
-	SortBy     string `json:"sort_by"`
-	Category   string `json:"category"`
-	Site       string `json:"site"`
-	DaysBack   int    `json:"days_back"`
+	Query      string  `json:"query"`
+	Language   string  `json:"language"`
+	Country    string  `json:"country"`
+	MaxResults int     `json:"max_results"`
+	SortBy     string  `json:"sort_by"`
+	Category   string  `json:"category"`
+	Site       string  `json:"site"`
+	DaysBack   int     `json:"days_back"`
+	MinQuality float64 `json:"min_quality"`
 }
 
 type StockNewsInput struct {
-	Symbol     string `json:"symbol"`
-	MaxResults int    `json:"max_results"`
+	Symbol     string  `json:"symbol"`
+	MaxResults int     `json:"max_results"`
+	MinQuality float64 `json:"min_quality"`
+}
+
+// TopHeadlinesInput get_top_headlines 工具入参
+type TopHeadlinesInput struct {
+	Topic      string  `json:"topic"`
+	Edition    string  `json:"edition"`
+	MaxResults int     `json:"max_results"`
+	MinQuality float64 `json:"min_quality"`
 }
 
 type NewsOutput struct {
@@ -0,0 +1,46 @@
+package models
+
+// 市场环境标签
+const (
+	RegimeRiskOn  = "risk-on"
+	RegimeNeutral = "neutral"
+	RegimeRiskOff = "risk-off"
+)
+
+// MarketRegime 交易日的大盘环境：指数趋势、波动率、板块表现与市场宽度，
+// 汇总为 risk-on/neutral/risk-off 标签注入各分析师提示词
+type MarketRegime struct {
+	Market  string        `json:"market"` // US/HK/CN
+	AsOf    string        `json:"as_of"`  // 所用行情的最后交易日
+	Label   string        `json:"label"`
+	Score   int           `json:"score"` // 各项信号得分之和，>= 2 为 risk-on，<= -2 为 risk-off
+	Indices []IndexTrend  `json:"indices"`
+	VIX     float64       `json:"vix,omitempty"`     // 0 表示该市场无波动率指数或数据缺失
+	Sectors []SectorTrend `json:"sectors,omitempty"` // 按 20 日涨跌幅降序
+	// Breadth 站上 50 日均线的板块（无板块数据时为指数）占比，0–1
+	Breadth float64  `json:"breadth"`
+	Signals []string `json:"signals"` // 各项得分的说明
+	// Volatility VIX 期限结构与波动率环境，仅美股；VIX 分数已计入 Score
+	Volatility *VolatilityRegime `json:"volatility,omitempty"`
+	// Rates 美国国债收益率曲线摘要，不参与打分；取不到时为空
+	Rates *RatesSummary `json:"rates,omitempty"`
+}
+
+// IndexTrend 指数（或跟踪指数的 ETF）的趋势
+type IndexTrend struct {
+	Symbol      string  `json:"symbol"`
+	Name        string  `json:"name"`
+	Close       float64 `json:"close"`
+	Return20d   float64 `json:"return_20d"` // 百分比
+	AboveSMA50  bool    `json:"above_sma50"`
+	AboveSMA200 bool    `json:"above_sma200"`
+}
+
+// SectorTrend 板块 ETF 的表现
+type SectorTrend struct {
+	Symbol     string  `json:"symbol"`
+	Name       string  `json:"name"`
+	Return20d  float64 `json:"return_20d"` // 百分比
+	AboveSMA50 bool    `json:"above_sma50"`
+	Defensive  bool    `json:"defensive"` // 公用事业、必需消费、医疗等防御板块
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// RunInputs 一次运行中影响结果的不确定输入，用于比较两次运行（如调整提示词前后）是否条件一致
+type RunInputs struct {
+	Seed          int       `json:"seed,omitempty"`           // LLM 种子，0 表示未固定
+	Temperature   *float32  `json:"temperature,omitempty"`    // 固定种子时为 0，否则为模型默认值（不记录）
+	Models        []string  `json:"models"`                   // 使用的模型
+	Depth         string    `json:"depth"`                    // 分析深度预设
+	RiskProfile   string    `json:"risk_profile,omitempty"`   // 风险偏好
+	PinnedData    bool      `json:"pinned_data"`              // 缓存数据是否不过期（固定数据快照）
+	Offline       bool      `json:"offline,omitempty"`        // 是否离线运行
+	SimulatedData bool      `json:"simulated_data,omitempty"` // 行情、新闻与社交数据是否由模拟数据源生成
+	PromptsDigest string    `json:"prompts_digest"`           // 全部提示词模板的摘要，提示词改动后不同
+	DataDigest    string    `json:"data_digest,omitempty"`    // 全部工具输出的摘要，相同表示两次运行看到的数据一致
+	ToolCalls     int       `json:"tool_calls,omitempty"`     // 工具调用次数
+	StartedAt     time.Time `json:"started_at"`               // 运行开始时间（相对日期的新闻检索以此为准）
+
+	Build        *SystemVersion `json:"build,omitempty"`         // 运行所用的 CortexGo 版本与 git 提交
+	LLMProvider  string         `json:"llm_provider,omitempty"`  // 模型提供方
+	ConfigDigest string         `json:"config_digest,omitempty"` // 影响分析的配置项摘要（不含密钥、路径与投递设置）
+	// 各提示词模板的摘要（如 trader/trader），定位结论由哪一版提示词产生
+	PromptDigests map[string]string `json:"prompt_digests,omitempty"`
+	// 本次调用过的工具 → 工具定义（描述与参数）的摘要
+	ToolVersions map[string]string `json:"tool_versions,omitempty"`
+	// 各数据源 → 本次用到的数据的截至日期（取最新）
+	DataAsOf map[string]string `json:"data_as_of,omitempty"`
+}
@@ -0,0 +1,49 @@
+package models
+
+// 板块轮动阶段：以相对基准的中期强度与短期动量划分
+const (
+	RotationLeading   = "leading"   // 中期跑赢、短期仍在跑赢
+	RotationWeakening = "weakening" // 中期跑赢、短期转弱
+	RotationLagging   = "lagging"   // 中期跑输、短期仍在跑输
+	RotationImproving = "improving" // 中期跑输、短期转强
+)
+
+// 领涨板块的风格倾向
+const (
+	TiltCyclical  = "cyclical"
+	TiltDefensive = "defensive"
+	TiltMixed     = "mixed"
+)
+
+// SectorRotationParams market.sectors 入参
+type SectorRotationParams struct {
+	Market    string `json:"market,omitempty"`     // 目前仅支持 US，默认 US
+	TradeDate string `json:"trade_date,omitempty"` // YYYY-MM-DD，只使用当天及之前的行情
+}
+
+// SectorRotation 板块 ETF 多周期表现与轮动排名，作为个股分析前自上而下的起点
+type SectorRotation struct {
+	Market    string `json:"market"`
+	AsOf      string `json:"as_of"`     // 所用行情的最后交易日
+	Benchmark string `json:"benchmark"` // 计算相对强弱的基准 ETF
+	// Windows 各统计周期（交易日），Returns / Relative 与之一一对应
+	Windows          []int               `json:"windows"`
+	BenchmarkReturns []float64           `json:"benchmark_returns"` // 百分比
+	Sectors          []SectorPerformance `json:"sectors"`           // 按排名升序
+	Tilt             string              `json:"tilt"`              // 领涨板块偏周期、偏防御或混合
+	Missing          []string            `json:"missing,omitempty"` // 行情不足、未参与排名的 ETF
+}
+
+// SectorPerformance 单个板块 ETF 的多周期表现
+type SectorPerformance struct {
+	Rank       int       `json:"rank"`
+	Symbol     string    `json:"symbol"`
+	Name       string    `json:"name"`
+	Defensive  bool      `json:"defensive,omitempty"`
+	Close      float64   `json:"close"`
+	Returns    []float64 `json:"returns"`  // 各周期涨跌幅，百分比
+	Relative   []float64 `json:"relative"` // 各周期相对基准的超额收益，百分比
+	Score      float64   `json:"score"`    // 各周期超额收益排名的平均值，越小越强
+	Phase      string    `json:"phase"`    // leading / weakening / lagging / improving
+	AboveSMA50 bool      `json:"above_sma50"`
+}
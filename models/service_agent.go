@@ -2,7 +2,104 @@ package models
 
 // AgentInitParams 描述交易 agent 初始化参数
 type AgentInitParams struct {
-	Symbol    string `json:"symbol"`
+	Symbol    string `json:"symbol" rpc:"required"`
 	TradeDate string `json:"trade_date"`
 	Prompt    string `json:"prompt"`
+	// EmailTo 本次分析完成后报告的收件人，为空时使用配置中的 email_recipients
+	EmailTo []string `json:"email_to,omitempty"`
+	// WebhookURLs 本次分析完成后推送结果的地址，为空时使用配置中的 webhook_urls
+	WebhookURLs []string `json:"webhook_urls,omitempty"`
+	// Offline 仅使用本地缓存与归档数据运行，缺失数据时直接失败而不访问网络
+	Offline bool `json:"offline,omitempty"`
+	// Depth 本次分析深度 quick/standard/deep，覆盖配置中的 depth
+	Depth string `json:"depth,omitempty"`
+	// RiskProfile 本次风险偏好 conservative/balanced/aggressive，覆盖配置中的 risk_profile
+	RiskProfile string `json:"risk_profile,omitempty"`
+	// ExportToolData 将本次全部工具原始结果打包为 zip（JSON/CSV），覆盖配置中的 export_tool_data
+	ExportToolData bool `json:"export_tool_data,omitempty"`
+}
+
+// AgentPlanParams agent.plan 入参，与 agent.stream 一致但不执行
+type AgentPlanParams struct {
+	Symbol    string `json:"symbol" rpc:"required"`
+	TradeDate string `json:"trade_date"`
+	Offline   bool   `json:"offline,omitempty"`
+	// Depth 分析深度 quick/standard/deep，为空时使用配置
+	Depth string `json:"depth,omitempty"`
+}
+
+// AgentPlanStep 编排中的一个 agent 节点
+type AgentPlanStep struct {
+	Stage string   `json:"stage"`
+	Agent string   `json:"agent"`
+	Model string   `json:"model"`
+	Tools []string `json:"tools,omitempty"`
+	// Calls 预计模型调用次数（含 ReAct 工具轮次与辩论轮次）
+	Calls        int `json:"calls"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// AgentPlanSource 运行中会访问的数据源
+type AgentPlanSource struct {
+	Name string `json:"name"`
+	// Mode live 实时请求 / cache 仅缓存 / mock 模拟数据 / degraded 连续失败已熔断 / off 未配置 / local 本地数据
+	Mode   string   `json:"mode"`
+	Detail string   `json:"detail,omitempty"`
+	Tools  []string `json:"tools"`
+}
+
+// AgentPlanResponse dry-run 结果：执行计划与 token/费用估算
+type AgentPlanResponse struct {
+	Symbol           string            `json:"symbol"`
+	TradeDate        string            `json:"trade_date"`
+	Depth            string            `json:"depth"`
+	Offline          bool              `json:"offline"`
+	MaxToolSteps     int               `json:"max_tool_steps"`
+	Steps            []AgentPlanStep   `json:"steps"`
+	Sources          []AgentPlanSource `json:"sources"`
+	LLMCalls         int               `json:"llm_calls"`
+	InputTokens      int               `json:"input_tokens"`
+	OutputTokens     int               `json:"output_tokens"`
+	EstimatedCostUSD float64           `json:"estimated_cost_usd"`
+	Warnings         []string          `json:"warnings,omitempty"`
+}
+
+// AgentDeltaParams agent.delta 入参
+type AgentDeltaParams struct {
+	SessionId string `json:"session_id" rpc:"required"` // 必填，作为基准的已完成分析
+	// 可选，只使用该时间之后发布的新闻（RFC3339），默认基准报告的生成时间
+	Since string `json:"since,omitempty"`
+	// 可选，基准分析时的价格，用于在提示中给出涨跌幅；默认取基准计划中的入场价
+	BasePrice float64 `json:"base_price,omitempty"`
+}
+
+// AgentCancelParams agent.cancel 入参
+type AgentCancelParams struct {
+	SessionId string `json:"session_id" rpc:"required"` // 必填，agent.stream / CortexGoAnalyzeAsync 返回的 session_id
+}
+
+// AgentCancelResponse 取消结果；cancelled=false 表示该会话已结束或不存在
+type AgentCancelResponse struct {
+	SessionId string `json:"session_id"`
+	Cancelled bool   `json:"cancelled"`
+}
+
+// AgentRunInfo 运行中分析的进度快照，agent.runs / CortexGoAnalysisStatus 返回
+type AgentRunInfo struct {
+	SessionId string `json:"session_id"`
+	Symbol    string `json:"symbol"`
+	TradeDate string `json:"trade_date"`
+	Depth     string `json:"depth"`
+	Offline   bool   `json:"offline"`
+	// Agent / Phase 当前发言的 agent 与所处阶段（analysts/research/trading/risk）
+	Agent     string `json:"agent,omitempty"`
+	Phase     string `json:"phase,omitempty"`
+	Events    int    `json:"events"`
+	StartedAt string `json:"started_at"`
+}
+
+// AgentRunsResponse agent.runs 出参
+type AgentRunsResponse struct {
+	Runs []AgentRunInfo `json:"runs"`
 }
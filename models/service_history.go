@@ -7,6 +7,12 @@ type HistoryParams struct {
 	Symbol    string `json:"symbol"`     // 可选，按标的过滤
 	TradeDate string `json:"trade_date"` // 可选，按交易日期过滤（YYYY-MM-DD）
 	Query     string `json:"query"`      // 可选，单一搜索词，匹配 symbol 或 trade_date
+	// Recommendation 可选，按最终建议过滤（BUY/HOLD/SELL）
+	Recommendation string `json:"recommendation"`
+	// Since 可选，仅返回交易日期不早于该日期的会话（YYYY-MM-DD）
+	Since string `json:"since"`
+	// MinConfidence 可选，结构化决策置信度下限（0-1）
+	MinConfidence float64 `json:"min_confidence"`
 }
 
 // HistorySession 表示一次会话的概要信息
@@ -18,16 +24,19 @@ type HistorySession struct {
 	Status    string `json:"status"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
+
+	Recommendation string  `json:"recommendation,omitempty"`
+	Confidence     float64 `json:"confidence,omitempty"`
 }
 
 // HistoryInfoParams 描述按 session_id 读取历史详情的参数
 type HistoryInfoParams struct {
-	SessionID string `json:"session_id"` // 必填
+	SessionID string `json:"session_id" rpc:"required"` // 必填
 }
 
 // HistoryDeleteParams 描述按 session_id 删除历史会话的参数
 type HistoryDeleteParams struct {
-	SessionID string `json:"session_id"` // 必填
+	SessionID string `json:"session_id" rpc:"required"` // 必填
 }
 
 // HistoryMessage 表示某个会话中的单条消息
@@ -64,3 +73,18 @@ type HistoryDeleteResponse struct {
 	SessionID string `json:"session_id"`
 	Deleted   bool   `json:"deleted"`
 }
+
+// ReportExportParams 导出会话报告的参数
+type ReportExportParams struct {
+	SessionID string `json:"session_id" rpc:"required"` // 必填，会话 ID
+	Format    string `json:"format"`                    // 可选，json/html/md/pdf、pine/tv_csv/tv_alerts 或 ics，默认 pdf
+	Output    string `json:"output,omitempty"`          // 可选，输出文件路径，默认写入 results_dir
+}
+
+// ReportExportResponse 导出结果
+type ReportExportResponse struct {
+	SessionID string `json:"session_id"`
+	Format    string `json:"format"`
+	Path      string `json:"path"`
+	Size      int    `json:"size"`
+}
@@ -0,0 +1,89 @@
+package models
+
+// MarketChartParams 生成K线图的参数
+type MarketChartParams struct {
+	Symbol    string `json:"symbol" rpc:"required"` // 必填，交易标的
+	StartDate string `json:"start_date,omitempty"`  // 可选，YYYY-MM-DD，默认 end_date 前 120 天
+	EndDate   string `json:"end_date,omitempty"`    // 可选，YYYY-MM-DD，默认当天
+	Format    string `json:"format,omitempty"`      // 可选，svg/png，默认 svg
+	Width     int    `json:"width,omitempty"`       // 可选，默认 960
+	Height    int    `json:"height,omitempty"`      // 可选，默认 540
+	Output    string `json:"output,omitempty"`      // 可选，输出文件路径，默认写入 results_dir
+}
+
+// MarketChartResponse 图表生成结果
+type MarketChartResponse struct {
+	Symbol  string `json:"symbol"`
+	Format  string `json:"format"`
+	Path    string `json:"path"`
+	Candles int    `json:"candles"`
+}
+
+// MarketQuoteParams 查询实时行情的参数
+type MarketQuoteParams struct {
+	Symbols []string `json:"symbols" rpc:"required"` // 必填，交易标的列表，如 ["AAPL.US","700.HK"]
+}
+
+// MarketQuote 单个标的的实时行情与 52 周区间
+type MarketQuote struct {
+	Symbol     string  `json:"symbol"`
+	Last       float64 `json:"last"`
+	PrevClose  float64 `json:"prev_close"`
+	Change     float64 `json:"change"`
+	ChangePct  float64 `json:"change_pct"`
+	Open       float64 `json:"open"`
+	High       float64 `json:"high"`
+	Low        float64 `json:"low"`
+	Volume     int64   `json:"volume"`
+	Turnover   float64 `json:"turnover"`
+	Week52Low  float64 `json:"week52_low,omitempty"`
+	Week52High float64 `json:"week52_high,omitempty"`
+	Timestamp  string  `json:"timestamp"`
+
+	// 查询时所处的交易时段，以及盘前、盘后、夜盘的成交（美股）
+	Session  string          `json:"session,omitempty"`
+	Extended []ExtendedQuote `json:"extended,omitempty"`
+}
+
+// ExtendedQuote 盘前/盘后/夜盘时段的行情，涨跌相对 PrevClose
+type ExtendedQuote struct {
+	Session   string  `json:"session"` // pre-market/after-hours/overnight
+	Last      float64 `json:"last"`
+	PrevClose float64 `json:"prev_close"`
+	Change    float64 `json:"change"`
+	ChangePct float64 `json:"change_pct"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Volume    int64   `json:"volume"`
+	Turnover  float64 `json:"turnover"`
+	Timestamp string  `json:"timestamp,omitempty"`
+}
+
+// MarketQuoteResponse 行情查询结果
+type MarketQuoteResponse struct {
+	Quotes []MarketQuote `json:"quotes"`
+}
+
+// MarketIndicatorsParams 单独计算技术指标的参数
+type MarketIndicatorsParams struct {
+	Symbol     string   `json:"symbol" rpc:"required"` // 必填，交易标的
+	Lookback   int      `json:"lookback,omitempty"`    // 可选，输出最近多少根日K线，默认 60
+	EndDate    string   `json:"end_date,omitempty"`    // 可选，YYYY-MM-DD，默认最新
+	Indicators []string `json:"indicators,omitempty"`  // 可选，指标子集，默认全部
+	Output     string   `json:"output,omitempty"`      // 可选，导出路径，.csv 导出 CSV，.parquet 导出 Parquet，其余导出 JSON
+}
+
+// IndicatorRow 某一交易日的收盘价与指标值；预热期不足的指标不出现
+type IndicatorRow struct {
+	Date   string             `json:"date"`
+	Close  float64            `json:"close"`
+	Values map[string]float64 `json:"values"`
+}
+
+// MarketIndicatorsResponse 指标计算结果
+type MarketIndicatorsResponse struct {
+	Symbol  string         `json:"symbol"`
+	Columns []string       `json:"columns"`
+	Rows    []IndicatorRow `json:"rows"`
+	Path    string         `json:"path,omitempty"`
+}
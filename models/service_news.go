@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// NewsListParams 单独运行新闻数据源的参数，用于查看 agent 实际收到的原始输入
+type NewsListParams struct {
+	Symbol string `json:"symbol" rpc:"required"` // 必填，交易标的
+	Days   int    `json:"days,omitempty"`        // 可选，回看天数，默认 3
+	Source string `json:"source,omitempty"`      // 可选，google/rss/reddit，默认 google
+	Limit  int    `json:"limit,omitempty"`       // 可选，最多返回条数，默认 20
+	Output string `json:"output,omitempty"`      // 可选，导出路径，.csv 导出 CSV，其余导出 JSON
+	// 可选，过滤来源可信度低于该分值（0~1）的新闻，reddit 来源不适用
+	MinQuality float64 `json:"min_quality,omitempty"`
+	// 可选，仅 rss：只返回此前增量轮询未投递过的条目，供定时任务反复调用
+	Incremental bool `json:"incremental,omitempty"`
+}
+
+// NewsItem 单条新闻或帖子
+type NewsItem struct {
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Source      string    `json:"source"`
+	PublishedAt time.Time `json:"published_at"`
+	Sentiment   float64   `json:"sentiment"`       // 词典打分，-1 ~ 1
+	Score       int       `json:"score,omitempty"` // reddit 帖子得分
+	// 发布时所处的交易时段：pre-market/intraday/after-hours/overnight/closed
+	Session string `json:"session,omitempty"`
+	// 来源等级（wire/major/aggregator/press_release/opinion/unknown）与可信度 0~1，reddit 帖子为空
+	SourceTier string  `json:"source_tier,omitempty"`
+	Quality    float64 `json:"quality,omitempty"`
+}
+
+// NewsListResponse 新闻查询结果
+type NewsListResponse struct {
+	Symbol       string     `json:"symbol"`
+	Source       string     `json:"source"`
+	Items        []NewsItem `json:"items"`
+	AvgSentiment float64    `json:"avg_sentiment"`
+	Path         string     `json:"path,omitempty"`
+}
@@ -0,0 +1,162 @@
+package models
+
+import "encoding/json"
+
+// ResultsServeParams 启动本地结果看板的参数
+type ResultsServeParams struct {
+	Addr string `json:"addr,omitempty"` // 可选，监听地址，默认 127.0.0.1:8765
+}
+
+// ResultsServeResponse 看板状态
+type ResultsServeResponse struct {
+	Running bool   `json:"running"`
+	URL     string `json:"url,omitempty"`
+}
+
+// ResultsStats 历史结果统计
+type ResultsStats struct {
+	TotalRuns        int            `json:"total_runs"`
+	Decisions        int            `json:"decisions"`
+	ByRecommendation map[string]int `json:"by_recommendation"`
+	AvgConfidence    float64        `json:"avg_confidence"`
+	Horizons         []HorizonStats `json:"horizons"`
+	// Journal 交易日志中实际执行的交易与已实现盈亏，没有记录时为空
+	Journal *JournalStats `json:"journal,omitempty"`
+}
+
+// HorizonStats 某个持有期的命中情况
+type HorizonStats struct {
+	HorizonDays  int     `json:"horizon_days"`
+	Evaluated    int     `json:"evaluated"`
+	Correct      int     `json:"correct"`
+	HitRate      float64 `json:"hit_rate"`
+	AvgReturnPct float64 `json:"avg_return_pct"`
+}
+
+// ResultsCalibrationParams 置信度校准统计的参数
+type ResultsCalibrationParams struct {
+	HorizonDays int    `json:"horizon_days,omitempty"` // 可选，按哪个持有期的表现判断命中，默认 5
+	Agent       string `json:"agent,omitempty"`        // 可选，只看某个 agent
+}
+
+// ResultsCalibrationResponse 各 agent 的置信度校准情况
+type ResultsCalibrationResponse struct {
+	HorizonDays int                `json:"horizon_days"`
+	Agents      []AgentCalibration `json:"agents"`
+}
+
+// AgentCalibration 某个 agent 声明置信度与实际命中率的对比
+type AgentCalibration struct {
+	Agent          string           `json:"agent"`
+	Samples        int              `json:"samples"`
+	MeanConfidence float64          `json:"mean_confidence"`
+	HitRate        float64          `json:"hit_rate"`
+	Gap            float64          `json:"gap"`    // 平均置信度 - 命中率，正数表示过度自信
+	Brier          float64          `json:"brier"`  // 声明置信度的 Brier 分数，越低越好
+	Method         string           `json:"method"` // 采用的校准方法：isotonic / platt / none（样本不足）
+	Overconfident  bool             `json:"overconfident"`
+	Bins           []CalibrationBin `json:"bins"`
+}
+
+// CalibrationBin 可靠性曲线的一个置信度区间
+type CalibrationBin struct {
+	Lower          float64 `json:"lower"`
+	Upper          float64 `json:"upper"`
+	Count          int     `json:"count"`
+	MeanConfidence float64 `json:"mean_confidence"`
+	HitRate        float64 `json:"hit_rate"`
+}
+
+// CalibrationSample 一条（声明置信度 → 实际表现）样本
+type CalibrationSample struct {
+	SessionId  int64
+	Agent      string
+	Action     string
+	Confidence float64
+	ReturnPct  float64
+}
+
+// ResultsEvaluateParams 评估历史决策表现的参数
+type ResultsEvaluateParams struct {
+	HorizonDays int `json:"horizon_days,omitempty"` // 可选，持有交易日数，默认 5
+	Limit       int `json:"limit,omitempty"`        // 可选，本次最多评估的会话数，默认 50
+}
+
+// ResultsEvaluateResponse 评估结果
+type ResultsEvaluateResponse struct {
+	HorizonDays int `json:"horizon_days"`
+	Evaluated   int `json:"evaluated"`
+	Skipped     int `json:"skipped"`
+}
+
+// ResultsExportParams 导出全部历史结果的参数
+type ResultsExportParams struct {
+	Output string `json:"output,omitempty"` // 可选，输出 JSON 文件路径，默认写入 results_dir
+}
+
+// ResultsExportResponse 导出结果
+type ResultsExportResponse struct {
+	Path string `json:"path"`
+	Runs int    `json:"runs"`
+}
+
+// ResultsExportRun 导出文件中的单次分析
+type ResultsExportRun struct {
+	SessionID      string           `json:"session_id"`
+	Symbol         string           `json:"symbol"`
+	TradeDate      string           `json:"trade_date"`
+	Status         string           `json:"status"`
+	Recommendation string           `json:"recommendation"`
+	CreatedAt      string           `json:"created_at"`
+	Decision       *TradingDecision `json:"decision,omitempty"`
+	Outcomes       []OutcomeRecord  `json:"outcomes,omitempty"`
+}
+
+// ResultsCompareParams 对比同一标的两次分析的参数；可传两个交易日，或两个 session_id
+type ResultsCompareParams struct {
+	Symbol   string `json:"symbol,omitempty"`
+	DateA    string `json:"date_a,omitempty"`
+	DateB    string `json:"date_b,omitempty"`
+	SessionA string `json:"session_a,omitempty"`
+	SessionB string `json:"session_b,omitempty"`
+}
+
+// ResultsArchiveParams 打包导出历史结果的参数
+type ResultsArchiveParams struct {
+	Format string `json:"format,omitempty"` // 可选，csv/parquet，默认 csv
+	Output string `json:"output,omitempty"` // 可选，zip 输出路径，默认写入 results_dir
+}
+
+// ResultsArchiveResponse 打包结果
+type ResultsArchiveResponse struct {
+	Path   string   `json:"path"`
+	Format string   `json:"format"`
+	Files  []string `json:"files"`
+	Runs   int      `json:"runs"`
+}
+
+// ResultsSyncParams 与对象存储同步结果的参数
+type ResultsSyncParams struct {
+	Direction string `json:"direction,omitempty"` // 可选，push/pull/both，默认 both
+}
+
+// ResultsSyncResponse 同步结果
+type ResultsSyncResponse struct {
+	Bucket string   `json:"bucket"`
+	Pushed int      `json:"pushed"`
+	Pulled int      `json:"pulled"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ResultInfoParams results.info 入参
+type ResultInfoParams struct {
+	SessionID string `json:"session_id" rpc:"required"` // 必填
+}
+
+// ResultInfoResponse 单次分析的完整结果：决策、表现与最终报告
+type ResultInfoResponse struct {
+	ResultsExportRun
+	// Report 最终报告（report.Report 的 JSON），分析未完成时为空
+	Report   json.RawMessage `json:"report,omitempty"`
+	Markdown string          `json:"markdown,omitempty"`
+}
@@ -0,0 +1,73 @@
+package models
+
+// DoctorParams 环境诊断参数
+type DoctorParams struct {
+	Checks     []string `json:"checks,omitempty"`      // 可选，只运行指定检查项，默认全部
+	TimeoutSec int      `json:"timeout_sec,omitempty"` // 可选，单项检查超时秒数，默认 10
+}
+
+// 诊断结果状态
+const (
+	DoctorOK   = "ok"
+	DoctorWarn = "warn"
+	DoctorFail = "fail"
+	DoctorSkip = "skip"
+)
+
+// DoctorCheck 单项检查结果
+type DoctorCheck struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Fix       string `json:"fix,omitempty"` // 失败或警告时的修复建议
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// DoctorResponse 诊断结果；OK 表示没有 fail 项
+type DoctorResponse struct {
+	OK     bool          `json:"ok"`
+	Checks []DoctorCheck `json:"checks"`
+}
+
+// SystemVersion 库的构建版本；commit/build_time 来自构建时的 VCS 信息，未记录时为空
+type SystemVersion struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"` // 构建时工作区有未提交的修改
+	GoVersion string `json:"go_version"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+}
+
+// CapabilityFeature 可选功能及其是否已配置
+type CapabilityFeature struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// SystemCapabilities 供宿主按功能探测：数据源与工具、可选功能、RPC 方法与回调事件
+type SystemCapabilities struct {
+	Version  string              `json:"version"`
+	LLM      CapabilityFeature   `json:"llm"`
+	Sources  []AgentPlanSource   `json:"sources"` // 数据源、当前模式（live/cache/mock）与工具
+	Tools    []string            `json:"tools"`
+	Features []CapabilityFeature `json:"features"`
+	Depths   []string            `json:"depths"`
+	Methods  []string            `json:"methods"` // Call 可用的方法
+	Events   []string            `json:"events"`  // 可能推送的回调 topic
+	// RiskProfiles 支持的风险偏好，从保守到激进
+	RiskProfiles []string `json:"risk_profiles"`
+}
+
+// EventSubscribeParams events.subscribe / events.unsubscribe 参数
+type EventSubscribeParams struct {
+	// Topics 完整 topic、前缀通配（analysis.*）、* 或分类 progress/reasoning/reports/errors
+	Topics []string `json:"topics" rpc:"required"`
+}
+
+// EventVerbosityParams events.verbosity 参数
+type EventVerbosityParams struct {
+	Level string `json:"level" rpc:"required"` // error/info/debug
+}
@@ -27,3 +27,73 @@ type MessageRecord struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
+
+type ReportRecord struct {
+	SessionId      int64
+	Symbol         string
+	TradeDate      string
+	Recommendation string
+	Content        string
+	CreatedAt      time.Time
+}
+
+// RunFilter 历史分析查询条件
+type RunFilter struct {
+	Symbol         string
+	Recommendation string
+	Status         string
+	Since          string  // trade_date >= Since（YYYY-MM-DD）
+	MinConfidence  float64 // 决策置信度下限，0 表示不过滤
+	Limit          int
+}
+
+// SessionFilter 历史会话分页查询条件
+type SessionFilter struct {
+	Cursor         int64
+	Limit          int
+	Symbol         string
+	TradeDate      string
+	Query          string
+	Recommendation string
+	Since          string
+	MinConfidence  float64
+}
+
+// RunRecord 会话及其最终建议
+type RunRecord struct {
+	SessionRecord
+	Recommendation string
+	Confidence     float64
+}
+
+// OutcomeRecord 决策在持有 HorizonDays 个交易日后的实际表现
+type OutcomeRecord struct {
+	SessionId   int64     `json:"session_id"`
+	HorizonDays int       `json:"horizon_days"`
+	EvalDate    string    `json:"eval_date"`
+	EntryClose  float64   `json:"entry_close"`
+	ExitClose   float64   `json:"exit_close"`
+	ReturnPct   float64   `json:"return_pct"`
+	Correct     bool      `json:"correct"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ReportChunk 报告切块及其向量，用于历史分析检索
+type ReportChunk struct {
+	SessionId      int64
+	Seq            int
+	Symbol         string
+	TradeDate      string
+	Recommendation string // 查询时从 reports 表带出
+	Section        string
+	Content        string
+	Embedder       string
+	Vector         []float32
+}
+
+// ChunkFilter 历史切块查询条件
+type ChunkFilter struct {
+	Symbol   string // 模糊匹配，同 RunFilter
+	Before   string // 只返回交易日早于该日期（YYYY-MM-DD）的切块
+	Embedder string // 只返回该向量模型生成的切块
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// SourceOutage 分析中一次因数据源不可用而失败的工具调用，决策方据此降低该分析师报告的权重
+type SourceOutage struct {
+	Agent  string    `json:"agent"`  // 调用工具的 agent
+	Tool   string    `json:"tool"`   // 工具名
+	Source string    `json:"source"` // 数据源，如 google_news
+	Error  string    `json:"error"`
+	At     time.Time `json:"at"`
+}
+
+// DegradedToolOutput 数据源降级时工具代替原结果返回给 agent 的内容
+type DegradedToolOutput struct {
+	Status string `json:"status"` // 固定为 degraded
+	Source string `json:"source"`
+	Error  string `json:"error"`
+	Result string `json:"result"` // 给 agent 的说明
+}
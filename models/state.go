@@ -63,6 +63,30 @@ type TradingState struct {
 
 	// Historical decisions for learning
 	PreviousDecisions []TradingDecision `json:"previous_decisions"`
+
+	// 各 agent 工具调用的输出，最终报告据此生成证据链
+	Evidence []*Evidence `json:"evidence"`
+
+	// 工具调用的完整参数与输出，仅在开启 export_tool_data 时记录，用于导出运行数据包
+	ToolResults []*ToolResult `json:"tool_results,omitempty"`
+
+	// 交易日的大盘环境，注入各分析师提示词；跳过或数据缺失时为 nil
+	MarketRegime *MarketRegime `json:"market_regime,omitempty"`
+
+	// 数据源不可用导致的工具调用失败，研究经理与风险裁判据此调低相应分析师的权重
+	SourceOutages []*SourceOutage `json:"source_outages,omitempty"`
+
+	// 工具或 agent 模型调用中被恢复的 panic，对应的输出缺失，决策方与报告据此说明
+	NodeFailures []*NodeFailure `json:"node_failures,omitempty"`
+
+	// 分析过程中发现的后续催化剂（财报日、解禁、宏观事件），随报告导出为 .ics 日历
+	Catalysts []Catalyst `json:"catalysts,omitempty"`
+
+	// 本次运行的不确定输入（种子、模型、提示词版本等），由编排器在创建状态时记录
+	RunInputs *RunInputs `json:"run_inputs,omitempty"`
+
+	// 本次运行的 ID，由编排器在创建状态时生成；运行产物写入 results_dir/<标的>/<交易日>/<run_id>/
+	RunID string `json:"run_id,omitempty"`
 }
 
 func NewTradingState(symbol string, date time.Time, userPrompt string, cfg *config.Config) *TradingState {
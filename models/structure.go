@@ -0,0 +1,88 @@
+package models
+
+// Wyckoff 阶段
+const (
+	PhaseAccumulation = "accumulation" // 下跌后的横盘吸筹
+	PhaseMarkup       = "markup"       // 上涨
+	PhaseDistribution = "distribution" // 上涨后的横盘派发
+	PhaseMarkdown     = "markdown"     // 下跌
+	PhaseRange        = "range"        // 横盘，方向未明
+	PhaseTransition   = "transition"   // 结构不清晰
+)
+
+// 摆动点趋势
+const (
+	TrendUp       = "uptrend"   // 高点抬高、低点抬高
+	TrendDown     = "downtrend" // 高点降低、低点降低
+	TrendSideways = "sideways"
+)
+
+// 结构事件类型
+const (
+	EventBreakout      = "breakout"       // 收盘站上区间上沿
+	EventBreakdown     = "breakdown"      // 收盘跌破区间下沿
+	EventUpthrust      = "upthrust"       // 冲高越过上沿后收回区间
+	EventSpring        = "spring"         // 跌破下沿后收回区间
+	EventBuyingClimax  = "buying_climax"  // 放量长阳
+	EventSellingClimax = "selling_climax" // 放量长阴
+)
+
+// MarketStructure 近期价格结构：摆动高低点、交易区间、突破与放量高潮，以及据此给出的 Wyckoff 阶段判断
+type MarketStructure struct {
+	Symbol     string           `json:"symbol"`
+	AsOf       string           `json:"as_of"`
+	Bars       int              `json:"bars"` // 参与分析的日K线根数
+	Close      float64          `json:"close"`
+	Phase      string           `json:"phase"`
+	Confidence string           `json:"confidence"` // low/medium/high
+	Trend      string           `json:"trend"`
+	Swings     []SwingPoint     `json:"swings,omitempty"` // 最近的摆动点，按时间先后
+	Range      *PriceRange      `json:"range,omitempty"`
+	Breakout   *StructureEvent  `json:"breakout,omitempty"` // 最近几根K线内对区间的突破或假突破
+	Climaxes   []StructureEvent `json:"climaxes,omitempty"`
+	Evidence   []string         `json:"evidence,omitempty"` // 支撑阶段判断的依据
+}
+
+// SwingPoint 摆动高点或低点；Label 与前一个同类摆动点比较：HH/LH 或 HL/LL，首个为空
+type SwingPoint struct {
+	Date  string  `json:"date"`
+	Kind  string  `json:"kind"` // high/low
+	Price float64 `json:"price"`
+	Label string  `json:"label,omitempty"`
+}
+
+// PriceRange 交易区间；Position 为最新收盘在区间中的位置，0 为下沿、1 为上沿，突破时超出 0–1
+type PriceRange struct {
+	High     float64 `json:"high"`
+	Low      float64 `json:"low"`
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+	Bars     int     `json:"bars"`
+	WidthPct float64 `json:"width_pct"`
+	Position float64 `json:"position"`
+}
+
+// StructureEvent 突破、假突破或放量高潮
+type StructureEvent struct {
+	Kind  string  `json:"kind"`
+	Date  string  `json:"date"`
+	Close float64 `json:"close"`
+	Level float64 `json:"level,omitempty"` // 被突破的区间边界
+	// VolumeRatio 突破当日成交量相对区间日均成交量的倍数
+	VolumeRatio float64 `json:"volume_ratio,omitempty"`
+	// VolumeZ 高潮当日成交量相对前 20 日的标准分
+	VolumeZ   float64 `json:"volume_z,omitempty"`
+	Confirmed bool    `json:"confirmed,omitempty"` // 突破是否有放量确认，仅用于 breakout/breakdown
+}
+
+// MarketStructureInput get_market_structure 工具入参
+type MarketStructureInput struct {
+	Symbol     string `json:"symbol"`
+	Lookback   int    `json:"lookback"`
+	BeforeDate string `json:"before_date"`
+}
+
+// MarketStructureOutput get_market_structure 工具出参
+type MarketStructureOutput struct {
+	Result string `json:"result"`
+}
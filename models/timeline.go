@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// TimelineEvent 新闻时间线上的一个事件：同一天同类型、标题相近的多篇报道合并为一条
+type TimelineEvent struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"` // earnings/guidance/analyst_rating/product/deal/legal/management/capital_return/other
+	Headline  string    `json:"headline"`
+	Source    string    `json:"source"`
+	URL       string    `json:"url"`
+	Session   string    `json:"session,omitempty"` // 发布时所处的交易时段
+	Sentiment float64   `json:"sentiment"`
+	Quality   float64   `json:"quality,omitempty"` // 代表报道的来源可信度
+	// 合并进来的其他报道来源（不含代表报道）
+	Coverage []string `json:"coverage,omitempty"`
+}
+
+// NewsTimelineInput get_news_timeline 工具入参
+type NewsTimelineInput struct {
+	Symbol     string `json:"symbol"`
+	DaysBack   int    `json:"days_back"`
+	BeforeDate string `json:"before_date"`
+}
+
+// NewsTimelineOutput get_news_timeline 工具出参
+type NewsTimelineOutput struct {
+	Events []TimelineEvent `json:"events"`
+	Result string          `json:"result"`
+}
@@ -0,0 +1,53 @@
+package models
+
+// 财报电话会发言所属环节
+const (
+	TranscriptSessionPrepared = "prepared_remarks"
+	TranscriptSessionQA       = "qa"
+)
+
+// TranscriptSegment 电话会中一位发言人的一段发言
+type TranscriptSegment struct {
+	Speaker     string `json:"speaker"`
+	Role        string `json:"role,omitempty"` // executive、analyst 或 operator
+	Affiliation string `json:"affiliation,omitempty"`
+	Session     string `json:"session"`
+	Text        string `json:"text"`
+}
+
+// EarningsCallTranscript 一场财报电话会的完整记录
+type EarningsCallTranscript struct {
+	Symbol   string              `json:"symbol"`
+	Title    string              `json:"title,omitempty"`
+	Year     int                 `json:"year"`
+	Quarter  int                 `json:"quarter"`
+	Date     string              `json:"date"`
+	Source   string              `json:"source"`
+	Segments []TranscriptSegment `json:"segments"`
+}
+
+// TranscriptExchange 问答环节中的一轮提问与管理层回答
+type TranscriptExchange struct {
+	Analyst  string              `json:"analyst"`
+	Firm     string              `json:"firm,omitempty"`
+	Question string              `json:"question"`
+	Answers  []TranscriptSegment `json:"answers"`
+	Topics   []string            `json:"topics,omitempty"`
+}
+
+// EarningsCallInput get_earnings_call_transcript 工具入参
+type EarningsCallInput struct {
+	Symbol     string `json:"symbol"`
+	BeforeDate string `json:"before_date"`
+}
+
+// EarningsCallOutput get_earnings_call_transcript 工具出参
+type EarningsCallOutput struct {
+	Symbol    string               `json:"symbol"`
+	Year      int                  `json:"year,omitempty"`
+	Quarter   int                  `json:"quarter,omitempty"`
+	Date      string               `json:"date,omitempty"`
+	Source    string               `json:"source,omitempty"`
+	Exchanges []TranscriptExchange `json:"exchanges,omitempty"`
+	Result    string               `json:"result"`
+}
@@ -0,0 +1,50 @@
+package models
+
+// 波动率环境（按 VIX 水平，期限结构倒挂时上调一级）
+const (
+	VolCalm     = "calm"
+	VolNormal   = "normal"
+	VolElevated = "elevated"
+	VolStressed = "stressed"
+)
+
+// VIX 期限结构：近月低于远月为 contango（常态），高于远月为 backwardation（恐慌）
+const (
+	TermContango      = "contango"
+	TermFlat          = "flat"
+	TermBackwardation = "backwardation"
+)
+
+// VolTermPoint VIX 期限结构上的一个点
+type VolTermPoint struct {
+	Tenor  string  `json:"tenor"` // 9D/30D/3M/6M
+	Symbol string  `json:"symbol"`
+	Level  float64 `json:"level"`
+}
+
+// VolatilityRegime 交易日的美股波动率环境：VIX 水平与一年分位、期限结构与建议的仓位系数
+type VolatilityRegime struct {
+	AsOf     string  `json:"as_of"`
+	VIX      float64 `json:"vix"`
+	Change5d float64 `json:"change_5d"` // 5 日涨跌幅，百分比
+	// Percentile1y 当前 VIX 在最近一年收盘中的分位，0–100
+	Percentile1y float64        `json:"percentile_1y"`
+	Term         []VolTermPoint `json:"term,omitempty"` // 按期限由短到长，缺数据的期限不列出
+	// TermRatio VIX 与 VIX3M 之比，大于 1 为倒挂；缺 VIX3M 时为 0
+	TermRatio     float64 `json:"term_ratio,omitempty"`
+	TermStructure string  `json:"term_structure,omitempty"`
+	Regime        string  `json:"regime"`
+	// SizeMultiplier 建议的仓位系数，风险经理据此缩放仓位上限
+	SizeMultiplier float64  `json:"size_multiplier"`
+	Notes          []string `json:"notes,omitempty"`
+}
+
+// VolatilityInput get_volatility_regime 工具入参
+type VolatilityInput struct {
+	BeforeDate string `json:"before_date"`
+}
+
+// VolatilityOutput get_volatility_regime 工具出参
+type VolatilityOutput struct {
+	Result string `json:"result"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// WatchEvent watch 模式一轮轮询的结果：行情、新出现的新闻，以及是否因重大变化重新分析
+type WatchEvent struct {
+	Symbol    string    `json:"symbol"`
+	At        time.Time `json:"at"`
+	Price     float64   `json:"price"`
+	BasePrice float64   `json:"base_price,omitempty"` // 上次分析时的价格
+	MovePct   float64   `json:"move_pct"`             // 相对上次分析时价格的涨跌幅（%）
+	// 本轮新出现的新闻（此前轮询返回过的不再出现）
+	NewItems []NewsItem `json:"new_items,omitempty"`
+	// 触发重新分析的原因；为空表示没有重大变化
+	Reasons []string `json:"reasons,omitempty"`
+	// 本轮运行的分析：initial（首次完整分析）或 refresh（重大变化后的轻量分析）；未分析时为空
+	Analysis       string `json:"analysis,omitempty"`
+	Recommendation string `json:"recommendation,omitempty"`
+	Error          string `json:"error,omitempty"` // 分析失败的原因
+	NewsError      string `json:"news_error,omitempty"`
+}
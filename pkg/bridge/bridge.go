@@ -4,14 +4,32 @@ type NotifyFunc func(topic string, payload string)
 
 var impl NotifyFunc
 
+// defaultFilter 过滤经 Notify 推送给全局回调的事件
+var defaultFilter = NewFilter()
+
 // SetNotifyImpl 由 main.go 调用，注入 CGO 的实现
 func SetNotifyImpl(f NotifyFunc) {
 	impl = f
 }
 
-// Notify 供 service 层调用，发送事件给 App
+// Notify 供 service 层调用，发送事件给 App；未通过订阅过滤的事件直接丢弃
 func Notify(topic string, payload string) {
-	if impl != nil {
+	if impl != nil && defaultFilter.Allow(topic) {
 		impl(topic, payload)
 	}
 }
+
+// Subscribe 全局回调只接收匹配的事件，见 Filter
+func Subscribe(patterns ...string) error { return defaultFilter.Subscribe(patterns...) }
+
+// Unsubscribe 全局回调不再接收匹配的事件
+func Unsubscribe(patterns ...string) error { return defaultFilter.Unsubscribe(patterns...) }
+
+// SetVerbosity 全局回调丢弃比 l 更详细的事件
+func SetVerbosity(l Level) { defaultFilter.SetLevel(l) }
+
+// ResetSubscriptions 恢复接收全部事件
+func ResetSubscriptions() { defaultFilter.Reset() }
+
+// Subscriptions 返回全局回调当前的订阅状态
+func Subscriptions() FilterState { return defaultFilter.State() }
@@ -0,0 +1,127 @@
+package bridge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Filter 按订阅与详细程度过滤事件。订阅模式可以是完整 topic（agent.decision）、
+// 前缀通配（analysis.*）、分类（progress/reasoning/reports/errors）或 *。
+// 未订阅任何模式时放行全部事件；退订的模式始终被过滤，优先于订阅
+type Filter struct {
+	mu      sync.RWMutex
+	include map[string]bool
+	exclude map[string]bool
+	level   Level
+}
+
+// FilterState 当前订阅状态
+type FilterState struct {
+	Subscribed   []string `json:"subscribed"`
+	Unsubscribed []string `json:"unsubscribed"`
+	Verbosity    string   `json:"verbosity"`
+}
+
+func NewFilter() *Filter {
+	return &Filter{include: map[string]bool{}, exclude: map[string]bool{}, level: LevelDebug}
+}
+
+// Subscribe 只接收匹配的事件（可多次调用累加），同时取消对这些模式的退订
+func (f *Filter) Subscribe(patterns ...string) error {
+	if err := validatePatterns(patterns); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range patterns {
+		f.include[p] = true
+		delete(f.exclude, p)
+	}
+	return nil
+}
+
+// Unsubscribe 不再接收匹配的事件
+func (f *Filter) Unsubscribe(patterns ...string) error {
+	if err := validatePatterns(patterns); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range patterns {
+		f.exclude[p] = true
+		delete(f.include, p)
+	}
+	return nil
+}
+
+// SetLevel 丢弃比 l 更详细的事件
+func (f *Filter) SetLevel(l Level) {
+	f.mu.Lock()
+	f.level = l
+	f.mu.Unlock()
+}
+
+// Reset 恢复默认：接收全部事件
+func (f *Filter) Reset() {
+	f.mu.Lock()
+	f.include = map[string]bool{}
+	f.exclude = map[string]bool{}
+	f.level = LevelDebug
+	f.mu.Unlock()
+}
+
+// Allow 报告 topic 是否应推送
+func (f *Filter) Allow(topic string) bool {
+	info := lookupTopic(topic)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if info.level > f.level {
+		return false
+	}
+	if matchAny(f.exclude, topic, info.category) {
+		return false
+	}
+	return len(f.include) == 0 || matchAny(f.include, topic, info.category)
+}
+
+func (f *Filter) State() FilterState {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return FilterState{Subscribed: sortedKeys(f.include), Unsubscribed: sortedKeys(f.exclude), Verbosity: f.level.String()}
+}
+
+func matchAny(patterns map[string]bool, topic, category string) bool {
+	if patterns["*"] || patterns[topic] || patterns[category] {
+		return true
+	}
+	for p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasPrefix(topic, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePatterns 拒绝既不是分类也不像 topic 的模式，避免拼写错误导致静默收不到事件
+func validatePatterns(patterns []string) error {
+	for _, p := range patterns {
+		switch {
+		case p == "*", strings.Contains(p, "."):
+		case p == CategoryProgress, p == CategoryReasoning, p == CategoryReports, p == CategoryErrors:
+		default:
+			return fmt.Errorf("unknown topic pattern %q (want a topic such as agent.decision, a prefix such as analysis.*, *, or one of progress, reasoning, reports, errors)", p)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,52 @@
+package bridge
+
+import "testing"
+
+func TestFilter(t *testing.T) {
+	f := NewFilter()
+	if !f.Allow("agent.message_chunk") || !f.Allow("custom.topic") {
+		t.Fatal("default filter should allow everything")
+	}
+
+	if err := f.Subscribe("progress", "errors"); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]bool{
+		"analysis.phase_complete": true,
+		"analysis.error":          true,
+		"agent.message_chunk":     false,
+		"analysis.decision":       false,
+	}
+	for topic, want := range cases {
+		if got := f.Allow(topic); got != want {
+			t.Errorf("subscribed progress,errors: Allow(%s) = %v", topic, got)
+		}
+	}
+
+	f.Reset()
+	if err := f.Unsubscribe("agent.*"); err != nil {
+		t.Fatal(err)
+	}
+	if f.Allow("agent.decision") || !f.Allow("analysis.decision") {
+		t.Error("unsubscribe agent.* should drop only agent topics")
+	}
+
+	f.Reset()
+	f.SetLevel(LevelInfo)
+	if f.Allow("analysis.report_chunk") || !f.Allow("analysis.decision") || !f.Allow("agent.error") {
+		t.Error("info verbosity should drop only debug topics")
+	}
+	f.SetLevel(LevelError)
+	if f.Allow("analysis.finished") || !f.Allow("engine.reload_failed") {
+		t.Error("error verbosity should keep only errors")
+	}
+
+	if err := f.Subscribe("progres"); err == nil {
+		t.Error("misspelled category accepted")
+	}
+	for _, topic := range Topics() {
+		if topic.Category == "" || topic.Level == "" {
+			t.Errorf("topic %s is not classified", topic.Name)
+		}
+	}
+}
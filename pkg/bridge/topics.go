@@ -0,0 +1,107 @@
+package bridge
+
+// 事件分类，可作为订阅模式使用
+const (
+	CategoryProgress  = "progress"  // 生命周期与阶段进度
+	CategoryReasoning = "reasoning" // 逐 token 推理、工具调用与结果
+	CategoryReports   = "reports"   // 报告正文与最终决策
+	CategoryErrors    = "errors"    // 错误
+)
+
+// Level 事件详细程度，数值越大越详细
+type Level int
+
+const (
+	LevelError Level = iota + 1 // 只有错误
+	LevelInfo                   // 进度、报告与决策
+	LevelDebug                  // 逐 token 输出与工具调用（默认，即全部事件）
+)
+
+var levelNames = map[Level]string{LevelError: "error", LevelInfo: "info", LevelDebug: "debug"}
+
+func (l Level) String() string { return levelNames[l] }
+
+// ParseLevel 解析 error/info/debug
+func ParseLevel(s string) (Level, bool) {
+	for l, name := range levelNames {
+		if name == s {
+			return l, true
+		}
+	}
+	return 0, false
+}
+
+// Topic 一个回调 topic 的分类与详细程度
+type Topic struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Level    string `json:"level"`
+}
+
+type topicInfo struct {
+	category string
+	level    Level
+}
+
+// topicOrder 全部 topic，按推送的大致顺序排列；新增事件时同步更新
+var topicOrder = []string{
+	"agent.message_chunk",
+	"agent.messgae_chunk_stop",
+	"agent.tool_call_stop",
+	"agent.text_final",
+	"agent.tool_call_result_final",
+	"agent.error",
+	"agent.decision",
+	"agent.finished",
+	"agent.cancelled",
+	"analysis.agent_started",
+	"analysis.report_chunk",
+	"analysis.phase_complete",
+	"analysis.decision",
+	"analysis.finished",
+	"analysis.error",
+	"analysis.cancelled",
+	"engine.reloaded",
+	"engine.reload_failed",
+	"alert.triggered",
+}
+
+var topicTable = map[string]topicInfo{
+	"agent.message_chunk":          {CategoryReasoning, LevelDebug},
+	"agent.messgae_chunk_stop":     {CategoryReasoning, LevelDebug},
+	"agent.tool_call_stop":         {CategoryReasoning, LevelDebug},
+	"agent.tool_call_result_final": {CategoryReasoning, LevelDebug},
+	"agent.text_final":             {CategoryReports, LevelInfo},
+	"agent.error":                  {CategoryErrors, LevelError},
+	"agent.decision":               {CategoryReports, LevelInfo},
+	"agent.finished":               {CategoryProgress, LevelInfo},
+	"agent.cancelled":              {CategoryProgress, LevelInfo},
+	"analysis.agent_started":       {CategoryProgress, LevelInfo},
+	"analysis.report_chunk":        {CategoryReports, LevelDebug},
+	"analysis.phase_complete":      {CategoryProgress, LevelInfo},
+	"analysis.decision":            {CategoryReports, LevelInfo},
+	"analysis.finished":            {CategoryProgress, LevelInfo},
+	"analysis.error":               {CategoryErrors, LevelError},
+	"analysis.cancelled":           {CategoryProgress, LevelInfo},
+	"engine.reloaded":              {CategoryProgress, LevelInfo},
+	"engine.reload_failed":         {CategoryErrors, LevelError},
+	"alert.triggered":              {CategoryProgress, LevelInfo},
+}
+
+// lookupTopic 未登记的 topic 归为 progress/info，不会因过滤被意外丢弃错误之外的事件
+func lookupTopic(topic string) topicInfo {
+	if info, ok := topicTable[topic]; ok {
+		return info
+	}
+	return topicInfo{CategoryProgress, LevelInfo}
+}
+
+// Topics 返回全部已登记的 topic 及其分类
+func Topics() []Topic {
+	out := make([]Topic, 0, len(topicOrder))
+	for _, name := range topicOrder {
+		info := topicTable[name]
+		out = append(out, Topic{Name: name, Category: info.category, Level: info.level.String()})
+	}
+	return out
+}
@@ -0,0 +1,243 @@
+// Package chart renders candlestick charts with moving averages, Bollinger
+// Bands and an RSI panel to SVG or PNG using only the standard library.
+package chart
+
+import (
+	"image/color"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+)
+
+// Options controls the rendered size and title.
+type Options struct {
+	Width  int
+	Height int
+	Title  string
+}
+
+func (o Options) withDefaults() Options {
+	if o.Width <= 0 {
+		o.Width = 960
+	}
+	if o.Height <= 0 {
+		o.Height = 540
+	}
+	return o
+}
+
+// Chart holds the candles to draw and the indicator series aligned to them.
+type Chart struct {
+	Symbol     string
+	Candles    []*models.MarketData
+	Indicators map[string][]models.IndicatorValue
+}
+
+// overlay series drawn over the price panel, in drawing order.
+var overlays = []struct {
+	Key   string
+	Color color.RGBA
+	Dash  bool
+}{
+	{Key: "boll_ub", Color: color.RGBA{158, 158, 158, 255}},
+	{Key: "boll_lb", Color: color.RGBA{158, 158, 158, 255}},
+	{Key: "boll", Color: color.RGBA{158, 158, 158, 255}, Dash: true},
+	{Key: "close_10_ema", Color: color.RGBA{255, 152, 0, 255}},
+	{Key: "close_50_sma", Color: color.RGBA{33, 150, 243, 255}},
+	{Key: "close_200_sma", Color: color.RGBA{156, 39, 176, 255}},
+}
+
+var (
+	colorUp     = color.RGBA{38, 166, 154, 255}
+	colorDown   = color.RGBA{239, 83, 80, 255}
+	colorRSI    = color.RGBA{121, 85, 72, 255}
+	colorGrid   = color.RGBA{224, 224, 224, 255}
+	colorText   = color.RGBA{66, 66, 66, 255}
+	colorBorder = color.RGBA{189, 189, 189, 255}
+)
+
+// New builds a chart for candles within [start, end]. data should include
+// enough history before start for the indicators to warm up (200 bars for SMA200).
+func New(symbol string, data []*models.MarketData, start, end time.Time) *Chart {
+	sorted := make([]*models.MarketData, 0, len(data))
+	for _, d := range data {
+		if d != nil {
+			sorted = append(sorted, d)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	var candles []*models.MarketData
+	for _, d := range sorted {
+		t, err := time.Parse("2006-01-02", d.Date)
+		if err != nil || t.Before(start) || t.After(end) {
+			continue
+		}
+		candles = append(candles, d)
+	}
+	return &Chart{
+		Symbol:     symbol,
+		Candles:    candles,
+		Indicators: dataflows.CalculateAllIndicators(sorted, start, end),
+	}
+}
+
+// shape primitives shared by the SVG and raster backends.
+type rect struct {
+	x, y, w, h float64
+	fill       color.RGBA
+}
+
+type line struct {
+	points [][2]float64
+	stroke color.RGBA
+	width  float64
+	dash   bool
+}
+
+type label struct {
+	x, y   float64
+	text   string
+	size   float64
+	anchor string
+}
+
+type scene struct {
+	width, height int
+	rects         []rect
+	lines         []line
+	labels        []label
+}
+
+const (
+	padLeft   = 60.0
+	padRight  = 20.0
+	padTop    = 36.0
+	padBottom = 28.0
+	panelGap  = 16.0
+)
+
+// layout converts the chart data into drawing primitives.
+func (c *Chart) layout(opts Options) *scene {
+	opts = opts.withDefaults()
+	sc := &scene{width: opts.Width, height: opts.Height}
+	title := opts.Title
+	if title == "" {
+		title = c.Symbol
+	}
+	sc.labels = append(sc.labels, label{x: padLeft, y: 22, text: title, size: 16, anchor: "start"})
+
+	plotW := float64(opts.Width) - padLeft - padRight
+	plotH := float64(opts.Height) - padTop - padBottom - panelGap
+	priceH := plotH * 0.72
+	rsiTop := padTop + priceH + panelGap
+	rsiH := plotH - priceH
+
+	sc.rects = append(sc.rects,
+		rect{x: padLeft, y: padTop, w: plotW, h: priceH, fill: color.RGBA{255, 255, 255, 255}},
+		rect{x: padLeft, y: rsiTop, w: plotW, h: rsiH, fill: color.RGBA{255, 255, 255, 255}},
+	)
+	border := func(x, y, w, h float64) {
+		sc.lines = append(sc.lines, line{points: [][2]float64{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}, {x, y}}, stroke: colorBorder, width: 1})
+	}
+	border(padLeft, padTop, plotW, priceH)
+	border(padLeft, rsiTop, plotW, rsiH)
+
+	n := len(c.Candles)
+	if n == 0 {
+		sc.labels = append(sc.labels, label{x: padLeft + plotW/2, y: padTop + priceH/2, text: "no data", size: 14, anchor: "middle"})
+		return sc
+	}
+
+	index := make(map[string]int, n)
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for i, d := range c.Candles {
+		index[d.Date] = i
+		lo = math.Min(lo, d.Low)
+		hi = math.Max(hi, d.High)
+	}
+	for _, ov := range overlays {
+		for _, v := range c.Indicators[ov.Key] {
+			if _, ok := index[v.Date]; ok {
+				lo = math.Min(lo, v.Value)
+				hi = math.Max(hi, v.Value)
+			}
+		}
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+	margin := (hi - lo) * 0.05
+	lo -= margin
+	hi += margin
+
+	step := plotW / float64(n)
+	xAt := func(i int) float64 { return padLeft + step*(float64(i)+0.5) }
+	yPrice := func(v float64) float64 { return padTop + (hi-v)/(hi-lo)*priceH }
+	yRSI := func(v float64) float64 { return rsiTop + (100-v)/100*rsiH }
+
+	// 价格网格与刻度
+	for i := 0; i <= 4; i++ {
+		v := lo + (hi-lo)*float64(i)/4
+		y := yPrice(v)
+		sc.lines = append(sc.lines, line{points: [][2]float64{{padLeft, y}, {padLeft + plotW, y}}, stroke: colorGrid, width: 1})
+		sc.labels = append(sc.labels, label{x: padLeft - 6, y: y + 4, text: formatPrice(v), size: 10, anchor: "end"})
+	}
+	for _, level := range []float64{30, 70} {
+		y := yRSI(level)
+		sc.lines = append(sc.lines, line{points: [][2]float64{{padLeft, y}, {padLeft + plotW, y}}, stroke: colorGrid, width: 1, dash: true})
+		sc.labels = append(sc.labels, label{x: padLeft - 6, y: y + 4, text: formatPrice(level), size: 10, anchor: "end"})
+	}
+	sc.labels = append(sc.labels, label{x: padLeft + 4, y: rsiTop + 12, text: "RSI(14)", size: 10, anchor: "start"})
+
+	// 日期刻度：首、中、尾
+	for _, i := range []int{0, n / 2, n - 1} {
+		sc.labels = append(sc.labels, label{x: xAt(i), y: float64(opts.Height) - 8, text: c.Candles[i].Date, size: 10, anchor: "middle"})
+	}
+
+	bodyW := math.Max(1, step*0.6)
+	for i, d := range c.Candles {
+		col := colorUp
+		if d.Close < d.Open {
+			col = colorDown
+		}
+		x := xAt(i)
+		sc.lines = append(sc.lines, line{points: [][2]float64{{x, yPrice(d.High)}, {x, yPrice(d.Low)}}, stroke: col, width: 1})
+		top := yPrice(math.Max(d.Open, d.Close))
+		h := math.Max(1, yPrice(math.Min(d.Open, d.Close))-top)
+		sc.rects = append(sc.rects, rect{x: x - bodyW/2, y: top, w: bodyW, h: h, fill: col})
+	}
+
+	series := func(key string, y func(float64) float64) [][2]float64 {
+		var pts [][2]float64
+		for _, v := range c.Indicators[key] {
+			if i, ok := index[v.Date]; ok {
+				pts = append(pts, [2]float64{xAt(i), y(v.Value)})
+			}
+		}
+		return pts
+	}
+	for _, ov := range overlays {
+		if pts := series(ov.Key, yPrice); len(pts) > 1 {
+			sc.lines = append(sc.lines, line{points: pts, stroke: ov.Color, width: 1.5, dash: ov.Dash})
+		}
+	}
+	if pts := series("rsi", yRSI); len(pts) > 1 {
+		sc.lines = append(sc.lines, line{points: pts, stroke: colorRSI, width: 1.5})
+	}
+	return sc
+}
+
+func formatPrice(v float64) string {
+	switch {
+	case math.Abs(v) >= 1000:
+		return trimFloat(v, 0)
+	case math.Abs(v) >= 10:
+		return trimFloat(v, 1)
+	default:
+		return trimFloat(v, 2)
+	}
+}
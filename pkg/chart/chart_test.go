@@ -0,0 +1,76 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+func syntheticBars(n int) []*models.MarketData {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := make([]*models.MarketData, 0, n)
+	price := 100.0
+	for i := 0; i < n; i++ {
+		open := price
+		if i%3 == 0 {
+			price -= 1.5
+		} else {
+			price += 1.2
+		}
+		bars = append(bars, &models.MarketData{
+			Symbol: "TEST.US",
+			Date:   start.AddDate(0, 0, i).Format("2006-01-02"),
+			Open:   open,
+			Close:  price,
+			High:   max(open, price) + 0.8,
+			Low:    min(open, price) - 0.8,
+			Volume: 1000 + int64(i),
+		})
+	}
+	return bars
+}
+
+func TestChartRendersRangeWithIndicators(t *testing.T) {
+	bars := syntheticBars(120)
+	start, _ := time.Parse("2006-01-02", bars[60].Date)
+	end, _ := time.Parse("2006-01-02", bars[119].Date)
+
+	c := New("TEST.US", bars, start, end)
+	if len(c.Candles) != 60 {
+		t.Fatalf("candles in range = %d, want 60", len(c.Candles))
+	}
+
+	svg := c.SVG(Options{})
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, "RSI(14)") {
+		t.Fatalf("unexpected svg output: %.200s", svg)
+	}
+	// 50 SMA 与布林带应作为折线绘制
+	for _, col := range []string{"#2196f3", "#9e9e9e", "#795548"} {
+		if !strings.Contains(svg, `stroke="`+col+`" stroke-width="1.5"`) {
+			t.Fatalf("missing series with color %s", col)
+		}
+	}
+
+	data, err := c.PNG(Options{Width: 400, Height: 300})
+	if err != nil {
+		t.Fatalf("PNG: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decode png: %v", err)
+	}
+	if img.Bounds().Dx() != 400 || img.Bounds().Dy() != 300 {
+		t.Fatalf("png size = %v", img.Bounds())
+	}
+}
+
+func TestChartEmptyRange(t *testing.T) {
+	c := New("TEST.US", nil, time.Now().AddDate(0, -1, 0), time.Now())
+	if svg := c.SVG(Options{}); !strings.Contains(svg, "no data") {
+		t.Fatal("expected placeholder for empty chart")
+	}
+}
@@ -0,0 +1,65 @@
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+)
+
+// Image rasterizes the chart. Text labels are only rendered in SVG output
+// since the standard library has no font rasterizer.
+func (c *Chart) Image(opts Options) *image.RGBA {
+	sc := c.layout(opts)
+	img := image.NewRGBA(image.Rect(0, 0, sc.width, sc.height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: color.RGBA{250, 250, 250, 255}}, image.Point{}, draw.Src)
+	for _, r := range sc.rects {
+		rr := image.Rect(int(math.Round(r.x)), int(math.Round(r.y)), int(math.Round(r.x+r.w)), int(math.Round(r.y+r.h)))
+		draw.Draw(img, rr, &image.Uniform{C: r.fill}, image.Point{}, draw.Src)
+	}
+	for _, l := range sc.lines {
+		for i := 1; i < len(l.points); i++ {
+			drawLine(img, l.points[i-1], l.points[i], l.stroke, l.width, l.dash)
+		}
+	}
+	return img
+}
+
+// PNG renders the chart as PNG bytes.
+func (c *Chart) PNG(opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.Image(opts)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine plots a segment by sampling along its length; width > 1 thickens it
+// perpendicular to the dominant axis.
+func drawLine(img *image.RGBA, a, b [2]float64, col color.RGBA, width float64, dash bool) {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	length := math.Hypot(dx, dy)
+	steps := int(math.Ceil(length))
+	if steps == 0 {
+		steps = 1
+	}
+	half := int(math.Floor(width / 2))
+	horizontal := math.Abs(dx) >= math.Abs(dy)
+	for i := 0; i <= steps; i++ {
+		if dash && (i/4)%2 == 1 {
+			continue
+		}
+		t := float64(i) / float64(steps)
+		x := int(math.Round(a[0] + dx*t))
+		y := int(math.Round(a[1] + dy*t))
+		for o := -half; o <= half; o++ {
+			if horizontal {
+				img.SetRGBA(x, y+o, col)
+			} else {
+				img.SetRGBA(x+o, y, col)
+			}
+		}
+	}
+}
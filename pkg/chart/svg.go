@@ -0,0 +1,47 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// SVG renders the chart as a standalone SVG document.
+func (c *Chart) SVG(opts Options) string {
+	sc := c.layout(opts)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n",
+		sc.width, sc.height, sc.width, sc.height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#fafafa"/>`+"\n")
+	for _, r := range sc.rects {
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`+"\n", r.x, r.y, r.w, r.h, hexColor(r.fill))
+	}
+	for _, l := range sc.lines {
+		pts := make([]string, len(l.points))
+		for i, p := range l.points {
+			pts[i] = fmt.Sprintf("%.1f,%.1f", p[0], p[1])
+		}
+		dash := ""
+		if l.dash {
+			dash = ` stroke-dasharray="4 3"`
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%.1f"%s/>`+"\n",
+			strings.Join(pts, " "), hexColor(l.stroke), l.width, dash)
+	}
+	for _, t := range sc.labels {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%.0f" fill="%s" text-anchor="%s">%s</text>`+"\n",
+			t.x, t.y, t.size, hexColor(colorText), t.anchor, html.EscapeString(t.text))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func trimFloat(v float64, prec int) string {
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
@@ -0,0 +1,323 @@
+package cortex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/agents"
+	"github.com/dyike/CortexGo/internal/bundle"
+	"github.com/dyike/CortexGo/internal/calibration"
+	"github.com/dyike/CortexGo/internal/graph"
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/rundir"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+)
+
+// Request describes one analysis run.
+type Request struct {
+	Symbol string // required, e.g. AAPL.US or 700.HK
+	// TradeDate is YYYY-MM-DD; empty means today.
+	TradeDate string
+	// Prompt overrides the default "Analyze trading opportunities for <symbol> on <date>".
+	Prompt string
+	// Depth overrides the configured preset: quick, standard or deep.
+	Depth string
+	// RiskProfile overrides the configured risk profile: conservative, balanced or aggressive.
+	RiskProfile string
+	// Offline serves every tool from cache and local archives only.
+	Offline bool
+	// ExportToolData writes every raw tool result of the run to a zip under
+	// the results directory; Result.DataBundle is its path.
+	ExportToolData bool
+}
+
+// Section is one titled block of the final report, usually one agent's output.
+type Section struct {
+	Key     string `json:"key"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+// Result is the outcome of a finished analysis.
+type Result struct {
+	SessionID      string    `json:"session_id"`
+	Symbol         string    `json:"symbol"`
+	TradeDate      string    `json:"trade_date"`
+	Recommendation string    `json:"recommendation"` // BUY, HOLD, SELL or empty
+	Confidence     float64   `json:"confidence"`     // 0-1, 0 when the agents gave none
+	EntryPrice     float64   `json:"entry_price,omitempty"`
+	StopLoss       float64   `json:"stop_loss,omitempty"`
+	TakeProfit     float64   `json:"take_profit,omitempty"`
+	Sections       []Section `json:"sections"`
+	GeneratedAt    time.Time `json:"generated_at"`
+	// CalibratedConfidence maps Confidence onto the deciding agent's past hit
+	// rate; 0 until enough of its calls have been evaluated.
+	CalibratedConfidence float64 `json:"calibrated_confidence,omitempty"`
+	// Markdown is the full report rendered as Markdown, with the
+	// report_template_md template when one is configured.
+	Markdown string `json:"markdown"`
+	// DataBundle is the zip of the run's raw tool results when requested.
+	DataBundle string `json:"data_bundle,omitempty"`
+	// RunDir is the run's directory: its state, events, agent reports and
+	// manifest.json.
+	RunDir string `json:"run_dir,omitempty"`
+	// RunInputs records what produced the result: the build, config and
+	// prompt digests, the models, the tool versions and data dates.
+	RunInputs *RunInputs `json:"run_inputs,omitempty"`
+}
+
+// Event types delivered to AnalyzeStream handlers; they match the agent.*
+// callback topics of libcortex, except EventMessageStop, whose libcortex
+// topic keeps its historical spelling agent.messgae_chunk_stop.
+const (
+	EventMessageChunk = "message_chunk"
+	EventMessageStop  = "message_chunk_stop"
+	EventToolCallStop = "tool_call_stop"
+	EventText         = "text_final"
+	EventToolResult   = "tool_call_result_final"
+	EventError        = "error"
+)
+
+// Event is one incremental update from a running analysis.
+type Event struct {
+	Type       string `json:"type"`
+	Agent      string `json:"agent"`
+	Role       string `json:"role,omitempty"`
+	Content    string `json:"content,omitempty"`
+	ToolName   string `json:"tool_name,omitempty"`
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	// ToolCalls is the JSON encoded list of tool calls requested by the agent.
+	ToolCalls string `json:"tool_calls,omitempty"`
+}
+
+// Analyze runs the full agent graph for req and blocks until it finishes.
+func (c *Client) Analyze(ctx context.Context, req Request) (*Result, error) {
+	return c.AnalyzeStream(ctx, req, nil)
+}
+
+// AnalyzeStream is Analyze with every incremental event passed to handler as
+// it happens. handler is called from the goroutines reading the model streams,
+// one call at a time, and should return quickly; all calls have returned by
+// the time AnalyzeStream does. The run is stored in agent.db, so ListResults and Backtest see it.
+// Cancelling ctx stops the graph and returns ctx.Err().
+func (c *Client) AnalyzeStream(ctx context.Context, req Request, handler func(Event)) (*Result, error) {
+	cfg, tradeDate, err := c.runConfig(&req)
+	if err != nil {
+		return nil, err
+	}
+	if err := agents.InitChatModel(ctx, &cfg); err != nil {
+		return nil, fmt.Errorf("init chat model: %w", err)
+	}
+	store, err := c.db()
+	if err != nil {
+		return nil, fmt.Errorf("open results db: %w", err)
+	}
+	session := &models.SessionRecord{
+		Symbol:    req.Symbol,
+		TradeDate: req.TradeDate,
+		Prompt:    req.Prompt,
+		Status:    storage.StatusInit,
+	}
+	if _, err := store.CreateSession(ctx, session); err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
+
+	var finalState *models.TradingState
+	genFunc := func(ctx context.Context) *models.TradingState {
+		finalState = models.NewTradingState(req.Symbol, tradeDate, req.Prompt, &cfg)
+		return finalState
+	}
+	events := &rundir.Events{}
+	var handlerMu sync.Mutex
+	emit := func(event string, data *models.ChatResp) {
+		events.Record(event, data)
+		if handler == nil || data == nil {
+			return
+		}
+		if event == "messgae_chunk_stop" {
+			event = EventMessageStop
+		}
+		ev := Event{
+			Type:       event,
+			Agent:      data.AgentName,
+			Role:       data.Role,
+			Content:    data.Content,
+			ToolName:   data.ToolName,
+			ToolCallID: data.ToolCallId,
+		}
+		if len(data.ToolCalls) > 0 {
+			if encoded, err := json.Marshal(data.ToolCalls); err == nil {
+				ev.ToolCalls = string(encoded)
+			}
+		}
+		handlerMu.Lock()
+		defer handlerMu.Unlock()
+		handler(ev)
+	}
+
+	orchestrator := graph.NewTradingOrchestrator[string, string, *models.TradingState](ctx, genFunc, &cfg)
+	logger := &graph.LoggerCallback{Emit: emit}
+	_, streamErr := orchestrator.Stream(ctx, req.Prompt, compose.WithCallbacks(logger))
+	// events of the last model streams may still be on their way
+	logger.Wait()
+
+	// Record the final status even when the caller cancelled ctx.
+	saveCtx := context.WithoutCancel(ctx)
+	sessionID := strconv.FormatInt(session.Id, 10)
+	// the run directory is a side output: failing to write it keeps the result
+	finishRun := func(rep *report.Report, runErr error) string {
+		if finalState == nil {
+			return ""
+		}
+		dir, err := rundir.Finish(saveCtx, &cfg, finalState, rep, events, sessionID, runErr)
+		if err != nil {
+			fmt.Printf("write run directory err=%v\n", err)
+			return ""
+		}
+		return rundir.Location(&cfg, dir)
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		_ = store.UpdateSessionStatus(saveCtx, session.Id, storage.StatusCancelled)
+		finishRun(nil, ctxErr)
+		return nil, ctxErr
+	}
+	if streamErr != nil {
+		_ = store.UpdateSessionStatus(saveCtx, session.Id, storage.StatusError)
+		finishRun(nil, streamErr)
+		return nil, streamErr
+	}
+	rep := report.FromState(finalState)
+	if rep == nil {
+		_ = store.UpdateSessionStatus(saveCtx, session.Id, storage.StatusError)
+		return nil, fmt.Errorf("analysis produced no report")
+	}
+	rep.SessionID = sessionID
+	if cfg.ExportToolData {
+		// the bundle is a side output: failing to write it keeps the report
+		if path, err := bundle.Write(saveCtx, &cfg, finalState, rep.SessionID); err != nil {
+			fmt.Printf("write tool data bundle err=%v\n", err)
+		} else {
+			rep.DataBundle = path
+		}
+	}
+	if err := saveReport(saveCtx, &cfg, store, session.Id, rep); err != nil {
+		return nil, err
+	}
+	if err := store.UpdateSessionStatus(saveCtx, session.Id, storage.StatusDone); err != nil {
+		return nil, err
+	}
+	res := newResult(&cfg, rep)
+	res.RunDir = finishRun(rep, nil)
+	return res, nil
+}
+
+// runConfig validates req, fills its defaults and returns the per-run config.
+func (c *Client) runConfig(req *Request) (config.Config, time.Time, error) {
+	req.Symbol = strings.TrimSpace(req.Symbol)
+	if req.Symbol == "" {
+		return config.Config{}, time.Time{}, fmt.Errorf("symbol is required")
+	}
+	if req.TradeDate == "" {
+		req.TradeDate = time.Now().Format("2006-01-02")
+	}
+	tradeDate, err := time.Parse("2006-01-02", req.TradeDate)
+	if err != nil {
+		return config.Config{}, time.Time{}, fmt.Errorf("invalid trade date: %w", err)
+	}
+	if strings.TrimSpace(req.Prompt) == "" {
+		req.Prompt = fmt.Sprintf("Analyze trading opportunities for %s on %s", req.Symbol, req.TradeDate)
+	}
+
+	cfg := c.cfg.Clone()
+	if err := cfg.LLMReady(); err != nil {
+		return config.Config{}, time.Time{}, err
+	}
+	if req.Depth != "" {
+		if !config.ValidDepth(req.Depth) {
+			return config.Config{}, time.Time{}, fmt.Errorf("invalid depth %q: want quick, standard or deep", req.Depth)
+		}
+		cfg.Depth = req.Depth
+	}
+	if req.RiskProfile != "" {
+		if !config.ValidRiskProfile(req.RiskProfile) {
+			return config.Config{}, time.Time{}, fmt.Errorf("invalid risk profile %q: want conservative, balanced or aggressive", req.RiskProfile)
+		}
+		cfg.RiskProfile = req.RiskProfile
+	}
+	if req.Offline {
+		cfg.Offline = true
+	}
+	if req.ExportToolData {
+		cfg.ExportToolData = true
+	}
+	if cfg.Offline {
+		if missing := tools.OfflinePreflight(&cfg, req.Symbol); len(missing) > 0 {
+			return config.Config{}, time.Time{}, fmt.Errorf("offline mode: missing local data: %s", strings.Join(missing, "; "))
+		}
+	}
+	return cfg, tradeDate, nil
+}
+
+func saveReport(ctx context.Context, cfg *config.Config, store *storage.Store, sessionID int64, rep *report.Report) error {
+	// a failed calibration keeps the stated confidence and still saves the report
+	if err := calibration.Annotate(ctx, store, rep); err != nil {
+		fmt.Printf("calibrate decision err=%v\n", err)
+	}
+	content, err := json.Marshal(rep)
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+	if err := store.SaveReport(ctx, &models.ReportRecord{
+		SessionId:      sessionID,
+		Symbol:         rep.Symbol,
+		TradeDate:      rep.TradeDate,
+		Recommendation: rep.Recommendation,
+		Content:        string(content),
+	}); err != nil {
+		return fmt.Errorf("save report: %w", err)
+	}
+	// the session document is a copy of what agent.db holds: failing to write it keeps the report
+	if err := report.SaveSession(ctx, cfg, sessionID, rep); err != nil {
+		fmt.Printf("save session document session=%d err=%v\n", sessionID, err)
+	}
+	if err := store.SaveAgentConfidences(ctx, sessionID, report.AgentConfidences(rep)); err != nil {
+		return err
+	}
+	if rep.Decision != nil {
+		return store.SaveDecision(ctx, sessionID, rep.Decision)
+	}
+	return nil
+}
+
+func newResult(cfg *config.Config, rep *report.Report) *Result {
+	res := &Result{
+		SessionID:      rep.SessionID,
+		Symbol:         rep.Symbol,
+		TradeDate:      rep.TradeDate,
+		Recommendation: rep.Recommendation,
+		GeneratedAt:    rep.GeneratedAt,
+		Markdown:       string(report.Rendered(cfg, rep, "md")),
+		DataBundle:     rep.DataBundle,
+		RunInputs:      rep.RunInputs,
+	}
+	for _, s := range rep.Sections {
+		res.Sections = append(res.Sections, Section{Key: s.Key, Title: s.Title, Content: s.Content})
+	}
+	if d := report.ExtractDecision(rep); d != nil {
+		res.Confidence = d.Confidence
+		res.EntryPrice, res.StopLoss, res.TakeProfit = d.EntryPrice, d.StopLoss, d.TakeProfit
+	}
+	if rep.Decision != nil {
+		res.CalibratedConfidence = rep.Decision.CalibratedConfidence
+	}
+	return res
+}
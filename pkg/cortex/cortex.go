@@ -0,0 +1,114 @@
+// Package cortex lets Go programs embed the CortexGo analysis engine directly,
+// without going through libcortex or copying cmd/ code. Its types are stable;
+// internal packages may change underneath them.
+package cortex
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/storage"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/dataflows"
+	"github.com/dyike/CortexGo/pkg/persist"
+	"github.com/dyike/CortexGo/pkg/secure"
+)
+
+// Config is the engine configuration, see config.Config.
+type Config = config.Config
+
+// RunInputs describes what a run was made of, see models.RunInputs.
+type RunInputs = models.RunInputs
+
+// Storage keeps reports, session documents, caches and memory documents,
+// see persist.Storage.
+type Storage = persist.Storage
+
+// SetStorage makes every client in the process, and the tools and agents
+// they run, keep their documents in s instead of the backend named by
+// storage_backend. agent.db stays the query index. nil restores the config.
+func SetStorage(s Storage) {
+	persist.SetDefault(s)
+}
+
+// SharedSession shares market-wide data (the market regime, finance
+// headlines, the yield curve, the VIX) between analyses, see
+// dataflows.Session.
+type SharedSession = dataflows.Session
+
+// NewSharedSession returns a session for a batch of analyses. Analyze calls
+// whose context carries it (WithSharedSession) fetch market-wide data once.
+func NewSharedSession() *SharedSession {
+	return dataflows.NewSession(tools.MarketWideTools...)
+}
+
+// WithSharedSession attaches s to ctx.
+func WithSharedSession(ctx context.Context, s *SharedSession) context.Context {
+	return dataflows.WithSession(ctx, s)
+}
+
+// Client runs analyses and reads stored results with one fixed config.
+// A Client is safe for concurrent use.
+type Client struct {
+	cfg config.Config
+
+	storeOnce sync.Once
+	store     *storage.Store
+	storeErr  error
+}
+
+// New returns a client for cfg. A nil cfg resolves the config the same way
+// the demo CLI does: $CORTEXGO_CONFIG, ./cortexgo.json, the user config dir,
+// then CORTEXGO_* and legacy environment variables.
+func New(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		resolved, _, err := config.LoadResolved("")
+		if err != nil {
+			return nil, err
+		}
+		cfg = resolved
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Client{cfg: cfg.Clone()}, nil
+}
+
+// Config returns a copy of the client's config.
+func (c *Client) Config() Config {
+	return c.cfg.Clone()
+}
+
+// Close releases the results database, if it was opened.
+func (c *Client) Close() error {
+	if c.store != nil {
+		return c.store.Close()
+	}
+	return nil
+}
+
+// db opens <data_dir>/agent.db on first use, with the configured encryption key.
+func (c *Client) db() (*storage.Store, error) {
+	c.storeOnce.Do(func() {
+		dataDir := strings.TrimSpace(c.cfg.DataDir)
+		if dataDir == "" {
+			c.storeErr = storage.ErrDataDirNotConfigured
+			return
+		}
+		cipher, err := secure.ForConfig(&c.cfg)
+		if err != nil {
+			c.storeErr = fmt.Errorf("load encryption key: %w", err)
+			return
+		}
+		c.store, c.storeErr = storage.NewStore(filepath.Join(dataDir, "agent.db"))
+		if c.storeErr == nil {
+			c.store.SetCipher(cipher)
+		}
+	})
+	return c.store, c.storeErr
+}
@@ -0,0 +1,124 @@
+package cortex
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/internal/agents"
+)
+
+func TestClientValidatesAndListsEmptyResults(t *testing.T) {
+	if _, err := New(&Config{}); err == nil {
+		t.Fatal("New should reject a config without directories")
+	}
+
+	cfg := config.DefaultConfigWithRoot(t.TempDir())
+	cfg.DeepSeekAPIKey = ""
+	client, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	if _, err := client.Analyze(ctx, Request{}); err == nil || !strings.Contains(err.Error(), "symbol") {
+		t.Fatalf("want symbol error, got %v", err)
+	}
+	if _, err := client.Analyze(ctx, Request{Symbol: "AAPL.US", TradeDate: "2025-12-15"}); err == nil || !strings.Contains(err.Error(), "api key") {
+		t.Fatalf("want api key error, got %v", err)
+	}
+	if _, err := client.Backtest(ctx, BacktestRequest{Symbol: "AAPL.US"}); err == nil {
+		t.Fatal("Backtest without trade dates should fail")
+	}
+
+	runs, err := client.ListResults(ctx, ResultFilter{})
+	if err != nil {
+		t.Fatalf("ListResults: %v", err)
+	}
+	if len(runs) != 0 {
+		t.Fatalf("fresh data dir has %d runs", len(runs))
+	}
+}
+
+// analyzeScripted runs AAPL.US with the mock LLM playing script, passing the
+// run's events to handler when it is not nil.
+func analyzeScripted(t *testing.T, script string, setup func(*config.Config), handler func(Event)) *Result {
+	t.Helper()
+	root := t.TempDir()
+	t.Chdir(root) // agents write their markdown reports under ./results
+	path := filepath.Join(root, "script.json")
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// the chat model is process-wide; rebuild it for this script
+	agents.ResetChatModel()
+	t.Cleanup(agents.ResetChatModel)
+	cfg := config.DefaultConfigWithRoot(root)
+	cfg.DeepSeekAPIKey = ""
+	cfg.LLMProvider = config.LLMMock
+	cfg.MockLLMScript = path
+	cfg.SkipMarketContext = true
+	if setup != nil {
+		setup(cfg)
+	}
+	client, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer client.Close()
+
+	res, err := client.AnalyzeStream(context.Background(), Request{Symbol: "AAPL.US", TradeDate: "2025-06-02"}, handler)
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	return res
+}
+
+func TestAnalyzeWithMockLLM(t *testing.T) {
+	var tools []string
+	res := analyzeScripted(t, `{
+		"market_analyst": [
+			{"tool_calls": [{"name": "get_market_data", "arguments": {"symbol": "AAPL.US", "count": 5}}]},
+			{"content": "Scripted market report"}
+		],
+		"risk_judge": [{"content": "FINAL TRANSACTION PROPOSAL: **BUY**\nCONFIDENCE: 0.7"}]
+	}`, nil, func(ev Event) {
+		if ev.Type == EventToolResult {
+			tools = append(tools, ev.ToolName)
+		}
+	})
+	if res.Recommendation != "BUY" || res.Confidence != 0.7 {
+		t.Errorf("result = %s at %v", res.Recommendation, res.Confidence)
+	}
+	if !strings.Contains(res.Markdown, "Scripted market report") || !strings.Contains(res.Markdown, "Mock LLM response") {
+		t.Errorf("report does not carry the scripted and canned responses:\n%s", res.Markdown)
+	}
+	if len(tools) != 1 || tools[0] != "get_market_data" {
+		t.Errorf("tool results = %v, want the scripted get_market_data call", tools)
+	}
+	if !strings.Contains(res.Markdown, "longport (mock") {
+		t.Errorf("report has no provenance of the scripted get_market_data call:\n%s", res.Markdown)
+	}
+}
+
+func TestAnalyzeWithSimulatedData(t *testing.T) {
+	res := analyzeScripted(t, `{
+		"market_analyst": [
+			{"tool_calls": [{"name": "get_market_data", "arguments": {"symbol": "AAPL.US", "count": 60}}]},
+			{"content": "Scripted market report"}
+		],
+		"news_analyst": [
+			{"tool_calls": [{"name": "get_google_stock_news", "arguments": {"symbol": "AAPL"}}]},
+			{"content": "Scripted news report"}
+		]
+	}`, func(cfg *config.Config) { cfg.DataProvider = config.DataSimulated }, nil)
+	for _, want := range []string{"longport (simulated", "google_news (simulated"} {
+		if !strings.Contains(res.Markdown, want) {
+			t.Errorf("report lacks %q:\n%s", want, res.Markdown)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package cortex
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/dyike/CortexGo/internal/report"
+	"github.com/dyike/CortexGo/internal/tools"
+	"github.com/dyike/CortexGo/models"
+)
+
+// ResultFilter selects stored runs; zero fields do not filter.
+type ResultFilter struct {
+	Symbol         string  // substring match
+	Recommendation string  // BUY, HOLD or SELL
+	Status         string  // init, done, error or cancelled
+	Since          string  // trade_date >= Since (YYYY-MM-DD)
+	MinConfidence  float64 // 0-1
+	Limit          int     // default 100, negative for no limit
+}
+
+// Run summarizes one stored analysis, newest first in ListResults.
+type Run struct {
+	SessionID      string    `json:"session_id"`
+	Symbol         string    `json:"symbol"`
+	TradeDate      string    `json:"trade_date"`
+	Status         string    `json:"status"`
+	Recommendation string    `json:"recommendation"`
+	Confidence     float64   `json:"confidence"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ListResults returns stored runs from <data_dir>/agent.db, including those
+// started through libcortex on the same data dir.
+func (c *Client) ListResults(ctx context.Context, filter ResultFilter) ([]Run, error) {
+	store, err := c.db()
+	if err != nil {
+		return nil, fmt.Errorf("open results db: %w", err)
+	}
+	recs, err := store.ListRuns(ctx, models.RunFilter{
+		Symbol:         filter.Symbol,
+		Recommendation: filter.Recommendation,
+		Status:         filter.Status,
+		Since:          filter.Since,
+		MinConfidence:  filter.MinConfidence,
+		Limit:          filter.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	runs := make([]Run, 0, len(recs))
+	for _, rec := range recs {
+		runs = append(runs, Run{
+			SessionID:      strconv.FormatInt(rec.Id, 10),
+			Symbol:         rec.Symbol,
+			TradeDate:      rec.TradeDate,
+			Status:         rec.Status,
+			Recommendation: rec.Recommendation,
+			Confidence:     rec.Confidence,
+			CreatedAt:      rec.CreatedAt,
+		})
+	}
+	return runs, nil
+}
+
+// BacktestRequest replays the analysis for one symbol on past trade dates.
+type BacktestRequest struct {
+	Symbol     string
+	TradeDates []string // YYYY-MM-DD, analyzed in order
+	// HorizonDays is the holding period in trading days; default 5.
+	HorizonDays int
+	Depth       string
+	Offline     bool
+	// OnTrade, if set, is called after each trade date is analyzed and scored.
+	OnTrade func(BacktestTrade)
+}
+
+// BacktestTrade is the recommendation for one trade date and how it played out.
+type BacktestTrade struct {
+	TradeDate      string  `json:"trade_date"`
+	SessionID      string  `json:"session_id,omitempty"`
+	Recommendation string  `json:"recommendation"`
+	Confidence     float64 `json:"confidence"`
+	Evaluated      bool    `json:"evaluated"`
+	EntryClose     float64 `json:"entry_close,omitempty"`
+	ExitClose      float64 `json:"exit_close,omitempty"`
+	ReturnPct      float64 `json:"return_pct,omitempty"`
+	Correct        bool    `json:"correct"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// BacktestResult aggregates the scored trades. A BUY is correct when the
+// price rose over the horizon, a SELL when it fell, a HOLD when it moved less
+// than report.HoldBand.
+type BacktestResult struct {
+	Symbol       string          `json:"symbol"`
+	HorizonDays  int             `json:"horizon_days"`
+	Trades       []BacktestTrade `json:"trades"`
+	Evaluated    int             `json:"evaluated"`
+	Correct      int             `json:"correct"`
+	HitRate      float64         `json:"hit_rate"`
+	AvgReturnPct float64         `json:"avg_return_pct"`
+}
+
+// Backtest analyzes req.Symbol on each trade date and scores every
+// recommendation against the price horizon days later. Failed dates are kept
+// with Error set; outcomes are stored so results.stats includes them.
+func (c *Client) Backtest(ctx context.Context, req BacktestRequest) (*BacktestResult, error) {
+	if req.Symbol == "" || len(req.TradeDates) == 0 {
+		return nil, fmt.Errorf("symbol and trade dates are required")
+	}
+	if req.HorizonDays <= 0 {
+		req.HorizonDays = 5
+	}
+	store, err := c.db()
+	if err != nil {
+		return nil, fmt.Errorf("open results db: %w", err)
+	}
+
+	// One bar series covers every date: from the earliest trade date to today
+	// plus headroom for weekends and holidays.
+	earliest := req.TradeDates[0]
+	for _, d := range req.TradeDates {
+		if d < earliest {
+			earliest = d
+		}
+	}
+	start, err := time.Parse("2006-01-02", earliest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trade date %q: %w", earliest, err)
+	}
+	cfg := c.cfg.Clone()
+	if req.Offline {
+		cfg.Offline = true
+	}
+	bars, barsErr := tools.FetchMarketData(ctx, &cfg, req.Symbol, int(time.Since(start).Hours()/24)+10)
+
+	out := &BacktestResult{Symbol: req.Symbol, HorizonDays: req.HorizonDays}
+	var totalReturn float64
+	for _, date := range req.TradeDates {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		trade := BacktestTrade{TradeDate: date}
+		res, err := c.Analyze(ctx, Request{Symbol: req.Symbol, TradeDate: date, Depth: req.Depth, Offline: req.Offline})
+		switch {
+		case err != nil:
+			trade.Error = err.Error()
+		case barsErr != nil:
+			trade.SessionID, trade.Recommendation, trade.Confidence = res.SessionID, res.Recommendation, res.Confidence
+			trade.Error = fmt.Sprintf("fetch prices: %v", barsErr)
+		default:
+			trade.SessionID, trade.Recommendation, trade.Confidence = res.SessionID, res.Recommendation, res.Confidence
+			outcome, err := report.EvaluateOutcome(res.Recommendation, bars, date, req.HorizonDays)
+			if err != nil {
+				trade.Error = err.Error()
+				break
+			}
+			trade.Evaluated = true
+			trade.EntryClose, trade.ExitClose = outcome.EntryClose, outcome.ExitClose
+			trade.ReturnPct, trade.Correct = outcome.ReturnPct, outcome.Correct
+			if id, err := strconv.ParseInt(res.SessionID, 10, 64); err == nil {
+				outcome.SessionId = id
+				if err := store.SaveOutcome(ctx, outcome); err != nil {
+					return out, err
+				}
+			}
+			out.Evaluated++
+			totalReturn += outcome.ReturnPct
+			if outcome.Correct {
+				out.Correct++
+			}
+		}
+		out.Trades = append(out.Trades, trade)
+		if req.OnTrade != nil {
+			req.OnTrade(trade)
+		}
+	}
+	if out.Evaluated > 0 {
+		out.HitRate = float64(out.Correct) / float64(out.Evaluated)
+		out.AvgReturnPct = totalReturn / float64(out.Evaluated)
+	}
+	return out, nil
+}
@@ -0,0 +1,77 @@
+package dataflows
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// BarFetcher returns daily bars for symbol, oldest first. The regime,
+// volatility, structure, intermarket, correlation and FX layers take one so
+// they can run on live quotes, cached data or test fixtures alike.
+type BarFetcher func(ctx context.Context, symbol string, count int) ([]*models.MarketData, error)
+
+// BarsUpTo keeps the bars with a close dated on or before date (all of them
+// when date is empty), sorted oldest first.
+func BarsUpTo(bars []*models.MarketData, date string) []*models.MarketData {
+	out := make([]*models.MarketData, 0, len(bars))
+	for _, bar := range bars {
+		if bar != nil && bar.Close > 0 && (date == "" || bar.Date <= date) {
+			out = append(out, bar)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
+
+// Closes returns the closes of bars, in order.
+func Closes(bars []*models.MarketData) []float64 {
+	closes := make([]float64, len(bars))
+	for i, bar := range bars {
+		closes[i] = bar.Close
+	}
+	return closes
+}
+
+// DailyReturns maps the date of each bar after the first to its
+// close-to-close return; bars are oldest first.
+func DailyReturns(bars []*models.MarketData) map[string]float64 {
+	returns := make(map[string]float64, len(bars))
+	for i := 1; i < len(bars); i++ {
+		returns[bars[i].Date] = bars[i].Close/bars[i-1].Close - 1
+	}
+	return returns
+}
+
+// Correlation is the Pearson correlation of two return series over their
+// shared dates. It reports false when they share fewer than minOverlap
+// dates or either is flat.
+func Correlation(a, b map[string]float64, minOverlap int) (float64, bool) {
+	var xs, ys []float64
+	for date, x := range a {
+		if y, ok := b[date]; ok {
+			xs, ys = append(xs, x), append(ys, y)
+		}
+	}
+	if len(xs) == 0 || len(xs) < minOverlap {
+		return 0, false
+	}
+	var mx, my float64
+	for i := range xs {
+		mx, my = mx+xs[i], my+ys[i]
+	}
+	mx, my = mx/float64(len(xs)), my/float64(len(ys))
+	var cov, vx, vy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(vx*vy), true
+}
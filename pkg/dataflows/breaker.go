@@ -0,0 +1,139 @@
+package dataflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending a request to a host whose recent
+// requests kept failing, until its cooldown has passed.
+var ErrCircuitOpen = errors.New("circuit open")
+
+const (
+	// breakerThreshold consecutive failures open a breaker.
+	breakerThreshold = 5
+	// breakerCooldown is how long an open breaker rejects requests before it
+	// lets a single trial request through.
+	breakerCooldown = 30 * time.Second
+)
+
+// circuitBreaker trips after threshold consecutive failures (breakerThreshold
+// when zero). Once the cooldown (breakerCooldown when zero) has passed it is
+// half-open: one trial request is let through, and its outcome closes the
+// breaker or opens it for another cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func (b *circuitBreaker) limits() (int, time.Duration) {
+	threshold, cooldown := b.threshold, b.cooldown
+	if threshold <= 0 {
+		threshold = breakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = breakerCooldown
+	}
+	return threshold, cooldown
+}
+
+func (b *circuitBreaker) allow() bool {
+	threshold, _ := b.limits()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < threshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *circuitBreaker) record(ok bool) {
+	threshold, cooldown := b.limits()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if ok {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= threshold {
+		b.openUntil = time.Now().Add(cooldown)
+	}
+}
+
+// state reports the consecutive failures and, while the breaker is open, when
+// it will let a trial request through.
+func (b *circuitBreaker) state() (failures int, retryAt time.Time) {
+	threshold, _ := b.limits()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures >= threshold && time.Now().Before(b.openUntil) {
+		retryAt = b.openUntil
+	}
+	return b.failures, retryAt
+}
+
+// release ends a trial request without counting its outcome.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+// breakerTransport keeps a circuit breaker per host in front of next, so a
+// site that is down or throttling us fails fast instead of eating the retry
+// budget of every tool call. Network errors, 5xx and 429 responses count as
+// failures; canceled requests do not count either way.
+type breakerTransport struct {
+	next  http.RoundTripper
+	mu    sync.Mutex
+	hosts map[string]*circuitBreaker
+}
+
+func newBreakerTransport(next http.RoundTripper) *breakerTransport {
+	return &breakerTransport{next: next, hosts: map[string]*circuitBreaker{}}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b := t.breaker(req.URL.Host)
+	if !b.allow() {
+		return nil, fmt.Errorf("%s: %w", req.URL.Host, ErrCircuitOpen)
+	}
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if errors.Is(err, context.Canceled) {
+		b.release()
+		return resp, err
+	}
+	b.record(err == nil && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests)
+	return resp, err
+}
+
+func (t *breakerTransport) breaker(host string) *circuitBreaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b, ok := t.hosts[host]
+	if !ok {
+		b = &circuitBreaker{}
+		t.hosts[host] = b
+	}
+	return b
+}
@@ -0,0 +1,69 @@
+package dataflows
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreakerTransportOpensPerHost(t *testing.T) {
+	var hits atomic.Int32
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer up.Close()
+
+	client := &http.Client{Transport: newBreakerTransport(nil)}
+	for range breakerThreshold {
+		resp, err := client.Get(down.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if _, err := client.Get(down.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after %d failures: err = %v, want ErrCircuitOpen", breakerThreshold, err)
+	}
+	if hits.Load() != breakerThreshold {
+		t.Errorf("open breaker let a request through: %d hits", hits.Load())
+	}
+	resp, err := client.Get(up.URL)
+	if err != nil {
+		t.Fatalf("other host blocked: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	b := &circuitBreaker{}
+	for range breakerThreshold {
+		b.record(false)
+	}
+	b.openUntil = time.Now().Add(-time.Second) // cooldown over
+	if !b.allow() {
+		t.Fatal("expected a trial request after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("only one trial request at a time")
+	}
+	b.record(true)
+	if !b.allow() || !b.allow() {
+		t.Fatal("a successful trial should close the breaker")
+	}
+}
+
+func TestNewHTTPClientIsShared(t *testing.T) {
+	a := newHTTPClient(&Config{}, "test-agent")
+	if newHTTPClient(&Config{}, "test-agent") != a {
+		t.Error("same settings should share a client")
+	}
+	if newHTTPClient(&Config{HTTPTimeout: 5}, "test-agent") == a || newHTTPClient(&Config{Offline: true}, "test-agent") == a {
+		t.Error("different settings should not share a client")
+	}
+}
@@ -0,0 +1,110 @@
+package dataflows
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/pkg/persist"
+)
+
+// cacheStore holds encoded cache entries by key.
+type cacheStore interface {
+	read(key string) (data []byte, modTime time.Time, ok bool)
+	write(key string, data []byte) error
+	remove(key string)
+}
+
+// fileStore keeps one file per entry in dir.
+type fileStore struct {
+	dir string
+}
+
+func (s fileStore) read(key string) ([]byte, time.Time, bool) {
+	path := filepath.Join(s.dir, key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	return data, info.ModTime(), true
+}
+
+func (s fileStore) write(key string, data []byte) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dir, key), data, 0600)
+}
+
+func (s fileStore) remove(key string) {
+	os.Remove(filepath.Join(s.dir, key))
+}
+
+type memEntry struct {
+	data    []byte
+	modTime time.Time
+}
+
+// memStore keeps entries in process memory; they are lost on exit.
+type memStore struct {
+	mu      sync.Mutex
+	entries map[string]memEntry
+}
+
+func newMemStore() *memStore {
+	return &memStore{entries: map[string]memEntry{}}
+}
+
+func (s *memStore) read(key string) ([]byte, time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	return e.data, e.modTime, ok
+}
+
+func (s *memStore) write(key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memEntry{data: append([]byte(nil), data...), modTime: time.Now()}
+	return nil
+}
+
+func (s *memStore) remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+// storageStore keeps entries in the cache area of a persist.Storage, under
+// the name of their source.
+type storageStore struct {
+	s      persist.Storage
+	source string
+}
+
+func (s storageStore) read(key string) ([]byte, time.Time, bool) {
+	data, modTime, err := s.s.Get(context.Background(), persist.Cache, s.source+"/"+key)
+	if err != nil {
+		if !errors.Is(err, persist.ErrNotFound) {
+			fmt.Printf("cache %s read err=%v\n", s.source, err)
+		}
+		return nil, time.Time{}, false
+	}
+	return data, modTime, true
+}
+
+func (s storageStore) write(key string, data []byte) error {
+	return s.s.Put(context.Background(), persist.Cache, s.source+"/"+key, data)
+}
+
+func (s storageStore) remove(key string) {
+	_ = s.s.Delete(context.Background(), persist.Cache, s.source+"/"+key)
+}
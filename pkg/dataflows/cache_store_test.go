@@ -0,0 +1,62 @@
+package dataflows
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/persist"
+)
+
+func TestCacheManagerStores(t *testing.T) {
+	for name, store := range map[string]cacheStore{
+		"file":   fileStore{dir: t.TempDir()},
+		"memory": newMemStore(),
+		"storage": storageStore{
+			s:      persist.NewLocal(map[persist.Area]string{"": t.TempDir()}),
+			source: "news",
+		},
+	} {
+		cm := &CacheManager{store: store, ttl: time.Hour, cacheEnabled: true}
+		if err := cm.Set("news", "search", "AAPL", []string{"a", "b"}); err != nil {
+			t.Fatalf("%s: set: %v", name, err)
+		}
+		var got []string
+		if !cm.Get("news", "search", "AAPL", &got) || len(got) != 2 {
+			t.Fatalf("%s: get = %v", name, got)
+		}
+		if cm.Get("news", "search", "MSFT", &got) {
+			t.Errorf("%s: hit for a key never set", name)
+		}
+
+		cm.ttl = 0
+		if cm.Get("news", "search", "AAPL", &got) {
+			t.Errorf("%s: expired entry served", name)
+		}
+		cm.ttl, cm.offline = time.Hour, true
+		if cm.Get("news", "search", "AAPL", &got) {
+			t.Errorf("%s: expired entry was not removed", name)
+		}
+	}
+}
+
+func TestCacheManagerProvenance(t *testing.T) {
+	cm := &CacheManager{store: newMemStore(), ttl: time.Hour, cacheEnabled: true}
+	if p := cm.Provenance("news"); p.Mode != models.ProvenanceLive || p.CacheHits != 0 {
+		t.Errorf("fresh manager = %+v", p)
+	}
+	written := time.Now()
+	if err := cm.Set("news", "search", "AAPL", []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	cm.Get("news", "search", "AAPL", &got)
+	p := cm.Provenance("news")
+	if p.Mode != models.ProvenanceCache || p.CacheHits != 1 || p.FetchedAt.Before(written.Add(-time.Second)) || p.FetchedAt.After(time.Now()) {
+		t.Errorf("after hit = %+v", p)
+	}
+	cm.Get("news", "search", "MSFT", &got)
+	if p := cm.Provenance("news"); p.Mode != models.ProvenanceMixed || p.CacheHits != 1 || p.CacheMisses != 1 {
+		t.Errorf("after miss = %+v", p)
+	}
+}
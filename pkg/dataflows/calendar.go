@@ -0,0 +1,170 @@
+package dataflows
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/go-resty/resty/v2"
+)
+
+// ErrNoCalendarProvider is returned when no Finnhub key is configured
+var ErrNoCalendarProvider = errors.New("earnings dates and listing dates need a Finnhub key: set finnhub_api_key")
+
+// CalendarClient fetches scheduled earnings releases and listing dates from Finnhub
+type CalendarClient struct {
+	client *resty.Client
+	cache  *CacheManager
+	key    string
+}
+
+// NewCalendarClient creates a new corporate calendar client
+func NewCalendarClient(config *Config) *CalendarClient {
+	return &CalendarClient{
+		client: newHTTPClient(config, "CortexGo/1.0"),
+		cache:  newCacheManager(config, "calendar", 12*time.Hour),
+		key:    strings.TrimSpace(config.FinnhubAPIKey),
+	}
+}
+
+// finnhubEarning is one row of Finnhub's earnings calendar
+type finnhubEarning struct {
+	Date            string   `json:"date"`
+	Hour            string   `json:"hour"`
+	Quarter         int      `json:"quarter"`
+	Year            int      `json:"year"`
+	Symbol          string   `json:"symbol"`
+	EPSEstimate     *float64 `json:"epsEstimate"`
+	RevenueEstimate *float64 `json:"revenueEstimate"`
+}
+
+// GetEarningsDates returns the earnings releases scheduled for symbol between
+// from and to (YYYY-MM-DD, both inclusive), earliest first.
+func (cc *CalendarClient) GetEarningsDates(symbol, from, to string) ([]models.Catalyst, error) {
+	ticker, err := cc.ticker(symbol)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{"symbol": ticker, "from": from, "to": to}
+	var rows []finnhubEarning
+	if !cc.cache.Get("finnhub", "earnings_calendar", params, &rows) {
+		if cc.cache.offline {
+			return nil, offlineMiss("calendar", ticker)
+		}
+		var resp struct {
+			EarningsCalendar []finnhubEarning `json:"earningsCalendar"`
+		}
+		if err := cc.fetch("/calendar/earnings", params, "earnings calendar", &resp); err != nil {
+			return nil, err
+		}
+		rows = resp.EarningsCalendar
+		cc.cache.Set("finnhub", "earnings_calendar", params, rows)
+	}
+
+	var out []models.Catalyst
+	for _, r := range rows {
+		if r.Date < from || r.Date > to {
+			continue
+		}
+		title := ticker + " earnings"
+		if r.Quarter > 0 && r.Year > 0 {
+			title = fmt.Sprintf("%s Q%d %d earnings", ticker, r.Quarter, r.Year)
+		}
+		var details []string
+		if r.EPSEstimate != nil {
+			details = append(details, fmt.Sprintf("EPS estimate %.2f", *r.EPSEstimate))
+		}
+		if r.RevenueEstimate != nil {
+			details = append(details, "revenue estimate "+formatLargeNumber(*r.RevenueEstimate))
+		}
+		out = append(out, models.Catalyst{
+			Date:   r.Date,
+			Time:   strings.ToLower(r.Hour),
+			Kind:   models.CatalystEarnings,
+			Symbol: ticker + ".US",
+			Title:  title,
+			Detail: strings.Join(details, ", "),
+			Source: "finnhub",
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out, nil
+}
+
+// GetIPODate returns the listing date Finnhub reports for symbol
+// (YYYY-MM-DD), or "" when it has none.
+func (cc *CalendarClient) GetIPODate(symbol string) (string, error) {
+	ticker, err := cc.ticker(symbol)
+	if err != nil {
+		return "", err
+	}
+	params := map[string]string{"symbol": ticker}
+	var ipo string
+	if !cc.cache.Get("finnhub", "ipo_date", params, &ipo) {
+		if cc.cache.offline {
+			return "", offlineMiss("calendar", ticker)
+		}
+		var resp struct {
+			IPO string `json:"ipo"`
+		}
+		if err := cc.fetch("/stock/profile2", params, "company profile", &resp); err != nil {
+			return "", err
+		}
+		ipo = resp.IPO
+		cc.cache.Set("finnhub", "ipo_date", params, ipo)
+	}
+	return ipo, nil
+}
+
+// ticker checks that symbol is a US listing, the only market Finnhub's
+// calendars cover on the free plan, and returns the bare ticker.
+func (cc *CalendarClient) ticker(symbol string) (string, error) {
+	symbol = NormalizeSymbol(symbol)
+	if err := ValidateSymbol(symbol); err != nil {
+		return "", err
+	}
+	ticker, market, found := strings.Cut(symbol, ".")
+	if found && market != "US" {
+		return "", fmt.Errorf("earnings and listing dates are only available for US listings, got %s", symbol)
+	}
+	if cc.key == "" {
+		return "", ErrNoCalendarProvider
+	}
+	return ticker, nil
+}
+
+func (cc *CalendarClient) fetch(path string, params map[string]string, what string, out any) error {
+	return WithRetry(DefaultRetryConfig(), func() error {
+		r, err := cc.client.R().SetHeader(finnhubTokenHeader, cc.key).SetQueryParams(params).Get(finnhubBaseURL + path)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s: %w", what, err)
+		}
+		switch code := r.StatusCode(); {
+		case code == http.StatusUnauthorized || code == http.StatusForbidden || code == http.StatusPaymentRequired:
+			return &noRetryError{fmt.Errorf("finnhub rejected the request (HTTP %d): check the API key", code)}
+		case code != http.StatusOK:
+			return fmt.Errorf("HTTP error %d when fetching %s", code, what)
+		}
+		if err := json.Unmarshal(r.Body(), out); err != nil {
+			return fmt.Errorf("failed to parse Finnhub %s: %w", what, err)
+		}
+		return nil
+	})
+}
+
+// formatLargeNumber abbreviates revenue figures, e.g. 89.3B
+func formatLargeNumber(v float64) string {
+	switch abs := math.Abs(v); {
+	case abs >= 1e9:
+		return fmt.Sprintf("%.1fB", v/1e9)
+	case abs >= 1e6:
+		return fmt.Sprintf("%.1fM", v/1e6)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
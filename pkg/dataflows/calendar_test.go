@@ -0,0 +1,68 @@
+package dataflows
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+func TestCalendarClient(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		q := r.URL.Query()
+		if q.Get("symbol") != "RDDT" || r.Header.Get("X-Finnhub-Token") != "key" || q.Get("token") != "" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/calendar/earnings":
+			w.Write([]byte(`{"earningsCalendar":[
+{"date":"2024-08-06","hour":"AMC","quarter":2,"year":2024,"symbol":"RDDT","epsEstimate":-0.03,"revenueEstimate":253400000},
+{"date":"2024-05-07","hour":"amc","quarter":1,"year":2024,"symbol":"RDDT","epsEstimate":null,"revenueEstimate":null}]}`))
+		case "/stock/profile2":
+			w.Write([]byte(`{"name":"Reddit Inc","ipo":"2024-03-21"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	defer func(old string) { finnhubBaseURL = old }(finnhubBaseURL)
+	finnhubBaseURL = srv.URL
+
+	cfg := &Config{DataCacheDir: t.TempDir(), CacheEnabled: true, FinnhubAPIKey: "key"}
+	cc := NewCalendarClient(cfg)
+	got, err := cc.GetEarningsDates("rddt.us", "2024-05-01", "2024-07-30")
+	if err != nil {
+		t.Fatalf("GetEarningsDates: %v", err)
+	}
+	want := models.Catalyst{Date: "2024-05-07", Time: "amc", Kind: models.CatalystEarnings, Symbol: "RDDT.US", Title: "RDDT Q1 2024 earnings", Source: "finnhub"}
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("earnings = %+v", got)
+	}
+	got, _ = cc.GetEarningsDates("RDDT", "2024-08-01", "2024-08-31")
+	if len(got) != 1 || got[0].Detail != "EPS estimate -0.03, revenue estimate 253.4M" {
+		t.Fatalf("earnings detail = %+v", got)
+	}
+	ipo, err := cc.GetIPODate("RDDT")
+	if err != nil || ipo != "2024-03-21" {
+		t.Fatalf("GetIPODate = %q, %v", ipo, err)
+	}
+	if _, err := NewCalendarClient(cfg).GetIPODate("RDDT"); err != nil || calls != 3 {
+		t.Errorf("cached lookup: calls = %d, err = %v", calls, err)
+	}
+
+	if _, err := NewCalendarClient(&Config{}).GetIPODate("RDDT"); !errors.Is(err, ErrNoCalendarProvider) {
+		t.Errorf("no key: %v", err)
+	}
+	if _, err := cc.GetEarningsDates("700.HK", "2024-05-01", "2024-07-30"); err == nil {
+		t.Error("want error for a non-US listing")
+	}
+	cfg.Offline = true
+	if _, err := NewCalendarClient(cfg).GetEarningsDates("NVDA", "2024-05-01", "2024-07-30"); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline miss: %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package dataflows
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/dyike/CortexGo/models"
+	"github.com/go-resty/resty/v2"
+)
+
+// constituentsBaseURL serves the index member lists; a variable so tests can
+// point it at a local server
+var constituentsBaseURL = "https://en.wikipedia.org/wiki/"
+
+// indexSource describes where an index's members are listed
+type indexSource struct {
+	ID     string
+	Name   string
+	Page   string
+	Market string // suffix appended to member tickers
+}
+
+var indexSources = []indexSource{
+	{ID: "SP500", Name: "S&P 500", Page: "List_of_S%26P_500_companies", Market: "US"},
+	{ID: "NDX", Name: "NASDAQ-100", Page: "Nasdaq-100", Market: "US"},
+	{ID: "HSI", Name: "Hang Seng Index", Page: "Hang_Seng_Index", Market: "HK"},
+}
+
+// indexAliases maps the names users type to index IDs
+var indexAliases = map[string]string{
+	"SP500": "SP500", "S&P500": "SP500", "SPX": "SP500", "GSPC": "SP500",
+	"NDX": "NDX", "NASDAQ100": "NDX", "NAS100": "NDX",
+	"HSI": "HSI", "HANGSENG": "HSI",
+}
+
+// IndexIDs lists the supported indices
+func IndexIDs() []string {
+	ids := make([]string, len(indexSources))
+	for i, s := range indexSources {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+// ResolveIndex maps an index name such as "S&P 500", "nasdaq-100" or "hsi"
+// to its ID
+func ResolveIndex(name string) (string, error) {
+	key := strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.TrimSpace(name)))
+	if id, ok := indexAliases[key]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("unknown index %q: want one of %s", name, strings.Join(IndexIDs(), ", "))
+}
+
+// ConstituentsClient fetches index member lists; lists change a few times a
+// year, so they are cached for a day
+type ConstituentsClient struct {
+	client *resty.Client
+	cache  *CacheManager
+}
+
+// NewConstituentsClient creates a new constituents client
+func NewConstituentsClient(config *Config) *ConstituentsClient {
+	return &ConstituentsClient{
+		client: newHTTPClient(config, "CortexGo/1.0 (index constituents)"),
+		cache:  newCacheManager(config, "constituents", 24*time.Hour),
+	}
+}
+
+// GetConstituents returns the current members of an index
+func (cc *ConstituentsClient) GetConstituents(index string) (*models.IndexConstituents, error) {
+	id, err := ResolveIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	var src indexSource
+	for _, s := range indexSources {
+		if s.ID == id {
+			src = s
+		}
+	}
+
+	var cached models.IndexConstituents
+	if cc.cache.Get("wikipedia", "constituents", id, &cached) {
+		return &cached, nil
+	}
+	if cc.cache.offline {
+		return nil, offlineMiss("constituents", id)
+	}
+
+	url := constituentsBaseURL + src.Page
+	var body []byte
+	err = WithRetry(DefaultRetryConfig(), func() error {
+		resp, err := cc.client.R().Get(url)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s constituents: %w", src.Name, err)
+		}
+		switch code := resp.StatusCode(); {
+		case code == http.StatusNotFound:
+			return &noRetryError{fmt.Errorf("%s constituents page not found", src.Name)}
+		case code != http.StatusOK:
+			return fmt.Errorf("HTTP error %d when fetching %s constituents", code, src.Name)
+		}
+		body = resp.Body()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	members, err := ParseConstituents(body, src.Market)
+	if err != nil {
+		return nil, fmt.Errorf("%s constituents: %w", src.Name, err)
+	}
+	out := &models.IndexConstituents{Index: id, Name: src.Name, Source: url, FetchedAt: time.Now(), Members: members}
+	cc.cache.Set("wikipedia", "constituents", id, out)
+	return out, nil
+}
+
+var (
+	footnoteRe = regexp.MustCompile(`\[[^\]]*\]`)
+	usTickerRe = regexp.MustCompile(`^[A-Z][A-Z0-9]*([.\-][A-Z])?$`)
+	digitsRe   = regexp.MustCompile(`\d+`)
+)
+
+// ParseConstituents reads the members from the first table of a page that
+// has a ticker column (the one with id "constituents" when present). Columns
+// are found by their headers, so reordered or added columns do not break it.
+func ParseConstituents(page []byte, market string) ([]models.IndexMember, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return nil, err
+	}
+	tables := doc.Find("table#constituents")
+	if tables.Length() == 0 {
+		tables = doc.Find("table.wikitable")
+	}
+	var members []models.IndexMember
+	tables.EachWithBreak(func(_ int, table *goquery.Selection) bool {
+		symbolCol, nameCol, sectorCol := -1, -1, -1
+		table.Find("tr").First().Find("th").Each(func(i int, th *goquery.Selection) {
+			header := strings.ToLower(cellText(th))
+			switch {
+			case symbolCol < 0 && (strings.Contains(header, "symbol") || strings.Contains(header, "ticker") || strings.Contains(header, "code")):
+				symbolCol = i
+			case nameCol < 0 && (strings.Contains(header, "security") || strings.Contains(header, "company") || strings.Contains(header, "name")):
+				nameCol = i
+			case sectorCol < 0 && (strings.Contains(header, "sector") || strings.Contains(header, "industry") || strings.Contains(header, "sub-index")):
+				sectorCol = i
+			}
+		})
+		if symbolCol < 0 {
+			return true
+		}
+		seen := map[string]bool{}
+		table.Find("tr").Each(func(_ int, row *goquery.Selection) {
+			cells := row.Children()
+			if cells.Length() <= symbolCol || row.Find("td").Length() == 0 {
+				return
+			}
+			symbol := memberSymbol(cellText(cells.Eq(symbolCol)), market)
+			if symbol == "" || seen[symbol] {
+				return
+			}
+			seen[symbol] = true
+			m := models.IndexMember{Symbol: symbol}
+			if nameCol >= 0 && nameCol < cells.Length() {
+				m.Name = cellText(cells.Eq(nameCol))
+			}
+			if sectorCol >= 0 && sectorCol < cells.Length() {
+				m.Sector = cellText(cells.Eq(sectorCol))
+			}
+			members = append(members, m)
+		})
+		return len(members) == 0
+	})
+	if len(members) == 0 {
+		return nil, fmt.Errorf("no constituents table found")
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i].Symbol < members[j].Symbol })
+	return members, nil
+}
+
+func cellText(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(footnoteRe.ReplaceAllString(s.Text(), "")), " ")
+}
+
+// memberSymbol converts a listed ticker to Longport format: class shares keep
+// their dot (BRK.B.US) and Hong Kong codes drop leading zeros (0005 -> 5.HK).
+func memberSymbol(ticker, market string) string {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	if market == "HK" {
+		code, err := strconv.Atoi(digitsRe.FindString(ticker))
+		if err != nil || code <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%d.HK", code)
+	}
+	if !usTickerRe.MatchString(ticker) {
+		return ""
+	}
+	return strings.ReplaceAll(ticker, "-", ".") + "." + market
+}
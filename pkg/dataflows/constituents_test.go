@@ -0,0 +1,82 @@
+package dataflows
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const sp500Page = `<html><body>
+<table class="wikitable"><tr><th>Notes</th></tr><tr><td>unrelated</td></tr></table>
+<table class="wikitable sortable" id="constituents">
+<tr><th>Symbol</th><th>Security</th><th>GICS Sector</th><th>Headquarters</th></tr>
+<tr><td><a href="#">MMM</a></td><td>3M</td><td>Industrials</td><td>Saint Paul</td></tr>
+<tr><td><a href="#">BRK.B</a></td><td>Berkshire Hathaway<sup>[4]</sup></td><td>Financials</td><td>Omaha</td></tr>
+<tr><td>AAPL</td><td>Apple Inc.</td><td>Information Technology</td><td>Cupertino</td></tr>
+<tr><td>AAPL</td><td>Apple Inc.</td><td>Information Technology</td><td>Cupertino</td></tr>
+</table></body></html>`
+
+const hsiPage = `<html><body>
+<table class="wikitable">
+<tr><th>No.</th><th>Ticker</th><th>Name</th><th>Sub-index</th></tr>
+<tr><td>1</td><td>SEHK: 5</td><td>HSBC Holdings</td><td>Finance</td></tr>
+<tr><td>2</td><td>SEHK: 0700</td><td>Tencent</td><td>Commerce &amp; Industry</td></tr>
+</table></body></html>`
+
+func TestParseConstituents(t *testing.T) {
+	members, err := ParseConstituents([]byte(sp500Page), "US")
+	if err != nil {
+		t.Fatalf("ParseConstituents: %v", err)
+	}
+	if len(members) != 3 || members[0].Symbol != "AAPL.US" || members[1].Symbol != "BRK.B.US" || members[1].Name != "Berkshire Hathaway" || members[2].Sector != "Industrials" {
+		t.Errorf("members = %+v", members)
+	}
+
+	members, err = ParseConstituents([]byte(hsiPage), "HK")
+	if err != nil {
+		t.Fatalf("ParseConstituents: %v", err)
+	}
+	if len(members) != 2 || members[0].Symbol != "5.HK" || members[1].Symbol != "700.HK" || members[1].Sector != "Commerce & Industry" {
+		t.Errorf("members = %+v", members)
+	}
+
+	if _, err := ParseConstituents([]byte(`<table class="wikitable"><tr><th>Notes</th></tr></table>`), "US"); err == nil {
+		t.Error("want error without a ticker column")
+	}
+}
+
+func TestGetConstituentsCachesAndResolves(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/List_of_S&P_500_companies" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(sp500Page))
+	}))
+	defer srv.Close()
+	defer func(old string) { constituentsBaseURL = old }(constituentsBaseURL)
+	constituentsBaseURL = srv.URL + "/"
+
+	cfg := &Config{DataCacheDir: t.TempDir(), CacheEnabled: true}
+	c, err := NewConstituentsClient(cfg).GetConstituents("S&P 500")
+	if err != nil {
+		t.Fatalf("GetConstituents: %v", err)
+	}
+	if c.Index != "SP500" || len(c.Members) != 3 {
+		t.Errorf("constituents = %+v", c)
+	}
+	if _, err := NewConstituentsClient(cfg).GetConstituents("sp500"); err != nil || calls != 1 {
+		t.Errorf("cached lookup: calls = %d, err = %v", calls, err)
+	}
+	if _, err := NewConstituentsClient(cfg).GetConstituents("DAX"); err == nil {
+		t.Error("want error for an unknown index")
+	}
+
+	cfg.Offline = true
+	if _, err := NewConstituentsClient(cfg).GetConstituents("HSI"); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline miss: %v", err)
+	}
+}
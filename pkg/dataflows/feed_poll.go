@@ -0,0 +1,80 @@
+package dataflows
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// FeedCursor remembers what a feed has already delivered: the GUIDs of its
+// items and a high-water mark, the newest publish time delivered so far.
+type FeedCursor interface {
+	FeedHighWater(ctx context.Context, feed string) (time.Time, error)
+	SeenFeedItems(ctx context.Context, feed string, guids []string) (map[string]bool, error)
+	MarkFeedItems(ctx context.Context, feed string, guids []string, highWater time.Time) error
+}
+
+// feedPollSlack lets items published up to this long before the high-water
+// mark through when their GUID is new: Google News indexes some stories late
+// and occasionally reissues GUIDs, so the mark alone would drop them.
+const feedPollSlack = 6 * time.Hour
+
+// PollGoogleNewsRSS fetches a Google News RSS feed and returns only the items
+// not delivered by an earlier poll of the same feed, then records them in
+// cursor. It always goes to the network: a cached page would hide new items.
+func (gnc *GoogleNewsClient) PollGoogleNewsRSS(ctx context.Context, params EnhancedGoogleNewsParams, cursor FeedCursor) ([]*NewsArticle, error) {
+	if gnc.cache.offline {
+		return nil, offlineMiss("google news rss poll", params.Query)
+	}
+	feed := gnc.buildGoogleNewsRSSURL(params)
+	articles, err := gnc.fetchRSS(feed, params.Query, params.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	ScoreArticles(articles)
+	return NewFeedItems(ctx, cursor, feed, articles)
+}
+
+// NewFeedItems filters articles down to those cursor has not seen for feed
+// and marks all of them as delivered. Items are keyed by their RSS GUID,
+// falling back to the link.
+func NewFeedItems(ctx context.Context, cursor FeedCursor, feed string, articles []*NewsArticle) ([]*NewsArticle, error) {
+	highWater, err := cursor.FeedHighWater(ctx, feed)
+	if err != nil {
+		return nil, err
+	}
+	guids := make([]string, 0, len(articles))
+	for _, a := range articles {
+		guids = append(guids, feedItemID(a))
+	}
+	seen, err := cursor.SeenFeedItems(ctx, feed, guids)
+	if err != nil {
+		return nil, err
+	}
+
+	var fresh []*NewsArticle
+	newest := highWater
+	for i, a := range articles {
+		if a.PublishedAt.After(newest) {
+			newest = a.PublishedAt
+		}
+		if seen[guids[i]] {
+			continue
+		}
+		if !highWater.IsZero() && a.PublishedAt.Before(highWater.Add(-feedPollSlack)) {
+			continue
+		}
+		fresh = append(fresh, a)
+	}
+	if err := cursor.MarkFeedItems(ctx, feed, guids, newest); err != nil {
+		return nil, fmt.Errorf("record polled items: %w", err)
+	}
+	return fresh, nil
+}
+
+func feedItemID(a *NewsArticle) string {
+	if guid := a.Metadata["guid"]; guid != "" {
+		return guid
+	}
+	return a.URL
+}
@@ -0,0 +1,69 @@
+package dataflows
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type memoryCursor struct {
+	seen      map[string]bool
+	highWater time.Time
+}
+
+func (c *memoryCursor) FeedHighWater(context.Context, string) (time.Time, error) {
+	return c.highWater, nil
+}
+
+func (c *memoryCursor) SeenFeedItems(_ context.Context, _ string, guids []string) (map[string]bool, error) {
+	out := map[string]bool{}
+	for _, g := range guids {
+		if c.seen[g] {
+			out[g] = true
+		}
+	}
+	return out, nil
+}
+
+func (c *memoryCursor) MarkFeedItems(_ context.Context, _ string, guids []string, highWater time.Time) error {
+	for _, g := range guids {
+		c.seen[g] = true
+	}
+	if highWater.After(c.highWater) {
+		c.highWater = highWater
+	}
+	return nil
+}
+
+func TestNewFeedItemsOnlyReturnsUnseenItems(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	item := func(guid string, age time.Duration) *NewsArticle {
+		return &NewsArticle{Title: guid, PublishedAt: now.Add(-age), Metadata: map[string]string{"guid": guid}}
+	}
+	cursor := &memoryCursor{seen: map[string]bool{}}
+
+	first, err := NewFeedItems(ctx, cursor, "feed", []*NewsArticle{item("a", 2*time.Hour), item("b", time.Hour)})
+	if err != nil || len(first) != 2 {
+		t.Fatalf("first poll: got %d items (%v)", len(first), err)
+	}
+	if !cursor.highWater.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("expected the high-water mark at the newest item, got %v", cursor.highWater)
+	}
+
+	// a repeat, a new story, a late-indexed story within the slack and a new
+	// GUID for a story far older than the mark
+	second, err := NewFeedItems(ctx, cursor, "feed", []*NewsArticle{
+		item("b", time.Hour), item("c", 0), item("d", 3*time.Hour), item("e", 48*time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, a := range second {
+		got = append(got, a.Title)
+	}
+	if len(got) != 2 || got[0] != "c" || got[1] != "d" {
+		t.Fatalf("second poll: got %v, want [c d]", got)
+	}
+}
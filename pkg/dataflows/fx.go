@@ -0,0 +1,143 @@
+package dataflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// fxBaseURL serves the ECB daily reference rates through the Frankfurter
+// API; a variable so tests can point it at a local server
+var fxBaseURL = "https://api.frankfurter.dev/v1/"
+
+// Quote currencies of the markets CortexGo trades.
+const (
+	CurrencyUSD = "USD"
+	CurrencyHKD = "HKD"
+	CurrencyCNY = "CNY"
+	CurrencySGD = "SGD"
+)
+
+// CurrencyOf returns the currency a symbol is quoted in, from its market
+// suffix; bare tickers are taken as US listings
+func CurrencyOf(symbol string) string {
+	symbol = NormalizeSymbol(symbol)
+	market := ""
+	if i := strings.LastIndex(symbol, "."); i >= 0 {
+		market = symbol[i+1:]
+	}
+	switch market {
+	case "HK":
+		return CurrencyHKD
+	case "SH", "SZ":
+		return CurrencyCNY
+	case "SG":
+		return CurrencySGD
+	}
+	return CurrencyUSD
+}
+
+// FXClient fetches daily reference exchange rates (the ECB fixing, published
+// on TARGET business days around 16:00 CET). No key is needed
+type FXClient struct {
+	client *resty.Client
+	cache  *CacheManager
+}
+
+// NewFXClient creates a new exchange rate client
+func NewFXClient(config *Config) *FXClient {
+	return &FXClient{
+		client: newHTTPClient(config, "CortexGo/1.0"),
+		cache:  newCacheManager(config, "fx", 12*time.Hour),
+	}
+}
+
+type fxSeries struct {
+	Rates map[string]map[string]float64 `json:"rates"`
+}
+
+// GetRates returns the price of one unit of from in to for each business day
+// between start and end (YYYY-MM-DD), keyed by date. The same currency yields
+// an empty map and no request
+func (fc *FXClient) GetRates(from, to, start, end string) (map[string]float64, error) {
+	from, to = strings.ToUpper(strings.TrimSpace(from)), strings.ToUpper(strings.TrimSpace(to))
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("currency is required")
+	}
+	if from == to {
+		return map[string]float64{}, nil
+	}
+	for _, d := range []string{start, end} {
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, fmt.Errorf("invalid date %q: want YYYY-MM-DD", d)
+		}
+	}
+	if start > end {
+		start, end = end, start
+	}
+	params := map[string]string{"from": from, "to": to, "start": start, "end": end}
+	var rates map[string]float64
+	if fc.cache.Get("fx", "rates", params, &rates) {
+		return rates, nil
+	}
+	if fc.cache.offline {
+		return nil, offlineMiss("fx", fmt.Sprintf("%s%s %s..%s", from, to, start, end))
+	}
+
+	var series fxSeries
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		resp, err := fc.client.R().
+			SetQueryParams(map[string]string{"base": from, "symbols": to}).
+			Get(fxBaseURL + start + ".." + end)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s/%s rates: %w", from, to, err)
+		}
+		switch code := resp.StatusCode(); {
+		case code == http.StatusNotFound || code == http.StatusUnprocessableEntity:
+			return &noRetryError{fmt.Errorf("no %s/%s rates: HTTP %d", from, to, code)}
+		case code != http.StatusOK:
+			return fmt.Errorf("HTTP error %d when fetching %s/%s rates", code, from, to)
+		}
+		if err := json.Unmarshal(resp.Body(), &series); err != nil {
+			return &noRetryError{fmt.Errorf("failed to parse %s/%s rates: %w", from, to, err)}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	rates = make(map[string]float64, len(series.Rates))
+	for date, r := range series.Rates {
+		if v := r[to]; v > 0 {
+			rates[date] = v
+		}
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no %s/%s rates between %s and %s", from, to, start, end)
+	}
+	fc.cache.Set("fx", "rates", params, rates)
+	return rates, nil
+}
+
+// RateOn returns the rate for date, or for the closest earlier date when the
+// fixing was not published that day (weekends, holidays); false when rates
+// holds nothing on or before date
+func RateOn(rates map[string]float64, date string) (float64, bool) {
+	if v, ok := rates[date]; ok {
+		return v, true
+	}
+	best := ""
+	for d := range rates {
+		if d <= date && d > best {
+			best = d
+		}
+	}
+	if best == "" {
+		return 0, false
+	}
+	return rates[best], true
+}
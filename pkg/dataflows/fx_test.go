@@ -0,0 +1,55 @@
+package dataflows
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCurrencyOf(t *testing.T) {
+	for symbol, want := range map[string]string{
+		"AAPL.US": CurrencyUSD, "700.HK": CurrencyHKD, "600519.SH": CurrencyCNY,
+		"000001.sz": CurrencyCNY, "D05.SG": CurrencySGD, "AAPL": CurrencyUSD,
+	} {
+		if got := CurrencyOf(symbol); got != want {
+			t.Errorf("CurrencyOf(%s) = %s, want %s", symbol, got, want)
+		}
+	}
+}
+
+func TestGetRates(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/2025-01-02..2025-01-06" || r.URL.Query().Get("base") != "HKD" || r.URL.Query().Get("symbols") != "USD" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"amount":1.0,"base":"HKD","start_date":"2025-01-02","end_date":"2025-01-06","rates":{"2025-01-02":{"USD":0.1287},"2025-01-03":{"USD":0.1286},"2025-01-06":{"USD":0.1285}}}`))
+	}))
+	defer srv.Close()
+	defer func(old string) { fxBaseURL = old }(fxBaseURL)
+	fxBaseURL = srv.URL + "/"
+
+	fc := NewFXClient(&Config{DataCacheDir: t.TempDir(), CacheEnabled: true})
+	rates, err := fc.GetRates("hkd", "USD", "2025-01-02", "2025-01-06")
+	if err != nil {
+		t.Fatalf("GetRates: %v", err)
+	}
+	if len(rates) != 3 || rates["2025-01-03"] != 0.1286 {
+		t.Errorf("rates = %v", rates)
+	}
+	if _, err := fc.GetRates("HKD", "USD", "2025-01-02", "2025-01-06"); err != nil || calls != 1 {
+		t.Errorf("second call: err %v, %d requests; want a cache hit", err, calls)
+	}
+	if same, err := fc.GetRates("USD", "USD", "2025-01-02", "2025-01-06"); err != nil || len(same) != 0 || calls != 1 {
+		t.Errorf("same currency: %v, %v", same, err)
+	}
+	// the weekend takes Friday's fixing
+	if v, ok := RateOn(rates, "2025-01-05"); !ok || v != 0.1286 {
+		t.Errorf("RateOn(weekend) = %v, %v", v, ok)
+	}
+	if _, ok := RateOn(rates, "2025-01-01"); ok {
+		t.Error("RateOn before the first fixing should fail")
+	}
+}
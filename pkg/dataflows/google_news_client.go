@@ -3,7 +3,9 @@ package dataflows
 import (
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -43,22 +45,25 @@ type Source struct {
 
 // GoogleNewsClient handles Google News operations
 type GoogleNewsClient struct {
-	client *resty.Client
-	cache  *CacheManager
+	client     *resty.Client
+	cache      *CacheManager
+	validators *CacheManager // ETag/Last-Modified of fetched article pages
+	crawl      crawlPolicy
+	sim        *Simulator // non-nil in simulated data mode
 }
 
 // NewGoogleNewsClient creates a new Google News client
 func NewGoogleNewsClient(config *Config) *GoogleNewsClient {
-	cacheDir := filepath.Join(config.DataCacheDir, "google_news")
-	cache := NewCacheManager(cacheDir, 30*time.Minute, config.CacheEnabled) // 30 minute cache for news
+	cache := newCacheManager(config, "google_news", 30*time.Minute) // 30 minute cache for news
 
-	client := resty.New()
-	client.SetTimeout(30 * time.Second)
-	client.SetHeader("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
+	client := newHTTPClient(config, "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36")
 
 	return &GoogleNewsClient{
-		client: client,
-		cache:  cache,
+		client:     client,
+		cache:      cache,
+		validators: newCacheManager(config, "article_validators", validatorTTL),
+		crawl:      newCrawlPolicy(config),
+		sim:        NewSimulator(config),
 	}
 }
 
@@ -94,13 +99,20 @@ func (gnc *GoogleNewsClient) GetGoogleNews(params EnhancedGoogleNewsParams, conf
 	if params.SortBy == "" {
 		params.SortBy = "date"
 	}
+	if gnc.sim != nil {
+		return gnc.sim.Articles(params.Query, params.MaxResults), nil
+	}
 
 	// Check cache first
 	cacheKey := fmt.Sprintf("%s_%s_%s_%s_%d", params.Query, params.Language, params.Country, params.SortBy, params.MaxResults)
 	var cached []*NewsArticle
 	if gnc.cache.Get("enhanced_search", "query", cacheKey, &cached) {
+		ScoreArticles(cached)
 		return cached, nil
 	}
+	if gnc.cache.offline {
+		return nil, offlineMiss("google news", params.Query)
+	}
 
 	// Try multiple search strategies
 	var allResults []*NewsArticle
@@ -125,6 +137,8 @@ func (gnc *GoogleNewsClient) GetGoogleNews(params EnhancedGoogleNewsParams, conf
 		allResults = allResults[:params.MaxResults]
 	}
 
+	ScoreArticles(allResults)
+
 	// Cache the result
 	gnc.cache.Set("enhanced_search", "query", cacheKey, allResults)
 
@@ -519,6 +533,9 @@ func (gnc *GoogleNewsClient) GetFinanceNews(maxResults int, config *Config) ([]*
 		}
 
 		articles, err := gnc.GetGoogleNews(params, config)
+		if errors.Is(err, ErrOffline) {
+			return nil, err
+		}
 		if err != nil {
 			continue // Skip failed queries
 		}
@@ -552,6 +569,9 @@ func (gnc *GoogleNewsClient) GetStockNews(symbol string, maxResults int, config
 	}
 
 	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if gnc.sim != nil {
+		return gnc.sim.Articles(symbol, maxResults), nil
+	}
 
 	// Try multiple search queries for the stock
 	queries := []string{
@@ -578,6 +598,9 @@ func (gnc *GoogleNewsClient) GetStockNews(symbol string, maxResults int, config
 		}
 
 		articles, err := gnc.GetGoogleNews(params, config)
+		if errors.Is(err, ErrOffline) {
+			return nil, err
+		}
 		if err != nil {
 			continue
 		}
@@ -634,15 +657,27 @@ func parseNumber(s string) int {
 
 // GetArticleContent 获取文章的具体内容
 func (gnc *GoogleNewsClient) GetArticleContent(articleURL string) (string, error) {
+	page, err := gnc.FetchArticle(articleURL)
+	return page.Text, err
+}
+
+// FetchArticle 获取文章正文；原链接失效或正文过短（付费墙、错误页）时改读Wayback Machine快照，并在结果中记录快照
+func (gnc *GoogleNewsClient) FetchArticle(articleURL string) (ArticleContent, error) {
 	if strings.TrimSpace(articleURL) == "" {
-		return "", fmt.Errorf("article URL cannot be empty")
+		return ArticleContent{}, fmt.Errorf("article URL cannot be empty")
+	}
+	if gnc.sim != nil {
+		return gnc.sim.ArticlePage(articleURL)
 	}
 
 	// 检查缓存
-	var cached string
-	if gnc.cache.Get("article_content", "url", articleURL, &cached) {
+	var cached ArticleContent
+	if gnc.cache.Get("article_page", "url", articleURL, &cached) {
 		return cached, nil
 	}
+	if gnc.cache.offline {
+		return ArticleContent{}, offlineMiss("article content", articleURL)
+	}
 
 	// 如果是Google News链接，尝试获取实际目标URL
 	actualURL := articleURL
@@ -653,13 +688,54 @@ func (gnc *GoogleNewsClient) GetArticleContent(articleURL string) (string, error
 		}
 	}
 
+	content, err := gnc.fetchArticlePage(actualURL)
+	page := ArticleContent{Text: content}
+	// robots.txt禁止抓取的页面不绕道存档
+	if err != nil && !errors.Is(err, ErrDisallowed) || err == nil && len(content) < minArticleChars {
+		archived, archiveErr := gnc.fetchWaybackArticle(actualURL)
+		switch {
+		case archiveErr == nil && len(archived.Text) > len(content):
+			fmt.Printf("  使用Wayback Machine快照: %s\n", archived.Archive.URL)
+			page, err = archived, nil
+		case err != nil && archiveErr != nil:
+			err = fmt.Errorf("%w (archive fallback: %v)", err, archiveErr)
+		}
+	}
+	if err != nil {
+		return ArticleContent{}, err
+	}
+
+	// 缓存结果
+	gnc.cache.Set("article_page", "url", articleURL, page)
+
+	return page, nil
+}
+
+// fetchArticlePage 抓取页面并提取正文
+func (gnc *GoogleNewsClient) fetchArticlePage(pageURL string) (string, error) {
+	// 条件请求：页面未变化时服务器返回304，沿用上次提取的正文
+	var stored pageValidators
+	gnc.validators.Get("article", "url", pageURL, &stored)
+
 	var content string
 	err := WithRetry(DefaultRetryConfig(), func() error {
-		resp, err := gnc.client.R().Get(actualURL)
+		// 遵守robots.txt与站点抓取间隔，重试同样计入间隔
+		if err := gnc.crawl.admit(gnc.client, pageURL); err != nil {
+			return &noRetryError{err}
+		}
+		resp, err := stored.apply(gnc.client.R()).Get(pageURL)
 		if err != nil {
 			return fmt.Errorf("failed to fetch article: %w", err)
 		}
 
+		if resp.StatusCode() == http.StatusNotModified && stored.Content != "" {
+			content = stored.Content
+			return nil
+		}
+		// 404、403等失效链接重试无用，直接交给存档回退
+		if code := resp.StatusCode(); code >= 400 && code < 500 && code != http.StatusTooManyRequests {
+			return &noRetryError{fmt.Errorf("HTTP error %d when fetching article", code)}
+		}
 		if resp.StatusCode() != 200 {
 			return fmt.Errorf("HTTP error %d when fetching article", resp.StatusCode())
 		}
@@ -671,17 +747,13 @@ func (gnc *GoogleNewsClient) GetArticleContent(articleURL string) (string, error
 		}
 
 		content = gnc.extractArticleContent(doc)
+		stored = pageValidators{ETag: resp.Header().Get("ETag"), LastModified: resp.Header().Get("Last-Modified"), Content: content}
+		if stored.ETag != "" || stored.LastModified != "" {
+			gnc.validators.Set("article", "url", pageURL, stored)
+		}
 		return nil
 	})
-
-	if err != nil {
-		return "", err
-	}
-
-	// 缓存结果
-	gnc.cache.Set("article_content", "url", articleURL, content)
-
-	return content, nil
+	return content, err
 }
 
 // followRedirect 跟随Google News重定向获取实际URL
@@ -844,11 +916,16 @@ func (gnc *GoogleNewsClient) GetNewsWithContent(params EnhancedGoogleNewsParams,
 
 		fmt.Printf("获取文章内容 %d/%d: %s\n", i+1, maxContentArticles, article.Title)
 
-		content, err := gnc.GetArticleContent(article.URL)
+		page, err := gnc.FetchArticle(article.URL)
+		if errors.Is(err, ErrOffline) {
+			return nil, err
+		}
 		if err != nil {
 			fmt.Printf("  获取内容失败: %v\n", err)
 			continue
 		}
+		content := page.Text
+		page.Archive.annotate(article)
 
 		if len(content) > 50 {
 			article.Content = content
@@ -866,62 +943,75 @@ func (gnc *GoogleNewsClient) GetNewsWithContent(params EnhancedGoogleNewsParams,
 
 // GetGoogleNewsRSS 通过RSS feed获取Google News
 func (gnc *GoogleNewsClient) GetGoogleNewsRSS(params EnhancedGoogleNewsParams, config *Config) ([]*NewsArticle, error) {
+	if gnc.sim != nil {
+		return gnc.sim.Articles(params.Query, params.MaxResults), nil
+	}
 	rssURL := gnc.buildGoogleNewsRSSURL(params)
 
 	fmt.Printf("📡 正在通过RSS获取Google News: %s\n", params.Query)
 
-	// 检查缓存
 	cacheKey := fmt.Sprintf("rss_%s_%s_%s", params.Query, params.Language, params.Country)
+	articles, err := gnc.fetchRSSArticles(rssURL, cacheKey, params.Query, params.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	// 保存到文件
+	if config.DataDir != "" {
+		gnc.saveRSSArticlesToFile(articles, params, config.DataDir)
+	}
+	return articles, nil
+}
+
+// fetchRSSArticles 拉取并解析RSS feed，结果按cacheKey缓存；query用于日志与文章关联
+func (gnc *GoogleNewsClient) fetchRSSArticles(rssURL, cacheKey, query string, maxResults int) ([]*NewsArticle, error) {
+	// 检查缓存
 	var cached []*NewsArticle
 	if gnc.cache.Get("google_news_rss", "query", cacheKey, &cached) {
 		fmt.Printf("✅ 从缓存获取到 %d 篇RSS文章\n", len(cached))
+		ScoreArticles(cached)
 		return cached, nil
 	}
+	if gnc.cache.offline {
+		return nil, offlineMiss("google news rss", query)
+	}
 
+	articles, err := gnc.fetchRSS(rssURL, query, maxResults)
+	if err != nil {
+		return nil, err
+	}
+	ScoreArticles(articles)
+
+	// 缓存结果
+	gnc.cache.Set("google_news_rss", "query", cacheKey, articles)
+
+	fmt.Printf("✅ RSS模式获取到 %d 篇文章\n", len(articles))
+	return articles, nil
+}
+
+// fetchRSS 直接请求RSS feed并转换条目，不经过缓存
+func (gnc *GoogleNewsClient) fetchRSS(rssURL, query string, maxResults int) ([]*NewsArticle, error) {
 	var articles []*NewsArticle
 	err := WithRetry(DefaultRetryConfig(), func() error {
-		resp, err := gnc.client.R().Get(rssURL)
+		resp, err := gnc.client.R().SetDoNotParseResponse(true).Get(rssURL)
 		if err != nil {
 			return fmt.Errorf("failed to fetch RSS feed: %w", err)
 		}
+		body := resp.RawBody()
+		defer body.Close()
 
 		if resp.StatusCode() != 200 {
 			return fmt.Errorf("HTTP error %d when fetching RSS feed", resp.StatusCode())
 		}
 
-		// 解析RSS XML
-		var rss RSS
-		if err := xml.Unmarshal(resp.Body(), &rss); err != nil {
-			return fmt.Errorf("failed to parse RSS XML: %w", err)
-		}
-
-		// 转换RSS项目为NewsArticle
-		for i, item := range rss.Channel.Items {
-			if params.MaxResults > 0 && i >= params.MaxResults {
-				break
-			}
-
-			article := gnc.convertRSSItemToNewsArticle(item, params.Query)
-			articles = append(articles, article)
-		}
-
-		return nil
+		// 流式解析RSS XML，逐条转换为NewsArticle，够数即停止读取
+		articles = nil
+		return decodeRSSItems(body, func(item Item) bool {
+			articles = append(articles, gnc.convertRSSItemToNewsArticle(item, query))
+			return maxResults <= 0 || len(articles) < maxResults
+		})
 	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	// 缓存结果
-	gnc.cache.Set("google_news_rss", "query", cacheKey, articles)
-
-	// 保存到文件
-	if config.DataDir != "" {
-		gnc.saveRSSArticlesToFile(articles, params, config.DataDir)
-	}
-
-	fmt.Printf("✅ RSS模式获取到 %d 篇文章\n", len(articles))
-	return articles, nil
+	return articles, err
 }
 
 // buildGoogleNewsRSSURL 构建Google News RSS URL
@@ -951,22 +1041,23 @@ func (gnc *GoogleNewsClient) buildGoogleNewsRSSURL(params EnhancedGoogleNewsPara
 	}
 
 	// 构建最终URL
-	if len(v) > 0 {
+	if params.Query != "" {
 		return baseURL + "/search?" + v.Encode()
 	}
 
-	// 如果没有搜索参数，使用分类URL
-	if params.Category != "" {
-		switch params.Category {
-		case "business":
-			return baseURL + "/topics/CAAqJggKIiBDQkFTRWdvSUwyMHZNRFZ4ZERBU0FtVnVHZ0pWVXlnQVAB"
-		case "technology":
-			return baseURL + "/topics/CAAqJggKIiBDQkFTRWdvSUwyMHZNRGx6TVdZU0FtVnVHZ0pWVXlnQVAB"
-		case "health":
-			return baseURL + "/topics/CAAqJQgKIh9DQkFTRVFvSUwyMHZNR3QwTlRFU0FtVnVHZ0pWVXlnQVAB"
+	// 如果没有搜索参数，使用分类（话题）feed
+	if topic, ok := LookupGoogleNewsTopic(params.Category); ok && params.Category != "" {
+		edition, ok := LookupGoogleNewsEdition(params.Country)
+		if !ok {
+			edition, _ = LookupGoogleNewsEdition(DefaultHeadlineEdition)
 		}
+		return topic.feedURL(edition)
 	}
 
+	// 既无查询也无分类时返回该地区版本的头版
+	if len(v) > 0 {
+		return baseURL + "?" + v.Encode()
+	}
 	return baseURL
 }
 
@@ -1011,6 +1102,9 @@ func (gnc *GoogleNewsClient) convertRSSItemToNewsArticle(item Item, query string
 
 // saveRSSArticlesToFile 保存RSS文章到文件
 func (gnc *GoogleNewsClient) saveRSSArticlesToFile(articles []*NewsArticle, params EnhancedGoogleNewsParams, dataDir string) {
+	if !hasFilesystem {
+		return
+	}
 	newsDir := filepath.Join(dataDir, "news_data")
 
 	// 创建目录
@@ -1025,12 +1119,16 @@ func (gnc *GoogleNewsClient) saveRSSArticlesToFile(articles []*NewsArticle, para
 	filename := fmt.Sprintf("google_news_rss_%s_%s.json", querySlug, today)
 	filePath := filepath.Join(newsDir, filename)
 
-	// 保存文章为JSON
+	// 保存文章为JSON（配置密钥时加密）
 	data, err := json.MarshalIndent(articles, "", "  ")
 	if err != nil {
 		fmt.Printf("无法序列化文章数据: %v\n", err)
 		return
 	}
+	if data, err = gnc.cache.cipher.Seal(data); err != nil {
+		fmt.Printf("无法加密文章数据: %v\n", err)
+		return
+	}
 
 	if err := os.WriteFile(filePath, data, 0644); err != nil {
 		fmt.Printf("无法写入文件 %s: %v\n", filePath, err)
@@ -1087,6 +1185,13 @@ func (gnc *GoogleNewsClient) stripHTMLTags(content string) string {
 
 // GetDirectNewsRSS 直接从各大新闻源RSS获取带真实摘要的新闻
 func (gnc *GoogleNewsClient) GetDirectNewsRSS(query string, maxResults int, config *Config) ([]*NewsArticle, error) {
+	if gnc.sim != nil {
+		return gnc.sim.Articles(query, maxResults), nil
+	}
+	// 直接RSS源没有缓存，离线模式下无法提供
+	if gnc.cache.offline {
+		return nil, offlineMiss("direct rss", query)
+	}
 	fmt.Printf("📡 正在从多个直接新闻源获取: %s\n", query)
 
 	// 主要新闻源RSS列表
@@ -1132,41 +1237,30 @@ func (gnc *GoogleNewsClient) fetchDirectRSSFeed(rssURL, sourceName, query string
 	var articles []*NewsArticle
 
 	err := WithRetry(DefaultRetryConfig(), func() error {
-		resp, err := gnc.client.R().Get(rssURL)
+		resp, err := gnc.client.R().SetDoNotParseResponse(true).Get(rssURL)
 		if err != nil {
 			return fmt.Errorf("failed to fetch RSS feed: %w", err)
 		}
+		body := resp.RawBody()
+		defer body.Close()
 
 		if resp.StatusCode() != 200 {
 			return fmt.Errorf("HTTP error %d", resp.StatusCode())
 		}
 
-		// 解析RSS XML
-		var rss RSS
-		if err := xml.Unmarshal(resp.Body(), &rss); err != nil {
-			return fmt.Errorf("failed to parse RSS XML: %w", err)
-		}
-
-		// 过滤与查询相关的文章
-		count := 0
-		for _, item := range rss.Channel.Items {
-			if count >= maxResults {
-				break
-			}
-
+		// 流式解析RSS XML，过滤与查询相关的文章
+		articles = nil
+		return decodeRSSItems(body, func(item Item) bool {
 			// 简单的关键词匹配
 			if query != "" && !gnc.containsKeyword(item.Title+item.Description, query) {
-				continue
+				return true
 			}
-
-			article := gnc.convertDirectRSSItem(item, sourceName, query)
-			articles = append(articles, article)
-			count++
-		}
-
-		return nil
+			articles = append(articles, gnc.convertDirectRSSItem(item, sourceName, query))
+			return len(articles) < maxResults
+		})
 	})
 
+	ScoreArticles(articles)
 	return articles, err
 }
 
@@ -0,0 +1,162 @@
+package dataflows
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// GoogleNewsTopic is a front-page feed that needs no search query. Section
+// topics map to Google News' locale-independent headline sections; topics
+// without a section are served from a search feed restricted to the last day.
+type GoogleNewsTopic struct {
+	Name        string
+	Description string
+	Section     string
+	Query       string
+}
+
+// GoogleNewsEdition is a country edition of Google News: the hl, gl and ceid
+// parameters that select its language and front page.
+type GoogleNewsEdition struct {
+	Code     string
+	Language string
+	Country  string
+	CEID     string
+}
+
+// DefaultHeadlineTopic and DefaultHeadlineEdition are used when a headline
+// request leaves them empty.
+const (
+	DefaultHeadlineTopic   = "top"
+	DefaultHeadlineEdition = "US"
+)
+
+var googleNewsTopics = map[string]GoogleNewsTopic{
+	"top":           {Name: "top", Description: "the edition's front page"},
+	"world":         {Name: "world", Description: "international news", Section: "WORLD"},
+	"nation":        {Name: "nation", Description: "national news for the edition's country", Section: "NATION"},
+	"business":      {Name: "business", Description: "business and economy", Section: "BUSINESS"},
+	"technology":    {Name: "technology", Description: "technology", Section: "TECHNOLOGY"},
+	"science":       {Name: "science", Description: "science", Section: "SCIENCE"},
+	"health":        {Name: "health", Description: "health", Section: "HEALTH"},
+	"entertainment": {Name: "entertainment", Description: "entertainment", Section: "ENTERTAINMENT"},
+	"sports":        {Name: "sports", Description: "sports", Section: "SPORTS"},
+	"markets":       {Name: "markets", Description: "stock market moves and index levels", Query: "stock market OR stocks OR equities when:1d"},
+	"economy":       {Name: "economy", Description: "macro data, inflation and central banks", Query: "economy OR inflation OR central bank OR interest rates when:1d"},
+	"earnings":      {Name: "earnings", Description: "company earnings reports", Query: "earnings results OR quarterly profit when:1d"},
+	"commodities":   {Name: "commodities", Description: "oil, gold and other commodities", Query: "oil prices OR gold prices OR commodities when:1d"},
+	"crypto":        {Name: "crypto", Description: "crypto assets", Query: "bitcoin OR crypto when:1d"},
+}
+
+var googleNewsEditions = map[string]GoogleNewsEdition{
+	"US": {Code: "US", Language: "en-US", Country: "US", CEID: "US:en"},
+	"GB": {Code: "GB", Language: "en-GB", Country: "GB", CEID: "GB:en"},
+	"CA": {Code: "CA", Language: "en-CA", Country: "CA", CEID: "CA:en"},
+	"AU": {Code: "AU", Language: "en-AU", Country: "AU", CEID: "AU:en"},
+	"IN": {Code: "IN", Language: "en-IN", Country: "IN", CEID: "IN:en"},
+	"SG": {Code: "SG", Language: "en-SG", Country: "SG", CEID: "SG:en"},
+	"HK": {Code: "HK", Language: "zh-HK", Country: "HK", CEID: "HK:zh-Hant"},
+	"TW": {Code: "TW", Language: "zh-TW", Country: "TW", CEID: "TW:zh-Hant"},
+	"CN": {Code: "CN", Language: "zh-CN", Country: "CN", CEID: "CN:zh-Hans"},
+	"JP": {Code: "JP", Language: "ja", Country: "JP", CEID: "JP:ja"},
+	"KR": {Code: "KR", Language: "ko", Country: "KR", CEID: "KR:ko"},
+	"DE": {Code: "DE", Language: "de", Country: "DE", CEID: "DE:de"},
+	"FR": {Code: "FR", Language: "fr", Country: "FR", CEID: "FR:fr"},
+}
+
+// LookupGoogleNewsTopic finds a topic by name, case-insensitively.
+func LookupGoogleNewsTopic(name string) (GoogleNewsTopic, bool) {
+	t, ok := googleNewsTopics[strings.ToLower(strings.TrimSpace(name))]
+	return t, ok
+}
+
+// LookupGoogleNewsEdition finds an edition by country code, case-insensitively.
+func LookupGoogleNewsEdition(code string) (GoogleNewsEdition, bool) {
+	e, ok := googleNewsEditions[strings.ToUpper(strings.TrimSpace(code))]
+	return e, ok
+}
+
+// GoogleNewsTopicNames lists the registered topics in alphabetical order.
+func GoogleNewsTopicNames() []string {
+	names := make([]string, 0, len(googleNewsTopics))
+	for name := range googleNewsTopics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GoogleNewsEditionCodes lists the registered editions in alphabetical order.
+func GoogleNewsEditionCodes() []string {
+	codes := make([]string, 0, len(googleNewsEditions))
+	for code := range googleNewsEditions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// GoogleNewsTopicURL builds the RSS feed URL of topic in edition, falling back
+// to the defaults for empty names.
+func GoogleNewsTopicURL(topic, edition string) (string, error) {
+	if topic == "" {
+		topic = DefaultHeadlineTopic
+	}
+	if edition == "" {
+		edition = DefaultHeadlineEdition
+	}
+	t, ok := LookupGoogleNewsTopic(topic)
+	if !ok {
+		return "", fmt.Errorf("unknown news topic %q (available: %s)", topic, strings.Join(GoogleNewsTopicNames(), ", "))
+	}
+	e, ok := LookupGoogleNewsEdition(edition)
+	if !ok {
+		return "", fmt.Errorf("unknown news edition %q (available: %s)", edition, strings.Join(GoogleNewsEditionCodes(), ", "))
+	}
+	return t.feedURL(e), nil
+}
+
+// feedURL is the RSS URL of the topic in edition e.
+func (t GoogleNewsTopic) feedURL(e GoogleNewsEdition) string {
+	const baseURL = "https://news.google.com/rss"
+	v := e.values()
+	switch {
+	case t.Section != "":
+		return baseURL + "/headlines/section/topic/" + t.Section + "?" + v.Encode()
+	case t.Query != "":
+		v.Set("q", t.Query)
+		return baseURL + "/search?" + v.Encode()
+	}
+	return baseURL + "?" + v.Encode()
+}
+
+func (e GoogleNewsEdition) values() url.Values {
+	v := url.Values{}
+	v.Set("hl", e.Language)
+	v.Set("gl", e.Country)
+	v.Set("ceid", e.CEID)
+	return v
+}
+
+// GetTopHeadlines fetches the current stories of a topic feed in a country
+// edition, e.g. the US front page or Hong Kong business news.
+func (gnc *GoogleNewsClient) GetTopHeadlines(topic, edition string, maxResults int) ([]*NewsArticle, error) {
+	if topic == "" {
+		topic = DefaultHeadlineTopic
+	}
+	if edition == "" {
+		edition = DefaultHeadlineEdition
+	}
+	rssURL, err := GoogleNewsTopicURL(topic, edition)
+	if err != nil {
+		return nil, err
+	}
+	topic, edition = strings.ToLower(topic), strings.ToUpper(edition)
+	if gnc.sim != nil {
+		return gnc.sim.Articles(topic, maxResults), nil
+	}
+	cacheKey := fmt.Sprintf("headlines_%s_%s_%d", topic, edition, maxResults)
+	return gnc.fetchRSSArticles(rssURL, cacheKey, topic+" headlines "+edition, maxResults)
+}
@@ -0,0 +1,49 @@
+package dataflows
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoogleNewsTopicURL(t *testing.T) {
+	cases := []struct {
+		topic, edition string
+		want           string
+	}{
+		{"", "", "https://news.google.com/rss?ceid=US%3Aen&gl=US&hl=en-US"},
+		{"Business", "hk", "https://news.google.com/rss/headlines/section/topic/BUSINESS?ceid=HK%3Azh-Hant&gl=HK&hl=zh-HK"},
+		{"world", "CN", "https://news.google.com/rss/headlines/section/topic/WORLD?ceid=CN%3Azh-Hans&gl=CN&hl=zh-CN"},
+	}
+	for _, c := range cases {
+		got, err := GoogleNewsTopicURL(c.topic, c.edition)
+		if err != nil {
+			t.Fatalf("%s/%s: %v", c.topic, c.edition, err)
+		}
+		if got != c.want {
+			t.Errorf("%s/%s: got %s, want %s", c.topic, c.edition, got, c.want)
+		}
+	}
+
+	markets, err := GoogleNewsTopicURL("markets", "GB")
+	if err != nil || !strings.Contains(markets, "/search?") || !strings.Contains(markets, "gl=GB") {
+		t.Errorf("expected a GB search feed for markets, got %s (%v)", markets, err)
+	}
+	if _, err := GoogleNewsTopicURL("gossip", "US"); err == nil {
+		t.Error("expected an error for an unknown topic")
+	}
+	if _, err := GoogleNewsTopicURL("world", "XX"); err == nil {
+		t.Error("expected an error for an unknown edition")
+	}
+}
+
+func TestBuildGoogleNewsRSSURLUsesTopicRegistry(t *testing.T) {
+	gnc := &GoogleNewsClient{}
+	got := gnc.buildGoogleNewsRSSURL(EnhancedGoogleNewsParams{Category: "technology", Language: "en", Country: "GB"})
+	if !strings.HasPrefix(got, "https://news.google.com/rss/headlines/section/topic/TECHNOLOGY?") || !strings.Contains(got, "ceid=GB%3Aen") {
+		t.Errorf("unexpected category feed URL: %s", got)
+	}
+	got = gnc.buildGoogleNewsRSSURL(EnhancedGoogleNewsParams{Query: "NVDA", Category: "technology", Language: "en", Country: "US"})
+	if !strings.Contains(got, "/search?") {
+		t.Errorf("a query should take precedence over the category: %s", got)
+	}
+}
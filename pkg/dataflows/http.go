@@ -0,0 +1,83 @@
+package dataflows
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// defaultHTTPTimeout bounds a whole request, body included, when
+// http_timeout is not set.
+const defaultHTTPTimeout = 30 * time.Second
+
+var (
+	transportMu   sync.Mutex
+	httpTransport http.RoundTripper = newBreakerTransport(defaultTransport())
+	httpClients                     = map[httpClientKey]*resty.Client{}
+)
+
+// httpClientKey is what tells shared clients apart.
+type httpClientKey struct {
+	userAgent string
+	offline   bool
+	timeout   time.Duration
+}
+
+// SetHTTPTransport replaces the transport used by data source clients created
+// afterwards; nil restores the platform default. Hosts embedding the dataflows
+// layer use it to route requests through their own stack, e.g. a CORS proxy.
+// Per-host circuit breakers are kept in front of rt.
+func SetHTTPTransport(rt http.RoundTripper) {
+	if rt == nil {
+		rt = defaultTransport()
+	}
+	transportMu.Lock()
+	httpTransport = newBreakerTransport(rt)
+	httpClients = map[httpClientKey]*resty.Client{}
+	transportMu.Unlock()
+}
+
+// newHTTPClient returns the resty client for the news and social data
+// sources, installing the offline guard in offline and simulated data mode. Clients are
+// shared by every caller with the same settings and all use one pooled
+// transport, so building a data source client per tool call reuses
+// connections. Callers must not change the returned client.
+func newHTTPClient(config *Config, userAgent string) *resty.Client {
+	offline := config.Offline || config.SimulatedData()
+	key := httpClientKey{userAgent: userAgent, offline: offline, timeout: defaultHTTPTimeout}
+	if config.HTTPTimeout > 0 {
+		key.timeout = time.Duration(config.HTTPTimeout) * time.Second
+	}
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	if client, ok := httpClients[key]; ok {
+		return client
+	}
+	client := resty.New()
+	client.SetTransport(httpTransport)
+	if offline {
+		client.OnBeforeRequest(offlineGuard)
+	}
+	client.SetTimeout(key.timeout)
+	client.SetHeader("User-Agent", userAgent)
+	httpClients[key] = client
+	return client
+}
+
+// withoutQuery drops the query string from the URL a transport error quotes,
+// so an API key sent as a query parameter stays out of logs, source outages
+// and the states and prompts they end up in.
+func withoutQuery(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		if i := strings.IndexByte(ue.URL, '?'); i >= 0 {
+			ue.URL = ue.URL[:i]
+		}
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package dataflows
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/go-resty/resty/v2"
+)
+
+// ErrNoInsiderProvider is returned when no Finnhub key is configured
+var ErrNoInsiderProvider = errors.New("insider sentiment needs a Finnhub key: set finnhub_api_key")
+
+// insiderHistoryMonths is how far back insider sentiment is requested
+const insiderHistoryMonths = 24
+
+// insiderTrendMSPR is the 3-month average MSPR, on its -100 to 100 scale,
+// beyond which insiders count as net buyers or sellers
+const insiderTrendMSPR = 10
+
+// InsiderClient fetches Finnhub's monthly insider sentiment (MSPR)
+type InsiderClient struct {
+	client *resty.Client
+	cache  *CacheManager
+	key    string
+}
+
+// NewInsiderClient creates a new insider sentiment client
+func NewInsiderClient(config *Config) *InsiderClient {
+	return &InsiderClient{
+		client: newHTTPClient(config, "CortexGo/1.0"),
+		cache:  newCacheManager(config, "insider", 24*time.Hour),
+		key:    strings.TrimSpace(config.FinnhubAPIKey),
+	}
+}
+
+// GetInsiderSentiment returns the months that ended before before
+// (YYYY-MM-DD, empty for today), newest first. The month of before itself is
+// left out: its figures are only final once it ends.
+func (ic *InsiderClient) GetInsiderSentiment(symbol, before string) ([]models.InsiderSentimentMonth, error) {
+	symbol = NormalizeSymbol(symbol)
+	if err := ValidateSymbol(symbol); err != nil {
+		return nil, err
+	}
+	ticker, market, found := strings.Cut(symbol, ".")
+	if found && market != "US" {
+		return nil, fmt.Errorf("insider sentiment is only available for US listings, got %s", symbol)
+	}
+	if ic.key == "" {
+		return nil, ErrNoInsiderProvider
+	}
+	to, err := insiderCutoff(before)
+	if err != nil {
+		return nil, err
+	}
+	from := to.AddDate(0, -insiderHistoryMonths, 0)
+	params := map[string]string{"symbol": ticker, "from": from.Format("2006-01-02"), "to": to.AddDate(0, 0, -1).Format("2006-01-02")}
+
+	var months []models.InsiderSentimentMonth
+	if !ic.cache.Get("finnhub", "insider_sentiment", params, &months) {
+		if ic.cache.offline {
+			return nil, offlineMiss("insider", ticker)
+		}
+		if months, err = ic.fetch(params); err != nil {
+			return nil, err
+		}
+		ic.cache.Set("finnhub", "insider_sentiment", params, months)
+	}
+
+	var kept []models.InsiderSentimentMonth
+	for _, m := range months {
+		if time.Date(m.Year, time.Month(m.Month), 1, 0, 0, 0, 0, time.UTC).Before(to) {
+			kept = append(kept, m)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].Year != kept[j].Year {
+			return kept[i].Year > kept[j].Year
+		}
+		return kept[i].Month > kept[j].Month
+	})
+	return kept, nil
+}
+
+// insiderCutoff is the first day of before's month (today's when empty):
+// the exclusive upper bound of the months that have ended.
+func insiderCutoff(before string) (time.Time, error) {
+	end := time.Now()
+	if before != "" {
+		var err error
+		if end, err = time.Parse("2006-01-02", before); err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q: want YYYY-MM-DD", before)
+		}
+	}
+	return time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+func (ic *InsiderClient) fetch(params map[string]string) ([]models.InsiderSentimentMonth, error) {
+	var resp struct {
+		Data []models.InsiderSentimentMonth `json:"data"`
+	}
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		r, err := ic.client.R().SetHeader(finnhubTokenHeader, ic.key).SetQueryParams(params).Get(finnhubBaseURL + "/stock/insider-sentiment")
+		if err != nil {
+			return fmt.Errorf("failed to fetch insider sentiment: %w", err)
+		}
+		switch code := r.StatusCode(); {
+		case code == http.StatusUnauthorized || code == http.StatusForbidden || code == http.StatusPaymentRequired:
+			return &noRetryError{fmt.Errorf("finnhub rejected the request (HTTP %d): check the API key", code)}
+		case code != http.StatusOK:
+			return fmt.Errorf("HTTP error %d when fetching insider sentiment", code)
+		}
+		if err := json.Unmarshal(r.Body(), &resp); err != nil {
+			return fmt.Errorf("failed to parse Finnhub insider sentiment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+// SummarizeInsiderSentiment averages MSPR and sums net shares over the 3
+// and 12 calendar months that ended before before, as returned newest first
+// by GetInsiderSentiment, and labels the trend from the 3-month average.
+// Months without insider transactions are not reported and count as quiet.
+func SummarizeInsiderSentiment(symbol, before string, months []models.InsiderSentimentMonth) *models.InsiderSentiment {
+	s := &models.InsiderSentiment{Symbol: symbol, Months: months, Trend: models.InsiderNeutral}
+	to, err := insiderCutoff(before)
+	if err != nil {
+		return s
+	}
+	last := to.AddDate(0, -1, 0)
+	s.AsOf = last.Format("2006-01")
+	if len(months) == 0 {
+		return s
+	}
+
+	latest := last.Year()*12 + int(last.Month())
+	var sum3, sum12 float64
+	var n3, n12 int
+	for _, m := range months {
+		age := latest - (m.Year*12 + m.Month)
+		if age < 0 {
+			continue
+		}
+		if age >= 12 {
+			break
+		}
+		sum12 += m.MSPR
+		n12++
+		s.NetShares12m += m.Change
+		switch {
+		case m.Change > 0:
+			s.BuyingMonths++
+		case m.Change < 0:
+			s.SellingMonths++
+		}
+		if age < 3 {
+			sum3 += m.MSPR
+			n3++
+			s.NetShares3m += m.Change
+		}
+	}
+	if n3 > 0 {
+		s.MSPR3m = math.Round(sum3/float64(n3)*10) / 10
+	}
+	if n12 > 0 {
+		s.MSPR12m = math.Round(sum12/float64(n12)*10) / 10
+	}
+
+	switch {
+	case n3 == 0:
+		s.Notes = append(s.Notes, "no insider transactions in the last 3 months")
+	case s.MSPR3m >= insiderTrendMSPR:
+		s.Trend = models.InsiderNetBuying
+	case s.MSPR3m <= -insiderTrendMSPR:
+		s.Trend = models.InsiderNetSelling
+	}
+	switch {
+	case s.Trend == models.InsiderNetBuying && s.MSPR12m <= -insiderTrendMSPR:
+		s.Notes = append(s.Notes, "insiders turned buyers after a year of net selling")
+	case s.Trend == models.InsiderNetSelling && s.MSPR12m >= insiderTrendMSPR:
+		s.Notes = append(s.Notes, "insiders turned sellers after a year of net buying")
+	}
+	if n12 < 12 {
+		s.Notes = append(s.Notes, fmt.Sprintf("insiders traded in %d of the last 12 months", n12))
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+package dataflows
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+const insiderBody = `{"symbol":"TSLA","data":[
+{"symbol":"TSLA","year":2024,"month":1,"change":-1500,"mspr":-40},
+{"symbol":"TSLA","year":2024,"month":3,"change":2000,"mspr":30},
+{"symbol":"TSLA","year":2024,"month":4,"change":900,"mspr":18.5},
+{"symbol":"TSLA","year":2024,"month":5,"change":100,"mspr":2}]}`
+
+func TestGetInsiderSentimentDropsUnfinishedMonth(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		q := r.URL.Query()
+		if r.URL.Path != "/stock/insider-sentiment" || q.Get("symbol") != "TSLA" || r.Header.Get("X-Finnhub-Token") != "key" || q.Get("token") != "" || q.Get("to") != "2024-04-30" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(insiderBody))
+	}))
+	defer srv.Close()
+	defer func(old string) { finnhubBaseURL = old }(finnhubBaseURL)
+	finnhubBaseURL = srv.URL
+
+	cfg := &Config{DataCacheDir: t.TempDir(), CacheEnabled: true, FinnhubAPIKey: "key"}
+	months, err := NewInsiderClient(cfg).GetInsiderSentiment("tsla.us", "2024-05-20")
+	if err != nil {
+		t.Fatalf("GetInsiderSentiment: %v", err)
+	}
+	if len(months) != 3 || months[0].Month != 4 || months[2].Month != 1 {
+		t.Fatalf("months = %+v", months)
+	}
+	if _, err := NewInsiderClient(cfg).GetInsiderSentiment("TSLA", "2024-05-02"); err != nil || calls != 1 {
+		t.Errorf("cached lookup: calls = %d, err = %v", calls, err)
+	}
+
+	s := SummarizeInsiderSentiment("TSLA", "2024-05-20", months)
+	// February had no transactions; March and April are the last 3 months' activity
+	if s.AsOf != "2024-04" || s.MSPR3m != 24.3 || s.NetShares3m != 2900 || s.MSPR12m != 2.8 || s.NetShares12m != 1400 {
+		t.Errorf("summary = %+v", s)
+	}
+	if s.Trend != models.InsiderNetBuying || s.BuyingMonths != 2 || s.SellingMonths != 1 {
+		t.Errorf("trend %s, buying %d, selling %d", s.Trend, s.BuyingMonths, s.SellingMonths)
+	}
+
+	if _, err := NewInsiderClient(&Config{}).GetInsiderSentiment("TSLA", ""); !errors.Is(err, ErrNoInsiderProvider) {
+		t.Errorf("no key: %v", err)
+	}
+	if _, err := NewInsiderClient(cfg).GetInsiderSentiment("700.HK", ""); err == nil {
+		t.Error("want error for a non-US listing")
+	}
+	cfg.Offline = true
+	if _, err := NewInsiderClient(cfg).GetInsiderSentiment("NVDA", "2024-05-20"); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline miss: %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+package dataflows
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Keyword is a phrase that recurs across a set of articles.
+type Keyword struct {
+	Phrase   string  `json:"phrase"`
+	Score    float64 `json:"score"`
+	Articles int     `json:"articles"` // number of articles mentioning it
+}
+
+// TopicCluster groups articles covering the same story or theme. Articles
+// holds indexes into the slice passed to ClusterTopics.
+type TopicCluster struct {
+	Label    string   `json:"label"`
+	Keywords []string `json:"keywords"`
+	Articles []int    `json:"articles"`
+}
+
+// stopWords split RAKE candidate phrases and are left out of TF-IDF vectors.
+// Besides function words it holds the boilerplate of market headlines, which
+// would otherwise link every article to every other.
+var stopWords = wordSet(`a about above after again against all also am an and any are as at be because
+	been before being below between both but by can could did do does doing down during each few for from
+	further had has have having he her here hers him his how i if in into is it its itself just me more
+	most my no nor not now of off on once only or other our out over own same she should so some such
+	than that the their them then there these they this those through to too under until up very was we
+	were what when where which while who whom why will with would you your says said say new amid via
+	vs per get gets got make makes like still much many one two three first last next week weeks
+	today year years day days time times report reports reported news update updates live latest here's
+	what's it's stock stocks share shares market markets investor investors inc corp co ltd company
+	companies price prices percent according may might`)
+
+const (
+	// maxPhraseWords caps the length of extracted keyphrases.
+	maxPhraseWords = 3
+	// minClusterSimilarity is the cosine similarity of TF-IDF vectors above
+	// which two articles are considered to cover the same topic.
+	minClusterSimilarity = 0.25
+)
+
+// ExtractKeywords ranks the phrases trending across docs. Words are scored
+// with RAKE (degree over frequency within runs of content words between stop
+// words and punctuation) and phrases are the runs' n-grams of up to
+// maxPhraseWords words, so "rate cut" matches in "Fed signals rate cut" and
+// "Fed rate cut". A phrase's score sums its RAKE score over the documents that
+// mention it, so themes picked up by several articles outrank a phrase
+// repeated within one. Phrases seen in a single document are dropped when
+// docs has more than one entry.
+func ExtractKeywords(docs []string, n int) []Keyword {
+	phrasesByDoc := make([][][]string, len(docs))
+	freq := map[string]float64{}
+	degree := map[string]float64{}
+	for i, doc := range docs {
+		phrasesByDoc[i] = candidatePhrases(doc)
+		for _, p := range phrasesByDoc[i] {
+			for _, w := range p {
+				freq[w]++
+				degree[w] += float64(len(p))
+			}
+		}
+	}
+
+	scores := map[string]float64{}
+	docFreq := map[string]int{}
+	for _, runs := range phrasesByDoc {
+		seen := map[string]bool{}
+		for _, run := range runs {
+			for size := 1; size <= maxPhraseWords && size <= len(run); size++ {
+				for start := 0; start+size <= len(run); start++ {
+					gram := run[start : start+size]
+					key := strings.Join(gram, " ")
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					score := 0.0
+					for _, w := range gram {
+						score += degree[w] / freq[w]
+					}
+					scores[key] += score
+					docFreq[key]++
+				}
+			}
+		}
+	}
+
+	keywords := make([]Keyword, 0, len(scores))
+	for phrase, score := range scores {
+		if len(docs) > 1 && docFreq[phrase] < 2 {
+			continue
+		}
+		keywords = append(keywords, Keyword{Phrase: phrase, Score: math.Round(score*100) / 100, Articles: docFreq[phrase]})
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Phrase < keywords[j].Phrase
+	})
+	keywords = dropContained(keywords)
+	if n > 0 && len(keywords) > n {
+		keywords = keywords[:n]
+	}
+	return keywords
+}
+
+// ClusterTopics groups docs whose TF-IDF vectors are similar, linking any
+// pair above minClusterSimilarity, and labels each group with the terms
+// weighing most in it. Only groups of two or more are returned, largest first.
+func ClusterTopics(docs []string) []TopicCluster {
+	vectors := tfidfVectors(docs)
+	parent := make([]int, len(docs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range vectors {
+		for j := i + 1; j < len(vectors); j++ {
+			if cosine(vectors[i], vectors[j]) >= minClusterSimilarity {
+				if ri, rj := find(i), find(j); ri != rj {
+					parent[rj] = ri
+				}
+			}
+		}
+	}
+
+	groups := map[int][]int{}
+	var roots []int
+	for i := range docs {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+	var clusters []TopicCluster
+	for _, root := range roots {
+		members := groups[root]
+		if len(members) < 2 {
+			continue
+		}
+		weights := map[string]float64{}
+		for _, i := range members {
+			for term, w := range vectors[i] {
+				weights[term] += w
+			}
+		}
+		terms := topTerms(weights, 4)
+		clusters = append(clusters, TopicCluster{Label: strings.Join(terms, " / "), Keywords: terms, Articles: members})
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return len(clusters[i].Articles) > len(clusters[j].Articles) })
+	return clusters
+}
+
+// candidatePhrases splits text into RAKE candidates: runs of content words
+// broken at stop words and punctuation.
+func candidatePhrases(text string) [][]string {
+	var phrases [][]string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			phrases = append(phrases, current)
+		}
+		current = nil
+	}
+	for _, chunk := range strings.FieldsFunc(strings.ToLower(text), isPhraseBreak) {
+		for _, word := range strings.Fields(chunk) {
+			word = strings.Trim(word, "'\"-$%&")
+			if !isContentWord(word) {
+				flush()
+				continue
+			}
+			current = append(current, word)
+		}
+		flush()
+	}
+	return phrases
+}
+
+// tfidfVectors returns each doc's unit-length TF-IDF vector over content words.
+func tfidfVectors(docs []string) []map[string]float64 {
+	counts := make([]map[string]float64, len(docs))
+	docFreq := map[string]int{}
+	for i, doc := range docs {
+		counts[i] = map[string]float64{}
+		for _, p := range candidatePhrases(doc) {
+			for _, w := range p {
+				counts[i][w]++
+			}
+		}
+		for w := range counts[i] {
+			docFreq[w]++
+		}
+	}
+	n := float64(len(docs))
+	for _, c := range counts {
+		norm := 0.0
+		for w, tf := range c {
+			c[w] = tf * math.Log(1+n/float64(docFreq[w]))
+			norm += c[w] * c[w]
+		}
+		norm = math.Sqrt(norm)
+		for w := range c {
+			c[w] /= norm
+		}
+	}
+	return counts
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	dot := 0.0
+	for w, x := range a {
+		dot += x * b[w]
+	}
+	return dot
+}
+
+// topTerms returns the n heaviest terms, ties broken alphabetically.
+func topTerms(weights map[string]float64, n int) []string {
+	terms := make([]string, 0, len(weights))
+	for t := range weights {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if weights[terms[i]] != weights[terms[j]] {
+			return weights[terms[i]] > weights[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	if len(terms) > n {
+		terms = terms[:n]
+	}
+	return terms
+}
+
+// dropContained removes keywords contained in a higher-ranked phrase, so
+// "rate cut" does not repeat under "fed rate cut".
+func dropContained(keywords []Keyword) []Keyword {
+	var kept []Keyword
+	for _, k := range keywords {
+		redundant := false
+		for _, prev := range kept {
+			if strings.Contains(" "+prev.Phrase+" ", " "+k.Phrase+" ") {
+				redundant = true
+				break
+			}
+		}
+		if !redundant {
+			kept = append(kept, k)
+		}
+	}
+	return kept
+}
+
+// isPhraseBreak splits at punctuation, keeping characters that occur inside
+// names and figures ("AT&T", "S&P", "co-founder").
+func isPhraseBreak(r rune) bool {
+	return r == '|' || unicode.IsPunct(r) && !strings.ContainsRune("'-&%", r)
+}
+
+func isContentWord(word string) bool {
+	if len(word) < 2 || stopWords[word] {
+		return false
+	}
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
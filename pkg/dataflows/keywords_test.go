@@ -0,0 +1,45 @@
+package dataflows
+
+import (
+	"slices"
+	"testing"
+)
+
+var keywordDocs = []string{
+	"Fed signals rate cut as inflation cools. Treasury yields fall after the Fed decision",
+	"Inflation cools in September, boosting bets on a Fed rate cut",
+	"Nvidia unveils new AI chips at developer conference",
+	"Nvidia AI chips demand lifts chipmakers; AI chips sold out through next year",
+	"Oil prices steady as OPEC weighs output",
+}
+
+func TestExtractKeywordsFindsSharedThemes(t *testing.T) {
+	keywords := ExtractKeywords(keywordDocs, 5)
+	var phrases []string
+	for _, k := range keywords {
+		if k.Articles < 2 {
+			t.Errorf("%q is mentioned by a single article", k.Phrase)
+		}
+		phrases = append(phrases, k.Phrase)
+	}
+	for _, want := range []string{"rate cut", "inflation cools", "ai chips"} {
+		if !slices.Contains(phrases, want) {
+			t.Errorf("expected %q among %v", want, phrases)
+		}
+	}
+	if slices.Contains(phrases, "chips") {
+		t.Errorf("expected %q to be folded into the longer phrase: %v", "chips", phrases)
+	}
+}
+
+func TestClusterTopicsGroupsSameStory(t *testing.T) {
+	clusters := ClusterTopics(keywordDocs)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %+v", clusters)
+	}
+	for _, c := range clusters {
+		if !slices.Equal(c.Articles, []int{0, 1}) && !slices.Equal(c.Articles, []int{2, 3}) {
+			t.Errorf("unexpected cluster %+v", c)
+		}
+	}
+}
@@ -3,59 +3,272 @@ package dataflows
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 
+	"github.com/dyike/CortexGo/config"
 	lpconfig "github.com/longportapp/openapi-go/config"
 	"github.com/longportapp/openapi-go/quote"
 	"github.com/longportapp/openapi-go/trade"
 )
 
+// Mainland China endpoints, used by accounts in the cn region
+const (
+	longportCNHTTPURL  = "https://openapi.longportapp.cn"
+	longportCNQuoteURL = "wss://openapi-quote.longportapp.cn"
+	longportCNTradeURL = "wss://openapi-trade.longportapp.cn"
+)
+
+// LongportConfig selects the Longport accounts a client uses. AppKey,
+// AppSecret and AccessToken describe a single account; Accounts adds more.
+// Profile names the account positions and balances are read from, empty for
+// the first one.
 type LongportConfig struct {
 	AppKey      string
 	AppSecret   string
 	AccessToken string
+	Region      string
+
+	Accounts []config.LongportAccount
+	Profile  string
 }
 
+// LongportConfigFrom builds the client configuration of every Longport
+// account in cfg.
+func LongportConfigFrom(cfg *Config) LongportConfig {
+	return LongportConfig{Accounts: cfg.LongportAccountList(), Profile: cfg.LongportProfile}
+}
+
+// LongportClient routes each request to the Longport accounts that cover the
+// symbol's market, failing over to the other accounts when one fails.
+// Connections are opened on first use.
 type LongportClient struct {
+	accounts []*longportAccount
+	profile  *longportAccount
+}
+
+type longportAccount struct {
+	config.LongportAccount
+	markets map[string]bool
+
+	mu       sync.Mutex
 	tradeCtx *trade.TradeContext
 	quoteCtx *quote.QuoteContext
 }
 
 func NewLongportClient(cfg LongportConfig) (*LongportClient, error) {
-	if cfg.AppKey == "" || cfg.AppSecret == "" || cfg.AccessToken == "" {
+	accounts := cfg.Accounts
+	if cfg.AppKey != "" || cfg.AppSecret != "" || cfg.AccessToken != "" {
+		single := config.LongportAccount{Name: config.LongportDefaultAccount, Region: cfg.Region, AppKey: cfg.AppKey, AppSecret: cfg.AppSecret, AccessToken: cfg.AccessToken}
+		accounts = append([]config.LongportAccount{single}, accounts...)
+	}
+
+	lpc := &LongportClient{}
+	for _, a := range accounts {
+		if !a.Complete() {
+			continue
+		}
+		acct := &longportAccount{LongportAccount: a, markets: map[string]bool{}}
+		for _, m := range a.MarketList() {
+			acct.markets[m] = true
+		}
+		lpc.accounts = append(lpc.accounts, acct)
+		if cfg.Profile != "" && a.Name == cfg.Profile {
+			lpc.profile = acct
+		}
+	}
+	if len(lpc.accounts) == 0 {
 		return nil, errors.New("longport API credentials not configured")
 	}
+	if cfg.Profile == "" {
+		lpc.profile = lpc.accounts[0]
+	}
+	if lpc.profile == nil {
+		return nil, fmt.Errorf("longport profile %q is not a configured account", cfg.Profile)
+	}
+	return lpc, nil
+}
+
+// WithProfile returns a client reading positions and balances from the
+// named account instead; empty keeps the current one.
+func (lpc *LongportClient) WithProfile(name string) (*LongportClient, error) {
+	if name == "" {
+		return lpc, nil
+	}
+	for _, a := range lpc.accounts {
+		if a.Name == name {
+			return &LongportClient{accounts: lpc.accounts, profile: a}, nil
+		}
+	}
+	return nil, fmt.Errorf("longport profile %q is not a configured account", name)
+}
+
+// Accounts lists the account names in routing order.
+func (lpc *LongportClient) Accounts() []string {
+	names := make([]string, len(lpc.accounts))
+	for i, a := range lpc.accounts {
+		names[i] = a.Name
+	}
+	return names
+}
+
+// route orders the accounts for a market: the ones covering it first, in
+// configured order, then the rest as fallbacks.
+func (lpc *LongportClient) route(market string) []*longportAccount {
+	var first, rest []*longportAccount
+	for _, a := range lpc.accounts {
+		if a.markets[market] {
+			first = append(first, a)
+		} else {
+			rest = append(rest, a)
+		}
+	}
+	return append(first, rest...)
+}
+
+// symbolMarket is the market suffix of a symbol (AAPL.US -> US).
+func symbolMarket(symbol string) string {
+	if i := strings.LastIndex(symbol, "."); i >= 0 {
+		return strings.ToUpper(symbol[i+1:])
+	}
+	return ""
+}
 
-	conf, err := lpconfig.New(lpconfig.WithConfigKey(cfg.AppKey, cfg.AppSecret, cfg.AccessToken))
+// withQuote calls fn with the quote connection of each account routed for
+// market until one succeeds. A cancelled context stops the failover.
+func (lpc *LongportClient) withQuote(ctx context.Context, market string, fn func(*quote.QuoteContext) error) error {
+	var errs []error
+	for _, a := range lpc.route(market) {
+		qc, err := a.quote()
+		if err == nil {
+			if err = fn(qc); err == nil {
+				return nil
+			}
+		}
+		errs = append(errs, fmt.Errorf("longport account %s: %w", a.Name, err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// groupByMarket splits symbols by market, keeping the order of first
+// appearance.
+func groupByMarket(symbols []string) ([]string, map[string][]string) {
+	var order []string
+	groups := map[string][]string{}
+	for _, s := range symbols {
+		m := symbolMarket(s)
+		if _, ok := groups[m]; !ok {
+			order = append(order, m)
+		}
+		groups[m] = append(groups[m], s)
+	}
+	return order, groups
+}
+
+func (a *longportAccount) config() (*lpconfig.Config, error) {
+	conf, err := lpconfig.New(lpconfig.WithConfigKey(a.AppKey, a.AppSecret, a.AccessToken))
 	if err != nil {
 		return nil, err
 	}
+	if strings.EqualFold(a.Region, config.LongportRegionCN) {
+		conf.HttpURL, conf.QuoteUrl, conf.TradeUrl = longportCNHTTPURL, longportCNQuoteURL, longportCNTradeURL
+	}
+	return conf, nil
+}
 
-	tradeContext, err := trade.NewFromCfg(conf)
+func (a *longportAccount) quote() (*quote.QuoteContext, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.quoteCtx != nil {
+		return a.quoteCtx, nil
+	}
+	conf, err := a.config()
 	if err != nil {
 		return nil, err
 	}
+	if a.quoteCtx, err = quote.NewFromCfg(conf); err != nil {
+		return nil, err
+	}
+	return a.quoteCtx, nil
+}
 
-	quoteContext, err := quote.NewFromCfg(conf)
+func (a *longportAccount) trade() (*trade.TradeContext, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.tradeCtx != nil {
+		return a.tradeCtx, nil
+	}
+	conf, err := a.config()
 	if err != nil {
 		return nil, err
 	}
-
-	return &LongportClient{
-		tradeCtx: tradeContext,
-		quoteCtx: quoteContext,
-	}, nil
+	if a.tradeCtx, err = trade.NewFromCfg(conf); err != nil {
+		return nil, err
+	}
+	return a.tradeCtx, nil
 }
 
 func (lpc *LongportClient) GetStaticInfo(ctx context.Context, symbols []string) (staticInfos []*quote.StaticInfo, err error) {
-	if lpc.quoteCtx != nil {
-		return lpc.quoteCtx.StaticInfo(ctx, symbols)
+	order, groups := groupByMarket(symbols)
+	for _, m := range order {
+		err := lpc.withQuote(ctx, m, func(qc *quote.QuoteContext) error {
+			infos, err := qc.StaticInfo(ctx, groups[m])
+			if err == nil {
+				staticInfos = append(staticInfos, infos...)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
-	return nil, errors.New("quote context is nil")
+	return staticInfos, nil
 }
 
 func (lpc *LongportClient) GetSticksWithDay(ctx context.Context, symbols string, count int) (sticks []*quote.Candlestick, err error) {
-	if lpc.quoteCtx != nil {
-		return lpc.quoteCtx.Candlesticks(ctx, symbols, quote.PeriodDay, int32(count), quote.AdjustTypeNo)
+	err = lpc.withQuote(ctx, symbolMarket(symbols), func(qc *quote.QuoteContext) error {
+		sticks, err = qc.Candlesticks(ctx, symbols, quote.PeriodDay, int32(count), quote.AdjustTypeNo)
+		return err
+	})
+	return sticks, err
+}
+
+func (lpc *LongportClient) GetQuote(ctx context.Context, symbols []string) (quotes []*quote.SecurityQuote, err error) {
+	order, groups := groupByMarket(symbols)
+	for _, m := range order {
+		err := lpc.withQuote(ctx, m, func(qc *quote.QuoteContext) error {
+			q, err := qc.Quote(ctx, groups[m])
+			if err == nil {
+				quotes = append(quotes, q...)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return quotes, nil
+}
+
+// GetStockPositions reads the positions of the profile account only; account
+// data never fails over to another account.
+func (lpc *LongportClient) GetStockPositions(ctx context.Context) (channels []*trade.StockPositionChannel, err error) {
+	tc, err := lpc.profile.trade()
+	if err != nil {
+		return nil, fmt.Errorf("longport account %s: %w", lpc.profile.Name, err)
+	}
+	return tc.StockPositions(ctx, nil)
+}
+
+// GetAccountBalance reads the balances of the profile account only.
+func (lpc *LongportClient) GetAccountBalance(ctx context.Context) (balances []*trade.AccountBalance, err error) {
+	tc, err := lpc.profile.trade()
+	if err != nil {
+		return nil, fmt.Errorf("longport account %s: %w", lpc.profile.Name, err)
 	}
-	return nil, errors.New("trade context is nil")
+	return tc.AccountBalance(ctx, &trade.GetAccountBalance{})
 }
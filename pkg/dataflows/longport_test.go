@@ -2,6 +2,7 @@ package dataflows
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/dyike/CortexGo/config"
@@ -51,3 +52,53 @@ func TestLongportClient_GetStaticInfo(t *testing.T) {
 		t.Logf("LotSize: %d", info.LotSize)
 	}
 }
+
+func TestLongportRoutingAndProfile(t *testing.T) {
+	cfg := &config.Config{
+		LongportAppKey: "k", LongportAppSecret: "s", LongportAccessToken: "t",
+		LongportAccounts: []config.LongportAccount{
+			{Name: "us", Region: "us", AppKey: "k2", AppSecret: "s2", AccessToken: "t2"},
+			{Name: "sg", Region: "sg", Markets: []string{"sg", "us"}, AppKey: "k3", AppSecret: "s3", AccessToken: "t3"},
+			{Name: "incomplete", AppKey: "k4"},
+		},
+		LongportProfile: "sg",
+	}
+	client, err := NewLongportClient(LongportConfigFrom(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := client.Accounts(); len(got) != 3 || got[0] != config.LongportDefaultAccount {
+		t.Fatalf("accounts = %v", got)
+	}
+	names := func(accts []*longportAccount) string {
+		var out []string
+		for _, a := range accts {
+			out = append(out, a.Name)
+		}
+		return strings.Join(out, ",")
+	}
+	cases := map[string]string{"US": "us,sg,default", "HK": "default,us,sg", "SG": "sg,default,us", "": "default,us,sg"}
+	for market, want := range cases {
+		if got := names(client.route(market)); got != want {
+			t.Errorf("route(%q) = %s, want %s", market, got, want)
+		}
+	}
+	if client.profile.Name != "sg" {
+		t.Errorf("profile = %s", client.profile.Name)
+	}
+	if other, err := client.WithProfile("us"); err != nil || other.profile.Name != "us" || client.profile.Name != "sg" {
+		t.Errorf("WithProfile: %v", err)
+	}
+	if _, err := client.WithProfile("nope"); err == nil {
+		t.Error("want an error for an unknown profile")
+	}
+	cfg.LongportProfile = "nope"
+	if _, err := NewLongportClient(LongportConfigFrom(cfg)); err == nil {
+		t.Error("want an error for an unknown configured profile")
+	}
+
+	order, groups := groupByMarket([]string{"AAPL.US", "700.HK", "TSLA.US"})
+	if strings.Join(order, ",") != "US,HK" || len(groups["US"]) != 2 {
+		t.Errorf("groups = %v %v", order, groups)
+	}
+}
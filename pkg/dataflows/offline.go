@@ -0,0 +1,36 @@
+package dataflows
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ErrOffline is wrapped by every error returned because offline mode needed
+// data that is not available in the local cache or archives.
+var ErrOffline = errors.New("offline mode: data not available locally")
+
+// OfflineMissError names the data offline mode could not find locally.
+type OfflineMissError struct {
+	Source string
+	Key    string
+}
+
+func (e *OfflineMissError) Error() string {
+	return fmt.Sprintf("offline mode: no local %s data for %q", e.Source, e.Key)
+}
+
+func (e *OfflineMissError) Unwrap() error {
+	return ErrOffline
+}
+
+func offlineMiss(source, key string) error {
+	return &OfflineMissError{Source: source, Key: key}
+}
+
+// offlineGuard is installed on HTTP clients in offline mode as a backstop so
+// no request leaves the machine even from code paths without an explicit check.
+func offlineGuard(_ *resty.Client, req *resty.Request) error {
+	return offlineMiss("network", req.URL)
+}
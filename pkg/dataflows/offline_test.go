@@ -0,0 +1,40 @@
+package dataflows
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheManagerOfflineIgnoresTTL(t *testing.T) {
+	dir := t.TempDir()
+	cm := NewCacheManager(dir, time.Nanosecond, true)
+	if err := cm.Set("src", "method", "key", map[string]string{"v": "1"}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	var got map[string]string
+	cm.offline = true
+	if !cm.Get("src", "method", "key", &got) || got["v"] != "1" {
+		t.Fatalf("expected expired entry to be served offline, got %v", got)
+	}
+	cm.offline = false
+	if cm.Get("src", "method", "key", &got) {
+		t.Fatal("expected expired entry to miss online")
+	}
+}
+
+func TestWithRetryStopsOnOffline(t *testing.T) {
+	calls := 0
+	err := WithRetry(&RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1}, func() error {
+		calls++
+		return offlineMiss("news", "AAPL")
+	})
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls)
+	}
+}
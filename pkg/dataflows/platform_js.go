@@ -0,0 +1,148 @@
+//go:build js && wasm
+
+package dataflows
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"syscall/js"
+)
+
+// hasFilesystem is false in the browser: wasm_exec.js stubs file writes out.
+const hasFilesystem = false
+
+var (
+	memStoresMu sync.Mutex
+	memStores   = map[string]*memStore{}
+)
+
+// newCacheStore returns the in-memory store for dir, shared by every client
+// created with the same cache directory so entries survive client re-creation.
+func newCacheStore(dir string) cacheStore {
+	memStoresMu.Lock()
+	defer memStoresMu.Unlock()
+	s, ok := memStores[dir]
+	if !ok {
+		s = newMemStore()
+		memStores[dir] = s
+	}
+	return s
+}
+
+func defaultTransport() http.RoundTripper { return &FetchTransport{} }
+
+// FetchTransport sends requests through the host's global fetch. Unlike the
+// net/http js transport it is also used under Node and Electron, where Go
+// disables fetch. Proxy, when set, is prepended to every URL for hosts that
+// need a CORS proxy (e.g. "https://proxy.example.com/").
+type FetchTransport struct {
+	Proxy string
+}
+
+// RoundTrip implements http.RoundTripper. It blocks on the JS promise, so it
+// must run on a goroutine other than the one serving a JS callback.
+func (t *FetchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fetch := js.Global().Get("fetch")
+	if fetch.Type() != js.TypeFunction {
+		return nil, errors.New("fetch is not available in this JS host")
+	}
+
+	opts := js.Global().Get("Object").New()
+	opts.Set("method", req.Method)
+	headers := js.Global().Get("Headers").New()
+	for key, values := range req.Header {
+		for _, v := range values {
+			headers.Call("append", key, v)
+		}
+	}
+	opts.Set("headers", headers)
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > 0 {
+			buf := js.Global().Get("Uint8Array").New(len(body))
+			js.CopyBytesToJS(buf, body)
+			opts.Set("body", buf)
+		}
+	}
+	if ac := js.Global().Get("AbortController"); ac.Type() == js.TypeFunction {
+		ctrl := ac.New()
+		opts.Set("signal", ctrl.Get("signal"))
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			select {
+			case <-req.Context().Done():
+				ctrl.Call("abort")
+			case <-finished:
+			}
+		}()
+	}
+
+	res, err := await(fetch.Invoke(t.Proxy+req.URL.String(), opts))
+	if err != nil {
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, fmt.Errorf("fetch %s: %w", req.URL, err)
+	}
+
+	header := http.Header{}
+	each := js.FuncOf(func(_ js.Value, args []js.Value) any {
+		header.Add(args[1].String(), args[0].String())
+		return nil
+	})
+	res.Get("headers").Call("forEach", each)
+	each.Release()
+
+	ab, err := await(res.Call("arrayBuffer"))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", req.URL, err)
+	}
+	data := make([]byte, ab.Get("byteLength").Int())
+	js.CopyBytesToGo(data, js.Global().Get("Uint8Array").New(ab))
+
+	code := res.Get("status").Int()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, res.Get("statusText").String()),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}, nil
+}
+
+// await blocks until promise settles.
+func await(promise js.Value) (js.Value, error) {
+	var (
+		value js.Value
+		err   error
+		done  = make(chan struct{})
+	)
+	onResolve := js.FuncOf(func(_ js.Value, args []js.Value) any {
+		value = args[0]
+		close(done)
+		return nil
+	})
+	onReject := js.FuncOf(func(_ js.Value, args []js.Value) any {
+		err = errors.New(args[0].Call("toString").String())
+		close(done)
+		return nil
+	})
+	defer onResolve.Release()
+	defer onReject.Release()
+	promise.Call("then", onResolve, onReject)
+	<-done
+	return value, err
+}
@@ -0,0 +1,26 @@
+//go:build !(js && wasm)
+
+package dataflows
+
+import (
+	"net/http"
+	"time"
+)
+
+// hasFilesystem reports whether archives such as news_data can be written.
+const hasFilesystem = true
+
+// defaultTransport is one connection pool for every data source, negotiating
+// HTTP/2 where the server offers it and keeping the environment's proxy.
+func defaultTransport() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.ForceAttemptHTTP2 = true
+	t.MaxIdleConns = 100
+	t.MaxIdleConnsPerHost = 10
+	t.IdleConnTimeout = 90 * time.Second
+	t.TLSHandshakeTimeout = 10 * time.Second
+	t.ResponseHeaderTimeout = 20 * time.Second
+	return t
+}
+
+func newCacheStore(dir string) cacheStore { return fileStore{dir: dir} }
@@ -0,0 +1,270 @@
+package dataflows
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ErrDisallowed is returned for pages a site's robots.txt asks crawlers to
+// stay away from.
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// Politeness defaults. The user-agent token is what robots.txt groups are
+// matched against; a site's own Crawl-delay wins when it is longer than the
+// configured gap, up to maxCrawlDelay.
+const (
+	crawlerToken      = "cortexgo"
+	defaultCrawlDelay = 2 * time.Second
+	maxCrawlDelay     = 30 * time.Second
+	robotsTTL         = 24 * time.Hour
+	// a robots.txt that cannot be fetched (5xx, network) blocks the host
+	// for this long, as RFC 9309 asks, before it is tried again
+	robotsRetryAfter = time.Hour
+)
+
+// crawlPolicy is how politely a client fetches article pages.
+type crawlPolicy struct {
+	delay        time.Duration
+	ignoreRobots bool
+}
+
+func newCrawlPolicy(config *Config) crawlPolicy {
+	p := crawlPolicy{delay: defaultCrawlDelay, ignoreRobots: config.IgnoreRobots}
+	if config.CrawlDelay > 0 {
+		p.delay = time.Duration(config.CrawlDelay) * time.Second
+	}
+	return p
+}
+
+// robotsRules is the robots.txt group that applies to us.
+type robotsRules struct {
+	rules       []robotsRule
+	crawlDelay  time.Duration
+	disallowAll bool
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// hostState is shared by every client in the process, since the tools build
+// a new client per call while the site sees a single crawler.
+type hostState struct {
+	mu      sync.Mutex
+	robots  *robotsRules
+	expires time.Time
+	next    time.Time // earliest start of the next request
+}
+
+var (
+	hostsMu sync.Mutex
+	hosts   = map[string]*hostState{}
+)
+
+func stateFor(host string) *hostState {
+	hostsMu.Lock()
+	defer hostsMu.Unlock()
+	h, ok := hosts[host]
+	if !ok {
+		h = &hostState{}
+		hosts[host] = h
+	}
+	return h
+}
+
+// admit blocks until a request to rawURL is polite to send: robots.txt allows
+// the path and the host's crawl delay has passed since the previous request.
+func (p crawlPolicy) admit(client *resty.Client, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid article URL %q", rawURL)
+	}
+	h := stateFor(strings.ToLower(u.Host))
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delay := p.delay
+	if !p.ignoreRobots {
+		if h.robots == nil || time.Now().After(h.expires) {
+			h.wait(delay)
+			h.robots, h.expires = fetchRobots(client, u)
+		}
+		path := u.EscapedPath()
+		if u.RawQuery != "" {
+			path += "?" + u.RawQuery
+		}
+		if !h.robots.allows(path) {
+			return fmt.Errorf("%s: %w", rawURL, ErrDisallowed)
+		}
+		siteDelay := h.robots.crawlDelay
+		if siteDelay > maxCrawlDelay {
+			siteDelay = maxCrawlDelay
+		}
+		if siteDelay > delay {
+			delay = siteDelay
+		}
+	}
+	h.wait(delay)
+	return nil
+}
+
+// wait sleeps until the host may be contacted again and books the next slot.
+func (h *hostState) wait(delay time.Duration) {
+	if d := time.Until(h.next); d > 0 {
+		time.Sleep(d)
+	}
+	h.next = time.Now().Add(delay)
+}
+
+// fetchRobots downloads and parses the host's robots.txt. A missing file
+// (4xx) allows everything; an unreachable one disallows everything for
+// robotsRetryAfter.
+func fetchRobots(client *resty.Client, u *url.URL) (*robotsRules, time.Time) {
+	resp, err := client.R().Get(u.Scheme + "://" + u.Host + "/robots.txt")
+	switch {
+	case err != nil || resp.StatusCode() >= 500:
+		return &robotsRules{disallowAll: true}, time.Now().Add(robotsRetryAfter)
+	case resp.StatusCode() >= 400:
+		return &robotsRules{}, time.Now().Add(robotsTTL)
+	}
+	return parseRobots(resp.String(), crawlerToken), time.Now().Add(robotsTTL)
+}
+
+// parseRobots extracts the group for agent (by case-insensitive substring),
+// falling back to the "*" group.
+func parseRobots(body, agent string) *robotsRules {
+	var (
+		specific, wildcard *robotsRules
+		current            []*robotsRules
+		inAgents           bool
+	)
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if key == "user-agent" {
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			name := strings.ToLower(value)
+			switch {
+			case name == "*":
+				if wildcard == nil {
+					wildcard = &robotsRules{}
+				}
+				current = append(current, wildcard)
+			case strings.Contains(agent, name) || strings.Contains(name, agent):
+				if specific == nil {
+					specific = &robotsRules{}
+				}
+				current = append(current, specific)
+			}
+			continue
+		}
+		inAgents = false
+		for _, r := range current {
+			switch key {
+			case "allow", "disallow":
+				if value != "" {
+					r.rules = append(r.rules, robotsRule{allow: key == "allow", pattern: value})
+				}
+			case "crawl-delay":
+				if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+					r.crawlDelay = time.Duration(secs * float64(time.Second))
+				}
+			}
+		}
+	}
+	if specific != nil {
+		return specific
+	}
+	if wildcard != nil {
+		return wildcard
+	}
+	return &robotsRules{}
+}
+
+// allows applies the longest matching rule; Allow wins a tie.
+func (r *robotsRules) allows(path string) bool {
+	if r.disallowAll {
+		return false
+	}
+	if path == "" {
+		path = "/"
+	}
+	allowed, best := true, -1
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > best || n == best && rule.allow {
+			allowed, best = rule.allow, n
+		}
+	}
+	return allowed
+}
+
+// robotsMatch matches a robots.txt path pattern, where * is any run of
+// characters and a trailing $ anchors the end.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	last := parts[len(parts)-1]
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
+
+// pageValidators remember what a page looked like at its last fetch, so the
+// next fetch can be a conditional GET answered with 304 Not Modified.
+type pageValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Content      string `json:"content"`
+}
+
+// validatorTTL is how long validators are kept; well past the article
+// content cache, which is what makes them useful.
+const validatorTTL = 30 * 24 * time.Hour
+
+func (v pageValidators) apply(req *resty.Request) *resty.Request {
+	if v.ETag != "" {
+		req.SetHeader("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		req.SetHeader("If-Modified-Since", v.LastModified)
+	}
+	return req
+}
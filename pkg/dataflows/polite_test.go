@@ -0,0 +1,97 @@
+package dataflows
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRobots(t *testing.T) {
+	body := `
+User-agent: *
+Disallow: /
+
+# our own group takes precedence over *
+User-agent: Googlebot
+User-agent: CortexGo
+Disallow: /private
+Disallow: /*.pdf$
+Allow: /private/press
+Crawl-delay: 5
+`
+	rules := parseRobots(body, crawlerToken)
+	if rules.crawlDelay != 5*time.Second {
+		t.Errorf("crawl delay = %v, want 5s", rules.crawlDelay)
+	}
+	cases := map[string]bool{
+		"/":                     true,
+		"/markets/story":        true,
+		"/private":              false,
+		"/private/memo":         false,
+		"/private/press/q3":     true,
+		"/files/report.pdf":     false,
+		"/files/report.pdf?x=1": true,
+	}
+	for path, want := range cases {
+		if got := rules.allows(path); got != want {
+			t.Errorf("allows(%q) = %v, want %v", path, got, want)
+		}
+	}
+
+	if parseRobots(body, "otherbot").allows("/markets") {
+		t.Error("other agents should fall back to the * group")
+	}
+	if !parseRobots("", crawlerToken).allows("/anything") {
+		t.Error("empty robots.txt should allow everything")
+	}
+}
+
+func TestGetArticleContentPolitely(t *testing.T) {
+	var fetches, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			w.Write([]byte("User-agent: *\nDisallow: /members\n"))
+		default:
+			fetches.Add(1)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified.Add(1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte("<html><body><article>" + strings.Repeat("<p>The stock rallied after the company raised its full-year outlook on strong demand.</p>", 3) + "</article></body></html>"))
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &Config{DataCacheDir: t.TempDir(), CacheEnabled: true, CrawlDelay: 1}
+	gnc := NewGoogleNewsClient(cfg)
+	if _, err := gnc.GetArticleContent(srv.URL + "/members/story"); !errors.Is(err, ErrDisallowed) {
+		t.Fatalf("disallowed page: err = %v", err)
+	}
+
+	first, err := gnc.GetArticleContent(srv.URL + "/story")
+	if err != nil || first == "" {
+		t.Fatalf("first fetch: %q, %v", first, err)
+	}
+	// once the content cache is gone, the stored ETag makes the refetch a 304
+	os.RemoveAll(filepath.Join(cfg.DataCacheDir, "google_news"))
+	start := time.Now()
+	second, err := gnc.GetArticleContent(srv.URL + "/story")
+	if err != nil || second != first {
+		t.Fatalf("conditional fetch: %q, %v", second, err)
+	}
+	if notModified.Load() != 1 || fetches.Load() != 2 {
+		t.Errorf("fetches = %d, 304s = %d; want 2 and 1", fetches.Load(), notModified.Load())
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("second request to the host after %v, want the 1s crawl delay", elapsed)
+	}
+}
@@ -0,0 +1,91 @@
+package dataflows
+
+import (
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// Provenance reports how the news searches so far were served: live, from
+// the cache, or simulated in offline mode.
+func (gnc *GoogleNewsClient) Provenance() models.Provenance {
+	if gnc.sim != nil {
+		return gnc.sim.Provenance("google_news")
+	}
+	return gnc.cache.Provenance("google_news")
+}
+
+// Provenance reports how the subreddit fetches so far were served: live,
+// from the cache, or simulated in offline mode.
+func (rc *RedditClient) Provenance() models.Provenance {
+	if rc.sim != nil {
+		return rc.sim.Provenance("reddit")
+	}
+	return rc.cache.Provenance("reddit")
+}
+
+// Provenance reports whether the earnings call transcripts came from FMP and
+// Finnhub or the cache.
+func (tc *TranscriptsClient) Provenance() models.Provenance {
+	return tc.cache.Provenance("transcripts")
+}
+
+// Provenance reports whether the index constituent lists were fetched or
+// read from the cache.
+func (cc *ConstituentsClient) Provenance() models.Provenance {
+	return cc.cache.Provenance("constituents")
+}
+
+// Provenance reports whether the Treasury yield curves were fetched or read
+// from the cache.
+func (tc *TreasuryClient) Provenance() models.Provenance {
+	return tc.cache.Provenance("treasury")
+}
+
+// Provenance reports whether the insider transactions came from Finnhub or
+// the cache.
+func (ic *InsiderClient) Provenance() models.Provenance {
+	return ic.cache.Provenance("insider")
+}
+
+// Provenance reports whether the earnings and economic calendars came from
+// Finnhub or the cache.
+func (cc *CalendarClient) Provenance() models.Provenance {
+	return cc.cache.Provenance("calendar")
+}
+
+// Provenance reports whether the exchange rates came from the ECB or the
+// cache.
+func (fc *FXClient) Provenance() models.Provenance {
+	return fc.cache.Provenance("fx")
+}
+
+// ArticlesAsOf is the publication date of the newest article, "" when none
+// is dated.
+func ArticlesAsOf(articles []*NewsArticle) string {
+	var newest time.Time
+	for _, a := range articles {
+		if a != nil && a.PublishedAt.After(newest) {
+			newest = a.PublishedAt
+		}
+	}
+	return asOfDate(newest)
+}
+
+// PostsAsOf is the creation date of the newest post, "" when none is dated.
+func PostsAsOf(posts []*RedditPost) string {
+	var newest time.Time
+	for _, p := range posts {
+		if p != nil && p.CreatedAt.After(newest) {
+			newest = p.CreatedAt
+		}
+	}
+	return asOfDate(newest)
+}
+
+func asOfDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -2,6 +2,7 @@ package dataflows
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"path/filepath"
@@ -16,20 +17,19 @@ import (
 type RedditClient struct {
 	client *resty.Client
 	cache  *CacheManager
+	sim    *Simulator // non-nil in simulated data mode
 }
 
 // NewRedditClient creates a new Reddit client
 func NewRedditClient(config *Config) *RedditClient {
-	cacheDir := filepath.Join(config.DataCacheDir, "reddit")
-	cache := NewCacheManager(cacheDir, 1*time.Hour, config.CacheEnabled) // 1 hour cache for Reddit
+	cache := newCacheManager(config, "reddit", 1*time.Hour) // 1 hour cache for Reddit
 
-	client := resty.New()
-	client.SetTimeout(30 * time.Second)
-	client.SetHeader("User-Agent", "CortexGo/1.0 (by /u/cortexgo)")
+	client := newHTTPClient(config, "CortexGo/1.0 (by /u/cortexgo)")
 
 	return &RedditClient{
 		client: client,
 		cache:  cache,
+		sim:    NewSimulator(config),
 	}
 }
 
@@ -98,6 +98,9 @@ func (rc *RedditClient) GetSubredditPosts(subreddit string, sort string, limit i
 	if limit <= 0 || limit > 100 {
 		limit = 25
 	}
+	if rc.sim != nil {
+		return rc.sim.SubredditPosts(subreddit, sort, limit), nil
+	}
 
 	// Check cache first
 	cacheKey := fmt.Sprintf("%s_%s_%d", subreddit, sort, limit)
@@ -105,6 +108,9 @@ func (rc *RedditClient) GetSubredditPosts(subreddit string, sort string, limit i
 	if rc.cache.Get("subreddit", "posts", cacheKey, &cached) {
 		return cached, nil
 	}
+	if rc.cache.offline {
+		return nil, offlineMiss("reddit", "r/"+subreddit+"/"+sort)
+	}
 
 	// Build Reddit URL
 	redditURL := fmt.Sprintf("https://www.reddit.com/r/%s/%s.json?limit=%d", subreddit, sort, limit)
@@ -164,12 +170,18 @@ func (rc *RedditClient) SearchReddit(params RedditSearchParams, config *Config)
 	if params.MaxResults <= 0 {
 		params.MaxResults = 50
 	}
+	if rc.sim != nil {
+		return rc.sim.Posts(params.Query, params.Subreddit, min(params.Limit, params.MaxResults)), nil
+	}
 
 	// Check cache first
 	var cached []*RedditPost
 	if rc.cache.Get("search", "query", params, &cached) {
 		return cached, nil
 	}
+	if rc.cache.offline {
+		return nil, offlineMiss("reddit search", params.Query)
+	}
 
 	var allResults []*RedditPost
 	after := params.After
@@ -246,6 +258,9 @@ func (rc *RedditClient) GetPopularFinancePosts(limit int, config *Config) ([]*Re
 
 	for _, subreddit := range financeSubreddits {
 		posts, err := rc.GetSubredditPosts(subreddit, "hot", postsPerSub, config)
+		if errors.Is(err, ErrOffline) {
+			return nil, err
+		}
 		if err != nil {
 			// Log error but continue with other subreddits
 			continue
@@ -277,6 +292,9 @@ func (rc *RedditClient) GetStockMentions(symbol string, config *Config) ([]*Redd
 
 	// Clean and format symbol
 	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if rc.sim != nil {
+		return rc.sim.Posts(symbol, "", 15), nil
+	}
 
 	// Create search queries for the symbol
 	queries := []string{
@@ -299,6 +317,9 @@ func (rc *RedditClient) GetStockMentions(symbol string, config *Config) ([]*Redd
 		}
 
 		posts, err := rc.SearchReddit(params, config)
+		if errors.Is(err, ErrOffline) {
+			return nil, err
+		}
 		if err != nil {
 			continue // Skip this query if it fails
 		}
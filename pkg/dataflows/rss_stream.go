@@ -0,0 +1,80 @@
+package dataflows
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Limits on a single feed. Reading stops at whichever comes first; the items
+// decoded up to that point are kept.
+const (
+	maxFeedBytes = 8 << 20
+	maxFeedItems = 500
+)
+
+// decodeRSSItems streams the <item> elements of an RSS document from r,
+// passing each to fn until fn returns false or a limit is reached, so a large
+// feed is never held in memory as a whole. Decoding is lenient about HTML
+// entities such as &nbsp; and stray ampersands. Items with neither a title nor a link are
+// skipped, and a feed that breaks off midway (malformed XML or the size cap)
+// yields the items before the break; an error is returned only when not a
+// single item could be read from it.
+func decodeRSSItems(r io.Reader, fn func(Item) bool) error {
+	d := xml.NewDecoder(io.LimitReader(r, maxFeedBytes))
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+
+	root, items := "", 0
+	for items < maxFeedItems {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if items > 0 {
+				break
+			}
+			return fmt.Errorf("failed to parse RSS XML: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if root == "" {
+			root = start.Name.Local
+			if root != "rss" && root != "RDF" {
+				return fmt.Errorf("failed to parse RSS XML: root element is <%s>, not <rss>", root)
+			}
+			continue
+		}
+		if start.Name.Local != "item" {
+			continue
+		}
+
+		var item Item
+		if err := d.DecodeElement(&item, &start); err != nil {
+			var syntax *xml.SyntaxError
+			if !errors.As(err, &syntax) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				continue
+			}
+			if items > 0 {
+				break
+			}
+			return fmt.Errorf("failed to parse RSS XML: %w", err)
+		}
+		if strings.TrimSpace(item.Title) == "" && strings.TrimSpace(item.Link) == "" {
+			continue
+		}
+		items++
+		if !fn(item) {
+			break
+		}
+	}
+	if root == "" {
+		return errors.New("failed to parse RSS XML: empty document")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package dataflows
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func collectRSS(t *testing.T, doc string, limit int) ([]Item, error) {
+	t.Helper()
+	var items []Item
+	err := decodeRSSItems(strings.NewReader(doc), func(item Item) bool {
+		items = append(items, item)
+		return limit <= 0 || len(items) < limit
+	})
+	return items, err
+}
+
+func TestDecodeRSSItems(t *testing.T) {
+	doc := `<?xml version="1.0"?><rss version="2.0"><channel><title>Feed</title>
+<item><title>Fed holds rates&nbsp;steady</title><link>https://example.com/a</link><guid>a</guid></item>
+<item><description>no title or link</description></item>
+<item><title>AT&T and chipmakers rally</title><link>https://example.com/b</link><source url="https://example.com">Example</source></item>
+</channel></rss>`
+	items, err := collectRSS(t, doc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[1].Source.Text != "Example" || !strings.HasPrefix(items[0].Title, "Fed holds rates") {
+		t.Fatalf("items = %+v", items)
+	}
+
+	if items, _ := collectRSS(t, doc, 1); len(items) != 1 {
+		t.Errorf("stop after first item: got %d", len(items))
+	}
+}
+
+func TestDecodeRSSItemsKeepsItemsBeforeBreak(t *testing.T) {
+	truncated := `<rss><channel><item><title>One</title><link>https://example.com/1</link></item><item><title>Tw`
+	items, err := collectRSS(t, truncated, 0)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("truncated feed: %d items, %v", len(items), err)
+	}
+
+	if _, err := collectRSS(t, `<html><body>Service Unavailable</body></html>`, 0); err == nil {
+		t.Error("expected an error for an HTML page")
+	}
+	if _, err := collectRSS(t, "", 0); err == nil {
+		t.Error("expected an error for an empty body")
+	}
+}
+
+func TestDecodeRSSItemsLimit(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("<rss><channel>")
+	for i := range maxFeedItems + 50 {
+		fmt.Fprintf(&b, "<item><title>Story %d</title><link>https://example.com/%d</link></item>", i, i)
+	}
+	b.WriteString("</channel></rss>")
+	items, err := collectRSS(t, b.String(), 0)
+	if err != nil || len(items) != maxFeedItems {
+		t.Fatalf("got %d items, %v; want %d", len(items), err, maxFeedItems)
+	}
+}
@@ -0,0 +1,70 @@
+package dataflows
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Finance-oriented word lists, loosely following the Loughran-McDonald
+// dictionary. Good enough to rank headlines; not a substitute for the
+// analysts' LLM reading of the articles.
+var (
+	positiveWords = wordSet(`beat beats beating surge surges surged soar soars soared rally rallies rallied
+		gain gains gained jump jumps jumped rise rises rising rose record upgrade upgrades upgraded
+		outperform outperforms bullish strong stronger growth grow grows profit profits profitable
+		boost boosts boosted exceed exceeds exceeded raise raises raised buy approval approved
+		breakthrough expand expands expansion win wins won positive optimistic rebound rebounds recovery
+		higher top tops dividend partnership launch launches`)
+	negativeWords = wordSet(`miss misses missed plunge plunges plunged drop drops dropped fall falls fell
+		slump slumps slumped tumble tumbles tumbled crash crashes crashed downgrade downgrades downgraded
+		underperform bearish weak weaker loss losses decline declines declined cut cuts lawsuit lawsuits
+		sue sued probe investigation fraud recall recalls layoff layoffs bankruptcy default warning warns
+		warned risk risks concern concerns fear fears sell selloff lower negative pessimistic halt halted
+		delay delays delayed fine fined penalty scandal slowdown`)
+	negators = wordSet(`not no never without fails fail failed`)
+)
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// ScoreSentiment returns a lexicon-based sentiment score in [-1, 1]: positive
+// minus negative hits normalised by the total hits, with a negator in the
+// preceding two words flipping the polarity. Text without hits scores 0.
+func ScoreSentiment(text string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+	var pos, neg float64
+	for i, w := range words {
+		polarity := 0.0
+		switch {
+		case positiveWords[w]:
+			polarity = 1
+		case negativeWords[w]:
+			polarity = -1
+		default:
+			continue
+		}
+		for j := i - 1; j >= 0 && j >= i-2; j-- {
+			if negators[words[j]] {
+				polarity = -polarity
+				break
+			}
+		}
+		if polarity > 0 {
+			pos++
+		} else {
+			neg++
+		}
+	}
+	if pos+neg == 0 {
+		return 0
+	}
+	return math.Round((pos-neg)/(pos+neg)*100) / 100
+}
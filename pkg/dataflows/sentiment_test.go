@@ -0,0 +1,21 @@
+package dataflows
+
+import "testing"
+
+func TestScoreSentiment(t *testing.T) {
+	cases := []struct {
+		text string
+		want float64
+	}{
+		{"Apple beats estimates, shares surge to record", 1},
+		{"Tesla shares plunge after earnings miss", -1},
+		{"Nvidia gains despite chip export concerns", 0},
+		{"Company did not miss guidance", 1},
+		{"Quarterly report scheduled for Thursday", 0},
+	}
+	for _, c := range cases {
+		if got := ScoreSentiment(c.text); got != c.want {
+			t.Errorf("ScoreSentiment(%q) = %v, want %v", c.text, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package dataflows
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Session shares market-wide data between the analyses of a batch: values
+// that do not depend on the symbol (the market regime, finance headlines,
+// the yield curve) are fetched by the first analysis that needs them and
+// reused by the rest. Concurrent requests for the same key wait for the
+// first fetch instead of repeating it. Failures are not kept, so a later
+// analysis retries.
+type Session struct {
+	mu      sync.Mutex
+	entries map[string]*sessionEntry
+	tools   map[string]bool
+	hits    int
+	misses  int
+}
+
+type sessionEntry struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+// SessionStats counts how often a session answered from memory.
+type SessionStats struct {
+	Hits   int `json:"hits"`
+	Misses int `json:"misses"`
+}
+
+// NewSession returns an empty session whose tool outputs are shared for the
+// named tools only.
+func NewSession(tools ...string) *Session {
+	s := &Session{entries: map[string]*sessionEntry{}, tools: map[string]bool{}}
+	for _, name := range tools {
+		s.tools[name] = true
+	}
+	return s
+}
+
+type sessionKey struct{}
+
+// WithSession attaches s to ctx; analyses run under ctx share its data.
+func WithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+// SessionFrom returns the session attached to ctx, or nil.
+func SessionFrom(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionKey{}).(*Session)
+	return s
+}
+
+// SharesTool reports whether outputs of the named tool are shared. A nil
+// session shares nothing.
+func (s *Session) SharesTool(name string) bool {
+	return s != nil && s.tools[name]
+}
+
+// Do returns the value stored under key, calling fetch to produce it the
+// first time. A nil session always calls fetch.
+func (s *Session) Do(key string, fetch func() (any, error)) (any, error) {
+	if s == nil {
+		return fetch()
+	}
+	s.mu.Lock()
+	if e, ok := s.entries[key]; ok {
+		s.mu.Unlock()
+		<-e.done
+		if e.err == nil {
+			s.mu.Lock()
+			s.hits++
+			s.mu.Unlock()
+			return e.value, nil
+		}
+		// the first fetch failed and was dropped; try again
+		return s.Do(key, fetch)
+	}
+	e := &sessionEntry{done: make(chan struct{})}
+	s.entries[key] = e
+	s.misses++
+	s.mu.Unlock()
+
+	// a panicking fetch must not leave the waiters blocked
+	e.err = errFetchIncomplete
+	defer func() {
+		if e.err != nil {
+			s.mu.Lock()
+			delete(s.entries, key)
+			s.mu.Unlock()
+		}
+		close(e.done)
+	}()
+	e.value, e.err = fetch()
+	return e.value, e.err
+}
+
+var errFetchIncomplete = errors.New("shared fetch did not complete")
+
+// Stats returns the session's hit and miss counts.
+func (s *Session) Stats() SessionStats {
+	if s == nil {
+		return SessionStats{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SessionStats{Hits: s.hits, Misses: s.misses}
+}
@@ -0,0 +1,81 @@
+package dataflows
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSessionFetchesOnce(t *testing.T) {
+	s := NewSession("get_top_headlines")
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fetch := func() (any, error) {
+		calls.Add(1)
+		<-release
+		return "headlines", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]any, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = s.Do("tool:get_top_headlines:{}", fetch)
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("fetch called %d times, want 1", n)
+	}
+	for i, r := range results {
+		if r != "headlines" {
+			t.Errorf("result %d = %v", i, r)
+		}
+	}
+	if st := s.Stats(); st.Misses != 1 || st.Hits != 7 {
+		t.Errorf("stats = %+v, want 1 miss and 7 hits", st)
+	}
+}
+
+func TestSessionRetriesFailures(t *testing.T) {
+	s := NewSession()
+	boom := errors.New("boom")
+	if _, err := s.Do("regime:US:2026-10-16", func() (any, error) { return nil, boom }); !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	v, err := s.Do("regime:US:2026-10-16", func() (any, error) { return "ok", nil })
+	if err != nil || v != "ok" {
+		t.Fatalf("retry = %v, %v; want ok", v, err)
+	}
+
+	func() {
+		defer func() { _ = recover() }()
+		s.Do("panics", func() (any, error) { panic("fetch crashed") })
+	}()
+	if v, err := s.Do("panics", func() (any, error) { return 1, nil }); err != nil || v != 1 {
+		t.Fatalf("after panic = %v, %v; want a fresh fetch", v, err)
+	}
+}
+
+func TestNilSession(t *testing.T) {
+	s := SessionFrom(context.Background())
+	if s.SharesTool("get_top_headlines") {
+		t.Error("nil session shares tools")
+	}
+	calls := 0
+	for range 2 {
+		s.Do("k", func() (any, error) { calls++; return nil, nil })
+	}
+	if calls != 2 {
+		t.Errorf("nil session fetched %d times, want every call", calls)
+	}
+	if got := SessionFrom(WithSession(context.Background(), NewSession("x"))); !got.SharesTool("x") {
+		t.Error("session not attached to context")
+	}
+}
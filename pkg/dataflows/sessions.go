@@ -0,0 +1,167 @@
+package dataflows
+
+import (
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/longportapp/openapi-go/quote"
+	"github.com/shopspring/decimal"
+)
+
+// Market sessions a timestamp can fall into. Reactions differ by session:
+// pre-market and after-hours moves trade on thin volume and are often
+// revised at the open.
+const (
+	SessionPreMarket  = "pre-market"
+	SessionIntraday   = "intraday"
+	SessionAfterHours = "after-hours"
+	SessionOvernight  = "overnight"
+	SessionClosed     = "closed"
+)
+
+// sessionWindow is a [start, end) range in minutes after local midnight.
+type sessionWindow struct {
+	start, end int
+	session    string
+}
+
+// sessionHours lists each market's sessions in exchange time; anything
+// outside them is closed. HK and A-share pre-market windows are the opening
+// auctions, and HK after-hours is the closing auction.
+var sessionHours = map[string][]sessionWindow{
+	"US": {
+		{0, 4 * 60, SessionOvernight},
+		{4 * 60, 9*60 + 30, SessionPreMarket},
+		{9*60 + 30, 16 * 60, SessionIntraday},
+		{16 * 60, 20 * 60, SessionAfterHours},
+		{20 * 60, 24 * 60, SessionOvernight},
+	},
+	"HK": {
+		{9 * 60, 9*60 + 30, SessionPreMarket},
+		{9*60 + 30, 12 * 60, SessionIntraday},
+		{13 * 60, 16 * 60, SessionIntraday},
+		{16 * 60, 16*60 + 10, SessionAfterHours},
+	},
+	"CN": {
+		{9*60 + 15, 9*60 + 30, SessionPreMarket},
+		{9*60 + 30, 11*60 + 30, SessionIntraday},
+		{13 * 60, 15 * 60, SessionIntraday},
+	},
+}
+
+// SessionMarket maps a ticker's suffix to the market whose trading hours
+// apply; bare tickers are treated as US listings.
+func SessionMarket(symbol string) string {
+	_, suffix, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(symbol)), ".")
+	if !ok {
+		return "US"
+	}
+	switch suffix {
+	case "HK":
+		return "HK"
+	case "SH", "SZ":
+		return "CN"
+	}
+	return "US"
+}
+
+// MarketSession reports which session of symbol's market t falls into.
+// Exchange holidays are not known here and count as regular weekdays.
+func MarketSession(symbol string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	market := SessionMarket(symbol)
+	local := ExchangeTime(market, t)
+	weekday := local.Weekday()
+	minute := local.Hour()*60 + local.Minute()
+	if market == "US" {
+		// overnight trading runs Sunday 20:00 to Friday 04:00 ET
+		switch {
+		case weekday == time.Saturday,
+			weekday == time.Sunday && minute < 20*60,
+			weekday == time.Friday && minute >= 20*60:
+			return SessionClosed
+		case weekday == time.Sunday:
+			return SessionOvernight
+		}
+	} else if weekday == time.Saturday || weekday == time.Sunday {
+		return SessionClosed
+	}
+	for _, w := range sessionHours[market] {
+		if minute >= w.start && minute < w.end {
+			return w.session
+		}
+	}
+	return SessionClosed
+}
+
+// ExchangeTime converts t to the exchange's local time. US Eastern time is
+// computed from the DST rules so no tz database is needed (js/wasm builds
+// ship without one).
+func ExchangeTime(market string, t time.Time) time.Time {
+	if market != "US" {
+		return t.In(time.FixedZone("UTC+8", 8*3600))
+	}
+	year := t.UTC().Year()
+	// DST starts the second Sunday of March and ends the first Sunday of
+	// November, both at 02:00 local time
+	start := nthSunday(year, time.March, 2).Add(2*time.Hour + 5*time.Hour)
+	end := nthSunday(year, time.November, 1).Add(2*time.Hour + 4*time.Hour)
+	if !t.Before(start) && t.Before(end) {
+		return t.In(time.FixedZone("EDT", -4*3600))
+	}
+	return t.In(time.FixedZone("EST", -5*3600))
+}
+
+// nthSunday returns midnight UTC of the nth Sunday of month.
+func nthSunday(year int, month time.Month, n int) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	offset := (7 - int(first.Weekday())) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// ExtendedQuotes extracts the pre-market, after-hours and overnight
+// sessions from a Longport quote, skipping sessions without trades.
+func ExtendedQuotes(q *quote.SecurityQuote) []models.ExtendedQuote {
+	var out []models.ExtendedQuote
+	for _, s := range []struct {
+		session string
+		prices  *quote.PrePostQuote
+	}{
+		{SessionPreMarket, q.PreMarketQuote},
+		{SessionAfterHours, q.PostMarketQuote},
+		{SessionOvernight, q.OverNightQuote},
+	} {
+		if s.prices == nil || s.prices.LastDone == nil || s.prices.LastDone.IsZero() {
+			continue
+		}
+		eq := models.ExtendedQuote{
+			Session:   s.session,
+			Last:      DecimalFloat(s.prices.LastDone),
+			PrevClose: DecimalFloat(s.prices.PrevClose),
+			High:      DecimalFloat(s.prices.High),
+			Low:       DecimalFloat(s.prices.Low),
+			Volume:    s.prices.Volume,
+			Turnover:  DecimalFloat(s.prices.Turnover),
+		}
+		if s.prices.Timestamp > 0 {
+			eq.Timestamp = time.Unix(s.prices.Timestamp, 0).Format(time.RFC3339)
+		}
+		if eq.PrevClose != 0 {
+			eq.Change = eq.Last - eq.PrevClose
+			eq.ChangePct = eq.Change / eq.PrevClose * 100
+		}
+		out = append(out, eq)
+	}
+	return out
+}
+
+// DecimalFloat converts a Longport decimal to a float64, 0 when it is unset.
+func DecimalFloat(d *decimal.Decimal) float64 {
+	if d == nil {
+		return 0
+	}
+	return d.InexactFloat64()
+}
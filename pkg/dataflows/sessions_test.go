@@ -0,0 +1,41 @@
+package dataflows
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarketSession(t *testing.T) {
+	utc := func(s string) time.Time {
+		ts, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts
+	}
+	for _, tc := range []struct {
+		symbol string
+		at     string
+		want   string
+	}{
+		{"AAPL", "2025-07-01 12:00", SessionPreMarket},     // 08:00 EDT
+		{"AAPL.US", "2025-07-01 13:30", SessionIntraday},   // 09:30 EDT
+		{"AAPL.US", "2025-01-07 13:30", SessionPreMarket},  // 08:30 EST
+		{"AAPL.US", "2025-01-07 21:30", SessionAfterHours}, // 16:30 EST
+		{"AAPL.US", "2025-01-08 02:00", SessionOvernight},  // 21:00 EST
+		{"AAPL.US", "2025-01-11 15:00", SessionClosed},     // Saturday
+		{"AAPL.US", "2025-01-13 01:30", SessionOvernight},  // Sunday 20:30 EST
+		{"AAPL.US", "2025-03-10 12:00", SessionPreMarket},  // 08:00 EDT, first day after the DST switch
+		{"700.HK", "2025-07-01 01:15", SessionPreMarket},   // 09:15 HKT
+		{"700.HK", "2025-07-01 04:30", SessionClosed},      // lunch break
+		{"600519.SH", "2025-07-01 06:00", SessionIntraday}, // 14:00 CST
+		{"600519.SH", "2025-07-01 08:00", SessionClosed},
+	} {
+		if got := MarketSession(tc.symbol, utc(tc.at)); got != tc.want {
+			t.Errorf("MarketSession(%s, %s UTC) = %s, want %s", tc.symbol, tc.at, got, tc.want)
+		}
+	}
+	if MarketSession("AAPL", time.Time{}) != "" {
+		t.Error("zero time should have no session")
+	}
+}
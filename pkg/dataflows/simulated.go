@@ -0,0 +1,333 @@
+package dataflows
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// simulatedEpoch is the first bar of every simulated price path. Paths always
+// start here, so a symbol's bar for a given day does not depend on how many
+// bars were asked for
+var simulatedEpoch = time.Date(2015, 1, 5, 0, 0, 0, 0, time.UTC)
+
+// simulatedHost is the reserved domain of simulated article and post links;
+// .invalid never resolves, so nothing can fetch them by accident
+const simulatedHost = "simulated.invalid"
+
+// Simulator generates realistic-shaped market data, news and social posts
+// without network access. Output is deterministic for a symbol or query, the
+// configured seed and the day, so repeated runs see the same data
+type Simulator struct {
+	seed int64
+	now  func() time.Time
+}
+
+// NewSimulator returns the generator for config, nil unless the simulated
+// data provider is selected
+func NewSimulator(config *Config) *Simulator {
+	if !config.SimulatedData() {
+		return nil
+	}
+	return NewSeededSimulator(config.Seed)
+}
+
+// NewSeededSimulator returns a generator whatever the data provider, e.g. to
+// stand in for a market data source that is not configured
+func NewSeededSimulator(seed int) *Simulator {
+	return &Simulator{seed: int64(seed), now: time.Now}
+}
+
+// Provenance reports simulated data from source, generated now.
+func (s *Simulator) Provenance(source string) models.Provenance {
+	return models.Provenance{Source: source, Mode: models.ProvenanceSimulated, FetchedAt: s.now()}
+}
+
+// rng returns a generator seeded by parts and the configured seed.
+func (s *Simulator) rng(parts ...string) *rand.Rand {
+	h := fnv.New64a()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return rand.New(rand.NewSource(int64(h.Sum64()) ^ s.seed))
+}
+
+// today is the current UTC date.
+func (s *Simulator) today() time.Time {
+	return s.now().UTC().Truncate(24 * time.Hour)
+}
+
+// Bars returns the last count daily bars of symbol up to the latest weekday.
+// Prices follow a geometric random walk with a per-symbol starting price and
+// volatility, drift regimes lasting a few weeks to a few months and a weak
+// pull back towards the starting price; volume rises on large moves. Market
+// holidays are not skipped
+func (s *Simulator) Bars(symbol string, count int) []*models.MarketData {
+	if count <= 0 {
+		return nil
+	}
+	symbol = NormalizeSymbol(symbol)
+	end := s.today()
+	for end.Weekday() == time.Saturday || end.Weekday() == time.Sunday {
+		end = end.AddDate(0, 0, -1)
+	}
+	currency := CurrencyOf(symbol)
+
+	r := s.rng("bars", symbol)
+	base := 20 + r.Float64()*480
+	vol := 0.01 + r.Float64()*0.02
+	baseVolume := 5e5 + r.Float64()*2e7
+	logBase := math.Log(base)
+
+	var bars []*models.MarketData
+	price, drift, regimeLeft := base, 0.0, 0
+	for day := simulatedEpoch; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		if regimeLeft == 0 {
+			drift = (r.Float64()*3.5 - 1.5) / 1000
+			regimeLeft = 20 + r.Intn(60)
+		}
+		regimeLeft--
+
+		ret := drift + vol*r.NormFloat64() - 0.005*(math.Log(price)-logBase)
+		open := price * (1 + 0.3*vol*r.NormFloat64())
+		price *= math.Exp(ret)
+		high := math.Max(open, price) * (1 + 0.5*vol*math.Abs(r.NormFloat64()))
+		low := math.Min(open, price) * (1 - 0.5*vol*math.Abs(r.NormFloat64()))
+		volume := baseVolume * math.Exp(0.3*r.NormFloat64()) * (1 + 10*math.Abs(ret))
+
+		bars = append(bars, &models.MarketData{
+			Symbol:   symbol,
+			Date:     day.Format("2006-01-02"),
+			Open:     roundCents(open),
+			High:     roundCents(high),
+			Low:      roundCents(low),
+			Close:    roundCents(price),
+			Volume:   int64(volume),
+			Currency: currency,
+		})
+	}
+	if len(bars) > count {
+		bars = bars[len(bars)-count:]
+	}
+	return bars
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// simulatedOutlets are the made-up publications simulated news is credited to.
+var simulatedOutlets = []string{
+	"Simulated Wire", "Simulated Markets Daily", "Simulated Business Review", "Simulated Finance Desk",
+}
+
+// simulatedTemplate is a headline and summary with %[1]s for the subject,
+// and the sentiment the text conveys
+type simulatedTemplate struct {
+	title, summary string
+	sentiment      float64
+}
+
+var companyTemplates = []simulatedTemplate{
+	{"%[1]s shares climb after analysts lift price targets", "Several brokers raised their targets on %[1]s, citing stronger demand and improving margins.", 0.6},
+	{"%[1]s beats quarterly earnings estimates", "%[1]s reported earnings and revenue above consensus and reaffirmed its full-year outlook.", 0.7},
+	{"%[1]s slides as guidance disappoints investors", "%[1]s guided next quarter below expectations, pointing to softer orders and higher costs.", -0.6},
+	{"%[1]s announces share buyback program", "The board of %[1]s approved a new repurchase authorization, signalling confidence in cash flow.", 0.5},
+	{"Regulators open review of %[1]s business practices", "A regulatory review adds uncertainty for %[1]s, though analysts expect a limited financial impact.", -0.4},
+	{"%[1]s unveils new product line at investor event", "%[1]s showed new products and said they would start contributing to revenue next year.", 0.4},
+	{"Short sellers increase bets against %[1]s", "Short interest in %[1]s rose to a multi-month high as some funds questioned its valuation.", -0.5},
+	{"%[1]s trades flat ahead of key industry conference", "%[1]s shares moved little as investors waited for updates from management next week.", 0},
+	{"%[1]s expands partnership with major cloud provider", "The expanded agreement gives %[1]s wider distribution and a multi-year revenue commitment.", 0.5},
+	{"Insider selling at %[1]s draws investor attention", "Filings show executives at %[1]s sold shares under pre-arranged trading plans.", -0.2},
+}
+
+var topicTemplates = []simulatedTemplate{
+	{"Markets digest the latest %[1]s headlines", "Investors weighed new developments around %[1]s as major indices traded in a narrow range.", 0},
+	{"What the %[1]s moves mean for investors", "Strategists say recent %[1]s moves reflect shifting rate expectations rather than a change in fundamentals.", 0.1},
+	{"Stocks rally as %[1]s fears ease", "Equities advanced broadly after %[1]s worries faded and bond yields slipped.", 0.5},
+	{"Volatility rises on renewed %[1]s concerns", "Risk appetite cooled as %[1]s concerns resurfaced, lifting demand for defensive sectors.", -0.5},
+	{"Analysts split on %[1]s outlook for next quarter", "Forecasts for %[1]s diverge, with some expecting a slowdown and others a steady expansion.", 0},
+	{"Fund managers boost exposure amid %[1]s optimism", "A monthly survey shows managers adding equity risk on improving %[1]s sentiment.", 0.4},
+}
+
+// isTicker reports whether query reads as a stock symbol rather than a topic.
+func isTicker(query string) bool {
+	if query == "" || len(query) > 12 || strings.ContainsAny(query, " \t") {
+		return false
+	}
+	for _, c := range query {
+		if !unicode.IsUpper(c) && !unicode.IsDigit(c) && c != '.' && c != '$' {
+			return false
+		}
+	}
+	return true
+}
+
+// simulatedSubject returns query's symbol without market suffix or
+// cashtag, or query itself when it names a topic
+func simulatedSubject(query string) (string, bool) {
+	query = strings.TrimSpace(query)
+	if first := strings.Fields(query); len(first) > 0 && isTicker(first[0]) {
+		sym := strings.TrimPrefix(first[0], "$")
+		if i := strings.LastIndex(sym, "."); i > 0 {
+			sym = sym[:i]
+		}
+		return sym, true
+	}
+	return query, false
+}
+
+// Articles returns n templated news articles about query, newest first,
+// published over the past week.
+func (s *Simulator) Articles(query string, n int) []*NewsArticle {
+	if n <= 0 {
+		n = 10
+	}
+	subject, ticker := simulatedSubject(query)
+	templates := topicTemplates
+	if ticker {
+		templates = companyTemplates
+	}
+	now := s.now().Truncate(time.Hour)
+	r := s.rng("news", strings.ToLower(query), s.today().Format("2006-01-02"))
+
+	articles := make([]*NewsArticle, 0, n)
+	var age time.Duration
+	for i, t := range r.Perm(max(n, len(templates))) {
+		if i == n {
+			break
+		}
+		tpl := templates[t%len(templates)]
+		age += time.Duration(1+r.Intn(16)) * time.Hour
+		title := fmt.Sprintf(tpl.title, subject)
+		articles = append(articles, &NewsArticle{
+			Title:       title,
+			Content:     fmt.Sprintf(tpl.summary, subject),
+			URL:         fmt.Sprintf("https://news.%s/%s-%d", simulatedHost, slug(title), i+1),
+			Source:      simulatedOutlets[r.Intn(len(simulatedOutlets))],
+			PublishedAt: now.Add(-age),
+			Sentiment:   tpl.sentiment,
+			Metadata:    map[string]string{"simulated": "true"},
+		})
+	}
+	ScoreArticles(articles)
+	return articles
+}
+
+// ArticlePage returns the body of a simulated article link.
+func (s *Simulator) ArticlePage(articleURL string) (ArticleContent, error) {
+	if !strings.Contains(articleURL, simulatedHost) {
+		return ArticleContent{}, fmt.Errorf("simulated data mode: %s is not a simulated article", articleURL)
+	}
+	title := articleURL[strings.LastIndex(articleURL, "/")+1:]
+	if i := strings.LastIndex(title, "-"); i > 0 {
+		title = title[:i]
+	}
+	title = strings.ReplaceAll(title, "-", " ")
+	text := fmt.Sprintf("%s. This article was generated by CortexGo's simulated data provider for demos and development; "+
+		"it does not describe real events. Prices, quotes and figures in it are illustrative only, and analysts should "+
+		"treat it as a placeholder for the coverage a live run would find.", title)
+	return ArticleContent{Text: text}, nil
+}
+
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(title) {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			b.WriteRune(c)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// simulatedTickers are the symbols posts in a subreddit feed talk about.
+var simulatedTickers = []string{"AAPL", "MSFT", "NVDA", "TSLA", "AMZN", "META", "GOOGL", "AMD"}
+
+var simulatedSubreddits = []string{"wallstreetbets", "stocks", "investing", "StockMarket", "SecurityAnalysis"}
+
+var postTemplates = []simulatedTemplate{
+	{"$%[1]s DD: why I think the market is underpricing this", "Went through the last few quarters of %[1]s. Margins are trending up and the balance sheet is clean. Not financial advice.", 0.6},
+	{"Bought more %[1]s on the dip today", "Added to my %[1]s position after the pullback. Long term thesis unchanged.", 0.5},
+	{"Is %[1]s overvalued at these levels?", "Trying to figure out if %[1]s still has room to run or if the easy money is gone. Thoughts?", -0.1},
+	{"Sold my %[1]s shares, here is why", "Guidance looked weak to me and competition is heating up. Taking profits on %[1]s.", -0.5},
+	{"%[1]s earnings play, calls or puts?", "Earnings for %[1]s are coming up. IV is elevated, thinking about a spread instead of naked calls.", 0.1},
+	{"$%[1]s chart looks ready to break out", "Volume has been building and %[1]s keeps bouncing off support. Watching for a close above resistance.", 0.4},
+	{"Bag holding %[1]s since last year, should I average down?", "Down a lot on %[1]s. Not sure whether to cut losses or keep averaging down.", -0.4},
+	{"Daily discussion: %[1]s", "Post your takes on %[1]s here. Keep it civil.", 0},
+}
+
+// Posts returns n templated Reddit posts about query, newest first, spread
+// over the past few days. An empty subreddit picks finance subreddits.
+func (s *Simulator) Posts(query, subreddit string, n int) []*RedditPost {
+	if n <= 0 {
+		n = 10
+	}
+	subject, _ := simulatedSubject(query)
+	r := s.rng("reddit", strings.ToLower(query), strings.ToLower(subreddit), s.today().Format("2006-01-02"))
+	return s.posts(r, []string{subject}, subreddit, n)
+}
+
+// posts generates n posts in subreddit, cycling through subjects.
+func (s *Simulator) posts(r *rand.Rand, subjects []string, subreddit string, n int) []*RedditPost {
+	now := s.now().Truncate(time.Minute)
+	posts := make([]*RedditPost, 0, n)
+	var age time.Duration
+	for i, t := range r.Perm(max(n, len(postTemplates))) {
+		if i == n {
+			break
+		}
+		tpl := postTemplates[t%len(postTemplates)]
+		subject := subjects[i%len(subjects)]
+		sub := subreddit
+		if sub == "" || strings.Contains(sub, "+") {
+			sub = simulatedSubreddits[r.Intn(len(simulatedSubreddits))]
+		}
+		age += time.Duration(10+r.Intn(360)) * time.Minute
+		id := fmt.Sprintf("sim%06x", r.Intn(1<<24))
+		posts = append(posts, &RedditPost{
+			ID:        id,
+			Title:     fmt.Sprintf(tpl.title, subject),
+			Content:   fmt.Sprintf(tpl.summary, subject),
+			URL:       fmt.Sprintf("https://reddit.%s/r/%s/comments/%s", simulatedHost, sub, id),
+			Subreddit: sub,
+			Author:    fmt.Sprintf("sim_trader_%d", r.Intn(1000)),
+			Score:     int(math.Exp(2 + 4*r.Float64())),
+			Comments:  int(math.Exp(1 + 4*r.Float64())),
+			CreatedAt: now.Add(-age),
+			Sentiment: tpl.sentiment,
+		})
+	}
+	return posts
+}
+
+// SubredditPosts returns limit templated posts of subreddit ordered by sort:
+// new by age, everything else by score.
+func (s *Simulator) SubredditPosts(subreddit, sortBy string, limit int) []*RedditPost {
+	if limit <= 0 {
+		limit = 25
+	}
+	r := s.rng("subreddit", strings.ToLower(subreddit), sortBy, s.today().Format("2006-01-02"))
+	tickers := append([]string(nil), simulatedTickers...)
+	r.Shuffle(len(tickers), func(i, j int) { tickers[i], tickers[j] = tickers[j], tickers[i] })
+	posts := s.posts(r, tickers, subreddit, limit)
+	if sortBy != "new" {
+		sort.SliceStable(posts, func(i, j int) bool { return posts[i].Score > posts[j].Score })
+	}
+	return posts
+}
@@ -0,0 +1,107 @@
+package dataflows
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/models"
+)
+
+func fixedSimulator(seed int, now time.Time) *Simulator {
+	s := NewSeededSimulator(seed)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestSimulatorBarsAreStableAndWellFormed(t *testing.T) {
+	now := time.Date(2026, 3, 14, 15, 0, 0, 0, time.UTC) // a Saturday
+	s := fixedSimulator(0, now)
+
+	long := s.Bars("700.HK", 250)
+	short := s.Bars("700.hk", 20)
+	if len(long) != 250 || len(short) != 20 {
+		t.Fatalf("got %d and %d bars", len(long), len(short))
+	}
+	if last := long[len(long)-1]; last.Date != "2026-03-13" || *last != *short[len(short)-1] {
+		t.Fatalf("latest bar differs by count or is not the last weekday: %+v vs %+v", last, short[len(short)-1])
+	}
+	for _, b := range long {
+		d, _ := time.Parse("2006-01-02", b.Date)
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+			t.Fatalf("weekend bar %s", b.Date)
+		}
+		if b.Low > b.Open || b.Low > b.Close || b.High < b.Open || b.High < b.Close || b.Low <= 0 || b.Volume <= 0 {
+			t.Fatalf("malformed bar %+v", b)
+		}
+		if b.Currency != CurrencyHKD {
+			t.Fatalf("currency %q, want HKD", b.Currency)
+		}
+	}
+
+	if other := fixedSimulator(7, now).Bars("700.HK", 1)[0]; other.Close == short[len(short)-1].Close {
+		t.Fatal("seed did not change the path")
+	}
+}
+
+func TestSimulatorArticlesAndPosts(t *testing.T) {
+	now := time.Date(2026, 3, 12, 15, 0, 0, 0, time.UTC)
+	s := fixedSimulator(0, now)
+
+	articles := s.Articles("TSLA.US", 8)
+	if len(articles) != 8 {
+		t.Fatalf("got %d articles", len(articles))
+	}
+	for i, a := range articles {
+		if !strings.Contains(a.Title+a.Content, "TSLA") || strings.Contains(a.Title, ".US") {
+			t.Fatalf("article not about TSLA: %q", a.Title)
+		}
+		if a.Metadata["simulated"] != "true" || !strings.Contains(a.URL, simulatedHost) || !a.PublishedAt.Before(now) {
+			t.Fatalf("article not marked simulated: %+v", a)
+		}
+		if i > 0 && a.PublishedAt.After(articles[i-1].PublishedAt) {
+			t.Fatal("articles not newest first")
+		}
+	}
+	if again := s.Articles("TSLA.US", 8); again[3].Title != articles[3].Title {
+		t.Fatal("articles differ between calls")
+	}
+	if page, err := s.ArticlePage(articles[0].URL); err != nil || !strings.Contains(page.Text, "simulated") {
+		t.Fatalf("article page: %v %q", err, page.Text)
+	}
+
+	posts := s.Posts("$TSLA", "", 5)
+	if len(posts) != 5 || !strings.Contains(posts[0].Title+posts[0].Content, "TSLA") || posts[0].Subreddit == "" {
+		t.Fatalf("posts: %+v", posts)
+	}
+	feed := s.SubredditPosts("stocks", "hot", 10)
+	for i := 1; i < len(feed); i++ {
+		if feed[i].Score > feed[i-1].Score || feed[i].Subreddit != "stocks" {
+			t.Fatalf("hot feed out of order or wrong subreddit: %+v", feed[i])
+		}
+	}
+}
+
+func TestSimulatedClientsStayOffTheNetwork(t *testing.T) {
+	cfg := &Config{DataCacheDir: t.TempDir(), DataProvider: config.DataSimulated}
+
+	news := NewGoogleNewsClient(cfg)
+	articles, err := news.GetStockNews("AAPL", 5, cfg)
+	if err != nil || len(articles) != 5 {
+		t.Fatalf("stock news: %v (%d articles)", err, len(articles))
+	}
+	if p := news.Provenance(); p.Mode != models.ProvenanceSimulated || p.Source != "google_news" {
+		t.Fatalf("provenance %+v", p)
+	}
+	posts, err := NewRedditClient(cfg).GetStockMentions("AAPL", cfg)
+	if err != nil || len(posts) == 0 {
+		t.Fatalf("reddit mentions: %v", err)
+	}
+
+	// sources without a generator behave as offline
+	if _, err := NewTreasuryClient(cfg).GetYieldCurves("", 10); !errors.Is(err, ErrOffline) {
+		t.Fatalf("treasury: want ErrOffline, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package dataflows
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Source tiers, from primary reporting down to opinion and SEO content.
+// Press releases are primary sources but written by the company itself.
+const (
+	SourceTierWire         = "wire"
+	SourceTierMajor        = "major"
+	SourceTierAggregator   = "aggregator"
+	SourceTierPressRelease = "press_release"
+	SourceTierOpinion      = "opinion"
+	SourceTierUnknown      = "unknown"
+)
+
+// sourceTierScores is the quality score in [0, 1] given to each tier.
+var sourceTierScores = map[string]float64{
+	SourceTierWire:         1.0,
+	SourceTierMajor:        0.85,
+	SourceTierAggregator:   0.6,
+	SourceTierPressRelease: 0.5,
+	SourceTierUnknown:      0.4,
+	SourceTierOpinion:      0.3,
+}
+
+// newsSource is a registry entry: the names Google News shows for an outlet
+// and the domains it publishes from.
+type newsSource struct {
+	tier    string
+	names   []string
+	domains []string
+}
+
+var newsSources = []newsSource{
+	{SourceTierWire, []string{"reuters", "associated press", "ap news", "bloomberg", "bloomberg.com", "dow jones newswires", "afp", "agence france-presse", "xinhua"}, []string{"reuters.com", "apnews.com", "bloomberg.com", "afp.com"}},
+	{SourceTierMajor, []string{"the wall street journal", "wall street journal", "wsj", "financial times", "ft", "cnbc", "barron's", "barrons", "marketwatch", "the new york times", "new york times", "the economist", "nikkei asia", "nikkei", "caixin global", "caixin", "south china morning post", "scmp", "bbc", "bbc news", "cnn", "cnn business", "the washington post", "fortune", "the information"}, []string{"wsj.com", "ft.com", "cnbc.com", "barrons.com", "marketwatch.com", "nytimes.com", "economist.com", "asia.nikkei.com", "nikkei.com", "caixinglobal.com", "scmp.com", "bbc.com", "bbc.co.uk", "cnn.com", "washingtonpost.com", "fortune.com", "theinformation.com"}},
+	{SourceTierAggregator, []string{"yahoo finance", "yahoo", "business insider", "markets insider", "forbes", "investopedia", "techcrunch", "the verge", "fox business", "investing.com", "thestreet", "nasdaq", "axios", "quartz"}, []string{"finance.yahoo.com", "yahoo.com", "businessinsider.com", "markets.businessinsider.com", "forbes.com", "investopedia.com", "techcrunch.com", "theverge.com", "foxbusiness.com", "investing.com", "thestreet.com", "nasdaq.com", "axios.com", "qz.com"}},
+	{SourceTierPressRelease, []string{"pr newswire", "prnewswire", "business wire", "businesswire", "globenewswire", "globe newswire", "accesswire", "newsfile"}, []string{"prnewswire.com", "businesswire.com", "globenewswire.com", "accesswire.com", "newsfilecorp.com"}},
+	{SourceTierOpinion, []string{"the motley fool", "motley fool", "seeking alpha", "benzinga", "investorplace", "zacks", "zacks investment research", "24/7 wall st.", "247wallst", "gurufocus", "simply wall st", "marketbeat", "ainvest", "finbold", "insider monkey", "tipranks"}, []string{"fool.com", "seekingalpha.com", "benzinga.com", "investorplace.com", "zacks.com", "247wallst.com", "gurufocus.com", "simplywall.st", "marketbeat.com", "ainvest.com", "finbold.com", "insidermonkey.com", "tipranks.com"}},
+}
+
+// SourceQuality classifies an outlet by the source name shown with an article
+// and, failing that, by the domain of any of the given URLs.
+func SourceQuality(source string, urls ...string) (string, float64) {
+	name := strings.ToLower(strings.TrimSpace(source))
+	for _, s := range newsSources {
+		for _, n := range s.names {
+			if name == n {
+				return s.tier, sourceTierScores[s.tier]
+			}
+		}
+	}
+	for _, raw := range append([]string{source}, urls...) {
+		host := hostOf(raw)
+		if host == "" {
+			continue
+		}
+		for _, s := range newsSources {
+			for _, d := range s.domains {
+				if host == d || strings.HasSuffix(host, "."+d) {
+					return s.tier, sourceTierScores[s.tier]
+				}
+			}
+		}
+	}
+	return SourceTierUnknown, sourceTierScores[SourceTierUnknown]
+}
+
+// ScoreArticles sets SourceTier and Quality on each article. Google News
+// links go through news.google.com, so the publisher's URL recorded in the
+// metadata is checked before the article link.
+func ScoreArticles(articles []*NewsArticle) {
+	for _, a := range articles {
+		if a == nil {
+			continue
+		}
+		a.SourceTier, a.Quality = SourceQuality(a.Source, a.Metadata["source_url"], a.Metadata["original_url"], a.URL)
+	}
+}
+
+// SortByQuality orders articles by source quality, newest first within a tier.
+func SortByQuality(articles []*NewsArticle) {
+	sort.SliceStable(articles, func(i, j int) bool {
+		if articles[i].Quality != articles[j].Quality {
+			return articles[i].Quality > articles[j].Quality
+		}
+		return articles[i].PublishedAt.After(articles[j].PublishedAt)
+	})
+}
+
+// FilterByQuality drops articles scoring below min.
+func FilterByQuality(articles []*NewsArticle, min float64) []*NewsArticle {
+	if min <= 0 {
+		return articles
+	}
+	kept := make([]*NewsArticle, 0, len(articles))
+	for _, a := range articles {
+		if a != nil && a.Quality >= min {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// hostOf returns the lower-case host of a URL or bare domain, without "www.".
+func hostOf(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" || strings.Contains(raw, " ") {
+		return ""
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || !strings.Contains(u.Hostname(), ".") {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
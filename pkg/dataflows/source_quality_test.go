@@ -0,0 +1,39 @@
+package dataflows
+
+import "testing"
+
+func TestSourceQuality(t *testing.T) {
+	cases := []struct {
+		source string
+		urls   []string
+		tier   string
+	}{
+		{"Reuters", nil, SourceTierWire},
+		{"The Motley Fool", nil, SourceTierOpinion},
+		{"CNBC", nil, SourceTierMajor},
+		{"finance.yahoo.com", nil, SourceTierAggregator},
+		{"Some Blog", []string{"https://www.seekingalpha.com/article/1"}, SourceTierOpinion},
+		{"Some Blog", []string{"https://news.google.com/rss/articles/x"}, SourceTierUnknown},
+	}
+	for _, c := range cases {
+		if tier, _ := SourceQuality(c.source, c.urls...); tier != c.tier {
+			t.Errorf("%s %v: got %s, want %s", c.source, c.urls, tier, c.tier)
+		}
+	}
+}
+
+func TestScoreSortAndFilterArticles(t *testing.T) {
+	articles := []*NewsArticle{
+		{Title: "a", Source: "Benzinga"},
+		{Title: "b", Source: "Unknown Site", Metadata: map[string]string{"source_url": "https://www.reuters.com"}},
+		{Title: "c", Source: "Forbes"},
+	}
+	ScoreArticles(articles)
+	SortByQuality(articles)
+	if articles[0].Title != "b" || articles[0].SourceTier != SourceTierWire || articles[2].Title != "a" {
+		t.Fatalf("unexpected order: %s, %s, %s", articles[0].Title, articles[1].Title, articles[2].Title)
+	}
+	if kept := FilterByQuality(articles, 0.6); len(kept) != 2 {
+		t.Fatalf("expected the opinion article to be dropped, kept %d", len(kept))
+	}
+}
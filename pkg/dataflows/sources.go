@@ -0,0 +1,115 @@
+package dataflows
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrSourceDegraded is returned without calling a data source whose breaker
+// is open after repeated outages.
+var ErrSourceDegraded = errors.New("source degraded")
+
+// A source breaker counts whole calls, each already retried, so it trips
+// sooner and stays open longer than the per-host breakers.
+const (
+	sourceBreakerThreshold = 3
+	sourceBreakerCooldown  = 2 * time.Minute
+)
+
+// SourceStatus is the health of a data source as seen by CallSource.
+type SourceStatus struct {
+	Name      string    `json:"name"`
+	Degraded  bool      `json:"degraded"`
+	Failures  int       `json:"failures"` // consecutive outages
+	LastError string    `json:"last_error,omitempty"`
+	RetryAt   time.Time `json:"retry_at,omitempty"` // when a degraded source is tried again
+}
+
+type sourceState struct {
+	breaker circuitBreaker
+	mu      sync.Mutex
+	lastErr string
+}
+
+var (
+	sourcesMu    sync.Mutex
+	sourceStates = map[string]*sourceState{}
+)
+
+func sourceFor(name string) *sourceState {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	s, ok := sourceStates[name]
+	if !ok {
+		s = &sourceState{breaker: circuitBreaker{threshold: sourceBreakerThreshold, cooldown: sourceBreakerCooldown}}
+		sourceStates[name] = s
+	}
+	return s
+}
+
+// CallSource runs fn against the named data source behind its circuit
+// breaker. While the breaker is open fn is not called and an error wrapping
+// ErrSourceDegraded is returned. Only outages (see IsOutage) count as
+// failures; bad input, missing data and offline cache misses mean the source
+// answered and leave it healthy.
+func CallSource(name string, fn func() error) error {
+	s := sourceFor(name)
+	if !s.breaker.allow() {
+		return fmt.Errorf("%s: %w", name, ErrSourceDegraded)
+	}
+	err := fn()
+	if !IsOutage(err) {
+		s.breaker.record(true)
+		return err
+	}
+	s.breaker.record(false)
+	s.mu.Lock()
+	s.lastErr = err.Error()
+	s.mu.Unlock()
+	return err
+}
+
+// IsOutage reports whether err means a data source could not be reached:
+// every retry failed or a breaker is open.
+func IsOutage(err error) bool {
+	return errors.Is(err, ErrRetriesExhausted) || errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrSourceDegraded)
+}
+
+// SourceDegraded reports whether the named source's breaker is open.
+func SourceDegraded(name string) bool {
+	sourcesMu.Lock()
+	s, ok := sourceStates[name]
+	sourcesMu.Unlock()
+	if !ok {
+		return false
+	}
+	_, retryAt := s.breaker.state()
+	return !retryAt.IsZero()
+}
+
+// SourceStatuses lists every source called so far, by name.
+func SourceStatuses() []SourceStatus {
+	sourcesMu.Lock()
+	names := make([]string, 0, len(sourceStates))
+	states := make(map[string]*sourceState, len(sourceStates))
+	for name, s := range sourceStates {
+		names = append(names, name)
+		states[name] = s
+	}
+	sourcesMu.Unlock()
+	sort.Strings(names)
+
+	statuses := make([]SourceStatus, 0, len(names))
+	for _, name := range names {
+		s := states[name]
+		failures, retryAt := s.breaker.state()
+		s.mu.Lock()
+		lastErr := s.lastErr
+		s.mu.Unlock()
+		statuses = append(statuses, SourceStatus{Name: name, Degraded: !retryAt.IsZero(), Failures: failures, LastError: lastErr, RetryAt: retryAt})
+	}
+	return statuses
+}
@@ -0,0 +1,39 @@
+package dataflows
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCallSourceDegradesAfterOutages(t *testing.T) {
+	const name = "test_source"
+	outage := fmt.Errorf("%w: HTTP error 503", ErrRetriesExhausted)
+	badInput := errors.New("symbol parameter is required")
+
+	for range 10 {
+		if err := CallSource(name, func() error { return badInput }); err != badInput {
+			t.Fatalf("err = %v", err)
+		}
+	}
+	if SourceDegraded(name) {
+		t.Fatal("input errors must not degrade a source")
+	}
+
+	for range sourceBreakerThreshold {
+		_ = CallSource(name, func() error { return outage })
+	}
+	called := false
+	err := CallSource(name, func() error { called = true; return nil })
+	if called || !errors.Is(err, ErrSourceDegraded) || !IsOutage(err) {
+		t.Fatalf("open source: called=%v err=%v", called, err)
+	}
+	if !SourceDegraded(name) {
+		t.Error("SourceDegraded = false for an open breaker")
+	}
+	for _, s := range SourceStatuses() {
+		if s.Name == name && (!s.Degraded || s.Failures != sourceBreakerThreshold || s.LastError != outage.Error() || s.RetryAt.IsZero()) {
+			t.Errorf("status = %+v", s)
+		}
+	}
+}
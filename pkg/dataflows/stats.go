@@ -0,0 +1,53 @@
+package dataflows
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+)
+
+// RequestStats are cumulative counters for data source requests made through
+// WithRetry. Callers running many analyses sample them to watch error and
+// rate-limit rates.
+type RequestStats struct {
+	Requests  int64 `json:"requests"`
+	Errors    int64 `json:"errors"`
+	Throttled int64 `json:"throttled"`
+}
+
+var requestRequests, requestErrors, requestThrottled atomic.Int64
+
+// Stats returns the request counters since process start.
+func Stats() RequestStats {
+	return RequestStats{
+		Requests:  requestRequests.Load(),
+		Errors:    requestErrors.Load(),
+		Throttled: requestThrottled.Load(),
+	}
+}
+
+func recordAttempt(err error) {
+	if errors.Is(err, ErrOffline) {
+		return
+	}
+	requestRequests.Add(1)
+	if err == nil {
+		return
+	}
+	requestErrors.Add(1)
+	if IsRateLimited(err) {
+		requestThrottled.Add(1)
+	}
+}
+
+// IsRateLimited reports whether err looks like a provider rate limit (HTTP 429).
+// Errors from HTTP clients and the LLM SDK only carry the status in their text.
+func IsRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "429") ||
+		strings.Contains(msg, "too many requests") ||
+		strings.Contains(msg, "rate limit")
+}
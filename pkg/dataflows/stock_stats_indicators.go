@@ -10,6 +10,14 @@ import (
 	"github.com/dyike/CortexGo/models"
 )
 
+// IndicatorNames lists the indicators produced by CalculateAllIndicators in
+// display order: trend averages, bands, then oscillators.
+var IndicatorNames = []string{
+	"close_10_ema", "close_50_sma", "close_200_sma", "vwma",
+	"boll", "boll_ub", "boll_lb",
+	"rsi", "macd", "macds", "macdh", "mfi", "atr",
+}
+
 func CalculateAllIndicators(data []*models.MarketData, startDate, endDate time.Time) map[string][]models.IndicatorValue {
 	if len(data) == 0 {
 		return make(map[string][]models.IndicatorValue)
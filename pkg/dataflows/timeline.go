@@ -0,0 +1,191 @@
+package dataflows
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+// Event types of a news timeline.
+const (
+	EventEarnings      = "earnings"
+	EventGuidance      = "guidance"
+	EventAnalystRating = "analyst_rating"
+	EventProduct       = "product"
+	EventDeal          = "deal"
+	EventLegal         = "legal"
+	EventManagement    = "management"
+	EventCapitalReturn = "capital_return"
+	EventOther         = "other"
+)
+
+// eventRules are checked in order, so a downgrade citing weak earnings is
+// typed as a rating action and a results release raising guidance as guidance.
+var eventRules = []struct {
+	event string
+	terms []string
+}{
+	{EventAnalystRating, []string{"upgrade", "upgrades", "upgraded", "downgrade", "downgrades", "downgraded", "price target", "initiates coverage", "initiated coverage", "overweight", "underweight", "outperform rating", "buy rating", "sell rating", "neutral rating"}},
+	{EventGuidance, []string{"guidance", "outlook", "forecast", "forecasts", "raises forecast", "cuts forecast", "profit warning"}},
+	{EventEarnings, []string{"earnings", "quarterly", "q1", "q2", "q3", "q4", "revenue", "eps", "results", "beats estimates", "misses estimates", "profit", "net income"}},
+	{EventDeal, []string{"acquire", "acquires", "acquired", "acquisition", "merger", "merge", "buyout", "takeover", "to buy", "stake", "spin-off", "spinoff", "ipo"}},
+	{EventLegal, []string{"lawsuit", "sues", "sued", "probe", "investigation", "sec", "antitrust", "fined", "settlement", "recall", "recalls", "regulator", "regulators", "ban", "tariff", "tariffs", "export controls"}},
+	{EventCapitalReturn, []string{"dividend", "dividends", "buyback", "buybacks", "repurchase", "stock split"}},
+	{EventManagement, []string{"ceo", "cfo", "chairman", "resigns", "steps down", "appoints", "appointed", "successor", "executive departure"}},
+	{EventProduct, []string{"launch", "launches", "launched", "unveil", "unveils", "unveiled", "introduces", "debut", "debuts", "rollout", "rolls out", "release", "releases", "partnership", "partners with"}},
+}
+
+const (
+	// timelineMergeWindow bounds how far apart two reports of one event can be.
+	timelineMergeWindow = 36 * time.Hour
+	// Title overlap (Jaccard of content words) above which two reports are
+	// merged: a lower bar when they share an event type.
+	sameTypeOverlap = 0.4
+	anyTypeOverlap  = 0.7
+)
+
+// ClassifyEvent types a report by its headline, falling back to the summary
+// when the headline matches no rule.
+func ClassifyEvent(title, content string) string {
+	for _, text := range []string{title, content} {
+		padded := " " + strings.Join(strings.FieldsFunc(strings.ToLower(text), isPhraseBreak), " ") + " "
+		for _, rule := range eventRules {
+			for _, term := range rule.terms {
+				if strings.Contains(padded, " "+term+" ") {
+					return rule.event
+				}
+			}
+		}
+	}
+	return EventOther
+}
+
+// BuildTimeline turns articles about symbol into a chronological list of
+// events. Reports of the same event (similar headlines within
+// timelineMergeWindow) are merged: the event is dated by the earliest report,
+// headed by the most reliable one, and lists the other outlets as coverage.
+func BuildTimeline(symbol string, articles []*NewsArticle) []models.TimelineEvent {
+	sorted := make([]*NewsArticle, 0, len(articles))
+	for _, a := range articles {
+		if a != nil && strings.TrimSpace(a.Title) != "" {
+			sorted = append(sorted, a)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].PublishedAt.Before(sorted[j].PublishedAt) })
+
+	ticker := strings.ToLower(strings.SplitN(strings.TrimSpace(symbol), ".", 2)[0])
+	type group struct {
+		event      models.TimelineEvent
+		words      map[string]bool
+		last       time.Time
+		sentiments []float64
+	}
+	var groups []*group
+	for _, a := range sorted {
+		if a.SourceTier == "" {
+			a.SourceTier, a.Quality = SourceQuality(a.Source, a.Metadata["source_url"], a.URL)
+		}
+		headline := headlineOf(a)
+		eventType := ClassifyEvent(headline, a.Content)
+		words := titleWords(headline, ticker)
+		sentiment := ScoreSentiment(headline + " " + a.Content)
+
+		var match *group
+		for _, g := range groups {
+			if a.PublishedAt.Sub(g.last) > timelineMergeWindow {
+				continue
+			}
+			overlap := jaccard(words, g.words)
+			if overlap >= anyTypeOverlap || g.event.Type == eventType && overlap >= sameTypeOverlap {
+				match = g
+				break
+			}
+		}
+		if match == nil {
+			groups = append(groups, &group{
+				event: models.TimelineEvent{
+					Time:     a.PublishedAt,
+					Type:     eventType,
+					Headline: headline,
+					Source:   a.Source,
+					URL:      a.URL,
+					Session:  MarketSession(symbol, a.PublishedAt),
+					Quality:  a.Quality,
+				},
+				words:      words,
+				last:       a.PublishedAt,
+				sentiments: []float64{sentiment},
+			})
+			continue
+		}
+		match.last = a.PublishedAt
+		match.sentiments = append(match.sentiments, sentiment)
+		for w := range words {
+			match.words[w] = true
+		}
+		e := &match.event
+		covered := a.Source
+		if a.Quality > e.Quality {
+			covered = e.Source
+			e.Headline, e.Source, e.URL, e.Quality = headline, a.Source, a.URL, a.Quality
+		}
+		if covered != "" && covered != e.Source && !slices.Contains(e.Coverage, covered) {
+			e.Coverage = append(e.Coverage, covered)
+		}
+	}
+
+	events := make([]models.TimelineEvent, 0, len(groups))
+	for _, g := range groups {
+		g.event.Sentiment = averageSentiment(g.sentiments)
+		events = append(events, g.event)
+	}
+	return events
+}
+
+// headlineOf strips the " - Source" suffix Google News appends to titles.
+func headlineOf(a *NewsArticle) string {
+	title := strings.TrimSpace(a.Title)
+	if a.Source != "" {
+		title = strings.TrimSpace(strings.TrimSuffix(title, " - "+a.Source))
+	}
+	return title
+}
+
+// titleWords is the set of content words in a headline, minus the ticker,
+// which every headline about the stock shares.
+func titleWords(title, ticker string) map[string]bool {
+	words := map[string]bool{}
+	for _, phrase := range candidatePhrases(title) {
+		for _, w := range phrase {
+			if w != ticker {
+				words[w] = true
+			}
+		}
+	}
+	return words
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+func averageSentiment(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return math.Round(sum/float64(len(values))*100) / 100
+}
@@ -0,0 +1,55 @@
+package dataflows
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifyEvent(t *testing.T) {
+	cases := map[string]string{
+		"Morgan Stanley downgrades Apple on weak iPhone earnings": EventAnalystRating,
+		"Apple raises full-year guidance after strong quarter":    EventGuidance,
+		"Apple Q3 earnings beat estimates":                        EventEarnings,
+		"Apple to acquire AI startup for $1 billion":              EventDeal,
+		"EU opens antitrust probe into App Store":                 EventLegal,
+		"Apple announces $90 billion buyback":                     EventCapitalReturn,
+		"Apple CFO steps down":                                    EventManagement,
+		"Apple unveils Vision Pro 2":                              EventProduct,
+		"Why Apple could be a great pick":                         EventOther,
+	}
+	for title, want := range cases {
+		if got := ClassifyEvent(title, ""); got != want {
+			t.Errorf("%q: got %s, want %s", title, got, want)
+		}
+	}
+}
+
+func TestBuildTimelineMergesDuplicateReports(t *testing.T) {
+	day := time.Date(2025, 7, 31, 20, 30, 0, 0, time.UTC)
+	articles := []*NewsArticle{
+		{Title: "Apple unveils new AI features for iPhone - The Motley Fool", Source: "The Motley Fool", PublishedAt: day.Add(-72 * time.Hour)},
+		{Title: "Apple Q3 earnings beat estimates on services strength - Benzinga", Source: "Benzinga", PublishedAt: day.Add(time.Hour)},
+		{Title: "Apple Q3 earnings beat estimates as services revenue jumps - Reuters", Source: "Reuters", PublishedAt: day.Add(2 * time.Hour)},
+		{Title: "Morgan Stanley downgrades AAPL", Source: "CNBC", PublishedAt: day.Add(26 * time.Hour)},
+	}
+	events := BuildTimeline("AAPL.US", articles)
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d: %+v", len(events), events)
+	}
+	if events[0].Type != EventProduct || events[2].Type != EventAnalystRating {
+		t.Errorf("unexpected order or types: %s, %s, %s", events[0].Type, events[1].Type, events[2].Type)
+	}
+	earnings := events[1]
+	if earnings.Type != EventEarnings || earnings.Source != "Reuters" || !earnings.Time.Equal(day.Add(time.Hour)) {
+		t.Errorf("expected the earnings reports merged under Reuters at the first report's time, got %+v", earnings)
+	}
+	if len(earnings.Coverage) != 1 || earnings.Coverage[0] != "Benzinga" {
+		t.Errorf("expected Benzinga as coverage, got %v", earnings.Coverage)
+	}
+	if earnings.Headline != "Apple Q3 earnings beat estimates as services revenue jumps" {
+		t.Errorf("expected the source suffix to be stripped, got %q", earnings.Headline)
+	}
+	if earnings.Session != SessionAfterHours {
+		t.Errorf("expected the after-hours session, got %q", earnings.Session)
+	}
+}
@@ -0,0 +1,490 @@
+package dataflows
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/go-resty/resty/v2"
+)
+
+// Finnhub and FMP base URLs; variables so tests can point them at a local server
+var (
+	finnhubBaseURL = "https://finnhub.io/api/v1"
+	fmpBaseURL     = "https://financialmodelingprep.com/api"
+)
+
+// finnhubTokenHeader carries the Finnhub API key; outside the query string it
+// stays out of the URLs that transport errors quote.
+const finnhubTokenHeader = "X-Finnhub-Token"
+
+// ErrNoTranscriptProvider is returned when neither a Finnhub nor an FMP key is configured
+var ErrNoTranscriptProvider = errors.New("no transcript provider configured: set finnhub_api_key or fmp_api_key")
+
+// ErrNoTranscript is returned when the provider has no call on or before the requested date
+var ErrNoTranscript = errors.New("no earnings call transcript found")
+
+// EarningsCallTranscript is the canonical models.EarningsCallTranscript
+type EarningsCallTranscript = models.EarningsCallTranscript
+
+// TranscriptsClient fetches earnings call transcripts from Finnhub, or from
+// Financial Modeling Prep when only an FMP key is configured
+type TranscriptsClient struct {
+	client     *resty.Client
+	cache      *CacheManager
+	finnhubKey string
+	fmpKey     string
+}
+
+// transcriptRef identifies one call in a provider's transcript listing
+type transcriptRef struct {
+	ID      string `json:"id"`
+	Year    int    `json:"year"`
+	Quarter int    `json:"quarter"`
+	Date    string `json:"date"`
+	Title   string `json:"title"`
+}
+
+// NewTranscriptsClient creates a new transcripts client
+func NewTranscriptsClient(config *Config) *TranscriptsClient {
+	// transcripts never change once published; the listing is what goes stale
+	cache := newCacheManager(config, "transcripts", 24*time.Hour)
+
+	return &TranscriptsClient{
+		client:     newHTTPClient(config, "CortexGo/1.0"),
+		cache:      cache,
+		finnhubKey: strings.TrimSpace(config.FinnhubAPIKey),
+		fmpKey:     strings.TrimSpace(config.FMPAPIKey),
+	}
+}
+
+// Provider returns the provider requests go to, or "" when none is configured
+func (tc *TranscriptsClient) Provider() string {
+	switch {
+	case tc.finnhubKey != "":
+		return "finnhub"
+	case tc.fmpKey != "":
+		return "fmp"
+	}
+	return ""
+}
+
+// TranscriptSymbol maps a Longport style ticker (AAPL.US) to the provider
+// ticker; transcripts are only available for US listings
+func TranscriptSymbol(symbol string) (string, error) {
+	symbol = NormalizeSymbol(symbol)
+	if err := ValidateSymbol(symbol); err != nil {
+		return "", err
+	}
+	base, market, found := strings.Cut(symbol, ".")
+	if found && market != "US" {
+		return "", fmt.Errorf("earnings call transcripts are only available for US listings, got %s", symbol)
+	}
+	return base, nil
+}
+
+// GetLatestTranscript returns the most recent earnings call held on or before
+// the given date (YYYY-MM-DD); an empty date means the latest call
+func (tc *TranscriptsClient) GetLatestTranscript(symbol, before string, config *Config) (*EarningsCallTranscript, error) {
+	ticker, err := TranscriptSymbol(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if tc.Provider() == "" {
+		return nil, ErrNoTranscriptProvider
+	}
+
+	refs, err := tc.listTranscripts(ticker)
+	if err != nil {
+		return nil, err
+	}
+	ref, ok := latestTranscriptRef(refs, before)
+	if !ok {
+		return nil, fmt.Errorf("%w for %s before %s", ErrNoTranscript, ticker, before)
+	}
+
+	var transcript EarningsCallTranscript
+	if tc.cache.Get(tc.Provider(), "transcript", ref, &transcript) {
+		return &transcript, nil
+	}
+	if tc.cache.offline {
+		return nil, offlineMiss("transcripts", fmt.Sprintf("%s Q%d %d", ticker, ref.Quarter, ref.Year))
+	}
+
+	var fetched *EarningsCallTranscript
+	if tc.Provider() == "finnhub" {
+		fetched, err = tc.fetchFinnhubTranscript(ref)
+	} else {
+		fetched, err = tc.fetchFMPTranscript(ticker, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+	fetched.Symbol = ticker
+
+	tc.cache.Set(tc.Provider(), "transcript", ref, fetched)
+
+	// Save to file
+	filePath := filepath.Join(config.DataDir, "transcripts_data",
+		fmt.Sprintf("%s_%dQ%d.json", ticker, ref.Year, ref.Quarter))
+	SaveDataToFile(fetched, filePath)
+
+	return fetched, nil
+}
+
+// listTranscripts returns the calls the provider has for a ticker
+func (tc *TranscriptsClient) listTranscripts(ticker string) ([]transcriptRef, error) {
+	var cached []transcriptRef
+	if tc.cache.Get(tc.Provider(), "list", ticker, &cached) {
+		return cached, nil
+	}
+	if tc.cache.offline {
+		return nil, offlineMiss("transcripts", ticker)
+	}
+
+	var refs []transcriptRef
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		var err error
+		if tc.Provider() == "finnhub" {
+			refs, err = tc.listFinnhub(ticker)
+		} else {
+			refs, err = tc.listFMP(ticker)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tc.cache.Set(tc.Provider(), "list", ticker, refs)
+	return refs, nil
+}
+
+func (tc *TranscriptsClient) listFinnhub(ticker string) ([]transcriptRef, error) {
+	body, err := tc.get(finnhubBaseURL+"/stock/transcripts/list", map[string]string{"symbol": ticker})
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Transcripts []struct {
+			ID      string `json:"id"`
+			Title   string `json:"title"`
+			Time    string `json:"time"`
+			Year    int    `json:"year"`
+			Quarter int    `json:"quarter"`
+		} `json:"transcripts"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse Finnhub transcript list: %w", err)
+	}
+	refs := make([]transcriptRef, 0, len(resp.Transcripts))
+	for _, t := range resp.Transcripts {
+		refs = append(refs, transcriptRef{ID: t.ID, Year: t.Year, Quarter: t.Quarter, Date: transcriptDate(t.Time), Title: t.Title})
+	}
+	return refs, nil
+}
+
+func (tc *TranscriptsClient) listFMP(ticker string) ([]transcriptRef, error) {
+	body, err := tc.get(fmpBaseURL+"/v4/earning_call_transcript", map[string]string{"symbol": ticker, "apikey": tc.fmpKey})
+	if err != nil {
+		return nil, err
+	}
+	// FMP lists calls as [quarter, year, "date time"] tuples
+	var rows [][]any
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse FMP transcript list: %w", err)
+	}
+	refs := make([]transcriptRef, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 3 {
+			continue
+		}
+		quarter, _ := row[0].(float64)
+		year, _ := row[1].(float64)
+		date, _ := row[2].(string)
+		refs = append(refs, transcriptRef{Year: int(year), Quarter: int(quarter), Date: transcriptDate(date)})
+	}
+	return refs, nil
+}
+
+func (tc *TranscriptsClient) fetchFinnhubTranscript(ref transcriptRef) (*EarningsCallTranscript, error) {
+	var resp struct {
+		Title       string `json:"title"`
+		Time        string `json:"time"`
+		Year        int    `json:"year"`
+		Quarter     int    `json:"quarter"`
+		Participant []struct {
+			Name        string `json:"name"`
+			Role        string `json:"role"`
+			Description string `json:"description"`
+		} `json:"participant"`
+		Transcript []struct {
+			Name    string   `json:"name"`
+			Speech  []string `json:"speech"`
+			Session string   `json:"session"`
+		} `json:"transcript"`
+	}
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		body, err := tc.get(finnhubBaseURL+"/stock/transcripts", map[string]string{"id": ref.ID})
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("failed to parse Finnhub transcript: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[string]string, len(resp.Participant))
+	affiliations := make(map[string]string, len(resp.Participant))
+	for _, p := range resp.Participant {
+		roles[p.Name] = p.Role
+		affiliations[p.Name] = p.Description
+	}
+	t := &EarningsCallTranscript{Title: resp.Title, Year: resp.Year, Quarter: resp.Quarter, Date: transcriptDate(resp.Time), Source: "finnhub"}
+	for _, seg := range resp.Transcript {
+		session := models.TranscriptSessionPrepared
+		if strings.Contains(strings.ToLower(seg.Session), "question") {
+			session = models.TranscriptSessionQA
+		}
+		role := roles[seg.Name]
+		if strings.EqualFold(seg.Name, "operator") {
+			role = "operator"
+		}
+		t.Segments = append(t.Segments, models.TranscriptSegment{
+			Speaker:     seg.Name,
+			Role:        role,
+			Affiliation: affiliations[seg.Name],
+			Session:     session,
+			Text:        strings.TrimSpace(strings.Join(seg.Speech, " ")),
+		})
+	}
+	return t, nil
+}
+
+func (tc *TranscriptsClient) fetchFMPTranscript(ticker string, ref transcriptRef) (*EarningsCallTranscript, error) {
+	var rows []struct {
+		Date    string `json:"date"`
+		Year    int    `json:"year"`
+		Quarter int    `json:"quarter"`
+		Content string `json:"content"`
+	}
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		body, err := tc.get(fmpBaseURL+"/v3/earning_call_transcript/"+ticker, map[string]string{
+			"year":    fmt.Sprint(ref.Year),
+			"quarter": fmt.Sprint(ref.Quarter),
+			"apikey":  tc.fmpKey,
+		})
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(body, &rows); err != nil {
+			return fmt.Errorf("failed to parse FMP transcript: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w for %s Q%d %d", ErrNoTranscript, ticker, ref.Quarter, ref.Year)
+	}
+	row := rows[0]
+	return &EarningsCallTranscript{
+		Title:    fmt.Sprintf("%s Q%d %d Earnings Call", ticker, row.Quarter, row.Year),
+		Year:     row.Year,
+		Quarter:  row.Quarter,
+		Date:     transcriptDate(row.Date),
+		Source:   "fmp",
+		Segments: ParseTranscriptText(row.Content),
+	}, nil
+}
+
+// get issues a GET and maps auth and plan errors to non-retryable failures
+func (tc *TranscriptsClient) get(endpoint string, query map[string]string) ([]byte, error) {
+	req := tc.client.R().SetQueryParams(query)
+	if strings.HasPrefix(endpoint, finnhubBaseURL) {
+		req.SetHeader(finnhubTokenHeader, tc.finnhubKey)
+	}
+	resp, err := req.Get(endpoint)
+	if err != nil {
+		// FMP only takes its key as the apikey query parameter
+		return nil, fmt.Errorf("failed to fetch transcripts: %w", withoutQuery(err))
+	}
+	switch code := resp.StatusCode(); {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden || code == http.StatusPaymentRequired:
+		return nil, &noRetryError{fmt.Errorf("%s rejected the request (HTTP %d): check the API key and that the plan includes transcripts", tc.Provider(), code)}
+	case code != http.StatusOK:
+		return nil, fmt.Errorf("HTTP error %d when fetching transcripts", code)
+	}
+	return resp.Body(), nil
+}
+
+// latestTranscriptRef picks the newest call dated on or before the given day
+func latestTranscriptRef(refs []transcriptRef, before string) (transcriptRef, bool) {
+	sorted := append([]transcriptRef(nil), refs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Year != sorted[j].Year {
+			return sorted[i].Year > sorted[j].Year
+		}
+		return sorted[i].Quarter > sorted[j].Quarter
+	})
+	for _, ref := range sorted {
+		if before == "" || (ref.Date != "" && ref.Date <= before) {
+			return ref, true
+		}
+	}
+	return transcriptRef{}, false
+}
+
+// transcriptDate trims provider timestamps to YYYY-MM-DD
+func transcriptDate(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 10 {
+		return s[:10]
+	}
+	return s
+}
+
+var (
+	// speakerLine matches "Tim Cook: text" at the start of a line
+	speakerLine = regexp.MustCompile(`^([A-Z][\w.'\-]*(?: [A-Z][\w.'\-]*){0,4}):\s+(.*)$`)
+	// qaOpening matches the operator turn that opens the question-and-answer session
+	qaOpening = regexp.MustCompile(`(?i)question[- ]and[- ]answer|q&a|open (?:up )?(?:the )?(?:line|call|floor) (?:for|to) questions|first question`)
+	// analystIntro captures "question comes from Erik Woodring with Morgan Stanley"
+	analystIntro = regexp.MustCompile(`(?i)(?:question|comes|line) (?:comes |is )?from (?:the line of )?([A-Z][\w.'\-]*(?: [A-Z][\w.'\-]*){0,3})(?:,? (?:with|from|of|at) ([A-Z][\w.&'\- ]+?))?(?:[.,;]|\s+(?:please|your)|$)`)
+)
+
+// ParseTranscriptText splits a plain "Speaker: text" transcript into segments
+// and labels the question-and-answer session and the analysts asking questions
+func ParseTranscriptText(content string) []models.TranscriptSegment {
+	var segments []models.TranscriptSegment
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := speakerLine.FindStringSubmatch(line); m != nil {
+			segments = append(segments, models.TranscriptSegment{Speaker: m[1], Session: models.TranscriptSessionPrepared, Text: m[2]})
+			continue
+		}
+		if len(segments) > 0 {
+			segments[len(segments)-1].Text += "\n" + line
+		}
+	}
+
+	analysts := map[string]string{}
+	prepared := map[string]bool{}
+	inQA := false
+	for i := range segments {
+		seg := &segments[i]
+		if strings.EqualFold(seg.Speaker, "operator") {
+			seg.Role = "operator"
+			if !inQA && i > 0 && qaOpening.MatchString(seg.Text) {
+				inQA = true
+			}
+			for _, m := range analystIntro.FindAllStringSubmatch(seg.Text, -1) {
+				analysts[m[1]] = strings.TrimSpace(m[2])
+			}
+		}
+		if inQA {
+			seg.Session = models.TranscriptSessionQA
+		} else {
+			prepared[seg.Speaker] = true
+		}
+	}
+
+	for i := range segments {
+		seg := &segments[i]
+		if seg.Role != "" {
+			continue
+		}
+		firm, named := analysts[seg.Speaker]
+		switch {
+		case named:
+			seg.Role = "analyst"
+			seg.Affiliation = firm
+		case seg.Session == models.TranscriptSessionQA && len(analysts) == 0 && !prepared[seg.Speaker]:
+			// without operator introductions, a speaker first heard in Q&A is taken to be an analyst
+			seg.Role = "analyst"
+		default:
+			seg.Role = "executive"
+		}
+	}
+	return segments
+}
+
+// QAExchanges groups the question-and-answer session into analyst questions
+// and the management answers that follow each one
+func QAExchanges(t *EarningsCallTranscript) []models.TranscriptExchange {
+	var exchanges []models.TranscriptExchange
+	for _, seg := range t.Segments {
+		if seg.Session != models.TranscriptSessionQA || seg.Role == "operator" || strings.TrimSpace(seg.Text) == "" {
+			continue
+		}
+		if seg.Role == "analyst" {
+			// follow-ups from the same analyst extend the current exchange
+			if n := len(exchanges); n > 0 && exchanges[n-1].Analyst == seg.Speaker {
+				exchanges[n-1].Question += "\n" + seg.Text
+				continue
+			}
+			exchanges = append(exchanges, models.TranscriptExchange{Analyst: seg.Speaker, Firm: seg.Affiliation, Question: seg.Text})
+			continue
+		}
+		if len(exchanges) > 0 {
+			exchanges[len(exchanges)-1].Answers = append(exchanges[len(exchanges)-1].Answers, seg)
+		}
+	}
+	for i := range exchanges {
+		exchanges[i].Topics = transcriptTopics(exchanges[i].Question)
+	}
+	return exchanges
+}
+
+// transcriptTopicKeywords tags questions with the themes analysts probe most
+var transcriptTopicKeywords = []struct {
+	topic    string
+	keywords []string
+}{
+	{"guidance", []string{"guidance", "guide", "outlook", "next quarter", "full year", "full-year"}},
+	{"margins", []string{"margin", "profitability", "cost"}},
+	{"demand", []string{"demand", "orders", "backlog", "bookings", "pipeline"}},
+	{"pricing", []string{"pricing", "price increase", "asp", "discount"}},
+	{"capex", []string{"capex", "capital expenditure", "capacity", "investment"}},
+	{"capital return", []string{"buyback", "repurchase", "dividend"}},
+	{"supply chain", []string{"supply", "inventory", "component"}},
+	{"competition", []string{"competition", "competitor", "share gain", "market share"}},
+	{"china", []string{"china", "greater china"}},
+	{"ai", []string{" ai ", "artificial intelligence", "gpu", "generative"}},
+	{"regulation", []string{"regulat", "tariff", "antitrust", "export control"}},
+}
+
+func transcriptTopics(text string) []string {
+	// pad and blank out punctuation so short keywords like " ai " match whole words
+	lower := " " + strings.Map(func(r rune) rune {
+		if strings.ContainsRune(".,;:?!()\"", r) {
+			return ' '
+		}
+		return r
+	}, strings.ToLower(text)) + " "
+	var topics []string
+	for _, t := range transcriptTopicKeywords {
+		for _, kw := range t.keywords {
+			if strings.Contains(lower, kw) {
+				topics = append(topics, t.topic)
+				break
+			}
+		}
+	}
+	return topics
+}
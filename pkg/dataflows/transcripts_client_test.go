@@ -0,0 +1,131 @@
+package dataflows
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dyike/CortexGo/models"
+)
+
+const fmpSample = `Operator: Good day and welcome to the Apple Q1 2024 earnings conference call.
+Suhasini Chandramouli: Thank you. Joining me today are Tim Cook and Luca Maestri.
+Tim Cook: Revenue was a record for the December quarter.
+Services grew double digits.
+Operator: We will now begin the question-and-answer session. Our first question comes from Erik Woodring with Morgan Stanley. Please go ahead.
+Erik Woodring: What is your gross margin guidance for the March quarter?
+Luca Maestri: We expect gross margin between 46% and 47%.
+Erik Woodring: And how is demand in Greater China?
+Tim Cook: China was down year over year.
+Operator: Our next question comes from Ben Reitzes of Melius Research.
+Ben Reitzes: How should we think about the AI roadmap?
+Tim Cook: We are investing heavily.`
+
+func TestParseTranscriptTextGroupsQA(t *testing.T) {
+	segments := ParseTranscriptText(fmpSample)
+	if len(segments) != 11 {
+		t.Fatalf("segments = %d, want 11", len(segments))
+	}
+	if segments[2].Speaker != "Tim Cook" || !strings.Contains(segments[2].Text, "Services grew") || segments[2].Session != models.TranscriptSessionPrepared {
+		t.Errorf("continuation line not joined: %+v", segments[2])
+	}
+
+	exchanges := QAExchanges(&EarningsCallTranscript{Segments: segments})
+	if len(exchanges) != 2 {
+		t.Fatalf("exchanges = %+v", exchanges)
+	}
+	first := exchanges[0]
+	if first.Analyst != "Erik Woodring" || first.Firm != "Morgan Stanley" || len(first.Answers) != 2 {
+		t.Errorf("first exchange = %+v", first)
+	}
+	if !strings.Contains(first.Question, "Greater China") {
+		t.Errorf("follow-up not merged: %q", first.Question)
+	}
+	if strings.Join(first.Topics, ",") != "guidance,margins,demand,china" {
+		t.Errorf("topics = %v", first.Topics)
+	}
+	if exchanges[1].Firm != "Melius Research" || strings.Join(exchanges[1].Topics, ",") != "ai" {
+		t.Errorf("second exchange = %+v", exchanges[1])
+	}
+}
+
+func TestGetLatestTranscriptRespectsDateAndAuth(t *testing.T) {
+	var transcriptCalls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Finnhub-Token") != "good" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/stock/transcripts/list":
+			w.Write([]byte(`{"transcripts":[
+				{"id":"AAPL_2","time":"2024-05-02 20:30:00","year":2024,"quarter":2},
+				{"id":"AAPL_1","time":"2024-02-01 20:30:00","year":2024,"quarter":1}]}`))
+		case "/stock/transcripts":
+			transcriptCalls++
+			if r.URL.Query().Get("id") != "AAPL_1" {
+				t.Errorf("fetched %s, want the call before the trade date", r.URL.Query().Get("id"))
+			}
+			w.Write([]byte(`{"year":2024,"quarter":1,"time":"2024-02-01 20:30:00",
+				"participant":[{"name":"Erik Woodring","role":"analyst","description":"Morgan Stanley"},{"name":"Tim Cook","role":"executive"}],
+				"transcript":[
+					{"name":"Tim Cook","speech":["Record quarter."],"session":"management discussion"},
+					{"name":"Operator","speech":["First question please."],"session":"question answer"},
+					{"name":"Erik Woodring","speech":["What about margins?"],"session":"question answer"},
+					{"name":"Tim Cook","speech":["They expanded."],"session":"question answer"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	defer func(old string) { finnhubBaseURL = old }(finnhubBaseURL)
+	finnhubBaseURL = srv.URL
+
+	cfg := &Config{DataDir: t.TempDir(), DataCacheDir: t.TempDir(), CacheEnabled: true, FinnhubAPIKey: "good"}
+	tc := NewTranscriptsClient(cfg)
+	transcript, err := tc.GetLatestTranscript("aapl.us", "2024-03-15", cfg)
+	if err != nil {
+		t.Fatalf("GetLatestTranscript: %v", err)
+	}
+	if transcript.Symbol != "AAPL" || transcript.Quarter != 1 || transcript.Date != "2024-02-01" {
+		t.Errorf("transcript = %+v", transcript)
+	}
+	exchanges := QAExchanges(transcript)
+	if len(exchanges) != 1 || exchanges[0].Firm != "Morgan Stanley" || exchanges[0].Answers[0].Text != "They expanded." {
+		t.Errorf("exchanges = %+v", exchanges)
+	}
+	// the second lookup is served from cache
+	if _, err := tc.GetLatestTranscript("AAPL.US", "2024-03-15", cfg); err != nil || transcriptCalls != 1 {
+		t.Errorf("cached lookup: calls = %d, err = %v", transcriptCalls, err)
+	}
+
+	if _, err := NewTranscriptsClient(cfg).GetLatestTranscript("AAPL.US", "2023-12-31", cfg); !errors.Is(err, ErrNoTranscript) {
+		t.Errorf("want ErrNoTranscript before the first call, got %v", err)
+	}
+	if _, err := NewTranscriptsClient(cfg).GetLatestTranscript("700.HK", "", cfg); err == nil {
+		t.Error("want error for a non-US listing")
+	}
+
+	bad := &Config{DataDir: t.TempDir(), DataCacheDir: t.TempDir(), FinnhubAPIKey: "bad"}
+	if _, err := NewTranscriptsClient(bad).GetLatestTranscript("AAPL", "", bad); err == nil || !strings.Contains(err.Error(), "HTTP 403") || strings.Contains(err.Error(), "max retries") {
+		t.Errorf("want an immediate auth error, got %v", err)
+	}
+}
+
+func TestTranscriptFetchErrorsHideTheAPIKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close() // connections are refused
+	defer func(old string) { fmpBaseURL = old }(fmpBaseURL)
+	fmpBaseURL = srv.URL
+
+	tc := NewTranscriptsClient(&Config{FMPAPIKey: "secret-key"})
+	_, err := tc.listFMP("AAPL")
+	if err == nil {
+		t.Fatal("expected a transport error")
+	}
+	if strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("error leaks the API key: %v", err)
+	}
+}
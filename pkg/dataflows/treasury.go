@@ -0,0 +1,177 @@
+package dataflows
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/go-resty/resty/v2"
+)
+
+// treasuryBaseURL serves the daily par yield curve as one CSV per year; a
+// variable so tests can point it at a local server
+var treasuryBaseURL = "https://home.treasury.gov/resource-center/data-chart-center/interest-rates/daily-treasury-rates.csv/"
+
+// TreasuryClient fetches the US Treasury daily par yield curve. No key is
+// needed; the current year's file changes daily, so it is cached for hours
+type TreasuryClient struct {
+	client *resty.Client
+	cache  *CacheManager
+}
+
+// NewTreasuryClient creates a new treasury client
+func NewTreasuryClient(config *Config) *TreasuryClient {
+	return &TreasuryClient{
+		client: newHTTPClient(config, "CortexGo/1.0"),
+		cache:  newCacheManager(config, "treasury", 6*time.Hour),
+	}
+}
+
+// GetYieldCurves returns the daily curves from days calendar days before
+// before (YYYY-MM-DD, empty for today) up to it, oldest first
+func (tc *TreasuryClient) GetYieldCurves(before string, days int) ([]models.YieldCurve, error) {
+	end := time.Now()
+	if before != "" {
+		var err error
+		if end, err = time.Parse("2006-01-02", before); err != nil {
+			return nil, fmt.Errorf("invalid date %q: want YYYY-MM-DD", before)
+		}
+	}
+	start := end.AddDate(0, 0, -days)
+	from, to := start.Format("2006-01-02"), end.Format("2006-01-02")
+
+	var curves []models.YieldCurve
+	for year := start.Year(); year <= end.Year(); year++ {
+		yearly, err := tc.yearCurves(year)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range yearly {
+			if c.Date >= from && c.Date <= to {
+				curves = append(curves, c)
+			}
+		}
+	}
+	sort.SliceStable(curves, func(i, j int) bool { return curves[i].Date < curves[j].Date })
+	if len(curves) == 0 {
+		return nil, fmt.Errorf("no treasury yields between %s and %s", from, to)
+	}
+	return curves, nil
+}
+
+func (tc *TreasuryClient) yearCurves(year int) ([]models.YieldCurve, error) {
+	var cached []models.YieldCurve
+	if tc.cache.Get("treasury", "par_yield_curve", year, &cached) {
+		return cached, nil
+	}
+	if tc.cache.offline {
+		return nil, offlineMiss("treasury", strconv.Itoa(year))
+	}
+
+	var body []byte
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		resp, err := tc.client.R().
+			SetQueryParams(map[string]string{
+				"type":                 "daily_treasury_yield_curve",
+				"field_tdr_date_value": strconv.Itoa(year),
+				"_format":              "csv",
+			}).
+			Get(fmt.Sprintf("%s%d/all", treasuryBaseURL, year))
+		if err != nil {
+			return fmt.Errorf("failed to fetch treasury yields: %w", err)
+		}
+		if code := resp.StatusCode(); code != http.StatusOK {
+			return fmt.Errorf("HTTP error %d when fetching treasury yields", code)
+		}
+		body = resp.Body()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	curves, err := ParseYieldCurveCSV(body)
+	if err != nil {
+		return nil, err
+	}
+	tc.cache.Set("treasury", "par_yield_curve", year, curves)
+	return curves, nil
+}
+
+// ParseYieldCurveCSV reads the Treasury CSV ("Date","1 Mo",...,"30 Yr";
+// dates as MM/DD/YYYY). Tenors are shortened to 1M, 2Y, ... and blank
+// cells (tenors not yet issued that day) are left out.
+func ParseYieldCurveCSV(data []byte) ([]models.YieldCurve, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse treasury yields: %w", err)
+	}
+	if len(rows) < 2 || !strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(rows[0][0], "\ufeff")), "date") {
+		return nil, fmt.Errorf("unexpected treasury yield file layout")
+	}
+	tenors := make([]string, len(rows[0]))
+	for i, h := range rows[0][1:] {
+		tenors[i+1] = ShortTenor(h)
+	}
+	curves := make([]models.YieldCurve, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		day, err := time.Parse("01/02/2006", strings.TrimSpace(row[0]))
+		if err != nil {
+			continue
+		}
+		c := models.YieldCurve{Date: day.Format("2006-01-02"), Yields: map[string]float64{}}
+		for i := 1; i < len(row) && i < len(tenors); i++ {
+			if v, err := strconv.ParseFloat(strings.TrimSpace(row[i]), 64); err == nil && tenors[i] != "" {
+				c.Yields[tenors[i]] = v
+			}
+		}
+		if len(c.Yields) > 0 {
+			curves = append(curves, c)
+		}
+	}
+	return curves, nil
+}
+
+// ShortTenor turns a Treasury column header ("3 Mo", "10 Yr", "6 Wk") into
+// a compact tenor ("3M", "10Y", "6W"); unknown headers return ""
+func ShortTenor(header string) string {
+	n, unit, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok {
+		return ""
+	}
+	if _, err := strconv.ParseFloat(n, 64); err != nil {
+		return ""
+	}
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "wk", "week", "weeks":
+		return n + "W"
+	case "mo", "month", "months":
+		return n + "M"
+	case "yr", "year", "years":
+		return n + "Y"
+	}
+	return ""
+}
+
+// TenorYears is the length of a short tenor in years, for ordering the curve
+func TenorYears(tenor string) float64 {
+	if len(tenor) < 2 {
+		return 0
+	}
+	n, err := strconv.ParseFloat(tenor[:len(tenor)-1], 64)
+	if err != nil {
+		return 0
+	}
+	switch tenor[len(tenor)-1] {
+	case 'W':
+		return n / 52
+	case 'M':
+		return n / 12
+	}
+	return n
+}
@@ -0,0 +1,70 @@
+package dataflows
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const treasury2024 = "\ufeffDate,\"1 Mo\",\"3 Mo\",\"2 Yr\",\"10 Yr\",\"30 Yr\"\n" +
+	"12/31/2024,4.37,4.37,4.25,4.58,4.78\n" +
+	"12/30/2024,4.43,4.37,4.24,4.55,4.75\n"
+
+const treasury2025 = "Date,\"1 Mo\",\"1.5 Month\",\"3 Mo\",\"2 Yr\",\"10 Yr\",\"30 Yr\"\n" +
+	"01/03/2025,4.34,,4.33,4.28,4.60,4.82\n" +
+	"01/02/2025,4.38,,4.34,4.25,4.57,4.79\n"
+
+func TestParseYieldCurveCSV(t *testing.T) {
+	curves, err := ParseYieldCurveCSV([]byte(treasury2025))
+	if err != nil {
+		t.Fatalf("ParseYieldCurveCSV: %v", err)
+	}
+	if len(curves) != 2 || curves[0].Date != "2025-01-03" || curves[0].Yields["10Y"] != 4.60 || curves[1].Yields["1M"] != 4.38 {
+		t.Errorf("curves = %+v", curves)
+	}
+	if _, ok := curves[0].Yields["1.5M"]; ok {
+		t.Error("blank cells must be left out")
+	}
+	if _, err := ParseYieldCurveCSV([]byte("foo,bar\n1,2\n")); err == nil {
+		t.Error("want error for an unknown layout")
+	}
+	if TenorYears("3M") != 0.25 || TenorYears("10Y") != 10 || ShortTenor("6 Wk") != "6W" || ShortTenor("Date") != "" {
+		t.Error("tenor helpers")
+	}
+}
+
+func TestGetYieldCurvesSpansYears(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/2024/all":
+			w.Write([]byte(treasury2024))
+		case "/2025/all":
+			w.Write([]byte(treasury2025))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	defer func(old string) { treasuryBaseURL = old }(treasuryBaseURL)
+	treasuryBaseURL = srv.URL + "/"
+
+	cfg := &Config{DataCacheDir: t.TempDir(), CacheEnabled: true}
+	curves, err := NewTreasuryClient(cfg).GetYieldCurves("2025-01-02", 10)
+	if err != nil {
+		t.Fatalf("GetYieldCurves: %v", err)
+	}
+	if len(curves) != 3 || curves[0].Date != "2024-12-30" || curves[2].Date != "2025-01-02" {
+		t.Errorf("curves = %+v", curves)
+	}
+	if _, err := NewTreasuryClient(cfg).GetYieldCurves("2025-01-03", 10); err != nil || calls != 2 {
+		t.Errorf("cached lookup: calls = %d, err = %v", calls, err)
+	}
+
+	cfg.Offline = true
+	if _, err := NewTreasuryClient(cfg).GetYieldCurves("2023-06-30", 10); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline miss: %v", err)
+	}
+}
@@ -3,30 +3,54 @@ package dataflows
 import (
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/dyike/CortexGo/models"
+	"github.com/dyike/CortexGo/pkg/persist"
+	"github.com/dyike/CortexGo/pkg/secure"
 )
 
-// CacheManager handles file-based caching for data
+// CacheManager caches data source responses, on disk or in memory where the
+// platform has no filesystem (js/wasm).
 type CacheManager struct {
-	cacheDir     string
+	store        cacheStore
 	ttl          time.Duration
 	cacheEnabled bool
+	cipher       *secure.Cipher
+	// offline serves entries regardless of TTL and never expires them
+	offline bool
+	// pinned (seeded runs) also ignores TTL, so repeated runs read the same
+	// snapshot, but still fetches and stores what is missing
+	pinned bool
+
+	// lookup counts behind Provenance
+	statsMu sync.Mutex
+	hits    int
+	misses  int
+	oldest  time.Time // write time of the oldest entry served
 }
 
 // NewCacheManager creates a new cache manager
 func NewCacheManager(cacheDir string, ttl time.Duration, cacheEnabled bool) *CacheManager {
 	return &CacheManager{
-		cacheDir:     cacheDir,
+		store:        newCacheStore(cacheDir),
 		ttl:          ttl,
 		cacheEnabled: cacheEnabled,
 	}
 }
 
+// SetCipher encrypts cache entries at rest; nil disables encryption.
+func (cm *CacheManager) SetCipher(c *secure.Cipher) {
+	cm.cipher = c
+}
+
 // getCacheKey generates a cache key from parameters
 func (cm *CacheManager) getCacheKey(source, method string, params interface{}) string {
 	data, _ := json.Marshal(params)
@@ -36,31 +60,59 @@ func (cm *CacheManager) getCacheKey(source, method string, params interface{}) s
 
 // Get retrieves data from cache if not expired
 func (cm *CacheManager) Get(source, method string, params interface{}, result interface{}) bool {
-	if !cm.cacheEnabled {
+	modTime, ok := cm.lookup(source, method, params, result)
+	cm.statsMu.Lock()
+	defer cm.statsMu.Unlock()
+	if !ok {
+		cm.misses++
 		return false
 	}
+	cm.hits++
+	if cm.oldest.IsZero() || modTime.Before(cm.oldest) {
+		cm.oldest = modTime
+	}
+	return true
+}
 
-	key := cm.getCacheKey(source, method, params)
-	filePath := filepath.Join(cm.cacheDir, key)
+// Provenance describes how the lookups made so far were served: all from
+// cache, all fetched live, or a mix. FetchedAt is when the oldest cached
+// entry served was written, or now when nothing came from the cache.
+func (cm *CacheManager) Provenance(source string) models.Provenance {
+	cm.statsMu.Lock()
+	defer cm.statsMu.Unlock()
+	p := models.Provenance{Source: source, Mode: models.ProvenanceLive, FetchedAt: time.Now(), CacheHits: cm.hits, CacheMisses: cm.misses}
+	if cm.hits > 0 {
+		p.FetchedAt = cm.oldest
+		p.Mode = models.ProvenanceCache
+		if cm.misses > 0 {
+			p.Mode = models.ProvenanceMixed
+		}
+	}
+	return p
+}
 
-	// Check if file exists and is not expired
-	info, err := os.Stat(filePath)
-	if err != nil {
-		return false
+func (cm *CacheManager) lookup(source, method string, params interface{}, result interface{}) (time.Time, bool) {
+	if !cm.cacheEnabled && !cm.offline {
+		return time.Time{}, false
 	}
 
-	if time.Since(info.ModTime()) > cm.ttl {
-		os.Remove(filePath) // Remove expired cache
-		return false
+	key := cm.getCacheKey(source, method, params)
+	data, modTime, ok := cm.store.read(key)
+	if !ok {
+		return modTime, false
 	}
 
-	// Read and unmarshal cached data
-	data, err := os.ReadFile(filePath)
+	if !cm.offline && !cm.pinned && time.Since(modTime) > cm.ttl {
+		cm.store.remove(key) // Remove expired cache
+		return modTime, false
+	}
+
+	data, err := cm.cipher.Open(data)
 	if err != nil {
-		return false
+		return modTime, false
 	}
 
-	return json.Unmarshal(data, result) == nil
+	return modTime, json.Unmarshal(data, result) == nil
 }
 
 // Set stores data in cache
@@ -69,21 +121,47 @@ func (cm *CacheManager) Set(source, method string, params interface{}, data inte
 		return nil
 	}
 
-	key := cm.getCacheKey(source, method, params)
-	filePath := filepath.Join(cm.cacheDir, key)
-
-	// Ensure cache directory exists
-	if err := os.MkdirAll(cm.cacheDir, 0755); err != nil {
-		return err
-	}
-
 	// Marshal and write data
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		return err
 	}
+	if jsonData, err = cm.cipher.Seal(jsonData); err != nil {
+		return err
+	}
 
-	return os.WriteFile(filePath, jsonData, 0644)
+	return cm.store.write(cm.getCacheKey(source, method, params), jsonData)
+}
+
+// newCacheManager builds a cache manager for a data source, encrypting entries
+// when an encryption key is configured. An unusable key disables the cache
+// rather than writing plaintext.
+func newCacheManager(config *Config, source string, ttl time.Duration) *CacheManager {
+	cm := NewCacheManager(filepath.Join(config.DataCacheDir, source), ttl, config.CacheEnabled)
+	if !persist.Local(config) {
+		// sqlite and s3 backends (or an embedder's own) hold the cache too
+		s, err := persist.For(config)
+		if err != nil {
+			fmt.Printf("cache %s disabled: %v\n", source, err)
+			cm.cacheEnabled = false
+			return cm
+		}
+		cm.store = storageStore{s: s, source: source}
+	}
+	// simulated data mode leaves the sources it does not generate offline
+	cm.offline = config.Offline || config.SimulatedData()
+	if config.Seed > 0 {
+		// a seeded run needs the cache to pin its data
+		cm.pinned, cm.cacheEnabled = true, true
+	}
+	cipher, err := secure.ForConfig(config)
+	if err != nil {
+		fmt.Printf("cache %s disabled: %v\n", source, err)
+		cm.cacheEnabled = false
+		return cm
+	}
+	cm.SetCipher(cipher)
+	return cm
 }
 
 // RetryConfig configures retry behavior
@@ -118,7 +196,16 @@ func WithRetry(config *RetryConfig, fn func() error) error {
 			time.Sleep(delay)
 		}
 
-		if err := fn(); err != nil {
+		err := fn()
+		recordAttempt(err)
+		if err != nil {
+			if errors.Is(err, ErrOffline) || errors.Is(err, ErrCircuitOpen) {
+				return err
+			}
+			var permanent *noRetryError
+			if errors.As(err, &permanent) {
+				return permanent.err
+			}
 			lastErr = err
 			continue
 		}
@@ -126,9 +213,21 @@ func WithRetry(config *RetryConfig, fn func() error) error {
 		return nil
 	}
 
-	return fmt.Errorf("max retries exceeded: %w", lastErr)
+	return fmt.Errorf("%w: %w", ErrRetriesExhausted, lastErr)
 }
 
+// ErrRetriesExhausted wraps the last error of a call that failed on every attempt.
+var ErrRetriesExhausted = errors.New("max retries exceeded")
+
+// noRetryError marks a failure that retrying cannot fix, such as a rejected API key
+type noRetryError struct {
+	err error
+}
+
+func (e *noRetryError) Error() string { return e.err.Error() }
+
+func (e *noRetryError) Unwrap() error { return e.err }
+
 // pow is a simple power function for floats
 func pow(base, exp float64) float64 {
 	result := 1.0
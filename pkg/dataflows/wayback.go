@@ -0,0 +1,108 @@
+package dataflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// waybackAvailableURL is the Wayback Machine availability API, which returns
+// the snapshot of a URL closest to now.
+var waybackAvailableURL = "https://archive.org/wayback/available"
+
+// minArticleChars is the extracted length below which a page is taken for a
+// paywall teaser or an error page and its archived copy is tried.
+const minArticleChars = 200
+
+// ArticleContent is the text extracted from an article page. Archive is set
+// when the live page failed or came back near-empty and the text was read
+// from a Wayback Machine snapshot instead.
+type ArticleContent struct {
+	Text    string           `json:"text"`
+	Archive *WaybackSnapshot `json:"archive,omitempty"`
+}
+
+// WaybackSnapshot is an archived copy of a page.
+type WaybackSnapshot struct {
+	URL       string    `json:"url"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// annotate records in the article's metadata that its content came from the
+// archive. It does nothing for a nil snapshot.
+func (s *WaybackSnapshot) annotate(a *NewsArticle) {
+	if s == nil {
+		return
+	}
+	if a.Metadata == nil {
+		a.Metadata = map[string]string{}
+	}
+	a.Metadata["content_source"] = "wayback"
+	a.Metadata["archive_url"] = s.URL
+	if !s.Timestamp.IsZero() {
+		a.Metadata["archive_time"] = s.Timestamp.Format(time.RFC3339)
+	}
+}
+
+// closestSnapshot asks the availability API for the latest archived copy of
+// pageURL.
+func (gnc *GoogleNewsClient) closestSnapshot(pageURL string) (*WaybackSnapshot, error) {
+	var body struct {
+		ArchivedSnapshots struct {
+			Closest *struct {
+				Available bool   `json:"available"`
+				URL       string `json:"url"`
+				Timestamp string `json:"timestamp"`
+				Status    string `json:"status"`
+			} `json:"closest"`
+		} `json:"archived_snapshots"`
+	}
+	err := WithRetry(DefaultRetryConfig(), func() error {
+		resp, err := gnc.client.R().SetQueryParam("url", pageURL).Get(waybackAvailableURL)
+		if err != nil {
+			return fmt.Errorf("wayback lookup: %w", err)
+		}
+		if resp.StatusCode() != 200 {
+			return fmt.Errorf("wayback lookup: HTTP %d", resp.StatusCode())
+		}
+		if err := json.Unmarshal(resp.Body(), &body); err != nil {
+			return &noRetryError{fmt.Errorf("wayback lookup: %w", err)}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	closest := body.ArchivedSnapshots.Closest
+	if closest == nil || !closest.Available || closest.URL == "" || closest.Status != "" && closest.Status != "200" {
+		return nil, fmt.Errorf("no wayback snapshot of %s", pageURL)
+	}
+	snapshot := &WaybackSnapshot{URL: closest.URL}
+	snapshot.Timestamp, _ = time.Parse("20060102150405", closest.Timestamp)
+	if u, err := url.Parse(closest.URL); err == nil && strings.HasSuffix(u.Host, "archive.org") {
+		u.Scheme = "https"
+		snapshot.URL = u.String()
+	}
+	return snapshot, nil
+}
+
+// fetchWaybackArticle extracts pageURL's text from its closest snapshot. The
+// raw capture ("id_") is fetched so the archive's toolbar and rewritten links
+// do not end up in the text.
+func (gnc *GoogleNewsClient) fetchWaybackArticle(pageURL string) (ArticleContent, error) {
+	snapshot, err := gnc.closestSnapshot(pageURL)
+	if err != nil {
+		return ArticleContent{}, err
+	}
+	rawURL := snapshot.URL
+	if ts := snapshot.Timestamp.Format("20060102150405"); !snapshot.Timestamp.IsZero() {
+		rawURL = strings.Replace(rawURL, "/"+ts+"/", "/"+ts+"id_/", 1)
+	}
+	text, err := gnc.fetchArticlePage(rawURL)
+	if err != nil {
+		return ArticleContent{}, err
+	}
+	return ArticleContent{Text: text, Archive: snapshot}, nil
+}
@@ -0,0 +1,49 @@
+package dataflows
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetchArticleFallsBackToWayback(t *testing.T) {
+	const ts = "20240102030405"
+	body := strings.Repeat("<p>The company raised its full-year outlook on strong data center demand.</p>", 4)
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/paywalled":
+			fmt.Fprint(w, "<html><body><p>To keep reading, become a subscriber today.</p></body></html>")
+		case r.URL.Path == "/gone":
+			http.NotFound(w, r)
+		case r.URL.Path == "/wayback/available":
+			fmt.Fprintf(w, `{"archived_snapshots":{"closest":{"available":true,"status":"200","timestamp":%q,"url":%q}}}`,
+				ts, srv.URL+"/web/"+ts+"/"+r.URL.Query().Get("url"))
+		case strings.HasPrefix(r.URL.Path, "/web/"+ts+"id_/"):
+			fmt.Fprintf(w, "<html><body><article>%s</article></body></html>", body)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	defer func(prev string) { waybackAvailableURL = prev }(waybackAvailableURL)
+	waybackAvailableURL = srv.URL + "/wayback/available"
+
+	gnc := NewGoogleNewsClient(&Config{DataCacheDir: t.TempDir(), IgnoreRobots: true, CrawlDelay: 1})
+	for _, path := range []string{"/paywalled", "/gone"} {
+		page, err := gnc.FetchArticle(srv.URL + path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if page.Archive == nil || !strings.Contains(page.Text, "full-year outlook") {
+			t.Fatalf("%s: expected archived text, got %+v", path, page)
+		}
+		article := &NewsArticle{URL: srv.URL + path}
+		page.Archive.annotate(article)
+		if article.Metadata["content_source"] != "wayback" || article.Metadata["archive_time"] != "2024-01-02T03:04:05Z" {
+			t.Errorf("%s: metadata = %v", path, article.Metadata)
+		}
+	}
+}
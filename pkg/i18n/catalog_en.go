@@ -0,0 +1,215 @@
+package i18n
+
+// catalogEn is the reference catalog; every key must exist here.
+var catalogEn = map[string]string{
+	"usage":            "Usage of %s:\n",
+	"alerts.usage":     "Usage: %s alerts add <symbol> -below|-above|-move <value> [-repeat] | list [symbol] [-all] | rm <id> | watch [-interval seconds]\n",
+	"experiment.usage": "Usage: %s experiment run -baseline <config.json> -candidate <config.json> -f <symbols.txt> [-date YYYY-MM-DD]\n",
+	"replay.usage":     "Usage: %s replay <run-id> [-speed 1] [-max-pause 5]\n",
+	"journal.usage":    "Usage: %s journal add [symbol] [-run <run-id>] -qty <n> -price <p> [-side long|short] [-date YYYY-MM-DD] [-fees f] [-notes text] | close <id> -price <p> [-date] [-fees] [-notes] | list [symbol] [-open] [-run <run-id>] | stats | rm <id>\n",
+	"index.usage":      "Usage: %s index list <index> [-sector text] | breadth <index> [-date YYYY-MM-DD] [-sector text]   (index: SP500, NDX, HSI)\n",
+	"watch.usage":      "Usage: %s watch <symbol> [-interval 15m] [-move 2] [-min-quality 0.85]\n",
+	"sectors.usage":    "Usage: %s sectors [-market US] [-date YYYY-MM-DD] [-analyze N]\n",
+
+	"flag.config":           "config file (default: $CORTEXGO_CONFIG, ./cortexgo.json, then <user config dir>/cortexgo/config.json)",
+	"flag.symbol":           "symbol to analyze",
+	"flag.date":             "trade date (YYYY-MM-DD)",
+	"flag.raw":              "print raw callback events as JSON instead of streaming text",
+	"flag.output":           "result format: text, json or yaml",
+	"flag.validate":         "validate the resolved config, listing every violation with its source, and exit",
+	"flag.strict":           "with -validate: also enforce strict rules (API keys, SMTP host) and reject unknown keys",
+	"flag.config_schema":    "print the JSON Schema of the config and exit",
+	"flag.print_env":        "print the CORTEXGO_* environment variable for every config field and exit",
+	"flag.print_config":     "print the effective config (secrets redacted) and exit",
+	"flag.quote":            "print live quotes for comma separated symbols, then exit",
+	"flag.news":             "print recent headlines with sentiment for a symbol, then exit",
+	"flag.source":           "news source for -news: google, rss or reddit",
+	"flag.days":             "lookback window in days for -news",
+	"flag.new_only":         "with -news -source rss, print only items not returned by an earlier -new-only run (for cron jobs)",
+	"flag.min_quality":      "drop -news items whose source quality is below this score (0-1, e.g. 0.6 hides opinion and unknown sites)",
+	"flag.export":           "export -news results to a .csv or .json file",
+	"flag.indicators":       "print technical indicators for a symbol, then exit",
+	"flag.lookback":         "number of trading days for -indicators",
+	"flag.format":           "table format for -indicators: table, csv or json (defaults to -output)",
+	"flag.doctor":           "probe configured providers and the local environment, then exit",
+	"flag.batch":            "analyze comma separated symbols as a resumable batch, then exit",
+	"flag.index":            "batch-analyze every constituent of an index (SP500, NDX, HSI), then exit",
+	"flag.sector":           "-index / index: only constituents whose sector contains this text",
+	"flag.resume":           "resume an interrupted batch by id, skipping completed symbols",
+	"flag.c":                "maximum number of symbols a batch analyzes in parallel",
+	"flag.watch":            "with -batch/-resume: reload the config file on change; later symbols use the new settings",
+	"flag.adaptive":         "adapt batch concurrency to rate limits and data source errors (false: always use -c workers)",
+	"flag.depth":            "analysis depth preset: quick, standard or deep (defaults to config)",
+	"flag.risk":             "risk profile for the risk team: conservative, balanced or aggressive (defaults to config)",
+	"flag.llm":              "LLM provider: deepseek, or mock for canned responses without an API key (defaults to config)",
+	"flag.data":             "data provider: live, or simulated for generated candles, news and posts without network access (defaults to config)",
+	"flag.dry_run":          "print the resolved plan (agents, tools, models, token and cost estimate) without running",
+	"flag.offline":          "serve all tools from cache and local archives only, failing fast on missing data",
+	"flag.tool_data":        "also write every raw tool result of the run to a zip of JSON and CSV under results_dir (defaults to config)",
+	"flag.seed":             "seeded run for comparisons: temperature 0 with this LLM seed, cached data pinned regardless of age (defaults to config)",
+	"flag.plain":            "plain output: no color, emoji or box-drawing characters (also NO_COLOR)",
+	"flag.ingest":           "ingest a document (pdf, txt, md or html) for the fundamentals analyst, tagged with -symbol if given, then exit",
+	"flag.kind":             "document type for -ingest: annual_report, broker_report, earnings_slides, filing or other",
+	"flag.title":            "document title for -ingest (defaults to the file name)",
+	"flag.portfolio":        "portfolio: show the stored holdings (show), pull them from the Longport account (sync, or sync:<account> for a named one), import a CSV file (path) or check return correlations and concentration (risk), then exit",
+	"flag.watchlist":        "-portfolio risk: comma-separated symbols to check instead of the holdings",
+	"flag.window":           "-portfolio risk: number of daily returns for the correlations (20-250)",
+	"flag.baseline":         "experiment: config file of the baseline arm",
+	"flag.candidate":        "experiment: config file of the candidate arm",
+	"flag.cases":            "experiment: file listing one symbol per line, optionally followed by a trade date",
+	"flag.replay_speed":     "replay: playback speed relative to the original run (e.g. 10); 0 prints the whole log without pauses",
+	"flag.replay_max_pause": "replay: longest pause in seconds for one message after applying -speed",
+	"flag.journal_run":      "journal: id of the analysis run the trade acted on (add) or to filter by (list)",
+	"flag.journal_side":     "journal add: long or short (defaults to the run's recommendation: SELL is short, otherwise long)",
+	"flag.journal_qty":      "journal add: quantity traded",
+	"flag.journal_price":    "journal: entry price (add) or exit price (close)",
+	"flag.journal_date":     "journal: entry date (add) or exit date (close), YYYY-MM-DD (default today)",
+	"flag.journal_fees":     "journal: fees paid, deducted from the realized P&L",
+	"flag.journal_currency": "journal add: quote currency (defaults from the market suffix)",
+	"flag.journal_notes":    "journal: free-form notes (appended on close)",
+	"flag.journal_open":     "journal list: only trades not yet closed",
+	"flag.alert_above":      "alerts add: trigger when the last price is at or above this value",
+	"flag.alert_below":      "alerts add: trigger when the last price is at or below this value",
+	"flag.alert_move":       "alerts add: trigger when the day's move from the previous close reaches this percent either way",
+	"flag.alert_repeat":     "alerts add: keep the alert after it fires; it re-arms once the condition clears",
+	"flag.alert_all":        "alerts list: include one-shot alerts that already fired",
+	"flag.alert_interval":   "alerts watch: seconds between quote checks (minimum 10)",
+	"flag.watch_interval":   "watch: time between quote and news polls, e.g. 15m (minimum 1m)",
+	"flag.watch_move":       "watch: re-analyze when the price moves this percent since the last analysis",
+	"flag.watch_quality":    "watch: re-analyze on news from sources at or above this quality (0-1); strongly worded news always counts",
+	"flag.watch_full":       "watch: refresh with a quick full analysis instead of the delta analyst",
+	"flag.market":           "sectors: market whose sector ETFs to rank (US)",
+	"flag.sectors_analyze":  "sectors: run quick analyses on the N best ranked sector ETFs (0: ranking only)",
+	"flag.lang":             "language of command line output: en or zh-CN (defaults to config locale, then LANG)",
+
+	"err.depth":           "invalid -depth %q: want quick, standard or deep",
+	"err.risk":            "invalid -risk %q: want conservative, balanced or aggressive",
+	"err.llm":             "invalid -llm %q: want deepseek or mock",
+	"err.data":            "invalid -data %q: want live or simulated",
+	"err.portfolio_mode":  "invalid -portfolio %q: want show, sync, sync:<account>, risk or a .csv file",
+	"err.alert_id":        "invalid alert id %q",
+	"err.journal_id":      "invalid journal entry id %q",
+	"err.output_format":   "unsupported output format %q (supported: text, json, yaml)",
+	"err.watch_config":    "-watch needs a config file (-config, $CORTEXGO_CONFIG or ./cortexgo.json)",
+	"err.watch_batch":     "-watch only applies to -batch and -resume",
+	"err.offline_missing": "offline mode: missing local data:",
+
+	"result.error":          "Error:",
+	"result.data_bundle":    "tool data: %s",
+	"result.run_dir":        "run files: %s",
+	"result.run_dir_failed": "write run directory: %v",
+	"result.bundle_failed":  "write tool data bundle: %v",
+
+	"config.env_header":      "ENV\tFIELD\tTYPE",
+	"config.defaults":        "defaults and environment",
+	"config.ok":              "config OK (%s)",
+	"config.problems":        "config %s: %d problem(s)",
+	"config.problems_header": "FIELD\tSOURCE\tRULE\tPROBLEM",
+
+	"stream.tool_result": "-> %s returned %d bytes",
+	"stream.tool_call":   "-> call %s %s",
+
+	"batch.start":           "batch %s: %d/%d symbols to analyze for %s (resume with -resume %s)",
+	"batch.reload_failed":   "config reload failed, keeping previous settings: %v",
+	"batch.reloaded":        "config reloaded: %s",
+	"batch.restart":         "config: %s change on restart",
+	"batch.offline_missing": "offline mode: missing local data: %s",
+	"batch.concurrency":     "concurrency %d -> %d (%s)",
+	"batch.done":            "batch %s: %d completed, %d failed, %d pending",
+	"batch.report_failed":   "write batch report: %v",
+	"batch.report":          "batch report: %s",
+	"batch.shared":          "shared market data: %d fetched, reused %d times",
+	"batch.correlation":     "correlation check skipped: %v",
+	"batch.interrupted":     "interrupted; continue with -resume %s",
+	"batch.header":          "RANK\tSYMBOL\tRECOMMENDATION\tCONFIDENCE\tSTATUS",
+
+	"experiment.start":    "experiment %s: %d case(s), baseline %s vs candidate %s",
+	"experiment.case":     "[%d/%d] %s %s: %s -> %s",
+	"experiment.summary":  "same decision in %d of %d compared case(s); cost $%.4f -> $%.4f; mean latency %.1fs -> %.1fs",
+	"experiment.report":   "experiment report: %s",
+	"experiment.failed":   "write experiment report: %v",
+	"experiment.header":   "SYMBOL\tDATE\tBASELINE\tCANDIDATE\tCOST\tLATENCY",
+	"experiment.no_cases": "-f is required: a file with one symbol (and optional trade date) per line",
+	"experiment.configs":  "-baseline and -candidate are both required",
+	"replay.bad_id":       "invalid run id %q: want the numeric session id from agent.history.list",
+	"replay.empty":        "run %d has no recorded events (only runs started from the app or by price alerts are recorded)",
+	"replay.start":        "replaying run %d: %s %s, %s, %d message(s)",
+	"replay.done":         "end of run %d (%s)",
+	"replay.interrupted":  "replay of run %d interrupted",
+
+	"doctor.header": "CHECK\tSTATUS\tDETAIL",
+
+	"plan.title":          "Plan for %s on %s, depth %s (max %d tool steps per analyst)",
+	"plan.offline":        " (offline)",
+	"plan.steps_header":   "STAGE\tAGENT\tMODEL\tCALLS\tTOKENS IN/OUT\tTOOLS",
+	"plan.sources_header": "SOURCE\tMODE\tDETAIL",
+	"plan.estimate":       "Estimated: %d LLM calls, %d input + %d output tokens, ~$%.4f",
+	"plan.warning":        "warning: %s",
+
+	"quote.header": "SYMBOL\tLAST\tCHANGE\tCHANGE%\tVOLUME\t52W LOW\t52W HIGH\tSESSION\tEXTENDED HOURS\t",
+
+	"news.header":   "DATE\tSENTIMENT\tSOURCE\tTITLE",
+	"news.summary":  "%d item(s) from %s, average sentiment %+.2f",
+	"news.exported": "exported to %s",
+
+	"indicators.date":  "date",
+	"indicators.close": "close",
+
+	"ingest.any_symbol": "any symbol",
+	"ingest.done":       "ingested #%d %q (%s, %s): %d page(s), %d chunk(s)",
+
+	"portfolio.summary":    "%s snapshot, synced %s",
+	"portfolio.net_assets": "net assets: %s %.2f",
+	"portfolio.header":     "SYMBOL\tNAME\tQUANTITY\tAVAILABLE\tCOST\tCURRENCY\t",
+	"portfolio.cash":       "CURRENCY\tAVAILABLE\tFROZEN\t",
+	"portfolio.empty":      "no open stock positions",
+	"portfolio.risk":       "correlation of daily returns over %d days up to %s (average %.2f)",
+	"portfolio.missing":    "not enough price history: %s",
+	"portfolio.flags":      "concentration flags:",
+	"portfolio.no_flags":   "no concentration flags",
+
+	"alerts.added":            "added alert #%d: %s",
+	"alerts.deleted":          "deleted alert #%d",
+	"alerts.none":             "no alerts",
+	"alerts.header":           "ID\tSYMBOL\tCONDITION\tTHRESHOLD\tREPEAT\tSTATUS\tLAST TRIGGERED\t",
+	"alerts.watching":         "watching %d alert(s), checking quotes every %s; press Ctrl+C to stop",
+	"alerts.triggered":        "alert %s triggered at %.2f: started analysis session %s",
+	"alerts.trigger_failed":   "alert %s triggered at %.2f but the analysis did not start: %s",
+	"journal.added":           "journal entry #%d: %s",
+	"journal.closed":          "closed journal entry #%d (%s): realized %+.2f %s (%+.2f%%)",
+	"journal.deleted":         "deleted journal entry #%d",
+	"journal.none":            "no journal entries",
+	"journal.header":          "ID\tSYMBOL\tSIDE\tQTY\tENTRY\tEXIT\tRUN\tREALIZED P&L",
+	"journal.stats":           "%d trade(s): %d open, %d closed; win rate %.0f%%, average return %+.2f%%",
+	"journal.followed":        "%d closed trade(s) followed the run's recommendation, average return %+.2f%%",
+	"journal.pnl":             "realized P&L %s: %+.2f",
+	"index.summary":           "%s (%s): %d constituent(s), listed by %s, fetched %s",
+	"index.header":            "SYMBOL\tNAME\tSECTOR",
+	"index.breadth":           "%s breadth on %s: %d of %d members with data",
+	"index.advance":           "advancers / decliners: %d / %d (%d unchanged)",
+	"index.above":             "above 50-day average: %.0f%%, above 200-day average: %.0f%%",
+	"index.highs":             "52-week highs / lows: %d / %d",
+	"index.missing":           "not enough price history: %s",
+	"index.sector_header":     "SECTOR\tMEMBERS\tABOVE 50-DAY",
+	"batch.index":             "%s: %d constituent(s) to analyze",
+	"err.index_batch":         "-index cannot be combined with -batch or -resume",
+	"watch.started":           "watching %s, polling every %s; the first poll runs a full analysis. Press Ctrl+C to stop",
+	"watch.analyzed":          "[%s] %s %.2f: %s analysis -> %s",
+	"watch.failed":            "[%s] %s %.2f: %s analysis failed: %s",
+	"watch.quiet":             "[%s] %s %.2f (%+.2f%% since the last analysis), %d new headline(s), no material change",
+	"watch.reason":            "  - %s",
+	"watch.news_err":          "  news unavailable: %s",
+	"sectors.summary":         "%s sector rotation as of %s, relative to %s: %s leadership",
+	"sectors.header":          "RANK\tSYMBOL\tSECTOR\tPHASE\tSCORE",
+	"sectors.header_rel":      "VS BENCH %dD",
+	"sectors.benchmark":       "benchmark",
+	"sectors.legend":          "score: average rank of the returns relative to the benchmark across the windows, lower is stronger; * defensive sector",
+	"sectors.analyze":         "running quick analyses on %d leading sector ETF(s): %s",
+	"sectors.tilt.cyclical":   "cyclical",
+	"sectors.tilt.defensive":  "defensive",
+	"sectors.tilt.mixed":      "mixed",
+	"sectors.phase.leading":   "leading",
+	"sectors.phase.weakening": "weakening",
+	"sectors.phase.lagging":   "lagging",
+	"sectors.phase.improving": "improving",
+}
@@ -0,0 +1,215 @@
+package i18n
+
+// catalogZhCN holds the Simplified Chinese translations.
+var catalogZhCN = map[string]string{
+	"usage":            "用法：%s [参数]\n",
+	"alerts.usage":     "用法：%s alerts add <标的> -below|-above|-move <数值> [-repeat] | list [标的] [-all] | rm <id> | watch [-interval 秒]\n",
+	"experiment.usage": "用法：%s experiment run -baseline <配置.json> -candidate <配置.json> -f <标的列表.txt> [-date YYYY-MM-DD]\n",
+	"replay.usage":     "用法：%s replay <运行 id> [-speed 1] [-max-pause 5]\n",
+	"journal.usage":    "用法：%s journal add [标的] [-run <运行 id>] -qty <数量> -price <价格> [-side long|short] [-date YYYY-MM-DD] [-fees 手续费] [-notes 备注] | close <id> -price <价格> [-date] [-fees] [-notes] | list [标的] [-open] [-run <运行 id>] | stats | rm <id>\n",
+	"index.usage":      "用法：%s index list <指数> [-sector 行业] | breadth <指数> [-date YYYY-MM-DD] [-sector 行业]（指数：SP500、NDX、HSI）\n",
+	"watch.usage":      "用法：%s watch <标的> [-interval 15m] [-move 2] [-min-quality 0.85]\n",
+	"sectors.usage":    "用法：%s sectors [-market US] [-date YYYY-MM-DD] [-analyze N]\n",
+
+	"flag.config":           "配置文件（默认依次查找 $CORTEXGO_CONFIG、./cortexgo.json、<用户配置目录>/cortexgo/config.json）",
+	"flag.symbol":           "要分析的标的",
+	"flag.date":             "交易日期（YYYY-MM-DD）",
+	"flag.raw":              "输出原始回调事件 JSON，而不是流式文本",
+	"flag.output":           "结果格式：text、json 或 yaml",
+	"flag.validate":         "校验生效配置，列出每个违规字段及其来源后退出",
+	"flag.strict":           "配合 -validate：额外检查严格规则（API Key、SMTP 主机）并拒绝未知键",
+	"flag.config_schema":    "输出配置的 JSON Schema 后退出",
+	"flag.print_env":        "列出每个配置字段对应的 CORTEXGO_* 环境变量后退出",
+	"flag.print_config":     "输出生效配置（隐藏密钥）后退出",
+	"flag.quote":            "输出实时行情（标的以逗号分隔）后退出",
+	"flag.news":             "输出标的近期新闻标题与情绪分后退出",
+	"flag.source":           "-news 的新闻来源：google、rss 或 reddit",
+	"flag.days":             "-news 的回看天数",
+	"flag.new_only":         "配合 -news -source rss，只输出之前 -new-only 运行未返回过的条目（用于定时任务）",
+	"flag.min_quality":      "-news 过滤来源可信度低于该分值（0~1，如 0.6 可隐藏观点类与未知网站）的新闻",
+	"flag.export":           "将 -news 结果导出为 .csv 或 .json 文件",
+	"flag.indicators":       "输出标的技术指标后退出",
+	"flag.lookback":         "-indicators 的交易日数量",
+	"flag.format":           "-indicators 的表格格式：table、csv 或 json（默认同 -output）",
+	"flag.doctor":           "探测已配置的数据源与本地环境后退出",
+	"flag.batch":            "以可恢复批次分析逗号分隔的多个标的后退出",
+	"flag.index":            "以批次分析指数（SP500、NDX、HSI）的全部成分股后退出",
+	"flag.sector":           "-index / index：只取行业名包含该文本的成分股",
+	"flag.resume":           "按 ID 恢复中断的批次，跳过已完成的标的",
+	"flag.c":                "批次中并行分析的最大标的数",
+	"flag.watch":            "配合 -batch/-resume：配置文件变更时重新加载，之后的标的使用新配置",
+	"flag.adaptive":         "根据限流与数据源错误自动调整批次并发（false：始终使用 -c 个 worker）",
+	"flag.depth":            "分析深度预设：quick、standard 或 deep（默认取配置）",
+	"flag.risk":             "风险偏好：conservative、balanced 或 aggressive（默认取配置）",
+	"flag.llm":              "LLM 提供方：deepseek，或 mock（返回预设回复，无需 API Key）（默认取配置）",
+	"flag.data":             "数据提供方：live，或 simulated（本地生成K线、新闻与帖子，不访问网络）（默认取配置）",
+	"flag.dry_run":          "只输出执行计划（agent、工具、模型、token 与费用估算），不实际运行",
+	"flag.offline":          "工具只读取缓存与本地归档，缺失数据时立即失败",
+	"flag.tool_data":        "同时将本次全部工具原始结果打包为 zip（JSON 与 CSV），写入 results_dir（默认取配置）",
+	"flag.seed":             "固定种子运行，便于对比：温度为 0 并使用该 LLM 种子，缓存数据不过期（默认取配置）",
+	"flag.plain":            "纯文本输出：不使用颜色、emoji 与制表符（也可设置 NO_COLOR）",
+	"flag.ingest":           "导入文档（pdf、txt、md 或 html）供基本面分析师检索，指定 -symbol 时关联该标的，完成后退出",
+	"flag.kind":             "-ingest 的文档类型：annual_report、broker_report、earnings_slides、filing 或 other",
+	"flag.title":            "-ingest 的文档标题（默认取文件名）",
+	"flag.portfolio":        "持仓：show 查看本地快照，sync 从长桥账户同步（sync:<账户名> 指定账户），传入 CSV 文件路径导入，risk 检查收益相关性与集中度，完成后退出",
+	"flag.watchlist":        "-portfolio risk：以逗号分隔的标的列表，替代持仓参与计算",
+	"flag.window":           "-portfolio risk：计算相关性使用的日收益个数（20-250）",
+	"flag.baseline":         "experiment：基线配置文件",
+	"flag.candidate":        "experiment：候选配置文件",
+	"flag.cases":            "experiment：标的列表文件，每行一个标的，可在其后写交易日",
+	"flag.replay_speed":     "replay：相对原始运行的回放倍速（如 10）；0 表示不停顿直接输出全部记录",
+	"flag.replay_max_pause": "replay：按 -speed 换算后单条消息的最长停顿秒数",
+	"flag.journal_run":      "journal：交易所依据的分析运行 id（add）或按运行过滤（list）",
+	"flag.journal_side":     "journal add：long 或 short（默认按该次分析的建议：SELL 为 short，其余为 long）",
+	"flag.journal_qty":      "journal add：成交数量",
+	"flag.journal_price":    "journal：建仓价（add）或平仓价（close）",
+	"flag.journal_date":     "journal：建仓日（add）或平仓日（close），YYYY-MM-DD，默认今天",
+	"flag.journal_fees":     "journal：手续费，从已实现盈亏中扣除",
+	"flag.journal_currency": "journal add：计价币种（默认按市场后缀）",
+	"flag.journal_notes":    "journal：备注（平仓时追加）",
+	"flag.journal_open":     "journal list：只列出未平仓的交易",
+	"flag.alert_above":      "alerts add：最新价高于等于该值时触发",
+	"flag.alert_below":      "alerts add：最新价低于等于该值时触发",
+	"flag.alert_move":       "alerts add：相对昨收涨跌幅绝对值达到该百分比时触发",
+	"flag.alert_repeat":     "alerts add：触发后保留提醒，条件解除后可再次触发",
+	"flag.alert_all":        "alerts list：同时列出已触发的一次性提醒",
+	"flag.alert_interval":   "alerts watch：行情轮询间隔（秒，最小 10）",
+	"flag.watch_interval":   "watch：行情与新闻的轮询间隔，如 15m（最小 1m）",
+	"flag.watch_move":       "watch：价格相对上次分析涨跌达到该百分比时重新分析",
+	"flag.watch_quality":    "watch：出现来源可信度不低于该值（0-1）的新闻时重新分析；措辞强烈的新闻总会触发",
+	"flag.watch_full":       "watch：刷新时运行一次快速深度的完整分析，而不是增量分析",
+	"flag.market":           "sectors：排名哪个市场的板块 ETF（US）",
+	"flag.sectors_analyze":  "sectors：对排名前 N 的板块 ETF 运行 quick 深度分析（0：只输出排名）",
+	"flag.lang":             "命令行输出语言：en 或 zh-CN（默认取配置 locale，其次 LANG）",
+
+	"err.depth":           "无效的 -depth %q：应为 quick、standard 或 deep",
+	"err.risk":            "无效的 -risk %q：应为 conservative、balanced 或 aggressive",
+	"err.llm":             "无效的 -llm %q：应为 deepseek 或 mock",
+	"err.data":            "无效的 -data %q：应为 live 或 simulated",
+	"err.portfolio_mode":  "无效的 -portfolio %q：应为 show、sync、sync:<账户名>、risk 或 .csv 文件",
+	"err.alert_id":        "无效的提醒 id %q",
+	"err.journal_id":      "无效的交易日志 id %q",
+	"err.output_format":   "不支持的输出格式 %q（支持 text、json、yaml）",
+	"err.watch_config":    "-watch 需要配置文件（-config、$CORTEXGO_CONFIG 或 ./cortexgo.json）",
+	"err.watch_batch":     "-watch 只能与 -batch 或 -resume 一起使用",
+	"err.offline_missing": "离线模式：缺少本地数据：",
+
+	"result.error":          "错误：",
+	"result.data_bundle":    "工具数据：%s",
+	"result.run_dir":        "运行产物：%s",
+	"result.run_dir_failed": "写入运行目录失败：%v",
+	"result.bundle_failed":  "写入工具数据包失败：%v",
+
+	"config.env_header":      "环境变量\t字段\t类型",
+	"config.defaults":        "默认值与环境变量",
+	"config.ok":              "配置有效（%s）",
+	"config.problems":        "配置 %s：%d 个问题",
+	"config.problems_header": "字段\t来源\t规则\t问题",
+
+	"stream.tool_result": "-> %s 返回 %d 字节",
+	"stream.tool_call":   "-> 调用 %s %s",
+
+	"batch.start":           "批次 %s：%d/%d 个标的待分析，交易日 %s（可用 -resume %s 恢复）",
+	"batch.reload_failed":   "配置重新加载失败，沿用原配置：%v",
+	"batch.reloaded":        "配置已重新加载：%s",
+	"batch.restart":         "配置：%s 的修改需重启生效",
+	"batch.offline_missing": "离线模式：缺少本地数据：%s",
+	"batch.concurrency":     "并发 %d -> %d（%s）",
+	"batch.done":            "批次 %s：完成 %d，失败 %d，待处理 %d",
+	"batch.report_failed":   "写入批次报告失败：%v",
+	"batch.report":          "批次报告：%s",
+	"batch.shared":          "共享大盘数据：获取 %d 次，复用 %d 次",
+	"batch.correlation":     "跳过相关性检查：%v",
+	"batch.interrupted":     "已中断，可用 -resume %s 继续",
+	"batch.header":          "排名\t标的\t建议\t置信度\t状态",
+
+	"experiment.start":    "实验 %s：%d 个用例，基线 %s 对比候选 %s",
+	"experiment.case":     "[%d/%d] %s %s：%s -> %s",
+	"experiment.summary":  "可比用例中 %d/%d 个决策一致；费用 $%.4f -> $%.4f；平均耗时 %.1fs -> %.1fs",
+	"experiment.report":   "实验报告：%s",
+	"experiment.failed":   "写入实验报告失败：%v",
+	"experiment.header":   "标的\t日期\t基线\t候选\t费用\t耗时",
+	"experiment.no_cases": "需要 -f：每行一个标的（可附交易日）的文件",
+	"experiment.configs":  "-baseline 与 -candidate 均为必填",
+	"replay.bad_id":       "无效的运行 id %q：应为 agent.history.list 返回的数字会话 id",
+	"replay.empty":        "运行 %d 没有可回放的记录（仅记录从 App 或价格提醒启动的分析）",
+	"replay.start":        "回放运行 %d：%s %s，%s，共 %d 条消息",
+	"replay.done":         "运行 %d 回放结束（%s）",
+	"replay.interrupted":  "运行 %d 的回放已中断",
+
+	"doctor.header": "检查项\t状态\t详情",
+
+	"plan.title":          "%s 在 %s 的执行计划，深度 %s（每位分析师最多 %d 步工具调用）",
+	"plan.offline":        "（离线）",
+	"plan.steps_header":   "阶段\tAGENT\t模型\t调用\t输入/输出 TOKEN\t工具",
+	"plan.sources_header": "数据源\t模式\t详情",
+	"plan.estimate":       "估算：%d 次模型调用，输入 %d + 输出 %d token，约 $%.4f",
+	"plan.warning":        "警告：%s",
+
+	"quote.header": "标的\t最新价\t涨跌\t涨跌幅\t成交量\t52周最低\t52周最高\t时段\t盘前盘后\t",
+
+	"news.header":   "日期\t情绪\t来源\t标题",
+	"news.summary":  "共 %d 条（%s），平均情绪 %+.2f",
+	"news.exported": "已导出到 %s",
+
+	"indicators.date":  "日期",
+	"indicators.close": "收盘",
+
+	"ingest.any_symbol": "全部标的",
+	"ingest.done":       "已导入 #%d %q（%s，%s）：%d 页，%d 个切块",
+
+	"portfolio.summary":    "%s 快照，同步于 %s",
+	"portfolio.net_assets": "净资产：%s %.2f",
+	"portfolio.header":     "标的\t名称\t数量\t可用\t成本价\t币种\t",
+	"portfolio.cash":       "币种\t可用\t冻结\t",
+	"portfolio.empty":      "暂无股票持仓",
+	"portfolio.risk":       "%d 日收益相关性，截至 %s（平均 %.2f）",
+	"portfolio.missing":    "行情不足：%s",
+	"portfolio.flags":      "集中度提示：",
+	"portfolio.no_flags":   "无集中度风险提示",
+
+	"alerts.added":            "已添加提醒 #%d：%s",
+	"alerts.deleted":          "已删除提醒 #%d",
+	"alerts.none":             "暂无提醒",
+	"alerts.header":           "ID\t标的\t条件\t阈值\t重复\t状态\t最近触发\t",
+	"alerts.watching":         "正在监控 %d 个提醒，每 %s 检查一次行情；按 Ctrl+C 停止",
+	"alerts.triggered":        "提醒 %s 在 %.2f 触发：已启动分析会话 %s",
+	"alerts.trigger_failed":   "提醒 %s 在 %.2f 触发，但分析未能启动：%s",
+	"journal.added":           "已记录交易 #%d：%s",
+	"journal.closed":          "交易 #%d（%s）已平仓：已实现盈亏 %+.2f %s（%+.2f%%）",
+	"journal.deleted":         "已删除交易 #%d",
+	"journal.none":            "暂无交易日志",
+	"journal.header":          "ID\t标的\t方向\t数量\t建仓\t平仓\t关联分析\t已实现盈亏",
+	"journal.stats":           "共 %d 笔交易：持仓中 %d 笔，已平仓 %d 笔；胜率 %.0f%%，平均收益 %+.2f%%",
+	"journal.followed":        "其中 %d 笔已平仓交易与关联分析的建议方向一致，平均收益 %+.2f%%",
+	"journal.pnl":             "已实现盈亏 %s：%+.2f",
+	"index.summary":           "%s（%s）：%d 只成分股，来源 %s，获取于 %s",
+	"index.header":            "标的\t名称\t行业",
+	"index.breadth":           "%s 广度（%s）：%d / %d 只成分股有行情",
+	"index.advance":           "上涨 / 下跌：%d / %d（平盘 %d）",
+	"index.above":             "站上 50 日均线：%.0f%%，站上 200 日均线：%.0f%%",
+	"index.highs":             "52 周新高 / 新低：%d / %d",
+	"index.missing":           "行情不足：%s",
+	"index.sector_header":     "行业\t成分股\t站上 50 日线",
+	"batch.index":             "%s：共 %d 只成分股待分析",
+	"err.index_batch":         "-index 不能与 -batch 或 -resume 同时使用",
+	"watch.started":           "正在监控 %s，每 %s 轮询一次；首次轮询运行完整分析，按 Ctrl+C 停止",
+	"watch.analyzed":          "[%s] %s %.2f：%s 分析 -> %s",
+	"watch.failed":            "[%s] %s %.2f：%s 分析失败：%s",
+	"watch.quiet":             "[%s] %s %.2f（相对上次分析 %+.2f%%），新增 %d 条新闻，无重大变化",
+	"watch.reason":            "  - %s",
+	"watch.news_err":          "  新闻暂不可用：%s",
+	"sectors.summary":         "%s 板块轮动（截至 %s，基准 %s）：领涨板块%s",
+	"sectors.header":          "排名\t标的\t板块\t阶段\t得分",
+	"sectors.header_rel":      "相对基准 %d日",
+	"sectors.benchmark":       "基准",
+	"sectors.legend":          "得分：各周期相对基准超额收益排名的平均值，越小越强；* 为防御板块",
+	"sectors.analyze":         "对 %d 个领涨板块 ETF 运行 quick 深度分析：%s",
+	"sectors.tilt.cyclical":   "偏周期",
+	"sectors.tilt.defensive":  "偏防御",
+	"sectors.tilt.mixed":      "风格混合",
+	"sectors.phase.leading":   "领涨",
+	"sectors.phase.weakening": "转弱",
+	"sectors.phase.lagging":   "落后",
+	"sectors.phase.improving": "转强",
+}
@@ -0,0 +1,79 @@
+// Package i18n holds the message catalogs for command line output and picks
+// the catalog from the configured locale or the environment.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Supported locales.
+const (
+	English = "en"
+	Chinese = "zh-CN"
+)
+
+var catalogs = map[string]map[string]string{
+	English: catalogEn,
+	Chinese: catalogZhCN,
+}
+
+var current atomic.Value // string
+
+// Locales lists the supported locales.
+func Locales() []string {
+	return []string{English, Chinese}
+}
+
+// SetLocale switches the active catalog; unknown tags fall back to English.
+func SetLocale(tag string) {
+	current.Store(Normalize(tag))
+}
+
+// Locale returns the active locale.
+func Locale() string {
+	if v, ok := current.Load().(string); ok {
+		return v
+	}
+	return English
+}
+
+// Normalize maps tags such as "zh", "zh_CN.UTF-8" or "zh-Hans" to a
+// supported locale.
+func Normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, ".@"); i >= 0 {
+		tag = tag[:i]
+	}
+	if tag == "zh" || strings.HasPrefix(tag, "zh-") || strings.HasPrefix(tag, "zh_") {
+		return Chinese
+	}
+	return English
+}
+
+// Detect reads the locale from LC_ALL, LC_MESSAGES and LANG, in that order.
+func Detect() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" && v != "C" && v != "POSIX" {
+			return Normalize(v)
+		}
+	}
+	return English
+}
+
+// T returns the message for key in the active locale, formatted with args.
+// Missing translations fall back to English, then to the key itself.
+func T(key string, args ...any) string {
+	msg, ok := catalogs[Locale()][key]
+	if !ok {
+		if msg, ok = catalogEn[key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
@@ -0,0 +1,43 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+var verb = regexp.MustCompile(`%[-+# 0]*[0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for key, en := range catalogEn {
+		zh, ok := catalogZhCN[key]
+		if !ok {
+			t.Errorf("zh-CN missing %q", key)
+			continue
+		}
+		if !slices.Equal(verb.FindAllString(en, -1), verb.FindAllString(zh, -1)) {
+			t.Errorf("%q: format verbs differ: %q vs %q", key, en, zh)
+		}
+	}
+	for key := range catalogZhCN {
+		if _, ok := catalogEn[key]; !ok {
+			t.Errorf("zh-CN has extra key %q", key)
+		}
+	}
+}
+
+func TestNormalizeAndFallback(t *testing.T) {
+	for tag, want := range map[string]string{"zh_CN.UTF-8": Chinese, "zh-Hans": Chinese, "ZH": Chinese, "en_US.UTF-8": English, "fr": English, "": English} {
+		if got := Normalize(tag); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", tag, got, want)
+		}
+	}
+	defer SetLocale(Locale())
+	SetLocale("zh-CN")
+	if got := T("plan.warning", "x"); got != "警告：x" {
+		t.Errorf("T = %q", got)
+	}
+	if got := T("no.such.key"); got != "no.such.key" {
+		t.Errorf("missing key = %q", got)
+	}
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildMIMEIncludesBodyAndAttachment(t *testing.T) {
+	msg := &Email{
+		To:       []string{"a@example.com", "b@example.com"},
+		Subject:  "AAPL.US 2024-05-10: BUY",
+		HTMLBody: "<p>hello</p>",
+		Attachments: []Attachment{{
+			Name:        "report.html",
+			ContentType: "text/html; charset=utf-8",
+			Data:        []byte(strings.Repeat("x", 200)),
+		}},
+	}
+	raw, err := buildMIME("bot@example.com", msg)
+	if err != nil {
+		t.Fatalf("buildMIME: %v", err)
+	}
+	out := string(raw)
+	for _, want := range []string{
+		"From: bot@example.com\r\n",
+		"To: a@example.com, b@example.com\r\n",
+		"Content-Type: multipart/mixed; boundary=",
+		`Content-Disposition: attachment; filename=report.html`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in message:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > 998 {
+			t.Fatalf("line exceeds SMTP limit: %d", len(line))
+		}
+	}
+}
+
+func TestSendEmailRequiresConfig(t *testing.T) {
+	if err := SendEmail(SMTPConfig{}, &Email{To: []string{"a@example.com"}}); err == nil {
+		t.Fatal("expected error for missing smtp config")
+	}
+	if err := SendEmail(SMTPConfig{Host: "localhost", From: "x@example.com"}, &Email{}); err == nil {
+		t.Fatal("expected error for missing recipients")
+	}
+}
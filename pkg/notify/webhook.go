@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// SignatureHeader carries "sha256=<hex>" of HMAC(secret, timestamp + "." + body).
+	SignatureHeader = "X-CortexGo-Signature"
+	// TimestampHeader carries the unix seconds used in the signature.
+	TimestampHeader = "X-CortexGo-Timestamp"
+	// EventHeader names the event that triggered the webhook.
+	EventHeader = "X-CortexGo-Event"
+)
+
+// Webhook posts JSON payloads to a set of URLs, optionally signed with a shared secret.
+type Webhook struct {
+	URLs   []string
+	Secret string
+	client *resty.Client
+}
+
+// NewWebhook creates a webhook sender with a short timeout and a couple of retries.
+func NewWebhook(urls []string, secret string) *Webhook {
+	client := resty.New()
+	client.SetTimeout(15 * time.Second)
+	client.SetRetryCount(2)
+	client.SetRetryWaitTime(time.Second)
+	client.SetHeader("User-Agent", "CortexGo-Webhook/1.0")
+	return &Webhook{URLs: urls, Secret: secret, client: client}
+}
+
+// Post sends body to every URL and returns the joined errors of the failed deliveries.
+func (w *Webhook) Post(ctx context.Context, event string, body []byte) error {
+	if len(w.URLs) == 0 {
+		return nil
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	var errs []error
+	for _, url := range w.URLs {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		req := w.client.R().
+			SetContext(ctx).
+			SetHeader("Content-Type", "application/json").
+			SetHeader(EventHeader, event).
+			SetHeader(TimestampHeader, ts).
+			SetBody(body)
+		if w.Secret != "" {
+			req.SetHeader(SignatureHeader, SignPayload(w.Secret, ts, body))
+		}
+		resp, err := req.Post(url)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("post %s: %w", url, err))
+			continue
+		}
+		if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+			errs = append(errs, fmt.Errorf("post %s: HTTP %d", url, resp.StatusCode()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SignPayload returns the signature header value for body sent at timestamp ts.
+func SignPayload(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a signature header produced by SignPayload in constant time.
+func VerifySignature(secret, ts string, body []byte, signature string) bool {
+	expected := SignPayload(secret, ts, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
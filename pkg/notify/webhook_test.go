@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookPostSignsBody(t *testing.T) {
+	const secret = "s3cret"
+	body := []byte(`{"symbol":"AAPL.US","recommendation":"BUY"}`)
+
+	var gotSig, gotTS, gotEvent string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(SignatureHeader)
+		gotTS = r.Header.Get(TimestampHeader)
+		gotEvent = r.Header.Get(EventHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhook([]string{srv.URL}, secret).Post(context.Background(), "analysis.completed", body); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if gotEvent != "analysis.completed" {
+		t.Fatalf("event header = %q", gotEvent)
+	}
+	if string(gotBody) != string(body) {
+		t.Fatalf("body = %s", gotBody)
+	}
+	if !VerifySignature(secret, gotTS, gotBody, gotSig) {
+		t.Fatalf("signature %q does not verify", gotSig)
+	}
+	if VerifySignature("other", gotTS, gotBody, gotSig) {
+		t.Fatal("signature verified with wrong secret")
+	}
+}
+
+func TestWebhookPostReportsHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhook([]string{srv.URL}, "").Post(context.Background(), "analysis.completed", []byte(`{}`)); err == nil {
+		t.Fatal("expected error for 400 response")
+	}
+}
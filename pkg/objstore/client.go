@@ -0,0 +1,227 @@
+// Package objstore is a minimal S3-compatible object storage client. It works
+// with AWS S3, MinIO/R2 and Google Cloud Storage through its XML interoperability
+// API (endpoint https://storage.googleapis.com with HMAC keys).
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config describes the bucket to talk to.
+type Config struct {
+	Endpoint  string // e.g. https://s3.us-east-1.amazonaws.com or https://storage.googleapis.com
+	Region    string // defaults to us-east-1 (GCS accepts "auto")
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses objects as <endpoint>/<bucket>/<key> instead of
+	// <bucket>.<endpoint>/<key>; required by MinIO and most self-hosted stores.
+	PathStyle bool
+}
+
+// ErrNotFound is returned by Get when the object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// Client performs signed requests against one bucket.
+type Client struct {
+	cfg  Config
+	http *http.Client
+	now  func() time.Time
+}
+
+// New validates cfg and returns a client.
+func New(cfg Config) (*Client, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("objstore endpoint and bucket are required")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, errors.New("objstore access key and secret key are required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if !strings.Contains(cfg.Endpoint, "://") {
+		cfg.Endpoint = "https://" + cfg.Endpoint
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	return &Client{cfg: cfg, http: &http.Client{Timeout: 60 * time.Second}, now: time.Now}, nil
+}
+
+func (c *Client) objectURL(key string, query url.Values) (*url.URL, error) {
+	base, err := url.Parse(c.cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	escapedKey := ""
+	if key != "" {
+		parts := strings.Split(key, "/")
+		for i, p := range parts {
+			parts[i] = uriEncode(p)
+		}
+		escapedKey = "/" + strings.Join(parts, "/")
+	}
+	if c.cfg.PathStyle {
+		base.Path = "/" + c.cfg.Bucket + pathOnly(escapedKey)
+		base.RawPath = "/" + c.cfg.Bucket + escapedKey
+	} else {
+		base.Host = c.cfg.Bucket + "." + base.Host
+		base.Path = pathOnly(escapedKey)
+		base.RawPath = escapedKey
+	}
+	if base.RawPath == "" {
+		base.Path, base.RawPath = "/", "/"
+	}
+	base.RawQuery = query.Encode()
+	return base, nil
+}
+
+func pathOnly(escaped string) string {
+	p, err := url.PathUnescape(escaped)
+	if err != nil {
+		return escaped
+	}
+	return p
+}
+
+func (c *Client) do(ctx context.Context, method, key string, query url.Values, body []byte, contentType string) (*http.Response, error) {
+	u, err := c.objectURL(key, query)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	payloadHash := emptyPayloadSHA
+	if len(body) > 0 {
+		payloadHash = hashHex(body)
+	}
+	signV4(req, c.cfg.AccessKey, c.cfg.SecretKey, c.cfg.Region, payloadHash, c.now())
+	return c.http.Do(req)
+}
+
+// Put uploads data to key.
+func (c *Client) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	resp, err := c.do(ctx, http.MethodPut, key, nil, data, contentType)
+	if err != nil {
+		return fmt.Errorf("put %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("put %s: %s", key, responseError(resp))
+	}
+	return nil
+}
+
+// Get downloads key.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	data, _, err := c.GetObject(ctx, key)
+	return data, err
+}
+
+// GetObject downloads key along with its last modification time.
+func (c *Client) GetObject(ctx context.Context, key string) ([]byte, time.Time, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil, "")
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("get %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, time.Time{}, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, time.Time{}, fmt.Errorf("get %s: %s", key, responseError(resp))
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("get %s: %w", key, err)
+	}
+	modified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return data, modified, nil
+}
+
+// Delete removes key; deleting a missing object succeeds.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("delete %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("delete %s: %s", key, responseError(resp))
+	}
+	return nil
+}
+
+// Object is an entry returned by List.
+type Object struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+}
+
+type listResult struct {
+	Contents              []Object `xml:"Contents"`
+	IsTruncated           bool     `xml:"IsTruncated"`
+	NextContinuationToken string   `xml:"NextContinuationToken"`
+	NextMarker            string   `xml:"NextMarker"`
+}
+
+// List returns all objects whose key starts with prefix, following pagination.
+func (c *Client) List(ctx context.Context, prefix string) ([]Object, error) {
+	var (
+		out   []Object
+		token string
+	)
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		resp, err := c.do(ctx, http.MethodGet, "", query, nil, "")
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %w", prefix, err)
+		}
+		if resp.StatusCode/100 != 2 {
+			err := fmt.Errorf("list %s: %s", prefix, responseError(resp))
+			resp.Body.Close()
+			return nil, err
+		}
+		var result listResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decode list: %w", err)
+		}
+		out = append(out, result.Contents...)
+		token = result.NextContinuationToken
+		if !result.IsTruncated || token == "" {
+			return out, nil
+		}
+	}
+}
+
+func responseError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	var e struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Sprintf("HTTP %d %s: %s", resp.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("HTTP %d", resp.StatusCode)
+}
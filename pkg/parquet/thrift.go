@@ -0,0 +1,110 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol type ids.
+const (
+	ctI32    = 5
+	ctI64    = 6
+	ctBinary = 8
+	ctList   = 9
+	ctStruct = 12
+)
+
+// compactWriter writes the subset of the Thrift compact protocol needed for
+// parquet metadata: structs with i32/i64/binary/list/struct fields.
+type compactWriter struct {
+	buf       bytes.Buffer
+	lastField []int16
+}
+
+func (w *compactWriter) beginStruct() {
+	w.lastField = append(w.lastField, 0)
+}
+
+func (w *compactWriter) endStruct() {
+	w.buf.WriteByte(0) // STOP
+	w.lastField = w.lastField[:len(w.lastField)-1]
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	last := w.lastField[len(w.lastField)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.varint(uint64(zigzag(int64(id))))
+	}
+	w.lastField[len(w.lastField)-1] = id
+}
+
+func (w *compactWriter) varint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	w.buf.Write(tmp[:n])
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (w *compactWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, ctI32)
+	w.varint(zigzag(int64(v)))
+}
+
+func (w *compactWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, ctI64)
+	w.varint(zigzag(v))
+}
+
+func (w *compactWriter) stringField(id int16, s string) {
+	w.fieldHeader(id, ctBinary)
+	w.varint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *compactWriter) structField(id int16, body func()) {
+	w.fieldHeader(id, ctStruct)
+	w.beginStruct()
+	body()
+	w.endStruct()
+}
+
+func (w *compactWriter) listHeader(id int16, elemType byte, size int) {
+	w.fieldHeader(id, ctList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		w.buf.WriteByte(0xF0 | elemType)
+		w.varint(uint64(size))
+	}
+}
+
+func (w *compactWriter) i32List(id int16, values []int32) {
+	w.listHeader(id, ctI32, len(values))
+	for _, v := range values {
+		w.varint(zigzag(int64(v)))
+	}
+}
+
+func (w *compactWriter) stringList(id int16, values []string) {
+	w.listHeader(id, ctBinary, len(values))
+	for _, v := range values {
+		w.varint(uint64(len(v)))
+		w.buf.WriteString(v)
+	}
+}
+
+// structList writes a list of structs, calling body for each element.
+func (w *compactWriter) structList(id int16, size int, body func(i int)) {
+	w.listHeader(id, ctStruct, size)
+	for i := 0; i < size; i++ {
+		w.beginStruct()
+		body(i)
+		w.endStruct()
+	}
+}
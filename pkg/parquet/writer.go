@@ -0,0 +1,217 @@
+// Package parquet writes small flat tables as Apache Parquet files without
+// external dependencies. Every column is REQUIRED, PLAIN encoded and
+// uncompressed, and the whole table is a single row group — enough for
+// exporting analysis history to pandas or DuckDB.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Type is the physical type of a column.
+type Type int32
+
+const (
+	Boolean Type = 0
+	Int64   Type = 2
+	Double  Type = 5
+	String  Type = 6 // BYTE_ARRAY annotated as UTF8
+)
+
+// Column describes one field of the table schema.
+type Column struct {
+	Name string
+	Type Type
+}
+
+const (
+	encodingPlain = 0
+	encodingRLE   = 3
+	pageTypeData  = 0
+	convertedUTF8 = 0
+	repRequired   = 0
+	codecNone     = 0
+)
+
+var magic = []byte("PAR1")
+
+// Write encodes rows (one []any per row, matching columns) as a parquet file.
+// Accepted Go values: bool; int, int32, int64; float32, float64; string.
+func Write(w io.Writer, columns []Column, rows [][]any) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("parquet: no columns")
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return fmt.Errorf("parquet: row %d has %d values, want %d", i, len(row), len(columns))
+		}
+	}
+
+	var file bytes.Buffer
+	file.Write(magic)
+
+	type chunkMeta struct {
+		offset int64
+		size   int64
+	}
+	chunks := make([]chunkMeta, len(columns))
+	for ci, col := range columns {
+		values, err := encodeColumn(col, rows, ci)
+		if err != nil {
+			return err
+		}
+		header := pageHeader(len(rows), len(values))
+		chunks[ci] = chunkMeta{offset: int64(file.Len()), size: int64(len(header) + len(values))}
+		file.Write(header)
+		file.Write(values)
+	}
+
+	var total int64
+	for _, c := range chunks {
+		total += c.size
+	}
+
+	meta := &compactWriter{}
+	meta.beginStruct()
+	meta.i32Field(1, 1) // version
+	meta.structList(2, len(columns)+1, func(i int) {
+		if i == 0 {
+			meta.stringField(4, "schema")
+			meta.i32Field(5, int32(len(columns)))
+			return
+		}
+		col := columns[i-1]
+		meta.i32Field(1, int32(col.Type))
+		meta.i32Field(3, repRequired)
+		meta.stringField(4, col.Name)
+		if col.Type == String {
+			meta.i32Field(6, convertedUTF8)
+		}
+	})
+	meta.i64Field(3, int64(len(rows)))
+	meta.structList(4, 1, func(int) {
+		meta.structList(1, len(columns), func(i int) {
+			meta.i64Field(2, chunks[i].offset)
+			meta.structField(3, func() {
+				meta.i32Field(1, int32(columns[i].Type))
+				meta.i32List(2, []int32{encodingPlain, encodingRLE})
+				meta.stringList(3, []string{columns[i].Name})
+				meta.i32Field(4, codecNone)
+				meta.i64Field(5, int64(len(rows)))
+				meta.i64Field(6, chunks[i].size)
+				meta.i64Field(7, chunks[i].size)
+				meta.i64Field(9, chunks[i].offset)
+			})
+		})
+		meta.i64Field(2, total)
+		meta.i64Field(3, int64(len(rows)))
+	})
+	meta.stringField(6, "CortexGo")
+	meta.endStruct()
+
+	file.Write(meta.buf.Bytes())
+	var footerLen [4]byte
+	binary.LittleEndian.PutUint32(footerLen[:], uint32(meta.buf.Len()))
+	file.Write(footerLen[:])
+	file.Write(magic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+func pageHeader(numValues, size int) []byte {
+	h := &compactWriter{}
+	h.beginStruct()
+	h.i32Field(1, pageTypeData)
+	h.i32Field(2, int32(size))
+	h.i32Field(3, int32(size))
+	h.structField(5, func() {
+		h.i32Field(1, int32(numValues))
+		h.i32Field(2, encodingPlain)
+		h.i32Field(3, encodingRLE)
+		h.i32Field(4, encodingRLE)
+	})
+	h.endStruct()
+	return h.buf.Bytes()
+}
+
+func encodeColumn(col Column, rows [][]any, ci int) ([]byte, error) {
+	var buf bytes.Buffer
+	var bits byte
+	var tmp [8]byte
+	for ri, row := range rows {
+		v := row[ci]
+		switch col.Type {
+		case Boolean:
+			b, ok := v.(bool)
+			if !ok {
+				return nil, typeErr(col, ri, v)
+			}
+			if b {
+				bits |= 1 << (ri % 8)
+			}
+			if ri%8 == 7 {
+				buf.WriteByte(bits)
+				bits = 0
+			}
+		case Int64:
+			n, ok := toInt64(v)
+			if !ok {
+				return nil, typeErr(col, ri, v)
+			}
+			binary.LittleEndian.PutUint64(tmp[:], uint64(n))
+			buf.Write(tmp[:8])
+		case Double:
+			f, ok := toFloat64(v)
+			if !ok {
+				return nil, typeErr(col, ri, v)
+			}
+			binary.LittleEndian.PutUint64(tmp[:], math.Float64bits(f))
+			buf.Write(tmp[:8])
+		case String:
+			s, ok := v.(string)
+			if !ok {
+				return nil, typeErr(col, ri, v)
+			}
+			binary.LittleEndian.PutUint32(tmp[:4], uint32(len(s)))
+			buf.Write(tmp[:4])
+			buf.WriteString(s)
+		default:
+			return nil, fmt.Errorf("parquet: unsupported type %d for column %s", col.Type, col.Name)
+		}
+	}
+	if col.Type == Boolean && len(rows)%8 != 0 {
+		buf.WriteByte(bits)
+	}
+	return buf.Bytes(), nil
+}
+
+func typeErr(col Column, row int, v any) error {
+	return fmt.Errorf("parquet: column %s row %d: unexpected value %T", col.Name, row, v)
+}
+
+func toInt64(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	}
+	return 0, false
+}
+
+func toFloat64(v any) (float64, bool) {
+	switch f := v.(type) {
+	case float64:
+		return f, true
+	case float32:
+		return float64(f), true
+	}
+	return 0, false
+}
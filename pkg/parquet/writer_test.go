@@ -0,0 +1,138 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// compactReader decodes the compact protocol back into nested maps so the
+// test can inspect the footer the way a parquet reader would.
+type compactReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *compactReader) byte() byte {
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *compactReader) varint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.varint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(typ byte) any {
+	switch typ {
+	case ctI32, ctI64:
+		return r.zigzag()
+	case ctBinary:
+		n := int(r.varint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case ctList:
+		h := r.byte()
+		size, elem := int(h>>4), h&0x0F
+		if size == 15 {
+			size = int(r.varint())
+		}
+		out := make([]any, size)
+		for i := range out {
+			out[i] = r.value(elem)
+		}
+		return out
+	case ctStruct:
+		return r.structValue()
+	}
+	panic(fmt.Sprintf("unexpected type %d", typ))
+}
+
+func (r *compactReader) structValue() map[int16]any {
+	out := map[int16]any{}
+	var last int16
+	for {
+		h := r.byte()
+		if h == 0 {
+			return out
+		}
+		typ := h & 0x0F
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		out[id] = r.value(typ)
+		last = id
+	}
+}
+
+func TestWriteRoundTripsMetadataAndValues(t *testing.T) {
+	cols := []Column{{"symbol", String}, {"confidence", Double}, {"horizon", Int64}, {"correct", Boolean}}
+	rows := [][]any{
+		{"AAPL.US", 0.72, 5, true},
+		{"TSLA.US", 0.4, int64(10), false},
+		{"700.HK", float32(0.5), int32(5), true},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, cols, rows); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, magic) || !bytes.HasSuffix(data, magic) {
+		t.Fatal("missing PAR1 magic")
+	}
+	footerLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &compactReader{data: data[len(data)-8-footerLen : len(data)-8]}
+	meta := footer.structValue()
+	if footer.pos != footerLen {
+		t.Fatalf("footer decoded %d of %d bytes", footer.pos, footerLen)
+	}
+
+	if meta[3].(int64) != 3 {
+		t.Fatalf("num_rows = %v", meta[3])
+	}
+	schema := meta[2].([]any)
+	if len(schema) != 5 || schema[1].(map[int16]any)[4] != "symbol" || schema[0].(map[int16]any)[5].(int64) != 4 {
+		t.Fatalf("unexpected schema: %v", schema)
+	}
+
+	chunks := meta[4].([]any)[0].(map[int16]any)[1].([]any)
+	conf := chunks[1].(map[int16]any)[3].(map[int16]any)
+	offset := conf[9].(int64)
+	page := &compactReader{data: data[offset:]}
+	header := page.structValue()
+	if header[5].(map[int16]any)[1].(int64) != 3 {
+		t.Fatalf("page num_values = %v", header)
+	}
+	values := data[int(offset)+page.pos:]
+	if got := math.Float64frombits(binary.LittleEndian.Uint64(values[8:])); got != 0.4 {
+		t.Fatalf("second confidence = %v", got)
+	}
+
+	correct := chunks[3].(map[int16]any)[3].(map[int16]any)
+	page = &compactReader{data: data[correct[9].(int64):]}
+	page.structValue()
+	if bits := data[int(correct[9].(int64))+page.pos]; bits != 0b101 {
+		t.Fatalf("boolean bits = %08b", bits)
+	}
+}
+
+func TestWriteRejectsBadRows(t *testing.T) {
+	cols := []Column{{"n", Int64}}
+	if err := Write(&bytes.Buffer{}, cols, [][]any{{"x"}}); err == nil {
+		t.Fatal("expected type error")
+	}
+	if err := Write(&bytes.Buffer{}, cols, [][]any{{1, 2}}); err == nil {
+		t.Fatal("expected arity error")
+	}
+}
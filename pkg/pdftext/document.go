@@ -0,0 +1,381 @@
+// Package pdftext extracts plain text from PDF files without external
+// dependencies. It recovers objects by scanning the file rather than trusting
+// the xref table, expands object streams, follows the page tree and decodes
+// text through ToUnicode CMaps — enough for text-based annual reports, broker
+// notes and slide decks. Scanned (image-only) and encrypted PDFs yield no text.
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	// ErrNotPDF is returned when the data has no %PDF- header.
+	ErrNotPDF = errors.New("pdftext: not a PDF file")
+	// ErrEncrypted is returned for password protected or DRM encrypted files.
+	ErrEncrypted = errors.New("pdftext: encrypted PDFs are not supported")
+	// ErrMalformed is returned when the file is too damaged to read.
+	ErrMalformed = errors.New("pdftext: malformed PDF")
+)
+
+// maxStreamSize caps what one stream may inflate to, so a small compressed
+// file cannot expand without bound.
+const maxStreamSize = 64 << 20
+
+// Page is the text of one page, numbered from 1.
+type Page struct {
+	Number int
+	Text   string
+}
+
+type object struct {
+	value  any
+	stream []byte // raw, still encoded; nil when the object has no stream
+}
+
+type document struct {
+	objects map[int]*object
+	trailer dict
+	fonts   map[any]*font
+}
+
+var (
+	objHeader     = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+	trailerHeader = regexp.MustCompile(`trailer\s*<<`)
+)
+
+// Extract returns the text of every page in page order. Damage the parser
+// does not anticipate is reported as ErrMalformed rather than a panic.
+func Extract(data []byte) (pages []Page, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			pages, err = nil, fmt.Errorf("%w: %v", ErrMalformed, r)
+		}
+	}()
+	head := data
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	if !bytes.Contains(head, []byte("%PDF-")) {
+		return nil, ErrNotPDF
+	}
+	d := parse(data)
+	if d.trailer["Encrypt"] != nil {
+		return nil, ErrEncrypted
+	}
+
+	nodes := d.pages()
+	out := make([]Page, 0, len(nodes))
+	for i, p := range nodes {
+		out = append(out, Page{Number: i + 1, Text: d.pageText(p)})
+	}
+	return out, nil
+}
+
+func parse(data []byte) *document {
+	d := &document{objects: map[int]*object{}, trailer: dict{}, fonts: map[any]*font{}}
+	for pos := 0; pos < len(data); {
+		loc := objHeader.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+		num, _ := strconv.Atoi(string(data[pos+loc[2] : pos+loc[3]]))
+		l := &lexer{data: data, pos: pos + loc[1]}
+		value, _ := l.object()
+		obj := &object{value: value}
+		l.skipSpace()
+		if bytes.HasPrefix(data[l.pos:], []byte("stream")) {
+			obj.stream, l.pos = readStream(data, l.pos+len("stream"), value)
+		}
+		// incremental updates append newer versions of the same object
+		d.objects[num] = obj
+		if l.pos <= pos+loc[0] {
+			l.pos = pos + loc[1]
+		}
+		pos = l.pos
+	}
+
+	// classic trailers, and cross-reference streams in PDF 1.5+
+	for _, loc := range trailerHeader.FindAllIndex(data, -1) {
+		l := &lexer{data: data, pos: loc[1] - 2}
+		if t, ok := l.object(); ok {
+			mergeTrailer(d.trailer, t)
+		}
+	}
+	nums := d.objectNumbers()
+	for _, num := range nums {
+		if sd, ok := d.objects[num].value.(dict); ok && sd["Type"] == name("XRef") {
+			mergeTrailer(d.trailer, sd)
+		}
+	}
+	for _, num := range nums {
+		d.expandObjectStream(d.objects[num])
+	}
+	return d
+}
+
+func mergeTrailer(trailer dict, v any) {
+	t, ok := v.(dict)
+	if !ok {
+		return
+	}
+	for _, k := range []name{"Root", "Encrypt", "Info"} {
+		if t[k] != nil {
+			trailer[k] = t[k]
+		}
+	}
+}
+
+// readStream returns the raw stream bytes starting after the "stream"
+// keyword and the position after "endstream". Direct /Length values are
+// trusted when they land on endstream; otherwise the data is scanned.
+func readStream(data []byte, start int, value any) ([]byte, int) {
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+	if d, ok := value.(dict); ok {
+		if n, ok := d["Length"].(float64); ok && n >= 0 && start+int(n) <= len(data) {
+			end := start + int(n)
+			rest := bytes.TrimLeft(data[end:], "\r\n \t")
+			if bytes.HasPrefix(rest, []byte("endstream")) {
+				return data[start:end], len(data) - len(rest) + len("endstream")
+			}
+		}
+	}
+	i := bytes.Index(data[start:], []byte("endstream"))
+	if i < 0 {
+		return data[start:], len(data)
+	}
+	end := start + i
+	raw := bytes.TrimRight(data[start:end], "\r\n")
+	return raw, end + len("endstream")
+}
+
+func (d *document) objectNumbers() []int {
+	nums := make([]int, 0, len(d.objects))
+	for n := range d.objects {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// expandObjectStream adds the objects packed in an /ObjStm stream. Objects
+// already found at the top level are kept.
+func (d *document) expandObjectStream(obj *object) {
+	sd, ok := obj.value.(dict)
+	if !ok || sd["Type"] != name("ObjStm") {
+		return
+	}
+	data, err := d.decode(obj)
+	if err != nil {
+		return
+	}
+	n, _ := d.resolve(sd["N"]).(float64)
+	first, _ := d.resolve(sd["First"]).(float64)
+	if first < 0 || int(first) > len(data) {
+		return
+	}
+	header := &lexer{data: data[:int(first)]}
+	for i := 0; i < int(n); i++ {
+		numTok, ok1 := header.token()
+		offTok, ok2 := header.token()
+		num, isNum := numTok.(float64)
+		off, isOff := offTok.(float64)
+		if !ok1 || !ok2 || !isNum || !isOff {
+			return
+		}
+		if _, exists := d.objects[int(num)]; exists {
+			continue
+		}
+		pos := int(first) + int(off)
+		if off < 0 || pos >= len(data) {
+			continue
+		}
+		l := &lexer{data: data, pos: pos}
+		if v, ok := l.object(); ok {
+			d.objects[int(num)] = &object{value: v}
+		}
+	}
+}
+
+// resolve follows indirect references.
+func (d *document) resolve(v any) any {
+	for i := 0; i < 32; i++ {
+		r, ok := v.(ref)
+		if !ok {
+			return v
+		}
+		obj := d.objects[r.num]
+		if obj == nil {
+			return nil
+		}
+		v = obj.value
+	}
+	return nil
+}
+
+func (d *document) dict(v any) dict {
+	out, _ := d.resolve(v).(dict)
+	return out
+}
+
+// streamOf returns the stream object v refers to, if any.
+func (d *document) streamOf(v any) *object {
+	r, ok := v.(ref)
+	if !ok {
+		return nil
+	}
+	obj := d.objects[r.num]
+	if obj == nil || obj.stream == nil {
+		return nil
+	}
+	return obj
+}
+
+// decode applies the stream's filters. Image filters are not supported
+// since they never carry text.
+func (d *document) decode(obj *object) ([]byte, error) {
+	sd, _ := obj.value.(dict)
+	var filters []any
+	switch f := d.resolve(sd["Filter"]).(type) {
+	case name:
+		filters = []any{f}
+	case array:
+		filters = f
+	}
+	data := obj.stream
+	for _, f := range filters {
+		switch d.resolve(f) {
+		case name("FlateDecode"), name("Fl"):
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			// truncated streams are common; keep whatever inflated
+			out, err := io.ReadAll(io.LimitReader(r, maxStreamSize+1))
+			if err != nil && len(out) == 0 {
+				return nil, err
+			}
+			if len(out) > maxStreamSize {
+				return nil, fmt.Errorf("pdftext: stream inflates beyond %d bytes", maxStreamSize)
+			}
+			data = out
+		case name("ASCIIHexDecode"), name("AHx"):
+			clean := bytes.Map(func(r rune) rune {
+				if r == '>' || isSpace(byte(r)) {
+					return -1
+				}
+				return r
+			}, data)
+			if len(clean)%2 == 1 {
+				clean = append(clean, '0')
+			}
+			out := make([]byte, len(clean)/2)
+			if _, err := hex.Decode(out, clean); err != nil {
+				return nil, err
+			}
+			data = out
+		case name("ASCII85Decode"), name("A85"):
+			clean := bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+			if i := bytes.Index(clean, []byte("~>")); i >= 0 {
+				clean = clean[:i]
+			}
+			out := make([]byte, 4*len(clean))
+			n, _, err := ascii85.Decode(out, clean, true)
+			if err != nil {
+				return nil, err
+			}
+			data = out[:n]
+		default:
+			return nil, fmt.Errorf("pdftext: unsupported filter %v", f)
+		}
+	}
+	return data, nil
+}
+
+// pageNode is a leaf of the page tree with its inherited resources.
+type pageNode struct {
+	page      dict
+	resources dict
+}
+
+func (d *document) pages() []pageNode {
+	var (
+		out  []pageNode
+		seen = map[int]bool{}
+		walk func(v any, resources dict)
+	)
+	walk = func(v any, resources dict) {
+		if r, ok := v.(ref); ok {
+			if seen[r.num] {
+				return
+			}
+			seen[r.num] = true
+		}
+		node := d.dict(v)
+		if node == nil {
+			return
+		}
+		if res := d.dict(node["Resources"]); res != nil {
+			resources = res
+		}
+		if kids, ok := d.resolve(node["Kids"]).(array); ok && node["Type"] != name("Page") {
+			for _, kid := range kids {
+				walk(kid, resources)
+			}
+			return
+		}
+		out = append(out, pageNode{page: node, resources: resources})
+	}
+	if root := d.dict(d.trailer["Root"]); root != nil {
+		walk(root["Pages"], nil)
+	}
+	if len(out) > 0 {
+		return out
+	}
+
+	// broken or missing catalog: take page objects in object order
+	for _, num := range d.objectNumbers() {
+		if p, ok := d.objects[num].value.(dict); ok && p["Type"] == name("Page") {
+			out = append(out, pageNode{page: p, resources: d.dict(p["Resources"])})
+		}
+	}
+	return out
+}
+
+func (d *document) pageText(p pageNode) string {
+	var content []byte
+	contents := p.page["Contents"]
+	refs, ok := d.resolve(contents).(array)
+	if !ok {
+		refs = array{contents}
+	}
+	for _, r := range refs {
+		obj := d.streamOf(r)
+		if obj == nil {
+			continue
+		}
+		data, err := d.decode(obj)
+		if err != nil {
+			continue
+		}
+		content = append(content, data...)
+		content = append(content, '\n')
+	}
+	w := &textWriter{}
+	d.runContent(content, p.resources, w, 0)
+	return w.String()
+}
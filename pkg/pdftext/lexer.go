@@ -0,0 +1,267 @@
+package pdftext
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// PDF object model: numbers are float64, strings are raw bytes and
+// operators or other bare words are keywords.
+type (
+	name    string
+	keyword string
+	str     []byte
+	array   []any
+	dict    map[name]any
+	ref     struct{ num, gen int }
+)
+
+type lexer struct {
+	data []byte
+	pos  int
+}
+
+func isSpace(c byte) bool {
+	return c == 0 || c == '\t' || c == '\n' || c == '\f' || c == '\r' || c == ' '
+}
+
+func isDelim(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// token returns the next token, or ok=false at end of input. "[", "]", "<<"
+// and ">>" are returned as keywords.
+func (l *lexer) token() (tok any, ok bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '(':
+		return l.literal(), true
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return keyword("<<"), true
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return keyword(">>"), true
+	case c == '<':
+		return l.hex(), true
+	case c == '/':
+		return l.name(), true
+	case c == '[' || c == ']' || c == '{' || c == '}' || c == ')' || c == '>':
+		l.pos++
+		return keyword([]byte{c}), true
+	}
+	start := l.pos
+	for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if f, err := strconv.ParseFloat(word, 64); err == nil && (word[0] == '.' || word[0] == '-' || word[0] == '+' || (word[0] >= '0' && word[0] <= '9')) {
+		return f, true
+	}
+	return keyword(word), true
+}
+
+func (l *lexer) literal() str {
+	l.pos++ // (
+	var (
+		out   []byte
+		depth = 1
+	)
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *lexer) hex() str {
+	l.pos++ // <
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	if l.pos < len(l.data) {
+		l.pos++ // >
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			continue
+		}
+		out = append(out, byte(v))
+	}
+	return out
+}
+
+func (l *lexer) name() name {
+	l.pos++ // /
+	var out []byte
+	for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelim(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		out = append(out, c)
+		l.pos++
+	}
+	return name(out)
+}
+
+// object parses one complete object, folding "num gen R" into a ref and
+// nested arrays and dictionaries into values. Unmatched closing delimiters
+// and operators are returned as keywords.
+func (l *lexer) object() (any, bool) {
+	tok, ok := l.token()
+	if !ok {
+		return nil, false
+	}
+	switch t := tok.(type) {
+	case keyword:
+		switch t {
+		case "[":
+			var arr array
+			for {
+				save := l.pos
+				v, ok := l.object()
+				if !ok || v == keyword("]") {
+					return arr, true
+				}
+				if kw, isKw := v.(keyword); isKw && (kw == ">>" || kw == "endobj") {
+					l.pos = save
+					return arr, true
+				}
+				arr = append(arr, v)
+			}
+		case "<<":
+			d := dict{}
+			for {
+				k, ok := l.object()
+				if !ok || k == keyword(">>") {
+					return d, true
+				}
+				key, isName := k.(name)
+				if !isName {
+					continue
+				}
+				v, ok := l.object()
+				if !ok || v == keyword(">>") {
+					return d, true
+				}
+				d[key] = v
+			}
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		case "null":
+			return nil, true
+		}
+	case float64:
+		save := l.pos
+		if gen, ok := l.token(); ok {
+			if g, isNum := gen.(float64); isNum {
+				if r, ok := l.token(); ok && r == keyword("R") {
+					return ref{num: int(t), gen: int(g)}, true
+				}
+			}
+		}
+		l.pos = save
+	}
+	return tok, true
+}
+
+// skipInlineImage moves past the binary data of an inline image, after the
+// "ID" operator, up to and including its "EI".
+func (l *lexer) skipInlineImage() {
+	if l.pos < len(l.data) {
+		l.pos++ // single whitespace after ID
+	}
+	for l.pos+2 <= len(l.data) {
+		i := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if i < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		at := l.pos + i
+		l.pos = at + 2
+		if at > 0 && isSpace(l.data[at-1]) && (l.pos == len(l.data) || isSpace(l.data[l.pos]) || isDelim(l.data[l.pos])) {
+			return
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func flate(s string) string {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write([]byte(s))
+	w.Close()
+	return b.String()
+}
+
+// buildPDF lays out objects in order; the xref table is omitted since
+// Extract recovers objects by scanning.
+func buildPDF(objects ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	for i, o := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+func stream(dict, data string) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func TestExtractPagesInTreeOrder(t *testing.T) {
+	page1 := "BT /F1 12 Tf 72 720 Td (Revenue grew 12% year over year.) Tj 0 -14 Td [(Gross)-300(margin) ( widened\\051)] TJ " +
+		"0 -40 Td (Guidance was raised.) Tj ET"
+	cmap := "/CIDInit /ProcSet findresource begin 12 dict begin begincmap\n" +
+		"1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"2 beginbfchar <0002> <6536> <0003> <FF0C> endbfchar\n" +
+		"1 beginbfrange <0001> <0001> <8425> endbfrange\n" +
+		"endcmap CMapName currentdict /CMap defineresource pop end end"
+	page2 := "BT /F2 10 Tf 1 0 0 1 72 700 Tm <000100020003> Tj ET q /Fm1 Do Q"
+	form := "BT /F1 9 Tf 72 100 Td (Footnote) Tj ET"
+
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [7 0 R 3 0 R] /Count 2 /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		"<< /Type /Font /Subtype /Type0 /BaseFont /SimSun /ToUnicode 8 0 R >>",
+		stream("/Filter /FlateDecode", flate(page2)),
+		"<< /Type /Page /Parent 2 0 R /Contents [9 0 R] >>",
+		stream("/Filter /FlateDecode", flate(cmap)),
+		stream("", page1),
+	)
+	// page 2 draws a form XObject with its own resources
+	data = bytes.Replace(data, []byte("/Parent 2 0 R /Contents 6 0 R"),
+		[]byte("/Parent 2 0 R /Contents 6 0 R /Resources << /Font << /F2 5 0 R >> /XObject << /Fm1 10 0 R >> >>"), 1)
+	data = bytes.Replace(data, []byte("trailer"), []byte("10 0 obj\n"+stream("/Type /XObject /Subtype /Form /Resources << /Font << /F1 4 0 R >> >>", form)+"\nendobj\ntrailer"), 1)
+
+	pages, err := Extract(data)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("pages = %d, want 2", len(pages))
+	}
+	want1 := "Revenue grew 12% year over year.\nGross margin widened)\n\nGuidance was raised."
+	if pages[0].Number != 1 || pages[0].Text != want1 {
+		t.Errorf("page 1 = %q, want %q", pages[0].Text, want1)
+	}
+	if pages[1].Text != "营收，\nFootnote" {
+		t.Errorf("page 2 = %q", pages[1].Text)
+	}
+}
+
+func TestExtractObjectStreams(t *testing.T) {
+	// catalog, page tree and page live in a compressed object stream
+	packed := []string{"<< /Type /Catalog /Pages 3 0 R >>", "<< /Type /Pages /Kids [4 0 R] /Count 1 >>", "<< /Type /Page /Contents 5 0 R >>"}
+	var header, body string
+	for i, o := range packed {
+		header += fmt.Sprintf("%d %d ", i+2, len(body))
+		body += o + " "
+	}
+	data := buildPDF(
+		stream(fmt.Sprintf("/Type /ObjStm /N 3 /First %d /Filter /FlateDecode", len(header)), flate(header+body)),
+		"<< /Placeholder true >>",
+		"<< /Placeholder true >>",
+		"<< /Placeholder true >>",
+		stream("", "BT 72 720 Td (Packed page) Tj ET"),
+	)
+	// top-level placeholders would shadow the packed objects; drop them
+	for _, n := range []string{"2", "3", "4"} {
+		data = bytes.Replace(data, []byte(n+" 0 obj\n<< /Placeholder true >>\nendobj\n"), nil, 1)
+	}
+	data = bytes.Replace(data, []byte("<< /Root 1 0 R >>"), []byte("<< /Root 2 0 R >>"), 1)
+
+	pages, err := Extract(data)
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if len(pages) != 1 || pages[0].Text != "Packed page" {
+		t.Fatalf("pages = %+v", pages)
+	}
+}
+
+func TestExtractRejectsEncryptedAndNonPDF(t *testing.T) {
+	if _, err := Extract([]byte("hello")); !errors.Is(err, ErrNotPDF) {
+		t.Errorf("err = %v, want ErrNotPDF", err)
+	}
+	data := bytes.Replace(buildPDF("<< /Type /Catalog >>"), []byte("<< /Root 1 0 R >>"), []byte("<< /Root 1 0 R /Encrypt << /Filter /Standard >> >>"), 1)
+	if _, err := Extract(data); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("err = %v, want ErrEncrypted", err)
+	}
+}
+
+func TestExtractMalformedObjectStreams(t *testing.T) {
+	for _, tc := range []struct{ header, first string }{
+		{"2 0 ", "-5"},    // negative /First
+		{"2 -5 ", "5"},    // negative object offset
+		{"2 9999 ", "7"},  // offset past the end
+		{"2 0 ", "99999"}, // /First past the end
+	} {
+		data := buildPDF(
+			stream("/Type /ObjStm /N 1 /First "+tc.first+" /Filter /FlateDecode", flate(tc.header+"<< /Type /Catalog >>")),
+		)
+		if _, err := Extract(data); err != nil {
+			t.Errorf("header %q /First %s: %v", tc.header, tc.first, err)
+		}
+	}
+}
+
+func TestExtractCapsInflatedStreams(t *testing.T) {
+	var b bytes.Buffer
+	w, _ := zlib.NewWriterLevel(&b, zlib.BestCompression)
+	w.Write(make([]byte, maxStreamSize+1024))
+	w.Close()
+	d := &document{objects: map[int]*object{}}
+	if _, err := d.decode(&object{value: dict{"Filter": name("FlateDecode")}, stream: b.Bytes()}); err == nil {
+		t.Fatal("a stream inflating beyond maxStreamSize should fail")
+	}
+}
+
+func FuzzExtract(f *testing.F) {
+	f.Add(buildPDF("<< /Type /Catalog /Pages 2 0 R >>", "<< /Type /Pages /Kids [3 0 R] /Count 1 >>", "<< /Type /Page /Contents 4 0 R >>", stream("", "BT (Hi) Tj ET")))
+	f.Add(buildPDF(stream("/Type /ObjStm /N 1 /First -5", "2 -5 << >>")))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// errors are fine, a recovered panic is a parser bug
+		if _, err := Extract(data); errors.Is(err, ErrMalformed) {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,418 @@
+package pdftext
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxFormDepth bounds nested form XObjects.
+const maxFormDepth = 5
+
+// textState tracks the text line position closely enough to tell line and
+// paragraph breaks apart; glyph widths are not computed.
+type textState struct {
+	x, y     float64 // origin of the current line, in user space
+	scaleX   float64
+	scaleY   float64
+	leading  float64
+	size     float64
+	font     *font
+	moved    bool // a positioning operator ran since the last shown string
+	hasLine  bool
+	lastY    float64
+	lastSize float64
+}
+
+func (s *textState) td(tx, ty float64) {
+	s.x += tx * s.scaleX
+	s.y += ty * s.scaleY
+	if tx != 0 || ty != 0 {
+		s.moved = true
+	}
+}
+
+// textWriter accumulates page text, inserting line breaks, blank lines
+// between paragraphs and spaces between runs on the same line.
+type textWriter struct {
+	b strings.Builder
+}
+
+func (w *textWriter) String() string {
+	lines := strings.Split(w.b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	text := strings.Join(lines, "\n")
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	return strings.TrimSpace(text)
+}
+
+func (w *textWriter) show(s *textState, text string) {
+	if text == "" {
+		return
+	}
+	size := s.size * math.Abs(s.scaleY)
+	if size == 0 {
+		size = 1
+	}
+	if s.hasLine {
+		dy := math.Abs(s.y - s.lastY)
+		switch {
+		case dy > 1.8*math.Max(size, s.lastSize):
+			w.b.WriteString("\n\n")
+		case dy > 0.3*size:
+			w.b.WriteString("\n")
+		case s.moved:
+			w.space(text)
+		}
+	}
+	w.b.WriteString(text)
+	s.hasLine = true
+	s.lastY = s.y
+	s.lastSize = size
+	s.moved = false
+}
+
+// space separates two runs unless either side is whitespace or CJK, which
+// is written without spaces.
+func (w *textWriter) space(next string) {
+	cur := w.b.String()
+	if cur == "" {
+		return
+	}
+	last, _ := utf8.DecodeLastRuneInString(cur)
+	first, _ := utf8.DecodeRuneInString(next)
+	if unicode.IsSpace(last) || unicode.IsSpace(first) || isCJK(last) || isCJK(first) {
+		return
+	}
+	w.b.WriteByte(' ')
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+func (d *document) runContent(data []byte, resources dict, w *textWriter, depth int) {
+	var (
+		l        = &lexer{data: data}
+		s        = &textState{scaleX: 1, scaleY: 1}
+		operands []any
+	)
+	num := func(i int) float64 {
+		if i < 0 || i >= len(operands) {
+			return 0
+		}
+		f, _ := operands[i].(float64)
+		return f
+	}
+	for {
+		tok, ok := l.object()
+		if !ok {
+			return
+		}
+		op, isOp := tok.(keyword)
+		if !isOp {
+			operands = append(operands, tok)
+			continue
+		}
+		switch op {
+		case "BT":
+			s.x, s.y, s.scaleX, s.scaleY = 0, 0, 1, 1
+		case "Tf":
+			if len(operands) == 2 {
+				fontName, _ := operands[0].(name)
+				s.font = d.font(resources, fontName)
+				s.size = num(1)
+			}
+		case "TL":
+			s.leading = num(0)
+		case "Td":
+			s.td(num(0), num(1))
+		case "TD":
+			s.leading = -num(1)
+			s.td(num(0), num(1))
+		case "Tm":
+			if len(operands) == 6 {
+				s.scaleX, s.scaleY = num(0), num(3)
+				if s.scaleX == 0 {
+					s.scaleX = 1
+				}
+				if s.scaleY == 0 {
+					s.scaleY = 1
+				}
+				s.x, s.y, s.moved = num(4), num(5), true
+			}
+		case "T*":
+			s.td(0, -s.leading)
+		case "Tj":
+			if len(operands) > 0 {
+				w.show(s, s.font.decode(operands[len(operands)-1]))
+			}
+		case "'", "\"":
+			s.td(0, -s.leading)
+			if len(operands) > 0 {
+				w.show(s, s.font.decode(operands[len(operands)-1]))
+			}
+		case "TJ":
+			if len(operands) == 0 {
+				break
+			}
+			arr, _ := operands[len(operands)-1].(array)
+			var run strings.Builder
+			for _, item := range arr {
+				switch v := item.(type) {
+				case str:
+					run.WriteString(s.font.decode(v))
+				case float64:
+					// a kerning gap wider than 0.15em is a word break
+					if v < -150 && run.Len() > 0 {
+						cur := run.String()
+						last, _ := utf8.DecodeLastRuneInString(cur)
+						if !unicode.IsSpace(last) && !isCJK(last) {
+							run.WriteByte(' ')
+						}
+					}
+				}
+			}
+			w.show(s, run.String())
+		case "Do":
+			if len(operands) > 0 && depth < maxFormDepth {
+				xname, _ := operands[0].(name)
+				d.runForm(resources, xname, w, depth+1)
+			}
+		case "BI":
+			for {
+				t, ok := l.token()
+				if !ok || t == keyword("ID") {
+					break
+				}
+			}
+			l.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+// runForm extracts text from a form XObject drawn with Do.
+func (d *document) runForm(resources dict, xname name, w *textWriter, depth int) {
+	xobjects := d.dict(resources["XObject"])
+	if xobjects == nil {
+		return
+	}
+	obj := d.streamOf(xobjects[xname])
+	if obj == nil {
+		return
+	}
+	sd, _ := obj.value.(dict)
+	if sd["Subtype"] != name("Form") {
+		return
+	}
+	data, err := d.decode(obj)
+	if err != nil {
+		return
+	}
+	if res := d.dict(sd["Resources"]); res != nil {
+		resources = res
+	}
+	// positions inside the form are not related to the page's, start a new line
+	if w.b.Len() > 0 {
+		w.b.WriteByte('\n')
+	}
+	d.runContent(data, resources, w, depth)
+}
+
+// font maps character codes of one font to Unicode.
+type font struct {
+	codeLen int  // bytes per character code
+	utf16   bool // predefined Uni*-UCS2/UTF16 CMap: codes are UTF-16BE
+	cmap    map[uint32]string
+}
+
+func (d *document) font(resources dict, fontName name) *font {
+	fonts := d.dict(resources["Font"])
+	if fonts == nil {
+		return nil
+	}
+	v := fonts[fontName]
+	r, isRef := v.(ref)
+	if isRef {
+		if f, ok := d.fonts[r]; ok {
+			return f
+		}
+	}
+	fd := d.dict(v)
+	if fd == nil {
+		return nil
+	}
+	f := &font{codeLen: 1}
+	if fd["Subtype"] == name("Type0") {
+		f.codeLen = 2
+		enc, _ := d.resolve(fd["Encoding"]).(name)
+		f.utf16 = strings.HasPrefix(string(enc), "Uni") && (strings.Contains(string(enc), "UCS2") || strings.Contains(string(enc), "UTF16"))
+	}
+	if obj := d.streamOf(fd["ToUnicode"]); obj != nil {
+		if data, err := d.decode(obj); err == nil {
+			f.cmap, f.codeLen = parseCMap(data, f.codeLen)
+		}
+	}
+	if isRef {
+		d.fonts[r] = f
+	}
+	return f
+}
+
+// decode converts a shown string to text. Without a ToUnicode CMap,
+// single-byte codes are read as WinAnsi and two-byte codes are dropped
+// unless the font uses a Unicode CMap.
+func (f *font) decode(v any) string {
+	s, ok := v.(str)
+	if !ok {
+		return ""
+	}
+	if f == nil || (f.cmap == nil && f.codeLen == 1) {
+		return winAnsi(s)
+	}
+	if f.cmap == nil && f.utf16 {
+		return utf16BE(s)
+	}
+	var b strings.Builder
+	for i := 0; i+f.codeLen <= len(s); i += f.codeLen {
+		var code uint32
+		for _, c := range s[i : i+f.codeLen] {
+			code = code<<8 | uint32(c)
+		}
+		if t, ok := f.cmap[code]; ok {
+			b.WriteString(t)
+		} else if f.codeLen == 1 {
+			b.WriteString(winAnsi(s[i : i+1]))
+		}
+	}
+	return b.String()
+}
+
+// winAnsiHigh covers the 0x80-0x9F range where WinAnsi differs from Latin-1.
+var winAnsiHigh = map[byte]rune{
+	0x80: '€', 0x85: '…', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”',
+	0x95: '•', 0x96: '–', 0x97: '—', 0x99: '™',
+}
+
+func winAnsi(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case winAnsiHigh[c] != 0:
+			b.WriteRune(winAnsiHigh[c])
+		case c == '\t' || c == '\n' || c == '\r':
+			b.WriteByte(' ')
+		case c >= 0x20 && c != 0x7F && (c < 0x80 || c >= 0xA0):
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+// maxRange bounds a single bfrange so a corrupt CMap cannot exhaust memory.
+const maxRange = 1 << 16
+
+// parseCMap reads the bfchar and bfrange mappings of a ToUnicode CMap and
+// the code length from its codespace ranges.
+func parseCMap(data []byte, codeLen int) (map[uint32]string, int) {
+	var (
+		l        = &lexer{data: data}
+		cmap     = map[uint32]string{}
+		operands []any
+	)
+	for {
+		tok, ok := l.object()
+		if !ok {
+			return cmap, codeLen
+		}
+		op, isOp := tok.(keyword)
+		if !isOp {
+			operands = append(operands, tok)
+			continue
+		}
+		switch op {
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if lo, ok := operands[0].(str); ok && len(lo) > 0 {
+					codeLen = len(lo)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok := operands[i].(str)
+				if !ok {
+					continue
+				}
+				if dst, ok := operands[i+1].(str); ok {
+					cmap[codeOf(src)] = utf16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(str)
+				hi, ok2 := operands[i+1].(str)
+				if !ok1 || !ok2 || codeOf(hi) < codeOf(lo) || codeOf(hi)-codeOf(lo) >= maxRange {
+					continue
+				}
+				start, end := codeOf(lo), codeOf(hi)
+				switch dst := operands[i+2].(type) {
+				case str:
+					for c := start; c <= end; c++ {
+						cmap[c] = utf16BE(offsetLast(dst, c-start))
+					}
+				case array:
+					for j, item := range dst {
+						if s, ok := item.(str); ok && start+uint32(j) <= end {
+							cmap[start+uint32(j)] = utf16BE(s)
+						}
+					}
+				}
+			}
+		}
+		if strings.HasPrefix(string(op), "end") || strings.HasPrefix(string(op), "begin") {
+			operands = operands[:0]
+		}
+	}
+}
+
+func codeOf(s []byte) uint32 {
+	var code uint32
+	for _, c := range s {
+		code = code<<8 | uint32(c)
+	}
+	return code
+}
+
+// offsetLast adds delta to the last byte pair of a bfrange destination.
+func offsetLast(dst []byte, delta uint32) []byte {
+	out := append([]byte(nil), dst...)
+	if len(out) < 2 {
+		if len(out) == 1 {
+			out[0] += byte(delta)
+		}
+		return out
+	}
+	v := uint32(out[len(out)-2])<<8 | uint32(out[len(out)-1])
+	v += delta
+	out[len(out)-2], out[len(out)-1] = byte(v>>8), byte(v)
+	return out
+}
+
+func utf16BE(b []byte) string {
+	if len(b)%2 == 1 {
+		return string(rune(b[0]))
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(u))
+}
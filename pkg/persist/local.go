@@ -0,0 +1,156 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+)
+
+// LocalStorage keeps one file per key under a directory per area.
+type LocalStorage struct {
+	dirs map[Area]string
+}
+
+// NewLocal returns a storage writing each area to its directory in dirs.
+// Areas without a directory go to <root>/<area>, where root is dirs[""].
+func NewLocal(dirs map[Area]string) *LocalStorage {
+	out := make(map[Area]string, len(dirs))
+	for area, dir := range dirs {
+		out[area] = dir
+	}
+	return &LocalStorage{dirs: out}
+}
+
+// LocalFor lays the areas out the way the rest of the tree expects:
+// results in results_dir, caches in data_cache_dir, sessions and memory
+// documents under data_dir.
+func LocalFor(cfg *config.Config) *LocalStorage {
+	return NewLocal(map[Area]string{
+		Results:  cfg.ResultsDir,
+		Cache:    cfg.DataCacheDir,
+		Sessions: filepath.Join(cfg.DataDir, "sessions"),
+		Memory:   filepath.Join(cfg.DataDir, "memory"),
+	})
+}
+
+// Dir returns the directory holding area.
+func (s *LocalStorage) Dir(area Area) string {
+	if dir, ok := s.dirs[area]; ok {
+		return dir
+	}
+	return filepath.Join(s.dirs[""], string(area))
+}
+
+func (s *LocalStorage) path(area Area, key string) (string, error) {
+	if err := checkKey(area, key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Dir(area), filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStorage) Put(_ context.Context, area Area, key string, data []byte) error {
+	path, err := s.path(area, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("put %s/%s: %w", area, key, err)
+	}
+	// write a temp file of our own then rename, so readers never see a
+	// partial file and concurrent writers to the key don't share one
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("put %s/%s: %w", area, key, err)
+	}
+	tmp := f.Name()
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(tmp, 0o644)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("put %s/%s: %w", area, key, err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) Get(_ context.Context, area Area, key string) ([]byte, time.Time, error) {
+	path, err := s.path(area, key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, time.Time{}, ErrNotFound
+		}
+		return nil, time.Time{}, fmt.Errorf("get %s/%s: %w", area, key, err)
+	}
+	if info.IsDir() {
+		return nil, time.Time{}, ErrNotFound
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, time.Time{}, ErrNotFound
+		}
+		return nil, time.Time{}, fmt.Errorf("get %s/%s: %w", area, key, err)
+	}
+	return data, info.ModTime(), nil
+}
+
+func (s *LocalStorage) Delete(_ context.Context, area Area, key string) error {
+	path, err := s.path(area, key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete %s/%s: %w", area, key, err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) List(_ context.Context, area Area, prefix string) ([]string, error) {
+	root := s.Dir(area)
+	var keys []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", area, err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *LocalStorage) Close() error { return nil }
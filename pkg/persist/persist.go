@@ -0,0 +1,153 @@
+// Package persist is the storage layer behind reports, session documents,
+// data source caches and memory documents. Storage is a small key/value
+// interface with local-filesystem, SQLite and S3 implementations, so an
+// embedder can keep everything on the device (a mobile app) or in a bucket
+// (a fleet of servers). agent.db remains the query index for sessions and
+// memory search; Storage holds the documents themselves.
+package persist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/pkg/objstore"
+)
+
+// Area groups keys by the kind of data they hold; backends keep areas apart.
+type Area string
+
+const (
+	// Results holds the Markdown reports agents write, keyed <symbol>/<trade_date>/<file>.
+	Results Area = "results"
+	// Sessions holds one JSON document per finished session, keyed <id>.json.
+	Sessions Area = "sessions"
+	// Cache holds data source responses, keyed <source>/<entry>.
+	Cache Area = "cache"
+	// Memory holds the embedded chunks of indexed reports, keyed <embedder>/<id>.json.
+	Memory Area = "memory"
+)
+
+// Areas lists every area.
+var Areas = []Area{Results, Sessions, Cache, Memory}
+
+// ErrNotFound is returned by Get when the key does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Storage keeps byte values under slash-separated keys within an area.
+// Implementations are safe for concurrent use.
+type Storage interface {
+	// Put creates or replaces key.
+	Put(ctx context.Context, area Area, key string, data []byte) error
+	// Get returns the value of key and when it was last written, or ErrNotFound.
+	Get(ctx context.Context, area Area, key string) ([]byte, time.Time, error)
+	// Delete removes key; deleting a missing key succeeds.
+	Delete(ctx context.Context, area Area, key string) error
+	// List returns the keys starting with prefix, sorted.
+	List(ctx context.Context, area Area, prefix string) ([]string, error)
+	// Close releases the backend's resources.
+	Close() error
+}
+
+// checkKey rejects keys that could escape their area.
+func checkKey(area Area, key string) error {
+	if area == "" || strings.ContainsAny(string(area), `/\`) {
+		return fmt.Errorf("invalid storage area %q", area)
+	}
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+var (
+	mu       sync.Mutex
+	override Storage
+	opened   = map[string]Storage{}
+)
+
+// SetDefault makes every later For call return s regardless of the config,
+// for embedders that bring their own backend. nil restores config selection.
+func SetDefault(s Storage) {
+	mu.Lock()
+	defer mu.Unlock()
+	override = s
+}
+
+// For returns the storage selected by cfg.StorageBackend, or the one set with
+// SetDefault. SQLite and S3 backends are opened once per database or bucket
+// and shared; they stay open for the life of the process.
+func For(cfg *config.Config) (Storage, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if override != nil {
+		return override, nil
+	}
+	switch cfg.StorageBackend {
+	case "", config.StorageLocal:
+		// stateless, so there is nothing to share
+		return LocalFor(cfg), nil
+	case config.StorageSQLite:
+		if strings.TrimSpace(cfg.DataDir) == "" {
+			return nil, errors.New("sqlite storage needs data_dir")
+		}
+		path := filepath.Join(cfg.DataDir, "agent.db")
+		return shared("sqlite:"+path, func() (Storage, error) { return NewSQLite(path) })
+	case config.StorageS3:
+		if !cfg.ObjstoreEnabled() {
+			return nil, errors.New("s3 storage needs objstore_endpoint and objstore_bucket")
+		}
+		prefix := cfg.ObjstorePrefix
+		if prefix == "" {
+			prefix = "cortexgo"
+		}
+		id := "s3:" + cfg.ObjstoreEndpoint + "/" + cfg.ObjstoreBucket + "/" + prefix
+		return shared(id, func() (Storage, error) {
+			client, err := objstore.New(objstore.Config{
+				Endpoint:  cfg.ObjstoreEndpoint,
+				Region:    cfg.ObjstoreRegion,
+				Bucket:    cfg.ObjstoreBucket,
+				AccessKey: cfg.ObjstoreAccessKey,
+				SecretKey: cfg.ObjstoreSecretKey,
+				PathStyle: cfg.ObjstorePathStyle,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return NewS3(client, prefix), nil
+		})
+	}
+	return nil, fmt.Errorf("unknown storage_backend %q: want local, sqlite or s3", cfg.StorageBackend)
+}
+
+// shared returns the storage opened under id, opening it on first use.
+// Callers hold mu.
+func shared(id string, open func() (Storage, error)) (Storage, error) {
+	if s, ok := opened[id]; ok {
+		return s, nil
+	}
+	s, err := open()
+	if err != nil {
+		return nil, err
+	}
+	opened[id] = s
+	return s, nil
+}
+
+// Local reports whether cfg keeps its data in the local directories, where
+// results_dir and data_cache_dir are the files themselves.
+func Local(cfg *config.Config) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return override == nil && (cfg.StorageBackend == "" || cfg.StorageBackend == config.StorageLocal)
+}
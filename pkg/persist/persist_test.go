@@ -0,0 +1,179 @@
+package persist
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dyike/CortexGo/config"
+	"github.com/dyike/CortexGo/pkg/objstore"
+)
+
+// fakeS3 serves path-style PUT, GET, DELETE and ListObjectsV2 for one bucket.
+func fakeS3(t *testing.T) *objstore.Client {
+	var (
+		mu      sync.Mutex
+		objects = map[string][]byte{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+			var out struct {
+				XMLName  xml.Name `xml:"ListBucketResult"`
+				Contents []struct {
+					Key string `xml:"Key"`
+				} `xml:"Contents"`
+			}
+			for k := range objects {
+				if strings.HasPrefix(k, r.URL.Query().Get("prefix")) {
+					out.Contents = append(out.Contents, struct {
+						Key string `xml:"Key"`
+					}{k})
+				}
+			}
+			xml.NewEncoder(w).Encode(out)
+		case r.Method == http.MethodPut:
+			objects[key], _ = io.ReadAll(r.Body)
+		case r.Method == http.MethodGet:
+			data, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			w.Write(data)
+		case r.Method == http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client, err := objstore.New(objstore.Config{Endpoint: srv.URL, Bucket: "bucket", AccessKey: "a", SecretKey: "s", PathStyle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestStorageBackends(t *testing.T) {
+	db, err := NewSQLite(filepath.Join(t.TempDir(), "agent.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	backends := map[string]Storage{
+		"local":  NewLocal(map[Area]string{"": t.TempDir()}),
+		"sqlite": db,
+		"s3":     NewS3(fakeS3(t), "cortexgo"),
+	}
+	ctx := context.Background()
+	for name, s := range backends {
+		if _, _, err := s.Get(ctx, Results, "AAPL.US/2025-06-02/trader_report.md"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("%s: get missing = %v, want ErrNotFound", name, err)
+		}
+		for _, key := range []string{"AAPL.US/2025-06-02/trader_report.md", "AAPL.US/2025-06-03/trader_report.md", "TSLA.US/2025-06-02/trader_report.md"} {
+			if err := s.Put(ctx, Results, key, []byte("# "+key)); err != nil {
+				t.Fatalf("%s: put: %v", name, err)
+			}
+		}
+		if err := s.Put(ctx, Cache, "AAPL.US/x", []byte("other area")); err != nil {
+			t.Fatalf("%s: put cache: %v", name, err)
+		}
+		data, modified, err := s.Get(ctx, Results, "AAPL.US/2025-06-03/trader_report.md")
+		if err != nil || string(data) != "# AAPL.US/2025-06-03/trader_report.md" || modified.IsZero() {
+			t.Fatalf("%s: get = %q %v %v", name, data, modified, err)
+		}
+		keys, err := s.List(ctx, Results, "AAPL.US/")
+		want := []string{"AAPL.US/2025-06-02/trader_report.md", "AAPL.US/2025-06-03/trader_report.md"}
+		if err != nil || !reflect.DeepEqual(keys, want) {
+			t.Fatalf("%s: list = %v %v", name, keys, err)
+		}
+		if err := s.Delete(ctx, Results, "AAPL.US/2025-06-02/trader_report.md"); err != nil {
+			t.Fatalf("%s: delete: %v", name, err)
+		}
+		if err := s.Delete(ctx, Results, "AAPL.US/2025-06-02/trader_report.md"); err != nil {
+			t.Fatalf("%s: delete missing: %v", name, err)
+		}
+		all, _ := s.List(ctx, Results, "")
+		sort.Strings(all)
+		if len(all) != 2 {
+			t.Fatalf("%s: after delete = %v", name, all)
+		}
+		if err := s.Put(ctx, Results, "../escape", nil); err == nil {
+			t.Fatalf("%s: key escaping its area accepted", name)
+		}
+	}
+}
+
+func TestLocalConcurrentPuts(t *testing.T) {
+	s := NewLocal(map[Area]string{"": t.TempDir()})
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.Put(ctx, Results, "AAPL.US/2025-06-02/state.json", []byte(strings.Repeat("x", 1<<16)))
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent put: %v", err)
+		}
+	}
+	data, _, err := s.Get(ctx, Results, "AAPL.US/2025-06-02/state.json")
+	if err != nil || len(data) != 1<<16 {
+		t.Fatalf("get = %d bytes, %v", len(data), err)
+	}
+	if keys, _ := s.List(ctx, Results, ""); len(keys) != 1 {
+		t.Fatalf("temp files left behind: %v", keys)
+	}
+}
+
+func TestForSelectsBackend(t *testing.T) {
+	cfg := config.DefaultConfigWithRoot(t.TempDir())
+	s, err := For(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, ok := s.(*LocalStorage)
+	if !ok || local.Dir(Results) != cfg.ResultsDir || local.Dir(Cache) != cfg.DataCacheDir || !Local(cfg) {
+		t.Fatalf("default backend = %#v", s)
+	}
+
+	cfg.StorageBackend = config.StorageS3
+	if _, err := For(cfg); err == nil {
+		t.Fatal("s3 without a bucket accepted")
+	}
+	cfg.StorageBackend = config.StorageSQLite
+	a, err := For(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := For(cfg); a != b || Local(cfg) {
+		t.Fatal("sqlite storage not shared")
+	}
+
+	mem := NewLocal(map[Area]string{"": t.TempDir()})
+	SetDefault(mem)
+	defer SetDefault(nil)
+	if s, _ := For(cfg); s != mem || Local(config.DefaultConfigWithRoot(t.TempDir())) {
+		t.Fatal("SetDefault ignored")
+	}
+}
@@ -0,0 +1,71 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dyike/CortexGo/pkg/objstore"
+)
+
+// S3Storage keeps every key as an object <prefix>/<area>/<key> in an
+// S3-compatible bucket, so several servers can share one installation.
+type S3Storage struct {
+	client *objstore.Client
+	prefix string
+}
+
+// NewS3 returns a storage in client's bucket under prefix (may be empty).
+func NewS3(client *objstore.Client, prefix string) *S3Storage {
+	return &S3Storage{client: client, prefix: strings.Trim(prefix, "/")}
+}
+
+func (s *S3Storage) areaPrefix(area Area) string {
+	if s.prefix == "" {
+		return string(area) + "/"
+	}
+	return s.prefix + "/" + string(area) + "/"
+}
+
+func (s *S3Storage) Put(ctx context.Context, area Area, key string, data []byte) error {
+	if err := checkKey(area, key); err != nil {
+		return err
+	}
+	return s.client.Put(ctx, s.areaPrefix(area)+key, data, "application/octet-stream")
+}
+
+func (s *S3Storage) Get(ctx context.Context, area Area, key string) ([]byte, time.Time, error) {
+	if err := checkKey(area, key); err != nil {
+		return nil, time.Time{}, err
+	}
+	data, modified, err := s.client.GetObject(ctx, s.areaPrefix(area)+key)
+	if errors.Is(err, objstore.ErrNotFound) {
+		return nil, time.Time{}, ErrNotFound
+	}
+	return data, modified, err
+}
+
+func (s *S3Storage) Delete(ctx context.Context, area Area, key string) error {
+	if err := checkKey(area, key); err != nil {
+		return err
+	}
+	return s.client.Delete(ctx, s.areaPrefix(area)+key)
+}
+
+func (s *S3Storage) List(ctx context.Context, area Area, prefix string) ([]string, error) {
+	base := s.areaPrefix(area)
+	objects, err := s.client.List(ctx, base+prefix)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(objects))
+	for _, o := range objects {
+		keys = append(keys, strings.TrimPrefix(o.Key, base))
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (s *S3Storage) Close() error { return nil }
@@ -0,0 +1,109 @@
+package persist
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dyike/CortexGo/pkg/sqlite"
+)
+
+const objectsDDL = `
+	CREATE TABLE IF NOT EXISTS objects (
+	  area TEXT NOT NULL,
+	  key TEXT NOT NULL,
+	  data BLOB NOT NULL,
+	  updated_at INTEGER NOT NULL,
+	  PRIMARY KEY (area, key)
+	);`
+
+// SQLiteStorage keeps every key as a row of an objects table, so a whole
+// installation fits in one database file.
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+// NewSQLite opens (or creates) the objects table in the database at path.
+// It may be the same file as agent.db.
+func NewSQLite(path string) (*SQLiteStorage, error) {
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(objectsDDL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create objects table: %w", err)
+	}
+	return &SQLiteStorage{db: db}, nil
+}
+
+func (s *SQLiteStorage) Put(ctx context.Context, area Area, key string, data []byte) error {
+	if err := checkKey(area, key); err != nil {
+		return err
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO objects (area, key, data, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(area, key) DO UPDATE SET data = excluded.data, updated_at = excluded.updated_at
+	`, string(area), key, data, time.Now().UnixNano())
+	if err != nil {
+		return fmt.Errorf("put %s/%s: %w", area, key, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) Get(ctx context.Context, area Area, key string) ([]byte, time.Time, error) {
+	if err := checkKey(area, key); err != nil {
+		return nil, time.Time{}, err
+	}
+	var (
+		data    []byte
+		updated int64
+	)
+	err := s.db.QueryRowContext(ctx, `SELECT data, updated_at FROM objects WHERE area = ? AND key = ?`, string(area), key).Scan(&data, &updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, time.Time{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("get %s/%s: %w", area, key, err)
+	}
+	return data, time.Unix(0, updated), nil
+}
+
+func (s *SQLiteStorage) Delete(ctx context.Context, area Area, key string) error {
+	if err := checkKey(area, key); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM objects WHERE area = ? AND key = ?`, string(area), key); err != nil {
+		return fmt.Errorf("delete %s/%s: %w", area, key, err)
+	}
+	return nil
+}
+
+func (s *SQLiteStorage) List(ctx context.Context, area Area, prefix string) ([]string, error) {
+	// substr keeps prefixes containing LIKE wildcards literal; it counts
+	// characters, not bytes
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT key FROM objects WHERE area = ? AND substr(key, 1, ?) = ? ORDER BY key
+	`, string(area), utf8.RuneCountInString(prefix), prefix)
+	if err != nil {
+		return nil, fmt.Errorf("list %s: %w", area, err)
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("list %s: %w", area, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (s *SQLiteStorage) Close() error { return s.db.Close() }
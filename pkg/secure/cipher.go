@@ -0,0 +1,142 @@
+// Package secure provides optional AES-256-GCM encryption for results and
+// cached data at rest.
+package secure
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix marks a sealed value. Values without it are treated as legacy
+// plaintext so existing databases and caches keep working after the key is
+// configured.
+const Prefix = "enc:v1:"
+
+// KeySize is the required key length (AES-256).
+const KeySize = 32
+
+// ErrNoKey is returned when opening a sealed value without a configured key.
+var ErrNoKey = errors.New("data is encrypted but no encryption key is configured")
+
+// Cipher seals and opens values. A nil *Cipher is valid and passes data
+// through unchanged, so callers do not need to branch on whether encryption
+// is enabled.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// New returns a cipher for a 32 byte key.
+func New(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// ParseKey decodes a base64 (std or URL) or hex encoded 32 byte key.
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, errors.New("encryption key is empty")
+	}
+	if len(s) == hex.EncodedLen(KeySize) {
+		if key, err := hex.DecodeString(s); err == nil {
+			return key, nil
+		}
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(s); err == nil && len(key) == KeySize {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("encryption key must be %d bytes encoded as base64 or hex", KeySize)
+}
+
+// GenerateKey returns a new random key encoded as base64.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Enabled reports whether values will be encrypted.
+func (c *Cipher) Enabled() bool {
+	return c != nil
+}
+
+// Seal encrypts plaintext and returns Prefix + base64(nonce || ciphertext).
+func (c *Cipher) Seal(plaintext []byte) ([]byte, error) {
+	if c == nil {
+		return plaintext, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, plaintext, nil)
+	out := make([]byte, len(Prefix)+base64.StdEncoding.EncodedLen(len(sealed)))
+	copy(out, Prefix)
+	base64.StdEncoding.Encode(out[len(Prefix):], sealed)
+	return out, nil
+}
+
+// Open decrypts a value produced by Seal. Values without Prefix are returned
+// unchanged.
+func (c *Cipher) Open(data []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	if c == nil {
+		return nil, ErrNoKey
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(data[len(Prefix):]))
+	if err != nil {
+		return nil, fmt.Errorf("decode sealed data: %w", err)
+	}
+	n := c.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, errors.New("sealed data too short")
+	}
+	plain, err := c.aead.Open(nil, sealed[:n], sealed[n:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plain, nil
+}
+
+// SealString is Seal for string columns.
+func (c *Cipher) SealString(s string) (string, error) {
+	if c == nil || s == "" {
+		return s, nil
+	}
+	out, err := c.Seal([]byte(s))
+	return string(out), err
+}
+
+// OpenString is Open for string columns.
+func (c *Cipher) OpenString(s string) (string, error) {
+	out, err := c.Open([]byte(s))
+	return string(out), err
+}
+
+// IsSealed reports whether data carries the encryption prefix.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(Prefix))
+}
@@ -0,0 +1,65 @@
+package secure
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	raw, err := ParseKey(key)
+	if err != nil {
+		t.Fatalf("ParseKey: %v", err)
+	}
+	c, err := New(raw)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	plain := []byte(`{"symbol":"AAPL.US","recommendation":"BUY"}`)
+	sealed, err := c.Seal(plain)
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !IsSealed(sealed) || bytes.Contains(sealed, []byte("AAPL")) {
+		t.Fatalf("sealed value leaks plaintext: %s", sealed)
+	}
+	again, _ := c.Seal(plain)
+	if bytes.Equal(sealed, again) {
+		t.Fatal("expected a fresh nonce per seal")
+	}
+	opened, err := c.Open(sealed)
+	if err != nil || !bytes.Equal(opened, plain) {
+		t.Fatalf("Open = %s, %v", opened, err)
+	}
+
+	// legacy plaintext passes through
+	if out, err := c.Open(plain); err != nil || !bytes.Equal(out, plain) {
+		t.Fatalf("plaintext passthrough = %s, %v", out, err)
+	}
+	// a nil cipher cannot open sealed data
+	var none *Cipher
+	if _, err := none.Open(sealed); !errors.Is(err, ErrNoKey) {
+		t.Fatalf("expected ErrNoKey, got %v", err)
+	}
+	// wrong key fails authentication
+	other, _ := New(bytes.Repeat([]byte{7}, KeySize))
+	if _, err := other.Open(sealed); err == nil {
+		t.Fatal("expected decrypt error with wrong key")
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	key := bytes.Repeat([]byte{0xab}, KeySize)
+	if got, err := ParseKey(hex.EncodeToString(key)); err != nil || !bytes.Equal(got, key) {
+		t.Fatalf("hex key = %x, %v", got, err)
+	}
+	if _, err := ParseKey("too-short"); err == nil {
+		t.Fatal("expected error for short key")
+	}
+}
@@ -0,0 +1,103 @@
+package secure
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/dyike/CortexGo/config"
+)
+
+// KeychainService is the service name used to look up the key in the OS
+// keychain (macOS Keychain via `security`, Linux Secret Service via
+// `secret-tool`).
+const KeychainService = "cortexgo"
+
+// KeySource returns where the configured key comes from, or "" when
+// encryption is disabled.
+func KeySource(cfg *config.Config) string {
+	switch {
+	case cfg == nil:
+		return ""
+	case strings.TrimSpace(cfg.EncryptionKey) != "":
+		return "config"
+	case strings.TrimSpace(cfg.EncryptionKeyFile) != "":
+		return "file"
+	case cfg.EncryptionKeychain:
+		return "keychain"
+	}
+	return ""
+}
+
+// LoadKey resolves the key from config, in order: encryption_key,
+// encryption_key_file, then the OS keychain when encryption_keychain is set.
+func LoadKey(cfg *config.Config) ([]byte, error) {
+	switch KeySource(cfg) {
+	case "config":
+		return ParseKey(cfg.EncryptionKey)
+	case "file":
+		data, err := os.ReadFile(cfg.EncryptionKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("read encryption key file: %w", err)
+		}
+		return ParseKey(string(data))
+	case "keychain":
+		secret, err := keychainLookup(KeychainService)
+		if err != nil {
+			return nil, err
+		}
+		return ParseKey(secret)
+	}
+	return nil, nil
+}
+
+var (
+	cipherMu    sync.Mutex
+	cipherCache = map[string]*Cipher{}
+)
+
+// ForConfig returns the cipher for cfg, or nil when encryption is disabled.
+// Keychain lookups are cached per key source so they only prompt once.
+func ForConfig(cfg *config.Config) (*Cipher, error) {
+	source := KeySource(cfg)
+	if source == "" {
+		return nil, nil
+	}
+	cacheKey := source + "\x00" + cfg.EncryptionKey + "\x00" + cfg.EncryptionKeyFile
+
+	cipherMu.Lock()
+	defer cipherMu.Unlock()
+	if c, ok := cipherCache[cacheKey]; ok {
+		return c, nil
+	}
+	key, err := LoadKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c, err := New(key)
+	if err != nil {
+		return nil, err
+	}
+	cipherCache[cacheKey] = c
+	return c, nil
+}
+
+func keychainLookup(service string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-w")
+	case "linux", "freebsd":
+		cmd = exec.Command("secret-tool", "lookup", "service", service)
+	default:
+		return "", fmt.Errorf("keychain lookup is not supported on %s", runtime.GOOS)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("keychain lookup for %q: %w", service, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
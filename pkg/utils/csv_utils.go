@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -187,76 +186,6 @@ func (c *CSVManager) FindLatestCSV(symbol string, minRecords int) (string, error
 	return bestFile, nil
 }
 
-// WriteIndicatorResultToCSV 将技术指标结果写入CSV
-func (c *CSVManager) WriteIndicatorResultToCSV(symbol string, indicators map[string][]models.IndicatorValue) error {
-	dirPath := filepath.Join(c.basePath, "csv", "indicators", symbol)
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %v", err)
-	}
-
-	filename := fmt.Sprintf("%s_indicators_%s.csv", symbol, time.Now().Format("20060102_150405"))
-	filePath := filepath.Join(dirPath, filename)
-
-	file, err := os.Create(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %v", err)
-	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// 收集所有日期和指标名称
-	allDates := make(map[string]bool)
-	indicatorNames := make([]string, 0, len(indicators))
-
-	for name, values := range indicators {
-		indicatorNames = append(indicatorNames, name)
-		for _, value := range values {
-			allDates[value.Date] = true
-		}
-	}
-
-	// 对指标名称排序
-	sort.Strings(indicatorNames)
-
-	// 转换为排序的日期列表
-	dates := make([]string, 0, len(allDates))
-	for date := range allDates {
-		dates = append(dates, date)
-	}
-	sort.Strings(dates)
-
-	// 写入标题行
-	headers := []string{"Date"}
-	headers = append(headers, indicatorNames...)
-	if err := writer.Write(headers); err != nil {
-		return fmt.Errorf("failed to write headers: %v", err)
-	}
-
-	// 按日期写入数据
-	for _, date := range dates {
-		row := []string{date}
-		for _, indicatorName := range indicatorNames {
-			value := ""
-			if values, exists := indicators[indicatorName]; exists {
-				for _, v := range values {
-					if v.Date == date {
-						value = strconv.FormatFloat(v.Value, 'f', 6, 64)
-						break
-					}
-				}
-			}
-			row = append(row, value)
-		}
-		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("failed to write row: %v", err)
-		}
-	}
-
-	return nil
-}
-
 // CleanOldCSVFiles 清理过期的CSV文件
 func (c *CSVManager) CleanOldCSVFiles(maxAge time.Duration) error {
 	marketDir := filepath.Join(c.basePath, "csv", "market")
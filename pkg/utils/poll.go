@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// Poll 立即调用一次 check，之后每隔 interval 调用一次，直到 ctx 结束。
+// 失败的轮次交给 onErr，循环继续
+func Poll(ctx context.Context, interval time.Duration, check func(context.Context) error, onErr func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := check(ctx); err != nil && ctx.Err() == nil && onErr != nil {
+			onErr(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package utils
+
+// OrDash 空值在 Markdown 表格中显示为 "-"
+func OrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
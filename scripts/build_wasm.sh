@@ -0,0 +1,21 @@
+#!/bin/bash
+
+# 编译 js/wasm 版本（数据流与指标层），输出 build/wasm/cortexgo.wasm 与 wasm_exec.js
+OUTPUT_DIR="build/wasm"
+
+set -e
+
+mkdir -p $OUTPUT_DIR
+
+echo "🛠  编译 js/wasm..."
+GOOS=js GOARCH=wasm go build -ldflags="-s -w" -o $OUTPUT_DIR/cortexgo.wasm ./cmd/wasm
+
+# Go 1.24 起 wasm_exec.js 位于 lib/wasm，之前的版本位于 misc/wasm
+GOROOT=$(go env GOROOT)
+if [ -f "$GOROOT/lib/wasm/wasm_exec.js" ]; then
+    cp "$GOROOT/lib/wasm/wasm_exec.js" $OUTPUT_DIR/
+else
+    cp "$GOROOT/misc/wasm/wasm_exec.js" $OUTPUT_DIR/
+fi
+
+echo "✅ 编译完成：$OUTPUT_DIR/cortexgo.wasm"